@@ -14,6 +14,7 @@ import {
   type Wallet,
   type FreezeWalletRequest,
   type CloseWalletRequest,
+  type ApprovalRequest,
   type Transaction,
 } from '@nivo/shared';
 
@@ -142,13 +143,14 @@ class AdminApiClient extends BaseApiClient {
     return response;
   }
 
-  async unfreezeWallet(walletId: string): Promise<Wallet> {
-    const response = await this.post<Wallet>(`/api/v1/wallet/wallets/${walletId}/unfreeze`, {});
+  // Unfreezing and closing are submitted for approval by a second admin
+  async unfreezeWallet(walletId: string): Promise<ApprovalRequest> {
+    const response = await this.post<ApprovalRequest>(`/api/v1/wallet/wallets/${walletId}/unfreeze`, {});
     return response;
   }
 
-  async closeWallet(walletId: string, data: CloseWalletRequest): Promise<Wallet> {
-    const response = await this.post<Wallet>(`/api/v1/wallet/wallets/${walletId}/close`, data);
+  async closeWallet(walletId: string, data: CloseWalletRequest): Promise<ApprovalRequest> {
+    const response = await this.post<ApprovalRequest>(`/api/v1/wallet/wallets/${walletId}/close`, data);
     return response;
   }
 
@@ -200,6 +200,30 @@ export interface CloseWalletRequest {
   reason: string;
 }
 
+// ============================================================================
+// Approval Types (operations that need a second admin)
+// ============================================================================
+
+export type ApprovalStatus = 'pending' | 'approved' | 'rejected' | 'executed' | 'failed';
+
+export interface ApprovalRequest {
+  id: string;
+  service: string;
+  operation: string;
+  resource_type: string;
+  resource_id: string;
+  payload: Record<string, unknown>;
+  status: ApprovalStatus;
+  maker_id: string;
+  checker_id?: string;
+  decision_reason?: string;
+  result?: unknown;
+  failure_reason?: string;
+  created_at: string;
+  decided_at?: string;
+  executed_at?: string;
+}
+
 // ============================================================================
 // User Suspension Request Types
 // ============================================================================
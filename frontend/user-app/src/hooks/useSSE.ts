@@ -32,11 +32,12 @@ export function useSSE({ topics, onEvent, onError, enabled = true }: UseSSEOptio
       return;
     }
 
-    // Build the SSE URL with topics
+    // Build the SSE URL with topics. EventSource cannot set headers, so the
+    // token goes in the query; it also subscribes us to our own notifications.
     const topicsParam = topics.join(',');
-    const url = `${API_BASE_URL}/api/v1/events?topics=${topicsParam}`;
+    const url = `${API_BASE_URL}/api/v1/events?topics=${encodeURIComponent(topicsParam)}&access_token=${encodeURIComponent(token)}`;
 
-    if (isDev) console.log('Connecting to SSE:', url);
+    if (isDev) console.log('Connecting to SSE with topics:', topicsParam);
 
     // Create EventSource connection
     const eventSource = new EventSource(url);
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/vnykmshr/nivo/gateway/internal/middleware"
 	"github.com/vnykmshr/nivo/shared/events"
 	"github.com/vnykmshr/nivo/shared/logger"
 )
@@ -24,8 +26,29 @@ func NewSSEHandler(broker *events.Broker, log *logger.Logger) *SSEHandler {
 	}
 }
 
-// HandleEvents handles SSE connections from clients.
+// HandleEvents handles SSE connections from clients. A client the gateway
+// authenticated is also subscribed to its user's private topic.
 func (h *SSEHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	// Get topics from query parameters (comma-separated)
+	var topics []string
+	for _, topic := range strings.Split(r.URL.Query().Get("topics"), ",") {
+		if topic = strings.TrimSpace(topic); topic == "" {
+			continue
+		}
+		// Private topics are only subscribed to on behalf of their user
+		if events.IsPrivateTopic(topic) {
+			http.Error(w, "Cannot subscribe to a private topic", http.StatusBadRequest)
+			return
+		}
+		topics = append(topics, topic)
+	}
+	if len(topics) == 0 {
+		topics = []string{"all"} // Subscribe to all topics by default
+	}
+	if userID, ok := r.Context().Value(middleware.UserIDKey).(string); ok && userID != "" {
+		topics = append(topics, events.UserTopic(userID))
+	}
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -42,14 +65,10 @@ func (h *SSEHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
 	clientID := fmt.Sprintf("%s-%d", requestID, time.Now().UnixNano())
 	client := events.NewClient(clientID)
 
-	// Get topics from query parameters (comma-separated)
-	topics := r.URL.Query().Get("topics")
-	if topics == "" {
-		topics = "all" // Subscribe to all topics by default
-	}
-
 	// Subscribe to requested topics
-	client.Subscribe(topics)
+	for _, topic := range topics {
+		client.Subscribe(topic)
+	}
 
 	h.logger.WithField("client_id", clientID).
 		WithField("topics", topics).
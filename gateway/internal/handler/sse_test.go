@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vnykmshr/nivo/gateway/internal/middleware"
 	"github.com/vnykmshr/nivo/shared/events"
 	"github.com/vnykmshr/nivo/shared/logger"
 )
@@ -177,6 +178,63 @@ func TestSSEHandler_HandleEvents(t *testing.T) {
 	})
 }
 
+func TestSSEHandler_HandleEvents_UserTopic(t *testing.T) {
+	broker := events.NewBroker()
+	broker.Start()
+	handler := NewSSEHandler(broker, logger.NewDefault("test"))
+
+	t.Run("authenticated client receives only its own private events", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), middleware.UserIDKey, "user-1"))
+		req := httptest.NewRequest(http.MethodGet, "/sse/events?topics=transactions", nil).WithContext(ctx)
+		rec := &mockFlusherRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			handler.HandleEvents(rec, req)
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		broker.Broadcast(events.UserTopic("user-1"), "notification.created", map[string]interface{}{"id": "mine"})
+		broker.Broadcast(events.UserTopic("user-2"), "notification.created", map[string]interface{}{"id": "theirs"})
+		broker.Broadcast("transactions", "transaction.created", map[string]interface{}{"id": "txn"})
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		<-done
+
+		body := rec.Body.String()
+		assert.Contains(t, body, `"id":"mine"`)
+		assert.Contains(t, body, `"id":"txn"`)
+		assert.NotContains(t, body, `"id":"theirs"`)
+	})
+
+	t.Run("subscribers to all do not receive private events", func(t *testing.T) {
+		client := events.NewClient("all-client")
+		client.Subscribe("all")
+		broker.Register(client)
+		defer broker.Unregister(client)
+
+		broker.Broadcast(events.UserTopic("user-1"), "notification.created", nil)
+		broker.Broadcast("wallets", "wallet.updated", nil)
+
+		select {
+		case event := <-client.Channel:
+			assert.Equal(t, "wallet.updated", event.Type)
+		case <-time.After(time.Second):
+			t.Fatal("expected the public event")
+		}
+	})
+
+	t.Run("private topics cannot be requested", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/sse/events?topics=transactions,user:user-2", nil)
+		rec := &mockFlusherRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+		handler.HandleEvents(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
 // mockFlusherRecorder is a ResponseRecorder that implements http.Flusher.
 type mockFlusherRecorder struct {
 	*httptest.ResponseRecorder
@@ -7,6 +7,7 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/response"
 )
 
@@ -34,15 +35,16 @@ type JWTClaims struct {
 	jwt.RegisteredClaims
 }
 
-// JWTValidator validates JWT tokens locally without calling external services.
+// JWTValidator validates JWT tokens locally against the identity service's
+// public keys, which are fetched once and cached rather than per request.
 type JWTValidator struct {
-	jwtSecret string
+	keys sharedjwt.KeySource
 }
 
 // NewJWTValidator creates a new JWT validator.
-func NewJWTValidator(jwtSecret string) *JWTValidator {
+func NewJWTValidator(keys sharedjwt.KeySource) *JWTValidator {
 	return &JWTValidator{
-		jwtSecret: jwtSecret,
+		keys: keys,
 	}
 }
 
@@ -67,15 +69,8 @@ func (v *JWTValidator) Authenticate(next http.Handler) http.Handler {
 
 		// Parse and validate token
 		claims := &JWTClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			// Validate signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(v.jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		// Service tokens are signed with the same keys but carry no user
+		if err := sharedjwt.ParseToken(tokenString, claims, v.keys); err != nil || claims.UserID == "" {
 			response.Error(w, errors.Unauthorized("invalid or expired token"))
 			return
 		}
@@ -151,14 +146,7 @@ func (v *JWTValidator) Optional(next http.Handler) http.Handler {
 
 		tokenString := parts[1]
 		claims := &JWTClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(v.jwtSecret), nil
-		})
-
-		if err == nil && token.Valid {
+		if err := sharedjwt.ParseToken(tokenString, claims, v.keys); err == nil && claims.UserID != "" {
 			// Valid token, add to context
 			ctx := r.Context()
 			ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
@@ -177,3 +165,20 @@ func (v *JWTValidator) Optional(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// TokenFromQuery moves an access_token query parameter into the Authorization
+// header, for clients such as the browser's EventSource that cannot set
+// headers. The parameter is removed from the URL once moved.
+func TokenFromQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if token := query.Get("access_token"); token != "" {
+			if r.Header.Get("Authorization") == "" {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+			query.Del("access_token")
+			r.URL.RawQuery = query.Encode()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -8,25 +8,27 @@ import (
 
 // ServiceRegistry holds the URLs of all backend services.
 type ServiceRegistry struct {
-	Identity    string
-	Ledger      string
-	RBAC        string
-	Transaction string
-	Wallet      string
-	Risk        string
-	Simulation  string
+	Identity     string
+	Ledger       string
+	RBAC         string
+	Transaction  string
+	Wallet       string
+	Risk         string
+	Simulation   string
+	Notification string
 }
 
 // NewServiceRegistry creates a new service registry from environment variables.
 func NewServiceRegistry() *ServiceRegistry {
 	return &ServiceRegistry{
-		Identity:    getEnvOrDefault("IDENTITY_SERVICE_URL", "http://identity-service:8080"),
-		Ledger:      getEnvOrDefault("LEDGER_SERVICE_URL", "http://ledger-service:8081"),
-		RBAC:        getEnvOrDefault("RBAC_SERVICE_URL", "http://rbac-service:8082"),
-		Transaction: getEnvOrDefault("TRANSACTION_SERVICE_URL", "http://transaction-service:8084"),
-		Wallet:      getEnvOrDefault("WALLET_SERVICE_URL", "http://wallet-service:8083"),
-		Risk:        getEnvOrDefault("RISK_SERVICE_URL", "http://risk-service:8085"),
-		Simulation:  getEnvOrDefault("SIMULATION_SERVICE_URL", "http://simulation-service:8086"),
+		Identity:     getEnvOrDefault("IDENTITY_SERVICE_URL", "http://identity-service:8080"),
+		Ledger:       getEnvOrDefault("LEDGER_SERVICE_URL", "http://ledger-service:8081"),
+		RBAC:         getEnvOrDefault("RBAC_SERVICE_URL", "http://rbac-service:8082"),
+		Transaction:  getEnvOrDefault("TRANSACTION_SERVICE_URL", "http://transaction-service:8084"),
+		Wallet:       getEnvOrDefault("WALLET_SERVICE_URL", "http://wallet-service:8083"),
+		Risk:         getEnvOrDefault("RISK_SERVICE_URL", "http://risk-service:8085"),
+		Simulation:   getEnvOrDefault("SIMULATION_SERVICE_URL", "http://simulation-service:8086"),
+		Notification: getEnvOrDefault("NOTIFICATION_SERVICE_URL", "http://notification-service:8087"),
 	}
 }
 
@@ -50,18 +52,23 @@ func (r *ServiceRegistry) GetServiceInfo(serviceName string) (*ServiceInfo, erro
 		return &ServiceInfo{URL: r.RBAC, IsAlias: false}, nil
 	case "transaction":
 		return &ServiceInfo{URL: r.Transaction, IsAlias: false}, nil
-	case "transactions":
-		// "transactions" is alias - preserve path segment
+	case "transactions", "scheduled-transfers", "payment-requests", "disputes":
+		// "transactions", "scheduled-transfers", "payment-requests", "disputes" are aliases - preserve path segment
 		return &ServiceInfo{URL: r.Transaction, IsAlias: true}, nil
 	case "wallet":
 		return &ServiceInfo{URL: r.Wallet, IsAlias: false}, nil
-	case "wallets":
-		// "wallets" is alias - preserve path segment
+	case "wallets", "expense-groups":
+		// "wallets", "expense-groups" are aliases - preserve path segment
 		return &ServiceInfo{URL: r.Wallet, IsAlias: true}, nil
 	case "risk":
 		return &ServiceInfo{URL: r.Risk, IsAlias: false}, nil
 	case "simulation":
 		return &ServiceInfo{URL: r.Simulation, IsAlias: false}, nil
+	case "notifications":
+		// Only the user inbox and preferences are served under /api/v1; the
+		// notification service's internal endpoints are not reachable through
+		// the gateway
+		return &ServiceInfo{URL: r.Notification, IsAlias: true}, nil
 	default:
 		return nil, fmt.Errorf("unknown service: %s", serviceName)
 	}
@@ -79,13 +86,14 @@ func (r *ServiceRegistry) GetServiceURL(serviceName string) (string, error) {
 // AllServices returns a map of all registered services.
 func (r *ServiceRegistry) AllServices() map[string]string {
 	return map[string]string{
-		"identity":    r.Identity,
-		"ledger":      r.Ledger,
-		"rbac":        r.RBAC,
-		"transaction": r.Transaction,
-		"wallet":      r.Wallet,
-		"risk":        r.Risk,
-		"simulation":  r.Simulation,
+		"identity":     r.Identity,
+		"ledger":       r.Ledger,
+		"rbac":         r.RBAC,
+		"transaction":  r.Transaction,
+		"wallet":       r.Wallet,
+		"risk":         r.Risk,
+		"simulation":   r.Simulation,
+		"notification": r.Notification,
 	}
 }
 
@@ -104,6 +112,10 @@ var pathRoutingRules = []pathRoutingRule{
 	{pattern: regexp.MustCompile(`^wallets/[^/]+/statements/`), service: "transactions"},
 	// Admin transaction endpoints (admin/* normally routes to identity, but transactions go to transaction service)
 	{pattern: regexp.MustCompile(`^admin/transactions/`), service: "transactions"},
+	// Admin reconciliation endpoints belong to wallet service
+	{pattern: regexp.MustCompile(`^admin/reconciliation/`), service: "wallets"},
+	// Admin wallet endpoints (wallet approvals) belong to wallet service
+	{pattern: regexp.MustCompile(`^admin/wallets/`), service: "wallets"},
 }
 
 // GetServiceByPath checks if the path matches any special routing rules.
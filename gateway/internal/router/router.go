@@ -9,6 +9,7 @@ import (
 	"github.com/vnykmshr/nivo/gateway/internal/handler"
 	"github.com/vnykmshr/nivo/gateway/internal/middleware"
 	"github.com/vnykmshr/nivo/gateway/internal/proxy"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/logger"
 	"github.com/vnykmshr/nivo/shared/metrics"
 	sharedMiddleware "github.com/vnykmshr/nivo/shared/middleware"
@@ -25,15 +26,16 @@ type Router struct {
 
 // NewRouter creates a new router with all handlers and middleware.
 func NewRouter(gateway *proxy.Gateway, sseHandler *handler.SSEHandler, log *logger.Logger) *Router {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		panic("JWT_SECRET environment variable is required")
+	// Tokens are verified with the identity service's published public keys
+	jwksURL := os.Getenv("JWKS_URL")
+	if jwksURL == "" {
+		jwksURL = sharedjwt.DefaultJWKSURL
 	}
 
 	return &Router{
 		gateway:    gateway,
 		sseHandler: sseHandler,
-		validator:  middleware.NewJWTValidator(jwtSecret),
+		validator:  middleware.NewJWTValidator(sharedjwt.NewRemoteKeySet(jwksURL)),
 		logger:     log,
 		metrics:    metrics.NewCollector("gateway"),
 	}
@@ -58,12 +60,22 @@ func (r *Router) SetupRoutes() http.Handler {
 	mux.HandleFunc("POST /api/v1/auth/register", r.gateway.ProxyRequest)
 	mux.HandleFunc("POST /api/v1/auth/login", r.gateway.ProxyRequest)
 
+	// Token refresh (authorized by the refresh token in the body, not a JWT)
+	mux.HandleFunc("POST /api/v1/identity/auth/refresh", r.gateway.ProxyRequest)
+	mux.HandleFunc("POST /api/v1/auth/refresh", r.gateway.ProxyRequest)
+
+	// Second step of two-factor login (authorized by the login challenge token, not a JWT)
+	mux.HandleFunc("POST /api/v1/auth/login/2fa", r.gateway.ProxyRequest)
+	mux.HandleFunc("POST /api/v1/auth/login/2fa/setup", r.gateway.ProxyRequest)
+	mux.HandleFunc("POST /api/v1/auth/login/2fa/setup/confirm", r.gateway.ProxyRequest)
+
 	// Password reset endpoints (public - no auth required)
 	mux.HandleFunc("POST /api/v1/auth/password/forgot", r.gateway.ProxyRequest)
 	mux.HandleFunc("POST /api/v1/auth/password/reset", r.gateway.ProxyRequest)
 
-	// SSE endpoints (authentication optional, can subscribe to public events)
-	mux.HandleFunc("GET /api/v1/events", r.sseHandler.HandleEvents)
+	// SSE endpoints (authentication optional, can subscribe to public events;
+	// authenticated clients also receive their own in-app notifications)
+	mux.Handle("GET /api/v1/events", middleware.TokenFromQuery(r.validator.Optional(http.HandlerFunc(r.sseHandler.HandleEvents))))
 	mux.HandleFunc("GET /api/v1/events/stats", r.sseHandler.HandleStats)
 	mux.HandleFunc("POST /api/v1/events/broadcast", r.sseHandler.HandleBroadcast)
 
@@ -99,6 +111,7 @@ func (r *Router) applyMiddleware(handler http.Handler) http.Handler {
 				"/api/v1/auth/password/reset",
 				"/api/v1/identity/auth/login",
 				"/api/v1/identity/auth/register",
+				"/api/v1/identity/auth/refresh",
 				"/api/v1/events",
 				"/health",
 				"/metrics",
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"time"
@@ -8,9 +9,13 @@ import (
 	"github.com/vnykmshr/nivo/services/identity/internal/handler"
 	"github.com/vnykmshr/nivo/services/identity/internal/repository"
 	"github.com/vnykmshr/nivo/services/identity/internal/service"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/audit"
 	"github.com/vnykmshr/nivo/shared/cache"
 	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/crypto"
 	"github.com/vnykmshr/nivo/shared/events"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/server"
 )
 
@@ -27,11 +32,28 @@ func main() {
 			kycRepo := repository.NewKYCRepository(ctx.DB)
 			sessionRepo := repository.NewSessionRepository(ctx.DB)
 			verificationRepo := repository.NewVerificationRepository(ctx.DB)
+			twoFactorRepo := repository.NewTwoFactorRepository(ctx.DB)
+			loginAttemptRepo := repository.NewLoginAttemptRepository(ctx.DB)
 
-			// Initialize external service clients with internal auth for service-to-service calls
-			internalSecret := server.GetEnv("INTERNAL_SERVICE_SECRET", "")
-			rbacClient := service.NewRBACClientWithSecret(server.GetEnv("RBAC_SERVICE_URL", "http://rbac-service:8082"), internalSecret)
-			walletClient := service.NewWalletClientWithSecret(server.GetEnv("WALLET_SERVICE_URL", "http://wallet-service:8083"), internalSecret)
+			// Access tokens and service tokens are signed with the same keys
+			signingKeys, err := loadSigningKeys(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			// Service tokens let the other services call internal endpoints, each with its own credential
+			serviceCredentials, err := service.ParseServiceCredentials(os.Getenv("SERVICE_CREDENTIALS"))
+			if err != nil {
+				return nil, err
+			}
+			if len(serviceCredentials) == 0 {
+				ctx.Logger.Warn("SERVICE_CREDENTIALS not set, other services cannot call internal endpoints")
+			}
+			serviceTokens := service.NewServiceTokenIssuer(signingKeys, serviceCredentials, sharedjwt.DefaultServiceTokenTTL)
+
+			// Initialize external service clients, signing internal calls with this service's own tokens
+			rbacClient := service.NewRBACClient(server.GetEnv("RBAC_SERVICE_URL", "http://rbac-service:8082"), serviceTokens)
+			walletClient := service.NewWalletClient(server.GetEnv("WALLET_SERVICE_URL", "http://wallet-service:8083"), serviceTokens)
 			notificationClient := clients.NewNotificationClient(server.GetEnv("NOTIFICATION_SERVICE_URL", "http://notification-service:8087"))
 
 			// Initialize event publisher
@@ -58,19 +80,45 @@ func main() {
 			}
 
 			// Initialize services
-			jwtSecret := server.RequireEnv("JWT_SECRET")
-			jwtExpiry := 24 * time.Hour
-			authService := service.NewAuthService(userRepo, userAdminRepo, kycRepo, sessionRepo, rbacClient, walletClient, notificationClient, jwtSecret, jwtExpiry, eventPublisher)
+			// JWT_SECRET only signs step-up verification tokens, which this service alone checks
+			server.RequireEnv("JWT_SECRET")
+			jwtExpiry, err := time.ParseDuration(server.GetEnv("JWT_EXPIRY", "15m"))
+			if err != nil {
+				return nil, err
+			}
+			refreshExpiry, err := time.ParseDuration(server.GetEnv("JWT_REFRESH_EXPIRY", "720h"))
+			if err != nil {
+				return nil, err
+			}
+			authService := service.NewAuthService(userRepo, userAdminRepo, kycRepo, sessionRepo, twoFactorRepo, loginAttemptRepo, rbacClient, walletClient, notificationClient, signingKeys, jwtExpiry, eventPublisher)
+			authService.SetRefreshTokenExpiry(refreshExpiry)
 
-			// Enable session caching if Redis is available
+			// TOTP secrets are stored encrypted, so a database leak does not give away second factors
+			totpCipher, err := crypto.NewSecretCipherFromBase64(server.RequireEnv("TOTP_ENCRYPTION_KEY"))
+			if err != nil {
+				return nil, err
+			}
+			authService.SetTOTPCipher(totpCipher)
+
+			// Enable session caching and Redis login attempt tracking if Redis is available
 			if sessionCache != nil {
 				authService.SetCache(sessionCache)
 			}
 
+			// Admin actions are recorded in the audit log shared by all services
+			auditStore := audit.NewPostgresStore(ctx.DB.DB)
+			auditLog := audit.NewLog(auditStore, "identity")
+			authService.SetAuditLog(auditLog)
+
+			// Overriding a rejected KYC needs a second admin's approval
+			approvals := approval.NewWorkflow(approval.NewPostgresStore(ctx.DB.DB), "identity")
+			approvals.SetAuditLog(auditLog)
+			authService.SetApprovals(approvals)
+
 			verificationService := service.NewVerificationService(verificationRepo, userAdminRepo)
 
 			// Initialize router
-			router := handler.NewRouter(authService, verificationService)
+			router := handler.NewRouter(authService, verificationService, serviceTokens, signingKeys, auditStore, approvals)
 
 			return router.SetupRoutes(), nil
 		},
@@ -82,3 +130,30 @@ func main() {
 		},
 	})
 }
+
+// loadSigningKeys reads the access token signing keys from JWT_SIGNING_KEYS_DIR,
+// one <kid>.pem file per key, signing with JWT_SIGNING_KEY_ID. Outside production
+// a temporary key is generated when no directory is set, so tokens stop working
+// when the service restarts.
+func loadSigningKeys(ctx *server.BootstrapContext) (*sharedjwt.KeySet, error) {
+	if dir := os.Getenv("JWT_SIGNING_KEYS_DIR"); dir != "" {
+		keys, err := sharedjwt.LoadKeySet(dir, os.Getenv("JWT_SIGNING_KEY_ID"))
+		if err != nil {
+			return nil, err
+		}
+		ctx.Logger.WithField("kid", keys.ActiveKeyID()).Info("JWT signing keys loaded")
+		return keys, nil
+	}
+
+	if ctx.Config.IsProduction() {
+		return nil, fmt.Errorf("JWT_SIGNING_KEYS_DIR environment variable is required in production")
+	}
+
+	// A new kid per start makes verifiers fetch the new key instead of using a cached one
+	key, err := sharedjwt.GenerateSigningKey(fmt.Sprintf("temp-%d", time.Now().Unix()))
+	if err != nil {
+		return nil, err
+	}
+	ctx.Logger.WithField("kid", key.ID).Warn("JWT_SIGNING_KEYS_DIR not set, signing tokens with a temporary key")
+	return sharedjwt.NewKeySet(key), nil
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vnykmshr/nivo/shared/audit"
+)
+
+// mockAuditReader implements audit.Reader for testing.
+type mockAuditReader struct {
+	entries    []*audit.Entry
+	lastFilter *audit.Filter
+}
+
+func (m *mockAuditReader) List(ctx context.Context, filter *audit.Filter) ([]*audit.Entry, int64, error) {
+	m.lastFilter = filter
+	return m.entries, int64(len(m.entries)), nil
+}
+
+func (m *mockAuditReader) Each(ctx context.Context, filter *audit.Filter, fn func(*audit.Entry) error) error {
+	m.lastFilter = filter
+	for _, entry := range m.entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockAuditReader) Verify(ctx context.Context) (*audit.Verification, error) {
+	return &audit.Verification{Valid: true, Entries: int64(len(m.entries))}, nil
+}
+
+func TestAuditHandler_ListEntries(t *testing.T) {
+	reader := &mockAuditReader{entries: []*audit.Entry{{Sequence: 1, Action: "user.suspend"}}}
+	handler := NewAuditHandler(reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-log?action=user.suspend&from=2024-03-01&to=2024-03-31&page=2&per_page=10", nil)
+	rec := httptest.NewRecorder()
+	handler.ListEntries(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	filter := reader.lastFilter
+	assert.Equal(t, "user.suspend", filter.Action)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), *filter.To, "a to date should include the whole day")
+	assert.Equal(t, 10, filter.Limit)
+	assert.Equal(t, 10, filter.Offset)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, true, body["success"])
+}
+
+func TestAuditHandler_ListEntries_InvalidFilter(t *testing.T) {
+	handler := NewAuditHandler(&mockAuditReader{})
+
+	for _, query := range []string{"from=yesterday", "from=2024-03-02&to=2024-03-01T00:00:00Z"} {
+		rec := httptest.NewRecorder()
+		handler.ListEntries(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-log?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func TestAuditHandler_ExportCSV(t *testing.T) {
+	reader := &mockAuditReader{entries: []*audit.Entry{
+		{
+			Sequence:     1,
+			Service:      "wallet",
+			ActorID:      "admin-1",
+			Action:       "wallet.freeze",
+			ResourceType: "wallet",
+			ResourceID:   "=HYPERLINK(\"x\")",
+			After:        json.RawMessage(`{"status":"frozen"}`),
+			CreatedAt:    time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+			Hash:         "abc",
+		},
+	}}
+	handler := NewAuditHandler(reader)
+
+	rec := httptest.NewRecorder()
+	handler.ExportCSV(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-log/export?service=wallet", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment; filename=audit_log_")
+	assert.Equal(t, "wallet", reader.lastFilter.Service)
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, auditCSVHeader, rows[0])
+	assert.Equal(t, "wallet.freeze", rows[1][5])
+	assert.Equal(t, "'=HYPERLINK(\"x\")", rows[1][7], "formula-like values should be escaped")
+	assert.Equal(t, `{"status":"frozen"}`, rows[1][9])
+}
+
+func TestAuditHandler_ExportCSV_NoEntries(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewAuditHandler(&mockAuditReader{}).ExportCSV(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-log/export", nil))
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{auditCSVHeader}, rows)
+}
@@ -115,6 +115,33 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, loginResp)
 }
 
+// Refresh exchanges a refresh token for a new access token and refresh token.
+// POST /api/v1/auth/refresh
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	req, bindErr := parseBody[models.RefreshTokenRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	tokens, svcErr := h.authService.Refresh(r.Context(), req.RefreshToken)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, tokens)
+}
+
+// JWKS publishes the public keys access tokens can be verified with. The body
+// is a plain JWK Set rather than the usual response envelope, so standard JWT
+// libraries can read it.
+// GET /.well-known/jwks.json
+func (h *AuthHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	response.JSON(w, http.StatusOK, h.authService.JWKS())
+}
+
 // Logout handles session termination.
 // POST /api/v1/auth/logout
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
@@ -153,6 +180,47 @@ func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
 	response.NoContent(w)
 }
 
+// ListSessions lists the devices the current user is signed in on.
+// GET /api/v1/users/me/sessions
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+	if user == nil {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	sessions, svcErr := h.authService.ListSessions(r.Context(), user.ID, extractBearerToken(r))
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, sessions)
+}
+
+// RevokeSession signs the current user out of one of their sessions.
+// DELETE /api/v1/users/me/sessions/{id}
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+	if user == nil {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	sessionID := r.PathValue("id")
+	if sessionID == "" {
+		response.Error(w, errors.BadRequest("session ID is required"))
+		return
+	}
+
+	if svcErr := h.authService.RevokeSession(r.Context(), user.ID, sessionID); svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.NoContent(w)
+}
+
 // GetProfile retrieves the current user's profile.
 // GET /api/v1/auth/me
 func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
@@ -388,6 +456,8 @@ type VerifyKYCRequest struct {
 }
 
 // VerifyKYC approves a user's KYC (admin operation).
+// Overriding a rejected KYC is submitted for approval by a second admin
+// and returns the pending request.
 // POST /api/v1/admin/kyc/verify
 func (h *AuthHandler) VerifyKYC(w http.ResponseWriter, r *http.Request) {
 	// Read request body
@@ -405,10 +475,15 @@ func (h *AuthHandler) VerifyKYC(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify KYC
-	if svcErr := h.authService.VerifyKYC(r.Context(), req.UserID); svcErr != nil {
+	approvalReq, svcErr := h.authService.VerifyKYCOrRequestOverride(r.Context(), req.UserID)
+	if svcErr != nil {
 		response.Error(w, svcErr)
 		return
 	}
+	if approvalReq != nil {
+		response.Success(w, http.StatusAccepted, approvalReq)
+		return
+	}
 
 	response.NoContent(w)
 }
@@ -626,6 +701,29 @@ func (h *AuthHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, http.StatusOK, map[string]string{"message": "user unsuspended successfully"})
 }
 
+// UnlockUser handles POST /api/v1/admin/users/:id/unlock
+// Clears failed logins and any sign-in lockout for the user.
+func (h *AuthHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("id")
+	if userID == "" {
+		response.Error(w, errors.BadRequest("user ID is required"))
+		return
+	}
+
+	adminUser := getUserFromContext(r.Context())
+	if adminUser == nil {
+		response.Error(w, errors.Unauthorized("authentication required"))
+		return
+	}
+
+	if svcErr := h.authService.UnlockLogin(r.Context(), userID, adminUser.ID); svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "user login unlocked successfully"})
+}
+
 // extractBearerToken extracts the JWT token from the Authorization header.
 func extractBearerToken(r *http.Request) string {
 	authHeader := r.Header.Get("Authorization")
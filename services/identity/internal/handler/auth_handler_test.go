@@ -17,6 +17,7 @@ import (
 	"github.com/vnykmshr/nivo/services/identity/internal/repository"
 	"github.com/vnykmshr/nivo/services/identity/internal/service"
 	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	sharedModels "github.com/vnykmshr/nivo/shared/models"
 )
 
@@ -193,6 +194,42 @@ func (m *mockSessionRepository) DeleteByUserID(ctx context.Context, userID strin
 	return nil
 }
 
+func (m *mockSessionRepository) ListByUserID(ctx context.Context, userID string) ([]*models.Session, *errors.Error) {
+	sessions := make([]*models.Session, 0)
+	for _, session := range m.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *mockSessionRepository) DeleteByID(ctx context.Context, userID, sessionID string) (string, *errors.Error) {
+	for hash, session := range m.sessions {
+		if session.ID == sessionID && session.UserID == userID {
+			delete(m.sessions, hash)
+			return hash, nil
+		}
+	}
+	return "", errors.NotFound("session")
+}
+
+func (m *mockSessionRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) *errors.Error {
+	return nil
+}
+
+func (m *mockSessionRepository) RecordDevice(ctx context.Context, userID, deviceName string) (bool, *errors.Error) {
+	return false, nil
+}
+
+func (m *mockSessionRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, *errors.Error) {
+	return nil, errors.Unauthorized("invalid refresh token")
+}
+
+func (m *mockSessionRepository) RotateRefreshToken(ctx context.Context, used, next *models.RefreshToken, accessTokenHash string) (string, *errors.Error) {
+	return "", errors.Unauthorized("refresh token has already been used")
+}
+
 // mockKYCRepository implements service.KYCRepositoryInterface.
 type mockKYCRepository struct{}
 
@@ -256,6 +293,82 @@ func (m *mockUserAdminRepository) ValidatePairing(ctx context.Context, adminUser
 	return false, nil
 }
 
+// mockTwoFactorRepository implements service.TwoFactorRepositoryInterface.
+// No user has 2FA enrolled, so logins complete after the password check.
+type mockTwoFactorRepository struct{}
+
+func (m *mockTwoFactorRepository) GetByUserID(ctx context.Context, userID string) (*models.TwoFactor, *errors.Error) {
+	return nil, errors.NotFound("two-factor enrolment")
+}
+
+func (m *mockTwoFactorRepository) SavePending(ctx context.Context, userID, secret string) *errors.Error {
+	return nil
+}
+
+func (m *mockTwoFactorRepository) Enable(ctx context.Context, userID string, step int64, codeHashes []string) *errors.Error {
+	return nil
+}
+
+func (m *mockTwoFactorRepository) UseStep(ctx context.Context, userID string, step int64) *errors.Error {
+	return nil
+}
+
+func (m *mockTwoFactorRepository) Delete(ctx context.Context, userID string) *errors.Error {
+	return nil
+}
+
+func (m *mockTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) *errors.Error {
+	return nil
+}
+
+func (m *mockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) *errors.Error {
+	return errors.Unauthorized("invalid recovery code")
+}
+
+func (m *mockTwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID string) (int, *errors.Error) {
+	return 0, nil
+}
+
+func (m *mockTwoFactorRepository) CreateChallenge(ctx context.Context, challenge *models.LoginChallenge) *errors.Error {
+	return nil
+}
+
+func (m *mockTwoFactorRepository) GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*models.LoginChallenge, *errors.Error) {
+	return nil, errors.Unauthorized("invalid or expired login challenge")
+}
+
+func (m *mockTwoFactorRepository) RecordChallengeFailure(ctx context.Context, id string) (int, *errors.Error) {
+	return 1, nil
+}
+
+func (m *mockTwoFactorRepository) ConsumeChallenge(ctx context.Context, id string) *errors.Error {
+	return nil
+}
+
+// mockLoginAttemptRepository implements service.LoginAttemptRepositoryInterface.
+// It never blocks, so handler tests are not affected by lockout.
+type mockLoginAttemptRepository struct{}
+
+func (m *mockLoginAttemptRepository) RecordFailure(ctx context.Context, identifierHash string, window time.Duration) (int, *errors.Error) {
+	return 1, nil
+}
+
+func (m *mockLoginAttemptRepository) Block(ctx context.Context, identifierHash string, until time.Time) *errors.Error {
+	return nil
+}
+
+func (m *mockLoginAttemptRepository) BlockedUntil(ctx context.Context, identifierHash string) (time.Time, *errors.Error) {
+	return time.Time{}, nil
+}
+
+func (m *mockLoginAttemptRepository) Reset(ctx context.Context, identifierHash string) *errors.Error {
+	return nil
+}
+
+func (m *mockLoginAttemptRepository) CreateAuditEntry(ctx context.Context, entry *models.LoginAuditEntry) *errors.Error {
+	return nil
+}
+
 // mockRBACClient implements service.RBACClientInterface.
 type mockRBACClient struct{}
 
@@ -303,10 +416,12 @@ func createTestAuthService() (*service.AuthService, *mockUserRepository) {
 		userAdminRepo,
 		&mockKYCRepository{},
 		sessionRepo,
+		&mockTwoFactorRepository{},
+		&mockLoginAttemptRepository{},
 		rbacClient,
-		nil,                               // walletClient
-		nil,                               // notificationClient
-		"test-jwt-secret-32-characters!!", // Must be at least 32 chars
+		nil, // walletClient
+		nil, // notificationClient
+		testSigningKeys(),
 		24*time.Hour,
 		nil, // eventPublisher
 	)
@@ -314,6 +429,15 @@ func createTestAuthService() (*service.AuthService, *mockUserRepository) {
 	return authService, userRepo
 }
 
+// testSigningKeys creates a key set with a fresh Ed25519 signing key.
+func testSigningKeys() *sharedjwt.KeySet {
+	key, err := sharedjwt.GenerateSigningKey("test-key")
+	if err != nil {
+		panic(err)
+	}
+	return sharedjwt.NewKeySet(key)
+}
+
 // makeRequest is a helper to create HTTP requests for testing.
 func makeRequest(t *testing.T, handler http.HandlerFunc, method, path string, body interface{}) (*httptest.ResponseRecorder, *apiResponse) {
 	t.Helper()
@@ -507,6 +631,7 @@ func TestAuthHandler_Login(t *testing.T) {
 		err := json.Unmarshal(resp.Data, &loginResp)
 		require.NoError(t, err)
 		assert.NotEmpty(t, loginResp["token"])
+		assert.NotEmpty(t, loginResp["refresh_token"])
 	})
 
 	t.Run("wrong password returns 401", func(t *testing.T) {
@@ -587,6 +712,31 @@ func TestAuthHandler_Logout(t *testing.T) {
 	})
 }
 
+func TestAuthHandler_Refresh(t *testing.T) {
+	authService, _ := createTestAuthService()
+	handler := NewAuthHandler(authService)
+
+	t.Run("missing refresh token returns validation error", func(t *testing.T) {
+		rec, resp := makeRequest(t, handler.Refresh, http.MethodPost, "/api/v1/auth/refresh", map[string]interface{}{})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.False(t, resp.Success)
+		assert.Equal(t, "VALIDATION_ERROR", resp.Error.Code)
+	})
+
+	t.Run("unknown refresh token returns 401", func(t *testing.T) {
+		body := map[string]interface{}{
+			"refresh_token": "unknown-refresh-token",
+		}
+
+		rec, resp := makeRequest(t, handler.Refresh, http.MethodPost, "/api/v1/auth/refresh", body)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.False(t, resp.Success)
+		assert.Equal(t, "UNAUTHORIZED", resp.Error.Code)
+	})
+}
+
 func TestAuthHandler_GetProfile(t *testing.T) {
 	authService, _ := createTestAuthService()
 	handler := NewAuthHandler(authService)
@@ -9,6 +9,7 @@ import (
 	"github.com/vnykmshr/nivo/services/identity/internal/models"
 	"github.com/vnykmshr/nivo/services/identity/internal/service"
 	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
 	"github.com/vnykmshr/nivo/shared/response"
 )
 
@@ -51,8 +52,14 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
-		// Set user in context
+		// Set user in context, and under the shared auth keys so shared packages
+		// (audit, approval) see the same user and permissions as other services
 		ctx := context.WithValue(r.Context(), UserContextKey, user)
+		ctx = context.WithValue(ctx, middleware.UserIDKey, user.ID)
+		ctx = context.WithValue(ctx, middleware.AccountTypeKey, string(user.AccountType))
+		if permissions, permErr := extractPermissionsFromToken(token); permErr == nil {
+			ctx = context.WithValue(ctx, middleware.UserPermissionsKey, permissions)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
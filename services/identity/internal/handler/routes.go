@@ -4,6 +4,9 @@ import (
 	"net/http"
 
 	"github.com/vnykmshr/nivo/services/identity/internal/service"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/audit"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/metrics"
 	"github.com/vnykmshr/nivo/shared/middleware"
 )
@@ -13,19 +16,30 @@ type Router struct {
 	authHandler         *AuthHandler
 	verificationHandler *VerificationHandler
 	passwordHandler     *PasswordHandler
+	twoFactorHandler    *TwoFactorHandler
+	serviceTokenHandler *ServiceTokenHandler
+	auditHandler        *AuditHandler
+	approvalHandler     *approval.Handler
 	authMiddleware      *AuthMiddleware
 	userAdminValidation *UserAdminValidation
+	serviceAuth         *middleware.ServiceAuth
 	metrics             *metrics.Collector
 }
 
-// NewRouter creates a new router with all handlers and middleware.
-func NewRouter(authService *service.AuthService, verificationService *service.VerificationService) *Router {
+// NewRouter creates a new router with all handlers and middleware. Service
+// tokens for this service's internal endpoints are verified with signingKeys.
+func NewRouter(authService *service.AuthService, verificationService *service.VerificationService, serviceTokens *service.ServiceTokenIssuer, signingKeys sharedjwt.KeySource, auditLog audit.Reader, approvals *approval.Workflow) *Router {
 	return &Router{
 		authHandler:         NewAuthHandler(authService),
 		verificationHandler: NewVerificationHandler(verificationService),
 		passwordHandler:     NewPasswordHandler(authService, verificationService),
+		twoFactorHandler:    NewTwoFactorHandler(authService),
+		serviceTokenHandler: NewServiceTokenHandler(serviceTokens),
+		auditHandler:        NewAuditHandler(auditLog),
+		approvalHandler:     approval.NewHandler(approvals),
 		authMiddleware:      NewAuthMiddleware(authService),
 		userAdminValidation: NewUserAdminValidation(authService),
+		serviceAuth:         middleware.NewServiceAuth(signingKeys, sharedjwt.ServiceIdentity),
 		metrics:             metrics.NewCollector("identity"),
 	}
 }
@@ -42,6 +56,27 @@ func (r *Router) SetupRoutes() http.Handler {
 	mux.Handle("POST /api/v1/auth/register", authRateLimit(http.HandlerFunc(r.authHandler.Register)))
 	mux.Handle("POST /api/v1/auth/login", authRateLimit(http.HandlerFunc(r.authHandler.Login)))
 
+	// Token refresh (public - authorized by the refresh token in the body)
+	mux.Handle("POST /api/v1/auth/refresh", authRateLimit(http.HandlerFunc(r.authHandler.Refresh)))
+
+	// Public keys for verifying access tokens (fetched and cached by other services)
+	mux.HandleFunc("GET /.well-known/jwks.json", r.authHandler.JWKS)
+
+	// ========================================================================
+	// Two-Factor Login Routes (public - authorized by the login challenge token)
+	// ========================================================================
+
+	// Second step of login: challenge token + TOTP or recovery code
+	mux.Handle("POST /api/v1/auth/login/2fa",
+		strictRateLimit(http.HandlerFunc(r.twoFactorHandler.VerifyLogin)))
+
+	// Mandatory enrolment for admin accounts without 2FA
+	mux.Handle("POST /api/v1/auth/login/2fa/setup",
+		strictRateLimit(http.HandlerFunc(r.twoFactorHandler.BeginLoginSetup)))
+
+	mux.Handle("POST /api/v1/auth/login/2fa/setup/confirm",
+		strictRateLimit(http.HandlerFunc(r.twoFactorHandler.ConfirmLoginSetup)))
+
 	// ========================================================================
 	// Password Reset Routes (public - no auth required)
 	// ========================================================================
@@ -74,6 +109,13 @@ func (r *Router) SetupRoutes() http.Handler {
 	mux.Handle("PUT /api/v1/users/me/password",
 		r.authMiddleware.Authenticate(http.HandlerFunc(r.authHandler.ChangePassword)))
 
+	// Active sessions (signed-in devices)
+	mux.Handle("GET /api/v1/users/me/sessions",
+		r.authMiddleware.Authenticate(http.HandlerFunc(r.authHandler.ListSessions)))
+
+	mux.Handle("DELETE /api/v1/users/me/sessions/{id}",
+		r.authMiddleware.Authenticate(http.HandlerFunc(r.authHandler.RevokeSession)))
+
 	// ========================================================================
 	// Password Change Routes (protected - requires authentication + verification)
 	// ========================================================================
@@ -88,6 +130,28 @@ func (r *Router) SetupRoutes() http.Handler {
 		r.authMiddleware.Authenticate(
 			http.HandlerFunc(r.passwordHandler.CompletePasswordChange)))
 
+	// ========================================================================
+	// Two-Factor Management Routes (protected - requires authentication)
+	// ========================================================================
+
+	mux.Handle("GET /api/v1/auth/2fa",
+		r.authMiddleware.Authenticate(http.HandlerFunc(r.twoFactorHandler.GetStatus)))
+
+	mux.Handle("POST /api/v1/auth/2fa/setup",
+		r.authMiddleware.Authenticate(http.HandlerFunc(r.twoFactorHandler.BeginSetup)))
+
+	mux.Handle("POST /api/v1/auth/2fa/confirm",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(http.HandlerFunc(r.twoFactorHandler.ConfirmSetup))))
+
+	mux.Handle("POST /api/v1/auth/2fa/disable",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(http.HandlerFunc(r.twoFactorHandler.Disable))))
+
+	mux.Handle("POST /api/v1/auth/2fa/recovery-codes",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(http.HandlerFunc(r.twoFactorHandler.RegenerateRecoveryCodes))))
+
 	// User lookup (rate limited to prevent phone number enumeration)
 	mux.Handle("GET /api/v1/users/lookup",
 		strictRateLimit(
@@ -146,6 +210,51 @@ func (r *Router) SetupRoutes() http.Handler {
 			r.authMiddleware.Authenticate(
 				userUnsuspendPermission(http.HandlerFunc(r.authHandler.UnsuspendUser)))))
 
+	// Unlocking sign-in is gated like unsuspending: both restore access to an account
+	mux.Handle("POST /api/v1/admin/users/{id}/unlock",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(
+				userUnsuspendPermission(http.HandlerFunc(r.authHandler.UnlockUser)))))
+
+	// Audit log of admin actions across all services (compliance review)
+	auditReadPermission := r.authMiddleware.RequirePermission("identity:audit:read")
+
+	mux.Handle("GET /api/v1/admin/audit-log",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(
+				auditReadPermission(http.HandlerFunc(r.auditHandler.ListEntries)))))
+
+	mux.Handle("GET /api/v1/admin/audit-log/export",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(
+				auditReadPermission(http.HandlerFunc(r.auditHandler.ExportCSV)))))
+
+	mux.Handle("GET /api/v1/admin/audit-log/verify",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(
+				auditReadPermission(http.HandlerFunc(r.auditHandler.VerifyChain)))))
+
+	// KYC override approvals: a second admin with the verify permission approves or rejects
+	mux.Handle("GET /api/v1/admin/approvals",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(
+				kycVerifyPermission(http.HandlerFunc(r.approvalHandler.List)))))
+
+	mux.Handle("GET /api/v1/admin/approvals/{id}",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(
+				kycVerifyPermission(http.HandlerFunc(r.approvalHandler.Get)))))
+
+	mux.Handle("POST /api/v1/admin/approvals/{id}/approve",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(
+				kycVerifyPermission(http.HandlerFunc(r.approvalHandler.Approve)))))
+
+	mux.Handle("POST /api/v1/admin/approvals/{id}/reject",
+		strictRateLimit(
+			r.authMiddleware.Authenticate(
+				kycVerifyPermission(http.HandlerFunc(r.approvalHandler.Reject)))))
+
 	// ========================================================================
 	// Verification Routes (OTP-based verification for sensitive operations)
 	// ========================================================================
@@ -182,6 +291,25 @@ func (r *Router) SetupRoutes() http.Handler {
 		r.authMiddleware.Authenticate(
 			http.HandlerFunc(r.verificationHandler.CancelVerification)))
 
+	// ========================================================================
+	// Internal Endpoints (service-to-service, authenticated by service token)
+	// ========================================================================
+
+	// Exchange a service's credential for a service token (authorized by the
+	// credential in the body)
+	mux.Handle("POST /internal/v1/service-tokens",
+		strictRateLimit(http.HandlerFunc(r.serviceTokenHandler.IssueServiceToken)))
+
+	// Redeem a verification token (called by transaction and wallet services
+	// for high-value transfers and beneficiary adds)
+	mux.HandleFunc("POST /internal/v1/verifications/redeem",
+		r.serviceAuth.Allow(r.verificationHandler.RedeemVerification, sharedjwt.ServiceTransaction, sharedjwt.ServiceWallet))
+
+	// Release a redeemed verification token (called by the transaction service
+	// when a high-value transfer is rejected)
+	mux.HandleFunc("POST /internal/v1/verifications/release",
+		r.serviceAuth.Allow(r.verificationHandler.ReleaseVerification, sharedjwt.ServiceTransaction))
+
 	// ========================================================================
 	// User-Admin Routes (for User-Admin accounts only)
 	// ========================================================================
@@ -210,6 +338,9 @@ func (r *Router) applyMiddleware(handler http.Handler) http.Handler {
 	// Apply metrics (outermost layer)
 	handler = r.metrics.Middleware("identity")(handler)
 
+	// Record the client IP with audited actions
+	handler = audit.Middleware()(handler)
+
 	// Apply request ID generation/extraction
 	handler = middleware.RequestID()(handler)
 
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/services/identity/internal/service"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// ServiceTokenHandler issues tokens for service-to-service calls.
+type ServiceTokenHandler struct {
+	issuer *service.ServiceTokenIssuer
+}
+
+// NewServiceTokenHandler creates a new service token handler.
+func NewServiceTokenHandler(issuer *service.ServiceTokenIssuer) *ServiceTokenHandler {
+	return &ServiceTokenHandler{
+		issuer: issuer,
+	}
+}
+
+// IssueServiceToken handles POST /internal/v1/service-tokens (internal endpoint)
+// Exchanges a service's credential for a short-lived token for calling another service.
+func (h *ServiceTokenHandler) IssueServiceToken(w http.ResponseWriter, r *http.Request) {
+	req, bindErr := parseBody[models.IssueServiceTokenRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	token, svcErr := h.issuer.Issue(req.Service, req.Secret, req.Audience)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, token)
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/vnykmshr/gopantic/pkg/model"
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/services/identity/internal/service"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// TwoFactorHandler handles TOTP enrolment and the second step of login.
+type TwoFactorHandler struct {
+	authService *service.AuthService
+}
+
+// NewTwoFactorHandler creates a new two-factor handler.
+func NewTwoFactorHandler(authService *service.AuthService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		authService: authService,
+	}
+}
+
+// ============================================================================
+// Login (public - authorized by the challenge token from POST /api/v1/auth/login)
+// ============================================================================
+
+// VerifyLogin handles POST /api/v1/auth/login/2fa
+// Exchanges a challenge token and a TOTP or recovery code for a JWT.
+func (h *TwoFactorHandler) VerifyLogin(w http.ResponseWriter, r *http.Request) {
+	req, bindErr := parseBody[models.TwoFactorLoginRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	loginResp, svcErr := h.authService.CompleteTwoFactorLogin(r.Context(), &req, extractIPAddress(r), r.UserAgent())
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, loginResp)
+}
+
+// BeginLoginSetup handles POST /api/v1/auth/login/2fa/setup
+// Starts enrolment for an admin account that must enrol before logging in.
+func (h *TwoFactorHandler) BeginLoginSetup(w http.ResponseWriter, r *http.Request) {
+	req, bindErr := parseBody[models.LoginTwoFactorSetupRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	setup, svcErr := h.authService.BeginLoginTwoFactorSetup(r.Context(), &req)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, setup)
+}
+
+// ConfirmLoginSetup handles POST /api/v1/auth/login/2fa/setup/confirm
+// Confirms enrolment with a code and completes the login. The response
+// includes the recovery codes, which are not shown again.
+func (h *TwoFactorHandler) ConfirmLoginSetup(w http.ResponseWriter, r *http.Request) {
+	req, bindErr := parseBody[models.LoginTwoFactorConfirmRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	loginResp, svcErr := h.authService.ConfirmLoginTwoFactorSetup(r.Context(), &req, extractIPAddress(r), r.UserAgent())
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, loginResp)
+}
+
+// ============================================================================
+// Management (protected - requires authentication)
+// ============================================================================
+
+// GetStatus handles GET /api/v1/auth/2fa
+func (h *TwoFactorHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+	if user == nil {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	status, svcErr := h.authService.GetTwoFactorStatus(r.Context(), user.ID)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, status)
+}
+
+// BeginSetup handles POST /api/v1/auth/2fa/setup
+// Returns a new secret and otpauth URI for the authenticator app.
+func (h *TwoFactorHandler) BeginSetup(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+	if user == nil {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	setup, svcErr := h.authService.BeginTwoFactorSetup(r.Context(), user.ID)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, setup)
+}
+
+// ConfirmSetup handles POST /api/v1/auth/2fa/confirm
+// Enables 2FA and returns the recovery codes.
+func (h *TwoFactorHandler) ConfirmSetup(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+	if user == nil {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := parseBody[models.ConfirmTwoFactorRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	codes, svcErr := h.authService.ConfirmTwoFactorSetup(r.Context(), user.ID, &req)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, codes)
+}
+
+// Disable handles POST /api/v1/auth/2fa/disable
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+	if user == nil {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := parseBody[models.DisableTwoFactorRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	if svcErr := h.authService.DisableTwoFactor(r.Context(), user.ID, &req); svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// RegenerateRecoveryCodes handles POST /api/v1/auth/2fa/recovery-codes
+// Replaces all recovery codes after checking a TOTP code.
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+	if user == nil {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := parseBody[models.ConfirmTwoFactorRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	codes, svcErr := h.authService.RegenerateRecoveryCodes(r.Context(), user.ID, &req)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, codes)
+}
+
+// parseBody reads and validates a JSON request body.
+func parseBody[T any](r *http.Request) (T, *errors.Error) {
+	var zero T
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return zero, errors.BadRequest("failed to read request body")
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	req, parseErr := model.ParseInto[T](body)
+	if parseErr != nil {
+		return zero, errors.Validation(parseErr.Error())
+	}
+
+	return req, nil
+}
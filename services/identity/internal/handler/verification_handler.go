@@ -275,3 +275,64 @@ func (h *VerificationHandler) GetVerification(w http.ResponseWriter, r *http.Req
 		"data": verification.SanitizeForUser(),
 	})
 }
+
+// RedeemVerification handles POST /internal/v1/verifications/redeem (internal endpoint)
+// Called by other services to use a verification token for the operation it was issued for.
+func (h *VerificationHandler) RedeemVerification(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeRedeemRequest(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	claims, redeemErr := h.service.RedeemVerificationToken(r.Context(), req.Token, req.OperationType, req.UserID, req.Metadata)
+	if redeemErr != nil {
+		response.Error(w, redeemErr)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"verification_id": claims.VerificationID,
+		"operation_type":  claims.OperationType,
+	})
+}
+
+// ReleaseVerification handles POST /internal/v1/verifications/release (internal endpoint)
+// Called by other services to make a redeemed token usable again when the operation was rejected.
+func (h *VerificationHandler) ReleaseVerification(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeRedeemRequest(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	claims, releaseErr := h.service.ReleaseVerificationToken(r.Context(), req.Token, req.OperationType, req.UserID, req.Metadata)
+	if releaseErr != nil {
+		response.Error(w, releaseErr)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"verification_id": claims.VerificationID,
+		"operation_type":  claims.OperationType,
+	})
+}
+
+// decodeRedeemRequest reads the body of a redeem or release request.
+func decodeRedeemRequest(r *http.Request) (*models.RedeemVerificationRequest, *errors.Error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.BadRequest("failed to read request body")
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	var req models.RedeemVerificationRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, errors.BadRequest("invalid request body")
+	}
+
+	if req.Token == "" || req.UserID == "" || !models.IsValidOperationType(req.OperationType) {
+		return nil, errors.BadRequest("token, user_id and a valid operation_type are required")
+	}
+	return &req, nil
+}
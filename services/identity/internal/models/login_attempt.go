@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// Login lockout policy. Failures are counted per portal and identifier within
+// LoginFailureWindow of the last failure.
+const (
+	LoginFailureWindow      = 30 * time.Minute
+	LoginDelayAfterFailures = 3  // Failures before each further attempt must wait
+	MaxFailedLogins         = 10 // Failures before the identifier is locked
+	MaxLoginDelay           = 30 * time.Second
+	LoginLockoutDuration    = 30 * time.Minute
+)
+
+// LoginAuditEvent is the kind of entry in the login audit log.
+type LoginAuditEvent string
+
+const (
+	LoginAuditFailed   LoginAuditEvent = "login_failed"
+	LoginAuditLocked   LoginAuditEvent = "login_locked"
+	LoginAuditUnlocked LoginAuditEvent = "login_unlocked"
+)
+
+// LoginAuditEntry records a failed login, a lockout or an admin unlock.
+type LoginAuditEntry struct {
+	ID             string           `json:"id" db:"id"`
+	Event          LoginAuditEvent  `json:"event" db:"event"`
+	IdentifierHash *string          `json:"-" db:"identifier_hash"`
+	UserID         *string          `json:"user_id,omitempty" db:"user_id"`   // Nil when no account matched
+	ActorID        *string          `json:"actor_id,omitempty" db:"actor_id"` // Admin who unlocked
+	IPAddress      string           `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent      string           `json:"user_agent,omitempty" db:"user_agent"`
+	CreatedAt      models.Timestamp `json:"created_at" db:"created_at"`
+}
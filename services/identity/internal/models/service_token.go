@@ -0,0 +1,9 @@
+package models
+
+// IssueServiceTokenRequest authenticates a calling service and names the
+// service it wants to call.
+type IssueServiceTokenRequest struct {
+	Service  string `json:"service" validate:"required"`
+	Secret   string `json:"secret" validate:"required"`
+	Audience string `json:"audience" validate:"required"`
+}
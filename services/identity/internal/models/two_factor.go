@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// LoginChallengePurpose is what a login challenge token may be exchanged for.
+type LoginChallengePurpose string
+
+const (
+	ChallengePurposeVerify LoginChallengePurpose = "2fa_verify" // Enrolled user must present a code
+	ChallengePurposeEnroll LoginChallengePurpose = "2fa_enroll" // Admin must enrol before logging in
+)
+
+const (
+	LoginChallengeTTL         = 5 * time.Minute
+	MaxLoginChallengeAttempts = 5  // Wrong codes before the challenge is invalidated
+	RecoveryCodeCount         = 10 // Recovery codes issued per enrolment
+	TOTPIssuer                = "Nivo"
+)
+
+// TwoFactor is a user's TOTP enrolment.
+type TwoFactor struct {
+	UserID       string            `json:"user_id" db:"user_id"`
+	Secret       string            `json:"-" db:"secret"`                        // Base32, encrypted with the user ID; see AuthService.SetTOTPCipher
+	EnabledAt    *models.Timestamp `json:"enabled_at,omitempty" db:"enabled_at"` // Nil while enrolment is pending
+	LastUsedStep int64             `json:"-" db:"last_used_step"`
+	CreatedAt    models.Timestamp  `json:"created_at" db:"created_at"`
+	UpdatedAt    models.Timestamp  `json:"updated_at" db:"updated_at"`
+}
+
+// IsEnabled returns true once enrolment has been confirmed with a code.
+func (t *TwoFactor) IsEnabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// LoginChallenge is issued after a correct password when a second factor is needed.
+type LoginChallenge struct {
+	ID         string                `json:"id" db:"id"`
+	UserID     string                `json:"user_id" db:"user_id"`
+	TokenHash  string                `json:"-" db:"token_hash"`
+	Purpose    LoginChallengePurpose `json:"purpose" db:"purpose"`
+	Attempts   int                   `json:"attempts" db:"attempts"`
+	ExpiresAt  models.Timestamp      `json:"expires_at" db:"expires_at"`
+	ConsumedAt *models.Timestamp     `json:"consumed_at,omitempty" db:"consumed_at"`
+	CreatedAt  models.Timestamp      `json:"created_at" db:"created_at"`
+}
+
+// TwoFactorStatus describes a user's 2FA state.
+type TwoFactorStatus struct {
+	Enabled                bool              `json:"enabled"`
+	Required               bool              `json:"required"` // Mandatory for admin and super_admin accounts
+	EnabledAt              *models.Timestamp `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int               `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorSetupResponse contains the secret to add to an authenticator app.
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`      // Base32, for manual entry
+	OTPAuthURI string `json:"otpauth_uri"` // For QR codes
+}
+
+// RecoveryCodesResponse contains newly issued recovery codes. They are only shown once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ConfirmTwoFactorRequest confirms enrolment with a code from the authenticator app.
+type ConfirmTwoFactorRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// DisableTwoFactorRequest turns 2FA off. Regular users also need a verification
+// token for the 2fa_disable operation.
+type DisableTwoFactorRequest struct {
+	Code              string `json:"code,omitempty"`          // TOTP code
+	RecoveryCode      string `json:"recovery_code,omitempty"` // Or a recovery code
+	VerificationToken string `json:"verification_token,omitempty"`
+}
+
+// TwoFactorLoginRequest completes a login with a second factor.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code,omitempty"`          // TOTP code
+	RecoveryCode   string `json:"recovery_code,omitempty"` // Or a recovery code
+}
+
+// LoginTwoFactorSetupRequest starts enrolment during login.
+type LoginTwoFactorSetupRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+}
+
+// LoginTwoFactorConfirmRequest confirms enrolment during login and completes it.
+type LoginTwoFactorConfirmRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
@@ -83,13 +83,51 @@ type Address struct {
 
 // Session represents an active user session.
 type Session struct {
-	ID        string           `json:"id" db:"id"`
-	UserID    string           `json:"user_id" db:"user_id"`
-	Token     string           `json:"token" db:"token_hash"` // JWT token hash
-	IPAddress string           `json:"ip_address" db:"ip_address"`
-	UserAgent string           `json:"user_agent" db:"user_agent"`
-	ExpiresAt models.Timestamp `json:"expires_at" db:"expires_at"`
-	CreatedAt models.Timestamp `json:"created_at" db:"created_at"`
+	ID         string           `json:"id" db:"id"`
+	UserID     string           `json:"user_id" db:"user_id"`
+	Token      string           `json:"token" db:"token_hash"` // Current access token (JWT) hash
+	IPAddress  string           `json:"ip_address" db:"ip_address"`
+	UserAgent  string           `json:"user_agent" db:"user_agent"`
+	ExpiresAt  models.Timestamp `json:"expires_at" db:"expires_at"`
+	LastSeenAt models.Timestamp `json:"last_seen_at" db:"last_seen_at"` // Updated on login and each refresh
+	CreatedAt  models.Timestamp `json:"created_at" db:"created_at"`
+}
+
+// SessionInfo describes a signed-in device in the user's session list.
+type SessionInfo struct {
+	ID          string           `json:"id"`
+	Device      string           `json:"device"`
+	IPAddress   string           `json:"ip_address"`
+	UserAgent   string           `json:"user_agent"`
+	FirstSeenAt models.Timestamp `json:"first_seen_at"`
+	LastSeenAt  models.Timestamp `json:"last_seen_at"`
+	ExpiresAt   models.Timestamp `json:"expires_at"`
+	Current     bool             `json:"current"` // The session making the request
+}
+
+// RefreshToken is an opaque, single-use token that renews a session's access token.
+// Every token issued for a session shares its SessionID (the token family).
+type RefreshToken struct {
+	ID        string            `json:"id" db:"id"`
+	SessionID string            `json:"session_id" db:"session_id"`
+	UserID    string            `json:"user_id" db:"user_id"`
+	TokenHash string            `json:"-" db:"token_hash"`
+	ExpiresAt models.Timestamp  `json:"expires_at" db:"expires_at"`
+	UsedAt    *models.Timestamp `json:"used_at,omitempty" db:"used_at"` // Set when rotated
+	CreatedAt models.Timestamp  `json:"created_at" db:"created_at"`
+}
+
+// RefreshTokenRequest exchanges a refresh token for new tokens.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// TokenResponse contains a new access token and the refresh token that replaces the one used.
+type TokenResponse struct {
+	Token            string `json:"token"`
+	ExpiresAt        int64  `json:"expires_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt int64  `json:"refresh_expires_at"`
 }
 
 // CreateUserRequest represents the request to create a new user (registration).
@@ -117,13 +155,24 @@ type LoginRequest struct {
 }
 
 // LoginResponse contains the authentication token.
+// When a second factor is needed, Token is empty and ChallengeToken must be
+// exchanged at /api/v1/auth/login/2fa (or used to enrol first when TwoFactorSetupRequired).
 type LoginResponse struct {
-	Token        string           `json:"token"`
-	ExpiresAt    int64            `json:"expires_at"`
-	User         *User            `json:"user"`
-	AccountType  AccountType      `json:"account_type"`             // Account type for easy frontend handling
-	PairedUserID string           `json:"paired_user_id,omitempty"` // For User-Admin: the regular user ID
-	AdminPortal  *AdminPortalInfo `json:"admin_portal,omitempty"`   // For regular users: admin portal info
+	Token            string           `json:"token,omitempty"`
+	ExpiresAt        int64            `json:"expires_at,omitempty"`
+	RefreshToken     string           `json:"refresh_token,omitempty"` // Opaque, single-use; exchange at /api/v1/auth/refresh
+	RefreshExpiresAt int64            `json:"refresh_expires_at,omitempty"`
+	User             *User            `json:"user,omitempty"`
+	AccountType      AccountType      `json:"account_type"`             // Account type for easy frontend handling
+	PairedUserID     string           `json:"paired_user_id,omitempty"` // For User-Admin: the regular user ID
+	AdminPortal      *AdminPortalInfo `json:"admin_portal,omitempty"`   // For regular users: admin portal info
+
+	// Two-factor login
+	TwoFactorRequired      bool     `json:"two_factor_required,omitempty"`       // Password accepted, TOTP or recovery code needed
+	TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"` // Account must enrol in 2FA before logging in
+	ChallengeToken         string   `json:"challenge_token,omitempty"`           // Short-lived token for the second step
+	ChallengeExpiresAt     int64    `json:"challenge_expires_at,omitempty"`
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"` // Shown once, after enrolling during login
 }
 
 // AdminPortalInfo contains information about the User-Admin portal for verification.
@@ -169,6 +218,11 @@ func (k *KYCInfo) IsKYCVerified() bool {
 	return k.Status == KYCStatusVerified
 }
 
+// RequiresTwoFactor returns true if the account cannot log in without 2FA.
+func (u *User) RequiresTwoFactor() bool {
+	return u.AccountType == AccountTypeAdmin || u.AccountType == AccountTypeSuperAdmin
+}
+
 // IsSuspended returns true if the user is suspended.
 func (u *User) IsSuspended() bool {
 	return u.Status == UserStatusSuspended
@@ -129,6 +129,15 @@ type VerifyOTPRequest struct {
 	OTP string `json:"otp" validate:"required,len=6,numeric"`
 }
 
+// RedeemVerificationRequest represents a service's request to use a verification
+// token for an operation on behalf of a user.
+type RedeemVerificationRequest struct {
+	Token         string                 `json:"token" validate:"required"`
+	UserID        string                 `json:"user_id" validate:"required"`
+	OperationType OperationType          `json:"operation_type" validate:"required"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"` // Details the token must be bound to
+}
+
 // ValidOperationTypes returns the list of valid operation types.
 func ValidOperationTypes() map[OperationType]bool {
 	return map[OperationType]bool{
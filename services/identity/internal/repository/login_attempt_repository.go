@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/database"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// LoginAttemptRepository stores failed login counters, used when Redis is not
+// available, and the login audit log.
+type LoginAttemptRepository struct {
+	db *database.DB
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository.
+func NewLoginAttemptRepository(db *database.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// RecordFailure counts a failed login and returns the failures so far. The count
+// starts again when the previous failure is older than window.
+func (r *LoginAttemptRepository) RecordFailure(ctx context.Context, identifierHash string, window time.Duration) (int, *errors.Error) {
+	var failures int
+
+	query := `
+		INSERT INTO login_attempts (identifier_hash, failed_count, last_failed_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (identifier_hash) DO UPDATE
+		SET failed_count = CASE
+				WHEN login_attempts.last_failed_at < NOW() - make_interval(secs => $2) THEN 1
+				ELSE login_attempts.failed_count + 1
+			END,
+			last_failed_at = NOW()
+		RETURNING failed_count
+	`
+
+	if err := r.db.QueryRowContext(ctx, query, identifierHash, window.Seconds()).Scan(&failures); err != nil {
+		return 0, errors.DatabaseWrap(err, "failed to record login failure")
+	}
+
+	return failures, nil
+}
+
+// Block stops logins for an identifier until the given time.
+func (r *LoginAttemptRepository) Block(ctx context.Context, identifierHash string, until time.Time) *errors.Error {
+	query := `
+		INSERT INTO login_attempts (identifier_hash, blocked_until)
+		VALUES ($1, $2)
+		ON CONFLICT (identifier_hash) DO UPDATE SET blocked_until = EXCLUDED.blocked_until
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, identifierHash, until); err != nil {
+		return errors.DatabaseWrap(err, "failed to block login")
+	}
+
+	return nil
+}
+
+// BlockedUntil returns when the identifier's current block ends, or the zero time if it is not blocked.
+func (r *LoginAttemptRepository) BlockedUntil(ctx context.Context, identifierHash string) (time.Time, *errors.Error) {
+	var until time.Time
+
+	query := `
+		SELECT blocked_until
+		FROM login_attempts
+		WHERE identifier_hash = $1 AND blocked_until > NOW()
+	`
+
+	err := r.db.QueryRowContext(ctx, query, identifierHash).Scan(&until)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, errors.DatabaseWrap(err, "failed to get login block")
+	}
+
+	return until, nil
+}
+
+// Reset clears an identifier's failures and any block.
+func (r *LoginAttemptRepository) Reset(ctx context.Context, identifierHash string) *errors.Error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM login_attempts WHERE identifier_hash = $1`, identifierHash); err != nil {
+		return errors.DatabaseWrap(err, "failed to reset login failures")
+	}
+
+	return nil
+}
+
+// CreateAuditEntry appends an entry to the login audit log.
+func (r *LoginAttemptRepository) CreateAuditEntry(ctx context.Context, entry *models.LoginAuditEntry) *errors.Error {
+	query := `
+		INSERT INTO login_audit_log (event, identifier_hash, user_id, actor_id, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		entry.Event,
+		entry.IdentifierHash,
+		entry.UserID,
+		entry.ActorID,
+		entry.IPAddress,
+		entry.UserAgent,
+	).Scan(&entry.ID, &entry.CreatedAt)
+
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to create login audit entry")
+	}
+
+	return nil
+}
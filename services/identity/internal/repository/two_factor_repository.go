@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/database"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// TwoFactorRepository handles database operations for TOTP enrolments,
+// recovery codes and login challenges.
+type TwoFactorRepository struct {
+	db *database.DB
+}
+
+// NewTwoFactorRepository creates a new two-factor repository.
+func NewTwoFactorRepository(db *database.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// GetByUserID retrieves a user's TOTP enrolment, confirmed or pending.
+func (r *TwoFactorRepository) GetByUserID(ctx context.Context, userID string) (*models.TwoFactor, *errors.Error) {
+	tf := &models.TwoFactor{}
+
+	query := `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at, updated_at
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&tf.UserID,
+		&tf.Secret,
+		&tf.EnabledAt,
+		&tf.LastUsedStep,
+		&tf.CreatedAt,
+		&tf.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound("two-factor enrolment")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get two-factor enrolment")
+	}
+
+	return tf, nil
+}
+
+// SavePending stores a new secret awaiting confirmation, replacing any earlier
+// pending secret. A confirmed enrolment is never overwritten.
+func (r *TwoFactorRepository) SavePending(ctx context.Context, userID, secret string) *errors.Error {
+	query := `
+		INSERT INTO user_totp (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, updated_at = NOW()
+		WHERE user_totp.enabled_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to save two-factor secret")
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.Conflict("two-factor authentication is already enabled")
+	}
+
+	return nil
+}
+
+// Enable confirms a pending enrolment and replaces the user's recovery codes.
+func (r *TwoFactorRepository) Enable(ctx context.Context, userID string, step int64, codeHashes []string) *errors.Error {
+	err := r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		query := `
+			UPDATE user_totp
+			SET enabled_at = NOW(), last_used_step = $2, updated_at = NOW()
+			WHERE user_id = $1 AND enabled_at IS NULL
+		`
+		result, err := tx.ExecContext(ctx, query, userID, step)
+		if err != nil {
+			return errors.DatabaseWrap(err, "failed to enable two-factor authentication")
+		}
+
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.Conflict("two-factor authentication is already enabled")
+		}
+
+		return replaceRecoveryCodes(ctx, tx, userID, codeHashes)
+	})
+
+	if err != nil {
+		if e, ok := err.(*errors.Error); ok {
+			return e
+		}
+		return errors.DatabaseWrap(err, "failed to enable two-factor authentication")
+	}
+
+	return nil
+}
+
+// UseStep records an accepted TOTP time step. It fails if the step, or a later
+// one, was already used, so concurrent requests cannot replay the same code.
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID string, step int64) *errors.Error {
+	query := `
+		UPDATE user_totp
+		SET last_used_step = $2, updated_at = NOW()
+		WHERE user_id = $1 AND last_used_step < $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to record two-factor code")
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.Unauthorized("two-factor code has already been used")
+	}
+
+	return nil
+}
+
+// Delete removes a user's TOTP enrolment and recovery codes.
+func (r *TwoFactorRepository) Delete(ctx context.Context, userID string) *errors.Error {
+	err := r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return errors.DatabaseWrap(err, "failed to delete recovery codes")
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+			return errors.DatabaseWrap(err, "failed to delete two-factor enrolment")
+		}
+		return nil
+	})
+
+	if err != nil {
+		if e, ok := err.(*errors.Error); ok {
+			return e
+		}
+		return errors.DatabaseWrap(err, "failed to disable two-factor authentication")
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes discards a user's recovery codes and stores new ones.
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) *errors.Error {
+	err := r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		return replaceRecoveryCodes(ctx, tx, userID, codeHashes)
+	})
+
+	if err != nil {
+		if e, ok := err.(*errors.Error); ok {
+			return e
+		}
+		return errors.DatabaseWrap(err, "failed to replace recovery codes")
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) *errors.Error {
+	query := `
+		UPDATE user_recovery_codes
+		SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to use recovery code")
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.Unauthorized("invalid recovery code")
+	}
+
+	return nil
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes a user has left.
+func (r *TwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID string) (int, *errors.Error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, errors.DatabaseWrap(err, "failed to count recovery codes")
+	}
+
+	return count, nil
+}
+
+// CreateChallenge stores a login challenge.
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, challenge *models.LoginChallenge) *errors.Error {
+	query := `
+		INSERT INTO login_challenges (user_id, token_hash, purpose, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, attempts, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		challenge.UserID,
+		challenge.TokenHash,
+		challenge.Purpose,
+		challenge.ExpiresAt,
+	).Scan(&challenge.ID, &challenge.Attempts, &challenge.CreatedAt)
+
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to create login challenge")
+	}
+
+	return nil
+}
+
+// GetChallengeByTokenHash retrieves an unexpired, unconsumed login challenge.
+func (r *TwoFactorRepository) GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*models.LoginChallenge, *errors.Error) {
+	challenge := &models.LoginChallenge{}
+
+	query := `
+		SELECT id, user_id, token_hash, purpose, attempts, expires_at, consumed_at, created_at
+		FROM login_challenges
+		WHERE token_hash = $1 AND consumed_at IS NULL AND expires_at > NOW()
+	`
+
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.TokenHash,
+		&challenge.Purpose,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&challenge.ConsumedAt,
+		&challenge.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Unauthorized("invalid or expired login challenge")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get login challenge")
+	}
+
+	return challenge, nil
+}
+
+// RecordChallengeFailure counts a wrong code and returns the attempts so far.
+func (r *TwoFactorRepository) RecordChallengeFailure(ctx context.Context, id string) (int, *errors.Error) {
+	var attempts int
+
+	query := `
+		UPDATE login_challenges
+		SET attempts = attempts + 1
+		WHERE id = $1
+		RETURNING attempts
+	`
+
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&attempts); err != nil {
+		return 0, errors.DatabaseWrap(err, "failed to record login challenge attempt")
+	}
+
+	return attempts, nil
+}
+
+// ConsumeChallenge marks a challenge used. Only the first caller succeeds.
+func (r *TwoFactorRepository) ConsumeChallenge(ctx context.Context, id string) *errors.Error {
+	query := `UPDATE login_challenges SET consumed_at = NOW() WHERE id = $1 AND consumed_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to consume login challenge")
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.Unauthorized("invalid or expired login challenge")
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes swaps a user's recovery codes within a transaction.
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID string, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return errors.DatabaseWrap(err, "failed to clear recovery codes")
+	}
+
+	for _, hash := range codeHashes {
+		query := `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`
+		if _, err := tx.ExecContext(ctx, query, userID, hash); err != nil {
+			return errors.DatabaseWrap(err, "failed to store recovery code")
+		}
+	}
+
+	return nil
+}
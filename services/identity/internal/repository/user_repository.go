@@ -828,7 +828,7 @@ func (r *SessionRepository) Create(ctx context.Context, session *models.Session)
 	query := `
 		INSERT INTO sessions (user_id, token_hash, ip_address, user_agent, expires_at)
 		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at
+		RETURNING id, last_seen_at, created_at
 	`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -837,7 +837,7 @@ func (r *SessionRepository) Create(ctx context.Context, session *models.Session)
 		session.IPAddress,
 		session.UserAgent,
 		session.ExpiresAt,
-	).Scan(&session.ID, &session.CreatedAt)
+	).Scan(&session.ID, &session.LastSeenAt, &session.CreatedAt)
 
 	if err != nil {
 		return errors.DatabaseWrap(err, "failed to create session")
@@ -851,7 +851,7 @@ func (r *SessionRepository) GetByTokenHash(ctx context.Context, tokenHash string
 	session := &models.Session{}
 
 	query := `
-		SELECT id, user_id, token_hash, ip_address, user_agent, expires_at, created_at
+		SELECT id, user_id, token_hash, ip_address, user_agent, expires_at, last_seen_at, created_at
 		FROM sessions
 		WHERE token_hash = $1 AND expires_at > NOW()
 	`
@@ -863,6 +863,7 @@ func (r *SessionRepository) GetByTokenHash(ctx context.Context, tokenHash string
 		&session.IPAddress,
 		&session.UserAgent,
 		&session.ExpiresAt,
+		&session.LastSeenAt,
 		&session.CreatedAt,
 	)
 
@@ -925,3 +926,198 @@ func (r *SessionRepository) CleanupExpired(ctx context.Context) (int, *errors.Er
 
 	return int(rows), nil
 }
+
+// ListByUserID retrieves a user's unexpired sessions, most recently seen first.
+func (r *SessionRepository) ListByUserID(ctx context.Context, userID string) ([]*models.Session, *errors.Error) {
+	query := `
+		SELECT id, user_id, token_hash, ip_address, user_agent, expires_at, last_seen_at, created_at
+		FROM sessions
+		WHERE user_id = $1 AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to list sessions")
+	}
+	defer func() { _ = rows.Close() }()
+
+	sessions := make([]*models.Session, 0)
+	for rows.Next() {
+		session := &models.Session{}
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.Token,
+			&session.IPAddress,
+			&session.UserAgent,
+			&session.ExpiresAt,
+			&session.LastSeenAt,
+			&session.CreatedAt,
+		); err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan session")
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to iterate session rows")
+	}
+
+	return sessions, nil
+}
+
+// DeleteByID deletes one of a user's sessions and, by cascade, its refresh tokens.
+// Returns the session's current access token hash so callers can evict it from caches.
+func (r *SessionRepository) DeleteByID(ctx context.Context, userID, sessionID string) (string, *errors.Error) {
+	var tokenHash string
+
+	query := `DELETE FROM sessions WHERE id = $1 AND user_id = $2 RETURNING token_hash`
+
+	err := r.db.QueryRowContext(ctx, query, sessionID, userID).Scan(&tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.NotFound("session")
+		}
+		return "", errors.DatabaseWrap(err, "failed to delete session")
+	}
+
+	return tokenHash, nil
+}
+
+// CreateRefreshToken stores a refresh token for a session.
+func (r *SessionRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) *errors.Error {
+	query := `
+		INSERT INTO refresh_tokens (session_id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		token.SessionID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+	).Scan(&token.ID, &token.CreatedAt)
+
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to create refresh token")
+	}
+
+	return nil
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by hash, including used and
+// expired tokens so the caller can detect reuse.
+func (r *SessionRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, *errors.Error) {
+	token := &models.RefreshToken{}
+
+	query := `
+		SELECT id, session_id, user_id, token_hash, expires_at, used_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.SessionID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Unauthorized("invalid refresh token")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get refresh token")
+	}
+
+	return token, nil
+}
+
+// RotateRefreshToken marks a refresh token used, stores its replacement and points
+// the session at the new access token, in one transaction. It fails with
+// Unauthorized if the token was already used. Returns the previous access token hash.
+func (r *SessionRepository) RotateRefreshToken(ctx context.Context, used, next *models.RefreshToken, accessTokenHash string) (string, *errors.Error) {
+	var previousHash string
+
+	err := r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL`, used.ID)
+		if err != nil {
+			return errors.DatabaseWrap(err, "failed to mark refresh token used")
+		}
+
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.Unauthorized("refresh token has already been used")
+		}
+
+		err = tx.QueryRowContext(ctx,
+			`SELECT token_hash FROM sessions WHERE id = $1 FOR UPDATE`, used.SessionID).Scan(&previousHash)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.Unauthorized("session has been revoked")
+			}
+			return errors.DatabaseWrap(err, "failed to get session")
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE sessions SET token_hash = $2, expires_at = $3, last_seen_at = NOW() WHERE id = $1`,
+			used.SessionID, accessTokenHash, next.ExpiresAt)
+		if err != nil {
+			return errors.DatabaseWrap(err, "failed to update session")
+		}
+
+		insertQuery := `
+			INSERT INTO refresh_tokens (session_id, user_id, token_hash, expires_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`
+		err = tx.QueryRowContext(ctx, insertQuery,
+			next.SessionID, next.UserID, next.TokenHash, next.ExpiresAt,
+		).Scan(&next.ID, &next.CreatedAt)
+		if err != nil {
+			return errors.DatabaseWrap(err, "failed to create refresh token")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if e, ok := err.(*errors.Error); ok {
+			return "", e
+		}
+		return "", errors.DatabaseWrap(err, "failed to rotate refresh token")
+	}
+
+	return previousHash, nil
+}
+
+// RecordDevice remembers that a user signed in from a device. It returns true when
+// the device is new and the user had signed in from other devices before, which is
+// when a new-device alert is worth sending.
+func (r *SessionRepository) RecordDevice(ctx context.Context, userID, deviceName string) (bool, *errors.Error) {
+	var hasDevices bool
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM user_devices WHERE user_id = $1)`, userID).Scan(&hasDevices); err != nil {
+		return false, errors.DatabaseWrap(err, "failed to check known devices")
+	}
+
+	query := `
+		INSERT INTO user_devices (user_id, device_name)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, device_name) DO UPDATE SET last_seen_at = NOW()
+		RETURNING (xmax = 0)
+	`
+
+	var inserted bool
+	if err := r.db.QueryRowContext(ctx, query, userID, deviceName).Scan(&inserted); err != nil {
+		return false, errors.DatabaseWrap(err, "failed to record device")
+	}
+
+	return inserted && hasDevices, nil
+}
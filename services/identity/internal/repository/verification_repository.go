@@ -236,6 +236,40 @@ func (r *VerificationRepository) IncrementAttempts(ctx context.Context, id strin
 	return count, nil
 }
 
+// MarkRedeemed records that the token of a verified request has been used.
+// Returns Forbidden if the request is not verified or was already redeemed, so
+// that a token authorizes a single operation.
+func (r *VerificationRepository) MarkRedeemed(ctx context.Context, id string) *errors.Error {
+	query := `
+		UPDATE verification_requests
+		SET redeemed_at = NOW()
+		WHERE id = $1 AND status = 'verified' AND redeemed_at IS NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to redeem verification")
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.Forbidden("verification token has already been used")
+	}
+	return nil
+}
+
+// ReleaseRedemption makes the token of a redeemed request usable again, for
+// when the operation it was redeemed for did not go ahead.
+func (r *VerificationRepository) ReleaseRedemption(ctx context.Context, id string) *errors.Error {
+	query := `
+		UPDATE verification_requests
+		SET redeemed_at = NULL
+		WHERE id = $1 AND status = 'verified'
+	`
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return errors.DatabaseWrap(err, "failed to release verification")
+	}
+	return nil
+}
+
 // CancelPendingForUser cancels all pending verifications for a user.
 func (r *VerificationRepository) CancelPendingForUser(ctx context.Context, userID string) *errors.Error {
 	query := `
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// KYC overrides for AuthService.
+//
+// Verifying a KYC that was rejected overrides an earlier compliance decision,
+// so it is submitted for approval by a second admin rather than applied by the
+// admin asking for it. Other verifications are applied directly.
+
+// OperationKYCOverride is the approval operation for verifying a rejected KYC.
+const OperationKYCOverride = "kyc.override"
+
+// SetApprovals sets the workflow through which KYC overrides are requested
+// and registers the override operation with it.
+func (s *AuthService) SetApprovals(workflow *approval.Workflow) {
+	s.approvals = workflow
+	workflow.Register(approval.Operation{
+		Name:         OperationKYCOverride,
+		ResourceType: "user",
+		Permission:   "identity:kyc:verify",
+		Execute: func(ctx context.Context, req *approval.Request) (any, *errors.Error) {
+			if err := s.VerifyKYC(ctx, req.ResourceID); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"kyc_status": models.KYCStatusVerified}, nil
+		},
+	})
+}
+
+// VerifyKYCOrRequestOverride verifies a user's KYC, unless it was rejected:
+// then the verification is submitted for approval and the pending request is
+// returned.
+func (s *AuthService) VerifyKYCOrRequestOverride(ctx context.Context, userID string) (*approval.Request, *errors.Error) {
+	kyc, err := s.kycRepo.GetByUserID(ctx, userID)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if kyc == nil || kyc.Status != models.KYCStatusRejected {
+		return nil, s.VerifyKYC(ctx, userID)
+	}
+
+	if s.approvals == nil {
+		return nil, errors.Internal("approval workflow is not configured")
+	}
+	return s.approvals.Submit(ctx, OperationKYCOverride, userID, struct{}{})
+}
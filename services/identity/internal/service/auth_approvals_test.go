@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+)
+
+// =====================================================================
+// Mock Approval Store
+// =====================================================================
+
+type mockApprovalStore struct {
+	requests map[string]*approval.Request
+}
+
+func newMockApprovalStore() *mockApprovalStore {
+	return &mockApprovalStore{requests: make(map[string]*approval.Request)}
+}
+
+func (m *mockApprovalStore) Create(ctx context.Context, req *approval.Request) *errors.Error {
+	stored := *req
+	m.requests[req.ID] = &stored
+	return nil
+}
+
+func (m *mockApprovalStore) Get(ctx context.Context, service, id string) (*approval.Request, *errors.Error) {
+	req, ok := m.requests[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("approval request", id)
+	}
+	copied := *req
+	return &copied, nil
+}
+
+func (m *mockApprovalStore) List(ctx context.Context, filter *approval.Filter) ([]*approval.Request, int64, *errors.Error) {
+	return nil, 0, nil
+}
+
+func (m *mockApprovalStore) Decide(ctx context.Context, req *approval.Request) *errors.Error {
+	if m.requests[req.ID].Status != approval.StatusPending {
+		return errors.Conflict("approval request has already been decided")
+	}
+	*m.requests[req.ID] = *req
+	return nil
+}
+
+func (m *mockApprovalStore) Complete(ctx context.Context, req *approval.Request) *errors.Error {
+	*m.requests[req.ID] = *req
+	return nil
+}
+
+func adminContext(userID string) context.Context {
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, userID)
+	return context.WithValue(ctx, middleware.UserPermissionsKey, []string{"identity:kyc:verify"})
+}
+
+// =====================================================================
+// KYC Override Tests
+// =====================================================================
+
+func TestVerifyKYCOrRequestOverride_PendingVerifiedDirectly(t *testing.T) {
+	service, userRepo, kycRepo, _, _ := setupTestAuthService()
+	service.SetApprovals(approval.NewWorkflow(newMockApprovalStore(), "identity"))
+
+	user := &models.User{ID: uuid.New().String(), Status: models.UserStatusPending}
+	addUserToMockRepo(userRepo, user)
+	kycRepo.kycData[user.ID] = &models.KYCInfo{UserID: user.ID, Status: models.KYCStatusPending}
+
+	req, err := service.VerifyKYCOrRequestOverride(adminContext("admin-1"), user.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if req != nil {
+		t.Errorf("expected no approval request for a pending KYC, got %+v", req)
+	}
+	if kycRepo.kycData[user.ID].Status != models.KYCStatusVerified {
+		t.Errorf("expected KYC verified, got %s", kycRepo.kycData[user.ID].Status)
+	}
+}
+
+func TestVerifyKYCOrRequestOverride_RejectedNeedsSecondAdmin(t *testing.T) {
+	service, userRepo, kycRepo, _, _ := setupTestAuthService()
+	workflow := approval.NewWorkflow(newMockApprovalStore(), "identity")
+	service.SetApprovals(workflow)
+
+	user := &models.User{ID: uuid.New().String(), Status: models.UserStatusPending}
+	addUserToMockRepo(userRepo, user)
+	kycRepo.kycData[user.ID] = &models.KYCInfo{UserID: user.ID, Status: models.KYCStatusRejected}
+
+	req, err := service.VerifyKYCOrRequestOverride(adminContext("maker"), user.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if req == nil || req.Operation != OperationKYCOverride || req.Status != approval.StatusPending {
+		t.Fatalf("expected pending override request, got %+v", req)
+	}
+	if kycRepo.kycData[user.ID].Status != models.KYCStatusRejected {
+		t.Fatal("KYC must stay rejected until the override is approved")
+	}
+
+	if _, err := workflow.Approve(adminContext("maker"), req.ID); err == nil || err.Code != errors.ErrCodeForbidden {
+		t.Fatalf("expected the maker to be refused, got %v", err)
+	}
+
+	approved, err := workflow.Approve(adminContext("checker"), req.ID)
+	if err != nil {
+		t.Fatalf("expected approval to succeed, got %v", err)
+	}
+	if approved.Status != approval.StatusExecuted {
+		t.Errorf("expected executed request, got %s", approved.Status)
+	}
+	if kycRepo.kycData[user.ID].Status != models.KYCStatusVerified {
+		t.Errorf("expected KYC verified after approval, got %s", kycRepo.kycData[user.ID].Status)
+	}
+}
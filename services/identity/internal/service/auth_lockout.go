@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/audit"
+	"github.com/vnykmshr/nivo/shared/cache"
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Login lockout for AuthService.
+//
+// Failed logins are counted per portal and identifier, including identifiers with
+// no account, and every response looks the same either way. From the
+// LoginDelayAfterFailures-th failure each attempt must wait longer than the last;
+// after MaxFailedLogins the identifier is locked for LoginLockoutDuration, even
+// for the right password, and the account owner is alerted. Wrong second-factor
+// codes are counted against the account's first identifier, and the second
+// factor is refused while any of its identifiers is locked. Failures are only
+// forgotten once a login succeeds in full.
+
+// LoginAttemptStore counts failed logins and holds delays and lockouts per identifier.
+type LoginAttemptStore interface {
+	RecordFailure(ctx context.Context, identifierHash string, window time.Duration) (int, *errors.Error)
+	Block(ctx context.Context, identifierHash string, until time.Time) *errors.Error
+	BlockedUntil(ctx context.Context, identifierHash string) (time.Time, *errors.Error)
+	Reset(ctx context.Context, identifierHash string) *errors.Error
+}
+
+// LoginAttemptRepositoryInterface is the Postgres login attempt store, which also keeps the audit log.
+type LoginAttemptRepositoryInterface interface {
+	LoginAttemptStore
+	CreateAuditEntry(ctx context.Context, entry *models.LoginAuditEntry) *errors.Error
+}
+
+// checkLoginAllowed rejects a login while the identifier is delayed or locked.
+// If the store is unavailable the login is allowed rather than locking everyone out.
+func (s *AuthService) checkLoginAllowed(ctx context.Context, identifierHash string) *errors.Error {
+	until, err := s.loginAttempts.BlockedUntil(ctx, identifierHash)
+	if err != nil || until.IsZero() {
+		return nil
+	}
+
+	retryAfter := int(math.Ceil(time.Until(until).Seconds()))
+	if retryAfter < 1 {
+		return nil
+	}
+
+	return errors.TooManyRequests("too many failed login attempts, please try again later").
+		WithDetails(map[string]interface{}{"retry_after": retryAfter})
+}
+
+// checkAccountLoginAllowed rejects a login while any of a user's identifiers is delayed or locked.
+func (s *AuthService) checkAccountLoginAllowed(ctx context.Context, user *models.User) *errors.Error {
+	for _, identifierHash := range loginAttemptKeysForUser(user) {
+		if err := s.checkLoginAllowed(ctx, identifierHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetLoginFailures forgets failed logins for a user's identifiers once a login has succeeded.
+func (s *AuthService) resetLoginFailures(ctx context.Context, user *models.User) {
+	for _, identifierHash := range loginAttemptKeysForUser(user) {
+		_ = s.loginAttempts.Reset(ctx, identifierHash)
+	}
+}
+
+// recordLoginFailure counts a failed login and applies any delay or lockout it earns.
+// user is nil when no account matched the identifier.
+func (s *AuthService) recordLoginFailure(ctx context.Context, identifierHash string, user *models.User, ipAddress, userAgent string) {
+	s.auditLogin(ctx, models.LoginAuditFailed, identifierHash, user, ipAddress, userAgent)
+
+	failures, err := s.loginAttempts.RecordFailure(ctx, identifierHash, models.LoginFailureWindow)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case failures >= models.MaxFailedLogins:
+		_ = s.loginAttempts.Block(ctx, identifierHash, time.Now().Add(models.LoginLockoutDuration))
+		if failures == models.MaxFailedLogins {
+			s.onLoginLocked(ctx, identifierHash, user, ipAddress, userAgent)
+		}
+	case failures >= models.LoginDelayAfterFailures:
+		_ = s.loginAttempts.Block(ctx, identifierHash, time.Now().Add(loginDelay(failures)))
+	}
+}
+
+// onLoginLocked audits a lockout and alerts the account owner, if there is one.
+func (s *AuthService) onLoginLocked(ctx context.Context, identifierHash string, user *models.User, ipAddress, userAgent string) {
+	s.auditLogin(ctx, models.LoginAuditLocked, identifierHash, user, ipAddress, userAgent)
+
+	if user == nil {
+		return
+	}
+
+	if s.eventPublisher != nil {
+		s.eventPublisher.PublishUserEvent("user.login_locked", user.ID, map[string]interface{}{
+			"locked_until": time.Now().Add(models.LoginLockoutDuration).Unix(),
+			"ip_address":   ipAddress,
+		})
+	}
+
+	if s.notificationClient != nil && user.Email != "" {
+		correlationID := fmt.Sprintf("login-locked-%s-%d", user.ID, time.Now().Unix())
+		emailReq := &clients.SendNotificationRequest{
+			UserID:     &user.ID,
+			Recipient:  user.Email,
+			Channel:    clients.NotificationChannelEmail,
+			Type:       clients.NotificationTypeSecurityAlert,
+			Priority:   clients.NotificationPriorityHigh,
+			TemplateID: "account_alert_email",
+			Variables: map[string]interface{}{
+				"user_name":  user.FullName,
+				"alert_type": "Sign-in locked",
+				"message": fmt.Sprintf("Sign-in to your account was locked for %d minutes after %d failed sign-in attempts, the last from IP address %s",
+					int(models.LoginLockoutDuration.Minutes()), models.MaxFailedLogins, ipAddress),
+				"date": time.Now().Format("02 Jan 2006, 03:04 PM"),
+			},
+			CorrelationID: &correlationID,
+			SourceService: "identity",
+		}
+		s.notificationClient.SendNotificationAsync(emailReq, "identity")
+	}
+}
+
+// UnlockLogin clears failed logins and any lockout for a user's identifiers.
+func (s *AuthService) UnlockLogin(ctx context.Context, userID, adminID string) *errors.Error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, identifierHash := range loginAttemptKeysForUser(user) {
+		if err := s.loginAttempts.Reset(ctx, identifierHash); err != nil {
+			return err
+		}
+	}
+
+	if err := s.loginAttemptRepo.CreateAuditEntry(ctx, &models.LoginAuditEntry{
+		Event:   models.LoginAuditUnlocked,
+		UserID:  &user.ID,
+		ActorID: &adminID,
+	}); err != nil {
+		return err
+	}
+
+	if s.eventPublisher != nil {
+		s.eventPublisher.PublishUserEvent("user.login_unlocked", user.ID, map[string]interface{}{
+			"unlocked_by": adminID,
+		})
+	}
+
+	s.recordAudit(ctx, audit.Event{
+		Action:       "user.unlock_login",
+		ResourceType: "user",
+		ResourceID:   user.ID,
+	})
+
+	return nil
+}
+
+// auditLogin writes a login audit entry. Failures are ignored so auditing never blocks a login.
+func (s *AuthService) auditLogin(ctx context.Context, event models.LoginAuditEvent, identifierHash string, user *models.User, ipAddress, userAgent string) {
+	entry := &models.LoginAuditEntry{
+		Event:          event,
+		IdentifierHash: &identifierHash,
+		IPAddress:      ipAddress,
+		UserAgent:      userAgent,
+	}
+	if user != nil {
+		entry.UserID = &user.ID
+	}
+	_ = s.loginAttemptRepo.CreateAuditEntry(ctx, entry)
+}
+
+// loginDelay is how long the next attempt must wait after the given number of
+// failures: one second at LoginDelayAfterFailures, doubling up to MaxLoginDelay.
+func loginDelay(failures int) time.Duration {
+	shift := failures - models.LoginDelayAfterFailures
+	if shift >= 16 {
+		return models.MaxLoginDelay
+	}
+	return min(time.Second<<shift, models.MaxLoginDelay)
+}
+
+// loginAttemptKey identifies the target of a login attempt without storing the identifier itself.
+func loginAttemptKey(portal models.PortalType, identifier string) string {
+	normalized := string(portal) + ":" + strings.ToLower(strings.TrimSpace(identifier))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+// loginAttemptKeysForUser returns the keys a user's logins are counted under.
+func loginAttemptKeysForUser(user *models.User) []string {
+	if user.AccountType != models.AccountTypeUser {
+		return []string{loginAttemptKey(models.PortalTypeAdmin, user.Email)}
+	}
+
+	keys := []string{loginAttemptKey(models.PortalTypeUser, user.Email)}
+	if user.Phone != "" {
+		keys = append(keys, loginAttemptKey(models.PortalTypeUser, user.Phone))
+	}
+	return keys
+}
+
+// dummyPasswordHash is compared against when no account matches, so a login for
+// an unknown identifier takes as long as one with a wrong password.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("nivo-dummy-password"), bcrypt.DefaultCost)
+	return string(hash)
+})
+
+// cacheLoginAttemptStore keeps login attempt state in the shared cache (Redis).
+type cacheLoginAttemptStore struct {
+	cache cache.Cache
+}
+
+func (c *cacheLoginAttemptStore) RecordFailure(ctx context.Context, identifierHash string, window time.Duration) (int, *errors.Error) {
+	failures, err := c.cache.Increment(ctx, cache.LoginFailuresKey(identifierHash), window)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrCodeUnavailable, "failed to record login failure")
+	}
+	return int(failures), nil
+}
+
+func (c *cacheLoginAttemptStore) Block(ctx context.Context, identifierHash string, until time.Time) *errors.Error {
+	value := strconv.FormatInt(until.UnixMilli(), 10)
+	if err := c.cache.Set(ctx, cache.LoginBlockKey(identifierHash), value, time.Until(until)); err != nil {
+		return errors.Wrap(err, errors.ErrCodeUnavailable, "failed to block login")
+	}
+	return nil
+}
+
+func (c *cacheLoginAttemptStore) BlockedUntil(ctx context.Context, identifierHash string) (time.Time, *errors.Error) {
+	value, found, err := c.cache.Get(ctx, cache.LoginBlockKey(identifierHash))
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, errors.ErrCodeUnavailable, "failed to get login block")
+	}
+	if !found {
+		return time.Time{}, nil
+	}
+
+	millis, parseErr := strconv.ParseInt(value, 10, 64)
+	if parseErr != nil {
+		return time.Time{}, nil
+	}
+	return time.UnixMilli(millis), nil
+}
+
+func (c *cacheLoginAttemptStore) Reset(ctx context.Context, identifierHash string) *errors.Error {
+	if err := c.cache.Delete(ctx, cache.LoginFailuresKey(identifierHash)); err != nil {
+		return errors.Wrap(err, errors.ErrCodeUnavailable, "failed to reset login failures")
+	}
+	if err := c.cache.Delete(ctx, cache.LoginBlockKey(identifierHash)); err != nil {
+		return errors.Wrap(err, errors.ErrCodeUnavailable, "failed to reset login block")
+	}
+	return nil
+}
+
+// fallbackLoginAttemptStore uses the cache and falls back to Postgres when the cache errors.
+type fallbackLoginAttemptStore struct {
+	primary  LoginAttemptStore
+	fallback LoginAttemptStore
+}
+
+func (f *fallbackLoginAttemptStore) RecordFailure(ctx context.Context, identifierHash string, window time.Duration) (int, *errors.Error) {
+	failures, err := f.primary.RecordFailure(ctx, identifierHash, window)
+	if err != nil {
+		return f.fallback.RecordFailure(ctx, identifierHash, window)
+	}
+	return failures, nil
+}
+
+func (f *fallbackLoginAttemptStore) Block(ctx context.Context, identifierHash string, until time.Time) *errors.Error {
+	if err := f.primary.Block(ctx, identifierHash, until); err != nil {
+		return f.fallback.Block(ctx, identifierHash, until)
+	}
+	return nil
+}
+
+func (f *fallbackLoginAttemptStore) BlockedUntil(ctx context.Context, identifierHash string) (time.Time, *errors.Error) {
+	until, err := f.primary.BlockedUntil(ctx, identifierHash)
+	if err != nil {
+		return f.fallback.BlockedUntil(ctx, identifierHash)
+	}
+	return until, nil
+}
+
+// Reset clears both stores, since state may have been written to either.
+func (f *fallbackLoginAttemptStore) Reset(ctx context.Context, identifierHash string) *errors.Error {
+	primaryErr := f.primary.Reset(ctx, identifierHash)
+	if err := f.fallback.Reset(ctx, identifierHash); err != nil {
+		return err
+	}
+	return primaryErr
+}
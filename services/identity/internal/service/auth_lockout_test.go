@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// =====================================================================
+// Mock Login Attempt Repository
+// =====================================================================
+
+type mockLoginAttemptRepository struct {
+	failures     map[string]int
+	blockedUntil map[string]time.Time
+	auditLog     []*models.LoginAuditEntry
+}
+
+func newMockLoginAttemptRepository() *mockLoginAttemptRepository {
+	return &mockLoginAttemptRepository{
+		failures:     make(map[string]int),
+		blockedUntil: make(map[string]time.Time),
+	}
+}
+
+func (m *mockLoginAttemptRepository) RecordFailure(ctx context.Context, identifierHash string, window time.Duration) (int, *errors.Error) {
+	m.failures[identifierHash]++
+	return m.failures[identifierHash], nil
+}
+
+func (m *mockLoginAttemptRepository) Block(ctx context.Context, identifierHash string, until time.Time) *errors.Error {
+	m.blockedUntil[identifierHash] = until
+	return nil
+}
+
+func (m *mockLoginAttemptRepository) BlockedUntil(ctx context.Context, identifierHash string) (time.Time, *errors.Error) {
+	until, ok := m.blockedUntil[identifierHash]
+	if !ok || until.Before(time.Now()) {
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+func (m *mockLoginAttemptRepository) Reset(ctx context.Context, identifierHash string) *errors.Error {
+	delete(m.failures, identifierHash)
+	delete(m.blockedUntil, identifierHash)
+	return nil
+}
+
+func (m *mockLoginAttemptRepository) CreateAuditEntry(ctx context.Context, entry *models.LoginAuditEntry) *errors.Error {
+	entry.ID = uuid.New().String()
+	m.auditLog = append(m.auditLog, entry)
+	return nil
+}
+
+// expireBlocks lifts every delay, standing in for the wait between attempts.
+func (m *mockLoginAttemptRepository) expireBlocks() {
+	for key := range m.blockedUntil {
+		delete(m.blockedUntil, key)
+	}
+}
+
+func (m *mockLoginAttemptRepository) countAudit(event models.LoginAuditEvent) int {
+	count := 0
+	for _, entry := range m.auditLog {
+		if entry.Event == event {
+			count++
+		}
+	}
+	return count
+}
+
+// failingCache is a cache whose every call fails, as when Redis is down.
+type failingCache struct {
+	memoryCache
+}
+
+var errCacheDown = stderrors.New("cache unavailable")
+
+func (c *failingCache) Get(ctx context.Context, key string) (string, bool, error) {
+	return "", false, errCacheDown
+}
+
+func (c *failingCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return errCacheDown
+}
+
+func (c *failingCache) Delete(ctx context.Context, key string) error { return errCacheDown }
+
+func (c *failingCache) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return 0, errCacheDown
+}
+
+// =====================================================================
+// Tests
+// =====================================================================
+
+func attemptLogin(service *AuthService, identifier, password string) *errors.Error {
+	_, err := service.Login(context.Background(), &models.LoginRequest{
+		Identifier: identifier,
+		Password:   password,
+	}, "203.0.113.7", chromeWindowsUA)
+	return err
+}
+
+func TestLogin_ProgressiveDelay(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	attempts := service.loginAttemptRepo.(*mockLoginAttemptRepository)
+
+	user, password := createSessionTestUser(userRepo)
+
+	for i := 0; i < models.LoginDelayAfterFailures; i++ {
+		if err := attemptLogin(service, user.Email, "WrongPassword1!"); err == nil || err.Code != errors.ErrCodeUnauthorized {
+			t.Fatalf("attempt %d: expected unauthorized error, got %v", i+1, err)
+		}
+	}
+
+	err := attemptLogin(service, user.Email, password)
+	if err == nil {
+		t.Fatal("expected login to be delayed, got nil")
+	}
+	if err.Code != errors.ErrCodeRateLimit {
+		t.Fatalf("expected rate limit error, got %s", err.Code)
+	}
+	if retryAfter, ok := err.Details["retry_after"].(int); !ok || retryAfter < 1 {
+		t.Errorf("expected positive retry_after, got %v", err.Details["retry_after"])
+	}
+
+	// Once the delay has passed the right password works and clears the count
+	attempts.expireBlocks()
+	if err := attemptLogin(service, user.Email, password); err != nil {
+		t.Fatalf("expected login to succeed after the delay, got %v", err)
+	}
+	if len(attempts.failures) != 0 {
+		t.Errorf("expected failures to be reset after login, got %v", attempts.failures)
+	}
+}
+
+func TestLogin_LockoutAfterMaxFailures(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	attempts := service.loginAttemptRepo.(*mockLoginAttemptRepository)
+
+	user, password := createSessionTestUser(userRepo)
+
+	for i := 0; i < models.MaxFailedLogins; i++ {
+		attempts.expireBlocks()
+		if err := attemptLogin(service, user.Email, "WrongPassword1!"); err == nil || err.Code != errors.ErrCodeUnauthorized {
+			t.Fatalf("attempt %d: expected unauthorized error, got %v", i+1, err)
+		}
+	}
+
+	err := attemptLogin(service, user.Email, password)
+	if err == nil || err.Code != errors.ErrCodeRateLimit {
+		t.Fatalf("expected locked login to be rejected with the right password, got %v", err)
+	}
+	if retryAfter, _ := err.Details["retry_after"].(int); retryAfter < int(models.LoginLockoutDuration.Seconds())-5 {
+		t.Errorf("expected retry_after close to the lockout duration, got %d", retryAfter)
+	}
+
+	if got := attempts.countAudit(models.LoginAuditFailed); got != models.MaxFailedLogins {
+		t.Errorf("expected %d failed login audit entries, got %d", models.MaxFailedLogins, got)
+	}
+	if got := attempts.countAudit(models.LoginAuditLocked); got != 1 {
+		t.Errorf("expected 1 lockout audit entry, got %d", got)
+	}
+	for _, entry := range attempts.auditLog {
+		if entry.UserID == nil || *entry.UserID != user.ID {
+			t.Errorf("expected audit entry to reference the user, got %v", entry.UserID)
+		}
+	}
+}
+
+func TestLogin_UnknownIdentifierLooksTheSame(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	attempts := service.loginAttemptRepo.(*mockLoginAttemptRepository)
+
+	user, _ := createSessionTestUser(userRepo)
+
+	for _, identifier := range []string{user.Email, "nobody@example.com"} {
+		var errs []*errors.Error
+		for i := 0; i <= models.LoginDelayAfterFailures; i++ {
+			errs = append(errs, attemptLogin(service, identifier, "WrongPassword1!"))
+		}
+
+		for i, err := range errs[:models.LoginDelayAfterFailures] {
+			if err == nil || err.Code != errors.ErrCodeUnauthorized || err.Message != "invalid credentials" {
+				t.Errorf("%s attempt %d: expected invalid credentials, got %v", identifier, i+1, err)
+			}
+		}
+		if last := errs[models.LoginDelayAfterFailures]; last == nil || last.Code != errors.ErrCodeRateLimit {
+			t.Errorf("%s: expected delayed attempt to be rate limited, got %v", identifier, last)
+		}
+	}
+
+	// The unknown identifier is audited without a user
+	var anonymous int
+	for _, entry := range attempts.auditLog {
+		if entry.UserID == nil {
+			anonymous++
+		}
+	}
+	if anonymous != models.LoginDelayAfterFailures {
+		t.Errorf("expected %d audit entries without a user, got %d", models.LoginDelayAfterFailures, anonymous)
+	}
+}
+
+func TestUnlockLogin(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	attempts := service.loginAttemptRepo.(*mockLoginAttemptRepository)
+	ctx := context.Background()
+
+	user, password := createSessionTestUser(userRepo)
+	key := loginAttemptKey(models.PortalTypeUser, user.Email)
+	attempts.failures[key] = models.MaxFailedLogins
+	attempts.blockedUntil[key] = time.Now().Add(models.LoginLockoutDuration)
+
+	if err := attemptLogin(service, user.Email, password); err == nil || err.Code != errors.ErrCodeRateLimit {
+		t.Fatalf("expected locked login to be rejected, got %v", err)
+	}
+
+	adminID := uuid.New().String()
+	if err := service.UnlockLogin(ctx, user.ID, adminID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := attemptLogin(service, user.Email, password); err != nil {
+		t.Fatalf("expected login to succeed after unlock, got %v", err)
+	}
+
+	if attempts.countAudit(models.LoginAuditUnlocked) != 1 {
+		t.Fatal("expected an unlock audit entry")
+	}
+	for _, entry := range attempts.auditLog {
+		if entry.Event == models.LoginAuditUnlocked && (entry.ActorID == nil || *entry.ActorID != adminID) {
+			t.Errorf("expected unlock to be attributed to the admin, got %v", entry.ActorID)
+		}
+	}
+}
+
+func TestUnlockLogin_UserNotFound(t *testing.T) {
+	service, _, _, _, _ := setupTestAuthService()
+
+	err := service.UnlockLogin(context.Background(), uuid.New().String(), uuid.New().String())
+	if err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestLoginAttempts_FallBackWhenCacheFails(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	attempts := service.loginAttemptRepo.(*mockLoginAttemptRepository)
+	service.SetCache(&failingCache{})
+
+	user, _ := createSessionTestUser(userRepo)
+
+	for i := 0; i <= models.LoginDelayAfterFailures; i++ {
+		_ = attemptLogin(service, user.Email, "WrongPassword1!")
+	}
+
+	if got := attempts.failures[loginAttemptKey(models.PortalTypeUser, user.Email)]; got != models.LoginDelayAfterFailures {
+		t.Errorf("expected failures to be counted in the fallback store, got %d", got)
+	}
+	if err := attemptLogin(service, user.Email, "WrongPassword1!"); err == nil || err.Code != errors.ErrCodeRateLimit {
+		t.Errorf("expected the fallback store to enforce the delay, got %v", err)
+	}
+}
+
+func TestLoginAttempts_UsesCache(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	attempts := service.loginAttemptRepo.(*mockLoginAttemptRepository)
+	service.SetCache(newMemoryCache())
+
+	user, _ := createSessionTestUser(userRepo)
+
+	for i := 0; i < models.LoginDelayAfterFailures; i++ {
+		_ = attemptLogin(service, user.Email, "WrongPassword1!")
+	}
+
+	if len(attempts.failures) != 0 {
+		t.Errorf("expected failures to stay in the cache, got %v", attempts.failures)
+	}
+	if err := attemptLogin(service, user.Email, "WrongPassword1!"); err == nil || err.Code != errors.ErrCodeRateLimit {
+		t.Errorf("expected the cache store to enforce the delay, got %v", err)
+	}
+}
+
+func TestLoginDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{models.LoginDelayAfterFailures, time.Second},
+		{models.LoginDelayAfterFailures + 1, 2 * time.Second},
+		{models.LoginDelayAfterFailures + 3, 8 * time.Second},
+		{models.LoginDelayAfterFailures + 5, models.MaxLoginDelay},
+		{100, models.MaxLoginDelay},
+	}
+
+	for _, tt := range tests {
+		if got := loginDelay(tt.failures); got != tt.want {
+			t.Errorf("loginDelay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginAttemptKey_Normalizes(t *testing.T) {
+	if loginAttemptKey(models.PortalTypeUser, " User@Example.com ") != loginAttemptKey(models.PortalTypeUser, "user@example.com") {
+		t.Error("expected identifiers differing in case and whitespace to share a key")
+	}
+	if loginAttemptKey(models.PortalTypeUser, "user@example.com") == loginAttemptKey(models.PortalTypeAdmin, "user@example.com") {
+		t.Error("expected portals to have separate keys")
+	}
+}
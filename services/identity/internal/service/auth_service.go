@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -10,14 +11,19 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/vnykmshr/nivo/services/identity/internal/models"
 	"github.com/vnykmshr/nivo/services/identity/internal/repository"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/audit"
 	"github.com/vnykmshr/nivo/shared/cache"
 	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/crypto"
 	"github.com/vnykmshr/nivo/shared/errors"
 	"github.com/vnykmshr/nivo/shared/events"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	sharedModels "github.com/vnykmshr/nivo/shared/models"
 )
 
@@ -53,6 +59,28 @@ type SessionRepositoryInterface interface {
 	GetByTokenHash(ctx context.Context, tokenHash string) (*models.Session, *errors.Error)
 	DeleteByTokenHash(ctx context.Context, tokenHash string) *errors.Error
 	DeleteByUserID(ctx context.Context, userID string) *errors.Error
+	ListByUserID(ctx context.Context, userID string) ([]*models.Session, *errors.Error)
+	DeleteByID(ctx context.Context, userID, sessionID string) (string, *errors.Error)
+	RecordDevice(ctx context.Context, userID, deviceName string) (bool, *errors.Error)
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) *errors.Error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, *errors.Error)
+	RotateRefreshToken(ctx context.Context, used, next *models.RefreshToken, accessTokenHash string) (string, *errors.Error)
+}
+
+// TwoFactorRepositoryInterface defines the interface for TOTP, recovery code and login challenge operations.
+type TwoFactorRepositoryInterface interface {
+	GetByUserID(ctx context.Context, userID string) (*models.TwoFactor, *errors.Error)
+	SavePending(ctx context.Context, userID, secret string) *errors.Error
+	Enable(ctx context.Context, userID string, step int64, codeHashes []string) *errors.Error
+	UseStep(ctx context.Context, userID string, step int64) *errors.Error
+	Delete(ctx context.Context, userID string) *errors.Error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) *errors.Error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) *errors.Error
+	CountUnusedRecoveryCodes(ctx context.Context, userID string) (int, *errors.Error)
+	CreateChallenge(ctx context.Context, challenge *models.LoginChallenge) *errors.Error
+	GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*models.LoginChallenge, *errors.Error)
+	RecordChallengeFailure(ctx context.Context, id string) (int, *errors.Error)
+	ConsumeChallenge(ctx context.Context, id string) *errors.Error
 }
 
 // RBACClientInterface defines the interface for RBAC client operations.
@@ -77,19 +105,57 @@ type AuthService struct {
 	userAdminRepo      UserAdminRepositoryInterface
 	kycRepo            KYCRepositoryInterface
 	sessionRepo        SessionRepositoryInterface
+	twoFactorRepo      TwoFactorRepositoryInterface
+	loginAttemptRepo   LoginAttemptRepositoryInterface
+	loginAttempts      LoginAttemptStore // loginAttemptRepo, or the cache backed by it
 	rbacClient         RBACClientInterface
 	walletClient       *WalletClient
 	notificationClient *clients.NotificationClient
-	jwtSecret          string
-	jwtExpiry          time.Duration
+	signingKeys        *sharedjwt.KeySet // Signs access tokens; public keys are served as a JWKS
+	jwtExpiry          time.Duration     // Access token lifetime
+	refreshExpiry      time.Duration     // Refresh token (and session) lifetime
 	eventPublisher     *events.Publisher
-	cache              cache.Cache // Optional cache for session/user data
+	cache              cache.Cache          // Optional cache for session/user data
+	auditLog           audit.Recorder       // Optional durable record of admin actions
+	approvals          *approval.Workflow   // Second-admin approval of KYC overrides
+	totpCipher         *crypto.SecretCipher // Encrypts TOTP secrets at rest
+}
+
+// DefaultRefreshTokenExpiry is how long a refresh token stays valid without being used.
+const DefaultRefreshTokenExpiry = 30 * 24 * time.Hour
+
+// SetRefreshTokenExpiry overrides DefaultRefreshTokenExpiry.
+// Each refresh issues a token with a full lifetime, so active sessions don't expire.
+func (s *AuthService) SetRefreshTokenExpiry(d time.Duration) {
+	s.refreshExpiry = d
+}
+
+// JWKS returns the public keys access tokens can be verified with.
+func (s *AuthService) JWKS() *sharedjwt.JWKS {
+	return s.signingKeys.JWKS()
 }
 
 // SetCache sets the cache for session and user data caching.
 // This is optional - if not set, all lookups go directly to the database.
 func (s *AuthService) SetCache(c cache.Cache) {
 	s.cache = c
+	s.loginAttempts = &fallbackLoginAttemptStore{
+		primary:  &cacheLoginAttemptStore{cache: c},
+		fallback: s.loginAttemptRepo,
+	}
+}
+
+// SetAuditLog sets where admin actions (suspensions, KYC decisions, unlocks)
+// are recorded. This is optional - if not set, they are not recorded.
+func (s *AuthService) SetAuditLog(auditLog audit.Recorder) {
+	s.auditLog = auditLog
+}
+
+// recordAudit records an admin action if an audit log is set.
+func (s *AuthService) recordAudit(ctx context.Context, event audit.Event) {
+	if s.auditLog != nil {
+		s.auditLog.Record(ctx, event)
+	}
 }
 
 // NewAuthService creates a new authentication service.
@@ -98,10 +164,12 @@ func NewAuthService(
 	userAdminRepo UserAdminRepositoryInterface,
 	kycRepo KYCRepositoryInterface,
 	sessionRepo SessionRepositoryInterface,
+	twoFactorRepo TwoFactorRepositoryInterface,
+	loginAttemptRepo LoginAttemptRepositoryInterface,
 	rbacClient RBACClientInterface,
 	walletClient *WalletClient,
 	notificationClient *clients.NotificationClient,
-	jwtSecret string,
+	signingKeys *sharedjwt.KeySet,
 	jwtExpiry time.Duration,
 	eventPublisher *events.Publisher,
 ) *AuthService {
@@ -110,11 +178,15 @@ func NewAuthService(
 		userAdminRepo:      userAdminRepo,
 		kycRepo:            kycRepo,
 		sessionRepo:        sessionRepo,
+		twoFactorRepo:      twoFactorRepo,
+		loginAttemptRepo:   loginAttemptRepo,
+		loginAttempts:      loginAttemptRepo,
 		rbacClient:         rbacClient,
 		walletClient:       walletClient,
 		notificationClient: notificationClient,
-		jwtSecret:          jwtSecret,
+		signingKeys:        signingKeys,
 		jwtExpiry:          jwtExpiry,
+		refreshExpiry:      DefaultRefreshTokenExpiry,
 		eventPublisher:     eventPublisher,
 	}
 }
@@ -271,6 +343,12 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 		portal = models.PortalTypeUser
 	}
 
+	// Refuse early while the identifier is delayed or locked out
+	attemptKey := loginAttemptKey(portal, req.Identifier)
+	if err := s.checkLoginAllowed(ctx, attemptKey); err != nil {
+		return nil, err
+	}
+
 	// Determine if identifier is email or phone
 	// Phone numbers start with +91 for India
 	var user *models.User
@@ -303,12 +381,15 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 	}
 
 	if err != nil {
-		// Don't reveal if user exists
+		// Don't reveal if user exists: take as long as a password check and count the failure
+		s.verifyPassword(req.Password, dummyPasswordHash())
+		s.recordLoginFailure(ctx, attemptKey, nil, ipAddress, userAgent)
 		return nil, errors.Unauthorized("invalid credentials")
 	}
 
 	// Verify password
 	if !s.verifyPassword(req.Password, user.PasswordHash) {
+		s.recordLoginFailure(ctx, attemptKey, user, ipAddress, userAgent)
 		return nil, errors.Unauthorized("invalid credentials")
 	}
 
@@ -321,42 +402,55 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 		return nil, errors.Forbidden("account is suspended")
 	}
 
-	// Fetch user permissions from RBAC service
-	var roles []string
-	var permissions []string
+	// Hold back the JWT until the second factor is presented (or enrolled, for admins).
+	// Earlier failures still count until then.
+	challenge, err := s.twoFactorChallenge(ctx, user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
 
-	userPerms, rbacErr := s.rbacClient.GetUserPermissions(ctx, user.ID)
-	if rbacErr == nil {
-		// Extract role names
-		for _, role := range userPerms.Roles {
-			roles = append(roles, role.Name)
-		}
-		// Extract permission names
-		for _, perm := range userPerms.Permissions {
-			permissions = append(permissions, perm.Name)
-		}
+	// The password alone is enough: forget earlier failures
+	_ = s.loginAttempts.Reset(ctx, attemptKey)
+
+	return s.completeLogin(ctx, user, ipAddress, userAgent)
+}
+
+// completeLogin issues a JWT and session for a user who has passed every login check.
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User, ipAddress, userAgent string) (*models.LoginResponse, *errors.Error) {
+	token, expiresAt, err := s.issueAccessToken(ctx, user)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate JWT token with roles and permissions
-	token, expiresAt, genErr := s.generateToken(user, roles, permissions)
+	refreshToken, refreshExpiresAt, genErr := s.generateRefreshToken()
 	if genErr != nil {
-		return nil, errors.Internal("failed to generate token")
+		return nil, errors.Internal("failed to generate refresh token")
 	}
 
-	// Create session
-	tokenHash := s.hashToken(token)
+	// Create session; it lives as long as its refresh token and is the token family
 	session := &models.Session{
 		UserID:    user.ID,
-		Token:     tokenHash,
+		Token:     s.hashToken(token),
 		IPAddress: ipAddress,
 		UserAgent: userAgent,
-		ExpiresAt: sharedModels.NewTimestamp(time.Unix(expiresAt, 0)),
+		ExpiresAt: sharedModels.NewTimestamp(refreshExpiresAt),
 	}
 
 	if err := s.sessionRepo.Create(ctx, session); err != nil {
 		return nil, err
 	}
 
+	if err := s.sessionRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		SessionID: session.ID,
+		UserID:    user.ID,
+		TokenHash: s.hashToken(refreshToken),
+		ExpiresAt: sharedModels.NewTimestamp(refreshExpiresAt),
+	}); err != nil {
+		return nil, err
+	}
+
+	s.recordLoginDevice(ctx, user, session)
+
 	// Load KYC info if available (for regular users only)
 	if user.AccountType == models.AccountTypeUser {
 		kyc, err := s.kycRepo.GetByUserID(ctx, user.ID)
@@ -370,10 +464,12 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 
 	// Build login response with account type-specific information
 	response := &models.LoginResponse{
-		Token:       token,
-		ExpiresAt:   expiresAt,
-		User:        user,
-		AccountType: user.AccountType,
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt.Unix(),
+		User:             user,
+		AccountType:      user.AccountType,
 	}
 
 	// Add account type-specific information
@@ -399,39 +495,124 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 	return response, nil
 }
 
+// Refresh exchanges a refresh token for a new access token and a new refresh token.
+// Refresh tokens are single-use: presenting one that was already rotated means it
+// was stolen or replayed, so the whole session (token family) is revoked.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.TokenResponse, *errors.Error) {
+	current, err := s.sessionRepo.GetRefreshTokenByHash(ctx, s.hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if current.UsedAt != nil {
+		s.revokeTokenFamily(ctx, current)
+		return nil, errors.Unauthorized("refresh token has already been used; please log in again")
+	}
+
+	if time.Now().After(current.ExpiresAt.Time) {
+		return nil, errors.Unauthorized("refresh token expired")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, current.UserID)
+	if err != nil {
+		return nil, errors.Unauthorized("invalid refresh token")
+	}
+
+	if user.Status == models.UserStatusClosed || user.Status == models.UserStatusSuspended {
+		s.revokeTokenFamily(ctx, current)
+		return nil, errors.Forbidden("account is not active")
+	}
+
+	// New access token picks up any role, permission or status changes
+	token, expiresAt, err := s.issueAccessToken(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	nextToken, nextExpiresAt, genErr := s.generateRefreshToken()
+	if genErr != nil {
+		return nil, errors.Internal("failed to generate refresh token")
+	}
+
+	next := &models.RefreshToken{
+		SessionID: current.SessionID,
+		UserID:    user.ID,
+		TokenHash: s.hashToken(nextToken),
+		ExpiresAt: sharedModels.NewTimestamp(nextExpiresAt),
+	}
+
+	previousHash, err := s.sessionRepo.RotateRefreshToken(ctx, current, next, s.hashToken(token))
+	if err != nil {
+		if errors.IsUnauthorized(err) {
+			// Lost a race with another use of the same token: treat it as reuse
+			s.revokeTokenFamily(ctx, current)
+		}
+		return nil, err
+	}
+
+	// The replaced access token no longer has a session
+	s.evictToken(ctx, previousHash)
+
+	return &models.TokenResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     nextToken,
+		RefreshExpiresAt: nextExpiresAt.Unix(),
+	}, nil
+}
+
+// revokeTokenFamily deletes the session a refresh token belongs to, which
+// revokes its access token and every refresh token issued for it.
+func (s *AuthService) revokeTokenFamily(ctx context.Context, token *models.RefreshToken) {
+	accessHash, err := s.sessionRepo.DeleteByID(ctx, token.UserID, token.SessionID)
+	if err != nil {
+		return // Already revoked
+	}
+
+	s.evictToken(ctx, accessHash)
+
+	if s.eventPublisher != nil {
+		s.eventPublisher.PublishUserEvent("user.session_revoked", token.UserID, map[string]interface{}{
+			"session_id": token.SessionID,
+			"reason":     "refresh_token_reuse_or_inactive_account",
+		})
+	}
+}
+
 // Logout invalidates a user's session.
 func (s *AuthService) Logout(ctx context.Context, token string) *errors.Error {
 	tokenHash := s.hashToken(token)
 
 	// Invalidate cache entry
-	if s.cache != nil {
-		_ = s.cache.Delete(ctx, cache.TokenKey(tokenHash))
-	}
+	s.evictToken(ctx, tokenHash)
 
 	return s.sessionRepo.DeleteByTokenHash(ctx, tokenHash)
 }
 
 // LogoutAll invalidates all sessions for a user.
 func (s *AuthService) LogoutAll(ctx context.Context, userID string) *errors.Error {
-	// Note: Cache entries will expire naturally (TTL-based)
-	// For immediate invalidation of all user sessions, we'd need to track
-	// all token hashes per user. For now, rely on session DB check.
-	return s.sessionRepo.DeleteByUserID(ctx, userID)
+	sessions, err := s.sessionRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	// Evict cached validations so the tokens stop working immediately
+	for _, session := range sessions {
+		s.evictToken(ctx, session.Token)
+	}
+
+	return nil
 }
 
 // ValidateToken validates a JWT token and returns the user.
 func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*models.User, *errors.Error) {
 	// Parse and validate JWT
 	claims := &JWTClaims{}
-	token, parseErr := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.jwtSecret), nil
-	})
-
-	if parseErr != nil || !token.Valid {
+	if parseErr := sharedjwt.ParseToken(tokenString, claims, s.signingKeys); parseErr != nil {
 		return nil, errors.Unauthorized("invalid token")
 	}
 
@@ -443,7 +624,7 @@ func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*m
 			// Verify user status (could have changed)
 			if user.Status == models.UserStatusClosed || user.Status == models.UserStatusSuspended {
 				// Invalidate cache for suspended/closed users
-				_ = s.cache.Delete(ctx, cache.SessionKey(user.ID, tokenHash))
+				s.evictToken(ctx, tokenHash)
 				return nil, errors.Forbidden("account is not active")
 			}
 			return user, nil
@@ -500,6 +681,15 @@ func (s *AuthService) getUserFromCache(ctx context.Context, tokenHash string) (*
 	return &user, true
 }
 
+// evictToken removes the cached validation for an access token hash, so a
+// revoked session is rejected on its next request rather than after the cache TTL.
+func (s *AuthService) evictToken(ctx context.Context, tokenHash string) {
+	if s.cache == nil || tokenHash == "" {
+		return
+	}
+	_ = s.cache.Delete(ctx, cache.TokenKey(tokenHash))
+}
+
 // cacheUser stores user data in cache.
 func (s *AuthService) cacheUser(ctx context.Context, tokenHash string, user *models.User) {
 	cacheKey := cache.TokenKey(tokenHash)
@@ -617,6 +807,8 @@ func (s *AuthService) VerifyKYC(ctx context.Context, userID string) *errors.Erro
 		return err
 	}
 
+	before := s.kycAuditSnapshot(ctx, user)
+
 	// Update KYC status
 	if err := s.kycRepo.UpdateStatus(ctx, userID, models.KYCStatusVerified, ""); err != nil {
 		return err
@@ -643,6 +835,17 @@ func (s *AuthService) VerifyKYC(ctx context.Context, userID string) *errors.Erro
 		})
 	}
 
+	s.recordAudit(ctx, audit.Event{
+		Action:       "kyc.verify",
+		ResourceType: "user",
+		ResourceID:   userID,
+		Before:       before,
+		After: map[string]interface{}{
+			"status":     models.UserStatusActive,
+			"kyc_status": models.KYCStatusVerified,
+		},
+	})
+
 	// Activate user's wallets (KYC approval unlocks wallet functionality)
 	if s.walletClient != nil {
 		wallets, walletErr := s.walletClient.ListUserWallets(ctx, userID)
@@ -706,10 +909,24 @@ func (s *AuthService) RejectKYC(ctx context.Context, userID string, reason strin
 		return err
 	}
 
+	before := s.kycAuditSnapshot(ctx, user)
+
 	if err := s.kycRepo.UpdateStatus(ctx, userID, models.KYCStatusRejected, reason); err != nil {
 		return err
 	}
 
+	s.recordAudit(ctx, audit.Event{
+		Action:       "kyc.reject",
+		ResourceType: "user",
+		ResourceID:   userID,
+		Before:       before,
+		After: map[string]interface{}{
+			"status":           before["status"],
+			"kyc_status":       models.KYCStatusRejected,
+			"rejection_reason": reason,
+		},
+	})
+
 	// Publish user.kyc_updated event
 	if s.eventPublisher != nil {
 		s.eventPublisher.PublishUserEvent("user.kyc_updated", userID, map[string]interface{}{
@@ -760,6 +977,15 @@ func (s *AuthService) RejectKYC(ctx context.Context, userID string, reason strin
 	return nil
 }
 
+// kycAuditSnapshot returns the user and KYC status for audit entries.
+func (s *AuthService) kycAuditSnapshot(ctx context.Context, user *models.User) map[string]interface{} {
+	snapshot := map[string]interface{}{"status": user.Status}
+	if kyc, err := s.kycRepo.GetByUserID(ctx, user.ID); err == nil {
+		snapshot["kyc_status"] = kyc.Status
+	}
+	return snapshot
+}
+
 // UpdateProfile updates a user's profile information.
 func (s *AuthService) UpdateProfile(ctx context.Context, userID string, req *models.UpdateProfileRequest) (*models.User, *errors.Error) {
 	// Get existing user
@@ -1032,14 +1258,14 @@ func (s *AuthService) generateToken(user *models.User, roles []string, permissio
 		Roles:       roles,
 		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(), // Unique per token, so a refresh within the same second gets a new session hash
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "nivo-identity",
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+	tokenString, err := s.signingKeys.Sign(claims)
 	if err != nil {
 		return "", 0, err
 	}
@@ -1047,6 +1273,41 @@ func (s *AuthService) generateToken(user *models.User, roles []string, permissio
 	return tokenString, expiresAt.Unix(), nil
 }
 
+// issueAccessToken generates a JWT carrying the user's current roles and permissions.
+// RBAC failures degrade to a token without roles rather than failing the login.
+func (s *AuthService) issueAccessToken(ctx context.Context, user *models.User) (string, int64, *errors.Error) {
+	var roles []string
+	var permissions []string
+
+	userPerms, rbacErr := s.rbacClient.GetUserPermissions(ctx, user.ID)
+	if rbacErr == nil {
+		// Extract role names
+		for _, role := range userPerms.Roles {
+			roles = append(roles, role.Name)
+		}
+		// Extract permission names
+		for _, perm := range userPerms.Permissions {
+			permissions = append(permissions, perm.Name)
+		}
+	}
+
+	token, expiresAt, err := s.generateToken(user, roles, permissions)
+	if err != nil {
+		return "", 0, errors.Internal("failed to generate token")
+	}
+
+	return token, expiresAt, nil
+}
+
+// generateRefreshToken generates an opaque refresh token and its expiry.
+func (s *AuthService) generateRefreshToken() (string, time.Time, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	return hex.EncodeToString(raw), time.Now().Add(s.refreshExpiry), nil
+}
+
 // hashToken creates a SHA-256 hash of a token for storage.
 func (s *AuthService) hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
@@ -1071,11 +1332,24 @@ func (s *AuthService) SuspendUser(ctx context.Context, userID string, reason str
 		return errors.BadRequest("user is already suspended")
 	}
 
+	previousStatus := user.Status
+
 	// Suspend the user
 	if err := s.userRepo.SuspendUser(ctx, userID, reason, adminUserID); err != nil {
 		return err
 	}
 
+	s.recordAudit(ctx, audit.Event{
+		Action:       "user.suspend",
+		ResourceType: "user",
+		ResourceID:   userID,
+		Before:       map[string]interface{}{"status": previousStatus},
+		After: map[string]interface{}{
+			"status":            models.UserStatusSuspended,
+			"suspension_reason": reason,
+		},
+	})
+
 	// Invalidate all active sessions (security measure)
 	_ = s.sessionRepo.DeleteByUserID(ctx, userID)
 
@@ -1095,7 +1369,19 @@ func (s *AuthService) UnsuspendUser(ctx context.Context, userID string) *errors.
 	}
 
 	// Unsuspend the user
-	return s.userRepo.UnsuspendUser(ctx, userID)
+	if err := s.userRepo.UnsuspendUser(ctx, userID); err != nil {
+		return err
+	}
+
+	s.recordAudit(ctx, audit.Event{
+		Action:       "user.unsuspend",
+		ResourceType: "user",
+		ResourceID:   userID,
+		Before:       map[string]interface{}{"status": models.UserStatusSuspended},
+		After:        map[string]interface{}{"status": models.UserStatusActive},
+	})
+
+	return nil
 }
 
 // GetPairedUserID returns the regular user ID for a given User-Admin account.
@@ -11,7 +11,9 @@ import (
 
 	"github.com/vnykmshr/nivo/services/identity/internal/models"
 	"github.com/vnykmshr/nivo/services/identity/internal/repository"
+	"github.com/vnykmshr/nivo/shared/audit"
 	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	sharedModels "github.com/vnykmshr/nivo/shared/models"
 )
 
@@ -92,7 +94,9 @@ func (m *mockUserRepository) GetByEmailAndAccountType(ctx context.Context, email
 	if !ok {
 		return nil, errors.NotFound("user")
 	}
-	return user, nil
+	// Return a copy, like a database read, so Sanitize doesn't clear the stored hash
+	copied := *user
+	return &copied, nil
 }
 
 func (m *mockUserRepository) GetByPhone(ctx context.Context, phone string) (*models.User, *errors.Error) {
@@ -111,7 +115,8 @@ func (m *mockUserRepository) GetByID(ctx context.Context, id string) (*models.Us
 	if !ok {
 		return nil, errors.NotFound("user")
 	}
-	return user, nil
+	copied := *user
+	return &copied, nil
 }
 
 func (m *mockUserRepository) Update(ctx context.Context, user *models.User) *errors.Error {
@@ -254,6 +259,8 @@ func (m *mockKYCRepository) ListPending(ctx context.Context, limit, offset int)
 type mockSessionRepository struct {
 	sessions           map[string]*models.Session
 	tokenIndex         map[string]*models.Session
+	refreshTokens      map[string]*models.RefreshToken // by token hash
+	devices            map[string]map[string]bool      // userID -> device names
 	createFunc         func(ctx context.Context, session *models.Session) *errors.Error
 	getByTokenHashFunc func(ctx context.Context, tokenHash string) (*models.Session, *errors.Error)
 }
@@ -264,6 +271,7 @@ func (m *mockSessionRepository) Create(ctx context.Context, session *models.Sess
 	}
 	session.ID = uuid.New().String()
 	session.CreatedAt = sharedModels.NewTimestamp(time.Now())
+	session.LastSeenAt = session.CreatedAt
 	m.sessions[session.ID] = session
 	m.tokenIndex[session.Token] = session
 	return nil
@@ -291,6 +299,7 @@ func (m *mockSessionRepository) DeleteByTokenHash(ctx context.Context, tokenHash
 	}
 	delete(m.sessions, session.ID)
 	delete(m.tokenIndex, tokenHash)
+	m.deleteRefreshTokens(session.ID)
 	return nil
 }
 
@@ -300,11 +309,101 @@ func (m *mockSessionRepository) DeleteByUserID(ctx context.Context, userID strin
 		if session.UserID == userID {
 			delete(m.sessions, id)
 			delete(m.tokenIndex, session.Token)
+			m.deleteRefreshTokens(id)
 		}
 	}
 	return nil
 }
 
+func (m *mockSessionRepository) ListByUserID(ctx context.Context, userID string) ([]*models.Session, *errors.Error) {
+	sessions := make([]*models.Session, 0)
+	for _, session := range m.sessions {
+		if session.UserID == userID && time.Now().Before(session.ExpiresAt.Time) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *mockSessionRepository) DeleteByID(ctx context.Context, userID, sessionID string) (string, *errors.Error) {
+	session, ok := m.sessions[sessionID]
+	if !ok || session.UserID != userID {
+		return "", errors.NotFound("session")
+	}
+	delete(m.sessions, sessionID)
+	delete(m.tokenIndex, session.Token)
+	m.deleteRefreshTokens(sessionID)
+	return session.Token, nil
+}
+
+func (m *mockSessionRepository) RecordDevice(ctx context.Context, userID, deviceName string) (bool, *errors.Error) {
+	if m.devices == nil {
+		m.devices = make(map[string]map[string]bool)
+	}
+	known := m.devices[userID]
+	if known == nil {
+		known = make(map[string]bool)
+		m.devices[userID] = known
+	}
+	isNew := !known[deviceName] && len(known) > 0
+	known[deviceName] = true
+	return isNew, nil
+}
+
+// deleteRefreshTokens mirrors the ON DELETE CASCADE from sessions to refresh_tokens.
+func (m *mockSessionRepository) deleteRefreshTokens(sessionID string) {
+	for hash, token := range m.refreshTokens {
+		if token.SessionID == sessionID {
+			delete(m.refreshTokens, hash)
+		}
+	}
+}
+
+func (m *mockSessionRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) *errors.Error {
+	if m.refreshTokens == nil {
+		m.refreshTokens = make(map[string]*models.RefreshToken)
+	}
+	token.ID = uuid.New().String()
+	token.CreatedAt = sharedModels.NewTimestamp(time.Now())
+	m.refreshTokens[token.TokenHash] = token
+	return nil
+}
+
+func (m *mockSessionRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, *errors.Error) {
+	token, ok := m.refreshTokens[tokenHash]
+	if !ok {
+		return nil, errors.Unauthorized("invalid refresh token")
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (m *mockSessionRepository) RotateRefreshToken(ctx context.Context, used, next *models.RefreshToken, accessTokenHash string) (string, *errors.Error) {
+	stored, ok := m.refreshTokens[used.TokenHash]
+	if !ok || stored.UsedAt != nil {
+		return "", errors.Unauthorized("refresh token has already been used")
+	}
+	session, ok := m.sessions[used.SessionID]
+	if !ok {
+		return "", errors.Unauthorized("session has been revoked")
+	}
+
+	now := sharedModels.NewTimestamp(time.Now())
+	stored.UsedAt = &now
+
+	previous := session.Token
+	delete(m.tokenIndex, previous)
+	session.Token = accessTokenHash
+	session.ExpiresAt = next.ExpiresAt
+	session.LastSeenAt = now
+	m.tokenIndex[accessTokenHash] = session
+
+	if err := m.CreateRefreshToken(ctx, next); err != nil {
+		return "", err
+	}
+	return previous, nil
+}
+
 type mockRBACClient struct {
 	assignDefaultRoleFunc  func(ctx context.Context, userID string) error
 	getUserPermissionsFunc func(ctx context.Context, userID string) (*UserPermissionsResponse, error)
@@ -416,10 +515,12 @@ func setupTestAuthService() (*AuthService, *mockUserRepository, *mockKYCReposito
 		userAdminRepo,
 		kycRepo,
 		sessionRepo,
+		newMockTwoFactorRepository(),
+		newMockLoginAttemptRepository(),
 		rbacClient,
 		nil, // wallet client (nil for tests)
 		nil, // notification client (nil for tests)
-		"test-secret-key-for-jwt-signing",
+		testSigningKeys(),
 		24*time.Hour, // 24 hour token expiry
 		nil,          // event publisher (nil for tests)
 	)
@@ -427,6 +528,15 @@ func setupTestAuthService() (*AuthService, *mockUserRepository, *mockKYCReposito
 	return service, userRepo, kycRepo, sessionRepo, rbacClient
 }
 
+// testSigningKeys creates a key set with a fresh Ed25519 signing key.
+func testSigningKeys() *sharedjwt.KeySet {
+	key, err := sharedjwt.GenerateSigningKey("test-key")
+	if err != nil {
+		panic(err)
+	}
+	return sharedjwt.NewKeySet(key)
+}
+
 func hashPassword(password string) string {
 	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(hash)
@@ -1007,6 +1117,178 @@ func TestLogoutAll_Success(t *testing.T) {
 	}
 }
 
+// =====================================================================
+// Refresh Tests
+// =====================================================================
+
+// loginForRefresh creates an active user and logs them in.
+func loginForRefresh(t *testing.T, service *AuthService, userRepo *mockUserRepository) (*models.User, *models.LoginResponse) {
+	t.Helper()
+
+	password := "TestPassword123!"
+	user := &models.User{
+		ID:           uuid.New().String(),
+		Email:        "test@example.com",
+		PasswordHash: hashPassword(password),
+		Status:       models.UserStatusActive,
+		AccountType:  models.AccountTypeUser,
+	}
+	addUserToMockRepo(userRepo, user)
+
+	loginResp, err := service.Login(context.Background(), &models.LoginRequest{
+		Identifier: "test@example.com",
+		Password:   password,
+	}, "192.168.1.1", "Mozilla/5.0")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if loginResp.RefreshToken == "" {
+		t.Fatal("expected login to return a refresh token")
+	}
+
+	return user, loginResp
+}
+
+func TestRefresh_Success(t *testing.T) {
+	service, userRepo, _, sessionRepo, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	user, loginResp := loginForRefresh(t, service, userRepo)
+
+	tokens, err := service.Refresh(ctx, loginResp.RefreshToken)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if tokens.Token == loginResp.Token {
+		t.Error("expected a new access token")
+	}
+	if tokens.RefreshToken == loginResp.RefreshToken {
+		t.Error("expected the refresh token to be rotated")
+	}
+
+	// Still one session; the new access token replaces the old one
+	if len(sessionRepo.sessions) != 1 {
+		t.Errorf("expected 1 session, got %d", len(sessionRepo.sessions))
+	}
+
+	if _, err := service.ValidateToken(ctx, loginResp.Token); err == nil {
+		t.Error("expected the replaced access token to be rejected")
+	}
+
+	validatedUser, err := service.ValidateToken(ctx, tokens.Token)
+	if err != nil {
+		t.Fatalf("expected new access token to be valid, got %v", err)
+	}
+	if validatedUser.ID != user.ID {
+		t.Errorf("expected user ID %s, got %s", user.ID, validatedUser.ID)
+	}
+
+	// The new refresh token can be used in turn
+	if _, err := service.Refresh(ctx, tokens.RefreshToken); err != nil {
+		t.Errorf("expected rotated refresh token to work, got %v", err)
+	}
+}
+
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	service, userRepo, _, sessionRepo, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	_, loginResp := loginForRefresh(t, service, userRepo)
+
+	tokens, err := service.Refresh(ctx, loginResp.RefreshToken)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Replaying the first refresh token revokes the whole session
+	_, err = service.Refresh(ctx, loginResp.RefreshToken)
+	if err == nil {
+		t.Fatal("expected error for reused refresh token, got nil")
+	}
+	if err.Code != errors.ErrCodeUnauthorized {
+		t.Errorf("expected unauthorized error, got %s", err.Code)
+	}
+
+	if len(sessionRepo.sessions) != 0 {
+		t.Errorf("expected session to be revoked, got %d sessions", len(sessionRepo.sessions))
+	}
+
+	// Tokens issued by the legitimate rotation are revoked too
+	if _, err := service.Refresh(ctx, tokens.RefreshToken); err == nil {
+		t.Error("expected the latest refresh token to be revoked")
+	}
+	if _, err := service.ValidateToken(ctx, tokens.Token); err == nil {
+		t.Error("expected the latest access token to be revoked")
+	}
+}
+
+func TestRefresh_Error_Expired(t *testing.T) {
+	service, userRepo, _, sessionRepo, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	_, loginResp := loginForRefresh(t, service, userRepo)
+
+	stored := sessionRepo.refreshTokens[service.hashToken(loginResp.RefreshToken)]
+	stored.ExpiresAt = sharedModels.NewTimestamp(time.Now().Add(-1 * time.Hour))
+
+	_, err := service.Refresh(ctx, loginResp.RefreshToken)
+	if err == nil {
+		t.Fatal("expected error for expired refresh token, got nil")
+	}
+	if err.Code != errors.ErrCodeUnauthorized {
+		t.Errorf("expected unauthorized error, got %s", err.Code)
+	}
+}
+
+func TestRefresh_Error_InvalidToken(t *testing.T) {
+	service, _, _, _, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	_, err := service.Refresh(ctx, "not-a-refresh-token")
+	if err == nil {
+		t.Fatal("expected error for invalid refresh token, got nil")
+	}
+	if err.Code != errors.ErrCodeUnauthorized {
+		t.Errorf("expected unauthorized error, got %s", err.Code)
+	}
+}
+
+func TestRefresh_Error_AfterLogout(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	_, loginResp := loginForRefresh(t, service, userRepo)
+
+	if err := service.Logout(ctx, loginResp.Token); err != nil {
+		t.Fatalf("logout failed: %v", err)
+	}
+
+	if _, err := service.Refresh(ctx, loginResp.RefreshToken); err == nil {
+		t.Fatal("expected refresh to fail after logout, got nil")
+	}
+}
+
+func TestRefresh_Error_SuspendedUser(t *testing.T) {
+	service, userRepo, _, sessionRepo, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	user, loginResp := loginForRefresh(t, service, userRepo)
+	userRepo.users[user.ID].Status = models.UserStatusSuspended
+
+	_, err := service.Refresh(ctx, loginResp.RefreshToken)
+	if err == nil {
+		t.Fatal("expected error for suspended user, got nil")
+	}
+	if err.Code != errors.ErrCodeForbidden {
+		t.Errorf("expected forbidden error, got %s", err.Code)
+	}
+
+	if len(sessionRepo.sessions) != 0 {
+		t.Errorf("expected session to be revoked, got %d sessions", len(sessionRepo.sessions))
+	}
+}
+
 // =====================================================================
 // ValidateToken Tests - CRITICAL PATH (100% coverage needed)
 // =====================================================================
@@ -1188,6 +1470,50 @@ func TestSuspendUser_Success(t *testing.T) {
 	}
 }
 
+// recordingAuditLog captures audit events for assertions.
+type recordingAuditLog struct {
+	events []audit.Event
+}
+
+func (r *recordingAuditLog) Record(ctx context.Context, event audit.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestSuspendUser_RecordsAudit(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	auditLog := &recordingAuditLog{}
+	service.SetAuditLog(auditLog)
+
+	user := &models.User{
+		ID:          uuid.New().String(),
+		Email:       "test@example.com",
+		FullName:    "Test User",
+		Status:      models.UserStatusActive,
+		AccountType: models.AccountTypeUser,
+	}
+	addUserToMockRepo(userRepo, user)
+
+	if err := service.SuspendUser(context.Background(), user.ID, "Chargeback fraud", "admin-id"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := service.SuspendUser(context.Background(), user.ID, "Again", "admin-id"); err == nil {
+		t.Fatal("expected error for already suspended user")
+	}
+
+	if len(auditLog.events) != 1 {
+		t.Fatalf("expected only the successful suspension to be recorded, got %d events", len(auditLog.events))
+	}
+	event := auditLog.events[0]
+	if event.Action != "user.suspend" || event.ResourceType != "user" || event.ResourceID != user.ID {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	before := event.Before.(map[string]interface{})
+	after := event.After.(map[string]interface{})
+	if before["status"] != models.UserStatusActive || after["status"] != models.UserStatusSuspended {
+		t.Errorf("expected active -> suspended snapshots, got %v -> %v", before, after)
+	}
+}
+
 func TestSuspendUser_AlreadySuspended(t *testing.T) {
 	service, userRepo, _, _, _ := setupTestAuthService()
 	ctx := context.Background()
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// Session and device management for AuthService.
+//
+// Every login creates a session, which lives until logout, revocation or the
+// refresh token expiring. Devices are remembered per user by a name derived from
+// the user agent, so a login from a device the user has not used before can be
+// flagged with a security alert.
+
+// ListSessions returns a user's active sessions. currentToken is the access token
+// of the request, used to mark the session it belongs to.
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentToken string) ([]*models.SessionInfo, *errors.Error) {
+	sessions, err := s.sessionRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentHash := s.hashToken(currentToken)
+
+	infos := make([]*models.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, &models.SessionInfo{
+			ID:          session.ID,
+			Device:      describeDevice(session.UserAgent),
+			IPAddress:   session.IPAddress,
+			UserAgent:   session.UserAgent,
+			FirstSeenAt: session.CreatedAt,
+			LastSeenAt:  session.LastSeenAt,
+			ExpiresAt:   session.ExpiresAt,
+			Current:     session.Token == currentHash,
+		})
+	}
+
+	return infos, nil
+}
+
+// RevokeSession signs a user out of one of their sessions. Its access token stops
+// working immediately and its refresh token can no longer be used.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) *errors.Error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return errors.BadRequest("invalid session ID")
+	}
+
+	accessHash, err := s.sessionRepo.DeleteByID(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	s.evictToken(ctx, accessHash)
+
+	if s.eventPublisher != nil {
+		s.eventPublisher.PublishUserEvent("user.session_revoked", userID, map[string]interface{}{
+			"session_id": sessionID,
+			"reason":     "user_revoked",
+		})
+	}
+
+	return nil
+}
+
+// recordLoginDevice remembers the device a session was created from and alerts
+// the user when it is one they have not signed in from before. Failures are
+// ignored so they never block a login.
+func (s *AuthService) recordLoginDevice(ctx context.Context, user *models.User, session *models.Session) {
+	device := describeDevice(session.UserAgent)
+
+	isNew, err := s.sessionRepo.RecordDevice(ctx, user.ID, device)
+	if err != nil || !isNew {
+		return
+	}
+
+	if s.eventPublisher != nil {
+		s.eventPublisher.PublishUserEvent("user.new_device_login", user.ID, map[string]interface{}{
+			"session_id": session.ID,
+			"device":     device,
+			"ip_address": session.IPAddress,
+		})
+	}
+
+	if s.notificationClient != nil && user.Email != "" {
+		correlationID := fmt.Sprintf("new-device-%s", session.ID)
+		emailReq := &clients.SendNotificationRequest{
+			UserID:     &user.ID,
+			Recipient:  user.Email,
+			Channel:    clients.NotificationChannelEmail,
+			Type:       clients.NotificationTypeSecurityAlert,
+			Priority:   clients.NotificationPriorityHigh,
+			TemplateID: "account_alert_email",
+			Variables: map[string]interface{}{
+				"user_name":  user.FullName,
+				"alert_type": "New device sign-in",
+				"message":    fmt.Sprintf("Your account was signed in from %s (IP address %s). You can review and sign out of devices under your active sessions", device, session.IPAddress),
+				"date":       time.Now().Format("02 Jan 2006, 03:04 PM"),
+			},
+			CorrelationID: &correlationID,
+			SourceService: "identity",
+		}
+		s.notificationClient.SendNotificationAsync(emailReq, "identity")
+	}
+}
+
+// describeDevice turns a user agent into a short name such as "Chrome on Windows".
+// It is deliberately coarse: browser versions change often and must not make a
+// known device look new.
+func describeDevice(userAgent string) string {
+	browser := matchFirst(userAgent, []deviceToken{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"SamsungBrowser", "Samsung Internet"},
+		{"FxiOS", "Firefox"},
+		{"Firefox/", "Firefox"},
+		{"CriOS", "Chrome"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	})
+
+	platform := matchFirst(userAgent, []deviceToken{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"CrOS", "ChromeOS"},
+		{"Macintosh", "macOS"},
+		{"Linux", "Linux"},
+	})
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case platform != "":
+		return platform
+	case browser != "":
+		return browser
+	default:
+		return "Unknown device"
+	}
+}
+
+// deviceToken maps a user agent substring to a display name.
+type deviceToken struct {
+	substring string
+	name      string
+}
+
+// matchFirst returns the name of the first token found in userAgent.
+func matchFirst(userAgent string, tokens []deviceToken) string {
+	for _, token := range tokens {
+		if strings.Contains(userAgent, token.substring) {
+			return token.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+const (
+	chromeWindowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	safariIPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1"
+)
+
+// memoryCache is an in-memory cache.Cache. TTLs are ignored.
+type memoryCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{data: make(map[string]string)}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.data[key]
+	return value, ok, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	return nil
+}
+
+func (c *memoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, key)
+	return nil
+}
+
+func (c *memoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	_, ok, _ := c.Get(ctx, key)
+	return ok, nil
+}
+
+func (c *memoryCache) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, _ := strconv.ParseInt(c.data[key], 10, 64)
+	n++
+	c.data[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+func (c *memoryCache) Ping(ctx context.Context) error { return nil }
+
+func (c *memoryCache) Close() error { return nil }
+
+// loginFrom logs a user in with the given user agent.
+func loginFrom(t *testing.T, service *AuthService, email, password, userAgent string) *models.LoginResponse {
+	t.Helper()
+
+	resp, err := service.Login(context.Background(), &models.LoginRequest{
+		Identifier: email,
+		Password:   password,
+	}, "192.168.1.1", userAgent)
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	return resp
+}
+
+func createSessionTestUser(userRepo *mockUserRepository) (*models.User, string) {
+	password := "TestPassword123!"
+	user := &models.User{
+		ID:           uuid.New().String(),
+		Email:        "sessions@example.com",
+		PasswordHash: hashPassword(password),
+		Status:       models.UserStatusActive,
+		AccountType:  models.AccountTypeUser,
+	}
+	addUserToMockRepo(userRepo, user)
+	return user, password
+}
+
+func TestListSessions_MarksCurrentSession(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	user, password := createSessionTestUser(userRepo)
+	laptop := loginFrom(t, service, user.Email, password, chromeWindowsUA)
+	loginFrom(t, service, user.Email, password, safariIPhoneUA)
+
+	sessions, err := service.ListSessions(ctx, user.ID, laptop.Token)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+
+	devices := make(map[string]bool)
+	for _, session := range sessions {
+		devices[session.Device] = session.Current
+	}
+
+	if current, ok := devices["Chrome on Windows"]; !ok || !current {
+		t.Errorf("expected the Chrome on Windows session to be current, got %v", devices)
+	}
+	if current, ok := devices["Safari on iPhone"]; !ok || current {
+		t.Errorf("expected the Safari on iPhone session not to be current, got %v", devices)
+	}
+}
+
+func TestRevokeSession_Success(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	sessionCache := newMemoryCache()
+	service.SetCache(sessionCache)
+
+	user, password := createSessionTestUser(userRepo)
+	laptop := loginFrom(t, service, user.Email, password, chromeWindowsUA)
+	phone := loginFrom(t, service, user.Email, password, safariIPhoneUA)
+
+	// Validate once so the phone's token is cached
+	if _, err := service.ValidateToken(ctx, phone.Token); err != nil {
+		t.Fatalf("expected phone token to be valid, got %v", err)
+	}
+
+	sessions, _ := service.ListSessions(ctx, user.ID, laptop.Token)
+	var phoneSessionID string
+	for _, session := range sessions {
+		if !session.Current {
+			phoneSessionID = session.ID
+		}
+	}
+
+	if err := service.RevokeSession(ctx, user.ID, phoneSessionID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := service.ValidateToken(ctx, phone.Token); err == nil {
+		t.Error("expected revoked access token to be rejected despite the cache")
+	}
+	if _, err := service.Refresh(ctx, phone.RefreshToken); err == nil {
+		t.Error("expected revoked refresh token to be rejected")
+	}
+	if _, err := service.ValidateToken(ctx, laptop.Token); err != nil {
+		t.Errorf("expected other session to stay valid, got %v", err)
+	}
+}
+
+func TestRevokeSession_OtherUsersSession(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	user, password := createSessionTestUser(userRepo)
+	resp := loginFrom(t, service, user.Email, password, chromeWindowsUA)
+
+	sessions, _ := service.ListSessions(ctx, user.ID, resp.Token)
+
+	err := service.RevokeSession(ctx, uuid.New().String(), sessions[0].ID)
+	if err == nil {
+		t.Fatal("expected error revoking another user's session, got nil")
+	}
+	if err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found error, got %s", err.Code)
+	}
+}
+
+func TestRevokeSession_InvalidID(t *testing.T) {
+	service, _, _, _, _ := setupTestAuthService()
+
+	err := service.RevokeSession(context.Background(), uuid.New().String(), "not-a-uuid")
+	if err == nil {
+		t.Fatal("expected error for invalid session ID, got nil")
+	}
+	if err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected bad request error, got %s", err.Code)
+	}
+}
+
+func TestLogoutAll_EvictsCachedTokens(t *testing.T) {
+	service, userRepo, _, _, _ := setupTestAuthService()
+	ctx := context.Background()
+
+	service.SetCache(newMemoryCache())
+
+	user, password := createSessionTestUser(userRepo)
+	resp := loginFrom(t, service, user.Email, password, chromeWindowsUA)
+
+	if _, err := service.ValidateToken(ctx, resp.Token); err != nil {
+		t.Fatalf("expected token to be valid, got %v", err)
+	}
+
+	if err := service.LogoutAll(ctx, user.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := service.ValidateToken(ctx, resp.Token); err == nil {
+		t.Error("expected token to be rejected after logout-all")
+	}
+}
+
+func TestLogin_RecordsDevices(t *testing.T) {
+	service, userRepo, _, sessionRepo, _ := setupTestAuthService()
+
+	user, password := createSessionTestUser(userRepo)
+	loginFrom(t, service, user.Email, password, chromeWindowsUA)
+	loginFrom(t, service, user.Email, password, chromeWindowsUA)
+	loginFrom(t, service, user.Email, password, safariIPhoneUA)
+
+	known := sessionRepo.devices[user.ID]
+	if len(known) != 2 {
+		t.Fatalf("expected 2 known devices, got %d", len(known))
+	}
+	if !known["Chrome on Windows"] || !known["Safari on iPhone"] {
+		t.Errorf("unexpected devices recorded: %v", known)
+	}
+}
+
+func TestDescribeDevice(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{chromeWindowsUA, "Chrome on Windows"},
+		{safariIPhoneUA, "Safari on iPhone"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", "Safari on macOS"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", "Edge on Windows"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox on Linux"},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "Chrome on Android"},
+		{"okhttp/4.12.0", "Unknown device"},
+		{"", "Unknown device"},
+	}
+
+	for _, tt := range tests {
+		if got := describeDevice(tt.userAgent); got != tt.want {
+			t.Errorf("describeDevice(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}
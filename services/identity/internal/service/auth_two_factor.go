@@ -0,0 +1,485 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/crypto"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// Two-factor authentication (RFC 6238 TOTP) for AuthService.
+//
+// Login is two-step for accounts with 2FA: a correct password returns a
+// short-lived challenge token, which CompleteTwoFactorLogin exchanges for the
+// JWT once a TOTP or recovery code is presented. Admin and super_admin accounts
+// without 2FA get an enrolment challenge instead and must enrol before a JWT is issued.
+// Wrong codes count toward the account's login lockout just like wrong passwords.
+// TOTP secrets are stored encrypted with the cipher set by SetTOTPCipher.
+
+// SetTOTPCipher sets the cipher TOTP secrets are encrypted with at rest. Each
+// secret is bound to its user ID. Two-factor authentication fails without it.
+func (s *AuthService) SetTOTPCipher(c *crypto.SecretCipher) {
+	s.totpCipher = c
+}
+
+// twoFactorChallenge returns a challenge response if the user needs a second
+// factor to log in, or nil if the password alone is enough.
+func (s *AuthService) twoFactorChallenge(ctx context.Context, user *models.User) (*models.LoginResponse, *errors.Error) {
+	tf, err := s.twoFactorRepo.GetByUserID(ctx, user.ID)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	var purpose models.LoginChallengePurpose
+	switch {
+	case tf.IsEnabled():
+		purpose = models.ChallengePurposeVerify
+	case user.RequiresTwoFactor():
+		purpose = models.ChallengePurposeEnroll
+	default:
+		return nil, nil
+	}
+
+	token, expiresAt, err := s.createLoginChallenge(ctx, user.ID, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		AccountType:            user.AccountType,
+		TwoFactorRequired:      purpose == models.ChallengePurposeVerify,
+		TwoFactorSetupRequired: purpose == models.ChallengePurposeEnroll,
+		ChallengeToken:         token,
+		ChallengeExpiresAt:     expiresAt,
+	}, nil
+}
+
+// CompleteTwoFactorLogin exchanges a login challenge and a TOTP or recovery code for a JWT.
+func (s *AuthService) CompleteTwoFactorLogin(ctx context.Context, req *models.TwoFactorLoginRequest, ipAddress, userAgent string) (*models.LoginResponse, *errors.Error) {
+	if req.Code == "" && req.RecoveryCode == "" {
+		return nil, errors.Validation("code or recovery_code is required")
+	}
+
+	challenge, user, err := s.loadLoginChallenge(ctx, req.ChallengeToken, models.ChallengePurposeVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	tf, err := s.twoFactorRepo.GetByUserID(ctx, user.ID)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if !tf.IsEnabled() {
+		// 2FA was disabled after the challenge was issued
+		return nil, errors.Unauthorized("invalid or expired login challenge")
+	}
+
+	if err := s.checkAccountLoginAllowed(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifySecondFactor(ctx, tf, req.Code, req.RecoveryCode); err != nil {
+		return nil, s.failLoginChallenge(ctx, challenge, user, err, ipAddress, userAgent)
+	}
+
+	if err := s.twoFactorRepo.ConsumeChallenge(ctx, challenge.ID); err != nil {
+		return nil, err
+	}
+
+	s.resetLoginFailures(ctx, user)
+	return s.completeLogin(ctx, user, ipAddress, userAgent)
+}
+
+// BeginLoginTwoFactorSetup starts enrolment for an account that must enrol before logging in.
+func (s *AuthService) BeginLoginTwoFactorSetup(ctx context.Context, req *models.LoginTwoFactorSetupRequest) (*models.TwoFactorSetupResponse, *errors.Error) {
+	_, user, err := s.loadLoginChallenge(ctx, req.ChallengeToken, models.ChallengePurposeEnroll)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.beginEnrolment(ctx, user)
+}
+
+// ConfirmLoginTwoFactorSetup confirms enrolment started during login and completes the login.
+// The response carries the recovery codes, which are not shown again.
+func (s *AuthService) ConfirmLoginTwoFactorSetup(ctx context.Context, req *models.LoginTwoFactorConfirmRequest, ipAddress, userAgent string) (*models.LoginResponse, *errors.Error) {
+	challenge, user, err := s.loadLoginChallenge(ctx, req.ChallengeToken, models.ChallengePurposeEnroll)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAccountLoginAllowed(ctx, user); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.confirmEnrolment(ctx, user.ID, req.Code)
+	if err != nil {
+		return nil, s.failLoginChallenge(ctx, challenge, user, err, ipAddress, userAgent)
+	}
+
+	if err := s.twoFactorRepo.ConsumeChallenge(ctx, challenge.ID); err != nil {
+		return nil, err
+	}
+
+	s.resetLoginFailures(ctx, user)
+
+	response, err := s.completeLogin(ctx, user, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	response.RecoveryCodes = codes
+
+	return response, nil
+}
+
+// GetTwoFactorStatus returns whether 2FA is enabled for a user.
+func (s *AuthService) GetTwoFactorStatus(ctx context.Context, userID string) (*models.TwoFactorStatus, *errors.Error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tf, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	status := &models.TwoFactorStatus{
+		Required: user.RequiresTwoFactor(),
+	}
+
+	if tf.IsEnabled() {
+		remaining, err := s.twoFactorRepo.CountUnusedRecoveryCodes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		status.Enabled = true
+		status.EnabledAt = tf.EnabledAt
+		status.RecoveryCodesRemaining = remaining
+	}
+
+	return status, nil
+}
+
+// BeginTwoFactorSetup generates a TOTP secret for a logged-in user. 2FA is not
+// enabled until ConfirmTwoFactorSetup is called with a code from the authenticator app.
+func (s *AuthService) BeginTwoFactorSetup(ctx context.Context, userID string) (*models.TwoFactorSetupResponse, *errors.Error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.beginEnrolment(ctx, user)
+}
+
+// ConfirmTwoFactorSetup enables 2FA for a logged-in user and returns their recovery codes.
+func (s *AuthService) ConfirmTwoFactorSetup(ctx context.Context, userID string, req *models.ConfirmTwoFactorRequest) (*models.RecoveryCodesResponse, *errors.Error) {
+	codes, err := s.confirmEnrolment(ctx, userID, req.Code)
+	if err != nil {
+		return nil, codeRejected(err)
+	}
+
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor turns 2FA off. Regular users also need a verification token
+// for the 2fa_disable operation; admin accounts cannot turn it off.
+func (s *AuthService) DisableTwoFactor(ctx context.Context, userID string, req *models.DisableTwoFactorRequest) *errors.Error {
+	if req.Code == "" && req.RecoveryCode == "" {
+		return errors.Validation("code or recovery_code is required")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.RequiresTwoFactor() {
+		return errors.Forbidden("two-factor authentication is mandatory for admin accounts")
+	}
+
+	tf, err := s.requireTwoFactorEnabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.AccountType == models.AccountTypeUser {
+		if req.VerificationToken == "" {
+			return errors.VerificationRequired("verification required to disable two-factor authentication").
+				WithDetails(map[string]interface{}{"operation_type": models.Op2FADisable})
+		}
+
+		claims, err := s.validateVerificationToken(req.VerificationToken, models.Op2FADisable)
+		if err != nil {
+			return err
+		}
+		if claims.UserID != userID {
+			return errors.Forbidden("verification token belongs to a different user")
+		}
+	}
+
+	if err := s.verifySecondFactor(ctx, tf, req.Code, req.RecoveryCode); err != nil {
+		return codeRejected(err)
+	}
+
+	if err := s.twoFactorRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	if s.eventPublisher != nil {
+		s.eventPublisher.PublishUserEvent("user.2fa_disabled", userID, map[string]interface{}{
+			"disabled_at": time.Now().Unix(),
+		})
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces a user's recovery codes after checking a TOTP code.
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID string, req *models.ConfirmTwoFactorRequest) (*models.RecoveryCodesResponse, *errors.Error) {
+	tf, err := s.requireTwoFactorEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyTOTP(ctx, tf, req.Code); err != nil {
+		return nil, codeRejected(err)
+	}
+
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// beginEnrolment stores a new pending secret and returns it with its otpauth URI.
+func (s *AuthService) beginEnrolment(ctx context.Context, user *models.User) (*models.TwoFactorSetupResponse, *errors.Error) {
+	secret, genErr := crypto.GenerateTOTPSecret()
+	if genErr != nil {
+		return nil, errors.Internal("failed to generate two-factor secret")
+	}
+
+	if s.totpCipher == nil {
+		return nil, errors.Internal("two-factor secret encryption is not configured")
+	}
+	encrypted, encErr := s.totpCipher.Encrypt(secret, user.ID)
+	if encErr != nil {
+		return nil, errors.InternalWrap(encErr, "failed to encrypt two-factor secret")
+	}
+
+	if err := s.twoFactorRepo.SavePending(ctx, user.ID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: crypto.TOTPAuthURI(models.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// confirmEnrolment checks a code against the pending secret, enables 2FA and
+// returns fresh recovery codes. A wrong code is reported as Unauthorized.
+func (s *AuthService) confirmEnrolment(ctx context.Context, userID, code string) ([]string, *errors.Error) {
+	tf, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.BadRequest("two-factor setup has not been started")
+		}
+		return nil, err
+	}
+	if tf.IsEnabled() {
+		return nil, errors.Conflict("two-factor authentication is already enabled")
+	}
+
+	secret, err := s.totpSecret(tf)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := crypto.ValidateTOTP(secret, code, time.Now(), tf.LastUsedStep)
+	if !ok {
+		return nil, errors.Unauthorized("invalid two-factor code")
+	}
+
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	if s.eventPublisher != nil {
+		s.eventPublisher.PublishUserEvent("user.2fa_enabled", userID, map[string]interface{}{
+			"enabled_at": time.Now().Unix(),
+		})
+	}
+
+	return codes, nil
+}
+
+// requireTwoFactorEnabled returns the user's enrolment, or BadRequest if 2FA is off.
+func (s *AuthService) requireTwoFactorEnabled(ctx context.Context, userID string) (*models.TwoFactor, *errors.Error) {
+	tf, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if !tf.IsEnabled() {
+		return nil, errors.BadRequest("two-factor authentication is not enabled")
+	}
+	return tf, nil
+}
+
+// verifySecondFactor accepts either a TOTP code or an unused recovery code.
+func (s *AuthService) verifySecondFactor(ctx context.Context, tf *models.TwoFactor, code, recoveryCode string) *errors.Error {
+	if recoveryCode != "" {
+		return s.twoFactorRepo.UseRecoveryCode(ctx, tf.UserID, hashRecoveryCode(recoveryCode))
+	}
+	return s.verifyTOTP(ctx, tf, code)
+}
+
+// verifyTOTP checks a TOTP code and records its time step so it cannot be reused.
+func (s *AuthService) verifyTOTP(ctx context.Context, tf *models.TwoFactor, code string) *errors.Error {
+	secret, err := s.totpSecret(tf)
+	if err != nil {
+		return err
+	}
+
+	step, ok := crypto.ValidateTOTP(secret, code, time.Now(), tf.LastUsedStep)
+	if !ok {
+		return errors.Unauthorized("invalid two-factor code")
+	}
+	return s.twoFactorRepo.UseStep(ctx, tf.UserID, step)
+}
+
+// totpSecret decrypts the stored TOTP secret of an enrolment.
+func (s *AuthService) totpSecret(tf *models.TwoFactor) (string, *errors.Error) {
+	if s.totpCipher == nil {
+		return "", errors.Internal("two-factor secret encryption is not configured")
+	}
+	secret, err := s.totpCipher.Decrypt(tf.Secret, tf.UserID)
+	if err != nil {
+		return "", errors.InternalWrap(err, "failed to decrypt two-factor secret")
+	}
+	return secret, nil
+}
+
+// createLoginChallenge stores a challenge and returns its token, which is only kept hashed.
+func (s *AuthService) createLoginChallenge(ctx context.Context, userID string, purpose models.LoginChallengePurpose) (string, int64, *errors.Error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", 0, errors.Internal("failed to generate login challenge")
+	}
+	token := hex.EncodeToString(raw)
+	expiresAt := time.Now().Add(models.LoginChallengeTTL)
+
+	challenge := &models.LoginChallenge{
+		UserID:    userID,
+		TokenHash: s.hashToken(token),
+		Purpose:   purpose,
+		ExpiresAt: sharedModels.NewTimestamp(expiresAt),
+	}
+	if err := s.twoFactorRepo.CreateChallenge(ctx, challenge); err != nil {
+		return "", 0, err
+	}
+
+	return token, expiresAt.Unix(), nil
+}
+
+// loadLoginChallenge resolves a challenge token and re-checks the account is still usable.
+func (s *AuthService) loadLoginChallenge(ctx context.Context, token string, purpose models.LoginChallengePurpose) (*models.LoginChallenge, *models.User, *errors.Error) {
+	challenge, err := s.twoFactorRepo.GetChallengeByTokenHash(ctx, s.hashToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge.Purpose != purpose || challenge.Attempts >= models.MaxLoginChallengeAttempts {
+		return nil, nil, errors.Unauthorized("invalid or expired login challenge")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, nil, errors.Unauthorized("invalid or expired login challenge")
+	}
+	if user.Status == models.UserStatusClosed || user.Status == models.UserStatusSuspended {
+		return nil, nil, errors.Forbidden("account is not active")
+	}
+
+	return challenge, user, nil
+}
+
+// failLoginChallenge counts a wrong code against the challenge, invalidating it
+// after MaxLoginChallengeAttempts, and against the account's failed logins, so
+// codes cannot be brute-forced with fresh challenges either.
+func (s *AuthService) failLoginChallenge(ctx context.Context, challenge *models.LoginChallenge, user *models.User, cause *errors.Error, ipAddress, userAgent string) *errors.Error {
+	if !errors.IsUnauthorized(cause) {
+		return cause
+	}
+
+	s.recordLoginFailure(ctx, loginAttemptKeysForUser(user)[0], user, ipAddress, userAgent)
+
+	attempts, err := s.twoFactorRepo.RecordChallengeFailure(ctx, challenge.ID)
+	if err != nil {
+		return err
+	}
+
+	remaining := models.MaxLoginChallengeAttempts - attempts
+	if remaining <= 0 {
+		_ = s.twoFactorRepo.ConsumeChallenge(ctx, challenge.ID)
+		return errors.Unauthorized("too many invalid codes, please log in again")
+	}
+
+	return cause.WithDetails(map[string]interface{}{"attempts_remaining": remaining})
+}
+
+// generateRecoveryCodes returns RecoveryCodeCount codes formatted XXXX-XXXX-XXXX-XXXX
+// along with the hashes to store.
+func (s *AuthService) generateRecoveryCodes() ([]string, []string, *errors.Error) {
+	codes := make([]string, 0, models.RecoveryCodeCount)
+	hashes := make([]string, 0, models.RecoveryCodeCount)
+
+	for i := 0; i < models.RecoveryCodeCount; i++ {
+		raw := make([]byte, 10) // 80 bits -> 16 base32 characters
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, errors.Internal("failed to generate recovery codes")
+		}
+		encoded := base32.StdEncoding.EncodeToString(raw)
+		code := encoded[0:4] + "-" + encoded[4:8] + "-" + encoded[8:12] + "-" + encoded[12:16]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a recovery code, ignoring case, spaces and dashes.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(code)
+	normalized = strings.NewReplacer("-", "", " ", "").Replace(normalized)
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+// codeRejected reports a wrong code to a logged-in user as 400 rather than 401,
+// so clients don't mistake it for an expired session.
+func codeRejected(err *errors.Error) *errors.Error {
+	if errors.IsUnauthorized(err) {
+		return errors.BadRequest(err.Message)
+	}
+	return err
+}
@@ -0,0 +1,592 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/crypto"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// =====================================================================
+// Mock Two-Factor Repository
+// =====================================================================
+
+type mockTwoFactorRepository struct {
+	enrolments    map[string]*models.TwoFactor
+	recoveryCodes map[string]map[string]bool // userID -> code hash -> used
+	challenges    map[string]*models.LoginChallenge
+}
+
+func newMockTwoFactorRepository() *mockTwoFactorRepository {
+	return &mockTwoFactorRepository{
+		enrolments:    make(map[string]*models.TwoFactor),
+		recoveryCodes: make(map[string]map[string]bool),
+		challenges:    make(map[string]*models.LoginChallenge),
+	}
+}
+
+func (m *mockTwoFactorRepository) GetByUserID(ctx context.Context, userID string) (*models.TwoFactor, *errors.Error) {
+	tf, ok := m.enrolments[userID]
+	if !ok {
+		return nil, errors.NotFound("two-factor enrolment")
+	}
+	copied := *tf
+	return &copied, nil
+}
+
+func (m *mockTwoFactorRepository) SavePending(ctx context.Context, userID, secret string) *errors.Error {
+	if tf, ok := m.enrolments[userID]; ok && tf.IsEnabled() {
+		return errors.Conflict("two-factor authentication is already enabled")
+	}
+	m.enrolments[userID] = &models.TwoFactor{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (m *mockTwoFactorRepository) Enable(ctx context.Context, userID string, step int64, codeHashes []string) *errors.Error {
+	tf, ok := m.enrolments[userID]
+	if !ok || tf.IsEnabled() {
+		return errors.Conflict("two-factor authentication is already enabled")
+	}
+	now := sharedModels.NewTimestamp(time.Now())
+	tf.EnabledAt = &now
+	tf.LastUsedStep = step
+	return m.ReplaceRecoveryCodes(ctx, userID, codeHashes)
+}
+
+func (m *mockTwoFactorRepository) UseStep(ctx context.Context, userID string, step int64) *errors.Error {
+	tf := m.enrolments[userID]
+	if step <= tf.LastUsedStep {
+		return errors.Unauthorized("two-factor code has already been used")
+	}
+	tf.LastUsedStep = step
+	return nil
+}
+
+func (m *mockTwoFactorRepository) Delete(ctx context.Context, userID string) *errors.Error {
+	delete(m.enrolments, userID)
+	delete(m.recoveryCodes, userID)
+	return nil
+}
+
+func (m *mockTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) *errors.Error {
+	m.recoveryCodes[userID] = make(map[string]bool)
+	for _, hash := range codeHashes {
+		m.recoveryCodes[userID][hash] = false
+	}
+	return nil
+}
+
+func (m *mockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) *errors.Error {
+	used, ok := m.recoveryCodes[userID][codeHash]
+	if !ok || used {
+		return errors.Unauthorized("invalid recovery code")
+	}
+	m.recoveryCodes[userID][codeHash] = true
+	return nil
+}
+
+func (m *mockTwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID string) (int, *errors.Error) {
+	count := 0
+	for _, used := range m.recoveryCodes[userID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockTwoFactorRepository) CreateChallenge(ctx context.Context, challenge *models.LoginChallenge) *errors.Error {
+	challenge.ID = uuid.New().String()
+	m.challenges[challenge.TokenHash] = challenge
+	return nil
+}
+
+func (m *mockTwoFactorRepository) GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*models.LoginChallenge, *errors.Error) {
+	challenge, ok := m.challenges[tokenHash]
+	if !ok || challenge.ConsumedAt != nil || time.Now().After(challenge.ExpiresAt.Time) {
+		return nil, errors.Unauthorized("invalid or expired login challenge")
+	}
+	copied := *challenge
+	return &copied, nil
+}
+
+func (m *mockTwoFactorRepository) RecordChallengeFailure(ctx context.Context, id string) (int, *errors.Error) {
+	for _, challenge := range m.challenges {
+		if challenge.ID == id {
+			challenge.Attempts++
+			return challenge.Attempts, nil
+		}
+	}
+	return 0, errors.NotFound("login challenge")
+}
+
+func (m *mockTwoFactorRepository) ConsumeChallenge(ctx context.Context, id string) *errors.Error {
+	for _, challenge := range m.challenges {
+		if challenge.ID == id && challenge.ConsumedAt == nil {
+			now := sharedModels.NewTimestamp(time.Now())
+			challenge.ConsumedAt = &now
+			return nil
+		}
+	}
+	return errors.Unauthorized("invalid or expired login challenge")
+}
+
+// =====================================================================
+// Test Helpers
+// =====================================================================
+
+const twoFactorTestPassword = "TestPassword123!"
+
+func setupTwoFactorTest(t *testing.T, accountType models.AccountType) (*AuthService, *mockTwoFactorRepository, *mockSessionRepository, *models.User) {
+	t.Helper()
+
+	service, userRepo, _, sessionRepo, _ := setupTestAuthService()
+	twoFactorRepo := service.twoFactorRepo.(*mockTwoFactorRepository)
+
+	totpCipher, err := crypto.NewSecretCipher(bytes.Repeat([]byte{7}, crypto.SecretKeySize))
+	if err != nil {
+		t.Fatalf("NewSecretCipher: %v", err)
+	}
+	service.SetTOTPCipher(totpCipher)
+
+	user := &models.User{
+		ID:           uuid.New().String(),
+		Email:        "2fa@example.com",
+		Phone:        "+919876543210",
+		FullName:     "Two Factor User",
+		PasswordHash: hashPassword(twoFactorTestPassword),
+		Status:       models.UserStatusActive,
+		AccountType:  accountType,
+	}
+	addUserToMockRepo(userRepo, user)
+
+	return service, twoFactorRepo, sessionRepo, user
+}
+
+// enrolTwoFactor enables 2FA for a user and returns the secret and recovery codes.
+func enrolTwoFactor(t *testing.T, service *AuthService, userID string) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	setup, err := service.BeginTwoFactorSetup(ctx, userID)
+	if err != nil {
+		t.Fatalf("BeginTwoFactorSetup: %v", err)
+	}
+
+	codes, err := service.ConfirmTwoFactorSetup(ctx, userID, &models.ConfirmTwoFactorRequest{
+		Code: currentTOTP(t, setup.Secret),
+	})
+	if err != nil {
+		t.Fatalf("ConfirmTwoFactorSetup: %v", err)
+	}
+
+	// Enrolment used the current step; move it back so the test can log in with a fresh code
+	service.twoFactorRepo.(*mockTwoFactorRepository).enrolments[userID].LastUsedStep = 0
+
+	return setup.Secret, codes.RecoveryCodes
+}
+
+func currentTOTP(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := crypto.TOTPCode(secret, crypto.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatalf("TOTPCode: %v", err)
+	}
+	return code
+}
+
+func passwordLogin(t *testing.T, service *AuthService, user *models.User, portal models.PortalType) *models.LoginResponse {
+	t.Helper()
+	resp, err := service.Login(context.Background(), &models.LoginRequest{
+		Identifier: user.Email,
+		Password:   twoFactorTestPassword,
+		Portal:     portal,
+	}, "192.168.1.1", "Mozilla/5.0")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return resp
+}
+
+// =====================================================================
+// Two-Factor Login Tests
+// =====================================================================
+
+func TestLogin_TwoFactorEnabled_ReturnsChallenge(t *testing.T) {
+	service, _, sessionRepo, user := setupTwoFactorTest(t, models.AccountTypeUser)
+	secret, _ := enrolTwoFactor(t, service, user.ID)
+	ctx := context.Background()
+
+	resp := passwordLogin(t, service, user, models.PortalTypeUser)
+	if !resp.TwoFactorRequired || resp.ChallengeToken == "" {
+		t.Fatalf("expected a two-factor challenge, got %+v", resp)
+	}
+	if resp.Token != "" {
+		t.Error("JWT must not be issued before the second factor")
+	}
+	if len(sessionRepo.sessions) != 0 {
+		t.Error("session must not be created before the second factor")
+	}
+
+	// The challenge token is not a session token
+	if _, err := service.ValidateToken(ctx, resp.ChallengeToken); err == nil {
+		t.Error("challenge token should not authenticate requests")
+	}
+
+	loginResp, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: resp.ChallengeToken,
+		Code:           currentTOTP(t, secret),
+	}, "192.168.1.1", "Mozilla/5.0")
+	if err != nil {
+		t.Fatalf("expected second step to succeed, got %v", err)
+	}
+	if loginResp.Token == "" {
+		t.Error("expected JWT after the second factor")
+	}
+	if len(sessionRepo.sessions) != 1 {
+		t.Errorf("expected 1 session, got %d", len(sessionRepo.sessions))
+	}
+
+	// The challenge is single-use
+	_, err = service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: resp.ChallengeToken,
+		Code:           currentTOTP(t, secret),
+	}, "192.168.1.1", "Mozilla/5.0")
+	if err == nil || err.Code != errors.ErrCodeUnauthorized {
+		t.Errorf("expected reused challenge to be rejected, got %v", err)
+	}
+}
+
+func TestCompleteTwoFactorLogin_ReplayedCodeRejected(t *testing.T) {
+	service, _, _, user := setupTwoFactorTest(t, models.AccountTypeUser)
+	secret, _ := enrolTwoFactor(t, service, user.ID)
+	ctx := context.Background()
+	code := currentTOTP(t, secret)
+
+	first := passwordLogin(t, service, user, models.PortalTypeUser)
+	if _, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: first.ChallengeToken,
+		Code:           code,
+	}, "", ""); err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+
+	second := passwordLogin(t, service, user, models.PortalTypeUser)
+	_, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: second.ChallengeToken,
+		Code:           code,
+	}, "", "")
+	if err == nil || err.Code != errors.ErrCodeUnauthorized {
+		t.Errorf("expected replayed code to be rejected, got %v", err)
+	}
+}
+
+func TestCompleteTwoFactorLogin_TooManyInvalidCodes(t *testing.T) {
+	service, _, _, user := setupTwoFactorTest(t, models.AccountTypeUser)
+	attempts := service.loginAttemptRepo.(*mockLoginAttemptRepository)
+	secret, _ := enrolTwoFactor(t, service, user.ID)
+	ctx := context.Background()
+
+	resp := passwordLogin(t, service, user, models.PortalTypeUser)
+	wrong := "000000"
+	if wrong == currentTOTP(t, secret) {
+		wrong = "111111"
+	}
+
+	for i := 1; i <= models.MaxLoginChallengeAttempts; i++ {
+		attempts.expireBlocks()
+		_, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+			ChallengeToken: resp.ChallengeToken,
+			Code:           wrong,
+		}, "", "")
+		if err == nil || err.Code != errors.ErrCodeUnauthorized {
+			t.Fatalf("attempt %d: expected unauthorized, got %v", i, err)
+		}
+	}
+
+	// Even the right code is refused once the challenge is spent
+	attempts.expireBlocks()
+	_, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: resp.ChallengeToken,
+		Code:           currentTOTP(t, secret),
+	}, "", "")
+	if err == nil {
+		t.Fatal("expected challenge to be invalidated after too many invalid codes")
+	}
+}
+
+func TestCompleteTwoFactorLogin_FailedCodesLockAccount(t *testing.T) {
+	service, _, _, user := setupTwoFactorTest(t, models.AccountTypeUser)
+	attempts := service.loginAttemptRepo.(*mockLoginAttemptRepository)
+	secret, _ := enrolTwoFactor(t, service, user.ID)
+	ctx := context.Background()
+
+	wrong := "000000"
+	if wrong == currentTOTP(t, secret) {
+		wrong = "111111"
+	}
+
+	// Fresh challenges from a correct password don't clear the wrong codes
+	var resp *models.LoginResponse
+	for i := 0; i < models.MaxFailedLogins; i++ {
+		attempts.expireBlocks()
+		if i%models.MaxLoginChallengeAttempts == 0 {
+			resp = passwordLogin(t, service, user, models.PortalTypeUser)
+		}
+		_, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+			ChallengeToken: resp.ChallengeToken,
+			Code:           wrong,
+		}, "203.0.113.7", chromeWindowsUA)
+		if err == nil || err.Code != errors.ErrCodeUnauthorized {
+			t.Fatalf("attempt %d: expected unauthorized, got %v", i+1, err)
+		}
+	}
+
+	if got := attempts.countAudit(models.LoginAuditLocked); got != 1 {
+		t.Errorf("expected 1 lockout audit entry, got %d", got)
+	}
+
+	if err := attemptLogin(service, user.Email, twoFactorTestPassword); err == nil || err.Code != errors.ErrCodeRateLimit {
+		t.Fatalf("expected locked login to be rejected with the right password, got %v", err)
+	}
+
+	// Logging in by phone gets a challenge, but the right code is still refused
+	resp, err := service.Login(ctx, &models.LoginRequest{
+		Identifier: user.Phone,
+		Password:   twoFactorTestPassword,
+	}, "203.0.113.7", chromeWindowsUA)
+	if err != nil {
+		t.Fatalf("Login by phone: %v", err)
+	}
+	_, err = service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: resp.ChallengeToken,
+		Code:           currentTOTP(t, secret),
+	}, "203.0.113.7", chromeWindowsUA)
+	if err == nil || err.Code != errors.ErrCodeRateLimit {
+		t.Fatalf("expected second factor to be refused while locked, got %v", err)
+	}
+
+	// Once the lockout has passed a full login clears the count
+	attempts.expireBlocks()
+	if _, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: resp.ChallengeToken,
+		Code:           currentTOTP(t, secret),
+	}, "203.0.113.7", chromeWindowsUA); err != nil {
+		t.Fatalf("expected login to succeed after the lockout, got %v", err)
+	}
+	if len(attempts.failures) != 0 {
+		t.Errorf("expected failures to be reset after login, got %v", attempts.failures)
+	}
+}
+
+func TestCompleteTwoFactorLogin_RecoveryCodeSingleUse(t *testing.T) {
+	service, _, _, user := setupTwoFactorTest(t, models.AccountTypeUser)
+	_, recoveryCodes := enrolTwoFactor(t, service, user.ID)
+	ctx := context.Background()
+
+	if len(recoveryCodes) != models.RecoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", models.RecoveryCodeCount, len(recoveryCodes))
+	}
+
+	first := passwordLogin(t, service, user, models.PortalTypeUser)
+	if _, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: first.ChallengeToken,
+		RecoveryCode:   recoveryCodes[0],
+	}, "", ""); err != nil {
+		t.Fatalf("expected recovery code to work, got %v", err)
+	}
+
+	second := passwordLogin(t, service, user, models.PortalTypeUser)
+	if _, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: second.ChallengeToken,
+		RecoveryCode:   recoveryCodes[0],
+	}, "", ""); err == nil {
+		t.Error("expected used recovery code to be rejected")
+	}
+
+	status, err := service.GetTwoFactorStatus(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetTwoFactorStatus: %v", err)
+	}
+	if status.RecoveryCodesRemaining != models.RecoveryCodeCount-1 {
+		t.Errorf("expected %d recovery codes remaining, got %d", models.RecoveryCodeCount-1, status.RecoveryCodesRemaining)
+	}
+}
+
+func TestLogin_AdminWithoutTwoFactor_MustEnrol(t *testing.T) {
+	service, twoFactorRepo, sessionRepo, admin := setupTwoFactorTest(t, models.AccountTypeAdmin)
+	ctx := context.Background()
+
+	resp := passwordLogin(t, service, admin, models.PortalTypeAdmin)
+	if !resp.TwoFactorSetupRequired || resp.Token != "" {
+		t.Fatalf("expected admin to be sent to 2FA enrolment, got %+v", resp)
+	}
+
+	// An enrolment challenge cannot be used to skip the second factor
+	if _, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: resp.ChallengeToken,
+		Code:           "123456",
+	}, "", ""); err == nil {
+		t.Fatal("expected enrolment challenge to be rejected by the verify step")
+	}
+
+	setup, err := service.BeginLoginTwoFactorSetup(ctx, &models.LoginTwoFactorSetupRequest{ChallengeToken: resp.ChallengeToken})
+	if err != nil {
+		t.Fatalf("BeginLoginTwoFactorSetup: %v", err)
+	}
+	if setup.OTPAuthURI == "" || setup.Secret == "" {
+		t.Fatal("expected secret and otpauth URI")
+	}
+
+	loginResp, err := service.ConfirmLoginTwoFactorSetup(ctx, &models.LoginTwoFactorConfirmRequest{
+		ChallengeToken: resp.ChallengeToken,
+		Code:           currentTOTP(t, setup.Secret),
+	}, "", "")
+	if err != nil {
+		t.Fatalf("ConfirmLoginTwoFactorSetup: %v", err)
+	}
+	if loginResp.Token == "" || len(loginResp.RecoveryCodes) != models.RecoveryCodeCount {
+		t.Errorf("expected JWT and recovery codes, got %+v", loginResp)
+	}
+	if len(sessionRepo.sessions) != 1 {
+		t.Errorf("expected 1 session, got %d", len(sessionRepo.sessions))
+	}
+	if !twoFactorRepo.enrolments[admin.ID].IsEnabled() {
+		t.Error("expected 2FA to be enabled")
+	}
+
+	// Next login asks for a code instead of enrolment
+	next := passwordLogin(t, service, admin, models.PortalTypeAdmin)
+	if !next.TwoFactorRequired || next.TwoFactorSetupRequired {
+		t.Errorf("expected code challenge on next login, got %+v", next)
+	}
+}
+
+// =====================================================================
+// Two-Factor Management Tests
+// =====================================================================
+
+func TestConfirmTwoFactorSetup_InvalidCode(t *testing.T) {
+	service, _, _, user := setupTwoFactorTest(t, models.AccountTypeUser)
+	ctx := context.Background()
+
+	if _, err := service.ConfirmTwoFactorSetup(ctx, user.ID, &models.ConfirmTwoFactorRequest{Code: "123456"}); err == nil || err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected bad request before setup starts, got %v", err)
+	}
+
+	setup, err := service.BeginTwoFactorSetup(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("BeginTwoFactorSetup: %v", err)
+	}
+
+	wrong := "000000"
+	if wrong == currentTOTP(t, setup.Secret) {
+		wrong = "111111"
+	}
+	// A wrong code is a 400, not a 401 that would look like an expired session
+	if _, err := service.ConfirmTwoFactorSetup(ctx, user.ID, &models.ConfirmTwoFactorRequest{Code: wrong}); err == nil || err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected bad request for wrong code, got %v", err)
+	}
+
+	status, _ := service.GetTwoFactorStatus(ctx, user.ID)
+	if status.Enabled {
+		t.Error("2FA should stay disabled until confirmed")
+	}
+}
+
+func TestBeginTwoFactorSetup_StoresSecretEncrypted(t *testing.T) {
+	service, twoFactorRepo, _, user := setupTwoFactorTest(t, models.AccountTypeUser)
+
+	setup, err := service.BeginTwoFactorSetup(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("BeginTwoFactorSetup: %v", err)
+	}
+
+	stored := twoFactorRepo.enrolments[user.ID].Secret
+	if stored == setup.Secret {
+		t.Fatal("expected the stored secret to be encrypted")
+	}
+	if secret, err := service.totpCipher.Decrypt(stored, user.ID); err != nil || secret != setup.Secret {
+		t.Errorf("expected the stored secret to decrypt to the issued one, got %q (%v)", secret, err)
+	}
+
+	// A secret copied to another user's enrolment does not decrypt
+	if _, err := service.totpCipher.Decrypt(stored, uuid.New().String()); err == nil {
+		t.Error("expected the secret to be bound to its user")
+	}
+}
+
+func TestBeginTwoFactorSetup_AlreadyEnabled(t *testing.T) {
+	service, _, _, user := setupTwoFactorTest(t, models.AccountTypeUser)
+	enrolTwoFactor(t, service, user.ID)
+
+	_, err := service.BeginTwoFactorSetup(context.Background(), user.ID)
+	if err == nil || err.Code != errors.ErrCodeConflict {
+		t.Errorf("expected conflict, got %v", err)
+	}
+}
+
+func TestDisableTwoFactor_AdminForbidden(t *testing.T) {
+	service, _, _, admin := setupTwoFactorTest(t, models.AccountTypeSuperAdmin)
+	secret, _ := enrolTwoFactor(t, service, admin.ID)
+
+	err := service.DisableTwoFactor(context.Background(), admin.ID, &models.DisableTwoFactorRequest{
+		Code: currentTOTP(t, secret),
+	})
+	if err == nil || err.Code != errors.ErrCodeForbidden {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+}
+
+func TestDisableTwoFactor_RequiresVerificationToken(t *testing.T) {
+	service, twoFactorRepo, _, user := setupTwoFactorTest(t, models.AccountTypeUser)
+	secret, _ := enrolTwoFactor(t, service, user.ID)
+
+	err := service.DisableTwoFactor(context.Background(), user.ID, &models.DisableTwoFactorRequest{
+		Code: currentTOTP(t, secret),
+	})
+	if err == nil || err.Code != errors.ErrCodeVerificationRequired {
+		t.Errorf("expected verification required, got %v", err)
+	}
+	if !twoFactorRepo.enrolments[user.ID].IsEnabled() {
+		t.Error("2FA should still be enabled")
+	}
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	service, _, _, user := setupTwoFactorTest(t, models.AccountTypeUser)
+	secret, oldCodes := enrolTwoFactor(t, service, user.ID)
+	ctx := context.Background()
+
+	resp, err := service.RegenerateRecoveryCodes(ctx, user.ID, &models.ConfirmTwoFactorRequest{
+		Code: currentTOTP(t, secret),
+	})
+	if err != nil {
+		t.Fatalf("RegenerateRecoveryCodes: %v", err)
+	}
+	if len(resp.RecoveryCodes) != models.RecoveryCodeCount {
+		t.Errorf("expected %d codes, got %d", models.RecoveryCodeCount, len(resp.RecoveryCodes))
+	}
+
+	// Old codes no longer work
+	login := passwordLogin(t, service, user, models.PortalTypeUser)
+	if _, err := service.CompleteTwoFactorLogin(ctx, &models.TwoFactorLoginRequest{
+		ChallengeToken: login.ChallengeToken,
+		RecoveryCode:   oldCodes[0],
+	}, "", ""); err == nil {
+		t.Error("expected old recovery code to be rejected")
+	}
+}
+
+func TestHashRecoveryCode_Normalizes(t *testing.T) {
+	if hashRecoveryCode("abcd-efgh-ijkl-mnop") != hashRecoveryCode("ABCDEFGH IJKLMNOP") {
+		t.Error("recovery codes should match regardless of case, dashes and spaces")
+	}
+}
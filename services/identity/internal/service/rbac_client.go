@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/vnykmshr/nivo/shared/clients"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 )
 
 // RBACClient handles communication with the RBAC service.
@@ -12,17 +13,10 @@ type RBACClient struct {
 	*clients.BaseClient
 }
 
-// NewRBACClient creates a new RBAC service client.
-func NewRBACClient(baseURL string) *RBACClient {
+// NewRBACClient creates an RBAC service client authenticated with service tokens.
+func NewRBACClient(baseURL string, tokens clients.ServiceTokenSource) *RBACClient {
 	return &RBACClient{
-		BaseClient: clients.NewBaseClient(baseURL, clients.ShortTimeout),
-	}
-}
-
-// NewRBACClientWithSecret creates an RBAC client with internal service authentication.
-func NewRBACClientWithSecret(baseURL, internalSecret string) *RBACClient {
-	return &RBACClient{
-		BaseClient: clients.NewInternalClient(baseURL, clients.ShortTimeout, internalSecret),
+		BaseClient: clients.NewInternalClient(baseURL, clients.ShortTimeout, tokens, sharedjwt.ServiceRBAC),
 	}
 }
 
@@ -46,7 +40,7 @@ type Permission struct {
 }
 
 // GetUserPermissions fetches all roles and permissions for a user.
-// Uses internal endpoint for service-to-service communication (no auth required).
+// Uses internal endpoint for service-to-service communication.
 func (c *RBACClient) GetUserPermissions(ctx context.Context, userID string) (*UserPermissionsResponse, error) {
 	var result UserPermissionsResponse
 	path := fmt.Sprintf("/internal/v1/users/%s/permissions", userID)
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
+)
+
+func TestServiceTokenIssuer_Issue(t *testing.T) {
+	keys := testSigningKeys()
+	issuer := NewServiceTokenIssuer(keys, map[string]string{"wallet": "wallet-secret"}, time.Minute)
+
+	token, err := issuer.Issue("wallet", "wallet-secret", sharedjwt.ServiceLedger)
+	if err != nil {
+		t.Fatalf("expected token, got %v", err)
+	}
+
+	claims, parseErr := sharedjwt.ParseServiceToken(token.Token, keys, sharedjwt.ServiceLedger)
+	if parseErr != nil {
+		t.Fatalf("expected issued token to verify, got %v", parseErr)
+	}
+	if claims.Subject != "wallet" {
+		t.Errorf("expected subject wallet, got %s", claims.Subject)
+	}
+	if token.ExpiresAt != claims.ExpiresAt.Unix() {
+		t.Errorf("expected expires_at %d, got %d", claims.ExpiresAt.Unix(), token.ExpiresAt)
+	}
+
+	for name, creds := range map[string][2]string{
+		"wrong secret":    {"wallet", "guess"},
+		"unknown service": {"ledger", "wallet-secret"},
+		"empty secret":    {"ledger", ""},
+	} {
+		if _, err := issuer.Issue(creds[0], creds[1], sharedjwt.ServiceLedger); err == nil || err.Code != errors.ErrCodeUnauthorized {
+			t.Errorf("%s: expected unauthorized, got %v", name, err)
+		}
+	}
+}
+
+func TestServiceTokenIssuer_Token(t *testing.T) {
+	keys := testSigningKeys()
+	issuer := NewServiceTokenIssuer(keys, nil, time.Minute)
+
+	token, err := issuer.Token(context.Background(), sharedjwt.ServiceRBAC)
+	if err != nil {
+		t.Fatalf("expected token, got %v", err)
+	}
+
+	claims, parseErr := sharedjwt.ParseServiceToken(token, keys, sharedjwt.ServiceRBAC)
+	if parseErr != nil || claims.Subject != sharedjwt.ServiceIdentity {
+		t.Errorf("expected identity token for rbac, got %+v, %v", claims, parseErr)
+	}
+}
+
+func TestParseServiceCredentials(t *testing.T) {
+	credentials, err := ParseServiceCredentials("wallet:abc, transaction:d:e,")
+	if err != nil {
+		t.Fatalf("expected credentials to parse, got %v", err)
+	}
+	if len(credentials) != 2 || credentials["wallet"] != "abc" || credentials["transaction"] != "d:e" {
+		t.Errorf("unexpected credentials: %v", credentials)
+	}
+
+	for _, value := range []string{"wallet", "wallet:", ":secret", "wallet:a,wallet:b"} {
+		if _, err := ParseServiceCredentials(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -278,6 +279,123 @@ func (s *VerificationService) ValidateVerificationToken(
 	return claims, nil
 }
 
+// RedeemVerificationToken validates a verification token for an operation and
+// uses it up. The token's metadata must match every value in expected, which
+// binds it to the details the user verified (e.g. amount and destination).
+// All rejections are Forbidden, so callers can ask the user to verify again.
+func (s *VerificationService) RedeemVerificationToken(
+	ctx context.Context,
+	tokenString string,
+	operation models.OperationType,
+	userID string,
+	expected map[string]interface{},
+) (*models.VerificationClaims, *errors.Error) {
+	claims, err := s.boundClaims(ctx, tokenString, operation, userID, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.MarkRedeemed(ctx, claims.VerificationID); err != nil {
+		return nil, err
+	}
+
+	s.logger.With(map[string]interface{}{
+		"verification_id": claims.VerificationID,
+		"user_id":         userID,
+		"operation":       operation,
+	}).Info("Verification token redeemed")
+
+	return claims, nil
+}
+
+// ReleaseVerificationToken makes a redeemed token usable again. Services call
+// it when the operation the token was redeemed for is rejected (e.g. a transfer
+// from a wallet with insufficient funds), so that the user can retry without
+// verifying again. The token is checked as for redemption and must still be
+// valid; releasing a token that is not redeemed does nothing.
+func (s *VerificationService) ReleaseVerificationToken(
+	ctx context.Context,
+	tokenString string,
+	operation models.OperationType,
+	userID string,
+	expected map[string]interface{},
+) (*models.VerificationClaims, *errors.Error) {
+	claims, err := s.boundClaims(ctx, tokenString, operation, userID, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReleaseRedemption(ctx, claims.VerificationID); err != nil {
+		return nil, err
+	}
+
+	s.logger.With(map[string]interface{}{
+		"verification_id": claims.VerificationID,
+		"user_id":         userID,
+		"operation":       operation,
+	}).Info("Verification token released")
+
+	return claims, nil
+}
+
+// boundClaims validates a token for an operation and checks that it is bound to
+// the expected details. All rejections are Forbidden.
+func (s *VerificationService) boundClaims(
+	ctx context.Context,
+	tokenString string,
+	operation models.OperationType,
+	userID string,
+	expected map[string]interface{},
+) (*models.VerificationClaims, *errors.Error) {
+	claims, err := s.ValidateVerificationToken(ctx, tokenString, operation, userID)
+	if err != nil {
+		if err.Code == errors.ErrCodeUnauthorized {
+			return nil, errors.Forbidden(err.Message)
+		}
+		return nil, err
+	}
+
+	if !metadataMatches(claims.Metadata, expected) {
+		s.logger.With(map[string]interface{}{
+			"verification_id": claims.VerificationID,
+			"user_id":         userID,
+			"operation":       operation,
+		}).Warn("Verification token used for different operation details")
+		return nil, errors.Forbidden("verification token does not match this request")
+	}
+
+	return claims, nil
+}
+
+// metadataMatches reports whether metadata holds every expected value.
+// Values are compared in their string form, so the amount 1500000 matches
+// whether it was sent as a JSON number or a string.
+func metadataMatches(metadata models.VerificationMeta, expected map[string]interface{}) bool {
+	for key, want := range expected {
+		got, ok := metadata[key]
+		if !ok || metadataString(got) != metadataString(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// metadataString formats a metadata value for comparison.
+func metadataString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 // CancelVerification cancels a pending verification.
 func (s *VerificationService) CancelVerification(
 	ctx context.Context,
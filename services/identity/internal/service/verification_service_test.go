@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vnykmshr/nivo/services/identity/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+func newTestVerificationToken(t *testing.T, svc *VerificationService, op models.OperationType, metadata models.VerificationMeta) string {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret-for-verification-tokens")
+
+	token, err := svc.generateVerificationToken(&models.VerificationRequest{
+		ID:            "ver_test",
+		UserID:        "user-1",
+		OperationType: op,
+		Metadata:      metadata,
+	})
+	if err != nil {
+		t.Fatalf("expected no error generating token, got %v", err)
+	}
+	return token
+}
+
+func TestMetadataMatches(t *testing.T) {
+	metadata := models.VerificationMeta{
+		"amount":                float64(1500000), // JSON numbers decode as float64
+		"destination_wallet_id": "wallet-2",
+	}
+
+	tests := []struct {
+		name     string
+		expected map[string]interface{}
+		want     bool
+	}{
+		{"same values", map[string]interface{}{"amount": int64(1500000), "destination_wallet_id": "wallet-2"}, true},
+		{"amount as string", map[string]interface{}{"amount": "1500000"}, true},
+		{"different amount", map[string]interface{}{"amount": int64(1500001), "destination_wallet_id": "wallet-2"}, false},
+		{"different destination", map[string]interface{}{"amount": int64(1500000), "destination_wallet_id": "wallet-3"}, false},
+		{"missing key", map[string]interface{}{"phone": "+919876543210"}, false},
+		{"nothing expected", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := metadataMatches(metadata, tt.expected); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRedeemVerificationToken_Rejected(t *testing.T) {
+	svc := NewVerificationService(nil, nil)
+	token := newTestVerificationToken(t, svc, models.OpBeneficiaryAdd, models.VerificationMeta{"phone": "+919876543210"})
+
+	tests := []struct {
+		name     string
+		token    string
+		op       models.OperationType
+		userID   string
+		expected map[string]interface{}
+	}{
+		{"invalid token", "not-a-token", models.OpBeneficiaryAdd, "user-1", nil},
+		{"other operation", token, models.OpHighValueTransfer, "user-1", nil},
+		{"other user", token, models.OpBeneficiaryAdd, "user-2", nil},
+		{"other phone", token, models.OpBeneficiaryAdd, "user-1", map[string]interface{}{"phone": "+919999999999"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.RedeemVerificationToken(context.Background(), tt.token, tt.op, tt.userID, tt.expected)
+			if err == nil || err.Code != errors.ErrCodeForbidden {
+				t.Errorf("expected forbidden, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReleaseVerificationToken_Rejected(t *testing.T) {
+	svc := NewVerificationService(nil, nil)
+	token := newTestVerificationToken(t, svc, models.OpHighValueTransfer, models.VerificationMeta{
+		"amount":                float64(1500000),
+		"destination_wallet_id": "wallet-2",
+	})
+
+	tests := []struct {
+		name     string
+		token    string
+		userID   string
+		expected map[string]interface{}
+	}{
+		{"invalid token", "not-a-token", "user-1", nil},
+		{"other user", token, "user-2", nil},
+		{"other destination", token, "user-1", map[string]interface{}{"amount": int64(1500000), "destination_wallet_id": "wallet-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ReleaseVerificationToken(context.Background(), tt.token, models.OpHighValueTransfer, tt.userID, tt.expected)
+			if err == nil || err.Code != errors.ErrCodeForbidden {
+				t.Errorf("expected forbidden, got %v", err)
+			}
+		})
+	}
+}
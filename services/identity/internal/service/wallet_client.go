@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/vnykmshr/nivo/shared/clients"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 )
 
 // WalletClient handles communication with the Wallet service.
@@ -12,17 +13,10 @@ type WalletClient struct {
 	*clients.BaseClient
 }
 
-// NewWalletClient creates a new wallet service client.
-func NewWalletClient(baseURL string) *WalletClient {
+// NewWalletClient creates a wallet service client authenticated with service tokens.
+func NewWalletClient(baseURL string, tokens clients.ServiceTokenSource) *WalletClient {
 	return &WalletClient{
-		BaseClient: clients.NewBaseClient(baseURL, clients.DefaultTimeout),
-	}
-}
-
-// NewWalletClientWithSecret creates a wallet client with internal service authentication.
-func NewWalletClientWithSecret(baseURL, internalSecret string) *WalletClient {
-	return &WalletClient{
-		BaseClient: clients.NewInternalClient(baseURL, clients.DefaultTimeout, internalSecret),
+		BaseClient: clients.NewInternalClient(baseURL, clients.DefaultTimeout, tokens, sharedjwt.ServiceWallet),
 	}
 }
 
@@ -56,7 +50,7 @@ func (c *WalletClient) CreateDefaultWallet(ctx context.Context, userID string) (
 	}
 
 	var result WalletResponse
-	// Use internal endpoint for service-to-service calls (no auth required)
+	// Use internal endpoint for service-to-service calls
 	if err := c.Post(ctx, "/internal/v1/wallets", req, &result); err != nil {
 		return nil, err
 	}
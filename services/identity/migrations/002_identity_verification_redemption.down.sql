@@ -0,0 +1 @@
+ALTER TABLE verification_requests DROP COLUMN IF EXISTS redeemed_at;
@@ -0,0 +1,5 @@
+-- Single-use verification tokens
+-- A verified request's token can be redeemed once by the operation it authorizes.
+
+ALTER TABLE verification_requests
+    ADD COLUMN IF NOT EXISTS redeemed_at TIMESTAMP WITH TIME ZONE;
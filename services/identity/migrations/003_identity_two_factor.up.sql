@@ -0,0 +1,38 @@
+-- Two-factor authentication
+-- TOTP enrolment, single-use recovery codes and the login challenges issued
+-- between the password check and the second factor.
+
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,                   -- Base32 TOTP secret, AES-256-GCM encrypted with TOTP_ENCRYPTION_KEY
+    enabled_at TIMESTAMP WITH TIME ZONE,    -- NULL until enrolment is confirmed with a code
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- Last accepted time step; older codes are replays
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,         -- SHA-256 of the normalized code
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_user_recovery_codes UNIQUE (user_id, code_hash)
+);
+
+CREATE INDEX idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
+
+CREATE TABLE IF NOT EXISTS login_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('2fa_verify', '2fa_enroll')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    consumed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_challenges_user_id ON login_challenges(user_id);
+CREATE INDEX idx_login_challenges_expires_at ON login_challenges(expires_at);
@@ -0,0 +1,17 @@
+-- Refresh tokens
+-- Each session is a token family: every refresh rotates the token, and presenting
+-- a rotated token again revokes the session and every token in it.
+-- sessions.expires_at now tracks the refresh token; access tokens expire via their JWT exp.
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE, -- Token family
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,       -- Set on rotation; a used token presented again is reuse
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
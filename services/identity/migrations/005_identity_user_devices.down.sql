@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_devices;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS last_seen_at;
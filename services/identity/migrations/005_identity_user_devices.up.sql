@@ -0,0 +1,15 @@
+-- Session and device management
+-- Sessions record when they were last refreshed, and user_devices remembers every
+-- device an account has signed in from so logins from a new one can be flagged.
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+CREATE TABLE IF NOT EXISTS user_devices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_name VARCHAR(100) NOT NULL,      -- Derived from the user agent, e.g. "Chrome on Windows"
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_devices_user_device_unique UNIQUE (user_id, device_name)
+);
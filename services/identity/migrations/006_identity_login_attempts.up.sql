@@ -0,0 +1,27 @@
+-- Login lockout
+-- Failed logins are counted per portal and identifier, whether or not an account
+-- exists. Redis holds the counters when configured; login_attempts is the
+-- fallback store. login_audit_log keeps failures, lockouts and admin unlocks.
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    identifier_hash VARCHAR(64) PRIMARY KEY,   -- SHA-256 of portal and normalized identifier
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    blocked_until TIMESTAMP WITH TIME ZONE     -- Progressive delay or lockout
+);
+
+CREATE TABLE IF NOT EXISTS login_audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event VARCHAR(20) NOT NULL,
+    identifier_hash VARCHAR(64),               -- NULL for unlocks, which cover every identifier of the user
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,   -- NULL when no account matched
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,  -- Admin who unlocked
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT login_audit_event_check CHECK (event IN ('login_failed', 'login_locked', 'login_unlocked'))
+);
+
+CREATE INDEX idx_login_audit_log_user_id ON login_audit_log(user_id, created_at DESC);
+CREATE INDEX idx_login_audit_log_identifier ON login_audit_log(identifier_hash, created_at DESC);
@@ -6,6 +6,7 @@ import (
 	"github.com/vnykmshr/nivo/services/ledger/internal/handler"
 	"github.com/vnykmshr/nivo/services/ledger/internal/repository"
 	"github.com/vnykmshr/nivo/services/ledger/internal/service"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/server"
 )
 
@@ -16,13 +17,17 @@ func main() {
 			// Initialize repositories
 			accountRepo := repository.NewAccountRepository(ctx.DB)
 			journalRepo := repository.NewJournalEntryRepository(ctx.DB)
+			reportRepo := repository.NewReportRepository(ctx.DB)
+			periodRepo := repository.NewPeriodRepository(ctx.DB)
 
 			// Initialize services
-			ledgerService := service.NewLedgerService(accountRepo, journalRepo)
+			ledgerService := service.NewLedgerService(accountRepo, journalRepo, periodRepo)
+			reportService := service.NewReportService(reportRepo)
+			periodService := service.NewPeriodService(periodRepo)
 
-			// Get JWT secret and setup router
-			jwtSecret := server.RequireEnv("JWT_SECRET")
-			router := handler.NewRouter(ledgerService, jwtSecret)
+			// Verify tokens against the identity service's published keys and setup router
+			jwtKeys := sharedjwt.NewRemoteKeySet(server.GetEnv("JWKS_URL", sharedjwt.DefaultJWKSURL))
+			router := handler.NewRouter(ledgerService, reportService, periodService, jwtKeys)
 
 			return router.SetupRoutes(), nil
 		},
@@ -3,11 +3,13 @@ package handler
 import (
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/vnykmshr/gopantic/pkg/model"
 	"github.com/vnykmshr/nivo/services/ledger/internal/models"
 	"github.com/vnykmshr/nivo/services/ledger/internal/service"
 	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/pagination"
 	"github.com/vnykmshr/nivo/shared/response"
 )
 
@@ -149,7 +151,8 @@ func (h *LedgerHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetAccountBalance retrieves the current balance of an account.
-// GET /api/v1/accounts/:id/balance
+// With as_of, the balance is computed from posted ledger lines at that point in time.
+// GET /api/v1/accounts/:id/balance?as_of=2025-03-31T23:59:00Z
 func (h *LedgerHandler) GetAccountBalance(w http.ResponseWriter, r *http.Request) {
 	accountID := r.PathValue("id")
 	if accountID == "" {
@@ -157,6 +160,23 @@ func (h *LedgerHandler) GetAccountBalance(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if asOfParam := r.URL.Query().Get("as_of"); asOfParam != "" {
+		asOf, parseErr := parseReportTime(asOfParam, "as_of", true, currentTime())
+		if parseErr != nil {
+			response.Error(w, parseErr)
+			return
+		}
+
+		accountBalance, svcErr := h.ledgerService.GetAccountBalanceAsOf(r.Context(), accountID, asOf)
+		if svcErr != nil {
+			response.Error(w, svcErr)
+			return
+		}
+
+		response.OK(w, accountBalance)
+		return
+	}
+
 	balance, svcErr := h.ledgerService.GetAccountBalance(r.Context(), accountID)
 	if svcErr != nil {
 		response.Error(w, svcErr)
@@ -169,6 +189,42 @@ func (h *LedgerHandler) GetAccountBalance(w http.ResponseWriter, r *http.Request
 	})
 }
 
+// GetAccountLedger lists an account's posted ledger lines with running balances.
+// GET /api/v1/accounts/:id/ledger?from=2025-03-01&to=2025-03-31&page=1&per_page=20
+func (h *LedgerHandler) GetAccountLedger(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("id")
+	if accountID == "" {
+		response.Error(w, errors.BadRequest("account ID is required"))
+		return
+	}
+
+	var from *time.Time
+	if fromParam := r.URL.Query().Get("from"); fromParam != "" {
+		parsed, parseErr := parseReportTime(fromParam, "from", false, time.Time{})
+		if parseErr != nil {
+			response.Error(w, parseErr)
+			return
+		}
+		from = &parsed
+	}
+
+	to, toErr := parseReportTime(r.URL.Query().Get("to"), "to", true, currentTime())
+	if toErr != nil {
+		response.Error(w, toErr)
+		return
+	}
+
+	params := pagination.FromRequest(r)
+
+	ledger, total, svcErr := h.ledgerService.GetAccountLedger(r.Context(), accountID, from, to, params.PerPage, params.Offset)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.Paginated(w, ledger, params.Page, params.PerPage, total)
+}
+
 // CreateJournalEntry creates a new journal entry.
 // POST /api/v1/journal-entries
 func (h *LedgerHandler) CreateJournalEntry(w http.ResponseWriter, r *http.Request) {
@@ -353,9 +409,60 @@ func (h *LedgerHandler) ReverseJournalEntry(w http.ResponseWriter, r *http.Reque
 	response.Created(w, reversalEntry)
 }
 
+// GetAccountBalancesInternal returns current balances for a batch of accounts (internal endpoint).
+// POST /internal/v1/accounts/balances
+// This is an internal endpoint for service-to-service communication (service token required).
+func (h *LedgerHandler) GetAccountBalancesInternal(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+
+	req, err := model.ParseInto[models.AccountBalancesRequest](body)
+	if err != nil {
+		response.Error(w, errors.Validation(err.Error()))
+		return
+	}
+
+	balances, svcErr := h.ledgerService.GetAccountBalances(r.Context(), req.AccountIDs)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, balances)
+}
+
+// RecordJournalEntryInternal creates and posts a journal entry (internal endpoint).
+// POST /internal/v1/journal-entries
+// Idempotent on reference_type and reference_id, so callers may safely retry.
+// This is an internal endpoint for service-to-service communication (service token required).
+func (h *LedgerHandler) RecordJournalEntryInternal(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+
+	req, err := model.ParseInto[models.CreateJournalEntryRequest](body)
+	if err != nil {
+		response.Error(w, errors.Validation(err.Error()))
+		return
+	}
+
+	entry, svcErr := h.ledgerService.RecordJournalEntry(r.Context(), &req)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, entry)
+}
+
 // CreateAccountInternal creates a new ledger account (internal endpoint).
 // POST /internal/v1/accounts
-// This is an internal endpoint for service-to-service communication (no authentication required).
+// This is an internal endpoint for service-to-service communication (service token required).
 func (h *LedgerHandler) CreateAccountInternal(w http.ResponseWriter, r *http.Request) {
 	// Read request body
 	body, err := io.ReadAll(r.Body)
@@ -113,6 +113,16 @@ func (m *mockAccountRepository) GetBalance(ctx context.Context, accountID string
 	return 0, errors.NotFound("account not found")
 }
 
+func (m *mockAccountRepository) ListBalances(ctx context.Context, accountIDs []string) ([]*models.AccountBalance, *errors.Error) {
+	balances := make([]*models.AccountBalance, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		if acct, ok := m.accounts[id]; ok {
+			balances = append(balances, &models.AccountBalance{AccountID: acct.ID, Balance: acct.Balance})
+		}
+	}
+	return balances, nil
+}
+
 func (m *mockAccountRepository) AddAccount(acct *models.Account) {
 	m.accounts[acct.ID] = acct
 }
@@ -129,6 +139,9 @@ type mockJournalEntryRepository struct {
 	ListFunc    func(ctx context.Context, status *models.EntryStatus, limit, offset int) ([]*models.JournalEntry, *errors.Error)
 	PostFunc    func(ctx context.Context, entryID, postedBy string) *errors.Error
 	VoidFunc    func(ctx context.Context, entryID, voidedBy, voidReason string) *errors.Error
+
+	SumAccountActivityFunc func(ctx context.Context, accountID string, asOf time.Time) (int64, int64, *errors.Error)
+	ListAccountLinesFunc   func(ctx context.Context, accountID string, from *time.Time, to time.Time, limit, offset int) ([]models.AccountLedgerLine, int64, *errors.Error)
 }
 
 func newMockJournalEntryRepository() *mockJournalEntryRepository {
@@ -203,10 +216,83 @@ func (m *mockJournalEntryRepository) Void(ctx context.Context, entryID, voidedBy
 	return errors.NotFound("journal entry not found")
 }
 
+func (m *mockJournalEntryRepository) SumAccountActivity(ctx context.Context, accountID string, asOf time.Time) (int64, int64, *errors.Error) {
+	if m.SumAccountActivityFunc != nil {
+		return m.SumAccountActivityFunc(ctx, accountID, asOf)
+	}
+	return 0, 0, nil
+}
+
+func (m *mockJournalEntryRepository) ListAccountLines(ctx context.Context, accountID string, from *time.Time, to time.Time, limit, offset int) ([]models.AccountLedgerLine, int64, *errors.Error) {
+	if m.ListAccountLinesFunc != nil {
+		return m.ListAccountLinesFunc(ctx, accountID, from, to, limit, offset)
+	}
+	return []models.AccountLedgerLine{}, 0, nil
+}
+
+func (m *mockJournalEntryRepository) GetByReference(ctx context.Context, referenceType, referenceID string) (*models.JournalEntry, *errors.Error) {
+	return nil, nil
+}
+
 func (m *mockJournalEntryRepository) AddEntry(entry *models.JournalEntry) {
 	m.entries[entry.ID] = entry
 }
 
+// ============================================================
+// Mock Period Repository
+// ============================================================
+
+type mockPeriodRepository struct {
+	periods []*models.AccountingPeriod
+}
+
+func (m *mockPeriodRepository) Create(ctx context.Context, period *models.AccountingPeriod) *errors.Error {
+	m.periods = append(m.periods, period)
+	return nil
+}
+
+func (m *mockPeriodRepository) GetByID(ctx context.Context, id string) (*models.AccountingPeriod, *errors.Error) {
+	for _, period := range m.periods {
+		if period.ID == id {
+			return period, nil
+		}
+	}
+	return nil, errors.NotFound("accounting period not found")
+}
+
+func (m *mockPeriodRepository) GetForDate(ctx context.Context, t time.Time) (*models.AccountingPeriod, *errors.Error) {
+	for _, period := range m.periods {
+		if period.Contains(t) {
+			return period, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockPeriodRepository) HasOverlap(ctx context.Context, startsAt, endsAt time.Time) (bool, *errors.Error) {
+	return false, nil
+}
+
+func (m *mockPeriodRepository) List(ctx context.Context, status *models.PeriodStatus, limit, offset int) ([]*models.AccountingPeriod, *errors.Error) {
+	return m.periods, nil
+}
+
+func (m *mockPeriodRepository) MarkClosing(ctx context.Context, id string) *errors.Error {
+	return nil
+}
+
+func (m *mockPeriodRepository) Close(ctx context.Context, id, closedBy string) *errors.Error {
+	return nil
+}
+
+func (m *mockPeriodRepository) Reopen(ctx context.Context, id, reopenedBy, reason string) *errors.Error {
+	return nil
+}
+
+func (m *mockPeriodRepository) ListBalances(ctx context.Context, periodID string) ([]*models.PeriodBalance, *errors.Error) {
+	return []*models.PeriodBalance{}, nil
+}
+
 // ============================================================
 // Test Helper Functions
 // ============================================================
@@ -225,7 +311,7 @@ type apiError struct {
 func createTestLedgerService() (*service.LedgerService, *mockAccountRepository, *mockJournalEntryRepository) {
 	accountRepo := newMockAccountRepository()
 	journalRepo := newMockJournalEntryRepository()
-	ledgerService := service.NewLedgerService(accountRepo, journalRepo)
+	ledgerService := service.NewLedgerService(accountRepo, journalRepo, &mockPeriodRepository{})
 	return ledgerService, accountRepo, journalRepo
 }
 
@@ -586,7 +672,7 @@ func TestLedgerHandler_UpdateAccount(t *testing.T) {
 }
 
 func TestLedgerHandler_GetAccountBalance(t *testing.T) {
-	ledgerService, accountRepo, _ := createTestLedgerService()
+	ledgerService, accountRepo, journalRepo := createTestLedgerService()
 	handler := NewLedgerHandler(ledgerService)
 
 	// Setup: Add a test account with balance
@@ -630,6 +716,83 @@ func TestLedgerHandler_GetAccountBalance(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
+
+	t.Run("get balance as of a date computes from ledger lines", func(t *testing.T) {
+		journalRepo.SumAccountActivityFunc = func(ctx context.Context, accountID string, asOf time.Time) (int64, int64, *errors.Error) {
+			assert.Equal(t, time.Date(2025, 3, 31, 23, 59, 59, 999999000, time.UTC), asOf)
+			return 300000, 100000, nil
+		}
+		defer func() { journalRepo.SumAccountActivityFunc = nil }()
+
+		rec, resp := makeRequestWithPathValue(t, handler.GetAccountBalance, http.MethodGet, "/api/v1/accounts/acct-balance-test/balance?as_of=2025-03-31", "id", "acct-balance-test", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, resp.Success)
+
+		var balance map[string]interface{}
+		err := json.Unmarshal(resp.Data, &balance)
+		require.NoError(t, err)
+		assert.Equal(t, float64(200000), balance["balance"])
+		assert.Equal(t, float64(300000), balance["debit_total"])
+		assert.Equal(t, float64(100000), balance["credit_total"])
+	})
+
+	t.Run("get balance with invalid as_of returns 400", func(t *testing.T) {
+		rec, resp := makeRequestWithPathValue(t, handler.GetAccountBalance, http.MethodGet, "/api/v1/accounts/acct-balance-test/balance?as_of=31-03-2025", "id", "acct-balance-test", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.False(t, resp.Success)
+	})
+}
+
+func TestLedgerHandler_GetAccountLedger(t *testing.T) {
+	ledgerService, accountRepo, journalRepo := createTestLedgerService()
+	handler := NewLedgerHandler(ledgerService)
+
+	accountRepo.AddAccount(&models.Account{
+		ID:       "acct-ledger-test",
+		Code:     "1000",
+		Name:     "Cash",
+		Type:     models.AccountTypeAsset,
+		Currency: "INR",
+		Status:   models.AccountStatusActive,
+	})
+
+	t.Run("list account ledger returns paginated lines", func(t *testing.T) {
+		journalRepo.ListAccountLinesFunc = func(ctx context.Context, accountID string, from *time.Time, to time.Time, limit, offset int) ([]models.AccountLedgerLine, int64, *errors.Error) {
+			require.NotNil(t, from)
+			assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), *from)
+			assert.Equal(t, 10, limit)
+			assert.Equal(t, 10, offset)
+			return []models.AccountLedgerLine{
+				{LineID: "line-1", DebitAmount: 5000, RunningBalance: 5000},
+			}, 11, nil
+		}
+
+		rec, resp := makeRequestWithPathValue(t, handler.GetAccountLedger, http.MethodGet, "/api/v1/accounts/acct-ledger-test/ledger?from=2025-03-01&to=2025-03-31&page=2&per_page=10", "id", "acct-ledger-test", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, resp.Success)
+
+		var ledger models.AccountLedger
+		err := json.Unmarshal(resp.Data, &ledger)
+		require.NoError(t, err)
+		assert.Equal(t, "1000", ledger.Code)
+		require.Len(t, ledger.Lines, 1)
+		assert.Equal(t, int64(5000), ledger.Lines[0].RunningBalance)
+	})
+
+	t.Run("list account ledger with invalid from returns 400", func(t *testing.T) {
+		rec, _ := makeRequestWithPathValue(t, handler.GetAccountLedger, http.MethodGet, "/api/v1/accounts/acct-ledger-test/ledger?from=yesterday", "id", "acct-ledger-test", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("list ledger for non-existent account returns 404", func(t *testing.T) {
+		rec, _ := makeRequestWithPathValue(t, handler.GetAccountLedger, http.MethodGet, "/api/v1/accounts/missing/ledger", "id", "missing", nil)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
 }
 
 // ============================================================
@@ -868,3 +1031,42 @@ func TestLedgerHandler_ReverseJournalEntry(t *testing.T) {
 		assert.Equal(t, "VALIDATION_ERROR", resp.Error.Code)
 	})
 }
+
+func TestLedgerHandler_GetAccountBalancesInternal(t *testing.T) {
+	ledgerService, accountRepo, _ := createTestLedgerService()
+	handler := NewLedgerHandler(ledgerService)
+
+	accountID := "6f1c2d3e-4a5b-4c6d-8e7f-8091a2b3c4d5"
+	accountRepo.AddAccount(&models.Account{
+		ID:      accountID,
+		Code:    "1001",
+		Name:    "Wallet",
+		Type:    models.AccountTypeAsset,
+		Balance: 125000,
+		Status:  models.AccountStatusActive,
+	})
+
+	t.Run("returns balances for known accounts", func(t *testing.T) {
+		body := map[string]interface{}{
+			"account_ids": []string{accountID, "0a0b0c0d-0000-4000-8000-000000000000"},
+		}
+		rec, resp := makeRequest(t, handler.GetAccountBalancesInternal, http.MethodPost, "/internal/v1/accounts/balances", body)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, resp.Success)
+
+		var balances []map[string]interface{}
+		require.NoError(t, json.Unmarshal(resp.Data, &balances))
+		require.Len(t, balances, 1)
+		assert.Equal(t, accountID, balances[0]["account_id"])
+		assert.Equal(t, float64(125000), balances[0]["balance"])
+	})
+
+	t.Run("rejects malformed account IDs", func(t *testing.T) {
+		body := map[string]interface{}{"account_ids": []string{"not-a-uuid"}}
+		rec, resp := makeRequest(t, handler.GetAccountBalancesInternal, http.MethodPost, "/internal/v1/accounts/balances", body)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.False(t, resp.Success)
+	})
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/vnykmshr/gopantic/pkg/model"
+	"github.com/vnykmshr/nivo/services/ledger/internal/models"
+	"github.com/vnykmshr/nivo/services/ledger/internal/service"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	"github.com/vnykmshr/nivo/shared/pagination"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// PeriodHandler handles HTTP requests for accounting periods.
+type PeriodHandler struct {
+	periodService *service.PeriodService
+}
+
+// NewPeriodHandler creates a new accounting period handler.
+func NewPeriodHandler(periodService *service.PeriodService) *PeriodHandler {
+	return &PeriodHandler{
+		periodService: periodService,
+	}
+}
+
+// CreatePeriod creates a new accounting period.
+// POST /api/v1/periods
+func (h *PeriodHandler) CreatePeriod(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+
+	req, err := model.ParseInto[models.CreatePeriodRequest](body)
+	if err != nil {
+		response.Error(w, errors.Validation(err.Error()))
+		return
+	}
+
+	period, svcErr := h.periodService.CreatePeriod(r.Context(), &req)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.Created(w, period)
+}
+
+// GetPeriod retrieves an accounting period by ID.
+// GET /api/v1/periods/:id
+func (h *PeriodHandler) GetPeriod(w http.ResponseWriter, r *http.Request) {
+	periodID := r.PathValue("id")
+	if periodID == "" {
+		response.Error(w, errors.BadRequest("period ID is required"))
+		return
+	}
+
+	period, svcErr := h.periodService.GetPeriod(r.Context(), periodID)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, period)
+}
+
+// ListPeriods lists accounting periods, most recent first.
+// GET /api/v1/periods?status=closed&page=1&per_page=20
+func (h *PeriodHandler) ListPeriods(w http.ResponseWriter, r *http.Request) {
+	var status *models.PeriodStatus
+	if statusParam := r.URL.Query().Get("status"); statusParam != "" {
+		s := models.PeriodStatus(statusParam)
+		status = &s
+	}
+
+	params := pagination.FromRequest(r)
+
+	periods, svcErr := h.periodService.ListPeriods(r.Context(), status, params.PerPage, params.Offset)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, periods)
+}
+
+// ClosePeriod closes an accounting period and snapshots account balances.
+// POST /api/v1/periods/:id/close
+func (h *PeriodHandler) ClosePeriod(w http.ResponseWriter, r *http.Request) {
+	periodID := r.PathValue("id")
+	if periodID == "" {
+		response.Error(w, errors.BadRequest("period ID is required"))
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	period, svcErr := h.periodService.ClosePeriod(r.Context(), periodID, userID)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, period)
+}
+
+// ReopenPeriod reopens a closed accounting period.
+// POST /api/v1/periods/:id/reopen
+func (h *PeriodHandler) ReopenPeriod(w http.ResponseWriter, r *http.Request) {
+	periodID := r.PathValue("id")
+	if periodID == "" {
+		response.Error(w, errors.BadRequest("period ID is required"))
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+
+	req, err := model.ParseInto[models.ReopenPeriodRequest](body)
+	if err != nil {
+		response.Error(w, errors.Validation(err.Error()))
+		return
+	}
+
+	period, svcErr := h.periodService.ReopenPeriod(r.Context(), periodID, userID, req.Reason)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, period)
+}
+
+// GetPeriodBalances returns the account balances captured when a period was closed.
+// GET /api/v1/periods/:id/balances
+func (h *PeriodHandler) GetPeriodBalances(w http.ResponseWriter, r *http.Request) {
+	periodID := r.PathValue("id")
+	if periodID == "" {
+		response.Error(w, errors.BadRequest("period ID is required"))
+		return
+	}
+
+	balances, svcErr := h.periodService.GetPeriodBalances(r.Context(), periodID)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, balances)
+}
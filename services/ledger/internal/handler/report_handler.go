@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/ledger/internal/service"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// dateFormat is the accepted date-only format for report query parameters.
+const dateFormat = "2006-01-02"
+
+// ReportHandler handles HTTP requests for financial reports.
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+// NewReportHandler creates a new report handler.
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// GetTrialBalance returns the trial balance as of a given date.
+// GET /api/v1/reports/trial-balance?as_of=2025-03-31
+func (h *ReportHandler) GetTrialBalance(w http.ResponseWriter, r *http.Request) {
+	asOf, parseErr := parseReportTime(r.URL.Query().Get("as_of"), "as_of", true, currentTime())
+	if parseErr != nil {
+		response.Error(w, parseErr)
+		return
+	}
+
+	report, svcErr := h.reportService.GetTrialBalance(r.Context(), asOf)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, report)
+}
+
+// GetBalanceSheet returns the balance sheet as of a given date.
+// GET /api/v1/reports/balance-sheet?as_of=2025-03-31
+func (h *ReportHandler) GetBalanceSheet(w http.ResponseWriter, r *http.Request) {
+	asOf, parseErr := parseReportTime(r.URL.Query().Get("as_of"), "as_of", true, currentTime())
+	if parseErr != nil {
+		response.Error(w, parseErr)
+		return
+	}
+
+	report, svcErr := h.reportService.GetBalanceSheet(r.Context(), asOf)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, report)
+}
+
+// GetIncomeStatement returns the income statement for a period.
+// Defaults to the current month to date.
+// GET /api/v1/reports/income-statement?from=2025-03-01&to=2025-03-31
+func (h *ReportHandler) GetIncomeStatement(w http.ResponseWriter, r *http.Request) {
+	now := currentTime()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	from, fromErr := parseReportTime(r.URL.Query().Get("from"), "from", false, monthStart)
+	if fromErr != nil {
+		response.Error(w, fromErr)
+		return
+	}
+
+	to, toErr := parseReportTime(r.URL.Query().Get("to"), "to", true, now)
+	if toErr != nil {
+		response.Error(w, toErr)
+		return
+	}
+
+	report, svcErr := h.reportService.GetIncomeStatement(r.Context(), from, to)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, report)
+}
+
+// parseReportTime parses a report query parameter as either YYYY-MM-DD or RFC3339.
+// Date-only values are interpreted in UTC; when endOfDay is set they resolve to
+// the last microsecond of that day so the whole day is included.
+func parseReportTime(value, name string, endOfDay bool, defaultValue time.Time) (time.Time, *errors.Error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	day, err := time.Parse(dateFormat, value)
+	if err != nil {
+		return time.Time{}, errors.BadRequest("invalid " + name + " format, expected YYYY-MM-DD or RFC3339")
+	}
+
+	if endOfDay {
+		return day.Add(24*time.Hour - time.Microsecond), nil
+	}
+	return day, nil
+}
+
+// currentTime returns the current time (can be mocked for testing).
+var currentTime = func() time.Time {
+	return time.Now().UTC()
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/vnykmshr/nivo/services/ledger/internal/service"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/metrics"
 	"github.com/vnykmshr/nivo/shared/middleware"
 )
@@ -11,15 +12,24 @@ import (
 // Router sets up HTTP routes for the Ledger Service.
 type Router struct {
 	ledgerHandler *LedgerHandler
-	jwtSecret     string
+	reportHandler *ReportHandler
+	periodHandler *PeriodHandler
+	jwtKeys       sharedjwt.KeySource
 	metrics       *metrics.Collector
 }
 
 // NewRouter creates a new router with all handlers.
-func NewRouter(ledgerService *service.LedgerService, jwtSecret string) *Router {
+func NewRouter(
+	ledgerService *service.LedgerService,
+	reportService *service.ReportService,
+	periodService *service.PeriodService,
+	jwtKeys sharedjwt.KeySource,
+) *Router {
 	return &Router{
 		ledgerHandler: NewLedgerHandler(ledgerService),
-		jwtSecret:     jwtSecret,
+		reportHandler: NewReportHandler(reportService),
+		periodHandler: NewPeriodHandler(periodService),
+		jwtKeys:       jwtKeys,
 		metrics:       metrics.NewCollector("ledger"),
 	}
 }
@@ -36,7 +46,7 @@ func (r *Router) SetupRoutes() http.Handler {
 
 	// Setup auth middleware
 	authConfig := middleware.AuthConfig{
-		JWTSecret: r.jwtSecret,
+		Keys:      r.jwtKeys,
 		SkipPaths: []string{"/health"},
 	}
 	authMiddleware := middleware.Auth(authConfig)
@@ -44,6 +54,10 @@ func (r *Router) SetupRoutes() http.Handler {
 	// Permission middleware for different operations
 	accountantPermission := middleware.RequireAnyPermission("ledger:account:create", "ledger:account:update")
 	viewLedgerPermission := middleware.RequireAnyPermission("ledger:account:read", "ledger:entry:read")
+	viewReportPermission := middleware.RequirePermission("ledger:report:read")
+	managePeriodPermission := middleware.RequirePermission("ledger:period:manage")
+	reopenPeriodPermission := middleware.RequirePermission("ledger:period:reopen")
+	viewPeriodPermission := middleware.RequireAnyPermission("ledger:period:manage", "ledger:report:read")
 
 	// Account endpoints (protected)
 	mux.Handle("POST /api/v1/accounts",
@@ -55,6 +69,9 @@ func (r *Router) SetupRoutes() http.Handler {
 	mux.Handle("GET /api/v1/accounts/{id}/balance",
 		authMiddleware(viewLedgerPermission(http.HandlerFunc(r.ledgerHandler.GetAccountBalance))))
 
+	mux.Handle("GET /api/v1/accounts/{id}/ledger",
+		authMiddleware(viewLedgerPermission(http.HandlerFunc(r.ledgerHandler.GetAccountLedger))))
+
 	mux.Handle("GET /api/v1/accounts/{id}",
 		authMiddleware(viewLedgerPermission(http.HandlerFunc(r.ledgerHandler.GetAccount))))
 
@@ -80,13 +97,52 @@ func (r *Router) SetupRoutes() http.Handler {
 	mux.Handle("POST /api/v1/journal-entries/{id}/reverse",
 		authMiddleware(middleware.RequirePermission("ledger:entry:reverse")(http.HandlerFunc(r.ledgerHandler.ReverseJournalEntry))))
 
+	// Financial report endpoints (protected)
+	mux.Handle("GET /api/v1/reports/trial-balance",
+		authMiddleware(viewReportPermission(http.HandlerFunc(r.reportHandler.GetTrialBalance))))
+
+	mux.Handle("GET /api/v1/reports/balance-sheet",
+		authMiddleware(viewReportPermission(http.HandlerFunc(r.reportHandler.GetBalanceSheet))))
+
+	mux.Handle("GET /api/v1/reports/income-statement",
+		authMiddleware(viewReportPermission(http.HandlerFunc(r.reportHandler.GetIncomeStatement))))
+
+	// Accounting period endpoints (protected)
+	mux.Handle("POST /api/v1/periods",
+		authMiddleware(managePeriodPermission(http.HandlerFunc(r.periodHandler.CreatePeriod))))
+
+	mux.Handle("GET /api/v1/periods",
+		authMiddleware(viewPeriodPermission(http.HandlerFunc(r.periodHandler.ListPeriods))))
+
+	mux.Handle("GET /api/v1/periods/{id}/balances",
+		authMiddleware(viewPeriodPermission(http.HandlerFunc(r.periodHandler.GetPeriodBalances))))
+
+	mux.Handle("GET /api/v1/periods/{id}",
+		authMiddleware(viewPeriodPermission(http.HandlerFunc(r.periodHandler.GetPeriod))))
+
+	mux.Handle("POST /api/v1/periods/{id}/close",
+		authMiddleware(managePeriodPermission(http.HandlerFunc(r.periodHandler.ClosePeriod))))
+
+	mux.Handle("POST /api/v1/periods/{id}/reopen",
+		authMiddleware(reopenPeriodPermission(http.HandlerFunc(r.periodHandler.ReopenPeriod))))
+
 	// ========================================================================
-	// Internal Endpoints (No Authentication - Service-to-Service Only)
+	// Internal Endpoints (Service-to-Service with Service Token Auth)
 	// ========================================================================
 
+	serviceAuth := middleware.NewServiceAuth(r.jwtKeys, sharedjwt.ServiceLedger)
+
 	// Internal endpoints for wallet service
-	mux.HandleFunc("POST /internal/v1/accounts", r.ledgerHandler.CreateAccountInternal)
-	mux.HandleFunc("GET /internal/v1/accounts/by-code/{code}", r.ledgerHandler.GetAccountByCode)
+	mux.HandleFunc("POST /internal/v1/accounts",
+		serviceAuth.Allow(r.ledgerHandler.CreateAccountInternal, sharedjwt.ServiceWallet))
+	mux.HandleFunc("GET /internal/v1/accounts/by-code/{code}",
+		serviceAuth.Allow(r.ledgerHandler.GetAccountByCode, sharedjwt.ServiceWallet, sharedjwt.ServiceTransaction))
+	mux.HandleFunc("POST /internal/v1/accounts/balances",
+		serviceAuth.Allow(r.ledgerHandler.GetAccountBalancesInternal, sharedjwt.ServiceWallet))
+
+	// Internal endpoints for transaction service
+	mux.HandleFunc("POST /internal/v1/journal-entries",
+		serviceAuth.Allow(r.ledgerHandler.RecordJournalEntryInternal, sharedjwt.ServiceTransaction))
 
 	// Apply middleware chain
 	handler := r.applyMiddleware(mux)
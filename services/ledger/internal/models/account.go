@@ -53,6 +53,29 @@ func (a *Account) IsCreditNormal() bool {
 	return a.Type == AccountTypeLiability || a.Type == AccountTypeEquity || a.Type == AccountTypeRevenue
 }
 
+// NormalBalance converts a net debit amount (debits minus credits) into a
+// balance on the account's normal side.
+func (a *Account) NormalBalance(netDebit int64) int64 {
+	if a.IsDebitNormal() {
+		return netDebit
+	}
+	return -netDebit
+}
+
+// AccountBalance represents an account's balance computed from posted ledger lines at a point in time.
+type AccountBalance struct {
+	AccountID   string           `json:"account_id"`
+	AsOf        models.Timestamp `json:"as_of"`
+	DebitTotal  int64            `json:"debit_total"`
+	CreditTotal int64            `json:"credit_total"`
+	Balance     int64            `json:"balance"`
+}
+
+// AccountBalancesRequest represents a batch request for current account balances.
+type AccountBalancesRequest struct {
+	AccountIDs []string `json:"account_ids" validate:"required,min:1,max:500"`
+}
+
 // CreateAccountRequest represents a request to create a new account.
 type CreateAccountRequest struct {
 	Code        string          `json:"code" validate:"required,min:1,max:20"`
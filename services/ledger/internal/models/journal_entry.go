@@ -37,6 +37,7 @@ type JournalEntry struct {
 	Description     string            `json:"description" db:"description"`
 	ReferenceType   string            `json:"reference_type,omitempty" db:"reference_type"` // e.g., "transaction", "invoice"
 	ReferenceID     string            `json:"reference_id,omitempty" db:"reference_id"`     // ID of referenced entity
+	EntryDate       models.Timestamp  `json:"entry_date" db:"entry_date"`                   // Accounting date (determines the period)
 	PostedAt        *models.Timestamp `json:"posted_at,omitempty" db:"posted_at"`
 	PostedBy        *string           `json:"posted_by,omitempty" db:"posted_by"` // User ID who posted
 	VoidedAt        *models.Timestamp `json:"voided_at,omitempty" db:"voided_at"`
@@ -118,6 +119,7 @@ type CreateJournalEntryRequest struct {
 	Description   string            `json:"description" validate:"required,min:5,max:500"`
 	ReferenceType string            `json:"reference_type,omitempty" validate:"omitempty,max:50"`
 	ReferenceID   string            `json:"reference_id,omitempty" validate:"omitempty,max:100"`
+	EntryDate     string            `json:"entry_date,omitempty" validate:"omitempty,max:35"` // YYYY-MM-DD or RFC3339; defaults to now
 	Lines         []LedgerLineInput `json:"lines" validate:"required,min:2,dive"`
 	MetadataRaw   json.RawMessage   `json:"metadata,omitempty" validate:"-"` // Raw JSON, parsed via GetMetadata()
 }
@@ -197,3 +199,34 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return e.Message
 }
+
+// AccountLedgerLine represents a posted ledger line in an account's history.
+// RunningBalance is the account balance (on its normal side) after this line.
+type AccountLedgerLine struct {
+	LineID           string           `json:"line_id"`
+	EntryID          string           `json:"entry_id"`
+	EntryNumber      string           `json:"entry_number"`
+	EntryType        EntryType        `json:"entry_type"`
+	EntryDescription string           `json:"entry_description"`
+	ReferenceType    string           `json:"reference_type,omitempty"`
+	ReferenceID      string           `json:"reference_id,omitempty"`
+	EntryDate        models.Timestamp `json:"entry_date"`
+	PostedAt         models.Timestamp `json:"posted_at"`
+	DebitAmount      int64            `json:"debit_amount"`
+	CreditAmount     int64            `json:"credit_amount"`
+	Description      string           `json:"description,omitempty"`
+	RunningBalance   int64            `json:"running_balance"`
+}
+
+// AccountLedger is a window of an account's posted history with opening and closing balances.
+type AccountLedger struct {
+	AccountID      string              `json:"account_id"`
+	Code           string              `json:"code"`
+	Name           string              `json:"name"`
+	Type           AccountType         `json:"type"`
+	From           *models.Timestamp   `json:"from,omitempty"`
+	To             models.Timestamp    `json:"to"`
+	OpeningBalance int64               `json:"opening_balance"`
+	ClosingBalance int64               `json:"closing_balance"`
+	Lines          []AccountLedgerLine `json:"lines"`
+}
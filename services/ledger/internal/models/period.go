@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// PeriodStatus represents the status of an accounting period.
+type PeriodStatus string
+
+const (
+	PeriodStatusOpen    PeriodStatus = "open"    // Entries may be created, posted and voided
+	PeriodStatusClosing PeriodStatus = "closing" // Close in progress, postings blocked
+	PeriodStatusClosed  PeriodStatus = "closed"  // Locked, balances snapshotted
+)
+
+// AccountingPeriod represents an accounting period covering [StartsAt, EndsAt).
+type AccountingPeriod struct {
+	ID           string            `json:"id" db:"id"`
+	Name         string            `json:"name" db:"name"` // e.g., "2025-03"
+	StartsAt     models.Timestamp  `json:"starts_at" db:"starts_at"`
+	EndsAt       models.Timestamp  `json:"ends_at" db:"ends_at"` // Exclusive
+	Status       PeriodStatus      `json:"status" db:"status"`
+	ClosedAt     *models.Timestamp `json:"closed_at,omitempty" db:"closed_at"`
+	ClosedBy     *string           `json:"closed_by,omitempty" db:"closed_by"`
+	ReopenedAt   *models.Timestamp `json:"reopened_at,omitempty" db:"reopened_at"`
+	ReopenedBy   *string           `json:"reopened_by,omitempty" db:"reopened_by"`
+	ReopenReason *string           `json:"reopen_reason,omitempty" db:"reopen_reason"`
+	CreatedAt    models.Timestamp  `json:"created_at" db:"created_at"`
+	UpdatedAt    models.Timestamp  `json:"updated_at" db:"updated_at"`
+}
+
+// IsOpen returns true if entries may be recorded in this period.
+func (p *AccountingPeriod) IsOpen() bool {
+	return p.Status == PeriodStatusOpen
+}
+
+// Contains returns true if t falls within the period.
+func (p *AccountingPeriod) Contains(t time.Time) bool {
+	return !t.Before(p.StartsAt.Time) && t.Before(p.EndsAt.Time)
+}
+
+// PeriodBalance is an account's balance captured when a period was closed.
+type PeriodBalance struct {
+	PeriodID      string           `json:"period_id" db:"period_id"`
+	AccountID     string           `json:"account_id" db:"account_id"`
+	AccountCode   string           `json:"account_code" db:"-"`
+	AccountName   string           `json:"account_name" db:"-"`
+	AccountType   AccountType      `json:"account_type" db:"-"`
+	PeriodDebits  int64            `json:"period_debits" db:"period_debits"`   // Debits dated within the period
+	PeriodCredits int64            `json:"period_credits" db:"period_credits"` // Credits dated within the period
+	DebitTotal    int64            `json:"debit_total" db:"debit_total"`       // Cumulative debits at period end
+	CreditTotal   int64            `json:"credit_total" db:"credit_total"`     // Cumulative credits at period end
+	Balance       int64            `json:"balance" db:"balance"`               // Closing balance on the normal side
+	CreatedAt     models.Timestamp `json:"created_at" db:"created_at"`
+}
+
+// CreatePeriodRequest represents a request to create an accounting period.
+// Dates are inclusive calendar days in UTC (YYYY-MM-DD).
+type CreatePeriodRequest struct {
+	Name      string `json:"name" validate:"required,min:1,max:50"`
+	StartDate string `json:"start_date" validate:"required,len:10"`
+	EndDate   string `json:"end_date" validate:"required,len:10"`
+}
+
+// ReopenPeriodRequest represents a request to reopen a closed accounting period.
+type ReopenPeriodRequest struct {
+	Reason string `json:"reason" validate:"required,min:10,max:500"`
+}
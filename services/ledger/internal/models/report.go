@@ -0,0 +1,71 @@
+package models
+
+import (
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// TrialBalanceLine represents a single account row in a trial balance.
+type TrialBalanceLine struct {
+	AccountID     string      `json:"account_id"`
+	Code          string      `json:"code"`
+	Name          string      `json:"name"`
+	Type          AccountType `json:"type"`
+	ParentID      *string     `json:"parent_id,omitempty"`
+	DebitTotal    int64       `json:"debit_total"`    // Sum of posted debits up to the report date
+	CreditTotal   int64       `json:"credit_total"`   // Sum of posted credits up to the report date
+	DebitBalance  int64       `json:"debit_balance"`  // Net balance when it falls on the debit side (paise)
+	CreditBalance int64       `json:"credit_balance"` // Net balance when it falls on the credit side (paise)
+}
+
+// TrialBalance lists every account with activity and proves that total debits equal total credits.
+type TrialBalance struct {
+	AsOf                models.Timestamp   `json:"as_of"`
+	Lines               []TrialBalanceLine `json:"lines"`
+	TotalDebits         int64              `json:"total_debits"`
+	TotalCredits        int64              `json:"total_credits"`
+	TotalDebitBalances  int64              `json:"total_debit_balances"`
+	TotalCreditBalances int64              `json:"total_credit_balances"`
+	Balanced            bool               `json:"balanced"`
+}
+
+// ReportLine represents an account in a hierarchical financial report.
+// Subtotal includes the account's own balance plus all of its descendants.
+type ReportLine struct {
+	AccountID string        `json:"account_id"`
+	Code      string        `json:"code"`
+	Name      string        `json:"name"`
+	Type      AccountType   `json:"type"`
+	ParentID  *string       `json:"parent_id,omitempty"`
+	Balance   int64         `json:"balance"`
+	Subtotal  int64         `json:"subtotal"`
+	Children  []*ReportLine `json:"children,omitempty"`
+}
+
+// ReportSection groups the accounts of one account type in a financial report.
+type ReportSection struct {
+	Type     AccountType   `json:"type"`
+	Accounts []*ReportLine `json:"accounts"`
+	Total    int64         `json:"total"`
+}
+
+// BalanceSheet reports assets, liabilities and equity as of a given date.
+// CurrentEarnings is revenue minus expenses that have not yet been closed to equity.
+type BalanceSheet struct {
+	AsOf                      models.Timestamp `json:"as_of"`
+	Assets                    ReportSection    `json:"assets"`
+	Liabilities               ReportSection    `json:"liabilities"`
+	Equity                    ReportSection    `json:"equity"`
+	CurrentEarnings           int64            `json:"current_earnings"`
+	TotalAssets               int64            `json:"total_assets"`
+	TotalLiabilitiesAndEquity int64            `json:"total_liabilities_and_equity"`
+	Balanced                  bool             `json:"balanced"`
+}
+
+// IncomeStatement reports revenue and expenses over a period.
+type IncomeStatement struct {
+	From      models.Timestamp `json:"from"`
+	To        models.Timestamp `json:"to"`
+	Revenue   ReportSection    `json:"revenue"`
+	Expenses  ReportSection    `json:"expenses"`
+	NetIncome int64            `json:"net_income"`
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 
+	"github.com/lib/pq"
 	"github.com/vnykmshr/nivo/services/ledger/internal/models"
 	"github.com/vnykmshr/nivo/shared/database"
 	"github.com/vnykmshr/nivo/shared/errors"
@@ -257,6 +258,38 @@ func (r *AccountRepository) Update(ctx context.Context, account *models.Account)
 	return nil
 }
 
+// ListBalances retrieves the current balance and totals of each of the given accounts.
+// Unknown IDs are skipped.
+func (r *AccountRepository) ListBalances(ctx context.Context, accountIDs []string) ([]*models.AccountBalance, *errors.Error) {
+	query := `
+		SELECT id, debit_total, credit_total, balance, NOW()
+		FROM accounts
+		WHERE id = ANY($1::uuid[])
+		ORDER BY code
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(accountIDs))
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to list account balances")
+	}
+	defer func() { _ = rows.Close() }()
+
+	balances := make([]*models.AccountBalance, 0, len(accountIDs))
+	for rows.Next() {
+		balance := &models.AccountBalance{}
+		if err := rows.Scan(&balance.AccountID, &balance.DebitTotal, &balance.CreditTotal, &balance.Balance, &balance.AsOf); err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan account balance")
+		}
+		balances = append(balances, balance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating account balances")
+	}
+
+	return balances, nil
+}
+
 // GetBalance retrieves the current balance of an account.
 func (r *AccountRepository) GetBalance(ctx context.Context, accountID string) (int64, *errors.Error) {
 	var balance int64
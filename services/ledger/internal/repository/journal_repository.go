@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/vnykmshr/nivo/services/ledger/internal/models"
 	"github.com/vnykmshr/nivo/shared/database"
@@ -41,9 +42,9 @@ func (r *JournalEntryRepository) Create(ctx context.Context, entry *models.Journ
 		// Insert journal entry
 		query := `
 			INSERT INTO journal_entries (entry_number, type, status, description,
-			                              reference_type, reference_id, metadata)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-			RETURNING id, created_at, updated_at
+			                              reference_type, reference_id, entry_date, metadata)
+			VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW()), $8)
+			RETURNING id, entry_date, created_at, updated_at
 		`
 
 		err = tx.QueryRowContext(ctx, query,
@@ -53,10 +54,14 @@ func (r *JournalEntryRepository) Create(ctx context.Context, entry *models.Journ
 			entry.Description,
 			entry.ReferenceType,
 			entry.ReferenceID,
+			entry.EntryDate,
 			metadataJSON,
-		).Scan(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
+		).Scan(&entry.ID, &entry.EntryDate, &entry.CreatedAt, &entry.UpdatedAt)
 
 		if err != nil {
+			if database.IsUniqueViolation(err) {
+				return errors.Conflict("journal entry already recorded for this reference")
+			}
 			return errors.DatabaseWrap(err, "failed to create journal entry")
 		}
 
@@ -118,7 +123,7 @@ func (r *JournalEntryRepository) GetByID(ctx context.Context, id string) (*model
 
 	query := `
 		SELECT id, entry_number, type, status, description, reference_type, reference_id,
-		       posted_at, posted_by, voided_at, voided_by, void_reason, reversal_entry_id,
+		       entry_date, posted_at, posted_by, voided_at, voided_by, void_reason, reversal_entry_id,
 		       metadata, created_at, updated_at
 		FROM journal_entries
 		WHERE id = $1
@@ -132,6 +137,7 @@ func (r *JournalEntryRepository) GetByID(ctx context.Context, id string) (*model
 		&entry.Description,
 		&entry.ReferenceType,
 		&entry.ReferenceID,
+		&entry.EntryDate,
 		&entry.PostedAt,
 		&entry.PostedBy,
 		&entry.VoidedAt,
@@ -167,6 +173,29 @@ func (r *JournalEntryRepository) GetByID(ctx context.Context, id string) (*model
 	return entry, nil
 }
 
+// GetByReference retrieves the draft or posted journal entry recorded for a reference.
+// Returns nil without an error when there is none.
+func (r *JournalEntryRepository) GetByReference(ctx context.Context, referenceType, referenceID string) (*models.JournalEntry, *errors.Error) {
+	query := `
+		SELECT id
+		FROM journal_entries
+		WHERE reference_type = $1 AND reference_id = $2 AND status IN ('draft', 'posted')
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query, referenceType, referenceID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get journal entry by reference")
+	}
+
+	return r.GetByID(ctx, id)
+}
+
 // GetLinesByEntryID retrieves all lines for a journal entry.
 func (r *JournalEntryRepository) GetLinesByEntryID(ctx context.Context, entryID string) ([]models.LedgerLine, *errors.Error) {
 	query := `
@@ -219,12 +248,18 @@ func (r *JournalEntryRepository) GetLinesByEntryID(ctx context.Context, entryID
 }
 
 // Post posts a draft journal entry.
+// Entries dated within a closing or closed accounting period are refused.
 func (r *JournalEntryRepository) Post(ctx context.Context, entryID, postedBy string) *errors.Error {
 	query := `
-		UPDATE journal_entries
+		UPDATE journal_entries je
 		SET status = 'posted', posted_at = NOW(), posted_by = $2, updated_at = NOW()
-		WHERE id = $1 AND status = 'draft'
-		RETURNING entry_number
+		WHERE je.id = $1 AND je.status = 'draft'
+		  AND NOT EXISTS (
+			SELECT 1 FROM accounting_periods p
+			WHERE p.status IN ('closing', 'closed')
+			  AND je.entry_date >= p.starts_at AND je.entry_date < p.ends_at
+		  )
+		RETURNING je.entry_number
 	`
 
 	var entryNumber string
@@ -232,7 +267,7 @@ func (r *JournalEntryRepository) Post(ctx context.Context, entryID, postedBy str
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return errors.BadRequest("journal entry not found or already posted")
+			return errors.BadRequest("journal entry not found, already posted, or dated in a closed period")
 		}
 		// Check if error is from validation trigger
 		if database.IsCheckViolation(err) {
@@ -271,7 +306,7 @@ func (r *JournalEntryRepository) Void(ctx context.Context, entryID, voidedBy, vo
 func (r *JournalEntryRepository) List(ctx context.Context, status *models.EntryStatus, limit, offset int) ([]*models.JournalEntry, *errors.Error) {
 	query := `
 		SELECT id, entry_number, type, status, description, reference_type, reference_id,
-		       posted_at, posted_by, voided_at, voided_by, void_reason, reversal_entry_id,
+		       entry_date, posted_at, posted_by, voided_at, voided_by, void_reason, reversal_entry_id,
 		       metadata, created_at, updated_at
 		FROM journal_entries
 		WHERE 1=1
@@ -308,6 +343,7 @@ func (r *JournalEntryRepository) List(ctx context.Context, status *models.EntryS
 			&entry.Description,
 			&entry.ReferenceType,
 			&entry.ReferenceID,
+			&entry.EntryDate,
 			&entry.PostedAt,
 			&entry.PostedBy,
 			&entry.VoidedAt,
@@ -341,3 +377,112 @@ func (r *JournalEntryRepository) List(ctx context.Context, status *models.EntryS
 
 	return entries, nil
 }
+
+// SumAccountActivity returns the total posted debits and credits for an account
+// from entries dated at or before asOf.
+func (r *JournalEntryRepository) SumAccountActivity(ctx context.Context, accountID string, asOf time.Time) (int64, int64, *errors.Error) {
+	query := `
+		SELECT COALESCE(SUM(ll.debit_amount), 0), COALESCE(SUM(ll.credit_amount), 0)
+		FROM ledger_lines ll
+		JOIN journal_entries je ON je.id = ll.entry_id
+		WHERE ll.account_id = $1
+		  AND je.status = 'posted'
+		  AND je.entry_date <= $2
+	`
+
+	var debits, credits int64
+	if err := r.db.QueryRowContext(ctx, query, accountID, asOf).Scan(&debits, &credits); err != nil {
+		return 0, 0, errors.DatabaseWrap(err, "failed to sum account activity")
+	}
+
+	return debits, credits, nil
+}
+
+// ListAccountLines retrieves posted ledger lines for an account with entries dated
+// within [from, to], oldest first, along with the total number of matching lines.
+// RunningBalance is returned as the cumulative net debit (debits minus credits) since
+// the account's first posting; callers convert it to the account's normal side.
+func (r *JournalEntryRepository) ListAccountLines(ctx context.Context, accountID string, from *time.Time, to time.Time, limit, offset int) ([]models.AccountLedgerLine, int64, *errors.Error) {
+	query := `
+		WITH history AS (
+			SELECT ll.id, ll.entry_id, je.entry_number, je.type, je.description AS entry_description,
+			       COALESCE(je.reference_type, '') AS reference_type,
+			       COALESCE(je.reference_id, '') AS reference_id,
+			       je.entry_date, je.posted_at, ll.debit_amount, ll.credit_amount,
+			       COALESCE(ll.description, '') AS description,
+			       SUM(ll.debit_amount - ll.credit_amount) OVER (
+			           ORDER BY je.entry_date, je.entry_number, ll.created_at, ll.id
+			           ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+			       ) AS running_net,
+			       ll.created_at
+			FROM ledger_lines ll
+			JOIN journal_entries je ON je.id = ll.entry_id
+			WHERE ll.account_id = $1
+			  AND je.status = 'posted'
+			  AND je.entry_date <= $3
+		)
+		SELECT id, entry_id, entry_number, type, entry_description, reference_type, reference_id,
+		       entry_date, posted_at, debit_amount, credit_amount, description, running_net,
+		       COUNT(*) OVER () AS total_count
+		FROM history
+		WHERE $2::timestamptz IS NULL OR entry_date >= $2
+		ORDER BY entry_date, entry_number, created_at, id
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, accountID, from, to, limit, offset)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list account ledger lines")
+	}
+	defer func() { _ = rows.Close() }()
+
+	var total int64
+	lines := make([]models.AccountLedgerLine, 0)
+	for rows.Next() {
+		line := models.AccountLedgerLine{}
+
+		err := rows.Scan(
+			&line.LineID,
+			&line.EntryID,
+			&line.EntryNumber,
+			&line.EntryType,
+			&line.EntryDescription,
+			&line.ReferenceType,
+			&line.ReferenceID,
+			&line.EntryDate,
+			&line.PostedAt,
+			&line.DebitAmount,
+			&line.CreditAmount,
+			&line.Description,
+			&line.RunningBalance,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan account ledger line")
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "error iterating account ledger lines")
+	}
+
+	// COUNT(*) OVER () is only available when the page has rows
+	if len(lines) == 0 && offset > 0 {
+		countQuery := `
+			SELECT COUNT(*)
+			FROM ledger_lines ll
+			JOIN journal_entries je ON je.id = ll.entry_id
+			WHERE ll.account_id = $1
+			  AND je.status = 'posted'
+			  AND ($2::timestamptz IS NULL OR je.entry_date >= $2)
+			  AND je.entry_date <= $3
+		`
+		if err := r.db.QueryRowContext(ctx, countQuery, accountID, from, to).Scan(&total); err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to count account ledger lines")
+		}
+	}
+
+	return lines, total, nil
+}
@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/ledger/internal/models"
+	"github.com/vnykmshr/nivo/shared/database"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// PeriodRepository handles database operations for accounting periods.
+type PeriodRepository struct {
+	db *database.DB
+}
+
+// NewPeriodRepository creates a new accounting period repository.
+func NewPeriodRepository(db *database.DB) *PeriodRepository {
+	return &PeriodRepository{db: db}
+}
+
+const periodColumns = `id, name, starts_at, ends_at, status, closed_at, closed_by,
+	       reopened_at, reopened_by, reopen_reason, created_at, updated_at`
+
+// Create creates a new accounting period.
+func (r *PeriodRepository) Create(ctx context.Context, period *models.AccountingPeriod) *errors.Error {
+	query := `
+		INSERT INTO accounting_periods (name, starts_at, ends_at, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		period.Name,
+		period.StartsAt,
+		period.EndsAt,
+		period.Status,
+	).Scan(&period.ID, &period.CreatedAt, &period.UpdatedAt)
+
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return errors.Conflict("accounting period with this name already exists")
+		}
+		return errors.DatabaseWrap(err, "failed to create accounting period")
+	}
+
+	return nil
+}
+
+// GetByID retrieves an accounting period by ID.
+func (r *PeriodRepository) GetByID(ctx context.Context, id string) (*models.AccountingPeriod, *errors.Error) {
+	query := `SELECT ` + periodColumns + ` FROM accounting_periods WHERE id = $1`
+
+	period, err := scanPeriod(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundWithID("accounting period", id)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get accounting period")
+	}
+
+	return period, nil
+}
+
+// GetForDate retrieves the accounting period covering t.
+// Returns nil without an error when no period covers the date.
+func (r *PeriodRepository) GetForDate(ctx context.Context, t time.Time) (*models.AccountingPeriod, *errors.Error) {
+	query := `
+		SELECT ` + periodColumns + `
+		FROM accounting_periods
+		WHERE starts_at <= $1 AND ends_at > $1
+		ORDER BY starts_at DESC
+		LIMIT 1
+	`
+
+	period, err := scanPeriod(r.db.QueryRowContext(ctx, query, t))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get accounting period for date")
+	}
+
+	return period, nil
+}
+
+// HasOverlap reports whether any existing period intersects [startsAt, endsAt).
+func (r *PeriodRepository) HasOverlap(ctx context.Context, startsAt, endsAt time.Time) (bool, *errors.Error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM accounting_periods
+			WHERE starts_at < $2 AND ends_at > $1
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, startsAt, endsAt).Scan(&exists); err != nil {
+		return false, errors.DatabaseWrap(err, "failed to check accounting period overlap")
+	}
+
+	return exists, nil
+}
+
+// List retrieves accounting periods, most recent first.
+func (r *PeriodRepository) List(ctx context.Context, status *models.PeriodStatus, limit, offset int) ([]*models.AccountingPeriod, *errors.Error) {
+	query := `SELECT ` + periodColumns + ` FROM accounting_periods WHERE 1=1`
+
+	args := []interface{}{}
+	argPos := 1
+
+	if status != nil {
+		query += fmt.Sprintf(" AND status = $%d", argPos)
+		args = append(args, *status)
+		argPos++
+	}
+
+	query += fmt.Sprintf(" ORDER BY starts_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to list accounting periods")
+	}
+	defer func() { _ = rows.Close() }()
+
+	periods := make([]*models.AccountingPeriod, 0)
+	for rows.Next() {
+		period, err := scanPeriod(rows)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan accounting period")
+		}
+		periods = append(periods, period)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating accounting periods")
+	}
+
+	return periods, nil
+}
+
+// MarkClosing moves an open period to closing, which blocks new postings into it.
+func (r *PeriodRepository) MarkClosing(ctx context.Context, id string) *errors.Error {
+	query := `
+		UPDATE accounting_periods
+		SET status = 'closing', updated_at = NOW()
+		WHERE id = $1 AND status IN ('open', 'closing')
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to mark accounting period as closing")
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.BadRequest("accounting period not found or already closed")
+	}
+
+	return nil
+}
+
+// Close snapshots every account balance at the end of the period and marks
+// the period closed, in a single transaction.
+func (r *PeriodRepository) Close(ctx context.Context, id, closedBy string) *errors.Error {
+	err := r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		// Replace any snapshot left behind by an earlier close
+		if _, err := tx.ExecContext(ctx, `DELETE FROM period_balances WHERE period_id = $1`, id); err != nil {
+			return errors.DatabaseWrap(err, "failed to clear period balances")
+		}
+
+		snapshotQuery := `
+			INSERT INTO period_balances (period_id, account_id, period_debits, period_credits,
+			                             debit_total, credit_total, balance)
+			SELECT p.id, a.id,
+			       COALESCE(SUM(l.debit_amount) FILTER (WHERE l.entry_date >= p.starts_at), 0),
+			       COALESCE(SUM(l.credit_amount) FILTER (WHERE l.entry_date >= p.starts_at), 0),
+			       COALESCE(SUM(l.debit_amount), 0),
+			       COALESCE(SUM(l.credit_amount), 0),
+			       CASE WHEN a.type IN ('asset', 'expense')
+			            THEN COALESCE(SUM(l.debit_amount), 0) - COALESCE(SUM(l.credit_amount), 0)
+			            ELSE COALESCE(SUM(l.credit_amount), 0) - COALESCE(SUM(l.debit_amount), 0)
+			       END
+			FROM accounting_periods p
+			CROSS JOIN accounts a
+			LEFT JOIN (
+				SELECT ll.account_id, ll.debit_amount, ll.credit_amount, je.entry_date
+				FROM ledger_lines ll
+				JOIN journal_entries je ON je.id = ll.entry_id
+				WHERE je.status = 'posted'
+			) l ON l.account_id = a.id AND l.entry_date < p.ends_at
+			WHERE p.id = $1
+			GROUP BY p.id, a.id
+		`
+		if _, err := tx.ExecContext(ctx, snapshotQuery, id); err != nil {
+			return errors.DatabaseWrap(err, "failed to snapshot period balances")
+		}
+
+		closeQuery := `
+			UPDATE accounting_periods
+			SET status = 'closed', closed_at = NOW(), closed_by = $2, updated_at = NOW()
+			WHERE id = $1 AND status = 'closing'
+		`
+		result, err := tx.ExecContext(ctx, closeQuery, id, closedBy)
+		if err != nil {
+			return errors.DatabaseWrap(err, "failed to close accounting period")
+		}
+
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.BadRequest("accounting period is not closing")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if e, ok := err.(*errors.Error); ok {
+			return e
+		}
+		return errors.DatabaseWrap(err, "failed to close accounting period")
+	}
+
+	return nil
+}
+
+// Reopen moves a closed period back to open, discarding its balance snapshot
+// and recording who reopened it and why.
+func (r *PeriodRepository) Reopen(ctx context.Context, id, reopenedBy, reason string) *errors.Error {
+	err := r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		query := `
+			UPDATE accounting_periods
+			SET status = 'open', reopened_at = NOW(), reopened_by = $2,
+			    reopen_reason = $3, updated_at = NOW()
+			WHERE id = $1 AND status = 'closed'
+		`
+		result, err := tx.ExecContext(ctx, query, id, reopenedBy, reason)
+		if err != nil {
+			return errors.DatabaseWrap(err, "failed to reopen accounting period")
+		}
+
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.BadRequest("accounting period not found or not closed")
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM period_balances WHERE period_id = $1`, id); err != nil {
+			return errors.DatabaseWrap(err, "failed to clear period balances")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if e, ok := err.(*errors.Error); ok {
+			return e
+		}
+		return errors.DatabaseWrap(err, "failed to reopen accounting period")
+	}
+
+	return nil
+}
+
+// ListBalances retrieves the balance snapshot taken when a period was closed.
+func (r *PeriodRepository) ListBalances(ctx context.Context, periodID string) ([]*models.PeriodBalance, *errors.Error) {
+	query := `
+		SELECT pb.period_id, pb.account_id, a.code, a.name, a.type,
+		       pb.period_debits, pb.period_credits, pb.debit_total, pb.credit_total,
+		       pb.balance, pb.created_at
+		FROM period_balances pb
+		JOIN accounts a ON a.id = pb.account_id
+		WHERE pb.period_id = $1
+		ORDER BY a.code
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, periodID)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to list period balances")
+	}
+	defer func() { _ = rows.Close() }()
+
+	balances := make([]*models.PeriodBalance, 0)
+	for rows.Next() {
+		balance := &models.PeriodBalance{}
+
+		err := rows.Scan(
+			&balance.PeriodID,
+			&balance.AccountID,
+			&balance.AccountCode,
+			&balance.AccountName,
+			&balance.AccountType,
+			&balance.PeriodDebits,
+			&balance.PeriodCredits,
+			&balance.DebitTotal,
+			&balance.CreditTotal,
+			&balance.Balance,
+			&balance.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan period balance")
+		}
+
+		balances = append(balances, balance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating period balances")
+	}
+
+	return balances, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPeriod(row rowScanner) (*models.AccountingPeriod, error) {
+	period := &models.AccountingPeriod{}
+
+	err := row.Scan(
+		&period.ID,
+		&period.Name,
+		&period.StartsAt,
+		&period.EndsAt,
+		&period.Status,
+		&period.ClosedAt,
+		&period.ClosedBy,
+		&period.ReopenedAt,
+		&period.ReopenedBy,
+		&period.ReopenReason,
+		&period.CreatedAt,
+		&period.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return period, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/ledger/internal/models"
+	"github.com/vnykmshr/nivo/shared/database"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// ReportRepository handles aggregate queries used by financial reports.
+type ReportRepository struct {
+	db *database.DB
+}
+
+// NewReportRepository creates a new report repository.
+func NewReportRepository(db *database.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// ListAccountTotals returns every account with debit, credit and balance totals
+// computed from posted ledger lines whose entry is dated within [from, to].
+// A nil from means "since the beginning". Balances follow each account's normal side.
+func (r *ReportRepository) ListAccountTotals(ctx context.Context, from *time.Time, to time.Time) ([]*models.Account, *errors.Error) {
+	query := `
+		SELECT a.id, a.code, a.name, a.type, a.currency, a.parent_id, a.status,
+		       a.created_at, a.updated_at,
+		       COALESCE(SUM(l.debit_amount), 0) AS debit_total,
+		       COALESCE(SUM(l.credit_amount), 0) AS credit_total
+		FROM accounts a
+		LEFT JOIN (
+			SELECT ll.account_id, ll.debit_amount, ll.credit_amount
+			FROM ledger_lines ll
+			JOIN journal_entries je ON je.id = ll.entry_id
+			WHERE je.status = 'posted'
+			  AND ($1::timestamptz IS NULL OR je.entry_date >= $1)
+			  AND je.entry_date <= $2
+		) l ON l.account_id = a.id
+		GROUP BY a.id
+		ORDER BY a.code
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to aggregate account totals")
+	}
+	defer func() { _ = rows.Close() }()
+
+	accounts := make([]*models.Account, 0)
+	for rows.Next() {
+		account := &models.Account{}
+
+		err := rows.Scan(
+			&account.ID,
+			&account.Code,
+			&account.Name,
+			&account.Type,
+			&account.Currency,
+			&account.ParentID,
+			&account.Status,
+			&account.CreatedAt,
+			&account.UpdatedAt,
+			&account.DebitTotal,
+			&account.CreditTotal,
+		)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan account totals")
+		}
+
+		account.Balance = account.NormalBalance(account.DebitTotal - account.CreditTotal)
+
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating account totals")
+	}
+
+	return accounts, nil
+}
@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/vnykmshr/nivo/services/ledger/internal/models"
 	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
 )
 
 // AccountRepositoryInterface defines the interface for account repository operations.
@@ -16,31 +19,38 @@ type AccountRepositoryInterface interface {
 	List(ctx context.Context, accountType *models.AccountType, status *models.AccountStatus, limit, offset int) ([]*models.Account, *errors.Error)
 	Update(ctx context.Context, account *models.Account) *errors.Error
 	GetBalance(ctx context.Context, accountID string) (int64, *errors.Error)
+	ListBalances(ctx context.Context, accountIDs []string) ([]*models.AccountBalance, *errors.Error)
 }
 
 // JournalEntryRepositoryInterface defines the interface for journal entry repository operations.
 type JournalEntryRepositoryInterface interface {
 	Create(ctx context.Context, entry *models.JournalEntry, lines []models.LedgerLine) *errors.Error
 	GetByID(ctx context.Context, id string) (*models.JournalEntry, *errors.Error)
+	GetByReference(ctx context.Context, referenceType, referenceID string) (*models.JournalEntry, *errors.Error)
 	List(ctx context.Context, status *models.EntryStatus, limit, offset int) ([]*models.JournalEntry, *errors.Error)
 	Post(ctx context.Context, entryID, postedBy string) *errors.Error
 	Void(ctx context.Context, entryID, voidedBy, voidReason string) *errors.Error
+	SumAccountActivity(ctx context.Context, accountID string, asOf time.Time) (int64, int64, *errors.Error)
+	ListAccountLines(ctx context.Context, accountID string, from *time.Time, to time.Time, limit, offset int) ([]models.AccountLedgerLine, int64, *errors.Error)
 }
 
 // LedgerService handles business logic for ledger operations.
 type LedgerService struct {
 	accountRepo AccountRepositoryInterface
 	journalRepo JournalEntryRepositoryInterface
+	periodRepo  PeriodRepositoryInterface
 }
 
 // NewLedgerService creates a new ledger service.
 func NewLedgerService(
 	accountRepo AccountRepositoryInterface,
 	journalRepo JournalEntryRepositoryInterface,
+	periodRepo PeriodRepositoryInterface,
 ) *LedgerService {
 	return &LedgerService{
 		accountRepo: accountRepo,
 		journalRepo: journalRepo,
+		periodRepo:  periodRepo,
 	}
 }
 
@@ -127,6 +137,15 @@ func (s *LedgerService) UpdateAccount(ctx context.Context, accountID string, req
 // CreateJournalEntry creates a new journal entry.
 // This validates the entry follows double-entry bookkeeping rules.
 func (s *LedgerService) CreateJournalEntry(ctx context.Context, req *models.CreateJournalEntryRequest) (*models.JournalEntry, *errors.Error) {
+	// Resolve the accounting date and make sure its period accepts entries
+	entryDate, dateErr := parseEntryDate(req.EntryDate)
+	if dateErr != nil {
+		return nil, dateErr
+	}
+	if periodErr := s.ensurePeriodOpen(ctx, entryDate); periodErr != nil {
+		return nil, periodErr
+	}
+
 	// Validate lines
 	if len(req.Lines) < 2 {
 		return nil, errors.Validation("journal entry must have at least 2 lines")
@@ -174,6 +193,7 @@ func (s *LedgerService) CreateJournalEntry(ctx context.Context, req *models.Crea
 		Description:   req.Description,
 		ReferenceType: req.ReferenceType,
 		ReferenceID:   req.ReferenceID,
+		EntryDate:     sharedModels.NewTimestamp(entryDate),
 		Metadata:      entryMetadata,
 	}
 
@@ -203,6 +223,46 @@ func (s *LedgerService) CreateJournalEntry(ctx context.Context, req *models.Crea
 	return entry, nil
 }
 
+// systemActorID is recorded as the poster of entries recorded by other services.
+const systemActorID = "00000000-0000-0000-0000-000000000000"
+
+// RecordJournalEntry creates and posts a journal entry on behalf of another service.
+// It is idempotent on the entry reference: a repeated request returns the entry
+// already recorded for the reference, posting it first if it was left in draft.
+func (s *LedgerService) RecordJournalEntry(ctx context.Context, req *models.CreateJournalEntryRequest) (*models.JournalEntry, *errors.Error) {
+	if req.ReferenceType == "" || req.ReferenceID == "" {
+		return nil, errors.Validation("reference_type and reference_id are required")
+	}
+
+	entry, err := s.journalRepo.GetByReference(ctx, req.ReferenceType, req.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == nil {
+		entry, err = s.CreateJournalEntry(ctx, req)
+		if err != nil {
+			if err.Code != errors.ErrCodeConflict {
+				return nil, err
+			}
+			// Lost a race with a concurrent delivery of the same entry
+			entry, err = s.journalRepo.GetByReference(ctx, req.ReferenceType, req.ReferenceID)
+			if err != nil {
+				return nil, err
+			}
+			if entry == nil {
+				return nil, errors.Conflict("journal entry already recorded for this reference")
+			}
+		}
+	}
+
+	if entry.Status == models.EntryStatusPosted {
+		return entry, nil
+	}
+
+	return s.PostJournalEntry(ctx, entry.ID, systemActorID)
+}
+
 // GetJournalEntry retrieves a journal entry with its lines.
 func (s *LedgerService) GetJournalEntry(ctx context.Context, entryID string) (*models.JournalEntry, *errors.Error) {
 	return s.journalRepo.GetByID(ctx, entryID)
@@ -232,6 +292,10 @@ func (s *LedgerService) PostJournalEntry(ctx context.Context, entryID, postedBy
 		return nil, errors.Validation("entry is not balanced")
 	}
 
+	if periodErr := s.ensurePeriodOpen(ctx, entry.EntryDate.Time); periodErr != nil {
+		return nil, periodErr
+	}
+
 	// Post entry (repository handles balance updates via trigger)
 	if postErr := s.journalRepo.Post(ctx, entryID, postedBy); postErr != nil {
 		return nil, postErr
@@ -255,6 +319,10 @@ func (s *LedgerService) VoidJournalEntry(ctx context.Context, entryID, voidedBy,
 		return nil, errors.BadRequest("only posted entries can be voided")
 	}
 
+	if periodErr := s.ensurePeriodOpen(ctx, entry.EntryDate.Time); periodErr != nil {
+		return nil, periodErr
+	}
+
 	// Void entry
 	if voidErr := s.journalRepo.Void(ctx, entryID, voidedBy, voidReason); voidErr != nil {
 		return nil, voidErr
@@ -265,7 +333,8 @@ func (s *LedgerService) VoidJournalEntry(ctx context.Context, entryID, voidedBy,
 }
 
 // ReverseJournalEntry creates a reversing entry for a posted journal entry.
-// This creates a new entry with opposite debit/credit amounts.
+// This creates a new entry with opposite debit/credit amounts, dated now so that
+// reversals of entries in a closed period land in the current open period.
 func (s *LedgerService) ReverseJournalEntry(ctx context.Context, entryID, reversedBy, reason string) (*models.JournalEntry, *errors.Error) {
 	// Get original entry
 	originalEntry, err := s.journalRepo.GetByID(ctx, entryID)
@@ -319,3 +388,128 @@ func (s *LedgerService) ReverseJournalEntry(ctx context.Context, entryID, revers
 func (s *LedgerService) GetAccountBalance(ctx context.Context, accountID string) (int64, *errors.Error) {
 	return s.accountRepo.GetBalance(ctx, accountID)
 }
+
+// GetAccountBalances retrieves the current balances of several accounts at once.
+// Used by other services to reconcile their records against the ledger.
+func (s *LedgerService) GetAccountBalances(ctx context.Context, accountIDs []string) ([]*models.AccountBalance, *errors.Error) {
+	for _, id := range accountIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, errors.Validation(fmt.Sprintf("invalid account ID: %s", id))
+		}
+	}
+
+	return s.accountRepo.ListBalances(ctx, accountIDs)
+}
+
+// GetAccountBalanceAsOf computes an account's balance from posted ledger lines
+// at a point in time, independent of the running totals kept on the account.
+func (s *LedgerService) GetAccountBalanceAsOf(ctx context.Context, accountID string, asOf time.Time) (*models.AccountBalance, *errors.Error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	debits, credits, sumErr := s.journalRepo.SumAccountActivity(ctx, accountID, asOf)
+	if sumErr != nil {
+		return nil, sumErr
+	}
+
+	return &models.AccountBalance{
+		AccountID:   account.ID,
+		AsOf:        sharedModels.NewTimestamp(asOf),
+		DebitTotal:  debits,
+		CreditTotal: credits,
+		Balance:     account.NormalBalance(debits - credits),
+	}, nil
+}
+
+// GetAccountLedger retrieves a page of an account's posted ledger lines within
+// [from, to] with a running balance after each line. A nil from starts at the
+// account's first posting. Returns the ledger and the total number of lines in the window.
+func (s *LedgerService) GetAccountLedger(ctx context.Context, accountID string, from *time.Time, to time.Time, limit, offset int) (*models.AccountLedger, int64, *errors.Error) {
+	if from != nil && from.After(to) {
+		return nil, 0, errors.BadRequest("from cannot be after to")
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lines, total, listErr := s.journalRepo.ListAccountLines(ctx, accountID, from, to, limit, offset)
+	if listErr != nil {
+		return nil, 0, listErr
+	}
+
+	// Convert cumulative net debits into balances on the account's normal side
+	for i := range lines {
+		lines[i].RunningBalance = account.NormalBalance(lines[i].RunningBalance)
+	}
+
+	ledger := &models.AccountLedger{
+		AccountID: account.ID,
+		Code:      account.Code,
+		Name:      account.Name,
+		Type:      account.Type,
+		To:        sharedModels.NewTimestamp(to),
+		Lines:     lines,
+	}
+
+	if from != nil {
+		fromTS := sharedModels.NewTimestamp(*from)
+		ledger.From = &fromTS
+
+		// Opening balance covers everything posted strictly before the window
+		debits, credits, sumErr := s.journalRepo.SumAccountActivity(ctx, accountID, from.Add(-time.Microsecond))
+		if sumErr != nil {
+			return nil, 0, sumErr
+		}
+		ledger.OpeningBalance = account.NormalBalance(debits - credits)
+	}
+
+	debits, credits, sumErr := s.journalRepo.SumAccountActivity(ctx, accountID, to)
+	if sumErr != nil {
+		return nil, 0, sumErr
+	}
+	ledger.ClosingBalance = account.NormalBalance(debits - credits)
+
+	return ledger, total, nil
+}
+
+// ensurePeriodOpen refuses entries dated within a closing or closed accounting period.
+// Dates not covered by any period are accepted.
+func (s *LedgerService) ensurePeriodOpen(ctx context.Context, entryDate time.Time) *errors.Error {
+	period, err := s.periodRepo.GetForDate(ctx, entryDate)
+	if err != nil {
+		return err
+	}
+
+	if period != nil && !period.IsOpen() {
+		return errors.New(errors.ErrCodePrecondition, fmt.Sprintf("accounting period %s is %s", period.Name, period.Status))
+	}
+
+	return nil
+}
+
+// parseEntryDate parses an entry date given as YYYY-MM-DD (start of day, UTC)
+// or RFC3339. An empty value means now; future dates are rejected.
+func parseEntryDate(value string) (time.Time, *errors.Error) {
+	now := currentTime()
+	if value == "" {
+		return now, nil
+	}
+
+	entryDate, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		entryDate, err = time.Parse(dateFormat, value)
+		if err != nil {
+			return time.Time{}, errors.Validation("invalid entry_date format, expected YYYY-MM-DD or RFC3339")
+		}
+	}
+
+	if entryDate.After(now) {
+		return time.Time{}, errors.Validation("entry_date cannot be in the future")
+	}
+
+	return entryDate, nil
+}
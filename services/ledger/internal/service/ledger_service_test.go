@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vnykmshr/nivo/services/ledger/internal/models"
@@ -69,6 +70,21 @@ func (m *mockAccountRepository) List(ctx context.Context, accountType *models.Ac
 	return nil, nil
 }
 
+func (m *mockAccountRepository) ListBalances(ctx context.Context, accountIDs []string) ([]*models.AccountBalance, *errors.Error) {
+	balances := make([]*models.AccountBalance, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		if account, ok := m.accounts[id]; ok {
+			balances = append(balances, &models.AccountBalance{
+				AccountID:   account.ID,
+				DebitTotal:  account.DebitTotal,
+				CreditTotal: account.CreditTotal,
+				Balance:     account.Balance,
+			})
+		}
+	}
+	return balances, nil
+}
+
 func (m *mockAccountRepository) GetBalance(ctx context.Context, accountID string) (int64, *errors.Error) {
 	if m.getBalanceFunc != nil {
 		return m.getBalanceFunc(ctx, accountID)
@@ -86,6 +102,8 @@ type mockJournalEntryRepository struct {
 	createFunc  func(ctx context.Context, entry *models.JournalEntry, lines []models.LedgerLine) *errors.Error
 	postFunc    func(ctx context.Context, entryID, postedBy string) *errors.Error
 	voidFunc    func(ctx context.Context, entryID, voidedBy, voidReason string) *errors.Error
+	sumFunc     func(ctx context.Context, accountID string, asOf time.Time) (int64, int64, *errors.Error)
+	linesFunc   func(ctx context.Context, accountID string, from *time.Time, to time.Time, limit, offset int) ([]models.AccountLedgerLine, int64, *errors.Error)
 }
 
 func (m *mockJournalEntryRepository) GetByID(ctx context.Context, id string) (*models.JournalEntry, *errors.Error) {
@@ -142,10 +160,34 @@ func (m *mockJournalEntryRepository) Void(ctx context.Context, entryID, voidedBy
 	return nil
 }
 
+func (m *mockJournalEntryRepository) GetByReference(ctx context.Context, referenceType, referenceID string) (*models.JournalEntry, *errors.Error) {
+	for _, entry := range m.entries {
+		if entry.ReferenceType == referenceType && entry.ReferenceID == referenceID &&
+			(entry.Status == models.EntryStatusDraft || entry.Status == models.EntryStatusPosted) {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
 func (m *mockJournalEntryRepository) List(ctx context.Context, status *models.EntryStatus, limit, offset int) ([]*models.JournalEntry, *errors.Error) {
 	return nil, nil
 }
 
+func (m *mockJournalEntryRepository) SumAccountActivity(ctx context.Context, accountID string, asOf time.Time) (int64, int64, *errors.Error) {
+	if m.sumFunc != nil {
+		return m.sumFunc(ctx, accountID, asOf)
+	}
+	return 0, 0, nil
+}
+
+func (m *mockJournalEntryRepository) ListAccountLines(ctx context.Context, accountID string, from *time.Time, to time.Time, limit, offset int) ([]models.AccountLedgerLine, int64, *errors.Error) {
+	if m.linesFunc != nil {
+		return m.linesFunc(ctx, accountID, from, to, limit, offset)
+	}
+	return []models.AccountLedgerLine{}, 0, nil
+}
+
 // =====================================================================
 // Test Helpers
 // =====================================================================
@@ -161,7 +203,7 @@ func setupTestService() (*LedgerService, *mockAccountRepository, *mockJournalEnt
 	journalRepo := &mockJournalEntryRepository{
 		entries: make(map[string]*models.JournalEntry),
 	}
-	service := NewLedgerService(accountRepo, journalRepo)
+	service := NewLedgerService(accountRepo, journalRepo, newMockPeriodRepository())
 	return service, accountRepo, journalRepo
 }
 
@@ -622,6 +664,115 @@ func TestReverseJournalEntry_Error_NotPosted(t *testing.T) {
 	}
 }
 
+// =====================================================================
+// RecordJournalEntry Tests
+// =====================================================================
+
+func newTransferRecordRequest(fromAccountID, toAccountID, transactionID string) *models.CreateJournalEntryRequest {
+	return &models.CreateJournalEntryRequest{
+		Type:          models.EntryTypeStandard,
+		Description:   "Transfer",
+		ReferenceType: "transaction",
+		ReferenceID:   transactionID,
+		Lines: []models.LedgerLineInput{
+			{AccountID: toAccountID, DebitAmount: 5000, Description: "Transfer in"},
+			{AccountID: fromAccountID, CreditAmount: 5000, Description: "Transfer out"},
+		},
+	}
+}
+
+func TestRecordJournalEntry_CreatesAndPosts(t *testing.T) {
+	service, accountRepo, _ := setupTestService()
+	ctx := context.Background()
+
+	from := createTestAccount(uuid.New().String(), "1100", "Wallet A", models.AccountTypeAsset)
+	to := createTestAccount(uuid.New().String(), "1101", "Wallet B", models.AccountTypeAsset)
+	accountRepo.accounts[from.ID] = from
+	accountRepo.accounts[to.ID] = to
+
+	entry, err := service.RecordJournalEntry(ctx, newTransferRecordRequest(from.ID, to.ID, "tx-001"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if entry.Status != models.EntryStatusPosted {
+		t.Errorf("expected posted status, got %s", entry.Status)
+	}
+	if entry.PostedBy == nil || *entry.PostedBy != systemActorID {
+		t.Errorf("expected posted by system actor, got %v", entry.PostedBy)
+	}
+}
+
+func TestRecordJournalEntry_RedeliveryReturnsExistingEntry(t *testing.T) {
+	service, accountRepo, journalRepo := setupTestService()
+	ctx := context.Background()
+
+	from := createTestAccount(uuid.New().String(), "1100", "Wallet A", models.AccountTypeAsset)
+	to := createTestAccount(uuid.New().String(), "1101", "Wallet B", models.AccountTypeAsset)
+	accountRepo.accounts[from.ID] = from
+	accountRepo.accounts[to.ID] = to
+
+	first, err := service.RecordJournalEntry(ctx, newTransferRecordRequest(from.ID, to.ID, "tx-001"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	second, err := service.RecordJournalEntry(ctx, newTransferRecordRequest(from.ID, to.ID, "tx-001"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if second.ID != first.ID {
+		t.Errorf("expected existing entry %s, got %s", first.ID, second.ID)
+	}
+	if len(journalRepo.entries) != 1 {
+		t.Errorf("expected 1 journal entry, got %d", len(journalRepo.entries))
+	}
+}
+
+func TestRecordJournalEntry_PostsLeftoverDraft(t *testing.T) {
+	service, _, journalRepo := setupTestService()
+	ctx := context.Background()
+
+	draft := &models.JournalEntry{
+		ID:            uuid.New().String(),
+		Type:          models.EntryTypeStandard,
+		Status:        models.EntryStatusDraft,
+		ReferenceType: "transaction",
+		ReferenceID:   "tx-001",
+		Lines: []models.LedgerLine{
+			{DebitAmount: 5000},
+			{CreditAmount: 5000},
+		},
+	}
+	journalRepo.entries[draft.ID] = draft
+
+	entry, err := service.RecordJournalEntry(ctx, newTransferRecordRequest(uuid.New().String(), uuid.New().String(), "tx-001"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if entry.ID != draft.ID {
+		t.Errorf("expected draft %s to be reused, got %s", draft.ID, entry.ID)
+	}
+	if entry.Status != models.EntryStatusPosted {
+		t.Errorf("expected posted status, got %s", entry.Status)
+	}
+}
+
+func TestRecordJournalEntry_RequiresReference(t *testing.T) {
+	service, _, _ := setupTestService()
+
+	req := newTransferRecordRequest(uuid.New().String(), uuid.New().String(), "")
+	_, err := service.RecordJournalEntry(context.Background(), req)
+	if err == nil {
+		t.Fatal("expected error for missing reference, got nil")
+	}
+	if err.Code != errors.ErrCodeValidation {
+		t.Errorf("expected validation error, got %s", err.Code)
+	}
+}
+
 // =====================================================================
 // VoidJournalEntry Tests
 // =====================================================================
@@ -796,3 +947,108 @@ func TestUpdateAccount_NotFound(t *testing.T) {
 		t.Errorf("expected not found error, got %s", err.Code)
 	}
 }
+
+// =====================================================================
+// Point-in-time Balance and Account Ledger Tests
+// =====================================================================
+
+func TestGetAccountBalanceAsOf_CreditNormal(t *testing.T) {
+	service, accountRepo, journalRepo := setupTestService()
+	ctx := context.Background()
+
+	account := createTestAccount("acc-2100", "2100", "Customer Deposits", models.AccountTypeLiability)
+	account.Balance = 999999 // running total must be ignored
+	accountRepo.accounts[account.ID] = account
+
+	asOf := time.Date(2025, 3, 31, 23, 59, 0, 0, time.UTC)
+	journalRepo.sumFunc = func(ctx context.Context, accountID string, at time.Time) (int64, int64, *errors.Error) {
+		if !at.Equal(asOf) {
+			t.Errorf("expected as-of %v, got %v", asOf, at)
+		}
+		return 20000, 150000, nil
+	}
+
+	balance, err := service.GetAccountBalanceAsOf(ctx, account.ID, asOf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if balance.Balance != 130000 {
+		t.Errorf("expected balance 130000, got %d", balance.Balance)
+	}
+	if balance.DebitTotal != 20000 || balance.CreditTotal != 150000 {
+		t.Errorf("unexpected totals: %d/%d", balance.DebitTotal, balance.CreditTotal)
+	}
+}
+
+func TestGetAccountBalanceAsOf_AccountNotFound(t *testing.T) {
+	service, _, _ := setupTestService()
+
+	_, err := service.GetAccountBalanceAsOf(context.Background(), "missing", time.Now())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected NOT_FOUND, got %s", err.Code)
+	}
+}
+
+func TestGetAccountLedger_RunningBalances(t *testing.T) {
+	service, accountRepo, journalRepo := setupTestService()
+	ctx := context.Background()
+
+	account := createTestAccount("acc-2100", "2100", "Customer Deposits", models.AccountTypeLiability)
+	accountRepo.accounts[account.ID] = account
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	journalRepo.linesFunc = func(ctx context.Context, accountID string, f *time.Time, tt time.Time, limit, offset int) ([]models.AccountLedgerLine, int64, *errors.Error) {
+		// Cumulative net debits: opening -50000, then credit 10000, then debit 5000
+		return []models.AccountLedgerLine{
+			{LineID: "l1", CreditAmount: 10000, RunningBalance: -60000},
+			{LineID: "l2", DebitAmount: 5000, RunningBalance: -55000},
+		}, 2, nil
+	}
+	journalRepo.sumFunc = func(ctx context.Context, accountID string, asOf time.Time) (int64, int64, *errors.Error) {
+		if asOf.Before(from) {
+			return 0, 50000, nil
+		}
+		return 5000, 60000, nil
+	}
+
+	ledger, total, err := service.GetAccountLedger(ctx, account.ID, &from, to, 20, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if total != 2 {
+		t.Errorf("expected total 2, got %d", total)
+	}
+	if ledger.OpeningBalance != 50000 {
+		t.Errorf("expected opening balance 50000, got %d", ledger.OpeningBalance)
+	}
+	if ledger.ClosingBalance != 55000 {
+		t.Errorf("expected closing balance 55000, got %d", ledger.ClosingBalance)
+	}
+	if ledger.Lines[0].RunningBalance != 60000 || ledger.Lines[1].RunningBalance != 55000 {
+		t.Errorf("unexpected running balances: %d, %d", ledger.Lines[0].RunningBalance, ledger.Lines[1].RunningBalance)
+	}
+}
+
+func TestGetAccountLedger_InvalidRange(t *testing.T) {
+	service, accountRepo, _ := setupTestService()
+	account := createTestAccount("acc-1000", "1000", "Cash", models.AccountTypeAsset)
+	accountRepo.accounts[account.ID] = account
+
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	from := to.Add(time.Hour)
+
+	_, _, err := service.GetAccountLedger(context.Background(), account.ID, &from, to, 20, 0)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected BAD_REQUEST, got %s", err.Code)
+	}
+}
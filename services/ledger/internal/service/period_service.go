@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/ledger/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// dateFormat is the calendar date format used for period boundaries and entry dates.
+const dateFormat = "2006-01-02"
+
+// PeriodRepositoryInterface defines the interface for accounting period repository operations.
+type PeriodRepositoryInterface interface {
+	Create(ctx context.Context, period *models.AccountingPeriod) *errors.Error
+	GetByID(ctx context.Context, id string) (*models.AccountingPeriod, *errors.Error)
+	GetForDate(ctx context.Context, t time.Time) (*models.AccountingPeriod, *errors.Error)
+	HasOverlap(ctx context.Context, startsAt, endsAt time.Time) (bool, *errors.Error)
+	List(ctx context.Context, status *models.PeriodStatus, limit, offset int) ([]*models.AccountingPeriod, *errors.Error)
+	MarkClosing(ctx context.Context, id string) *errors.Error
+	Close(ctx context.Context, id, closedBy string) *errors.Error
+	Reopen(ctx context.Context, id, reopenedBy, reason string) *errors.Error
+	ListBalances(ctx context.Context, periodID string) ([]*models.PeriodBalance, *errors.Error)
+}
+
+// PeriodService handles the accounting period lifecycle: open, closing, closed.
+type PeriodService struct {
+	periodRepo PeriodRepositoryInterface
+}
+
+// NewPeriodService creates a new accounting period service.
+func NewPeriodService(periodRepo PeriodRepositoryInterface) *PeriodService {
+	return &PeriodService{
+		periodRepo: periodRepo,
+	}
+}
+
+// CreatePeriod creates an open accounting period covering whole calendar days
+// from StartDate through EndDate. Periods may not overlap.
+func (s *PeriodService) CreatePeriod(ctx context.Context, req *models.CreatePeriodRequest) (*models.AccountingPeriod, *errors.Error) {
+	startsAt, err := time.Parse(dateFormat, req.StartDate)
+	if err != nil {
+		return nil, errors.Validation("invalid start_date format, expected YYYY-MM-DD")
+	}
+
+	endDate, err := time.Parse(dateFormat, req.EndDate)
+	if err != nil {
+		return nil, errors.Validation("invalid end_date format, expected YYYY-MM-DD")
+	}
+
+	if endDate.Before(startsAt) {
+		return nil, errors.Validation("end_date cannot be before start_date")
+	}
+
+	// Stored as a half-open range so the last day is fully included
+	endsAt := endDate.AddDate(0, 0, 1)
+
+	overlaps, overlapErr := s.periodRepo.HasOverlap(ctx, startsAt, endsAt)
+	if overlapErr != nil {
+		return nil, overlapErr
+	}
+	if overlaps {
+		return nil, errors.Conflict("accounting period overlaps an existing period")
+	}
+
+	period := &models.AccountingPeriod{
+		Name:     req.Name,
+		StartsAt: sharedModels.NewTimestamp(startsAt),
+		EndsAt:   sharedModels.NewTimestamp(endsAt),
+		Status:   models.PeriodStatusOpen,
+	}
+
+	if createErr := s.periodRepo.Create(ctx, period); createErr != nil {
+		return nil, createErr
+	}
+
+	return period, nil
+}
+
+// GetPeriod retrieves an accounting period by ID.
+func (s *PeriodService) GetPeriod(ctx context.Context, id string) (*models.AccountingPeriod, *errors.Error) {
+	return s.periodRepo.GetByID(ctx, id)
+}
+
+// ListPeriods retrieves accounting periods with an optional status filter.
+func (s *PeriodService) ListPeriods(ctx context.Context, status *models.PeriodStatus, limit, offset int) ([]*models.AccountingPeriod, *errors.Error) {
+	return s.periodRepo.List(ctx, status, limit, offset)
+}
+
+// ClosePeriod locks a period that has ended. The period first moves to closing,
+// which blocks postings, then every account balance is snapshotted and the
+// period is marked closed. A period stuck in closing can be closed again.
+func (s *PeriodService) ClosePeriod(ctx context.Context, id, closedBy string) (*models.AccountingPeriod, *errors.Error) {
+	period, err := s.periodRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if period.Status == models.PeriodStatusClosed {
+		return nil, errors.BadRequest("accounting period is already closed")
+	}
+
+	if currentTime().Before(period.EndsAt.Time) {
+		return nil, errors.BadRequest("accounting period cannot be closed before it ends")
+	}
+
+	if markErr := s.periodRepo.MarkClosing(ctx, id); markErr != nil {
+		return nil, markErr
+	}
+
+	if closeErr := s.periodRepo.Close(ctx, id, closedBy); closeErr != nil {
+		return nil, closeErr
+	}
+
+	return s.periodRepo.GetByID(ctx, id)
+}
+
+// ReopenPeriod reopens a closed period so entries dated within it can be
+// recorded again. The balance snapshot is discarded and retaken on the next close.
+func (s *PeriodService) ReopenPeriod(ctx context.Context, id, reopenedBy, reason string) (*models.AccountingPeriod, *errors.Error) {
+	period, err := s.periodRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if period.Status != models.PeriodStatusClosed {
+		return nil, errors.BadRequest("only closed accounting periods can be reopened")
+	}
+
+	if reopenErr := s.periodRepo.Reopen(ctx, id, reopenedBy, reason); reopenErr != nil {
+		return nil, reopenErr
+	}
+
+	return s.periodRepo.GetByID(ctx, id)
+}
+
+// GetPeriodBalances retrieves the account balances captured when a period was closed.
+func (s *PeriodService) GetPeriodBalances(ctx context.Context, id string) ([]*models.PeriodBalance, *errors.Error) {
+	period, err := s.periodRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if period.Status != models.PeriodStatusClosed {
+		return nil, errors.New(errors.ErrCodePrecondition, "accounting period has not been closed")
+	}
+
+	return s.periodRepo.ListBalances(ctx, id)
+}
+
+// currentTime returns the current time (can be mocked for testing).
+var currentTime = func() time.Time {
+	return time.Now().UTC()
+}
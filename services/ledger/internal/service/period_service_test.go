@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/ledger/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// =====================================================================
+// Mock Period Repository
+// =====================================================================
+
+type mockPeriodRepository struct {
+	periods        map[string]*models.AccountingPeriod
+	hasOverlapFunc func(ctx context.Context, startsAt, endsAt time.Time) (bool, *errors.Error)
+	calls          []string
+}
+
+func newMockPeriodRepository() *mockPeriodRepository {
+	return &mockPeriodRepository{
+		periods: make(map[string]*models.AccountingPeriod),
+	}
+}
+
+func (m *mockPeriodRepository) Create(ctx context.Context, period *models.AccountingPeriod) *errors.Error {
+	period.ID = uuid.New().String()
+	m.periods[period.ID] = period
+	return nil
+}
+
+func (m *mockPeriodRepository) GetByID(ctx context.Context, id string) (*models.AccountingPeriod, *errors.Error) {
+	period, ok := m.periods[id]
+	if !ok {
+		return nil, errors.NotFound("accounting period not found")
+	}
+	return period, nil
+}
+
+func (m *mockPeriodRepository) GetForDate(ctx context.Context, t time.Time) (*models.AccountingPeriod, *errors.Error) {
+	for _, period := range m.periods {
+		if period.Contains(t) {
+			return period, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockPeriodRepository) HasOverlap(ctx context.Context, startsAt, endsAt time.Time) (bool, *errors.Error) {
+	if m.hasOverlapFunc != nil {
+		return m.hasOverlapFunc(ctx, startsAt, endsAt)
+	}
+	return false, nil
+}
+
+func (m *mockPeriodRepository) List(ctx context.Context, status *models.PeriodStatus, limit, offset int) ([]*models.AccountingPeriod, *errors.Error) {
+	return nil, nil
+}
+
+func (m *mockPeriodRepository) MarkClosing(ctx context.Context, id string) *errors.Error {
+	m.calls = append(m.calls, "mark_closing")
+	m.periods[id].Status = models.PeriodStatusClosing
+	return nil
+}
+
+func (m *mockPeriodRepository) Close(ctx context.Context, id, closedBy string) *errors.Error {
+	m.calls = append(m.calls, "close")
+	period := m.periods[id]
+	period.Status = models.PeriodStatusClosed
+	period.ClosedBy = &closedBy
+	return nil
+}
+
+func (m *mockPeriodRepository) Reopen(ctx context.Context, id, reopenedBy, reason string) *errors.Error {
+	m.calls = append(m.calls, "reopen")
+	period := m.periods[id]
+	period.Status = models.PeriodStatusOpen
+	period.ReopenedBy = &reopenedBy
+	period.ReopenReason = &reason
+	return nil
+}
+
+func (m *mockPeriodRepository) ListBalances(ctx context.Context, periodID string) ([]*models.PeriodBalance, *errors.Error) {
+	return []*models.PeriodBalance{}, nil
+}
+
+var _ PeriodRepositoryInterface = (*mockPeriodRepository)(nil)
+
+// addPeriod registers a period covering [start, end) with the given status.
+func (m *mockPeriodRepository) addPeriod(name string, start, end time.Time, status models.PeriodStatus) *models.AccountingPeriod {
+	period := &models.AccountingPeriod{
+		ID:       uuid.New().String(),
+		Name:     name,
+		StartsAt: sharedModels.NewTimestamp(start),
+		EndsAt:   sharedModels.NewTimestamp(end),
+		Status:   status,
+	}
+	m.periods[period.ID] = period
+	return period
+}
+
+// =====================================================================
+// Period Lifecycle Tests
+// =====================================================================
+
+func TestCreatePeriod_Success(t *testing.T) {
+	repo := newMockPeriodRepository()
+	service := NewPeriodService(repo)
+
+	period, err := service.CreatePeriod(context.Background(), &models.CreatePeriodRequest{
+		Name:      "2025-03",
+		StartDate: "2025-03-01",
+		EndDate:   "2025-03-31",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if period.Status != models.PeriodStatusOpen {
+		t.Errorf("expected open status, got %s", period.Status)
+	}
+	expectedEnd := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	if !period.EndsAt.Time.Equal(expectedEnd) {
+		t.Errorf("expected ends_at %v, got %v", expectedEnd, period.EndsAt.Time)
+	}
+}
+
+func TestCreatePeriod_InvalidRange(t *testing.T) {
+	service := NewPeriodService(newMockPeriodRepository())
+
+	_, err := service.CreatePeriod(context.Background(), &models.CreatePeriodRequest{
+		Name:      "2025-03",
+		StartDate: "2025-03-31",
+		EndDate:   "2025-03-01",
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err.Code != errors.ErrCodeValidation {
+		t.Errorf("expected VALIDATION_ERROR, got %s", err.Code)
+	}
+}
+
+func TestCreatePeriod_Overlap(t *testing.T) {
+	repo := newMockPeriodRepository()
+	repo.hasOverlapFunc = func(ctx context.Context, startsAt, endsAt time.Time) (bool, *errors.Error) {
+		return true, nil
+	}
+	service := NewPeriodService(repo)
+
+	_, err := service.CreatePeriod(context.Background(), &models.CreatePeriodRequest{
+		Name:      "2025-03",
+		StartDate: "2025-03-01",
+		EndDate:   "2025-03-31",
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err.Code != errors.ErrCodeConflict {
+		t.Errorf("expected CONFLICT, got %s", err.Code)
+	}
+}
+
+func TestClosePeriod_Success(t *testing.T) {
+	repo := newMockPeriodRepository()
+	period := repo.addPeriod("2025-03",
+		time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		models.PeriodStatusOpen)
+	service := NewPeriodService(repo)
+
+	closed, err := service.ClosePeriod(context.Background(), period.ID, "user-123")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if closed.Status != models.PeriodStatusClosed {
+		t.Errorf("expected closed status, got %s", closed.Status)
+	}
+	if len(repo.calls) != 2 || repo.calls[0] != "mark_closing" || repo.calls[1] != "close" {
+		t.Errorf("expected mark_closing then close, got %v", repo.calls)
+	}
+}
+
+func TestClosePeriod_NotEnded(t *testing.T) {
+	repo := newMockPeriodRepository()
+	now := time.Now().UTC()
+	period := repo.addPeriod("current", now.AddDate(0, 0, -1), now.AddDate(0, 0, 1), models.PeriodStatusOpen)
+	service := NewPeriodService(repo)
+
+	_, err := service.ClosePeriod(context.Background(), period.ID, "user-123")
+	if err == nil {
+		t.Fatal("expected error for period that has not ended, got nil")
+	}
+	if len(repo.calls) != 0 {
+		t.Errorf("expected no repository writes, got %v", repo.calls)
+	}
+}
+
+func TestReopenPeriod_NotClosed(t *testing.T) {
+	repo := newMockPeriodRepository()
+	period := repo.addPeriod("2025-03",
+		time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		models.PeriodStatusOpen)
+	service := NewPeriodService(repo)
+
+	_, err := service.ReopenPeriod(context.Background(), period.ID, "admin-1", "late supplier invoice")
+	if err == nil {
+		t.Fatal("expected error reopening an open period, got nil")
+	}
+	if err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected BAD_REQUEST, got %s", err.Code)
+	}
+}
+
+// =====================================================================
+// Period Lock Tests
+// =====================================================================
+
+func setupLockedLedger(t *testing.T) (*LedgerService, *mockAccountRepository, *mockJournalEntryRepository, *models.AccountingPeriod) {
+	t.Helper()
+
+	service, accountRepo, journalRepo := setupTestService()
+	periodRepo := newMockPeriodRepository()
+	service.periodRepo = periodRepo
+
+	closed := periodRepo.addPeriod("2025-03",
+		time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		models.PeriodStatusClosed)
+
+	cash := createTestAccount("cash", "1000", "Cash", models.AccountTypeAsset)
+	revenue := createTestAccount("revenue", "4000", "Revenue", models.AccountTypeRevenue)
+	accountRepo.accounts[cash.ID] = cash
+	accountRepo.accounts[revenue.ID] = revenue
+
+	return service, accountRepo, journalRepo, closed
+}
+
+func balancedLines() []models.LedgerLineInput {
+	return []models.LedgerLineInput{
+		{AccountID: "cash", DebitAmount: 10000},
+		{AccountID: "revenue", CreditAmount: 10000},
+	}
+}
+
+func TestCreateJournalEntry_ClosedPeriod(t *testing.T) {
+	service, _, _, _ := setupLockedLedger(t)
+
+	_, err := service.CreateJournalEntry(context.Background(), &models.CreateJournalEntryRequest{
+		Type:        models.EntryTypeAdjusting,
+		Description: "Backdated adjustment",
+		EntryDate:   "2025-03-15",
+		Lines:       balancedLines(),
+	})
+	if err == nil {
+		t.Fatal("expected error for entry in closed period, got nil")
+	}
+	if err.Code != errors.ErrCodePrecondition {
+		t.Errorf("expected PRECONDITION_FAILED, got %s", err.Code)
+	}
+}
+
+func TestCreateJournalEntry_FutureEntryDate(t *testing.T) {
+	service, _, _ := setupTestService()
+
+	_, err := service.CreateJournalEntry(context.Background(), &models.CreateJournalEntryRequest{
+		Type:        models.EntryTypeStandard,
+		Description: "Future entry",
+		EntryDate:   time.Now().AddDate(0, 0, 2).Format(dateFormat),
+		Lines:       balancedLines(),
+	})
+	if err == nil {
+		t.Fatal("expected error for future entry date, got nil")
+	}
+	if err.Code != errors.ErrCodeValidation {
+		t.Errorf("expected VALIDATION_ERROR, got %s", err.Code)
+	}
+}
+
+func TestPostJournalEntry_ClosedPeriod(t *testing.T) {
+	service, _, journalRepo, _ := setupLockedLedger(t)
+
+	draft := &models.JournalEntry{
+		ID:        uuid.New().String(),
+		Status:    models.EntryStatusDraft,
+		EntryDate: sharedModels.NewTimestamp(time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)),
+		Lines: []models.LedgerLine{
+			{AccountID: "cash", DebitAmount: 10000},
+			{AccountID: "revenue", CreditAmount: 10000},
+		},
+	}
+	journalRepo.entries[draft.ID] = draft
+
+	_, err := service.PostJournalEntry(context.Background(), draft.ID, "user-123")
+	if err == nil {
+		t.Fatal("expected error posting into closed period, got nil")
+	}
+	if draft.Status != models.EntryStatusDraft {
+		t.Errorf("expected entry to remain draft, got %s", draft.Status)
+	}
+}
+
+func TestReverseJournalEntry_ClosedPeriodLandsInCurrentPeriod(t *testing.T) {
+	service, _, journalRepo, _ := setupLockedLedger(t)
+
+	original := &models.JournalEntry{
+		ID:          uuid.New().String(),
+		EntryNumber: "JE-2025-00001",
+		Status:      models.EntryStatusPosted,
+		EntryDate:   sharedModels.NewTimestamp(time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)),
+		Lines: []models.LedgerLine{
+			{AccountID: "cash", DebitAmount: 10000},
+			{AccountID: "revenue", CreditAmount: 10000},
+		},
+	}
+	journalRepo.entries[original.ID] = original
+
+	reversal, err := service.ReverseJournalEntry(context.Background(), original.ID, "user-123", "customer refund")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if reversal.Status != models.EntryStatusPosted {
+		t.Errorf("expected posted reversal, got %s", reversal.Status)
+	}
+	if !reversal.EntryDate.Time.After(original.EntryDate.Time) {
+		t.Errorf("expected reversal dated after the closed period, got %v", reversal.EntryDate.Time)
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/ledger/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// ReportRepositoryInterface defines the interface for report repository operations.
+type ReportRepositoryInterface interface {
+	ListAccountTotals(ctx context.Context, from *time.Time, to time.Time) ([]*models.Account, *errors.Error)
+}
+
+// ReportService builds financial reports from posted journal entries.
+type ReportService struct {
+	reportRepo ReportRepositoryInterface
+}
+
+// NewReportService creates a new report service.
+func NewReportService(reportRepo ReportRepositoryInterface) *ReportService {
+	return &ReportService{
+		reportRepo: reportRepo,
+	}
+}
+
+// GetTrialBalance lists every account with posted activity up to asOf and
+// verifies that total debits equal total credits.
+func (s *ReportService) GetTrialBalance(ctx context.Context, asOf time.Time) (*models.TrialBalance, *errors.Error) {
+	accounts, err := s.reportRepo.ListAccountTotals(ctx, nil, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.TrialBalance{
+		AsOf:  sharedModels.NewTimestamp(asOf),
+		Lines: make([]models.TrialBalanceLine, 0, len(accounts)),
+	}
+
+	for _, account := range accounts {
+		if account.DebitTotal == 0 && account.CreditTotal == 0 {
+			continue
+		}
+
+		line := models.TrialBalanceLine{
+			AccountID:   account.ID,
+			Code:        account.Code,
+			Name:        account.Name,
+			Type:        account.Type,
+			ParentID:    account.ParentID,
+			DebitTotal:  account.DebitTotal,
+			CreditTotal: account.CreditTotal,
+		}
+
+		// Present the net balance on whichever side it falls
+		net := account.DebitTotal - account.CreditTotal
+		if net >= 0 {
+			line.DebitBalance = net
+		} else {
+			line.CreditBalance = -net
+		}
+
+		report.TotalDebits += line.DebitTotal
+		report.TotalCredits += line.CreditTotal
+		report.TotalDebitBalances += line.DebitBalance
+		report.TotalCreditBalances += line.CreditBalance
+		report.Lines = append(report.Lines, line)
+	}
+
+	report.Balanced = report.TotalDebits == report.TotalCredits &&
+		report.TotalDebitBalances == report.TotalCreditBalances
+
+	return report, nil
+}
+
+// GetBalanceSheet reports assets, liabilities and equity as of asOf.
+// Revenue and expenses not yet closed to equity are shown as current earnings.
+func (s *ReportService) GetBalanceSheet(ctx context.Context, asOf time.Time) (*models.BalanceSheet, *errors.Error) {
+	accounts, err := s.reportRepo.ListAccountTotals(ctx, nil, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := buildSections(accounts,
+		models.AccountTypeAsset, models.AccountTypeLiability, models.AccountTypeEquity,
+		models.AccountTypeRevenue, models.AccountTypeExpense)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.BalanceSheet{
+		AsOf:        sharedModels.NewTimestamp(asOf),
+		Assets:      sections[0],
+		Liabilities: sections[1],
+		Equity:      sections[2],
+	}
+
+	revenue, expenses := sections[3], sections[4]
+	report.CurrentEarnings = revenue.Total - expenses.Total
+
+	report.TotalAssets = report.Assets.Total
+	report.TotalLiabilitiesAndEquity = report.Liabilities.Total + report.Equity.Total + report.CurrentEarnings
+	report.Balanced = report.TotalAssets == report.TotalLiabilitiesAndEquity
+
+	return report, nil
+}
+
+// GetIncomeStatement reports revenue, expenses and net income for entries
+// posted between from and to (inclusive).
+func (s *ReportService) GetIncomeStatement(ctx context.Context, from, to time.Time) (*models.IncomeStatement, *errors.Error) {
+	if from.After(to) {
+		return nil, errors.BadRequest("from cannot be after to")
+	}
+
+	accounts, err := s.reportRepo.ListAccountTotals(ctx, &from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := buildSections(accounts, models.AccountTypeRevenue, models.AccountTypeExpense)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.IncomeStatement{
+		From:     sharedModels.NewTimestamp(from),
+		To:       sharedModels.NewTimestamp(to),
+		Revenue:  sections[0],
+		Expenses: sections[1],
+	}
+	report.NetIncome = report.Revenue.Total - report.Expenses.Total
+
+	return report, nil
+}
+
+// buildSections builds a report section for each of the account types, in order.
+func buildSections(accounts []*models.Account, accountTypes ...models.AccountType) ([]models.ReportSection, *errors.Error) {
+	sections := make([]models.ReportSection, 0, len(accountTypes))
+	for _, accountType := range accountTypes {
+		section, err := buildSection(accounts, accountType)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+// buildSection arranges accounts of one type into their ParentID hierarchy and
+// rolls child balances up into subtotals. Accounts without activity are omitted
+// unless a descendant has activity. Accounts whose parent is of a different
+// type (or missing) are treated as top-level. Accounts whose parent chain loops
+// back on itself cannot be placed, and fail the section rather than being left
+// out of its total.
+func buildSection(accounts []*models.Account, accountType models.AccountType) (models.ReportSection, *errors.Error) {
+	section := models.ReportSection{
+		Type:     accountType,
+		Accounts: make([]*models.ReportLine, 0),
+	}
+
+	lines := make(map[string]*models.ReportLine)
+	ordered := make([]*models.Account, 0)
+	for _, account := range accounts {
+		if account.Type != accountType {
+			continue
+		}
+		lines[account.ID] = &models.ReportLine{
+			AccountID: account.ID,
+			Code:      account.Code,
+			Name:      account.Name,
+			Type:      account.Type,
+			ParentID:  account.ParentID,
+			Balance:   account.Balance,
+		}
+		ordered = append(ordered, account)
+	}
+
+	// Link children to parents, preserving the account code ordering
+	roots := make([]*models.ReportLine, 0)
+	for _, account := range ordered {
+		line := lines[account.ID]
+		if account.ParentID != nil {
+			if parent, ok := lines[*account.ParentID]; ok && parent != line {
+				parent.Children = append(parent.Children, line)
+				continue
+			}
+		}
+		roots = append(roots, line)
+	}
+
+	active := make(map[string]bool, len(ordered))
+	for _, account := range ordered {
+		active[account.ID] = account.DebitTotal != 0 || account.CreditTotal != 0
+	}
+
+	visited := make(map[string]bool, len(lines))
+	for _, root := range roots {
+		if rollUp(root, active, visited) {
+			section.Accounts = append(section.Accounts, root)
+			section.Total += root.Subtotal
+		}
+	}
+
+	// Lines no root reaches sit on a cycle of parents
+	for _, account := range ordered {
+		if !visited[account.ID] {
+			return models.ReportSection{}, errors.Internal(fmt.Sprintf(
+				"account hierarchy has a cycle through account %s (%s)", account.Code, account.ID))
+		}
+	}
+
+	return section, nil
+}
+
+// rollUp computes the subtotal of a report line and prunes inactive children.
+// It reports whether the line (or any descendant) has posted activity.
+func rollUp(line *models.ReportLine, active, visited map[string]bool) bool {
+	if visited[line.AccountID] {
+		// Guard against cycles in the account hierarchy
+		return false
+	}
+	visited[line.AccountID] = true
+
+	line.Subtotal = line.Balance
+	hasActivity := active[line.AccountID]
+
+	children := make([]*models.ReportLine, 0, len(line.Children))
+	for _, child := range line.Children {
+		if rollUp(child, active, visited) {
+			line.Subtotal += child.Subtotal
+			children = append(children, child)
+			hasActivity = true
+		}
+	}
+	line.Children = children
+
+	return hasActivity
+}
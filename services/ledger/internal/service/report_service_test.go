@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/ledger/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// =====================================================================
+// Mock Report Repository
+// =====================================================================
+
+type mockReportRepository struct {
+	accounts              []*models.Account
+	listAccountTotalsFunc func(ctx context.Context, from *time.Time, to time.Time) ([]*models.Account, *errors.Error)
+}
+
+func (m *mockReportRepository) ListAccountTotals(ctx context.Context, from *time.Time, to time.Time) ([]*models.Account, *errors.Error) {
+	if m.listAccountTotalsFunc != nil {
+		return m.listAccountTotalsFunc(ctx, from, to)
+	}
+	return m.accounts, nil
+}
+
+var _ ReportRepositoryInterface = (*mockReportRepository)(nil)
+
+// totalsAccount builds an account with debit/credit totals and a normal-side balance.
+func totalsAccount(id, code string, accountType models.AccountType, parentID *string, debits, credits int64) *models.Account {
+	account := createTestAccount(id, code, code, accountType)
+	account.ParentID = parentID
+	account.DebitTotal = debits
+	account.CreditTotal = credits
+	account.Balance = account.NormalBalance(debits - credits)
+	return account
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// sampleAccounts returns a balanced set of account totals:
+// cash 1500 (of which bank 1000), deposits 1200, capital 200, fees 150, opex 50.
+func sampleAccounts() []*models.Account {
+	return []*models.Account{
+		totalsAccount("cash", "1000", models.AccountTypeAsset, nil, 700, 200),
+		totalsAccount("bank", "1010", models.AccountTypeAsset, strPtr("cash"), 1000, 0),
+		totalsAccount("loans", "1200", models.AccountTypeAsset, nil, 0, 0),
+		totalsAccount("deposits", "2100", models.AccountTypeLiability, nil, 100, 1300),
+		totalsAccount("capital", "3000", models.AccountTypeEquity, nil, 0, 200),
+		totalsAccount("fees", "4100", models.AccountTypeRevenue, nil, 0, 150),
+		totalsAccount("opex", "5100", models.AccountTypeExpense, nil, 50, 0),
+	}
+}
+
+// =====================================================================
+// Trial Balance Tests
+// =====================================================================
+
+func TestGetTrialBalance_Balanced(t *testing.T) {
+	service := NewReportService(&mockReportRepository{accounts: sampleAccounts()})
+	asOf := time.Date(2025, 3, 31, 23, 59, 0, 0, time.UTC)
+
+	report, err := service.GetTrialBalance(context.Background(), asOf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !report.Balanced {
+		t.Errorf("expected trial balance to be balanced: debits=%d credits=%d", report.TotalDebits, report.TotalCredits)
+	}
+	if report.TotalDebits != 1850 || report.TotalCredits != 1850 {
+		t.Errorf("expected totals 1850/1850, got %d/%d", report.TotalDebits, report.TotalCredits)
+	}
+	if report.TotalDebitBalances != 1550 || report.TotalCreditBalances != 1550 {
+		t.Errorf("expected balance totals 1550/1550, got %d/%d", report.TotalDebitBalances, report.TotalCreditBalances)
+	}
+	// The loans account has no activity and must be omitted
+	if len(report.Lines) != 6 {
+		t.Errorf("expected 6 lines, got %d", len(report.Lines))
+	}
+	if !report.AsOf.Time.Equal(asOf) {
+		t.Errorf("expected as_of %v, got %v", asOf, report.AsOf.Time)
+	}
+}
+
+func TestGetTrialBalance_Unbalanced(t *testing.T) {
+	accounts := sampleAccounts()
+	accounts[0].DebitTotal += 10
+
+	service := NewReportService(&mockReportRepository{accounts: accounts})
+
+	report, err := service.GetTrialBalance(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Balanced {
+		t.Error("expected trial balance to be unbalanced")
+	}
+}
+
+func TestGetTrialBalance_RepositoryError(t *testing.T) {
+	repo := &mockReportRepository{
+		listAccountTotalsFunc: func(ctx context.Context, from *time.Time, to time.Time) ([]*models.Account, *errors.Error) {
+			return nil, errors.Database("connection lost")
+		},
+	}
+	service := NewReportService(repo)
+
+	_, err := service.GetTrialBalance(context.Background(), time.Now())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// =====================================================================
+// Balance Sheet Tests
+// =====================================================================
+
+func TestGetBalanceSheet_RollsUpHierarchy(t *testing.T) {
+	service := NewReportService(&mockReportRepository{accounts: sampleAccounts()})
+
+	report, err := service.GetBalanceSheet(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(report.Assets.Accounts) != 1 {
+		t.Fatalf("expected 1 top-level asset account, got %d", len(report.Assets.Accounts))
+	}
+	cash := report.Assets.Accounts[0]
+	if cash.Balance != 500 || cash.Subtotal != 1500 {
+		t.Errorf("expected cash balance 500 / subtotal 1500, got %d / %d", cash.Balance, cash.Subtotal)
+	}
+	if len(cash.Children) != 1 || cash.Children[0].AccountID != "bank" {
+		t.Errorf("expected bank as child of cash, got %+v", cash.Children)
+	}
+
+	if report.CurrentEarnings != 100 {
+		t.Errorf("expected current earnings 100, got %d", report.CurrentEarnings)
+	}
+	if report.TotalAssets != 1500 || report.TotalLiabilitiesAndEquity != 1500 {
+		t.Errorf("expected 1500 = 1500, got %d = %d", report.TotalAssets, report.TotalLiabilitiesAndEquity)
+	}
+	if !report.Balanced {
+		t.Error("expected balance sheet to be balanced")
+	}
+}
+
+func TestGetBalanceSheet_HierarchyCycle(t *testing.T) {
+	// savings and bank2 are each other's parent, so neither is reachable from a top-level account
+	accounts := append(sampleAccounts(),
+		totalsAccount("savings", "1020", models.AccountTypeAsset, strPtr("bank2"), 300, 0),
+		totalsAccount("bank2", "1030", models.AccountTypeAsset, strPtr("savings"), 200, 0),
+	)
+	service := NewReportService(&mockReportRepository{accounts: accounts})
+
+	_, err := service.GetBalanceSheet(context.Background(), time.Now())
+	if err == nil || err.Code != errors.ErrCodeInternal {
+		t.Fatalf("expected an internal error for the cycle, got %v", err)
+	}
+}
+
+// =====================================================================
+// Income Statement Tests
+// =====================================================================
+
+func TestGetIncomeStatement_Success(t *testing.T) {
+	var gotFrom *time.Time
+	repo := &mockReportRepository{
+		listAccountTotalsFunc: func(ctx context.Context, from *time.Time, to time.Time) ([]*models.Account, *errors.Error) {
+			gotFrom = from
+			return sampleAccounts(), nil
+		},
+	}
+	service := NewReportService(repo)
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	report, err := service.GetIncomeStatement(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if gotFrom == nil || !gotFrom.Equal(from) {
+		t.Errorf("expected repository to be queried from %v, got %v", from, gotFrom)
+	}
+	if report.Revenue.Total != 150 || report.Expenses.Total != 50 {
+		t.Errorf("expected revenue 150 / expenses 50, got %d / %d", report.Revenue.Total, report.Expenses.Total)
+	}
+	if report.NetIncome != 100 {
+		t.Errorf("expected net income 100, got %d", report.NetIncome)
+	}
+}
+
+func TestGetIncomeStatement_InvalidRange(t *testing.T) {
+	service := NewReportService(&mockReportRepository{})
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.GetIncomeStatement(context.Background(), to.Add(time.Hour), to)
+	if err == nil {
+		t.Fatal("expected error for inverted range, got nil")
+	}
+	if err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected BAD_REQUEST, got %s", err.Code)
+	}
+}
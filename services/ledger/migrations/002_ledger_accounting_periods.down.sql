@@ -0,0 +1,7 @@
+-- Accounting Periods Rollback
+
+DROP TABLE IF EXISTS period_balances CASCADE;
+DROP TABLE IF EXISTS accounting_periods CASCADE;
+
+DROP INDEX IF EXISTS idx_journal_entries_entry_date;
+ALTER TABLE journal_entries DROP COLUMN IF EXISTS entry_date;
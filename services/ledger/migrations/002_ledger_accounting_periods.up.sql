@@ -0,0 +1,74 @@
+-- ============================================================================
+-- Journal Entry Accounting Date
+-- ============================================================================
+
+-- entry_date is the accounting date of an entry. It defaults to the time the
+-- entry is created and may be backdated (e.g. month-end adjustments), which is
+-- why postings must be checked against closed accounting periods.
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS entry_date TIMESTAMP WITH TIME ZONE;
+
+UPDATE journal_entries SET entry_date = COALESCE(posted_at, created_at) WHERE entry_date IS NULL;
+
+ALTER TABLE journal_entries ALTER COLUMN entry_date SET DEFAULT NOW();
+ALTER TABLE journal_entries ALTER COLUMN entry_date SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_entry_date ON journal_entries(entry_date);
+
+-- ============================================================================
+-- Accounting Periods Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS accounting_periods (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL UNIQUE,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    closed_at TIMESTAMP WITH TIME ZONE,
+    closed_by UUID,
+    reopened_at TIMESTAMP WITH TIME ZONE,
+    reopened_by UUID,
+    reopen_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT accounting_periods_status_check CHECK (status IN ('open', 'closing', 'closed')),
+    CONSTRAINT accounting_periods_range_check CHECK (starts_at < ends_at),
+    CONSTRAINT accounting_periods_closed_check CHECK (
+        (status = 'closed' AND closed_at IS NOT NULL AND closed_by IS NOT NULL) OR
+        (status != 'closed')
+    )
+);
+
+CREATE INDEX idx_accounting_periods_range ON accounting_periods(starts_at, ends_at);
+CREATE INDEX idx_accounting_periods_status ON accounting_periods(status);
+
+CREATE TRIGGER update_accounting_periods_updated_at
+    BEFORE UPDATE ON accounting_periods
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE accounting_periods IS
+'Accounting periods covering [starts_at, ends_at). Entries dated in a closing or closed period cannot be created, posted or voided.';
+
+-- ============================================================================
+-- Period Balance Snapshots
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS period_balances (
+    period_id UUID NOT NULL REFERENCES accounting_periods(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    period_debits BIGINT NOT NULL DEFAULT 0,
+    period_credits BIGINT NOT NULL DEFAULT 0,
+    debit_total BIGINT NOT NULL DEFAULT 0,
+    credit_total BIGINT NOT NULL DEFAULT 0,
+    balance BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (period_id, account_id)
+);
+
+CREATE INDEX idx_period_balances_account_id ON period_balances(account_id);
+
+COMMENT ON TABLE period_balances IS
+'Closing balance of every account, captured when an accounting period is closed.';
@@ -0,0 +1,11 @@
+-- ============================================================================
+-- One live journal entry per transaction
+-- ============================================================================
+
+-- The transaction service delivers ledger postings at least once. This index
+-- lets the ledger recognise a redelivery instead of recording the entry twice.
+-- Voided and reversed entries are excluded so a transaction can be re-recorded
+-- after its entry has been voided.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_transaction_reference
+    ON journal_entries(reference_id)
+    WHERE reference_type = 'transaction' AND status IN ('draft', 'posted');
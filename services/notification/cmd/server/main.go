@@ -2,14 +2,20 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/vnykmshr/nivo/services/notification/internal/handler"
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
 	"github.com/vnykmshr/nivo/services/notification/internal/repository"
 	"github.com/vnykmshr/nivo/services/notification/internal/service"
+	"github.com/vnykmshr/nivo/shared/config"
+	"github.com/vnykmshr/nivo/shared/events"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/server"
 )
 
@@ -23,16 +29,39 @@ func main() {
 			// Initialize repositories
 			notifRepo := repository.NewNotificationRepository(ctx.DB.DB)
 			templateRepo := repository.NewTemplateRepository(ctx.DB.DB)
+			prefRepo := repository.NewPreferenceRepository(ctx.DB.DB)
 
 			// Load simulation configuration
 			simConfig := loadSimulationConfig()
 			ctx.Logger.WithField("delay_ms", simConfig.DeliveryDelayMs).
 				WithField("failure_rate", simConfig.FailureRatePercent).
-				WithField("max_retries", simConfig.MaxRetryAttempts).
 				Info("Simulation config loaded")
 
+			// Select a delivery provider per channel
+			providers, err := loadProviders(simConfig)
+			if err != nil {
+				return nil, err
+			}
+			for channel, provider := range providers {
+				ctx.Logger.WithField("channel", string(channel)).
+					WithField("provider", provider.Name()).
+					Info("Delivery provider configured")
+			}
+
 			// Initialize service
-			notifService := service.NewNotificationService(notifRepo, templateRepo, simConfig)
+			notifService := service.NewNotificationService(notifRepo, templateRepo, prefRepo, providers)
+			queueConfig := loadQueueConfig()
+			notifService.SetQueueConfig(queueConfig)
+			ctx.Logger.WithField("lease", queueConfig.Lease.String()).
+				WithField("retry_base_delay", queueConfig.Retry.BaseDelay.String()).
+				WithField("retry_max_delay", queueConfig.Retry.MaxDelay.String()).
+				Info("Delivery queue config loaded")
+
+			// Push new in-app notifications to the user's event stream
+			notifService.SetInboxPublisher(events.NewPublisher(events.PublishConfig{
+				GatewayURL:  server.GetEnv("GATEWAY_URL", "http://gateway:8000"),
+				ServiceName: "notification",
+			}))
 
 			// Start background worker for processing queued notifications
 			workerCtx, cancel := context.WithCancel(context.Background())
@@ -40,13 +69,13 @@ func main() {
 
 			go func() {
 				ctx.Logger.Info("Starting background worker for notification processing...")
-				ticker := time.NewTicker(5 * time.Second)
+				ticker := time.NewTicker(config.GetEnvAsDuration("QUEUE_POLL_INTERVAL", time.Second))
 				defer ticker.Stop()
 
 				for {
 					select {
 					case <-ticker.C:
-						if err := notifService.ProcessQueuedNotifications(workerCtx, 10); err != nil {
+						if err := notifService.ProcessQueuedNotifications(workerCtx); err != nil {
 							ctx.Logger.WithError(err).Error("Worker error")
 						}
 					case <-workerCtx.Done():
@@ -56,9 +85,29 @@ func main() {
 				}
 			}()
 
+			// Prune old inbox items
+			inboxReadRetention := config.GetEnvAsDuration("INBOX_READ_RETENTION", 30*24*time.Hour)
+			inboxRetention := config.GetEnvAsDuration("INBOX_RETENTION", 180*24*time.Hour)
+			go func() {
+				ticker := time.NewTicker(config.GetEnvAsDuration("INBOX_PRUNE_INTERVAL", time.Hour))
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						if err := notifService.PruneInbox(workerCtx, inboxReadRetention, inboxRetention); err != nil {
+							ctx.Logger.WithError(err).Error("Inbox pruning error")
+						}
+					case <-workerCtx.Done():
+						return
+					}
+				}
+			}()
+
 			// Initialize handler and router
 			notifHandler := handler.NewNotificationHandler(notifService)
-			router := handler.NewRouter(notifHandler)
+			jwtKeys := sharedjwt.NewRemoteKeySet(server.GetEnv("JWKS_URL", sharedjwt.DefaultJWKSURL))
+			router := handler.NewRouter(notifHandler, jwtKeys)
 
 			return router.SetupRoutes(), nil
 		},
@@ -93,17 +142,110 @@ func loadSimulationConfig() service.SimulationConfig {
 		}
 	}
 
-	if val := os.Getenv("SIM_MAX_RETRY_ATTEMPTS"); val != "" {
-		if retries, err := strconv.Atoi(val); err == nil {
-			config.MaxRetryAttempts = retries
-		}
+	return config
+}
+
+// loadQueueConfig loads the delivery queue configuration from environment
+// variables: QUEUE_CONCURRENCY_<CHANNEL> and RETRY_MAX_ATTEMPTS_<PRIORITY>
+// override the defaults of each channel and priority.
+func loadQueueConfig() service.QueueConfig {
+	queueConfig := service.DefaultQueueConfig()
+
+	for _, channel := range models.NotificationChannels {
+		key := "QUEUE_CONCURRENCY_" + strings.ToUpper(string(channel))
+		queueConfig.Concurrency[channel] = config.GetEnvAsInt(key, queueConfig.Concurrency[channel])
+	}
+	for priority, attempts := range queueConfig.Retry.MaxAttempts {
+		key := "RETRY_MAX_ATTEMPTS_" + strings.ToUpper(string(priority))
+		queueConfig.Retry.MaxAttempts[priority] = config.GetEnvAsInt(key, attempts)
 	}
 
-	if val := os.Getenv("SIM_RETRY_DELAY_MS"); val != "" {
-		if delay, err := strconv.Atoi(val); err == nil {
-			config.RetryDelayMs = delay
+	queueConfig.Lease = config.GetEnvAsDuration("QUEUE_LEASE", queueConfig.Lease)
+	queueConfig.Retry.BaseDelay = config.GetEnvAsDuration("RETRY_BASE_DELAY", queueConfig.Retry.BaseDelay)
+	queueConfig.Retry.MaxDelay = config.GetEnvAsDuration("RETRY_MAX_DELAY", queueConfig.Retry.MaxDelay)
+
+	return queueConfig
+}
+
+// loadProviders builds the delivery provider of each channel from the
+// EMAIL_PROVIDER, SMS_PROVIDER, PUSH_PROVIDER and IN_APP_PROVIDER environment
+// variables and the settings of the providers they name.
+func loadProviders(simConfig service.SimulationConfig) (service.ProviderSet, error) {
+	selected := map[models.NotificationChannel]string{
+		models.ChannelEmail: config.GetEnvOrDefault("EMAIL_PROVIDER", service.ProviderSimulation),
+		models.ChannelSMS:   config.GetEnvOrDefault("SMS_PROVIDER", service.ProviderSimulation),
+		models.ChannelPush:  config.GetEnvOrDefault("PUSH_PROVIDER", service.ProviderSimulation),
+		models.ChannelInApp: config.GetEnvOrDefault("IN_APP_PROVIDER", service.ProviderInbox),
+	}
+
+	// Each provider is built once and shared by the channels that use it
+	built := make(map[string]service.Provider)
+	providers := make(service.ProviderSet)
+	for channel, name := range selected {
+		provider, ok := built[name]
+		if !ok {
+			var err error
+			if provider, err = newProvider(name, simConfig); err != nil {
+				return nil, err
+			}
+			built[name] = provider
 		}
+		providers[channel] = provider
 	}
 
-	return config
+	if err := providers.Validate(); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// newProvider builds the named provider from its environment variables.
+func newProvider(name string, simConfig service.SimulationConfig) (service.Provider, error) {
+	timeout := config.GetEnvAsDuration("PROVIDER_TIMEOUT", 10*time.Second)
+
+	switch name {
+	case service.ProviderSimulation:
+		return service.NewSimulationEngine(simConfig), nil
+
+	case service.ProviderInbox:
+		return service.NewInboxProvider(), nil
+
+	case service.ProviderSMTP:
+		return service.NewSMTPProvider(service.SMTPConfig{
+			Host:     config.GetEnvOrDefault("SMTP_HOST", "localhost"),
+			Port:     config.GetEnvAsInt("SMTP_PORT", 1025),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     config.GetEnvOrDefault("SMTP_FROM", "Nivo Money <no-reply@nivomoney.com>"),
+			StartTLS: config.GetEnvAsBool("SMTP_STARTTLS", false),
+			Timeout:  timeout,
+		})
+
+	case service.ProviderSMSGateway:
+		gatewayConfig := service.SMSGatewayConfig{
+			URL:           os.Getenv("SMS_GATEWAY_URL"),
+			APIKey:        os.Getenv("SMS_GATEWAY_API_KEY"),
+			SenderID:      config.GetEnvOrDefault("SMS_GATEWAY_SENDER_ID", "NIVOMN"),
+			ReceiptSecret: os.Getenv("SMS_GATEWAY_RECEIPT_SECRET"),
+			Timeout:       timeout,
+		}
+		if gatewayConfig.URL == "" || gatewayConfig.APIKey == "" {
+			return nil, fmt.Errorf("SMS_GATEWAY_URL and SMS_GATEWAY_API_KEY are required for the sms_gateway provider")
+		}
+		return service.NewSMSGatewayProvider(gatewayConfig), nil
+
+	case service.ProviderWebhook:
+		webhookConfig := service.WebhookConfig{
+			URL:     os.Getenv("WEBHOOK_URL"),
+			Secret:  os.Getenv("WEBHOOK_SECRET"),
+			Timeout: timeout,
+		}
+		if webhookConfig.URL == "" || len(webhookConfig.Secret) < 32 {
+			return nil, fmt.Errorf("WEBHOOK_URL and a WEBHOOK_SECRET of at least 32 characters are required for the webhook provider")
+		}
+		return service.NewWebhookProvider(webhookConfig), nil
+
+	default:
+		return nil, fmt.Errorf("unknown notification provider %q", name)
+	}
 }
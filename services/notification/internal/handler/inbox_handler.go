@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	"github.com/vnykmshr/nivo/shared/pagination"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// ListInbox lists the authenticated user's in-app notifications.
+// GET /api/v1/notifications/me
+// Query parameters: status (unread, read, archived), page, per_page.
+func (h *NotificationHandler) ListInbox(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	params := pagination.FromRequest(r)
+	notifications, total, svcErr := h.notifService.ListInbox(r.Context(), &models.InboxQuery{
+		UserID: userID,
+		Filter: models.InboxFilter(r.URL.Query().Get("status")),
+		Limit:  params.PerPage,
+		Offset: params.Offset,
+	})
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.Paginated(w, notifications, params.Page, params.PerPage, total)
+}
+
+// GetUnreadCount returns the number of unread in-app notifications.
+// GET /api/v1/notifications/me/unread-count
+func (h *NotificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	count, svcErr := h.notifService.UnreadCount(r.Context(), userID)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, count)
+}
+
+// MarkRead marks an in-app notification read.
+// POST /api/v1/notifications/me/{id}/read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	if svcErr := h.notifService.MarkRead(r.Context(), userID, r.PathValue("id")); svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// MarkAllRead marks all unread in-app notifications read.
+// POST /api/v1/notifications/me/read-all
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	result, svcErr := h.notifService.MarkAllRead(r.Context(), userID)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, result)
+}
+
+// ArchiveNotification archives an in-app notification.
+// POST /api/v1/notifications/me/{id}/archive
+func (h *NotificationHandler) ArchiveNotification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	if svcErr := h.notifService.Archive(r.Context(), userID, r.PathValue("id")); svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.NoContent(w)
+}
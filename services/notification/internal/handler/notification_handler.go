@@ -9,6 +9,7 @@ import (
 	"github.com/vnykmshr/nivo/services/notification/internal/models"
 	"github.com/vnykmshr/nivo/services/notification/internal/service"
 	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/pagination"
 	"github.com/vnykmshr/nivo/shared/response"
 )
 
@@ -119,6 +120,23 @@ func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.R
 	response.OK(w, resp)
 }
 
+// ReceiveReceipt records a delivery receipt posted by a provider.
+// POST /v1/notifications/receipts/{provider}
+func (h *NotificationHandler) ReceiveReceipt(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+
+	if svcErr := h.notifService.HandleReceipt(r.Context(), r.PathValue("provider"), r.Header, body); svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.NoContent(w)
+}
+
 // CreateTemplate creates a new notification template.
 // POST /v1/templates
 func (h *NotificationHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
@@ -143,8 +161,9 @@ func (h *NotificationHandler) CreateTemplate(w http.ResponseWriter, r *http.Requ
 	response.Created(w, template)
 }
 
-// GetTemplate retrieves a template by ID.
+// GetTemplate retrieves a template by ID, or by name in a locale.
 // GET /v1/templates/{id}
+// Query parameters: locale (for names; falls back to the language, then English).
 func (h *NotificationHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
@@ -153,7 +172,7 @@ func (h *NotificationHandler) GetTemplate(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	template, svcErr := h.notifService.GetTemplate(r.Context(), id)
+	template, svcErr := h.notifService.GetTemplate(r.Context(), id, r.URL.Query().Get("locale"))
 	if svcErr != nil {
 		response.Error(w, svcErr)
 		return
@@ -232,7 +251,7 @@ func (h *NotificationHandler) PreviewTemplate(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	preview, svcErr := h.notifService.PreviewTemplate(r.Context(), id, req.Variables)
+	preview, svcErr := h.notifService.PreviewTemplate(r.Context(), id, req.Locale, req.Variables)
 	if svcErr != nil {
 		response.Error(w, svcErr)
 		return
@@ -241,6 +260,56 @@ func (h *NotificationHandler) PreviewTemplate(w http.ResponseWriter, r *http.Req
 	response.OK(w, preview)
 }
 
+// ListTemplateVersions lists the versions of a template.
+// GET /v1/templates/{id}/versions
+func (h *NotificationHandler) ListTemplateVersions(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if id == "" {
+		response.Error(w, errors.BadRequest("template id is required"))
+		return
+	}
+
+	versions, svcErr := h.notifService.ListTemplateVersions(r.Context(), id)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, versions)
+}
+
+// RollbackTemplate restores the content of an earlier template version.
+// POST /v1/templates/{id}/rollback
+func (h *NotificationHandler) RollbackTemplate(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if id == "" {
+		response.Error(w, errors.BadRequest("template id is required"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+
+	req, err := model.ParseInto[models.RollbackTemplateRequest](body)
+	if err != nil {
+		response.Error(w, errors.Validation(err.Error()))
+		return
+	}
+
+	template, svcErr := h.notifService.RollbackTemplate(r.Context(), id, req.Version)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, template)
+}
+
 // GetStats retrieves notification statistics.
 // GET /admin/notifications/stats
 func (h *NotificationHandler) GetStats(w http.ResponseWriter, r *http.Request) {
@@ -271,6 +340,44 @@ func (h *NotificationHandler) ReplayNotification(w http.ResponseWriter, r *http.
 	response.NoContent(w)
 }
 
+// ListDeadLetters lists notifications whose delivery was given up.
+// GET /admin/notifications/dead-letter
+// Query parameters: channel, page, per_page.
+func (h *NotificationHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	var channel *models.NotificationChannel
+	if ch := r.URL.Query().Get("channel"); ch != "" {
+		c := models.NotificationChannel(ch)
+		channel = &c
+	}
+
+	params := pagination.FromRequest(r)
+	notifications, total, svcErr := h.notifService.ListDeadLetters(r.Context(), channel, params.PerPage, params.Offset)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.Paginated(w, notifications, params.Page, params.PerPage, total)
+}
+
+// RequeueDeadLetter queues a dead-lettered notification for delivery again.
+// POST /admin/notifications/{id}/requeue
+func (h *NotificationHandler) RequeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if id == "" {
+		response.Error(w, errors.BadRequest("notification id is required"))
+		return
+	}
+
+	if svcErr := h.notifService.RequeueDeadLetter(r.Context(), id); svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.NoContent(w)
+}
+
 // Health check endpoint.
 // GET /health
 func (h *NotificationHandler) Health(w http.ResponseWriter, r *http.Request) {
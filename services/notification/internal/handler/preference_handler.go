@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/vnykmshr/gopantic/pkg/model"
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// GetPreferences returns the authenticated user's notification preferences
+// and quiet hours.
+// GET /api/v1/notifications/me/preferences
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	settings, svcErr := h.notifService.GetSettings(r.Context(), userID)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, settings)
+}
+
+// UpdatePreferences turns notification types on or off per channel.
+// PUT /api/v1/notifications/me/preferences
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+
+	req, err := model.ParseInto[models.UpdatePreferencesRequest](body)
+	if err != nil {
+		response.Error(w, errors.Validation(err.Error()))
+		return
+	}
+
+	settings, svcErr := h.notifService.UpdatePreferences(r.Context(), userID, &req)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, settings)
+}
+
+// SetQuietHours sets the authenticated user's quiet hours.
+// PUT /api/v1/notifications/me/preferences/quiet-hours
+func (h *NotificationHandler) SetQuietHours(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+
+	req, err := model.ParseInto[models.QuietHours](body)
+	if err != nil {
+		response.Error(w, errors.Validation(err.Error()))
+		return
+	}
+
+	quiet, svcErr := h.notifService.SetQuietHours(r.Context(), userID, &req)
+	if svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.OK(w, quiet)
+}
+
+// ClearQuietHours removes the authenticated user's quiet hours.
+// DELETE /api/v1/notifications/me/preferences/quiet-hours
+func (h *NotificationHandler) ClearQuietHours(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	if svcErr := h.notifService.ClearQuietHours(r.Context(), userID); svcErr != nil {
+		response.Error(w, svcErr)
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/metrics"
 	"github.com/vnykmshr/nivo/shared/middleware"
 )
@@ -10,13 +11,16 @@ import (
 // Router handles HTTP routing for the notification service.
 type Router struct {
 	handler *NotificationHandler
+	jwtKeys sharedjwt.KeySource
 	metrics *metrics.Collector
 }
 
-// NewRouter creates a new router.
-func NewRouter(handler *NotificationHandler) *Router {
+// NewRouter creates a new router. Users' inbox and preference requests are
+// authenticated with tokens verified against jwtKeys.
+func NewRouter(handler *NotificationHandler, jwtKeys sharedjwt.KeySource) *Router {
 	return &Router{
 		handler: handler,
+		jwtKeys: jwtKeys,
 		metrics: metrics.NewCollector("notification"),
 	}
 }
@@ -37,16 +41,37 @@ func (ro *Router) SetupRoutes() http.Handler {
 	mux.HandleFunc("GET /v1/notifications/{id}", ro.handler.GetNotification)
 	mux.HandleFunc("GET /v1/notifications", ro.handler.ListNotifications)
 
+	// Delivery receipts from providers (authenticated by the provider's signature)
+	mux.HandleFunc("POST /v1/notifications/receipts/{provider}", ro.handler.ReceiveReceipt)
+
+	// In-app inbox of the authenticated user (reached through the gateway)
+	authMiddleware := middleware.Auth(middleware.AuthConfig{Keys: ro.jwtKeys})
+	mux.Handle("GET /api/v1/notifications/me", authMiddleware(http.HandlerFunc(ro.handler.ListInbox)))
+	mux.Handle("GET /api/v1/notifications/me/unread-count", authMiddleware(http.HandlerFunc(ro.handler.GetUnreadCount)))
+	mux.Handle("POST /api/v1/notifications/me/read-all", authMiddleware(http.HandlerFunc(ro.handler.MarkAllRead)))
+	mux.Handle("POST /api/v1/notifications/me/{id}/read", authMiddleware(http.HandlerFunc(ro.handler.MarkRead)))
+	mux.Handle("POST /api/v1/notifications/me/{id}/archive", authMiddleware(http.HandlerFunc(ro.handler.ArchiveNotification)))
+
+	// Notification preferences and quiet hours of the authenticated user
+	mux.Handle("GET /api/v1/notifications/me/preferences", authMiddleware(http.HandlerFunc(ro.handler.GetPreferences)))
+	mux.Handle("PUT /api/v1/notifications/me/preferences", authMiddleware(http.HandlerFunc(ro.handler.UpdatePreferences)))
+	mux.Handle("PUT /api/v1/notifications/me/preferences/quiet-hours", authMiddleware(http.HandlerFunc(ro.handler.SetQuietHours)))
+	mux.Handle("DELETE /api/v1/notifications/me/preferences/quiet-hours", authMiddleware(http.HandlerFunc(ro.handler.ClearQuietHours)))
+
 	// Template endpoints
 	mux.HandleFunc("POST /v1/templates", ro.handler.CreateTemplate)
 	mux.HandleFunc("GET /v1/templates/{id}", ro.handler.GetTemplate)
 	mux.HandleFunc("GET /v1/templates", ro.handler.ListTemplates)
 	mux.HandleFunc("PUT /v1/templates/{id}", ro.handler.UpdateTemplate)
 	mux.HandleFunc("POST /v1/templates/{id}/preview", ro.handler.PreviewTemplate)
+	mux.HandleFunc("GET /v1/templates/{id}/versions", ro.handler.ListTemplateVersions)
+	mux.HandleFunc("POST /v1/templates/{id}/rollback", ro.handler.RollbackTemplate)
 
 	// Admin endpoints (protected by RBAC in gateway)
 	mux.HandleFunc("GET /admin/notifications/stats", ro.handler.GetStats)
 	mux.HandleFunc("POST /admin/notifications/{id}/replay", ro.handler.ReplayNotification)
+	mux.HandleFunc("GET /admin/notifications/dead-letter", ro.handler.ListDeadLetters)
+	mux.HandleFunc("POST /admin/notifications/{id}/requeue", ro.handler.RequeueDeadLetter)
 
 	// Apply middleware chain
 	handler := ro.applyMiddleware(mux)
@@ -28,16 +28,33 @@ const (
 	TypeSecurityAlert    NotificationType = "security_alert"    // Security-related alert
 	TypeWalletAlert      NotificationType = "wallet_alert"      // Wallet-related alert
 	TypeSystemAlert      NotificationType = "system_alert"      // System notification
+	TypeKYCStatus        NotificationType = "kyc_status"        // KYC status change
+	TypeWalletCreated    NotificationType = "wallet_created"    // Wallet created
+	TypeWalletActivated  NotificationType = "wallet_activated"  // Wallet activated
+	TypeMarketing        NotificationType = "marketing"         // Marketing message
 )
 
+// NotificationTypes lists the known notification types, which users can set
+// preferences for.
+var NotificationTypes = []NotificationType{
+	TypeOTP, TypeTransactionAlert, TypeAccountAlert, TypeKYCUpdate, TypeKYCStatus,
+	TypeWelcome, TypeSecurityAlert, TypeWalletAlert, TypeWalletCreated,
+	TypeWalletActivated, TypeSystemAlert, TypeMarketing,
+}
+
+// NotificationChannels lists the delivery channels.
+var NotificationChannels = []NotificationChannel{ChannelSMS, ChannelEmail, ChannelPush, ChannelInApp}
+
 // NotificationStatus represents the delivery status of a notification.
 type NotificationStatus string
 
 const (
-	StatusQueued    NotificationStatus = "queued"    // Queued for delivery
-	StatusSent      NotificationStatus = "sent"      // Sent to provider
-	StatusDelivered NotificationStatus = "delivered" // Successfully delivered
-	StatusFailed    NotificationStatus = "failed"    // Delivery failed
+	StatusQueued     NotificationStatus = "queued"      // Queued for delivery
+	StatusSent       NotificationStatus = "sent"        // Sent to provider
+	StatusDelivered  NotificationStatus = "delivered"   // Successfully delivered
+	StatusFailed     NotificationStatus = "failed"      // Delivery failed
+	StatusSuppressed NotificationStatus = "suppressed"  // Not sent: the user opted out
+	StatusDeadLetter NotificationStatus = "dead_letter" // Given up after the last delivery attempt
 )
 
 // NotificationPriority represents the priority level of a notification.
@@ -52,27 +69,37 @@ const (
 
 // Notification represents a notification in the system.
 type Notification struct {
-	ID            string                 `json:"id" db:"id"`
-	UserID        *string                `json:"user_id,omitempty" db:"user_id"` // Null for system-wide notifications
-	Channel       NotificationChannel    `json:"channel" db:"channel"`
-	Type          NotificationType       `json:"type" db:"type"`
-	Priority      NotificationPriority   `json:"priority" db:"priority"`
-	Recipient     string                 `json:"recipient" db:"recipient"`       // Email address or phone number
-	Subject       string                 `json:"subject,omitempty" db:"subject"` // For email/push
-	Body          string                 `json:"body" db:"body"`
-	TemplateID    *string                `json:"template_id,omitempty" db:"template_id"`
-	Status        NotificationStatus     `json:"status" db:"status"`
-	CorrelationID *string                `json:"correlation_id,omitempty" db:"correlation_id"` // For idempotency
-	SourceService string                 `json:"source_service" db:"source_service"`
-	Metadata      map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
-	RetryCount    int                    `json:"retry_count" db:"retry_count"`
-	FailureReason *string                `json:"failure_reason,omitempty" db:"failure_reason"`
-	QueuedAt      models.Timestamp       `json:"queued_at" db:"queued_at"`
-	SentAt        *models.Timestamp      `json:"sent_at,omitempty" db:"sent_at"`
-	DeliveredAt   *models.Timestamp      `json:"delivered_at,omitempty" db:"delivered_at"`
-	FailedAt      *models.Timestamp      `json:"failed_at,omitempty" db:"failed_at"`
-	CreatedAt     models.Timestamp       `json:"created_at" db:"created_at"`
-	UpdatedAt     models.Timestamp       `json:"updated_at" db:"updated_at"`
+	ID                string                 `json:"id" db:"id"`
+	UserID            *string                `json:"user_id,omitempty" db:"user_id"` // Null for system-wide notifications
+	Channel           NotificationChannel    `json:"channel" db:"channel"`
+	Type              NotificationType       `json:"type" db:"type"`
+	Priority          NotificationPriority   `json:"priority" db:"priority"`
+	Recipient         string                 `json:"recipient" db:"recipient"`       // Email address or phone number
+	Subject           string                 `json:"subject,omitempty" db:"subject"` // For email/push
+	Body              string                 `json:"body" db:"body"`
+	HTMLBody          string                 `json:"html_body,omitempty" db:"html_body"` // Email only: HTML alternative to the plain-text body
+	TemplateID        *string                `json:"template_id,omitempty" db:"template_id"`
+	TemplateVersion   *int                   `json:"template_version,omitempty" db:"template_version"` // Version of the template rendered
+	Status            NotificationStatus     `json:"status" db:"status"`
+	CorrelationID     *string                `json:"correlation_id,omitempty" db:"correlation_id"` // For idempotency
+	SourceService     string                 `json:"source_service" db:"source_service"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
+	RetryCount        int                    `json:"retry_count" db:"retry_count"`
+	FailureReason     *string                `json:"failure_reason,omitempty" db:"failure_reason"`
+	Provider          *string                `json:"provider,omitempty" db:"provider"`                       // Provider that accepted the notification
+	ProviderMessageID *string                `json:"provider_message_id,omitempty" db:"provider_message_id"` // The provider's ID, matched against delivery receipts
+	QueuedAt          models.Timestamp       `json:"queued_at" db:"queued_at"`
+	SentAt            *models.Timestamp      `json:"sent_at,omitempty" db:"sent_at"`
+	DeliveredAt       *models.Timestamp      `json:"delivered_at,omitempty" db:"delivered_at"`
+	FailedAt          *models.Timestamp      `json:"failed_at,omitempty" db:"failed_at"`
+	ReadAt            *models.Timestamp      `json:"read_at,omitempty" db:"read_at"`                         // In-app only: when the user read it
+	ArchivedAt        *models.Timestamp      `json:"archived_at,omitempty" db:"archived_at"`                 // In-app only: when the user archived it
+	Decision          *PreferenceDecision    `json:"preference_decision,omitempty" db:"preference_decision"` // How the user's preferences applied; null without a user
+	DeliverAfter      *models.Timestamp      `json:"deliver_after,omitempty" db:"deliver_after"`             // Deferred by quiet hours until then
+	NextAttemptAt     models.Timestamp       `json:"next_attempt_at" db:"next_attempt_at"`                   // When a queued notification is next due for delivery
+	DeadLetteredAt    *models.Timestamp      `json:"dead_lettered_at,omitempty" db:"dead_lettered_at"`
+	CreatedAt         models.Timestamp       `json:"created_at" db:"created_at"`
+	UpdatedAt         models.Timestamp       `json:"updated_at" db:"updated_at"`
 }
 
 // IsQueued returns true if the notification is queued.
@@ -95,6 +122,11 @@ func (n *Notification) IsFailed() bool {
 	return n.Status == StatusFailed
 }
 
+// IsDeadLettered returns true if delivery was given up.
+func (n *Notification) IsDeadLettered() bool {
+	return n.Status == StatusDeadLetter
+}
+
 // IsCritical returns true if the notification is critical priority.
 func (n *Notification) IsCritical() bool {
 	return n.Priority == PriorityCritical
@@ -108,6 +140,7 @@ type SendNotificationRequest struct {
 	Priority      NotificationPriority   `json:"priority,omitempty" validate:"omitempty,oneof=critical high normal low"`
 	Recipient     string                 `json:"recipient" validate:"required"`
 	TemplateID    *string                `json:"template_id,omitempty" validate:"omitempty,uuid"`
+	Locale        string                 `json:"locale,omitempty"` // Template variant to render, e.g. hi-IN; falls back to the language, then English
 	Subject       string                 `json:"subject,omitempty" validate:"omitempty,max=200"`
 	Body          string                 `json:"body,omitempty" validate:"omitempty,max=5000"`
 	Variables     map[string]interface{} `json:"variables,omitempty"`
@@ -129,9 +162,11 @@ func (r *SendNotificationRequest) GetMetadata() (map[string]interface{}, error)
 
 // SendNotificationResponse represents the response after sending a notification.
 type SendNotificationResponse struct {
-	NotificationID string             `json:"notification_id"`
-	Status         NotificationStatus `json:"status"`
-	QueuedAt       models.Timestamp   `json:"queued_at"`
+	NotificationID string              `json:"notification_id"`
+	Status         NotificationStatus  `json:"status"`
+	Decision       *PreferenceDecision `json:"preference_decision,omitempty"`
+	DeliverAfter   *models.Timestamp   `json:"deliver_after,omitempty"`
+	QueuedAt       models.Timestamp    `json:"queued_at"`
 }
 
 // ListNotificationsRequest represents a request to list notifications with filters.
@@ -155,6 +190,43 @@ type ListNotificationsResponse struct {
 	Offset        int             `json:"offset"`
 }
 
+// InboxFilter selects the items of a user's in-app inbox.
+type InboxFilter string
+
+const (
+	InboxAll      InboxFilter = ""         // Unread and read items that are not archived
+	InboxUnread   InboxFilter = "unread"   // Items not yet read
+	InboxRead     InboxFilter = "read"     // Read items that are not archived
+	InboxArchived InboxFilter = "archived" // Archived items
+)
+
+// IsValid returns true if the filter is known.
+func (f InboxFilter) IsValid() bool {
+	switch f {
+	case InboxAll, InboxUnread, InboxRead, InboxArchived:
+		return true
+	}
+	return false
+}
+
+// InboxQuery selects a page of a user's in-app inbox.
+type InboxQuery struct {
+	UserID string
+	Filter InboxFilter
+	Limit  int
+	Offset int
+}
+
+// InboxUnreadCount is the number of unread items in a user's in-app inbox.
+type InboxUnreadCount struct {
+	Unread int64 `json:"unread"`
+}
+
+// InboxReadAllResult reports how many inbox items were marked read.
+type InboxReadAllResult struct {
+	Marked int64 `json:"marked"`
+}
+
 // NotificationStats represents statistics for notifications.
 type NotificationStats struct {
 	TotalNotifications int64                       `json:"total_notifications"`
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// PreferenceDecision records how a user's preferences applied to a notification.
+type PreferenceDecision string
+
+const (
+	DecisionAllowed    PreferenceDecision = "allowed"    // Preferences allow it now
+	DecisionDeferred   PreferenceDecision = "deferred"   // Held until the user's quiet hours end
+	DecisionSuppressed PreferenceDecision = "suppressed" // The user opted out; not sent
+	DecisionMandatory  PreferenceDecision = "mandatory"  // Critical, sent although the user opted out
+)
+
+// NotificationPreference is whether a user receives notifications of a type
+// on a channel. Without a stored preference, the user receives them.
+type NotificationPreference struct {
+	Type    NotificationType    `json:"type" validate:"required"`
+	Channel NotificationChannel `json:"channel" validate:"required,oneof=sms email push in_app"`
+	Enabled bool                `json:"enabled"`
+}
+
+// QuietHours is a daily window, in the user's timezone, during which normal
+// and low priority notifications are held back. A window whose start is after
+// its end runs past midnight.
+type QuietHours struct {
+	Start     string            `json:"start" validate:"required"`    // HH:MM
+	End       string            `json:"end" validate:"required"`      // HH:MM
+	Timezone  string            `json:"timezone" validate:"required"` // IANA name, e.g. Asia/Kolkata
+	UpdatedAt *models.Timestamp `json:"updated_at,omitempty"`
+}
+
+// Validate checks the window's times and timezone.
+func (q *QuietHours) Validate() error {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("start and end must differ")
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", q.Timezone)
+	}
+	return nil
+}
+
+// EndsAt returns when the quiet hours containing t end, or false if t is
+// outside quiet hours. The window must be valid.
+func (q *QuietHours) EndsAt(t time.Time) (time.Time, bool) {
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, _ := parseClock(q.Start)
+	end, _ := parseClock(q.End)
+
+	local := t.In(loc)
+	now := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	endToday := time.Date(local.Year(), local.Month(), local.Day(), int(end/time.Hour), int(end%time.Hour/time.Minute), 0, 0, loc)
+
+	switch {
+	case start < end && now >= start && now < end:
+		return endToday, true
+	case start > end && now < end:
+		return endToday, true
+	case start > end && now >= start:
+		return endToday.AddDate(0, 0, 1), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// parseClock parses an HH:MM time of day into its offset from midnight.
+func parseClock(clock string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", clock)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// NotificationSettings are a user's preferences for every type and channel,
+// and their quiet hours if set.
+type NotificationSettings struct {
+	Preferences []NotificationPreference `json:"preferences"`
+	QuietHours  *QuietHours              `json:"quiet_hours"`
+}
+
+// UpdatePreferencesRequest changes some of a user's preferences.
+type UpdatePreferencesRequest struct {
+	Preferences []NotificationPreference `json:"preferences" validate:"required,min=1,dive"`
+}
@@ -2,30 +2,76 @@ package models
 
 import (
 	"encoding/json"
+	"regexp"
+	"strings"
 
 	"github.com/vnykmshr/nivo/shared/models"
 )
 
-// NotificationTemplate represents a notification template with variable substitution.
+// DefaultLocale is the locale every template has a variant in, used when a
+// notification's locale has none.
+const DefaultLocale = "en"
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// NormalizeLocale lower-cases a BCP 47 style locale (hi-IN, hi_IN) to hi-in and
+// reports whether it is well formed.
+func NormalizeLocale(locale string) (string, bool) {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	return locale, localePattern.MatchString(locale)
+}
+
+// LocaleFallbacks returns the locales to look for a template variant in, most
+// specific first: hi-in, hi, then the default locale.
+func LocaleFallbacks(locale string) []string {
+	locales := make([]string, 0, 3)
+	if normalized, ok := NormalizeLocale(locale); ok {
+		locales = append(locales, normalized)
+		if language, _, found := strings.Cut(normalized, "-"); found {
+			locales = append(locales, language)
+		}
+	}
+	if len(locales) == 0 || locales[len(locales)-1] != DefaultLocale {
+		locales = append(locales, DefaultLocale)
+	}
+	return locales
+}
+
+// NotificationTemplate represents a notification template, written in Go's
+// template language. A template has one variant per locale, sharing its name.
 type NotificationTemplate struct {
-	ID              string              `json:"id" db:"id"`
-	Name            string              `json:"name" db:"name"` // Unique identifier (e.g., "otp_sms", "transaction_alert_email")
-	Channel         NotificationChannel `json:"channel" db:"channel"`
-	SubjectTemplate string              `json:"subject_template,omitempty" db:"subject_template"` // For email/push
-	BodyTemplate    string              `json:"body_template" db:"body_template"`
-	Version         int                 `json:"version" db:"version"`
-	Metadata        map[string]string   `json:"metadata,omitempty" db:"metadata"`
-	CreatedAt       models.Timestamp    `json:"created_at" db:"created_at"`
-	UpdatedAt       models.Timestamp    `json:"updated_at" db:"updated_at"`
+	ID               string              `json:"id" db:"id"`
+	Name             string              `json:"name" db:"name"` // Identifier shared by its locale variants (e.g., "otp_sms", "transaction_alert_email")
+	Locale           string              `json:"locale" db:"locale"`
+	Channel          NotificationChannel `json:"channel" db:"channel"`
+	SubjectTemplate  string              `json:"subject_template,omitempty" db:"subject_template"`     // For email/push
+	BodyTemplate     string              `json:"body_template" db:"body_template"`                     // Plain text
+	HTMLBodyTemplate string              `json:"html_body_template,omitempty" db:"html_body_template"` // Email only, sent alongside the plain text
+	Version          int                 `json:"version" db:"version"`
+	Metadata         map[string]string   `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt        models.Timestamp    `json:"created_at" db:"created_at"`
+	UpdatedAt        models.Timestamp    `json:"updated_at" db:"updated_at"`
+}
+
+// TemplateVersion is the content of a template at one of its versions.
+type TemplateVersion struct {
+	TemplateID       string           `json:"template_id"`
+	Version          int              `json:"version"`
+	SubjectTemplate  string           `json:"subject_template,omitempty"`
+	BodyTemplate     string           `json:"body_template"`
+	HTMLBodyTemplate string           `json:"html_body_template,omitempty"`
+	CreatedAt        models.Timestamp `json:"created_at"`
 }
 
 // CreateTemplateRequest represents a request to create a notification template.
 type CreateTemplateRequest struct {
-	Name            string              `json:"name" validate:"required,min=3,max=100"`
-	Channel         NotificationChannel `json:"channel" validate:"required,oneof=sms email push in_app"`
-	SubjectTemplate string              `json:"subject_template,omitempty" validate:"omitempty,max=200"`
-	BodyTemplate    string              `json:"body_template" validate:"required,max=5000"`
-	MetadataRaw     json.RawMessage     `json:"metadata,omitempty"`
+	Name             string              `json:"name" validate:"required,min=3,max=100"`
+	Locale           string              `json:"locale,omitempty"` // Defaults to DefaultLocale
+	Channel          NotificationChannel `json:"channel" validate:"required,oneof=sms email push in_app"`
+	SubjectTemplate  string              `json:"subject_template,omitempty" validate:"omitempty,max=200"`
+	BodyTemplate     string              `json:"body_template" validate:"required,max=5000"`
+	HTMLBodyTemplate string              `json:"html_body_template,omitempty" validate:"omitempty,max=50000"`
+	MetadataRaw      json.RawMessage     `json:"metadata,omitempty"`
 }
 
 // GetMetadata parses and returns the metadata map.
@@ -42,9 +88,21 @@ func (r *CreateTemplateRequest) GetMetadata() (map[string]string, error) {
 
 // UpdateTemplateRequest represents a request to update a notification template.
 type UpdateTemplateRequest struct {
-	SubjectTemplate *string         `json:"subject_template,omitempty" validate:"omitempty,max=200"`
-	BodyTemplate    *string         `json:"body_template,omitempty" validate:"omitempty,max=5000"`
-	MetadataRaw     json.RawMessage `json:"metadata,omitempty"`
+	SubjectTemplate  *string         `json:"subject_template,omitempty" validate:"omitempty,max=200"`
+	BodyTemplate     *string         `json:"body_template,omitempty" validate:"omitempty,max=5000"`
+	HTMLBodyTemplate *string         `json:"html_body_template,omitempty" validate:"omitempty,max=50000"`
+	MetadataRaw      json.RawMessage `json:"metadata,omitempty"`
+}
+
+// ChangesContent reports whether the update changes the template's content,
+// which makes a new version.
+func (r *UpdateTemplateRequest) ChangesContent() bool {
+	return r.SubjectTemplate != nil || r.BodyTemplate != nil || r.HTMLBodyTemplate != nil
+}
+
+// RollbackTemplateRequest restores the content of an earlier template version.
+type RollbackTemplateRequest struct {
+	Version int `json:"version" validate:"required,min=1"`
 }
 
 // GetMetadata parses and returns the metadata map.
@@ -62,12 +120,16 @@ func (r *UpdateTemplateRequest) GetMetadata() (map[string]string, error) {
 // PreviewTemplateRequest represents a request to preview a template with variables.
 type PreviewTemplateRequest struct {
 	Variables map[string]interface{} `json:"variables"`
+	Locale    string                 `json:"locale,omitempty"` // Variant previewed when the template is given by name
 }
 
 // PreviewTemplateResponse represents the rendered template preview.
 type PreviewTemplateResponse struct {
 	Subject      string           `json:"subject,omitempty"`
 	Body         string           `json:"body"`
+	HTMLBody     string           `json:"html_body,omitempty"`
+	Locale       string           `json:"locale"`
+	Version      int              `json:"version"`
 	RenderedAt   models.Timestamp `json:"rendered_at"`
 	VariableUsed []string         `json:"variables_used"` // List of variables that were substituted
 }
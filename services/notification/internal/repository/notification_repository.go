@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/vnykmshr/nivo/services/notification/internal/models"
 	"github.com/vnykmshr/nivo/shared/errors"
@@ -37,10 +38,11 @@ func (r *NotificationRepository) Create(ctx context.Context, notif *models.Notif
 		INSERT INTO notifications (
 			user_id, channel, type, priority, recipient, subject, body,
 			template_id, status, correlation_id, source_service, metadata,
-			retry_count, queued_at
+			retry_count, queued_at, preference_decision, deliver_after,
+			html_body, template_version, next_attempt_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-		RETURNING id, created_at, updated_at
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, COALESCE($16, NOW()))
+		RETURNING id, next_attempt_at, created_at, updated_at
 	`
 
 	err = r.db.QueryRowContext(ctx, query,
@@ -58,7 +60,11 @@ func (r *NotificationRepository) Create(ctx context.Context, notif *models.Notif
 		metadataJSON,
 		notif.RetryCount,
 		notif.QueuedAt,
-	).Scan(&notif.ID, &notif.CreatedAt, &notif.UpdatedAt)
+		notif.Decision,
+		notif.DeliverAfter,
+		notif.HTMLBody,
+		notif.TemplateVersion,
+	).Scan(&notif.ID, &notif.NextAttemptAt, &notif.CreatedAt, &notif.UpdatedAt)
 
 	if err != nil {
 		// Check for duplicate correlation_id (idempotency)
@@ -78,9 +84,11 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*model
 
 	query := `
 		SELECT id, user_id, channel, type, priority, recipient, subject, body,
-		       template_id, status, correlation_id, source_service, metadata,
+		       html_body, template_id, template_version, status, correlation_id, source_service, metadata,
 		       retry_count, failure_reason, queued_at, sent_at, delivered_at,
-		       failed_at, created_at, updated_at
+		       failed_at, provider, provider_message_id, read_at, archived_at,
+		       preference_decision, deliver_after, next_attempt_at, dead_lettered_at,
+		       created_at, updated_at
 		FROM notifications
 		WHERE id = $1
 	`
@@ -94,7 +102,9 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*model
 		&notif.Recipient,
 		&notif.Subject,
 		&notif.Body,
+		&notif.HTMLBody,
 		&notif.TemplateID,
+		&notif.TemplateVersion,
 		&notif.Status,
 		&notif.CorrelationID,
 		&notif.SourceService,
@@ -105,6 +115,14 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*model
 		&notif.SentAt,
 		&notif.DeliveredAt,
 		&notif.FailedAt,
+		&notif.Provider,
+		&notif.ProviderMessageID,
+		&notif.ReadAt,
+		&notif.ArchivedAt,
+		&notif.Decision,
+		&notif.DeliverAfter,
+		&notif.NextAttemptAt,
+		&notif.DeadLetteredAt,
 		&notif.CreatedAt,
 		&notif.UpdatedAt,
 	)
@@ -133,9 +151,11 @@ func (r *NotificationRepository) GetByCorrelationID(ctx context.Context, correla
 
 	query := `
 		SELECT id, user_id, channel, type, priority, recipient, subject, body,
-		       template_id, status, correlation_id, source_service, metadata,
+		       html_body, template_id, template_version, status, correlation_id, source_service, metadata,
 		       retry_count, failure_reason, queued_at, sent_at, delivered_at,
-		       failed_at, created_at, updated_at
+		       failed_at, provider, provider_message_id, read_at, archived_at,
+		       preference_decision, deliver_after, next_attempt_at, dead_lettered_at,
+		       created_at, updated_at
 		FROM notifications
 		WHERE correlation_id = $1
 		LIMIT 1
@@ -150,7 +170,9 @@ func (r *NotificationRepository) GetByCorrelationID(ctx context.Context, correla
 		&notif.Recipient,
 		&notif.Subject,
 		&notif.Body,
+		&notif.HTMLBody,
 		&notif.TemplateID,
+		&notif.TemplateVersion,
 		&notif.Status,
 		&notif.CorrelationID,
 		&notif.SourceService,
@@ -161,6 +183,14 @@ func (r *NotificationRepository) GetByCorrelationID(ctx context.Context, correla
 		&notif.SentAt,
 		&notif.DeliveredAt,
 		&notif.FailedAt,
+		&notif.Provider,
+		&notif.ProviderMessageID,
+		&notif.ReadAt,
+		&notif.ArchivedAt,
+		&notif.Decision,
+		&notif.DeliverAfter,
+		&notif.NextAttemptAt,
+		&notif.DeadLetteredAt,
 		&notif.CreatedAt,
 		&notif.UpdatedAt,
 	)
@@ -253,9 +283,11 @@ func (r *NotificationRepository) List(ctx context.Context, req *models.ListNotif
 	//nolint:gosec // whereClause is built from controlled filter values, not user input
 	query := fmt.Sprintf(`
 		SELECT id, user_id, channel, type, priority, recipient, subject, body,
-		       template_id, status, correlation_id, source_service, metadata,
+		       html_body, template_id, template_version, status, correlation_id, source_service, metadata,
 		       retry_count, failure_reason, queued_at, sent_at, delivered_at,
-		       failed_at, created_at, updated_at
+		       failed_at, provider, provider_message_id, read_at, archived_at,
+		       preference_decision, deliver_after, next_attempt_at, dead_lettered_at,
+		       created_at, updated_at
 		FROM notifications
 		%s
 		ORDER BY created_at DESC
@@ -286,7 +318,9 @@ func (r *NotificationRepository) List(ctx context.Context, req *models.ListNotif
 			&notif.Recipient,
 			&notif.Subject,
 			&notif.Body,
+			&notif.HTMLBody,
 			&notif.TemplateID,
+			&notif.TemplateVersion,
 			&notif.Status,
 			&notif.CorrelationID,
 			&notif.SourceService,
@@ -297,6 +331,14 @@ func (r *NotificationRepository) List(ctx context.Context, req *models.ListNotif
 			&notif.SentAt,
 			&notif.DeliveredAt,
 			&notif.FailedAt,
+			&notif.Provider,
+			&notif.ProviderMessageID,
+			&notif.ReadAt,
+			&notif.ArchivedAt,
+			&notif.Decision,
+			&notif.DeliverAfter,
+			&notif.NextAttemptAt,
+			&notif.DeadLetteredAt,
 			&notif.CreatedAt,
 			&notif.UpdatedAt,
 		); err != nil {
@@ -329,6 +371,7 @@ func (r *NotificationRepository) UpdateStatus(ctx context.Context, id string, st
 		    sent_at = CASE WHEN $1::text = 'sent' AND sent_at IS NULL THEN NOW() ELSE sent_at END,
 		    delivered_at = CASE WHEN $1::text = 'delivered' AND delivered_at IS NULL THEN NOW() ELSE delivered_at END,
 		    failed_at = CASE WHEN $1::text = 'failed' AND failed_at IS NULL THEN NOW() ELSE failed_at END,
+		    locked_until = NULL,
 		    updated_at = NOW()
 		WHERE id = $3
 	`
@@ -350,18 +393,76 @@ func (r *NotificationRepository) UpdateStatus(ctx context.Context, id string, st
 	return nil
 }
 
-// IncrementRetryCount increments the retry count for a notification.
-func (r *NotificationRepository) IncrementRetryCount(ctx context.Context, id string) *errors.Error {
+// ScheduleRetry records a failed delivery attempt of a queued notification
+// and releases its claim until the next attempt is due.
+func (r *NotificationRepository) ScheduleRetry(ctx context.Context, id string, nextAttemptAt time.Time, failureReason string) *errors.Error {
 	query := `
 		UPDATE notifications
 		SET retry_count = retry_count + 1,
+		    failure_reason = $2,
+		    next_attempt_at = $3,
+		    locked_until = NULL,
+		    updated_at = NOW()
+		WHERE id = $1 AND status = 'queued'
+	`
+
+	return r.updateQueued(ctx, query, "failed to schedule notification retry", id, failureReason, nextAttemptAt)
+}
+
+// DeadLetter gives up delivering a queued notification.
+func (r *NotificationRepository) DeadLetter(ctx context.Context, id string, failureReason string) *errors.Error {
+	query := `
+		UPDATE notifications
+		SET status = 'dead_letter',
+		    failure_reason = $2,
+		    failed_at = NOW(),
+		    dead_lettered_at = NOW(),
+		    locked_until = NULL,
+		    updated_at = NOW()
+		WHERE id = $1 AND status = 'queued'
+	`
+
+	return r.updateQueued(ctx, query, "failed to dead-letter notification", id, failureReason)
+}
+
+// updateQueued runs an update of a queued notification, reporting a
+// notification that is missing or no longer queued as not found.
+func (r *NotificationRepository) updateQueued(ctx context.Context, query, message, id string, args ...any) *errors.Error {
+	result, err := r.db.ExecContext(ctx, query, append([]any{id}, args...)...)
+	if err != nil {
+		return errors.DatabaseWrap(err, message)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NotFound("queued notification " + id)
+	}
+
+	return nil
+}
+
+// Requeue queues a notification for immediate delivery with a fresh set of
+// attempts.
+func (r *NotificationRepository) Requeue(ctx context.Context, id string) *errors.Error {
+	query := `
+		UPDATE notifications
+		SET status = 'queued',
+		    retry_count = 0,
+		    failure_reason = NULL,
+		    next_attempt_at = NOW(),
+		    locked_until = NULL,
+		    dead_lettered_at = NULL,
 		    updated_at = NOW()
 		WHERE id = $1
 	`
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
-		return errors.DatabaseWrap(err, "failed to increment retry count")
+		return errors.DatabaseWrap(err, "failed to requeue notification")
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -376,33 +477,119 @@ func (r *NotificationRepository) IncrementRetryCount(ctx context.Context, id str
 	return nil
 }
 
-// GetQueuedNotifications retrieves queued notifications ordered by priority and creation time.
-func (r *NotificationRepository) GetQueuedNotifications(ctx context.Context, limit int) ([]*models.Notification, *errors.Error) {
-	if limit == 0 {
-		limit = 100 // Default batch size
+// MarkSent marks a notification as sent, recording the provider that accepted
+// it and the provider's message ID.
+func (r *NotificationRepository) MarkSent(ctx context.Context, id, provider, providerMessageID string) *errors.Error {
+	query := `
+		UPDATE notifications
+		SET status = 'sent',
+		    provider = $2,
+		    provider_message_id = $3,
+		    failure_reason = NULL,
+		    sent_at = NOW(),
+		    locked_until = NULL,
+		    updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, provider, providerMessageID)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to mark notification sent")
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NotFoundWithID("notification", id)
+	}
+
+	return nil
+}
+
+// ApplyReceipt moves the sent notification with the provider's message ID to
+// the receipt's final status. A repeated receipt, or one for a notification
+// that already has a final status, changes nothing.
+func (r *NotificationRepository) ApplyReceipt(ctx context.Context, provider, providerMessageID string, status models.NotificationStatus, failureReason *string) *errors.Error {
 	query := `
-		SELECT id, user_id, channel, type, priority, recipient, subject, body,
-		       template_id, status, correlation_id, source_service, metadata,
-		       retry_count, failure_reason, queued_at, sent_at, delivered_at,
-		       failed_at, created_at, updated_at
-		FROM notifications
-		WHERE status = 'queued'
-		ORDER BY
-		    CASE priority
-		        WHEN 'critical' THEN 1
-		        WHEN 'high' THEN 2
-		        WHEN 'normal' THEN 3
-		        WHEN 'low' THEN 4
-		    END,
-		    created_at ASC
-		LIMIT $1
+		UPDATE notifications
+		SET status = $3::text,
+		    failure_reason = $4,
+		    delivered_at = CASE WHEN $3::text = 'delivered' THEN NOW() ELSE delivered_at END,
+		    failed_at = CASE WHEN $3::text = 'failed' THEN NOW() ELSE failed_at END,
+		    updated_at = NOW()
+		WHERE provider = $1 AND provider_message_id = $2 AND status = 'sent'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, provider, providerMessageID, string(status), failureReason)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to apply delivery receipt")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to get rows affected")
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var exists bool
+	err = r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM notifications WHERE provider = $1 AND provider_message_id = $2)`,
+		provider, providerMessageID,
+	).Scan(&exists)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to look up notification by provider message ID")
+	}
+	if !exists {
+		return errors.NotFound("notification for provider message " + providerMessageID)
+	}
+
+	return nil
+}
+
+// ClaimQueued claims up to limit due notifications of a channel for delivery,
+// highest priority first. A claim lasts for the lease; notifications another
+// worker holds are skipped, so several replicas can claim concurrently.
+func (r *NotificationRepository) ClaimQueued(ctx context.Context, channel models.NotificationChannel, limit int, lease time.Duration) ([]*models.Notification, *errors.Error) {
+	if limit <= 0 {
+		return []*models.Notification{}, nil
+	}
+
+	query := `
+		UPDATE notifications
+		SET locked_until = NOW() + make_interval(secs => $3),
+		    updated_at = NOW()
+		WHERE id IN (
+		    SELECT id
+		    FROM notifications
+		    WHERE status = 'queued' AND channel = $1 AND next_attempt_at <= NOW()
+		      AND (locked_until IS NULL OR locked_until <= NOW())
+		    ORDER BY
+		        CASE priority
+		            WHEN 'critical' THEN 1
+		            WHEN 'high' THEN 2
+		            WHEN 'normal' THEN 3
+		            WHEN 'low' THEN 4
+		        END,
+		        next_attempt_at ASC
+		    LIMIT $2
+		    FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, channel, type, priority, recipient, subject, body,
+		          html_body, template_id, template_version, status, correlation_id, source_service, metadata,
+		          retry_count, failure_reason, queued_at, sent_at, delivered_at,
+		          failed_at, provider, provider_message_id, read_at, archived_at,
+		          preference_decision, deliver_after, next_attempt_at, dead_lettered_at,
+		          created_at, updated_at
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.db.QueryContext(ctx, query, channel, limit, lease.Seconds())
 	if err != nil {
-		return nil, errors.DatabaseWrap(err, "failed to get queued notifications")
+		return nil, errors.DatabaseWrap(err, "failed to claim queued notifications")
 	}
 	defer func() {
 		_ = rows.Close()
@@ -422,7 +609,9 @@ func (r *NotificationRepository) GetQueuedNotifications(ctx context.Context, lim
 			&notif.Recipient,
 			&notif.Subject,
 			&notif.Body,
+			&notif.HTMLBody,
 			&notif.TemplateID,
+			&notif.TemplateVersion,
 			&notif.Status,
 			&notif.CorrelationID,
 			&notif.SourceService,
@@ -433,6 +622,14 @@ func (r *NotificationRepository) GetQueuedNotifications(ctx context.Context, lim
 			&notif.SentAt,
 			&notif.DeliveredAt,
 			&notif.FailedAt,
+			&notif.Provider,
+			&notif.ProviderMessageID,
+			&notif.ReadAt,
+			&notif.ArchivedAt,
+			&notif.Decision,
+			&notif.DeliverAfter,
+			&notif.NextAttemptAt,
+			&notif.DeadLetteredAt,
 			&notif.CreatedAt,
 			&notif.UpdatedAt,
 		); err != nil {
@@ -456,6 +653,215 @@ func (r *NotificationRepository) GetQueuedNotifications(ctx context.Context, lim
 	return notifications, nil
 }
 
+// inboxConditions returns the conditions selecting the items of a user's inbox
+// (its delivered in-app notifications) that match the filter. The user ID is $1.
+func inboxConditions(filter models.InboxFilter) string {
+	conditions := "user_id = $1 AND channel = 'in_app' AND status = 'delivered'"
+	switch filter {
+	case models.InboxUnread:
+		return conditions + " AND read_at IS NULL AND archived_at IS NULL"
+	case models.InboxRead:
+		return conditions + " AND read_at IS NOT NULL AND archived_at IS NULL"
+	case models.InboxArchived:
+		return conditions + " AND archived_at IS NOT NULL"
+	default:
+		return conditions + " AND archived_at IS NULL"
+	}
+}
+
+// ListInbox retrieves a page of a user's in-app inbox, newest first.
+func (r *NotificationRepository) ListInbox(ctx context.Context, query *models.InboxQuery) ([]*models.Notification, int64, *errors.Error) {
+	conditions := inboxConditions(query.Filter)
+
+	var total int64
+	//nolint:gosec // conditions are chosen from fixed strings, not user input
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM notifications WHERE "+conditions, query.UserID).Scan(&total); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count inbox notifications")
+	}
+
+	//nolint:gosec // conditions are chosen from fixed strings, not user input
+	listQuery := fmt.Sprintf(`
+		SELECT id, user_id, channel, type, priority, recipient, subject, body,
+		       html_body, template_id, template_version, status, correlation_id, source_service, metadata,
+		       retry_count, failure_reason, queued_at, sent_at, delivered_at,
+		       failed_at, provider, provider_message_id, read_at, archived_at,
+		       preference_decision, deliver_after, next_attempt_at, dead_lettered_at,
+		       created_at, updated_at
+		FROM notifications
+		WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, conditions)
+
+	rows, err := r.db.QueryContext(ctx, listQuery, query.UserID, query.Limit, query.Offset)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list inbox notifications")
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	notifications := make([]*models.Notification, 0)
+	for rows.Next() {
+		notif := &models.Notification{}
+		var metadataJSON []byte
+
+		if err := rows.Scan(
+			&notif.ID,
+			&notif.UserID,
+			&notif.Channel,
+			&notif.Type,
+			&notif.Priority,
+			&notif.Recipient,
+			&notif.Subject,
+			&notif.Body,
+			&notif.HTMLBody,
+			&notif.TemplateID,
+			&notif.TemplateVersion,
+			&notif.Status,
+			&notif.CorrelationID,
+			&notif.SourceService,
+			&metadataJSON,
+			&notif.RetryCount,
+			&notif.FailureReason,
+			&notif.QueuedAt,
+			&notif.SentAt,
+			&notif.DeliveredAt,
+			&notif.FailedAt,
+			&notif.Provider,
+			&notif.ProviderMessageID,
+			&notif.ReadAt,
+			&notif.ArchivedAt,
+			&notif.Decision,
+			&notif.DeliverAfter,
+			&notif.NextAttemptAt,
+			&notif.DeadLetteredAt,
+			&notif.CreatedAt,
+			&notif.UpdatedAt,
+		); err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan notification")
+		}
+
+		// Unmarshal metadata
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &notif.Metadata); err != nil {
+				return nil, 0, errors.Internal("failed to unmarshal metadata")
+			}
+		}
+
+		notifications = append(notifications, notif)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "error iterating notifications")
+	}
+
+	return notifications, total, nil
+}
+
+// CountUnread counts the unread items in a user's in-app inbox.
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int64, *errors.Error) {
+	var count int64
+	//nolint:gosec // conditions are chosen from fixed strings, not user input
+	query := "SELECT COUNT(*) FROM notifications WHERE " + inboxConditions(models.InboxUnread)
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, errors.DatabaseWrap(err, "failed to count unread notifications")
+	}
+	return count, nil
+}
+
+// MarkRead marks an item of a user's in-app inbox read. Marking an item read
+// again keeps the time it was first read.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id string) *errors.Error {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW()),
+		    updated_at = NOW()
+		WHERE id = $2 AND user_id = $1 AND channel = 'in_app' AND status = 'delivered'
+	`
+
+	return r.updateInboxItem(ctx, query, userID, id)
+}
+
+// Archive archives an item of a user's in-app inbox, which also marks it read.
+func (r *NotificationRepository) Archive(ctx context.Context, userID, id string) *errors.Error {
+	//nolint:gosec // conditions are chosen from fixed strings, not user input
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW()),
+		    archived_at = COALESCE(archived_at, NOW()),
+		    updated_at = NOW()
+		WHERE id = $2 AND user_id = $1 AND channel = 'in_app' AND status = 'delivered'
+	`
+
+	return r.updateInboxItem(ctx, query, userID, id)
+}
+
+// updateInboxItem runs an update of one inbox item, reporting an item the user
+// does not have as not found.
+func (r *NotificationRepository) updateInboxItem(ctx context.Context, query, userID, id string) *errors.Error {
+	result, err := r.db.ExecContext(ctx, query, userID, id)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to update inbox notification")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NotFoundWithID("notification", id)
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread item of a user's in-app inbox read and
+// returns how many it marked.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, *errors.Error) {
+	//nolint:gosec // conditions are chosen from fixed strings, not user input
+	query := `
+		UPDATE notifications
+		SET read_at = NOW(),
+		    updated_at = NOW()
+		WHERE ` + inboxConditions(models.InboxUnread)
+
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, errors.DatabaseWrap(err, "failed to mark notifications read")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.DatabaseWrap(err, "failed to get rows affected")
+	}
+
+	return rowsAffected, nil
+}
+
+// PruneInbox deletes in-app inbox items read or archived before readBefore
+// and any created before createdBefore, returning how many it deleted.
+func (r *NotificationRepository) PruneInbox(ctx context.Context, readBefore, createdBefore time.Time) (int64, *errors.Error) {
+	query := `
+		DELETE FROM notifications
+		WHERE channel = 'in_app' AND status = 'delivered'
+		  AND (COALESCE(archived_at, read_at) < $1 OR created_at < $2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, readBefore, createdBefore)
+	if err != nil {
+		return 0, errors.DatabaseWrap(err, "failed to prune inbox notifications")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.DatabaseWrap(err, "failed to get rows affected")
+	}
+
+	return rowsAffected, nil
+}
+
 // GetStats retrieves notification statistics.
 func (r *NotificationRepository) GetStats(ctx context.Context) (*models.NotificationStats, *errors.Error) {
 	stats := &models.NotificationStats{
@@ -531,14 +937,14 @@ func (r *NotificationRepository) GetStats(ctx context.Context) (*models.Notifica
 
 	// Calculate success rate
 	delivered := stats.ByStatus[models.StatusDelivered]
-	failed := stats.ByStatus[models.StatusFailed]
+	failed := stats.ByStatus[models.StatusFailed] + stats.ByStatus[models.StatusDeadLetter]
 	if (delivered + failed) > 0 {
 		stats.SuccessRate = float64(delivered) / float64(delivered+failed) * 100
 	}
 
 	// Get average retries
 	var avgRetries sql.NullFloat64
-	retryQuery := "SELECT AVG(retry_count) FROM notifications WHERE status IN ('delivered', 'failed')"
+	retryQuery := "SELECT AVG(retry_count) FROM notifications WHERE status IN ('delivered', 'failed', 'dead_letter')"
 	if err := r.db.QueryRowContext(ctx, retryQuery).Scan(&avgRetries); err != nil {
 		return nil, errors.DatabaseWrap(err, "failed to get average retries")
 	}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// PreferenceRepository handles database operations for users' notification
+// preferences and quiet hours.
+type PreferenceRepository struct {
+	db *sql.DB
+}
+
+// NewPreferenceRepository creates a new preference repository.
+func NewPreferenceRepository(db *sql.DB) *PreferenceRepository {
+	return &PreferenceRepository{db: db}
+}
+
+// ListPreferences retrieves the preferences a user has stored.
+func (r *PreferenceRepository) ListPreferences(ctx context.Context, userID string) ([]models.NotificationPreference, *errors.Error) {
+	query := `
+		SELECT type, channel, enabled
+		FROM notification_preferences
+		WHERE user_id = $1
+		ORDER BY type, channel
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to list notification preferences")
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	preferences := make([]models.NotificationPreference, 0)
+	for rows.Next() {
+		var pref models.NotificationPreference
+		if err := rows.Scan(&pref.Type, &pref.Channel, &pref.Enabled); err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan notification preference")
+		}
+		preferences = append(preferences, pref)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating notification preferences")
+	}
+
+	return preferences, nil
+}
+
+// IsEnabled reports whether a user receives notifications of a type on a
+// channel, which they do unless they turned it off.
+func (r *PreferenceRepository) IsEnabled(ctx context.Context, userID string, notifType models.NotificationType, channel models.NotificationChannel) (bool, *errors.Error) {
+	query := `
+		SELECT enabled
+		FROM notification_preferences
+		WHERE user_id = $1 AND type = $2 AND channel = $3
+	`
+
+	var enabled bool
+	err := r.db.QueryRowContext(ctx, query, userID, notifType, channel).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return false, errors.DatabaseWrap(err, "failed to get notification preference")
+	}
+
+	return enabled, nil
+}
+
+// SavePreferences stores a user's preferences in one transaction, replacing
+// those they already had for the same type and channel.
+func (r *PreferenceRepository) SavePreferences(ctx context.Context, userID string, preferences []models.NotificationPreference) *errors.Error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query := `
+		INSERT INTO notification_preferences (user_id, type, channel, enabled)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, type, channel) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+	for _, pref := range preferences {
+		if _, err := tx.ExecContext(ctx, query, userID, pref.Type, pref.Channel, pref.Enabled); err != nil {
+			return errors.DatabaseWrap(err, "failed to save notification preference")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit notification preferences")
+	}
+	committed = true
+
+	return nil
+}
+
+// GetQuietHours retrieves a user's quiet hours.
+func (r *PreferenceRepository) GetQuietHours(ctx context.Context, userID string) (*models.QuietHours, *errors.Error) {
+	query := `
+		SELECT start_time, end_time, timezone, updated_at
+		FROM notification_quiet_hours
+		WHERE user_id = $1
+	`
+
+	quiet := &models.QuietHours{}
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&quiet.Start, &quiet.End, &quiet.Timezone, &quiet.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound("quiet hours")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get quiet hours")
+	}
+
+	return quiet, nil
+}
+
+// SetQuietHours stores a user's quiet hours, replacing any they had.
+func (r *PreferenceRepository) SetQuietHours(ctx context.Context, userID string, quiet *models.QuietHours) *errors.Error {
+	query := `
+		INSERT INTO notification_quiet_hours (user_id, start_time, end_time, timezone)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET start_time = EXCLUDED.start_time,
+		    end_time = EXCLUDED.end_time,
+		    timezone = EXCLUDED.timezone
+		RETURNING updated_at
+	`
+
+	if err := r.db.QueryRowContext(ctx, query, userID, quiet.Start, quiet.End, quiet.Timezone).Scan(&quiet.UpdatedAt); err != nil {
+		return errors.DatabaseWrap(err, "failed to save quiet hours")
+	}
+
+	return nil
+}
+
+// DeleteQuietHours removes a user's quiet hours.
+func (r *PreferenceRepository) DeleteQuietHours(ctx context.Context, userID string) *errors.Error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM notification_quiet_hours WHERE user_id = $1`, userID); err != nil {
+		return errors.DatabaseWrap(err, "failed to delete quiet hours")
+	}
+	return nil
+}
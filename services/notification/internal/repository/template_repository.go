@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/lib/pq"
 	"github.com/vnykmshr/nivo/services/notification/internal/models"
 	"github.com/vnykmshr/nivo/shared/errors"
 )
@@ -33,31 +34,55 @@ func (r *TemplateRepository) Create(ctx context.Context, template *models.Notifi
 		}
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
 	query := `
 		INSERT INTO notification_templates (
-			name, channel, subject_template, body_template, version, metadata
+			name, locale, channel, subject_template, body_template,
+			html_body_template, version, metadata
 		)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, created_at, updated_at
 	`
 
-	err = r.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		template.Name,
+		template.Locale,
 		template.Channel,
 		template.SubjectTemplate,
 		template.BodyTemplate,
+		template.HTMLBodyTemplate,
 		template.Version,
 		metadataJSON,
 	).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
 
 	if err != nil {
-		// Check for duplicate name
-		if strings.Contains(err.Error(), "notification_templates_name_key") {
-			return errors.Conflict("template with this name already exists")
+		// Check for duplicate name and locale
+		if strings.Contains(err.Error(), "notification_templates_name_locale_key") {
+			return errors.Conflict("template with this name and locale already exists")
 		}
 		return errors.DatabaseWrap(err, "failed to create template")
 	}
 
+	if err := recordVersion(ctx, tx, template.ID, template.Version); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit template")
+	}
+	committed = true
+
 	return nil
 }
 
@@ -67,8 +92,8 @@ func (r *TemplateRepository) GetByID(ctx context.Context, id string) (*models.No
 	var metadataJSON []byte
 
 	query := `
-		SELECT id, name, channel, subject_template, body_template, version,
-		       metadata, created_at, updated_at
+		SELECT id, name, locale, channel, subject_template, body_template,
+		       html_body_template, version, metadata, created_at, updated_at
 		FROM notification_templates
 		WHERE id = $1
 	`
@@ -76,9 +101,11 @@ func (r *TemplateRepository) GetByID(ctx context.Context, id string) (*models.No
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&template.ID,
 		&template.Name,
+		&template.Locale,
 		&template.Channel,
 		&template.SubjectTemplate,
 		&template.BodyTemplate,
+		&template.HTMLBodyTemplate,
 		&template.Version,
 		&metadataJSON,
 		&template.CreatedAt,
@@ -102,24 +129,29 @@ func (r *TemplateRepository) GetByID(ctx context.Context, id string) (*models.No
 	return template, nil
 }
 
-// GetByName retrieves a template by name.
-func (r *TemplateRepository) GetByName(ctx context.Context, name string) (*models.NotificationTemplate, *errors.Error) {
+// GetByName retrieves the variant of a template in the first of the locales
+// it has one in.
+func (r *TemplateRepository) GetByName(ctx context.Context, name string, locales []string) (*models.NotificationTemplate, *errors.Error) {
 	template := &models.NotificationTemplate{}
 	var metadataJSON []byte
 
 	query := `
-		SELECT id, name, channel, subject_template, body_template, version,
-		       metadata, created_at, updated_at
+		SELECT id, name, locale, channel, subject_template, body_template,
+		       html_body_template, version, metadata, created_at, updated_at
 		FROM notification_templates
-		WHERE name = $1
+		WHERE name = $1 AND locale = ANY($2)
+		ORDER BY array_position($2, locale)
+		LIMIT 1
 	`
 
-	err := r.db.QueryRowContext(ctx, query, name).Scan(
+	err := r.db.QueryRowContext(ctx, query, name, pq.Array(locales)).Scan(
 		&template.ID,
 		&template.Name,
+		&template.Locale,
 		&template.Channel,
 		&template.SubjectTemplate,
 		&template.BodyTemplate,
+		&template.HTMLBodyTemplate,
 		&template.Version,
 		&metadataJSON,
 		&template.CreatedAt,
@@ -151,19 +183,19 @@ func (r *TemplateRepository) List(ctx context.Context, channel *models.Notificat
 
 	if channel != nil {
 		query = `
-			SELECT id, name, channel, subject_template, body_template, version,
-			       metadata, created_at, updated_at
+			SELECT id, name, locale, channel, subject_template, body_template,
+			       html_body_template, version, metadata, created_at, updated_at
 			FROM notification_templates
 			WHERE channel = $1
-			ORDER BY name ASC
+			ORDER BY name ASC, locale ASC
 		`
 		rows, err = r.db.QueryContext(ctx, query, *channel)
 	} else {
 		query = `
-			SELECT id, name, channel, subject_template, body_template, version,
-			       metadata, created_at, updated_at
+			SELECT id, name, locale, channel, subject_template, body_template,
+			       html_body_template, version, metadata, created_at, updated_at
 			FROM notification_templates
-			ORDER BY name ASC
+			ORDER BY name ASC, locale ASC
 		`
 		rows, err = r.db.QueryContext(ctx, query)
 	}
@@ -183,9 +215,11 @@ func (r *TemplateRepository) List(ctx context.Context, channel *models.Notificat
 		if err := rows.Scan(
 			&template.ID,
 			&template.Name,
+			&template.Locale,
 			&template.Channel,
 			&template.SubjectTemplate,
 			&template.BodyTemplate,
+			&template.HTMLBodyTemplate,
 			&template.Version,
 			&metadataJSON,
 			&template.CreatedAt,
@@ -211,7 +245,8 @@ func (r *TemplateRepository) List(ctx context.Context, channel *models.Notificat
 	return templates, nil
 }
 
-// Update updates a notification template.
+// Update updates a notification template. A change to its content makes a
+// new version, recorded in its history.
 func (r *TemplateRepository) Update(ctx context.Context, id string, req *models.UpdateTemplateRequest) *errors.Error {
 	// Build dynamic update
 	var setClauses []string
@@ -228,7 +263,16 @@ func (r *TemplateRepository) Update(ctx context.Context, id string, req *models.
 		setClauses = append(setClauses, "body_template = $"+fmt.Sprint(argIndex))
 		args = append(args, *req.BodyTemplate)
 		argIndex++
-		// Increment version when body is updated
+	}
+
+	if req.HTMLBodyTemplate != nil {
+		setClauses = append(setClauses, "html_body_template = $"+fmt.Sprint(argIndex))
+		args = append(args, *req.HTMLBodyTemplate)
+		argIndex++
+	}
+
+	// Increment version when the content is updated
+	if req.ChangesContent() {
 		setClauses = append(setClauses, "version = version + 1")
 	}
 
@@ -261,25 +305,129 @@ func (r *TemplateRepository) Update(ctx context.Context, id string, req *models.
 		UPDATE notification_templates
 		SET %s
 		WHERE id = $%d
+		RETURNING version
 	`, strings.Join(setClauses, ", "), argIndex)
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var version int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NotFoundWithID("template", id)
+		}
 		return errors.DatabaseWrap(err, "failed to update template")
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return errors.DatabaseWrap(err, "failed to get rows affected")
+	if req.ChangesContent() {
+		if err := recordVersion(ctx, tx, id, version); err != nil {
+			return err
+		}
 	}
 
-	if rowsAffected == 0 {
-		return errors.NotFoundWithID("template", id)
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit template")
 	}
+	committed = true
+
+	return nil
+}
+
+// recordVersion copies a template's current content into its history.
+func recordVersion(ctx context.Context, tx *sql.Tx, templateID string, version int) *errors.Error {
+	query := `
+		INSERT INTO notification_template_versions (
+			template_id, version, subject_template, body_template, html_body_template
+		)
+		SELECT id, version, COALESCE(subject_template, ''), body_template, html_body_template
+		FROM notification_templates
+		WHERE id = $1 AND version = $2
+	`
 
+	if _, err := tx.ExecContext(ctx, query, templateID, version); err != nil {
+		return errors.DatabaseWrap(err, "failed to record template version")
+	}
 	return nil
 }
 
+// ListVersions retrieves a template's versions, newest first.
+func (r *TemplateRepository) ListVersions(ctx context.Context, templateID string) ([]*models.TemplateVersion, *errors.Error) {
+	query := `
+		SELECT template_id, version, subject_template, body_template,
+		       html_body_template, created_at
+		FROM notification_template_versions
+		WHERE template_id = $1
+		ORDER BY version DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, templateID)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to list template versions")
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	versions := make([]*models.TemplateVersion, 0)
+	for rows.Next() {
+		version := &models.TemplateVersion{}
+		if err := rows.Scan(
+			&version.TemplateID,
+			&version.Version,
+			&version.SubjectTemplate,
+			&version.BodyTemplate,
+			&version.HTMLBodyTemplate,
+			&version.CreatedAt,
+		); err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan template version")
+		}
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating template versions")
+	}
+
+	return versions, nil
+}
+
+// GetVersion retrieves one version of a template.
+func (r *TemplateRepository) GetVersion(ctx context.Context, templateID string, version int) (*models.TemplateVersion, *errors.Error) {
+	query := `
+		SELECT template_id, version, subject_template, body_template,
+		       html_body_template, created_at
+		FROM notification_template_versions
+		WHERE template_id = $1 AND version = $2
+	`
+
+	v := &models.TemplateVersion{}
+	err := r.db.QueryRowContext(ctx, query, templateID, version).Scan(
+		&v.TemplateID,
+		&v.Version,
+		&v.SubjectTemplate,
+		&v.BodyTemplate,
+		&v.HTMLBodyTemplate,
+		&v.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound(fmt.Sprintf("version %d of template", version))
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get template version")
+	}
+
+	return v, nil
+}
+
 // Delete deletes a notification template.
 func (r *TemplateRepository) Delete(ctx context.Context, id string) *errors.Error {
 	query := "DELETE FROM notification_templates WHERE id = $1"
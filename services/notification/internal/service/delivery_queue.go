@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// maxRetryCount is the most retries a notification can record, as enforced by
+// notifications_retry_count_check.
+const maxRetryCount = 10
+
+// RetryPolicy decides how often, and how far apart, delivery of a
+// notification is attempted when its provider is temporarily unavailable.
+type RetryPolicy struct {
+	MaxAttempts map[models.NotificationPriority]int // Attempts before a notification is dead-lettered
+	BaseDelay   time.Duration                       // Delay before the first retry, doubled for each one after
+	MaxDelay    time.Duration                       // Longest delay between attempts
+}
+
+// Attempts returns the number of delivery attempts for a priority, at least
+// one and at most maxRetryCount+1.
+func (p RetryPolicy) Attempts(priority models.NotificationPriority) int {
+	attempts, ok := p.MaxAttempts[priority]
+	if !ok || attempts < 1 {
+		attempts = 1
+	}
+	return min(attempts, maxRetryCount+1)
+}
+
+// Delay returns the delay before the retry following retryCount earlier
+// retries: BaseDelay * 2^retryCount, capped at MaxDelay.
+func (p RetryPolicy) Delay(retryCount int) time.Duration {
+	delay := p.BaseDelay
+	for i := 0; i < retryCount && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// QueueConfig configures how the delivery queue is worked.
+type QueueConfig struct {
+	Concurrency map[models.NotificationChannel]int // Most notifications of a channel being delivered at once by this replica
+	Lease       time.Duration                      // How long a claim lasts before another worker may take the notification over
+	Retry       RetryPolicy
+}
+
+// DefaultQueueConfig returns the default queue configuration. Critical and
+// high priority notifications get more attempts; with the default delays the
+// last retry of a critical notification is about 8 minutes after it was queued.
+func DefaultQueueConfig() QueueConfig {
+	return QueueConfig{
+		Concurrency: map[models.NotificationChannel]int{
+			models.ChannelSMS:   10,
+			models.ChannelEmail: 10,
+			models.ChannelPush:  20,
+			models.ChannelInApp: 50,
+		},
+		Lease: 2 * time.Minute,
+		Retry: RetryPolicy{
+			MaxAttempts: map[models.NotificationPriority]int{
+				models.PriorityCritical: 5,
+				models.PriorityHigh:     5,
+				models.PriorityNormal:   3,
+				models.PriorityLow:      2,
+			},
+			BaseDelay: 30 * time.Second,
+			MaxDelay:  15 * time.Minute,
+		},
+	}
+}
+
+// SetQueueConfig sets how the delivery queue is worked. It must be called
+// before notifications are processed.
+func (s *NotificationService) SetQueueConfig(config QueueConfig) {
+	s.queue = config
+	s.slots = make(map[models.NotificationChannel]chan struct{}, len(models.NotificationChannels))
+	for _, channel := range models.NotificationChannels {
+		s.slots[channel] = make(chan struct{}, max(config.Concurrency[channel], 1))
+	}
+}
+
+// ProcessQueuedNotifications claims due notifications of each channel that
+// has free delivery slots and delivers them in the background (called by the
+// background worker). Claims are leased, so any number of replicas can process
+// the queue at once.
+func (s *NotificationService) ProcessQueuedNotifications(ctx context.Context) *errors.Error {
+	for _, channel := range models.NotificationChannels {
+		slots := s.slots[channel]
+		free := cap(slots) - len(slots)
+		if free == 0 {
+			continue
+		}
+
+		notifications, err := s.notifRepo.ClaimQueued(ctx, channel, free, s.queue.Lease)
+		if err != nil {
+			return err
+		}
+		if len(notifications) == 0 {
+			continue
+		}
+
+		log.Printf("[notification] Claimed %d queued %s notifications", len(notifications), channel)
+
+		for _, notif := range notifications {
+			slots <- struct{}{}
+			go func(n *models.Notification) {
+				defer func() { <-slots }()
+				s.deliver(ctx, n)
+			}(notif)
+		}
+	}
+
+	return nil
+}
+
+// attemptFailed records a delivery attempt the provider did not accept. A
+// temporary failure is retried after a backoff until the notification's
+// attempts run out, when it is dead-lettered; a rejection fails it for good.
+func (s *NotificationService) attemptFailed(ctx context.Context, notif *models.Notification, err *errors.Error) {
+	reason := err.Message
+
+	if err.Code != errors.ErrCodeUnavailable && err.Code != errors.ErrCodeTimeout {
+		if updateErr := s.notifRepo.UpdateStatus(ctx, notif.ID, models.StatusFailed, &reason); updateErr != nil {
+			log.Printf("[notification] Failed to update notification %s to failed: %v", notif.ID, updateErr)
+		}
+		return
+	}
+
+	attempts := notif.RetryCount + 1
+	if attempts >= s.queue.Retry.Attempts(notif.Priority) {
+		s.deadLetter(ctx, notif, fmt.Sprintf("%s (after %d attempts)", reason, attempts))
+		return
+	}
+
+	nextAttemptAt := s.now().Add(s.queue.Retry.Delay(notif.RetryCount))
+	if updateErr := s.notifRepo.ScheduleRetry(ctx, notif.ID, nextAttemptAt, reason); updateErr != nil {
+		log.Printf("[notification] Failed to schedule retry of notification %s: %v", notif.ID, updateErr)
+		return
+	}
+	log.Printf("[notification] Notification %s attempt %d failed, retrying at %s", notif.ID, attempts, nextAttemptAt.Format(time.RFC3339))
+}
+
+// deadLetter gives up delivering a notification.
+func (s *NotificationService) deadLetter(ctx context.Context, notif *models.Notification, reason string) {
+	if err := s.notifRepo.DeadLetter(ctx, notif.ID, reason); err != nil {
+		log.Printf("[notification] Failed to dead-letter notification %s: %v", notif.ID, err)
+		return
+	}
+	log.Printf("[notification] Dead-lettered notification %s (channel=%s, priority=%s): %s", notif.ID, notif.Channel, notif.Priority, reason)
+}
+
+// ListDeadLetters lists dead-lettered notifications, optionally of one channel.
+func (s *NotificationService) ListDeadLetters(ctx context.Context, channel *models.NotificationChannel, limit, offset int) ([]*models.Notification, int64, *errors.Error) {
+	status := models.StatusDeadLetter
+	return s.notifRepo.List(ctx, &models.ListNotificationsRequest{
+		Channel: channel,
+		Status:  &status,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// RequeueDeadLetter queues a dead-lettered notification for immediate
+// delivery with a fresh set of attempts.
+func (s *NotificationService) RequeueDeadLetter(ctx context.Context, id string) *errors.Error {
+	notif, err := s.notifRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !notif.IsDeadLettered() {
+		return errors.Conflict(fmt.Sprintf("notification is %s, not dead-lettered", notif.Status))
+	}
+
+	if err := s.notifRepo.Requeue(ctx, id); err != nil {
+		return err
+	}
+
+	log.Printf("[notification] Requeued dead-lettered notification %s", id)
+	return nil
+}
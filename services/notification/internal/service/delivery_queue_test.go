@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// blockingProvider holds every notification until released, then confirms
+// its delivery.
+type blockingProvider struct {
+	channel models.NotificationChannel
+	release chan struct{}
+
+	mu      sync.Mutex
+	sending int
+	peak    int
+}
+
+func (p *blockingProvider) Name() string { return "blocking" }
+
+func (p *blockingProvider) Channels() []models.NotificationChannel {
+	return []models.NotificationChannel{p.channel}
+}
+
+func (p *blockingProvider) Send(ctx context.Context, notif *models.Notification) (*SendResult, *errors.Error) {
+	p.mu.Lock()
+	p.sending++
+	p.peak = max(p.peak, p.sending)
+	p.mu.Unlock()
+
+	<-p.release
+
+	p.mu.Lock()
+	p.sending--
+	p.mu.Unlock()
+	return &SendResult{MessageID: notif.ID, Delivered: true}, nil
+}
+
+func (p *blockingProvider) counts() (sending, peak int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sending, p.peak
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := DefaultQueueConfig().Retry
+
+	delays := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 15 * time.Minute}
+	for retryCount, want := range delays {
+		if got := policy.Delay(retryCount); got != want {
+			t.Errorf("retry %d: expected %s, got %s", retryCount, want, got)
+		}
+	}
+	if got := policy.Delay(100); got != policy.MaxDelay {
+		t.Errorf("expected a long retry to wait %s, got %s", policy.MaxDelay, got)
+	}
+
+	policy.MaxAttempts[models.PriorityLow] = 50
+	attempts := map[models.NotificationPriority]int{
+		models.PriorityCritical: 5,
+		models.PriorityNormal:   3,
+		models.PriorityLow:      maxRetryCount + 1,
+		"unknown":               1,
+	}
+	for priority, want := range attempts {
+		if got := policy.Attempts(priority); got != want {
+			t.Errorf("%s: expected %d attempts, got %d", priority, want, got)
+		}
+	}
+}
+
+func TestDeliver_RetriesTemporaryFailure(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, providerErr := range []*errors.Error{errors.Unavailable("gateway returned 503"), errors.Timeout("gateway timed out")} {
+		t.Run(string(providerErr.Code), func(t *testing.T) {
+			notif := queuedNotification("n-1", models.ChannelSMS)
+			notif.Priority = models.PriorityNormal
+			notif.RetryCount = 1
+			provider := &fakeProvider{name: "fake", channel: models.ChannelSMS, err: providerErr}
+			repo := newMockNotificationRepository(notif)
+			svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet(provider))
+			svc.now = func() time.Time { return now }
+
+			svc.deliver(context.Background(), repo.notifications["n-1"])
+
+			n, _ := repo.GetByID(context.Background(), "n-1")
+			if n.Status != models.StatusQueued || n.RetryCount != 2 || *n.FailureReason != providerErr.Message {
+				t.Fatalf("expected queued for retry, got %s after %d retries (%v)", n.Status, n.RetryCount, n.FailureReason)
+			}
+			if want := now.Add(time.Minute); !n.NextAttemptAt.Time.Equal(want) {
+				t.Errorf("expected next attempt at %s, got %s", want, n.NextAttemptAt.Time)
+			}
+		})
+	}
+}
+
+func TestDeliver_DeadLettersWhenAttemptsRunOut(t *testing.T) {
+	notif := queuedNotification("n-1", models.ChannelEmail)
+	notif.Priority = models.PriorityLow
+	notif.RetryCount = 1
+	provider := &fakeProvider{name: "fake", channel: models.ChannelEmail, err: errors.Unavailable("SMTP server unavailable")}
+	repo := newMockNotificationRepository(notif)
+	svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet(provider))
+
+	svc.deliver(context.Background(), repo.notifications["n-1"])
+
+	n, _ := repo.GetByID(context.Background(), "n-1")
+	if n.Status != models.StatusDeadLetter || !strings.Contains(*n.FailureReason, "after 2 attempts") {
+		t.Errorf("expected dead-lettered after 2 attempts, got %s (%v)", n.Status, n.FailureReason)
+	}
+}
+
+func TestProcessQueuedNotifications_ChannelConcurrency(t *testing.T) {
+	provider := &blockingProvider{channel: models.ChannelSMS, release: make(chan struct{})}
+	var notifs []*models.Notification
+	for _, id := range []string{"n-1", "n-2", "n-3", "n-4", "n-5"} {
+		notifs = append(notifs, queuedNotification(id, models.ChannelSMS))
+	}
+	repo := newMockNotificationRepository(notifs...)
+	svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet(provider))
+	config := DefaultQueueConfig()
+	config.Concurrency[models.ChannelSMS] = 2
+	svc.SetQueueConfig(config)
+
+	waitFor := func(what string, done func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !done() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// Polling again while both slots are busy claims nothing more
+	for range 3 {
+		if err := svc.ProcessQueuedNotifications(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor("two deliveries", func() bool { sending, _ := provider.counts(); return sending == 2 })
+
+	close(provider.release)
+	waitFor("every delivery", func() bool {
+		if err := svc.ProcessQueuedNotifications(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range notifs {
+			if repo.status(n.ID) != models.StatusDelivered {
+				return false
+			}
+		}
+		return true
+	})
+
+	if _, peak := provider.counts(); peak > 2 {
+		t.Errorf("expected at most 2 concurrent deliveries, got %d", peak)
+	}
+}
+
+func TestRequeueDeadLetter(t *testing.T) {
+	reason := "SMTP server unavailable (after 3 attempts)"
+	dead := queuedNotification("n-1", models.ChannelEmail)
+	dead.Status = models.StatusDeadLetter
+	dead.RetryCount = 2
+	dead.FailureReason = &reason
+	repo := newMockNotificationRepository(dead, queuedNotification("n-2", models.ChannelEmail))
+	svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet())
+
+	if err := svc.RequeueDeadLetter(context.Background(), "n-1"); err != nil {
+		t.Fatalf("expected requeue to succeed, got %v", err)
+	}
+	n, _ := repo.GetByID(context.Background(), "n-1")
+	if n.Status != models.StatusQueued || n.RetryCount != 0 || n.FailureReason != nil {
+		t.Errorf("expected queued with fresh attempts, got %s after %d retries", n.Status, n.RetryCount)
+	}
+
+	if err := svc.RequeueDeadLetter(context.Background(), "n-2"); err == nil || err.Code != errors.ErrCodeConflict {
+		t.Errorf("expected conflict requeueing a notification that is not dead-lettered, got %v", err)
+	}
+	if err := svc.RequeueDeadLetter(context.Background(), "n-missing"); err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// InboxProvider delivers in-app notifications. The stored notification is the
+// user's inbox entry, so it is delivered as soon as it is processed.
+type InboxProvider struct{}
+
+// NewInboxProvider creates a new in-app inbox provider.
+func NewInboxProvider() *InboxProvider {
+	return &InboxProvider{}
+}
+
+// Name returns the provider name.
+func (p *InboxProvider) Name() string {
+	return ProviderInbox
+}
+
+// Channels returns the channels the provider delivers.
+func (p *InboxProvider) Channels() []models.NotificationChannel {
+	return []models.NotificationChannel{models.ChannelInApp}
+}
+
+// Send delivers an in-app notification to the user's inbox.
+func (p *InboxProvider) Send(ctx context.Context, notif *models.Notification) (*SendResult, *errors.Error) {
+	if notif.UserID == nil {
+		return nil, errors.BadRequest("in-app notifications need a user")
+	}
+	return &SendResult{MessageID: notif.ID, Delivered: true}, nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// Inbox event types published to the user's private SSE topic.
+const (
+	InboxEventCreated = "notification.created"
+)
+
+// InboxEventPublisher publishes in-app inbox events to the user's private
+// topic on the gateway's SSE broker.
+type InboxEventPublisher interface {
+	PublishInboxEvent(eventType string, userID string, data map[string]interface{})
+}
+
+// SetInboxPublisher sets the publisher that pushes new in-app notifications
+// to the user's event stream. Without one, the inbox is only read through the
+// API.
+func (s *NotificationService) SetInboxPublisher(publisher InboxEventPublisher) {
+	s.inboxEvents = publisher
+}
+
+// publishInboxItem pushes a newly delivered in-app notification to its user.
+func (s *NotificationService) publishInboxItem(notif *models.Notification) {
+	if s.inboxEvents == nil || notif.UserID == nil {
+		return
+	}
+
+	s.inboxEvents.PublishInboxEvent(InboxEventCreated, *notif.UserID, map[string]interface{}{
+		"notification_id": notif.ID,
+		"type":            string(notif.Type),
+		"priority":        string(notif.Priority),
+		"subject":         notif.Subject,
+		"body":            notif.Body,
+		"created_at":      notif.CreatedAt,
+	})
+}
+
+// ListInbox retrieves a page of the user's in-app inbox, newest first.
+func (s *NotificationService) ListInbox(ctx context.Context, query *models.InboxQuery) ([]*models.Notification, int64, *errors.Error) {
+	if !query.Filter.IsValid() {
+		return nil, 0, errors.BadRequest("status must be one of unread, read or archived")
+	}
+	return s.notifRepo.ListInbox(ctx, query)
+}
+
+// UnreadCount returns the number of unread items in the user's in-app inbox.
+func (s *NotificationService) UnreadCount(ctx context.Context, userID string) (*models.InboxUnreadCount, *errors.Error) {
+	count, err := s.notifRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.InboxUnreadCount{Unread: count}, nil
+}
+
+// MarkRead marks an item of the user's in-app inbox read.
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id string) *errors.Error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errors.NotFoundWithID("notification", id)
+	}
+	return s.notifRepo.MarkRead(ctx, userID, id)
+}
+
+// MarkAllRead marks every unread item of the user's in-app inbox read.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID string) (*models.InboxReadAllResult, *errors.Error) {
+	marked, err := s.notifRepo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.InboxReadAllResult{Marked: marked}, nil
+}
+
+// Archive archives an item of the user's in-app inbox.
+func (s *NotificationService) Archive(ctx context.Context, userID, id string) *errors.Error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errors.NotFoundWithID("notification", id)
+	}
+	return s.notifRepo.Archive(ctx, userID, id)
+}
+
+// PruneInbox deletes in-app inbox items read or archived longer ago than
+// readRetention, and any older than retention (called by background worker).
+func (s *NotificationService) PruneInbox(ctx context.Context, readRetention, retention time.Duration) *errors.Error {
+	now := time.Now()
+	pruned, err := s.notifRepo.PruneInbox(ctx, now.Add(-readRetention), now.Add(-retention))
+	if err != nil {
+		return err
+	}
+
+	if pruned > 0 {
+		log.Printf("[notification] Pruned %d inbox notifications", pruned)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+const (
+	inboxUser  = "11111111-1111-1111-1111-111111111111"
+	otherUser  = "22222222-2222-2222-2222-222222222222"
+	inboxItem1 = "a0000000-0000-0000-0000-000000000001"
+	inboxItem2 = "a0000000-0000-0000-0000-000000000002"
+	inboxItem3 = "a0000000-0000-0000-0000-000000000003"
+	otherItem  = "b0000000-0000-0000-0000-000000000001"
+)
+
+type publishedInboxEvent struct {
+	eventType string
+	userID    string
+	data      map[string]interface{}
+}
+
+type mockInboxPublisher struct {
+	mu     sync.Mutex
+	events []publishedInboxEvent
+}
+
+func (p *mockInboxPublisher) PublishInboxEvent(eventType string, userID string, data map[string]interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, publishedInboxEvent{eventType, userID, data})
+}
+
+// inboxNotification returns a delivered in-app notification created the given
+// time ago.
+func inboxNotification(id, userID string, age time.Duration) *models.Notification {
+	return &models.Notification{
+		ID:        id,
+		UserID:    &userID,
+		Channel:   models.ChannelInApp,
+		Status:    models.StatusDelivered,
+		Body:      "Hello",
+		CreatedAt: sharedModels.NewTimestamp(time.Now().Add(-age)),
+	}
+}
+
+func newInboxFixture() (*NotificationService, *mockNotificationRepository) {
+	email := inboxNotification("c0000000-0000-0000-0000-000000000001", inboxUser, time.Minute)
+	email.Channel = models.ChannelEmail
+
+	repo := newMockNotificationRepository(
+		inboxNotification(inboxItem1, inboxUser, 3*time.Hour),
+		inboxNotification(inboxItem2, inboxUser, 2*time.Hour),
+		inboxNotification(inboxItem3, inboxUser, time.Hour),
+		inboxNotification(otherItem, otherUser, time.Hour),
+		email,
+	)
+	return NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet()), repo
+}
+
+func inboxIDs(t *testing.T, svc *NotificationService, filter models.InboxFilter) []string {
+	t.Helper()
+	items, _, err := svc.ListInbox(context.Background(), &models.InboxQuery{UserID: inboxUser, Filter: filter, Limit: 20})
+	if err != nil {
+		t.Fatalf("failed to list inbox: %v", err)
+	}
+	ids := make([]string, len(items))
+	for i, n := range items {
+		ids[i] = n.ID
+	}
+	return ids
+}
+
+func unread(t *testing.T, svc *NotificationService) int64 {
+	t.Helper()
+	count, err := svc.UnreadCount(context.Background(), inboxUser)
+	if err != nil {
+		t.Fatalf("failed to count unread: %v", err)
+	}
+	return count.Unread
+}
+
+func TestInbox_ReadAndArchive(t *testing.T) {
+	svc, _ := newInboxFixture()
+	ctx := context.Background()
+
+	// Only the user's own in-app notifications, newest first
+	if got := inboxIDs(t, svc, models.InboxAll); len(got) != 3 || got[0] != inboxItem3 || got[2] != inboxItem1 {
+		t.Fatalf("unexpected inbox: %v", got)
+	}
+	if got := unread(t, svc); got != 3 {
+		t.Errorf("expected 3 unread, got %d", got)
+	}
+
+	if err := svc.MarkRead(ctx, inboxUser, inboxItem2); err != nil {
+		t.Fatalf("failed to mark read: %v", err)
+	}
+	if err := svc.Archive(ctx, inboxUser, inboxItem1); err != nil {
+		t.Fatalf("failed to archive: %v", err)
+	}
+
+	if got := inboxIDs(t, svc, models.InboxUnread); len(got) != 1 || got[0] != inboxItem3 {
+		t.Errorf("expected only item 3 unread, got %v", got)
+	}
+	if got := inboxIDs(t, svc, models.InboxRead); len(got) != 1 || got[0] != inboxItem2 {
+		t.Errorf("expected only item 2 read, got %v", got)
+	}
+	if got := inboxIDs(t, svc, models.InboxArchived); len(got) != 1 || got[0] != inboxItem1 {
+		t.Errorf("expected only item 1 archived, got %v", got)
+	}
+	if got := inboxIDs(t, svc, models.InboxAll); len(got) != 2 {
+		t.Errorf("expected archived items to leave the inbox, got %v", got)
+	}
+
+	result, err := svc.MarkAllRead(ctx, inboxUser)
+	if err != nil || result.Marked != 1 {
+		t.Fatalf("expected one item marked read, got %+v (%v)", result, err)
+	}
+	if got := unread(t, svc); got != 0 {
+		t.Errorf("expected no unread, got %d", got)
+	}
+}
+
+func TestInbox_OtherUsersItems(t *testing.T) {
+	svc, repo := newInboxFixture()
+	ctx := context.Background()
+
+	for _, id := range []string{otherItem, "c0000000-0000-0000-0000-000000000001", "not-a-uuid"} {
+		if err := svc.MarkRead(ctx, inboxUser, id); err == nil || err.Code != errors.ErrCodeNotFound {
+			t.Errorf("%s: expected not found marking read, got %v", id, err)
+		}
+		if err := svc.Archive(ctx, inboxUser, id); err == nil || err.Code != errors.ErrCodeNotFound {
+			t.Errorf("%s: expected not found archiving, got %v", id, err)
+		}
+	}
+
+	if _, err := svc.MarkAllRead(ctx, inboxUser); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := repo.GetByID(ctx, otherItem); n.ReadAt != nil {
+		t.Error("expected another user's notification to stay unread")
+	}
+}
+
+func TestInbox_InvalidFilter(t *testing.T) {
+	svc, _ := newInboxFixture()
+
+	_, _, err := svc.ListInbox(context.Background(), &models.InboxQuery{UserID: inboxUser, Filter: "deleted", Limit: 20})
+	if err == nil || err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected bad request, got %v", err)
+	}
+}
+
+func TestInbox_Prune(t *testing.T) {
+	_, repo := newInboxFixture()
+	old := inboxNotification("d0000000-0000-0000-0000-000000000001", inboxUser, 200*24*time.Hour)
+	repo.notifications[old.ID] = old
+	svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet())
+	ctx := context.Background()
+
+	// Read long ago
+	readAt := sharedModels.NewTimestamp(time.Now().Add(-40 * 24 * time.Hour))
+	repo.notifications[inboxItem1].ReadAt = &readAt
+	// Read just now
+	if err := svc.MarkRead(ctx, inboxUser, inboxItem2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.PruneInbox(ctx, 30*24*time.Hour, 180*24*time.Hour); err != nil {
+		t.Fatalf("failed to prune: %v", err)
+	}
+
+	for id, wantKept := range map[string]bool{
+		inboxItem1: false, // read beyond the read retention
+		old.ID:     false, // older than the retention
+		inboxItem2: true,
+		inboxItem3: true,
+		otherItem:  true,
+	} {
+		if _, err := repo.GetByID(ctx, id); (err == nil) != wantKept {
+			t.Errorf("%s: expected kept=%v", id, wantKept)
+		}
+	}
+}
+
+func TestDeliver_PublishesInboxItem(t *testing.T) {
+	inApp := queuedNotification("n-1", models.ChannelInApp)
+	email := queuedNotification("n-2", models.ChannelEmail)
+	repo := newMockNotificationRepository(inApp, email)
+	svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet(
+		&fakeProvider{name: "fake", channel: models.ChannelEmail, result: &SendResult{MessageID: "m-2", Delivered: true}},
+	))
+	publisher := &mockInboxPublisher{}
+	svc.SetInboxPublisher(publisher)
+
+	svc.deliver(context.Background(), inApp)
+	svc.deliver(context.Background(), email)
+
+	if len(publisher.events) != 1 {
+		t.Fatalf("expected one inbox event, got %+v", publisher.events)
+	}
+	event := publisher.events[0]
+	if event.eventType != InboxEventCreated || event.userID != "user-1" || event.data["notification_id"] != "n-1" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
@@ -3,41 +3,82 @@ package service
 import (
 	"context"
 	"log"
+	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vnykmshr/nivo/services/notification/internal/models"
-	"github.com/vnykmshr/nivo/services/notification/internal/repository"
 	"github.com/vnykmshr/nivo/shared/errors"
 	sharedModels "github.com/vnykmshr/nivo/shared/models"
 )
 
+// NotificationRepositoryInterface defines the notification storage used by the service.
+type NotificationRepositoryInterface interface {
+	Create(ctx context.Context, notif *models.Notification) *errors.Error
+	GetByID(ctx context.Context, id string) (*models.Notification, *errors.Error)
+	GetByCorrelationID(ctx context.Context, correlationID string) (*models.Notification, *errors.Error)
+	List(ctx context.Context, req *models.ListNotificationsRequest) ([]*models.Notification, int64, *errors.Error)
+	UpdateStatus(ctx context.Context, id string, status models.NotificationStatus, failureReason *string) *errors.Error
+	MarkSent(ctx context.Context, id, provider, providerMessageID string) *errors.Error
+	ApplyReceipt(ctx context.Context, provider, providerMessageID string, status models.NotificationStatus, failureReason *string) *errors.Error
+	ClaimQueued(ctx context.Context, channel models.NotificationChannel, limit int, lease time.Duration) ([]*models.Notification, *errors.Error)
+	ScheduleRetry(ctx context.Context, id string, nextAttemptAt time.Time, failureReason string) *errors.Error
+	DeadLetter(ctx context.Context, id string, failureReason string) *errors.Error
+	Requeue(ctx context.Context, id string) *errors.Error
+	GetStats(ctx context.Context) (*models.NotificationStats, *errors.Error)
+	ListInbox(ctx context.Context, query *models.InboxQuery) ([]*models.Notification, int64, *errors.Error)
+	CountUnread(ctx context.Context, userID string) (int64, *errors.Error)
+	MarkRead(ctx context.Context, userID, id string) *errors.Error
+	MarkAllRead(ctx context.Context, userID string) (int64, *errors.Error)
+	Archive(ctx context.Context, userID, id string) *errors.Error
+	PruneInbox(ctx context.Context, readBefore, createdBefore time.Time) (int64, *errors.Error)
+}
+
 // NotificationService handles notification business logic.
 type NotificationService struct {
-	notifRepo      *repository.NotificationRepository
-	templateRepo   *repository.TemplateRepository
+	notifRepo      NotificationRepositoryInterface
+	templateRepo   TemplateRepositoryInterface
+	prefRepo       PreferenceRepositoryInterface
 	templateEngine *TemplateEngine
-	simEngine      *SimulationEngine
+	providers      ProviderSet
+	inboxEvents    InboxEventPublisher
+	queue          QueueConfig
+	slots          map[models.NotificationChannel]chan struct{} // Delivery slots of each channel
+	now            func() time.Time
 }
 
-// NewNotificationService creates a new notification service.
+// NewNotificationService creates a new notification service that delivers
+// each channel through the given provider, subject to users' preferences.
 func NewNotificationService(
-	notifRepo *repository.NotificationRepository,
-	templateRepo *repository.TemplateRepository,
-	simConfig SimulationConfig,
+	notifRepo NotificationRepositoryInterface,
+	templateRepo TemplateRepositoryInterface,
+	prefRepo PreferenceRepositoryInterface,
+	providers ProviderSet,
 ) *NotificationService {
 	service := &NotificationService{
 		notifRepo:      notifRepo,
 		templateRepo:   templateRepo,
+		prefRepo:       prefRepo,
 		templateEngine: NewTemplateEngine(),
+		providers:      providers,
+		now:            time.Now,
 	}
+	service.SetQueueConfig(DefaultQueueConfig())
 
-	// Initialize simulation engine with the repository
-	service.simEngine = NewSimulationEngine(simConfig, notifRepo)
+	// Providers that learn delivery outcomes in-process report them here
+	for _, provider := range providers {
+		if reporter, ok := provider.(interface{ SetReceiptFunc(ReceiptFunc) }); ok {
+			reporter.SetReceiptFunc(service.recordReceipt)
+		}
+	}
 
 	return service
 }
 
-// SendNotification creates and queues a notification for delivery.
+// SendNotification creates and queues a notification for delivery. A
+// notification for a user is first checked against their preferences: it is
+// suppressed if they opted out of its type on its channel, or deferred until
+// their quiet hours end, and the decision is recorded on it.
 func (s *NotificationService) SendNotification(ctx context.Context, req *models.SendNotificationRequest) (*models.SendNotificationResponse, *errors.Error) {
 	// Check for duplicate notification using correlation_id
 	if req.CorrelationID != nil && *req.CorrelationID != "" {
@@ -49,39 +90,37 @@ func (s *NotificationService) SendNotification(ctx context.Context, req *models.
 			return &models.SendNotificationResponse{
 				NotificationID: existing.ID,
 				Status:         existing.Status,
+				Decision:       existing.Decision,
+				DeliverAfter:   existing.DeliverAfter,
 				QueuedAt:       existing.QueuedAt,
 			}, nil
 		}
 	}
 
 	// Prepare notification
-	var subject, body string
+	var subject, body, htmlBody string
 	var templateID *string
+	var templateVersion *int
 
-	// If template is specified, render it
+	// If template is specified, render the variant for the locale
 	if req.TemplateID != nil && *req.TemplateID != "" {
-		// Try to look up template by ID (if valid UUID) or by name
-		var template *models.NotificationTemplate
-		var err *errors.Error
-
-		if _, uuidErr := uuid.Parse(*req.TemplateID); uuidErr == nil {
-			// Valid UUID - look up by ID
-			template, err = s.templateRepo.GetByID(ctx, *req.TemplateID)
-		} else {
-			// Not a UUID - look up by name
-			template, err = s.templateRepo.GetByName(ctx, *req.TemplateID)
+		template, err := s.findTemplate(ctx, *req.TemplateID, req.Locale)
+		if err != nil {
+			return nil, err
 		}
 
+		// Only email carries an HTML body
+		rendered, err := s.renderTemplate(template, req.Variables, req.Channel == models.ChannelEmail)
 		if err != nil {
 			return nil, err
 		}
-
-		// Render subject and body
-		if template.SubjectTemplate != "" {
-			subject, _ = s.templateEngine.Render(template.SubjectTemplate, req.Variables)
+		if len(rendered.Missing) > 0 {
+			log.Printf("[notification] Template %s (locale=%s) rendered without variables %v", template.Name, template.Locale, rendered.Missing)
 		}
-		body, _ = s.templateEngine.Render(template.BodyTemplate, req.Variables)
+
+		subject, body, htmlBody = rendered.Subject, rendered.Body, rendered.HTMLBody
 		templateID = &template.ID
+		templateVersion = &template.Version
 	} else {
 		// Use provided subject and body
 		subject = req.Subject
@@ -115,23 +154,32 @@ func (s *NotificationService) SendNotification(ctx context.Context, req *models.
 
 	// Create notification
 	notif := &models.Notification{
-		ID:            uuid.New().String(),
-		UserID:        req.UserID,
-		Channel:       req.Channel,
-		Type:          req.Type,
-		Priority:      priority,
-		Recipient:     req.Recipient,
-		Subject:       subject,
-		Body:          body,
-		TemplateID:    templateID,
-		Status:        models.StatusQueued,
-		CorrelationID: req.CorrelationID,
-		SourceService: sourceService,
-		Metadata:      metadata,
-		RetryCount:    0,
-		QueuedAt:      sharedModels.Now(),
-		CreatedAt:     sharedModels.Now(),
-		UpdatedAt:     sharedModels.Now(),
+		ID:              uuid.New().String(),
+		UserID:          req.UserID,
+		Channel:         req.Channel,
+		Type:            req.Type,
+		Priority:        priority,
+		Recipient:       req.Recipient,
+		Subject:         subject,
+		Body:            body,
+		HTMLBody:        htmlBody,
+		TemplateID:      templateID,
+		TemplateVersion: templateVersion,
+		Status:          models.StatusQueued,
+		CorrelationID:   req.CorrelationID,
+		SourceService:   sourceService,
+		Metadata:        metadata,
+		RetryCount:      0,
+		QueuedAt:        sharedModels.Now(),
+		CreatedAt:       sharedModels.Now(),
+		UpdatedAt:       sharedModels.Now(),
+	}
+
+	// Apply the user's preferences (system-wide notifications have none)
+	if notif.UserID != nil {
+		if err := s.applyPreferences(ctx, *notif.UserID, notif); err != nil {
+			return nil, err
+		}
 	}
 
 	// Save to database
@@ -139,12 +187,14 @@ func (s *NotificationService) SendNotification(ctx context.Context, req *models.
 		return nil, err
 	}
 
-	log.Printf("[notification] Created notification %s (type=%s, channel=%s, recipient=%s, priority=%s)",
-		notif.ID, notif.Type, notif.Channel, notif.Recipient, notif.Priority)
+	log.Printf("[notification] Created notification %s (type=%s, channel=%s, recipient=%s, priority=%s, status=%s)",
+		notif.ID, notif.Type, notif.Channel, notif.Recipient, notif.Priority, notif.Status)
 
 	return &models.SendNotificationResponse{
 		NotificationID: notif.ID,
 		Status:         notif.Status,
+		Decision:       notif.Decision,
+		DeliverAfter:   notif.DeliverAfter,
 		QueuedAt:       notif.QueuedAt,
 	}, nil
 }
@@ -174,67 +224,136 @@ func (s *NotificationService) GetStats(ctx context.Context) (*models.Notificatio
 	return s.notifRepo.GetStats(ctx)
 }
 
-// ProcessQueuedNotifications processes queued notifications (called by background worker).
-func (s *NotificationService) ProcessQueuedNotifications(ctx context.Context, batchSize int) *errors.Error {
-	// Get queued notifications
-	notifications, err := s.notifRepo.GetQueuedNotifications(ctx, batchSize)
+// deliver hands a claimed notification to its channel's provider and records
+// the outcome. A notification the provider accepts is marked sent, then
+// delivered once the provider confirms delivery, which may be straight away or
+// later through a receipt. One the provider does not accept is retried or
+// failed by attemptFailed.
+func (s *NotificationService) deliver(ctx context.Context, notif *models.Notification) {
+	provider, ok := s.providers[notif.Channel]
+	if !ok {
+		s.deadLetter(ctx, notif, "no provider for channel "+string(notif.Channel))
+		return
+	}
+
+	result, err := provider.Send(ctx, notif)
 	if err != nil {
-		return err
+		log.Printf("[notification] Provider %s failed notification %s: %s", provider.Name(), notif.ID, err.Message)
+		s.attemptFailed(ctx, notif, err)
+		return
 	}
 
-	if len(notifications) == 0 {
-		return nil
+	if err := s.notifRepo.MarkSent(ctx, notif.ID, provider.Name(), result.MessageID); err != nil {
+		log.Printf("[notification] Failed to mark notification %s sent: %v", notif.ID, err)
+		return
 	}
+	log.Printf("[notification] Notification %s sent via %s (message_id=%s)", notif.ID, provider.Name(), result.MessageID)
 
-	log.Printf("[notification] Processing %d queued notifications", len(notifications))
+	if !result.Delivered {
+		return
+	}
+	if err := s.applyReceipt(ctx, provider.Name(), &DeliveryReceipt{MessageID: result.MessageID, Delivered: true}); err != nil {
+		log.Printf("[notification] Failed to mark notification %s delivered: %v", notif.ID, err)
+		return
+	}
 
-	// Process each notification
-	for _, notif := range notifications {
-		// Process in goroutine for concurrency (in production, use worker pool)
-		go func(n *models.Notification) {
-			if err := s.simEngine.ProcessNotification(ctx, n); err != nil {
-				log.Printf("[notification] Error processing notification %s: %v", n.ID, err)
-			}
-		}(notif)
+	// A delivered in-app notification is new in the user's inbox
+	if notif.Channel == models.ChannelInApp {
+		s.publishInboxItem(notif)
 	}
+}
 
-	return nil
+// HandleReceipt records a delivery receipt a provider posted to its callback
+// endpoint.
+func (s *NotificationService) HandleReceipt(ctx context.Context, providerName string, header http.Header, body []byte) *errors.Error {
+	provider, ok := s.providers.ByName(providerName)
+	if !ok {
+		return errors.NotFound("provider " + providerName)
+	}
+	parser, ok := provider.(ReceiptParser)
+	if !ok {
+		return errors.NotFound("receipts for provider " + providerName)
+	}
+
+	receipt, err := parser.ParseReceipt(header, body)
+	if err != nil {
+		return err
+	}
+	if receipt == nil {
+		return nil // Interim status
+	}
+
+	return s.applyReceipt(ctx, providerName, receipt)
+}
+
+// recordReceipt records a delivery receipt reported in-process, logging failures.
+func (s *NotificationService) recordReceipt(ctx context.Context, provider string, receipt *DeliveryReceipt) {
+	if err := s.applyReceipt(ctx, provider, receipt); err != nil {
+		log.Printf("[notification] Failed to record %s receipt for message %s: %v", provider, receipt.MessageID, err)
+	}
 }
 
-// CreateTemplate creates a new notification template.
+// applyReceipt moves the notification a receipt refers to to its final status.
+func (s *NotificationService) applyReceipt(ctx context.Context, provider string, receipt *DeliveryReceipt) *errors.Error {
+	if receipt.Delivered {
+		return s.notifRepo.ApplyReceipt(ctx, provider, receipt.MessageID, models.StatusDelivered, nil)
+	}
+
+	reason := receipt.FailureReason
+	log.Printf("[notification] Provider %s reported message %s failed: %s", provider, receipt.MessageID, reason)
+	return s.notifRepo.ApplyReceipt(ctx, provider, receipt.MessageID, models.StatusFailed, &reason)
+}
+
+// CreateTemplate creates a new notification template, or a new locale variant
+// of an existing one.
 func (s *NotificationService) CreateTemplate(ctx context.Context, req *models.CreateTemplateRequest) (*models.NotificationTemplate, *errors.Error) {
 	metadata, err := req.GetMetadata()
 	if err != nil {
 		return nil, errors.Validation("invalid metadata JSON")
 	}
 
+	locale := models.DefaultLocale
+	if req.Locale != "" {
+		normalized, ok := models.NormalizeLocale(req.Locale)
+		if !ok {
+			return nil, errors.Validation("invalid locale: " + req.Locale)
+		}
+		locale = normalized
+	}
+
+	if req.HTMLBodyTemplate != "" && req.Channel != models.ChannelEmail {
+		return nil, errors.Validation("html_body_template is only supported for email templates")
+	}
+	if err := s.checkTemplate(&req.SubjectTemplate, &req.BodyTemplate, &req.HTMLBodyTemplate); err != nil {
+		return nil, err
+	}
+
 	template := &models.NotificationTemplate{
-		ID:              uuid.New().String(),
-		Name:            req.Name,
-		Channel:         req.Channel,
-		SubjectTemplate: req.SubjectTemplate,
-		BodyTemplate:    req.BodyTemplate,
-		Version:         1,
-		Metadata:        metadata,
-		CreatedAt:       sharedModels.Now(),
-		UpdatedAt:       sharedModels.Now(),
+		ID:               uuid.New().String(),
+		Name:             req.Name,
+		Locale:           locale,
+		Channel:          req.Channel,
+		SubjectTemplate:  req.SubjectTemplate,
+		BodyTemplate:     req.BodyTemplate,
+		HTMLBodyTemplate: req.HTMLBodyTemplate,
+		Version:          1,
+		Metadata:         metadata,
+		CreatedAt:        sharedModels.Now(),
+		UpdatedAt:        sharedModels.Now(),
 	}
 
 	if err := s.templateRepo.Create(ctx, template); err != nil {
 		return nil, err
 	}
 
-	log.Printf("[notification] Created template %s (name=%s, channel=%s)", template.ID, template.Name, template.Channel)
+	log.Printf("[notification] Created template %s (name=%s, locale=%s, channel=%s)", template.ID, template.Name, template.Locale, template.Channel)
 	return template, nil
 }
 
-// GetTemplate retrieves a template by ID or name.
-func (s *NotificationService) GetTemplate(ctx context.Context, id string) (*models.NotificationTemplate, *errors.Error) {
-	// Try by ID first if valid UUID, otherwise by name
-	if _, uuidErr := uuid.Parse(id); uuidErr == nil {
-		return s.templateRepo.GetByID(ctx, id)
-	}
-	return s.templateRepo.GetByName(ctx, id)
+// GetTemplate retrieves a template by ID, or by name in the locale (falling
+// back like notifications do).
+func (s *NotificationService) GetTemplate(ctx context.Context, id, locale string) (*models.NotificationTemplate, *errors.Error) {
+	return s.findTemplate(ctx, id, locale)
 }
 
 // ListTemplates retrieves all templates, optionally filtered by channel.
@@ -242,44 +361,50 @@ func (s *NotificationService) ListTemplates(ctx context.Context, channel *models
 	return s.templateRepo.List(ctx, channel)
 }
 
-// UpdateTemplate updates an existing template.
+// UpdateTemplate updates an existing template. Changing its content makes a
+// new version.
 func (s *NotificationService) UpdateTemplate(ctx context.Context, id string, req *models.UpdateTemplateRequest) *errors.Error {
+	if _, uuidErr := uuid.Parse(id); uuidErr != nil {
+		return errors.NotFoundWithID("template", id)
+	}
+
+	if req.HTMLBodyTemplate != nil && *req.HTMLBodyTemplate != "" {
+		template, err := s.templateRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if template.Channel != models.ChannelEmail {
+			return errors.Validation("html_body_template is only supported for email templates")
+		}
+	}
+	if err := s.checkTemplate(req.SubjectTemplate, req.BodyTemplate, req.HTMLBodyTemplate); err != nil {
+		return err
+	}
+
 	return s.templateRepo.Update(ctx, id, req)
 }
 
 // PreviewTemplate renders a template with provided variables (for testing).
-func (s *NotificationService) PreviewTemplate(ctx context.Context, templateID string, variables map[string]interface{}) (*models.PreviewTemplateResponse, *errors.Error) {
-	// Look up by ID if valid UUID, otherwise by name
-	var template *models.NotificationTemplate
-	var err *errors.Error
-
-	if _, uuidErr := uuid.Parse(templateID); uuidErr == nil {
-		template, err = s.templateRepo.GetByID(ctx, templateID)
-	} else {
-		template, err = s.templateRepo.GetByName(ctx, templateID)
-	}
-
+// A template given by name is previewed in the locale's variant.
+func (s *NotificationService) PreviewTemplate(ctx context.Context, templateID, locale string, variables map[string]interface{}) (*models.PreviewTemplateResponse, *errors.Error) {
+	template, err := s.findTemplate(ctx, templateID, locale)
 	if err != nil {
 		return nil, err
 	}
 
-	var subject string
-	var subjectVars []string
-
-	if template.SubjectTemplate != "" {
-		subject, subjectVars = s.templateEngine.Render(template.SubjectTemplate, variables)
+	rendered, err := s.renderTemplate(template, variables, true)
+	if err != nil {
+		return nil, err
 	}
 
-	body, bodyVars := s.templateEngine.Render(template.BodyTemplate, variables)
-
-	// Combine used variables
-	allVars := append(subjectVars, bodyVars...)
-
 	return &models.PreviewTemplateResponse{
-		Subject:      subject,
-		Body:         body,
+		Subject:      rendered.Subject,
+		Body:         rendered.Body,
+		HTMLBody:     rendered.HTMLBody,
+		Locale:       template.Locale,
+		Version:      template.Version,
 		RenderedAt:   sharedModels.Now(),
-		VariableUsed: allVars,
+		VariableUsed: rendered.Used,
 	}, nil
 }
 
@@ -291,8 +416,8 @@ func (s *NotificationService) ReplayNotification(ctx context.Context, id string)
 		return err
 	}
 
-	// Reset to queued status
-	if err := s.notifRepo.UpdateStatus(ctx, id, models.StatusQueued, nil); err != nil {
+	// Queue it again with a fresh set of attempts
+	if err := s.notifRepo.Requeue(ctx, id); err != nil {
 		return err
 	}
 
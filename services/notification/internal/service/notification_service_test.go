@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// =====================================================================
+// Mocks
+// =====================================================================
+
+type mockNotificationRepository struct {
+	mu            sync.Mutex
+	notifications map[string]*models.Notification
+	claimed       map[string]bool
+}
+
+func newMockNotificationRepository(notifs ...*models.Notification) *mockNotificationRepository {
+	m := &mockNotificationRepository{
+		notifications: make(map[string]*models.Notification),
+		claimed:       make(map[string]bool),
+	}
+	for _, n := range notifs {
+		m.notifications[n.ID] = n
+	}
+	return m
+}
+
+func (m *mockNotificationRepository) Create(ctx context.Context, notif *models.Notification) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifications[notif.ID] = notif
+	return nil
+}
+
+func (m *mockNotificationRepository) GetByID(ctx context.Context, id string) (*models.Notification, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.notifications[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("notification", id)
+	}
+	copied := *n
+	return &copied, nil
+}
+
+func (m *mockNotificationRepository) GetByCorrelationID(ctx context.Context, correlationID string) (*models.Notification, *errors.Error) {
+	return nil, errors.NotFound("notification")
+}
+
+func (m *mockNotificationRepository) List(ctx context.Context, req *models.ListNotificationsRequest) ([]*models.Notification, int64, *errors.Error) {
+	return nil, 0, nil
+}
+
+func (m *mockNotificationRepository) UpdateStatus(ctx context.Context, id string, status models.NotificationStatus, failureReason *string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := m.notifications[id]
+	n.Status = status
+	n.FailureReason = failureReason
+	delete(m.claimed, id)
+	return nil
+}
+
+func (m *mockNotificationRepository) MarkSent(ctx context.Context, id, provider, providerMessageID string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := m.notifications[id]
+	n.Status = models.StatusSent
+	n.Provider = &provider
+	n.ProviderMessageID = &providerMessageID
+	delete(m.claimed, id)
+	return nil
+}
+
+func (m *mockNotificationRepository) ApplyReceipt(ctx context.Context, provider, providerMessageID string, status models.NotificationStatus, failureReason *string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, n := range m.notifications {
+		if n.Provider != nil && *n.Provider == provider && *n.ProviderMessageID == providerMessageID {
+			if n.Status == models.StatusSent {
+				n.Status = status
+				n.FailureReason = failureReason
+			}
+			return nil
+		}
+	}
+	return errors.NotFound("notification for provider message " + providerMessageID)
+}
+
+func (m *mockNotificationRepository) ClaimQueued(ctx context.Context, channel models.NotificationChannel, limit int, lease time.Duration) ([]*models.Notification, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	claimed := make([]*models.Notification, 0)
+	for id, n := range m.notifications {
+		if len(claimed) == limit {
+			break
+		}
+		if n.Status != models.StatusQueued || n.Channel != channel || m.claimed[id] || n.NextAttemptAt.Time.After(time.Now()) {
+			continue
+		}
+		m.claimed[id] = true
+		copied := *n
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (m *mockNotificationRepository) ScheduleRetry(ctx context.Context, id string, nextAttemptAt time.Time, failureReason string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := m.notifications[id]
+	n.RetryCount++
+	n.FailureReason = &failureReason
+	n.NextAttemptAt = sharedModels.NewTimestamp(nextAttemptAt)
+	delete(m.claimed, id)
+	return nil
+}
+
+func (m *mockNotificationRepository) DeadLetter(ctx context.Context, id string, failureReason string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := m.notifications[id]
+	now := sharedModels.Now()
+	n.Status = models.StatusDeadLetter
+	n.FailureReason = &failureReason
+	n.DeadLetteredAt = &now
+	delete(m.claimed, id)
+	return nil
+}
+
+func (m *mockNotificationRepository) Requeue(ctx context.Context, id string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.notifications[id]
+	if !ok {
+		return errors.NotFoundWithID("notification", id)
+	}
+	n.Status = models.StatusQueued
+	n.RetryCount = 0
+	n.FailureReason = nil
+	n.NextAttemptAt = sharedModels.Now()
+	n.DeadLetteredAt = nil
+	delete(m.claimed, id)
+	return nil
+}
+
+func (m *mockNotificationRepository) GetStats(ctx context.Context) (*models.NotificationStats, *errors.Error) {
+	return nil, nil
+}
+
+// inboxItems returns the user's delivered in-app notifications matching the
+// filter, newest first.
+func (m *mockNotificationRepository) inboxItems(userID string, filter models.InboxFilter) []*models.Notification {
+	var items []*models.Notification
+	for _, n := range m.notifications {
+		if n.UserID == nil || *n.UserID != userID || n.Channel != models.ChannelInApp || n.Status != models.StatusDelivered {
+			continue
+		}
+		read, archived := n.ReadAt != nil, n.ArchivedAt != nil
+		switch {
+		case filter == models.InboxUnread && (read || archived),
+			filter == models.InboxRead && (!read || archived),
+			filter == models.InboxArchived && !archived,
+			filter == models.InboxAll && archived:
+			continue
+		}
+		items = append(items, n)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.After(items[j].CreatedAt) })
+	return items
+}
+
+func (m *mockNotificationRepository) ListInbox(ctx context.Context, query *models.InboxQuery) ([]*models.Notification, int64, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := m.inboxItems(query.UserID, query.Filter)
+	total := int64(len(items))
+	if query.Offset >= len(items) {
+		return []*models.Notification{}, total, nil
+	}
+	items = items[query.Offset:]
+	if len(items) > query.Limit {
+		items = items[:query.Limit]
+	}
+	return items, total, nil
+}
+
+func (m *mockNotificationRepository) CountUnread(ctx context.Context, userID string) (int64, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return int64(len(m.inboxItems(userID, models.InboxUnread))), nil
+}
+
+func (m *mockNotificationRepository) inboxItem(userID, id string) (*models.Notification, *errors.Error) {
+	n, ok := m.notifications[id]
+	if !ok || n.UserID == nil || *n.UserID != userID || n.Channel != models.ChannelInApp || n.Status != models.StatusDelivered {
+		return nil, errors.NotFoundWithID("notification", id)
+	}
+	return n, nil
+}
+
+func (m *mockNotificationRepository) MarkRead(ctx context.Context, userID, id string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, err := m.inboxItem(userID, id)
+	if err != nil {
+		return err
+	}
+	if n.ReadAt == nil {
+		now := sharedModels.Now()
+		n.ReadAt = &now
+	}
+	return nil
+}
+
+func (m *mockNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := m.inboxItems(userID, models.InboxUnread)
+	for _, n := range items {
+		now := sharedModels.Now()
+		n.ReadAt = &now
+	}
+	return int64(len(items)), nil
+}
+
+func (m *mockNotificationRepository) Archive(ctx context.Context, userID, id string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, err := m.inboxItem(userID, id)
+	if err != nil {
+		return err
+	}
+	now := sharedModels.Now()
+	if n.ReadAt == nil {
+		n.ReadAt = &now
+	}
+	if n.ArchivedAt == nil {
+		n.ArchivedAt = &now
+	}
+	return nil
+}
+
+func (m *mockNotificationRepository) PruneInbox(ctx context.Context, readBefore, createdBefore time.Time) (int64, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var pruned int64
+	for id, n := range m.notifications {
+		if n.Channel != models.ChannelInApp || n.Status != models.StatusDelivered {
+			continue
+		}
+		seen := n.ArchivedAt
+		if seen == nil {
+			seen = n.ReadAt
+		}
+		if (seen != nil && seen.Time.Before(readBefore)) || n.CreatedAt.Time.Before(createdBefore) {
+			delete(m.notifications, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+func (m *mockNotificationRepository) status(id string) models.NotificationStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.notifications[id].Status
+}
+
+// fakeProvider returns a fixed result for every notification.
+type fakeProvider struct {
+	name    string
+	channel models.NotificationChannel
+	result  *SendResult
+	err     *errors.Error
+	sent    []string
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Channels() []models.NotificationChannel {
+	return []models.NotificationChannel{p.channel}
+}
+
+func (p *fakeProvider) Send(ctx context.Context, notif *models.Notification) (*SendResult, *errors.Error) {
+	p.sent = append(p.sent, notif.ID)
+	return p.result, p.err
+}
+
+// Compile-time interface checks
+var (
+	_ NotificationRepositoryInterface = (*mockNotificationRepository)(nil)
+	_ Provider                        = (*SimulationEngine)(nil)
+	_ Provider                        = (*SMTPProvider)(nil)
+	_ Provider                        = (*SMSGatewayProvider)(nil)
+	_ Provider                        = (*WebhookProvider)(nil)
+	_ Provider                        = (*InboxProvider)(nil)
+	_ ReceiptParser                   = (*SMSGatewayProvider)(nil)
+)
+
+func queuedNotification(id string, channel models.NotificationChannel) *models.Notification {
+	userID := "user-1"
+	return &models.Notification{ID: id, UserID: &userID, Channel: channel, Status: models.StatusQueued, Body: "Hello"}
+}
+
+// providerSet assigns the given providers to their channels and the inbox to
+// the rest.
+func providerSet(providers ...Provider) ProviderSet {
+	set := ProviderSet{}
+	for _, channel := range allChannels {
+		set[channel] = NewInboxProvider()
+	}
+	for _, p := range providers {
+		set[p.Channels()[0]] = p
+	}
+	return set
+}
+
+// =====================================================================
+// Delivery Tests
+// =====================================================================
+
+func TestDeliver_ConfirmedDelivery(t *testing.T) {
+	repo := newMockNotificationRepository(queuedNotification("n-1", models.ChannelInApp))
+	svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet())
+
+	svc.deliver(context.Background(), repo.notifications["n-1"])
+
+	n, _ := repo.GetByID(context.Background(), "n-1")
+	if n.Status != models.StatusDelivered || *n.Provider != ProviderInbox || *n.ProviderMessageID != "n-1" {
+		t.Errorf("expected delivered via inbox, got %s via %v", n.Status, n.Provider)
+	}
+}
+
+func TestDeliver_ProviderRejects(t *testing.T) {
+	provider := &fakeProvider{name: "fake", channel: models.ChannelEmail, err: errors.BadRequest("mailbox does not exist")}
+	repo := newMockNotificationRepository(queuedNotification("n-1", models.ChannelEmail))
+	svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet(provider))
+
+	svc.deliver(context.Background(), repo.notifications["n-1"])
+
+	n, _ := repo.GetByID(context.Background(), "n-1")
+	if n.Status != models.StatusFailed || *n.FailureReason != "mailbox does not exist" {
+		t.Errorf("expected failed with the provider's reason, got %s (%v)", n.Status, n.FailureReason)
+	}
+}
+
+func TestDeliver_ReceiptDrivesFinalStatus(t *testing.T) {
+	gateway := NewSMSGatewayProvider(SMSGatewayConfig{ReceiptSecret: testReceiptSecret})
+	repo := newMockNotificationRepository(
+		queuedNotification("n-1", models.ChannelSMS),
+		queuedNotification("n-2", models.ChannelSMS),
+	)
+	svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet(gateway))
+
+	// Stand in for the gateway accepting both messages
+	for i, id := range []string{"n-1", "n-2"} {
+		messageID := []string{"gw-1", "gw-2"}[i]
+		if err := repo.MarkSent(context.Background(), id, ProviderSMSGateway, messageID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	receipts := []struct {
+		body string
+		id   string
+		want models.NotificationStatus
+	}{
+		{`{"message_id":"gw-1","status":"delivered"}`, "n-1", models.StatusDelivered},
+		{`{"message_id":"gw-2","status":"failed","error":"Number barred"}`, "n-2", models.StatusFailed},
+		// A late, contradictory receipt does not change a final status
+		{`{"message_id":"gw-1","status":"failed"}`, "n-1", models.StatusDelivered},
+	}
+	for _, r := range receipts {
+		body := []byte(r.body)
+		if err := svc.HandleReceipt(context.Background(), ProviderSMSGateway, signedReceiptHeader(testReceiptSecret, body), body); err != nil {
+			t.Fatalf("expected receipt to be recorded, got %v", err)
+		}
+		if got := repo.status(r.id); got != r.want {
+			t.Errorf("after %s: expected %s, got %s", r.body, r.want, got)
+		}
+	}
+
+	body := []byte(`{"message_id":"gw-unknown","status":"delivered"}`)
+	if err := svc.HandleReceipt(context.Background(), ProviderSMSGateway, signedReceiptHeader(testReceiptSecret, body), body); err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found for an unknown message, got %v", err)
+	}
+}
+
+func TestHandleReceipt_UnknownProvider(t *testing.T) {
+	svc := NewNotificationService(newMockNotificationRepository(), nil, newMockPreferenceRepository(), providerSet())
+
+	for _, name := range []string{"carrier-pigeon", ProviderInbox} {
+		if err := svc.HandleReceipt(context.Background(), name, nil, []byte(`{}`)); err == nil || err.Code != errors.ErrCodeNotFound {
+			t.Errorf("%s: expected not found, got %v", name, err)
+		}
+	}
+}
+
+func TestDeliver_SimulationReportsReceipt(t *testing.T) {
+	tests := []struct {
+		failureRate float64
+		want        models.NotificationStatus
+	}{
+		{0, models.StatusDelivered},
+		{100, models.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			sim := NewSimulationEngine(SimulationConfig{FinalDelayMs: 10, FailureRatePercent: tt.failureRate})
+			repo := newMockNotificationRepository(queuedNotification("n-1", models.ChannelSMS))
+			svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), ProviderSet{
+				models.ChannelSMS: sim, models.ChannelEmail: sim, models.ChannelPush: sim, models.ChannelInApp: sim,
+			})
+
+			svc.deliver(context.Background(), repo.notifications["n-1"])
+			if got := repo.status("n-1"); got != models.StatusSent {
+				t.Fatalf("expected sent before the receipt, got %s", got)
+			}
+
+			deadline := time.Now().Add(2 * time.Second)
+			for repo.status("n-1") == models.StatusSent && time.Now().Before(deadline) {
+				time.Sleep(5 * time.Millisecond)
+			}
+			if got := repo.status("n-1"); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProviderSet_Validate(t *testing.T) {
+	sim := NewSimulationEngine(DefaultSimulationConfig())
+	valid := func() ProviderSet {
+		return ProviderSet{
+			models.ChannelSMS:   &fakeProvider{name: "fake", channel: models.ChannelSMS},
+			models.ChannelEmail: sim,
+			models.ChannelPush:  sim,
+			models.ChannelInApp: NewInboxProvider(),
+		}
+	}
+
+	if err := valid().Validate(); err != nil {
+		t.Errorf("expected valid provider set, got %v", err)
+	}
+
+	// The inbox cannot deliver email
+	invalid := valid()
+	invalid[models.ChannelEmail] = NewInboxProvider()
+	if err := invalid.Validate(); err == nil {
+		t.Error("expected error for a provider that cannot deliver its channel")
+	}
+
+	missing := valid()
+	delete(missing, models.ChannelPush)
+	if err := missing.Validate(); err == nil {
+		t.Error("expected error for a channel without a provider")
+	}
+}
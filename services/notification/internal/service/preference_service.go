@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// PreferenceRepositoryInterface defines the storage of users' notification
+// preferences and quiet hours.
+type PreferenceRepositoryInterface interface {
+	ListPreferences(ctx context.Context, userID string) ([]models.NotificationPreference, *errors.Error)
+	IsEnabled(ctx context.Context, userID string, notifType models.NotificationType, channel models.NotificationChannel) (bool, *errors.Error)
+	SavePreferences(ctx context.Context, userID string, preferences []models.NotificationPreference) *errors.Error
+	GetQuietHours(ctx context.Context, userID string) (*models.QuietHours, *errors.Error)
+	SetQuietHours(ctx context.Context, userID string, quiet *models.QuietHours) *errors.Error
+	DeleteQuietHours(ctx context.Context, userID string) *errors.Error
+}
+
+// decide applies a user's preferences to a notification sent at now. Critical
+// notifications ignore them: they are sent even when the user opted out, which
+// is recorded as mandatory, and quiet hours never delay them. Quiet hours only
+// defer normal and low priority notifications, until the window ends.
+func decide(enabled bool, quiet *models.QuietHours, priority models.NotificationPriority, now time.Time) (models.PreferenceDecision, *time.Time) {
+	if priority == models.PriorityCritical {
+		if !enabled {
+			return models.DecisionMandatory, nil
+		}
+		return models.DecisionAllowed, nil
+	}
+
+	if !enabled {
+		return models.DecisionSuppressed, nil
+	}
+
+	if quiet != nil && (priority == models.PriorityNormal || priority == models.PriorityLow) {
+		if endsAt, ok := quiet.EndsAt(now); ok {
+			return models.DecisionDeferred, &endsAt
+		}
+	}
+
+	return models.DecisionAllowed, nil
+}
+
+// applyPreferences decides how the user's preferences apply to a notification
+// and records the decision on it.
+func (s *NotificationService) applyPreferences(ctx context.Context, userID string, notif *models.Notification) *errors.Error {
+	enabled, err := s.prefRepo.IsEnabled(ctx, userID, notif.Type, notif.Channel)
+	if err != nil {
+		return err
+	}
+
+	quiet, err := s.quietHours(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	decision, deliverAfter := decide(enabled, quiet, notif.Priority, s.now())
+	notif.Decision = &decision
+	switch decision {
+	case models.DecisionSuppressed:
+		notif.Status = models.StatusSuppressed
+	case models.DecisionDeferred:
+		after := sharedModels.NewTimestamp(deliverAfter.UTC())
+		notif.DeliverAfter = &after
+	}
+
+	return nil
+}
+
+// quietHours returns the user's quiet hours, or nil if they have none.
+func (s *NotificationService) quietHours(ctx context.Context, userID string) (*models.QuietHours, *errors.Error) {
+	quiet, err := s.prefRepo.GetQuietHours(ctx, userID)
+	if err != nil {
+		if err.Code == errors.ErrCodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return quiet, nil
+}
+
+// GetSettings returns the user's preference for every type and channel, and
+// their quiet hours.
+func (s *NotificationService) GetSettings(ctx context.Context, userID string) (*models.NotificationSettings, *errors.Error) {
+	stored, err := s.prefRepo.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		notifType models.NotificationType
+		channel   models.NotificationChannel
+	}
+	disabled := make(map[key]bool)
+	for _, pref := range stored {
+		disabled[key{pref.Type, pref.Channel}] = !pref.Enabled
+	}
+
+	settings := &models.NotificationSettings{
+		Preferences: make([]models.NotificationPreference, 0, len(models.NotificationTypes)*len(models.NotificationChannels)),
+	}
+	for _, notifType := range models.NotificationTypes {
+		for _, channel := range models.NotificationChannels {
+			settings.Preferences = append(settings.Preferences, models.NotificationPreference{
+				Type:    notifType,
+				Channel: channel,
+				Enabled: !disabled[key{notifType, channel}],
+			})
+		}
+	}
+
+	if settings.QuietHours, err = s.quietHours(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// UpdatePreferences stores the user's preferences and returns all their settings.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID string, req *models.UpdatePreferencesRequest) (*models.NotificationSettings, *errors.Error) {
+	if len(req.Preferences) == 0 {
+		return nil, errors.Validation("at least one preference is required")
+	}
+	for _, pref := range req.Preferences {
+		if !slices.Contains(models.NotificationTypes, pref.Type) {
+			return nil, errors.Validation("unknown notification type: " + string(pref.Type))
+		}
+		if !slices.Contains(models.NotificationChannels, pref.Channel) {
+			return nil, errors.Validation("unknown notification channel: " + string(pref.Channel))
+		}
+	}
+
+	if err := s.prefRepo.SavePreferences(ctx, userID, req.Preferences); err != nil {
+		return nil, err
+	}
+
+	return s.GetSettings(ctx, userID)
+}
+
+// SetQuietHours sets the user's quiet hours.
+func (s *NotificationService) SetQuietHours(ctx context.Context, userID string, quiet *models.QuietHours) (*models.QuietHours, *errors.Error) {
+	if err := quiet.Validate(); err != nil {
+		return nil, errors.Validation("invalid quiet hours: " + err.Error())
+	}
+
+	if err := s.prefRepo.SetQuietHours(ctx, userID, quiet); err != nil {
+		return nil, err
+	}
+
+	return quiet, nil
+}
+
+// ClearQuietHours removes the user's quiet hours.
+func (s *NotificationService) ClearQuietHours(ctx context.Context, userID string) *errors.Error {
+	return s.prefRepo.DeleteQuietHours(ctx, userID)
+}
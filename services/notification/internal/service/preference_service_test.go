@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+type preferenceKey struct {
+	userID    string
+	notifType models.NotificationType
+	channel   models.NotificationChannel
+}
+
+type mockPreferenceRepository struct {
+	enabled map[preferenceKey]bool
+	quiet   map[string]*models.QuietHours
+}
+
+func newMockPreferenceRepository() *mockPreferenceRepository {
+	return &mockPreferenceRepository{
+		enabled: make(map[preferenceKey]bool),
+		quiet:   make(map[string]*models.QuietHours),
+	}
+}
+
+func (m *mockPreferenceRepository) ListPreferences(ctx context.Context, userID string) ([]models.NotificationPreference, *errors.Error) {
+	var prefs []models.NotificationPreference
+	for key, enabled := range m.enabled {
+		if key.userID == userID {
+			prefs = append(prefs, models.NotificationPreference{Type: key.notifType, Channel: key.channel, Enabled: enabled})
+		}
+	}
+	return prefs, nil
+}
+
+func (m *mockPreferenceRepository) IsEnabled(ctx context.Context, userID string, notifType models.NotificationType, channel models.NotificationChannel) (bool, *errors.Error) {
+	enabled, ok := m.enabled[preferenceKey{userID, notifType, channel}]
+	return !ok || enabled, nil
+}
+
+func (m *mockPreferenceRepository) SavePreferences(ctx context.Context, userID string, preferences []models.NotificationPreference) *errors.Error {
+	for _, pref := range preferences {
+		m.enabled[preferenceKey{userID, pref.Type, pref.Channel}] = pref.Enabled
+	}
+	return nil
+}
+
+func (m *mockPreferenceRepository) GetQuietHours(ctx context.Context, userID string) (*models.QuietHours, *errors.Error) {
+	quiet, ok := m.quiet[userID]
+	if !ok {
+		return nil, errors.NotFound("quiet hours")
+	}
+	return quiet, nil
+}
+
+func (m *mockPreferenceRepository) SetQuietHours(ctx context.Context, userID string, quiet *models.QuietHours) *errors.Error {
+	m.quiet[userID] = quiet
+	return nil
+}
+
+func (m *mockPreferenceRepository) DeleteQuietHours(ctx context.Context, userID string) *errors.Error {
+	delete(m.quiet, userID)
+	return nil
+}
+
+var _ PreferenceRepositoryInterface = (*mockPreferenceRepository)(nil)
+
+func TestQuietHours_EndsAt(t *testing.T) {
+	ist, _ := time.LoadLocation("Asia/Kolkata")
+	overnight := &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "Asia/Kolkata"}
+	daytime := &models.QuietHours{Start: "13:00", End: "14:30", Timezone: "Asia/Kolkata"}
+
+	tests := []struct {
+		name   string
+		quiet  *models.QuietHours
+		at     time.Time
+		want   time.Time
+		inside bool
+	}{
+		{"overnight, before midnight", overnight, time.Date(2026, 3, 1, 23, 15, 0, 0, ist), time.Date(2026, 3, 2, 7, 0, 0, 0, ist), true},
+		{"overnight, after midnight", overnight, time.Date(2026, 3, 2, 6, 59, 0, 0, ist), time.Date(2026, 3, 2, 7, 0, 0, 0, ist), true},
+		{"overnight, at the end", overnight, time.Date(2026, 3, 2, 7, 0, 0, 0, ist), time.Time{}, false},
+		{"overnight, daytime", overnight, time.Date(2026, 3, 2, 12, 0, 0, 0, ist), time.Time{}, false},
+		{"daytime window", daytime, time.Date(2026, 3, 2, 13, 0, 0, 0, ist), time.Date(2026, 3, 2, 14, 30, 0, 0, ist), true},
+		// 17:00 UTC is 22:30 in India
+		{"other timezone", overnight, time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 7, 0, 0, 0, ist), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, inside := tt.quiet.EndsAt(tt.at)
+			if inside != tt.inside || !got.Equal(tt.want) {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.want, tt.inside, got, inside)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Validate(t *testing.T) {
+	tests := []struct {
+		quiet models.QuietHours
+		valid bool
+	}{
+		{models.QuietHours{Start: "22:00", End: "07:00", Timezone: "Asia/Kolkata"}, true},
+		{models.QuietHours{Start: "22:00", End: "22:00", Timezone: "Asia/Kolkata"}, false},
+		{models.QuietHours{Start: "25:00", End: "07:00", Timezone: "Asia/Kolkata"}, false},
+		{models.QuietHours{Start: "22:00", End: "7am", Timezone: "Asia/Kolkata"}, false},
+		{models.QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.quiet.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%+v: expected valid=%v, got %v", tt.quiet, tt.valid, err)
+		}
+	}
+}
+
+func TestSendNotification_AppliesPreferences(t *testing.T) {
+	ist, _ := time.LoadLocation("Asia/Kolkata")
+	night := time.Date(2026, 3, 1, 23, 0, 0, 0, ist)
+	morning := time.Date(2026, 3, 2, 7, 0, 0, 0, ist)
+
+	prefs := newMockPreferenceRepository()
+	_ = prefs.SavePreferences(context.Background(), inboxUser, []models.NotificationPreference{
+		{Type: models.TypeTransactionAlert, Channel: models.ChannelEmail, Enabled: false},
+		{Type: models.TypeOTP, Channel: models.ChannelSMS, Enabled: false},
+	})
+	prefs.quiet[inboxUser] = &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "Asia/Kolkata"}
+
+	tests := []struct {
+		name         string
+		userID       string
+		notifType    models.NotificationType
+		channel      models.NotificationChannel
+		priority     models.NotificationPriority
+		wantStatus   models.NotificationStatus
+		wantDecision models.PreferenceDecision
+		wantAfter    bool
+	}{
+		{"opted out", inboxUser, models.TypeTransactionAlert, models.ChannelEmail, models.PriorityHigh, models.StatusSuppressed, models.DecisionSuppressed, false},
+		{"other channel allowed", inboxUser, models.TypeTransactionAlert, models.ChannelSMS, models.PriorityHigh, models.StatusQueued, models.DecisionAllowed, false},
+		{"critical despite opt-out", inboxUser, models.TypeOTP, models.ChannelSMS, models.PriorityCritical, models.StatusQueued, models.DecisionMandatory, false},
+		{"normal deferred in quiet hours", inboxUser, models.TypeWalletAlert, models.ChannelPush, models.PriorityNormal, models.StatusQueued, models.DecisionDeferred, true},
+		{"low deferred in quiet hours", inboxUser, models.TypeMarketing, models.ChannelEmail, models.PriorityLow, models.StatusQueued, models.DecisionDeferred, true},
+		{"critical not deferred", inboxUser, models.TypeSecurityAlert, models.ChannelSMS, models.PriorityCritical, models.StatusQueued, models.DecisionAllowed, false},
+		{"opt-out wins over quiet hours", inboxUser, models.TypeTransactionAlert, models.ChannelEmail, models.PriorityNormal, models.StatusSuppressed, models.DecisionSuppressed, false},
+		{"other user", otherUser, models.TypeTransactionAlert, models.ChannelEmail, models.PriorityNormal, models.StatusQueued, models.DecisionAllowed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockNotificationRepository()
+			svc := NewNotificationService(repo, nil, prefs, providerSet())
+			svc.now = func() time.Time { return night }
+
+			userID := tt.userID
+			resp, err := svc.SendNotification(context.Background(), &models.SendNotificationRequest{
+				UserID:    &userID,
+				Channel:   tt.channel,
+				Type:      tt.notifType,
+				Priority:  tt.priority,
+				Recipient: "recipient",
+				Body:      "Hello",
+			})
+			if err != nil {
+				t.Fatalf("failed to send: %v", err)
+			}
+
+			n := repo.notifications[resp.NotificationID]
+			if n.Status != tt.wantStatus || n.Decision == nil || *n.Decision != tt.wantDecision {
+				t.Fatalf("expected %s/%s, got %s/%v", tt.wantStatus, tt.wantDecision, n.Status, n.Decision)
+			}
+			if tt.wantAfter {
+				if n.DeliverAfter == nil || !n.DeliverAfter.Time.Equal(morning) {
+					t.Errorf("expected delivery deferred until %v, got %v", morning, n.DeliverAfter)
+				}
+			} else if n.DeliverAfter != nil {
+				t.Errorf("expected no deferral, got %v", n.DeliverAfter)
+			}
+		})
+	}
+}
+
+func TestSendNotification_SystemWideSkipsPreferences(t *testing.T) {
+	repo := newMockNotificationRepository()
+	svc := NewNotificationService(repo, nil, newMockPreferenceRepository(), providerSet())
+
+	resp, err := svc.SendNotification(context.Background(), &models.SendNotificationRequest{
+		Channel:   models.ChannelEmail,
+		Type:      models.TypeSystemAlert,
+		Recipient: "ops@example.com",
+		Body:      "Maintenance tonight",
+	})
+	if err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	if n := repo.notifications[resp.NotificationID]; n.Decision != nil || n.Status != models.StatusQueued {
+		t.Errorf("expected a queued notification without a decision, got %s/%v", n.Status, n.Decision)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	svc := NewNotificationService(newMockNotificationRepository(), nil, newMockPreferenceRepository(), providerSet())
+	ctx := context.Background()
+
+	settings, err := svc.UpdatePreferences(ctx, inboxUser, &models.UpdatePreferencesRequest{
+		Preferences: []models.NotificationPreference{{Type: models.TypeMarketing, Channel: models.ChannelSMS, Enabled: false}},
+	})
+	if err != nil {
+		t.Fatalf("failed to update preferences: %v", err)
+	}
+	if len(settings.Preferences) != len(models.NotificationTypes)*len(models.NotificationChannels) {
+		t.Errorf("expected every type and channel, got %d preferences", len(settings.Preferences))
+	}
+	for _, pref := range settings.Preferences {
+		want := pref.Type != models.TypeMarketing || pref.Channel != models.ChannelSMS
+		if pref.Enabled != want {
+			t.Errorf("%s/%s: expected enabled=%v", pref.Type, pref.Channel, want)
+		}
+	}
+	if settings.QuietHours != nil {
+		t.Errorf("expected no quiet hours, got %+v", settings.QuietHours)
+	}
+
+	_, err = svc.UpdatePreferences(ctx, inboxUser, &models.UpdatePreferencesRequest{
+		Preferences: []models.NotificationPreference{{Type: "carrier_pigeon", Channel: models.ChannelSMS}},
+	})
+	if err == nil || err.Code != errors.ErrCodeValidation {
+		t.Errorf("expected validation error for an unknown type, got %v", err)
+	}
+
+	if _, err := svc.SetQuietHours(ctx, inboxUser, &models.QuietHours{Start: "22:00", End: "22:00", Timezone: "UTC"}); err == nil || err.Code != errors.ErrCodeValidation {
+		t.Errorf("expected validation error for an empty window, got %v", err)
+	}
+	if _, err := svc.SetQuietHours(ctx, inboxUser, &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"}); err != nil {
+		t.Fatalf("failed to set quiet hours: %v", err)
+	}
+	if settings, _ := svc.GetSettings(ctx, inboxUser); settings.QuietHours == nil || settings.QuietHours.Start != "22:00" {
+		t.Errorf("expected quiet hours in settings, got %+v", settings.QuietHours)
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// Provider names, used in configuration, on notifications and in receipt URLs.
+const (
+	ProviderSimulation = "simulation"
+	ProviderSMTP       = "smtp"
+	ProviderSMSGateway = "sms_gateway"
+	ProviderWebhook    = "webhook"
+	ProviderInbox      = "inbox"
+)
+
+// Provider delivers notifications of one or more channels.
+//
+// Send hands a notification to the provider. An error means the provider did
+// not accept it: errors.Unavailable and errors.Timeout are temporary (network
+// trouble, 5xx responses), other codes mean the provider rejected the message.
+type Provider interface {
+	Name() string
+	Channels() []models.NotificationChannel
+	Send(ctx context.Context, notif *models.Notification) (*SendResult, *errors.Error)
+}
+
+// SendResult is a provider's acceptance of a notification.
+type SendResult struct {
+	MessageID string // The provider's ID for the message, matched against delivery receipts
+	Delivered bool   // The provider confirmed delivery; otherwise a receipt is awaited
+}
+
+// DeliveryReceipt reports the final outcome of a message a provider accepted.
+type DeliveryReceipt struct {
+	MessageID     string
+	Delivered     bool
+	FailureReason string
+}
+
+// ReceiptParser is implemented by providers that report delivery through
+// callbacks to POST /v1/notifications/receipts/{provider}. ParseReceipt
+// authenticates the callback and returns the receipt it carries.
+type ReceiptParser interface {
+	ParseReceipt(header http.Header, body []byte) (*DeliveryReceipt, *errors.Error)
+}
+
+// ReceiptFunc records a delivery receipt. Providers that learn the outcome of
+// a message in-process, rather than through a callback, report it with one.
+type ReceiptFunc func(ctx context.Context, provider string, receipt *DeliveryReceipt)
+
+// ProviderSet maps each channel to the provider that delivers it.
+type ProviderSet map[models.NotificationChannel]Provider
+
+// Validate checks that every channel has a provider that supports it.
+func (ps ProviderSet) Validate() error {
+	for _, channel := range allChannels {
+		provider, ok := ps[channel]
+		if !ok {
+			return fmt.Errorf("no provider configured for channel %s", channel)
+		}
+		if !slices.Contains(provider.Channels(), channel) {
+			return fmt.Errorf("provider %s cannot deliver channel %s", provider.Name(), channel)
+		}
+	}
+	return nil
+}
+
+// ByName returns the configured provider with the given name.
+func (ps ProviderSet) ByName(name string) (Provider, bool) {
+	for _, provider := range ps {
+		if provider.Name() == name {
+			return provider, true
+		}
+	}
+	return nil, false
+}
+
+// allChannels lists every notification channel.
+var allChannels = []models.NotificationChannel{
+	models.ChannelSMS, models.ChannelEmail, models.ChannelPush, models.ChannelInApp,
+}
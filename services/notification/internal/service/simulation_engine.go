@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/vnykmshr/nivo/services/notification/internal/models"
@@ -15,8 +16,6 @@ type SimulationConfig struct {
 	DeliveryDelayMs      int     // Delay in milliseconds before marking as sent
 	FinalDelayMs         int     // Delay in milliseconds before final status (delivered/failed)
 	FailureRatePercent   float64 // Percentage of notifications that should fail (0-100)
-	MaxRetryAttempts     int     // Maximum retry attempts for failed notifications
-	RetryDelayMs         int     // Base delay between retries (exponential backoff)
 	CriticalPriorityOnly bool    // Process only critical priority (for testing)
 }
 
@@ -26,80 +25,73 @@ func DefaultSimulationConfig() SimulationConfig {
 		DeliveryDelayMs:    1000, // 1 second to mark as sent
 		FinalDelayMs:       2000, // 2 seconds to mark as delivered/failed
 		FailureRatePercent: 10.0, // 10% failure rate
-		MaxRetryAttempts:   3,    // Retry up to 3 times
-		RetryDelayMs:       2000, // 2 seconds base delay
 	}
 }
 
-// SimulationEngine simulates notification delivery with realistic behavior.
+// SimulationEngine is a provider that simulates delivery of every channel
+// without sending anything. It accepts a notification after a network delay
+// and reports delivery, or a random failure, through a receipt after a further
+// delay, like a real SMS gateway would.
 type SimulationEngine struct {
-	config SimulationConfig
-	repo   NotificationRepositoryInterface
-	rand   *rand.Rand
+	config   SimulationConfig
+	receipts ReceiptFunc
+	rand     *rand.Rand
+	mu       sync.Mutex // Guards rand, which is not safe for concurrent use
 }
 
 // NewSimulationEngine creates a new simulation engine.
-func NewSimulationEngine(config SimulationConfig, repo NotificationRepositoryInterface) *SimulationEngine {
+func NewSimulationEngine(config SimulationConfig) *SimulationEngine {
 	return &SimulationEngine{
 		config: config,
-		repo:   repo,
 		//nolint:gosec // Using math/rand for simulation randomness, not cryptographic security
 		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
-// ProcessNotification simulates processing a single notification.
-// This is the core simulation logic that mimics real-world delivery.
-func (e *SimulationEngine) ProcessNotification(ctx context.Context, notif *models.Notification) *errors.Error {
-	log.Printf("[simulation] Processing notification %s (type=%s, channel=%s, priority=%s)",
-		notif.ID, notif.Type, notif.Channel, notif.Priority)
-
-	// Step 1: Simulate network delay before sending
-	time.Sleep(time.Duration(e.config.DeliveryDelayMs) * time.Millisecond)
-
-	// Update status to 'sent'
-	if err := e.repo.UpdateStatus(ctx, notif.ID, models.StatusSent, nil); err != nil {
-		log.Printf("[simulation] Failed to update notification %s to sent: %v", notif.ID, err)
-		return err
-	}
-
-	log.Printf("[simulation] Notification %s marked as sent", notif.ID)
-
-	// Step 2: Simulate processing delay before final status
-	time.Sleep(time.Duration(e.config.FinalDelayMs) * time.Millisecond)
-
-	// Step 3: Determine if delivery should fail (random simulation)
-	shouldFail := e.shouldSimulateFailure()
+// SetReceiptFunc sets where simulated delivery receipts are reported.
+func (e *SimulationEngine) SetReceiptFunc(receipts ReceiptFunc) {
+	e.receipts = receipts
+}
 
-	if shouldFail {
-		// Simulate failure
-		failureReason := e.generateFailureReason(notif.Channel)
-		if err := e.repo.UpdateStatus(ctx, notif.ID, models.StatusFailed, &failureReason); err != nil {
-			log.Printf("[simulation] Failed to update notification %s to failed: %v", notif.ID, err)
-			return err
-		}
+// Name returns the provider name.
+func (e *SimulationEngine) Name() string {
+	return ProviderSimulation
+}
 
-		log.Printf("[simulation] Notification %s marked as failed: %s", notif.ID, failureReason)
+// Channels returns the channels the simulation delivers: all of them.
+func (e *SimulationEngine) Channels() []models.NotificationChannel {
+	return allChannels
+}
 
-		// Check if retry is needed
-		if notif.RetryCount < e.config.MaxRetryAttempts {
-			log.Printf("[simulation] Notification %s will be retried (attempt %d/%d)",
-				notif.ID, notif.RetryCount+1, e.config.MaxRetryAttempts)
-		} else {
-			log.Printf("[simulation] Notification %s exceeded max retries", notif.ID)
-		}
+// Send simulates handing a notification to a provider. The outcome is
+// reported through the receipt function after SimulationConfig.FinalDelayMs.
+func (e *SimulationEngine) Send(ctx context.Context, notif *models.Notification) (*SendResult, *errors.Error) {
+	log.Printf("[simulation] Processing notification %s (type=%s, channel=%s, priority=%s)",
+		notif.ID, notif.Type, notif.Channel, notif.Priority)
 
-		return nil
+	// Simulate network delay before the provider accepts the message
+	select {
+	case <-time.After(time.Duration(e.config.DeliveryDelayMs) * time.Millisecond):
+	case <-ctx.Done():
+		return nil, errors.Unavailable("simulation interrupted")
 	}
 
-	// Simulate successful delivery
-	if err := e.repo.UpdateStatus(ctx, notif.ID, models.StatusDelivered, nil); err != nil {
-		log.Printf("[simulation] Failed to update notification %s to delivered: %v", notif.ID, err)
-		return err
+	messageID := "sim-" + notif.ID
+	if e.receipts == nil {
+		return &SendResult{MessageID: messageID, Delivered: true}, nil
 	}
 
-	log.Printf("[simulation] Notification %s marked as delivered successfully", notif.ID)
-	return nil
+	receiptCtx := context.WithoutCancel(ctx)
+	time.AfterFunc(time.Duration(e.config.FinalDelayMs)*time.Millisecond, func() {
+		receipt := &DeliveryReceipt{MessageID: messageID, Delivered: true}
+		if e.shouldSimulateFailure() {
+			receipt.Delivered = false
+			receipt.FailureReason = e.generateFailureReason(notif.Channel)
+		}
+		e.receipts(receiptCtx, ProviderSimulation, receipt)
+	})
+
+	return &SendResult{MessageID: messageID}, nil
 }
 
 // shouldSimulateFailure determines if the current notification should fail.
@@ -111,7 +103,9 @@ func (e *SimulationEngine) shouldSimulateFailure() bool {
 		return true
 	}
 
+	e.mu.Lock()
 	randomValue := e.rand.Float64() * 100 // 0-100
+	e.mu.Unlock()
 	return randomValue < e.config.FailureRatePercent
 }
 
@@ -152,25 +146,7 @@ func (e *SimulationEngine) generateFailureReason(channel models.NotificationChan
 	}
 
 	// Pick random reason
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	return channelReasons[e.rand.Intn(len(channelReasons))]
 }
-
-// CalculateRetryDelay calculates the delay before next retry using exponential backoff.
-func (e *SimulationEngine) CalculateRetryDelay(retryCount int) time.Duration {
-	// Exponential backoff: base * 2^retryCount
-	baseDelay := time.Duration(e.config.RetryDelayMs) * time.Millisecond
-	multiplier := 1 << retryCount // 2^retryCount
-	return baseDelay * time.Duration(multiplier)
-}
-
-// GetProcessingDelay returns the total processing delay for a notification.
-func (e *SimulationEngine) GetProcessingDelay() time.Duration {
-	return time.Duration(e.config.DeliveryDelayMs+e.config.FinalDelayMs) * time.Millisecond
-}
-
-// NotificationRepositoryInterface defines the repository methods needed by the simulation engine.
-type NotificationRepositoryInterface interface {
-	UpdateStatus(ctx context.Context, id string, status models.NotificationStatus, failureReason *string) *errors.Error
-	IncrementRetryCount(ctx context.Context, id string) *errors.Error
-	GetQueuedNotifications(ctx context.Context, limit int) ([]*models.Notification, *errors.Error)
-}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// SMSGatewayConfig holds the HTTP SMS gateway SMS is sent through.
+type SMSGatewayConfig struct {
+	URL           string // Endpoint messages are POSTed to
+	APIKey        string // Sent as a bearer token
+	SenderID      string // Sender ID shown to recipients
+	ReceiptSecret string // Key of the HMAC-SHA256 signature on delivery receipts
+	Timeout       time.Duration
+}
+
+// SMSGatewayProvider sends SMS through a generic HTTP SMS gateway and takes
+// its delivery receipts.
+//
+// A message is POSTed as JSON {"to", "from", "message", "reference"} and the
+// gateway replies with {"message_id"}. Receipts are POSTed back as JSON
+// {"message_id", "status", "error"} with the hex HMAC-SHA256 of the body in
+// the X-Signature header; status "delivered" is a delivery and "failed",
+// "undelivered", "rejected" or "expired" a failure.
+type SMSGatewayProvider struct {
+	config     SMSGatewayConfig
+	httpClient *http.Client
+}
+
+// NewSMSGatewayProvider creates a new SMS gateway provider.
+func NewSMSGatewayProvider(config SMSGatewayConfig) *SMSGatewayProvider {
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	return &SMSGatewayProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Name returns the provider name.
+func (p *SMSGatewayProvider) Name() string {
+	return ProviderSMSGateway
+}
+
+// Channels returns the channels the provider delivers.
+func (p *SMSGatewayProvider) Channels() []models.NotificationChannel {
+	return []models.NotificationChannel{models.ChannelSMS}
+}
+
+type smsGatewayRequest struct {
+	To        string `json:"to"`
+	From      string `json:"from,omitempty"`
+	Message   string `json:"message"`
+	Reference string `json:"reference"`
+}
+
+type smsGatewayResponse struct {
+	MessageID string `json:"message_id"`
+}
+
+// Send submits an SMS to the gateway. Delivery is reported by a receipt.
+func (p *SMSGatewayProvider) Send(ctx context.Context, notif *models.Notification) (*SendResult, *errors.Error) {
+	body, err := json.Marshal(smsGatewayRequest{
+		To:        notif.Recipient,
+		From:      p.config.SenderID,
+		Message:   notif.Body,
+		Reference: notif.ID,
+	})
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to encode SMS")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to create SMS gateway request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Unavailable("SMS gateway unreachable: " + err.Error())
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if httpErr := providerHTTPError("SMS gateway", resp.StatusCode, respBody); httpErr != nil {
+		return nil, httpErr
+	}
+
+	var accepted smsGatewayResponse
+	if err := json.Unmarshal(respBody, &accepted); err != nil || accepted.MessageID == "" {
+		return nil, errors.Unavailable("SMS gateway returned no message ID")
+	}
+
+	return &SendResult{MessageID: accepted.MessageID}, nil
+}
+
+type smsGatewayReceipt struct {
+	MessageID string `json:"message_id"`
+	Status    string `json:"status"`
+	Error     string `json:"error"`
+}
+
+// ParseReceipt verifies and parses a delivery receipt callback. Interim
+// statuses (e.g. "queued", "sent") return a nil receipt.
+func (p *SMSGatewayProvider) ParseReceipt(header http.Header, body []byte) (*DeliveryReceipt, *errors.Error) {
+	if p.config.ReceiptSecret == "" {
+		return nil, errors.Unauthorized("SMS gateway receipts are not configured")
+	}
+
+	signature, err := hex.DecodeString(header.Get("X-Signature"))
+	if err != nil || !hmac.Equal(signature, hmacSHA256(p.config.ReceiptSecret, body)) {
+		return nil, errors.Unauthorized("invalid receipt signature")
+	}
+
+	var receipt smsGatewayReceipt
+	if err := json.Unmarshal(body, &receipt); err != nil || receipt.MessageID == "" {
+		return nil, errors.BadRequest("invalid receipt")
+	}
+
+	switch receipt.Status {
+	case "delivered":
+		return &DeliveryReceipt{MessageID: receipt.MessageID, Delivered: true}, nil
+	case "failed", "undelivered", "rejected", "expired":
+		reason := receipt.Error
+		if reason == "" {
+			reason = "SMS " + receipt.Status
+		}
+		return &DeliveryReceipt{MessageID: receipt.MessageID, FailureReason: reason}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// providerHTTPError classifies a provider's HTTP response: 4xx is a rejection
+// of the message and 5xx a temporary failure.
+func providerHTTPError(provider string, status int, body []byte) *errors.Error {
+	switch {
+	case status >= 200 && status < 300:
+		return nil
+	case status >= 400 && status < 500 && status != http.StatusTooManyRequests && status != http.StatusRequestTimeout:
+		return errors.BadRequest(fmt.Sprintf("%s rejected message: %d %s", provider, status, truncate(string(body), 200)))
+	default:
+		return errors.Unavailable(fmt.Sprintf("%s returned %d", provider, status))
+	}
+}
+
+func hmacSHA256(secret string, data []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
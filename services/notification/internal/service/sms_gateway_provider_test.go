@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+const testReceiptSecret = "receipt-secret-0123456789abcdef"
+
+func TestSMSGatewayProvider_Send(t *testing.T) {
+	var got smsGatewayRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"message_id":"gw-123","status":"accepted"}`))
+	}))
+	defer server.Close()
+
+	provider := NewSMSGatewayProvider(SMSGatewayConfig{URL: server.URL, APIKey: "key-1", SenderID: "NIVOMN"})
+	result, err := provider.Send(context.Background(), &models.Notification{
+		ID:        "notif-1",
+		Channel:   models.ChannelSMS,
+		Recipient: "+919876543210",
+		Body:      "Your OTP is 123456",
+	})
+	if err != nil {
+		t.Fatalf("expected SMS to be accepted, got %v", err)
+	}
+
+	// Delivery is confirmed later by a receipt
+	if result.MessageID != "gw-123" || result.Delivered {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	want := smsGatewayRequest{To: "+919876543210", From: "NIVOMN", Message: "Your OTP is 123456", Reference: "notif-1"}
+	if got != want || auth != "Bearer key-1" {
+		t.Errorf("unexpected request %+v with authorization %q", got, auth)
+	}
+}
+
+func TestSMSGatewayProvider_SendErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantCode errors.ErrorCode
+	}{
+		{"rejected", http.StatusBadRequest, `{"error":"invalid number"}`, errors.ErrCodeBadRequest},
+		{"rate limited", http.StatusTooManyRequests, ``, errors.ErrCodeUnavailable},
+		{"gateway error", http.StatusBadGateway, ``, errors.ErrCodeUnavailable},
+		{"no message id", http.StatusOK, `{}`, errors.ErrCodeUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := NewSMSGatewayProvider(SMSGatewayConfig{URL: server.URL, APIKey: "key-1"})
+			_, err := provider.Send(context.Background(), &models.Notification{ID: "notif-1", Recipient: "+919876543210", Body: "Hi"})
+			if err == nil || err.Code != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func signedReceiptHeader(secret string, body []byte) http.Header {
+	header := http.Header{}
+	header.Set("X-Signature", hex.EncodeToString(hmacSHA256(secret, body)))
+	return header
+}
+
+func TestSMSGatewayProvider_ParseReceipt(t *testing.T) {
+	provider := NewSMSGatewayProvider(SMSGatewayConfig{ReceiptSecret: testReceiptSecret})
+
+	tests := []struct {
+		name     string
+		body     string
+		header   func(body []byte) http.Header
+		want     *DeliveryReceipt
+		wantCode errors.ErrorCode
+	}{
+		{
+			name: "delivered",
+			body: `{"message_id":"gw-1","status":"delivered"}`,
+			want: &DeliveryReceipt{MessageID: "gw-1", Delivered: true},
+		},
+		{
+			name: "undelivered",
+			body: `{"message_id":"gw-1","status":"undelivered","error":"Handset unreachable"}`,
+			want: &DeliveryReceipt{MessageID: "gw-1", FailureReason: "Handset unreachable"},
+		},
+		{
+			name: "interim status",
+			body: `{"message_id":"gw-1","status":"sent"}`,
+		},
+		{
+			name:     "bad signature",
+			body:     `{"message_id":"gw-1","status":"delivered"}`,
+			header:   func(body []byte) http.Header { return signedReceiptHeader("wrong-secret", body) },
+			wantCode: errors.ErrCodeUnauthorized,
+		},
+		{
+			name:     "missing message id",
+			body:     `{"status":"delivered"}`,
+			wantCode: errors.ErrCodeBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := []byte(tt.body)
+			header := signedReceiptHeader(testReceiptSecret, body)
+			if tt.header != nil {
+				header = tt.header(body)
+			}
+
+			receipt, err := provider.ParseReceipt(header, body)
+			if tt.wantCode != "" {
+				if err == nil || err.Code != tt.wantCode {
+					t.Fatalf("expected %s, got %v", tt.wantCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if (receipt == nil) != (tt.want == nil) || (receipt != nil && *receipt != *tt.want) {
+				t.Errorf("expected receipt %+v, got %+v", tt.want, receipt)
+			}
+		})
+	}
+}
+
+func TestSMSGatewayProvider_ReceiptsRequireSecret(t *testing.T) {
+	provider := NewSMSGatewayProvider(SMSGatewayConfig{})
+	body := []byte(`{"message_id":"gw-1","status":"delivered"}`)
+
+	_, err := provider.ParseReceipt(signedReceiptHeader("", body), body)
+	if err == nil || err.Code != errors.ErrCodeUnauthorized {
+		t.Fatalf("expected unauthorized without a receipt secret, got %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// SMTPConfig holds the SMTP server email is sent through.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Leave empty for servers without authentication, like MailHog
+	Password string
+	From     string // Sender address, e.g. "Nivo Money <no-reply@nivomoney.com>"
+	StartTLS bool   // Require STARTTLS before authenticating and sending
+	Timeout  time.Duration
+}
+
+// SMTPProvider sends email notifications through an SMTP server. Email has no
+// delivery receipts, so a message the server accepts counts as delivered.
+type SMTPProvider struct {
+	config SMTPConfig
+	from   *mail.Address
+}
+
+// NewSMTPProvider creates a new SMTP email provider.
+func NewSMTPProvider(config SMTPConfig) (*SMTPProvider, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP sender address %q: %w", config.From, err)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	return &SMTPProvider{config: config, from: from}, nil
+}
+
+// Name returns the provider name.
+func (p *SMTPProvider) Name() string {
+	return ProviderSMTP
+}
+
+// Channels returns the channels the provider delivers.
+func (p *SMTPProvider) Channels() []models.NotificationChannel {
+	return []models.NotificationChannel{models.ChannelEmail}
+}
+
+// Send sends an email notification. Permanent (5xx) SMTP replies are returned
+// as rejections and everything else as temporary errors.
+func (p *SMTPProvider) Send(ctx context.Context, notif *models.Notification) (*SendResult, *errors.Error) {
+	to, err := mail.ParseAddress(notif.Recipient)
+	if err != nil {
+		return nil, errors.BadRequest("invalid email address")
+	}
+
+	messageID := fmt.Sprintf("<%s@%s>", notif.ID, p.from.Address[strings.LastIndex(p.from.Address, "@")+1:])
+	message, err := p.buildMessage(notif, to, messageID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to build email")
+	}
+
+	if err := p.deliver(ctx, to.Address, message); err != nil {
+		return nil, smtpError(err)
+	}
+
+	return &SendResult{MessageID: messageID, Delivered: true}, nil
+}
+
+// deliver runs the SMTP conversation for one message.
+func (p *SMTPProvider) deliver(ctx context.Context, to string, message []byte) error {
+	addr := net.JoinHostPort(p.config.Host, strconv.Itoa(p.config.Port))
+	dialer := &net.Dialer{Timeout: p.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(p.config.Timeout))
+
+	client, err := smtp.NewClient(conn, p.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	if p.config.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: p.config.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if p.config.Username != "" {
+		// PlainAuth refuses to send credentials without TLS, except to localhost
+		if err := client.Auth(smtp.PlainAuth("", p.config.Username, p.config.Password, p.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(p.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage renders the notification as a MIME message: plain text, or
+// multipart/alternative with an HTML part when the notification has an HTML
+// body.
+func (p *SMTPProvider) buildMessage(notif *models.Notification, to *mail.Address, messageID string) ([]byte, error) {
+	var body bytes.Buffer
+	contentType := "text/plain; charset=utf-8"
+	if notif.HTMLBody == "" {
+		if err := writeQuotedPrintable(&body, notif.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		parts := multipart.NewWriter(&body)
+		contentType = "multipart/alternative; boundary=" + parts.Boundary()
+		// Clients show the last part they support, so the HTML goes last
+		for _, part := range [][2]string{
+			{"text/plain; charset=utf-8", notif.Body},
+			{"text/html; charset=utf-8", notif.HTMLBody},
+		} {
+			w, err := parts.CreatePart(textproto.MIMEHeader{
+				"Content-Type":              {part[0]},
+				"Content-Transfer-Encoding": {"quoted-printable"},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := writeQuotedPrintable(w, part[1]); err != nil {
+				return nil, err
+			}
+		}
+		if err := parts.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", p.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", notif.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", contentType},
+	}
+	if notif.HTMLBody == "" {
+		headers = append(headers, [2]string{"Content-Transfer-Encoding", "quoted-printable"})
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h[0], h[1])
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+// writeQuotedPrintable writes text quoted-printable encoded.
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(text)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// smtpError classifies an SMTP failure: 5xx replies are permanent rejections.
+func smtpError(err error) *errors.Error {
+	if protoErr, ok := err.(*textproto.Error); ok && protoErr.Code >= 500 {
+		return errors.BadRequest(fmt.Sprintf("SMTP server rejected email: %d %s", protoErr.Code, protoErr.Msg))
+	}
+	return errors.Unavailable("SMTP delivery failed: " + err.Error())
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// fakeSMTPServer is a minimal MailHog-style SMTP server that records the
+// messages it accepts. Recipients containing "reject" are refused with 550.
+type fakeSMTPServer struct {
+	listener net.Listener
+	messages chan smtpMessage
+}
+
+type smtpMessage struct {
+	from, to string
+	data     string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: listener, messages: make(chan smtpMessage, 10)}
+	go s.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(textproto.NewConn(conn))
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn *textproto.Conn) {
+	defer func() { _ = conn.Close() }()
+	_ = conn.PrintfLine("220 localhost ESMTP fake")
+
+	var msg smtpMessage
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			_ = conn.PrintfLine("250 localhost")
+		case "MAIL":
+			msg.from = line
+			_ = conn.PrintfLine("250 OK")
+		case "RCPT":
+			if strings.Contains(line, "reject") {
+				_ = conn.PrintfLine("550 No such user")
+				continue
+			}
+			msg.to = line
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := conn.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data = string(data)
+			s.messages <- msg
+			_ = conn.PrintfLine("250 OK queued")
+		case "QUIT":
+			_ = conn.PrintfLine("221 Bye")
+			return
+		default:
+			_ = conn.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func newTestSMTPProvider(t *testing.T, port int) *SMTPProvider {
+	t.Helper()
+	provider, err := NewSMTPProvider(SMTPConfig{
+		Host: "127.0.0.1",
+		Port: port,
+		From: "Nivo Money <no-reply@nivomoney.com>",
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	return provider
+}
+
+func TestSMTPProvider_SendsEmail(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider := newTestSMTPProvider(t, server.port())
+
+	result, err := provider.Send(context.Background(), &models.Notification{
+		ID:        "notif-1",
+		Channel:   models.ChannelEmail,
+		Recipient: "Asha Rao <asha@example.com>",
+		Subject:   "Transfer received ₹500",
+		Body:      "You received ₹500 from Ravi.\nBalance: ₹1,500",
+	})
+	if err != nil {
+		t.Fatalf("expected email to be sent, got %v", err)
+	}
+	if !result.Delivered || result.MessageID != "<notif-1@nivomoney.com>" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	msg := <-server.messages
+	if msg.from != "MAIL FROM:<no-reply@nivomoney.com>" || !strings.HasPrefix(msg.to, "RCPT TO:<asha@example.com>") {
+		t.Errorf("unexpected envelope: %q, %q", msg.from, msg.to)
+	}
+	for _, want := range []string{
+		"To: \"Asha Rao\" <asha@example.com>",
+		"Subject: =?utf-8?q?Transfer_received_=E2=82=B9500?=",
+		"Message-ID: <notif-1@nivomoney.com>",
+		"Content-Transfer-Encoding: quoted-printable",
+		"You received =E2=82=B9500 from Ravi.",
+	} {
+		if !strings.Contains(msg.data, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, msg.data)
+		}
+	}
+}
+
+func TestSMTPProvider_SendsHTMLAlternative(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider := newTestSMTPProvider(t, server.port())
+
+	_, err := provider.Send(context.Background(), &models.Notification{
+		ID:        "notif-2",
+		Channel:   models.ChannelEmail,
+		Recipient: "asha@example.com",
+		Subject:   "Welcome",
+		Body:      "Welcome, Asha!",
+		HTMLBody:  "<p>Welcome, <b>Asha</b>!</p>",
+	})
+	if err != nil {
+		t.Fatalf("expected email to be sent, got %v", err)
+	}
+
+	msg, readErr := mail.ReadMessage(strings.NewReader((<-server.messages).data))
+	if readErr != nil {
+		t.Fatalf("failed to read message: %v", readErr)
+	}
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("expected multipart/alternative, got %q", mediaType)
+	}
+
+	var got []string
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, partErr := parts.NextPart()
+		if partErr == io.EOF {
+			break
+		}
+		if partErr != nil {
+			t.Fatalf("failed to read part: %v", partErr)
+		}
+		content, _ := io.ReadAll(part)
+		got = append(got, part.Header.Get("Content-Type")+": "+string(content))
+	}
+
+	want := []string{
+		"text/plain; charset=utf-8: Welcome, Asha!",
+		"text/html; charset=utf-8: <p>Welcome, <b>Asha</b>!</p>",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected parts %q, got %q", want, got)
+	}
+}
+
+func TestSMTPProvider_Failures(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	// Find a port nothing listens on
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	tests := []struct {
+		name      string
+		port      int
+		recipient string
+		wantCode  errors.ErrorCode
+	}{
+		{"invalid address", server.port(), "not an address", errors.ErrCodeBadRequest},
+		{"recipient refused", server.port(), "reject@example.com", errors.ErrCodeBadRequest},
+		{"server unreachable", closedPort, "asha@example.com", errors.ErrCodeUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestSMTPProvider(t, tt.port)
+			_, err := provider.Send(context.Background(), &models.Notification{
+				ID:        "notif-" + strconv.Itoa(tt.port),
+				Recipient: tt.recipient,
+				Body:      "Hello",
+			})
+			if err == nil || err.Code != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
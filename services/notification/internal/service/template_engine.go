@@ -1,97 +1,205 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
-	"regexp"
-	"strings"
+	htmltemplate "html/template"
+	"io"
+	"slices"
+	"text/template"
+	"text/template/parse"
 )
 
-// TemplateEngine handles variable substitution in notification templates.
+// maxRenderedSize caps a rendered template, so a template looping over a
+// huge range fails instead of exhausting memory.
+const maxRenderedSize = 64 << 10
+
+// TemplateEngine renders notification templates written in Go's template
+// language ({{.variable}}, {{if}}, {{range}}, pipelines). Templates are
+// sandboxed: they see only the variables of the notification, which are plain
+// JSON values without methods, and the functions in templateFuncs. HTML
+// templates are escaped contextually.
 type TemplateEngine struct {
-	// Regex to match {{variable_name}} patterns
-	variablePattern *regexp.Regexp
+	funcs template.FuncMap
 }
 
 // NewTemplateEngine creates a new template engine.
 func NewTemplateEngine() *TemplateEngine {
-	return &TemplateEngine{
-		variablePattern: regexp.MustCompile(`\{\{([a-zA-Z0-9_]+)\}\}`),
+	return &TemplateEngine{funcs: templateFuncs}
+}
+
+// Check parses a template, reporting syntax errors and unknown functions.
+func (e *TemplateEngine) Check(text string, html bool) error {
+	if html {
+		_, err := htmltemplate.New("template").Funcs(htmltemplate.FuncMap(e.funcs)).Parse(text)
+		return err
 	}
+	_, err := e.parse(text)
+	return err
 }
 
-// Render replaces all {{variable}} placeholders in the template with actual values.
-// Returns the rendered text and a list of variables that were substituted.
-func (e *TemplateEngine) Render(template string, variables map[string]interface{}) (string, []string) {
-	usedVariables := make([]string, 0)
-	rendered := template
+// Render renders a plain-text template with the variables. It returns the
+// rendered text and the variables the template used.
+func (e *TemplateEngine) Render(text string, variables map[string]interface{}) (string, []string, error) {
+	tmpl, err := e.parse(text)
+	if err != nil {
+		return "", nil, err
+	}
+	data, used := templateData(tmpl, variables)
+	rendered, err := execute(tmpl.Execute, data)
+	return rendered, used, err
+}
 
-	// Find all variable placeholders
-	matches := e.variablePattern.FindAllStringSubmatch(template, -1)
+// RenderHTML renders an HTML template with the variables, escaping them for
+// the context they appear in. It returns the rendered HTML and the variables
+// the template used.
+func (e *TemplateEngine) RenderHTML(text string, variables map[string]interface{}) (string, []string, error) {
+	tmpl, err := e.parse(text)
+	if err != nil {
+		return "", nil, err
+	}
+	html, err := htmltemplate.New("template").Funcs(htmltemplate.FuncMap(e.funcs)).Parse(text)
+	if err != nil {
+		return "", nil, err
+	}
+	data, used := templateData(tmpl, variables)
+	rendered, err := execute(html.Execute, data)
+	return rendered, used, err
+}
 
-	for _, match := range matches {
-		if len(match) < 2 {
-			continue
+// ExtractVariables returns the names of the variables a template refers to,
+// or nil if it does not parse.
+func (e *TemplateEngine) ExtractVariables(text string) []string {
+	tmpl, err := e.parse(text)
+	if err != nil {
+		return nil
+	}
+	return referencedVariables(tmpl)
+}
+
+// Validate returns the variables a template refers to that are missing from
+// the variables map.
+func (e *TemplateEngine) Validate(text string, variables map[string]interface{}) []string {
+	missing := make([]string, 0)
+	for _, name := range e.ExtractVariables(text) {
+		if _, exists := variables[name]; !exists {
+			missing = append(missing, name)
 		}
+	}
+	return missing
+}
 
-		placeholder := match[0]  // Full match: {{variable_name}}
-		variableName := match[1] // Captured group: variable_name
+func (e *TemplateEngine) parse(text string) (*template.Template, error) {
+	return template.New("template").Funcs(e.funcs).Parse(text)
+}
 
-		// Get variable value
-		value, exists := variables[variableName]
-		if !exists {
-			// Keep placeholder if variable not provided
-			continue
-		}
+// templateData returns the data a template is executed with: the variables,
+// with those the template refers to but which were not provided set to "" so
+// they render empty and test false. It also returns the variables used.
+func templateData(tmpl *template.Template, variables map[string]interface{}) (map[string]interface{}, []string) {
+	data := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		data[name] = value
+	}
 
-		// Convert value to string
-		var stringValue string
-		switch v := value.(type) {
-		case string:
-			stringValue = v
-		case int:
-			stringValue = fmt.Sprintf("%d", v)
-		case int64:
-			stringValue = fmt.Sprintf("%d", v)
-		case float64:
-			stringValue = fmt.Sprintf("%.2f", v)
-		case bool:
-			stringValue = fmt.Sprintf("%t", v)
-		default:
-			stringValue = fmt.Sprintf("%v", v)
+	used := make([]string, 0)
+	for _, name := range referencedVariables(tmpl) {
+		if _, exists := variables[name]; exists {
+			used = append(used, name)
+		} else {
+			data[name] = ""
 		}
-
-		// Replace placeholder with value
-		rendered = strings.ReplaceAll(rendered, placeholder, stringValue)
-		usedVariables = append(usedVariables, variableName)
 	}
+	return data, used
+}
 
-	return rendered, usedVariables
+// execute runs a parsed template, failing once its output exceeds
+// maxRenderedSize.
+func execute(run func(w io.Writer, data any) error, data map[string]interface{}) (string, error) {
+	out := &limitedBuffer{limit: maxRenderedSize}
+	if err := run(out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
 }
 
-// ExtractVariables extracts all variable names from a template.
-func (e *TemplateEngine) ExtractVariables(template string) []string {
-	matches := e.variablePattern.FindAllStringSubmatch(template, -1)
-	variables := make([]string, 0, len(matches))
+// limitedBuffer is a buffer that refuses writes beyond its limit.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
 
-	for _, match := range matches {
-		if len(match) >= 2 {
-			variables = append(variables, match[1])
-		}
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, fmt.Errorf("rendered template exceeds %d bytes", b.limit)
 	}
-
-	return variables
+	return b.Buffer.Write(p)
 }
 
-// Validate checks if all required variables are present in the provided variables map.
-func (e *TemplateEngine) Validate(template string, variables map[string]interface{}) []string {
-	required := e.ExtractVariables(template)
-	missing := make([]string, 0)
+// referencedVariables returns the top-level variables (.name or $.name) the
+// main template refers to, in order of first use. Fields inside range and
+// with blocks, and inside {{define}}d templates, belong to another value
+// and are not variables.
+func referencedVariables(tmpl *template.Template) []string {
+	var names []string
+	var walk func(node parse.Node, top bool)
+	add := func(name string) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
 
-	for _, varName := range required {
-		if _, exists := variables[varName]; !exists {
-			missing = append(missing, varName)
+	walk = func(node parse.Node, top bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, top)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, top)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd, top)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, top)
+			}
+		case *parse.ChainNode:
+			walk(n.Node, top)
+		case *parse.FieldNode:
+			if top {
+				add(n.Ident[0])
+			}
+		case *parse.VariableNode:
+			if n.Ident[0] == "$" && len(n.Ident) > 1 {
+				add(n.Ident[1])
+			}
+		case *parse.IfNode:
+			walk(n.Pipe, top)
+			walk(n.List, top)
+			walk(n.ElseList, top)
+		case *parse.RangeNode:
+			walk(n.Pipe, top)
+			walk(n.List, false)
+			walk(n.ElseList, top)
+		case *parse.WithNode:
+			walk(n.Pipe, top)
+			walk(n.List, false)
+			walk(n.ElseList, top)
+		case *parse.TemplateNode:
+			walk(n.Pipe, top)
 		}
 	}
 
-	return missing
+	if tmpl.Tree != nil {
+		walk(tmpl.Tree.Root, true)
+	}
+	return names
 }
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateEngine_Render(t *testing.T) {
+	engine := NewTemplateEngine()
+
+	tests := []struct {
+		name      string
+		template  string
+		variables map[string]interface{}
+		want      string
+		wantUsed  []string
+	}{
+		{
+			name:      "variables",
+			template:  "Hi {{.name}}, your OTP is {{.otp}}",
+			variables: map[string]interface{}{"name": "Asha", "otp": "123456", "unused": true},
+			want:      "Hi Asha, your OTP is 123456",
+			wantUsed:  []string{"name", "otp"},
+		},
+		{
+			name:      "money from JSON",
+			template:  "Received {{money .amount}}",
+			variables: map[string]interface{}{"amount": float64(12345678)},
+			want:      "Received ₹1,23,456.78",
+			wantUsed:  []string{"amount"},
+		},
+		{
+			name:      "conditional",
+			template:  "{{if .approved}}Verified{{else}}Rejected: {{.reason}}{{end}}",
+			variables: map[string]interface{}{"approved": false, "reason": "blurred photo"},
+			want:      "Rejected: blurred photo",
+			wantUsed:  []string{"approved", "reason"},
+		},
+		{
+			name:     "loop",
+			template: "{{range .items}}{{.name}} {{money .amount}}; {{end}}",
+			variables: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"name": "Rent", "amount": 2500000},
+				map[string]interface{}{"name": "Tea", "amount": 1500},
+			}},
+			want:     "Rent ₹25,000.00; Tea ₹15.00; ",
+			wantUsed: []string{"items"},
+		},
+		{
+			name:      "missing variables render empty",
+			template:  "Dear {{.name | default \"customer\"}},{{if .vip}} VIP{{end}} ref {{.ref}}.",
+			variables: map[string]interface{}{},
+			want:      "Dear customer, ref .",
+			wantUsed:  []string{},
+		},
+		{
+			name:      "masking",
+			template:  "A/c {{mask .account}} for {{maskEmail .email}}",
+			variables: map[string]interface{}{"account": "001234567890", "email": "asha@example.com"},
+			want:      "A/c XXXXXXXX7890 for a***@example.com",
+			wantUsed:  []string{"account", "email"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, used, err := engine.Render(tt.template, tt.variables)
+			if err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if strings.Join(used, ",") != strings.Join(tt.wantUsed, ",") {
+				t.Errorf("expected variables %v used, got %v", tt.wantUsed, used)
+			}
+		})
+	}
+}
+
+func TestTemplateEngine_RenderHTMLEscapes(t *testing.T) {
+	got, _, err := NewTemplateEngine().RenderHTML(`<p>Hi {{.name}}</p><a href="/r?ref={{.ref}}">View</a>`, map[string]interface{}{
+		"name": "<script>alert(1)</script>",
+		"ref":  "a&b",
+	})
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	want := `<p>Hi &lt;script&gt;alert(1)&lt;/script&gt;</p><a href="/r?ref=a%26b">View</a>`
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestTemplateEngine_Sandbox(t *testing.T) {
+	engine := NewTemplateEngine()
+
+	for name, template := range map[string]string{
+		"unknown function": `{{exec "ls"}}`,
+		"syntax error":     `{{if .approved}}unterminated`,
+	} {
+		if err := engine.Check(template, false); err == nil {
+			t.Errorf("%s: expected check to fail", name)
+		}
+	}
+
+	// A huge loop stops at the output limit instead of exhausting memory
+	if _, _, err := engine.Render(`{{range 100000000}}padding{{end}}`, nil); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("expected the output limit to stop rendering, got %v", err)
+	}
+
+	if _, _, err := engine.Render(`{{money .amount}}`, map[string]interface{}{"amount": "lots"}); err == nil {
+		t.Error("expected money to reject a non-number")
+	}
+}
+
+func TestTemplateEngine_ExtractVariables(t *testing.T) {
+	engine := NewTemplateEngine()
+
+	got := engine.ExtractVariables(`{{.a}} {{if .b}}{{.c}}{{end}} {{range .items}}{{.field}} {{$.d}}{{end}} {{with .e}}{{.inner}}{{end}}`)
+	if strings.Join(got, ",") != "a,b,c,items,d,e" {
+		t.Errorf("unexpected variables: %v", got)
+	}
+
+	missing := engine.Validate(`{{.a}} {{.b}}`, map[string]interface{}{"a": 1})
+	if len(missing) != 1 || missing[0] != "b" {
+		t.Errorf("expected b missing, got %v", missing)
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		amount   any
+		currency []string
+		want     string
+	}{
+		{0, nil, "₹0.00"},
+		{99, nil, "₹0.99"},
+		{100000, nil, "₹1,000.00"},
+		{int64(12345678), nil, "₹1,23,456.78"},
+		{float64(1234567890), nil, "₹1,23,45,678.90"},
+		{-50050, nil, "-₹500.50"},
+		{"250000", nil, "₹2,500.00"},
+		{12345678, []string{"usd"}, "$123,456.78"},
+		{12345678, []string{"AED"}, "AED 123,456.78"},
+	}
+
+	for _, tt := range tests {
+		got, err := formatMoney(tt.amount, tt.currency...)
+		if err != nil || got != tt.want {
+			t.Errorf("money(%v, %v): expected %q, got %q (%v)", tt.amount, tt.currency, tt.want, got, err)
+		}
+	}
+
+	if _, err := formatMoney(12.5); err == nil {
+		t.Error("expected a fractional amount of paise to be rejected")
+	}
+}
+
+func TestFormatDates(t *testing.T) {
+	// 18:45 UTC is 00:15 the next day in India
+	at := time.Date(2026, 3, 1, 18, 45, 0, 0, time.UTC)
+
+	tests := []struct {
+		format func(any) (string, error)
+		value  any
+		want   string
+	}{
+		{formatDate, at, "2 Mar 2026"},
+		{formatDate, "2026-03-01T18:45:00Z", "2 Mar 2026"},
+		{formatDate, "2026-03-01", "1 Mar 2026"},
+		{formatDate, float64(at.Unix()), "2 Mar 2026"},
+		{formatDateTime, at, "2 Mar 2026, 12:15 AM IST"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.format(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("%v: expected %q, got %q (%v)", tt.value, tt.want, got, err)
+		}
+	}
+
+	if _, err := formatDate("yesterday"); err == nil {
+		t.Error("expected an unparseable date to be rejected")
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{mask("9876543210"), "XXXXXX3210"},
+		{mask(1234567890123456, 6), "XXXXXXXXXX123456"},
+		{mask("123"), "XXX"},
+		{maskEmail("asha.rao@example.com"), "a***@example.com"},
+		{maskEmail("not-an-email"), "XXXXXXXXmail"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, tt.got)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// displayZone is the timezone dates are shown in. India has no daylight
+// saving, so a fixed zone avoids depending on the host's zoneinfo.
+var displayZone = time.FixedZone("IST", 5*60*60+30*60)
+
+// templateFuncs are the only functions templates can call besides Go's
+// built-ins (and, or, not, len, index, printf, eq, lt, ...).
+var templateFuncs = template.FuncMap{
+	"money":     formatMoney,
+	"date":      formatDate,
+	"datetime":  formatDateTime,
+	"mask":      mask,
+	"maskEmail": maskEmail,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"default":   defaultValue,
+}
+
+// currencySymbols are the symbols money writes instead of a currency code.
+var currencySymbols = map[string]string{
+	"INR": "₹",
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+}
+
+// formatMoney formats an amount in the smallest currency unit (paise for INR)
+// with its currency symbol: {{money .amount}} writes ₹1,23,456.78 for 12345678
+// and {{money .amount "USD"}} writes $123,456.78. Rupees are grouped the Indian
+// way, in lakhs and crores; other currencies in thousands.
+func formatMoney(amount any, currency ...string) (string, error) {
+	minor, err := toInt64(amount)
+	if err != nil {
+		return "", fmt.Errorf("money: %w", err)
+	}
+
+	code := "INR"
+	if len(currency) > 0 && currency[0] != "" {
+		code = strings.ToUpper(currency[0])
+	}
+	symbol, ok := currencySymbols[code]
+	if !ok {
+		symbol = code + " "
+	}
+
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	units := strconv.FormatInt(minor/100, 10)
+	if code == "INR" {
+		units = groupDigits(units, 2)
+	} else {
+		units = groupDigits(units, 3)
+	}
+
+	return fmt.Sprintf("%s%s%s.%02d", sign, symbol, units, minor%100), nil
+}
+
+// groupDigits separates the last three digits, then groups of size digits,
+// with commas.
+func groupDigits(digits string, size int) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	var groups []string
+	for len(head) > size {
+		groups = append([]string{head[len(head)-size:]}, groups...)
+		head = head[:len(head)-size]
+	}
+	groups = append([]string{head}, groups...)
+	return strings.Join(groups, ",") + "," + tail
+}
+
+// toInt64 converts a whole number, as decoded from JSON or passed in-process,
+// to an int64.
+func toInt64(value any) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt64 {
+			return 0, fmt.Errorf("%v is not a whole number", v)
+		}
+		return int64(v), nil
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("%v (%T) is not a number", value, value)
+	}
+}
+
+// formatDate formats a time as 2 Jan 2006 in Indian time.
+func formatDate(value any) (string, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return "", fmt.Errorf("date: %w", err)
+	}
+	return t.In(displayZone).Format("2 Jan 2006"), nil
+}
+
+// formatDateTime formats a time as 2 Jan 2006, 3:04 PM IST.
+func formatDateTime(value any) (string, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return "", fmt.Errorf("datetime: %w", err)
+	}
+	return t.In(displayZone).Format("2 Jan 2006, 3:04 PM MST"), nil
+}
+
+// toTime converts an RFC 3339 timestamp, a YYYY-MM-DD date or Unix seconds to
+// a time.
+func toTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		if t, err := time.ParseInLocation("2006-01-02", v, displayZone); err == nil {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("%q is not an RFC 3339 time or a YYYY-MM-DD date", v)
+	default:
+		seconds, err := toInt64(value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(seconds, 0), nil
+	}
+}
+
+// mask hides all but the last characters of a value, four by default:
+// {{mask .account_number}} writes XXXXXXXX1234.
+func mask(value any, keep ...int) string {
+	visible := 4
+	if len(keep) > 0 {
+		visible = max(keep[0], 0)
+	}
+
+	runes := []rune(fmt.Sprint(value))
+	hidden := max(len(runes)-visible, 0)
+	if hidden == 0 {
+		// Too short to show any of it
+		hidden = len(runes)
+	}
+	return strings.Repeat("X", hidden) + string(runes[hidden:])
+}
+
+// maskEmail hides the local part of an email address but its first
+// character: r***@example.com.
+func maskEmail(value any) string {
+	email := fmt.Sprint(value)
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return mask(email)
+	}
+	first := []rune(email[:at])[0]
+	return string(first) + "***" + email[at:]
+}
+
+// defaultValue returns value, or fallback if value is empty:
+// {{.name | default "there"}}.
+func defaultValue(fallback, value any) any {
+	if value == nil || value == "" {
+		return fallback
+	}
+	return value
+}
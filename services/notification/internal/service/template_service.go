@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// TemplateRepositoryInterface defines the template storage used by the service.
+type TemplateRepositoryInterface interface {
+	Create(ctx context.Context, template *models.NotificationTemplate) *errors.Error
+	GetByID(ctx context.Context, id string) (*models.NotificationTemplate, *errors.Error)
+	GetByName(ctx context.Context, name string, locales []string) (*models.NotificationTemplate, *errors.Error)
+	List(ctx context.Context, channel *models.NotificationChannel) ([]*models.NotificationTemplate, *errors.Error)
+	Update(ctx context.Context, id string, req *models.UpdateTemplateRequest) *errors.Error
+	ListVersions(ctx context.Context, templateID string) ([]*models.TemplateVersion, *errors.Error)
+	GetVersion(ctx context.Context, templateID string, version int) (*models.TemplateVersion, *errors.Error)
+}
+
+// renderedTemplate is a template rendered for a notification.
+type renderedTemplate struct {
+	Subject  string
+	Body     string
+	HTMLBody string
+	Used     []string // Variables the template used
+	Missing  []string // Variables the template refers to that were not provided
+}
+
+// findTemplate looks a template up by ID, which names one locale variant, or
+// by name, picking the variant for the locale or its fallbacks.
+func (s *NotificationService) findTemplate(ctx context.Context, idOrName, locale string) (*models.NotificationTemplate, *errors.Error) {
+	if _, uuidErr := uuid.Parse(idOrName); uuidErr == nil {
+		return s.templateRepo.GetByID(ctx, idOrName)
+	}
+	return s.templateRepo.GetByName(ctx, idOrName, models.LocaleFallbacks(locale))
+}
+
+// renderTemplate renders a template's subject and bodies. The HTML body is
+// only rendered when html is set.
+func (s *NotificationService) renderTemplate(template *models.NotificationTemplate, variables map[string]interface{}, html bool) (*renderedTemplate, *errors.Error) {
+	type templatePart struct {
+		text string
+		html bool
+		out  *string
+	}
+
+	rendered := &renderedTemplate{Used: make([]string, 0)}
+	parts := []templatePart{
+		{template.SubjectTemplate, false, &rendered.Subject},
+		{template.BodyTemplate, false, &rendered.Body},
+	}
+	if html {
+		parts = append(parts, templatePart{template.HTMLBodyTemplate, true, &rendered.HTMLBody})
+	}
+
+	for _, part := range parts {
+		if part.text == "" {
+			continue
+		}
+
+		render := s.templateEngine.Render
+		if part.html {
+			render = s.templateEngine.RenderHTML
+		}
+		out, used, err := render(part.text, variables)
+		if err != nil {
+			return nil, errors.Validation(fmt.Sprintf("failed to render template %s: %v", template.Name, err))
+		}
+
+		*part.out = out
+		rendered.Used = appendNew(rendered.Used, used...)
+		rendered.Missing = appendNew(rendered.Missing, s.templateEngine.Validate(part.text, variables)...)
+	}
+
+	return rendered, nil
+}
+
+// appendNew appends the names not already in names.
+func appendNew(names []string, more ...string) []string {
+	for _, name := range more {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// checkTemplate parses the parts of a template that are set, so templates
+// with syntax errors or unknown functions are rejected when saved rather than
+// when sent.
+func (s *NotificationService) checkTemplate(subject, body, html *string) *errors.Error {
+	parts := []struct {
+		field string
+		text  *string
+		html  bool
+	}{
+		{"subject_template", subject, false},
+		{"body_template", body, false},
+		{"html_body_template", html, true},
+	}
+
+	for _, part := range parts {
+		if part.text == nil {
+			continue
+		}
+		if err := s.templateEngine.Check(*part.text, part.html); err != nil {
+			return errors.Validation(fmt.Sprintf("invalid %s: %v", part.field, err))
+		}
+	}
+	return nil
+}
+
+// ListTemplateVersions retrieves the versions of a template, newest first.
+func (s *NotificationService) ListTemplateVersions(ctx context.Context, id string) ([]*models.TemplateVersion, *errors.Error) {
+	if _, uuidErr := uuid.Parse(id); uuidErr != nil {
+		return nil, errors.NotFoundWithID("template", id)
+	}
+	if _, err := s.templateRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.templateRepo.ListVersions(ctx, id)
+}
+
+// RollbackTemplate restores the content of an earlier version of a template.
+// The restored content becomes a new version, so the history is kept.
+func (s *NotificationService) RollbackTemplate(ctx context.Context, id string, version int) (*models.NotificationTemplate, *errors.Error) {
+	if _, uuidErr := uuid.Parse(id); uuidErr != nil {
+		return nil, errors.NotFoundWithID("template", id)
+	}
+
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version == template.Version {
+		return nil, errors.Validation(fmt.Sprintf("template is already at version %d", version))
+	}
+
+	previous, err := s.templateRepo.GetVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Update(ctx, id, &models.UpdateTemplateRequest{
+		SubjectTemplate:  &previous.SubjectTemplate,
+		BodyTemplate:     &previous.BodyTemplate,
+		HTMLBodyTemplate: &previous.HTMLBodyTemplate,
+	}); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[notification] Rolled back template %s (name=%s, locale=%s) from version %d to the content of version %d",
+		id, template.Name, template.Locale, template.Version, version)
+	return s.templateRepo.GetByID(ctx, id)
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+type mockTemplateRepository struct {
+	templates map[string]*models.NotificationTemplate
+	versions  map[string][]*models.TemplateVersion
+}
+
+func newMockTemplateRepository(templates ...*models.NotificationTemplate) *mockTemplateRepository {
+	m := &mockTemplateRepository{
+		templates: make(map[string]*models.NotificationTemplate),
+		versions:  make(map[string][]*models.TemplateVersion),
+	}
+	for _, template := range templates {
+		_ = m.Create(context.Background(), template)
+	}
+	return m
+}
+
+func (m *mockTemplateRepository) Create(ctx context.Context, template *models.NotificationTemplate) *errors.Error {
+	for _, existing := range m.templates {
+		if existing.Name == template.Name && existing.Locale == template.Locale {
+			return errors.Conflict("template with this name and locale already exists")
+		}
+	}
+	if template.ID == "" {
+		template.ID = uuid.New().String()
+	}
+	if template.Version == 0 {
+		template.Version = 1
+	}
+	m.templates[template.ID] = template
+	m.record(template)
+	return nil
+}
+
+func (m *mockTemplateRepository) record(template *models.NotificationTemplate) {
+	m.versions[template.ID] = append(m.versions[template.ID], &models.TemplateVersion{
+		TemplateID:       template.ID,
+		Version:          template.Version,
+		SubjectTemplate:  template.SubjectTemplate,
+		BodyTemplate:     template.BodyTemplate,
+		HTMLBodyTemplate: template.HTMLBodyTemplate,
+	})
+}
+
+func (m *mockTemplateRepository) GetByID(ctx context.Context, id string) (*models.NotificationTemplate, *errors.Error) {
+	template, ok := m.templates[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("template", id)
+	}
+	copied := *template
+	return &copied, nil
+}
+
+func (m *mockTemplateRepository) GetByName(ctx context.Context, name string, locales []string) (*models.NotificationTemplate, *errors.Error) {
+	for _, locale := range locales {
+		for _, template := range m.templates {
+			if template.Name == name && template.Locale == locale {
+				copied := *template
+				return &copied, nil
+			}
+		}
+	}
+	return nil, errors.NotFound("template")
+}
+
+func (m *mockTemplateRepository) List(ctx context.Context, channel *models.NotificationChannel) ([]*models.NotificationTemplate, *errors.Error) {
+	templates := make([]*models.NotificationTemplate, 0)
+	for _, template := range m.templates {
+		if channel == nil || template.Channel == *channel {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+func (m *mockTemplateRepository) Update(ctx context.Context, id string, req *models.UpdateTemplateRequest) *errors.Error {
+	template, ok := m.templates[id]
+	if !ok {
+		return errors.NotFoundWithID("template", id)
+	}
+	if req.SubjectTemplate != nil {
+		template.SubjectTemplate = *req.SubjectTemplate
+	}
+	if req.BodyTemplate != nil {
+		template.BodyTemplate = *req.BodyTemplate
+	}
+	if req.HTMLBodyTemplate != nil {
+		template.HTMLBodyTemplate = *req.HTMLBodyTemplate
+	}
+	if req.ChangesContent() {
+		template.Version++
+		m.record(template)
+	}
+	return nil
+}
+
+func (m *mockTemplateRepository) ListVersions(ctx context.Context, templateID string) ([]*models.TemplateVersion, *errors.Error) {
+	versions := append([]*models.TemplateVersion(nil), m.versions[templateID]...)
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version > versions[j].Version })
+	return versions, nil
+}
+
+func (m *mockTemplateRepository) GetVersion(ctx context.Context, templateID string, version int) (*models.TemplateVersion, *errors.Error) {
+	for _, v := range m.versions[templateID] {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, errors.NotFound(fmt.Sprintf("version %d of template", version))
+}
+
+var _ TemplateRepositoryInterface = (*mockTemplateRepository)(nil)
+
+func newTemplateFixture() (*NotificationService, *mockNotificationRepository, *mockTemplateRepository) {
+	templates := newMockTemplateRepository(
+		&models.NotificationTemplate{
+			Name:             "transfer_received_email",
+			Locale:           "en",
+			Channel:          models.ChannelEmail,
+			SubjectTemplate:  "You received {{money .amount}}",
+			BodyTemplate:     "{{.sender}} sent you {{money .amount}}.",
+			HTMLBodyTemplate: "<p><b>{{.sender}}</b> sent you {{money .amount}}.</p>",
+		},
+		&models.NotificationTemplate{
+			Name:            "transfer_received_email",
+			Locale:          "hi",
+			Channel:         models.ChannelEmail,
+			SubjectTemplate: "आपको {{money .amount}} मिले",
+			BodyTemplate:    "{{.sender}} ने आपको {{money .amount}} भेजे।",
+		},
+		&models.NotificationTemplate{
+			Name:         "otp_sms",
+			Locale:       "en",
+			Channel:      models.ChannelSMS,
+			BodyTemplate: "Your OTP is {{.otp}}",
+		},
+	)
+	repo := newMockNotificationRepository()
+	return NewNotificationService(repo, templates, newMockPreferenceRepository(), providerSet()), repo, templates
+}
+
+func TestSendNotification_RendersLocaleVariant(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		locale      string
+		channel     models.NotificationChannel
+		wantSubject string
+		wantBody    string
+		wantHTML    string
+	}{
+		{"english with html", "transfer_received_email", "", models.ChannelEmail, "You received ₹1,500.00", "Ravi sent you ₹1,500.00.", "<p><b>Ravi</b> sent you ₹1,500.00.</p>"},
+		{"hindi region falls back to hindi", "transfer_received_email", "hi-IN", models.ChannelEmail, "आपको ₹1,500.00 मिले", "Ravi ने आपको ₹1,500.00 भेजे।", ""},
+		{"missing locale falls back to english", "otp_sms", "hi_IN", models.ChannelSMS, "", "Your OTP is 123456", ""},
+		{"html only for email", "transfer_received_email", "en", models.ChannelInApp, "You received ₹1,500.00", "Ravi sent you ₹1,500.00.", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _ := newTemplateFixture()
+			templateID := tt.template
+
+			resp, err := svc.SendNotification(context.Background(), &models.SendNotificationRequest{
+				Channel:    tt.channel,
+				Type:       models.TypeTransactionAlert,
+				Recipient:  "asha@example.com",
+				TemplateID: &templateID,
+				Locale:     tt.locale,
+				Variables:  map[string]interface{}{"sender": "Ravi", "amount": float64(150000), "otp": "123456"},
+			})
+			if err != nil {
+				t.Fatalf("failed to send: %v", err)
+			}
+
+			n := repo.notifications[resp.NotificationID]
+			if n.Subject != tt.wantSubject || n.Body != tt.wantBody || n.HTMLBody != tt.wantHTML {
+				t.Errorf("expected %q / %q / %q, got %q / %q / %q", tt.wantSubject, tt.wantBody, tt.wantHTML, n.Subject, n.Body, n.HTMLBody)
+			}
+			if n.TemplateVersion == nil || *n.TemplateVersion != 1 {
+				t.Errorf("expected template version 1 recorded, got %v", n.TemplateVersion)
+			}
+		})
+	}
+}
+
+func TestSendNotification_RenderError(t *testing.T) {
+	svc, _, _ := newTemplateFixture()
+	templateID := "transfer_received_email"
+
+	_, err := svc.SendNotification(context.Background(), &models.SendNotificationRequest{
+		Channel:    models.ChannelEmail,
+		Type:       models.TypeTransactionAlert,
+		Recipient:  "asha@example.com",
+		TemplateID: &templateID,
+		Variables:  map[string]interface{}{"sender": "Ravi", "amount": "a lot"},
+	})
+	if err == nil || err.Code != errors.ErrCodeValidation {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestTemplateVersions_Rollback(t *testing.T) {
+	svc, _, templates := newTemplateFixture()
+	ctx := context.Background()
+
+	otp, _ := templates.GetByName(ctx, "otp_sms", []string{"en"})
+	for _, body := range []string{"OTP: {{.otp}}", "{{.otp}} is your Nivo OTP"} {
+		body := body
+		if err := svc.UpdateTemplate(ctx, otp.ID, &models.UpdateTemplateRequest{BodyTemplate: &body}); err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+	}
+
+	restored, err := svc.RollbackTemplate(ctx, otp.ID, 1)
+	if err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+	if restored.Version != 4 || restored.BodyTemplate != "Your OTP is {{.otp}}" {
+		t.Errorf("expected version 4 with the original body, got %d: %q", restored.Version, restored.BodyTemplate)
+	}
+
+	versions, err := svc.ListTemplateVersions(ctx, otp.ID)
+	if err != nil {
+		t.Fatalf("failed to list versions: %v", err)
+	}
+	var got []string
+	for _, v := range versions {
+		got = append(got, fmt.Sprintf("%d:%s", v.Version, v.BodyTemplate))
+	}
+	want := "4:Your OTP is {{.otp}}|3:{{.otp}} is your Nivo OTP|2:OTP: {{.otp}}|1:Your OTP is {{.otp}}"
+	if strings.Join(got, "|") != want {
+		t.Errorf("unexpected history: %v", got)
+	}
+
+	if _, err := svc.RollbackTemplate(ctx, otp.ID, 4); err == nil || err.Code != errors.ErrCodeValidation {
+		t.Errorf("expected rolling back to the current version to fail, got %v", err)
+	}
+	if _, err := svc.RollbackTemplate(ctx, otp.ID, 9); err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected an unknown version to be not found, got %v", err)
+	}
+}
+
+func TestTemplates_RejectInvalid(t *testing.T) {
+	svc, _, templates := newTemplateFixture()
+	ctx := context.Background()
+	otp, _ := templates.GetByName(ctx, "otp_sms", []string{"en"})
+
+	badBody := "Your OTP is {{.otp"
+	html := "<p>{{.otp}}</p>"
+
+	tests := []struct {
+		name string
+		call func() *errors.Error
+	}{
+		{"create with syntax error", func() *errors.Error {
+			_, err := svc.CreateTemplate(ctx, &models.CreateTemplateRequest{Name: "broken", Channel: models.ChannelSMS, BodyTemplate: badBody})
+			return err
+		}},
+		{"create with unknown function", func() *errors.Error {
+			_, err := svc.CreateTemplate(ctx, &models.CreateTemplateRequest{Name: "broken", Channel: models.ChannelSMS, BodyTemplate: `{{env "SECRET"}}`})
+			return err
+		}},
+		{"create with invalid locale", func() *errors.Error {
+			_, err := svc.CreateTemplate(ctx, &models.CreateTemplateRequest{Name: "otp_sms", Locale: "hindi language", Channel: models.ChannelSMS, BodyTemplate: "OTP {{.otp}}"})
+			return err
+		}},
+		{"create sms with html", func() *errors.Error {
+			_, err := svc.CreateTemplate(ctx, &models.CreateTemplateRequest{Name: "otp_sms", Locale: "ta", Channel: models.ChannelSMS, BodyTemplate: "OTP {{.otp}}", HTMLBodyTemplate: html})
+			return err
+		}},
+		{"update with syntax error", func() *errors.Error {
+			return svc.UpdateTemplate(ctx, otp.ID, &models.UpdateTemplateRequest{BodyTemplate: &badBody})
+		}},
+		{"update sms with html", func() *errors.Error {
+			return svc.UpdateTemplate(ctx, otp.ID, &models.UpdateTemplateRequest{HTMLBodyTemplate: &html})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err == nil || err.Code != errors.ErrCodeValidation {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+
+	created, err := svc.CreateTemplate(ctx, &models.CreateTemplateRequest{Name: "otp_sms", Locale: "TA_in", Channel: models.ChannelSMS, BodyTemplate: "OTP {{.otp}}"})
+	if err != nil || created.Locale != "ta-in" {
+		t.Errorf("expected a ta-in variant, got %+v (%v)", created, err)
+	}
+}
+
+func TestLocaleFallbacks(t *testing.T) {
+	tests := map[string]string{
+		"":        "en",
+		"en":      "en",
+		"hi":      "hi,en",
+		"hi-IN":   "hi-in,hi,en",
+		"en_GB":   "en-gb,en",
+		"gibber!": "en",
+	}
+
+	for locale, want := range tests {
+		if got := strings.Join(models.LocaleFallbacks(locale), ","); got != want {
+			t.Errorf("%q: expected %s, got %s", locale, want, got)
+		}
+	}
+}
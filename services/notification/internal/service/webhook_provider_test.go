@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/notification/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+const testWebhookSecret = "webhook-secret-0123456789abcdef0"
+
+func TestWebhookProvider_SendsSignedPayload(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sentAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	provider := NewWebhookProvider(WebhookConfig{URL: server.URL, Secret: testWebhookSecret})
+	provider.now = func() time.Time { return sentAt }
+
+	userID := "user-1"
+	result, err := provider.Send(context.Background(), &models.Notification{
+		ID:        "notif-1",
+		UserID:    &userID,
+		Channel:   models.ChannelPush,
+		Type:      models.TypeTransactionAlert,
+		Priority:  models.PriorityHigh,
+		Recipient: "device-token-1",
+		Subject:   "Money received",
+		Body:      "You received ₹500",
+		CreatedAt: sharedModels.NewTimestamp(sentAt),
+	})
+	if err != nil {
+		t.Fatalf("expected webhook to be delivered, got %v", err)
+	}
+	if !result.Delivered || result.MessageID != "notif-1" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if got, want := header.Get("X-Nivo-Signature"), webhookSignature(testWebhookSecret, sentAt.Unix(), body); got != want {
+		t.Errorf("expected signature %q, got %q", want, got)
+	}
+	if header.Get("X-Nivo-Notification-ID") != "notif-1" {
+		t.Errorf("expected notification ID header, got %v", header)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.Channel != models.ChannelPush || payload.Recipient != "device-token-1" || payload.Body != "You received ₹500" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	// HMAC-SHA256 of "1700000000.{}" keyed with "secret"
+	want := "t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if got := webhookSignature("secret", 1700000000, []byte("{}")); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestWebhookProvider_Errors(t *testing.T) {
+	tests := []struct {
+		status   int
+		wantCode errors.ErrorCode
+	}{
+		{http.StatusGone, errors.ErrCodeBadRequest},
+		{http.StatusServiceUnavailable, errors.ErrCodeUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			provider := NewWebhookProvider(WebhookConfig{URL: server.URL, Secret: testWebhookSecret})
+			_, err := provider.Send(context.Background(), &models.Notification{ID: "notif-1"})
+			if err == nil || err.Code != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
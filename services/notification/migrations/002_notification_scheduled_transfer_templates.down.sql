@@ -0,0 +1,3 @@
+-- Scheduled Transfer Templates Rollback
+
+DELETE FROM notification_templates WHERE name = 'scheduled_transfer_failed_inapp';
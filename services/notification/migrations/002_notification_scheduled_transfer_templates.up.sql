@@ -0,0 +1,14 @@
+-- ============================================================================
+-- Scheduled Transfer Templates
+-- ============================================================================
+
+-- Sent by the transaction service when an occurrence of a scheduled transfer fails
+INSERT INTO notification_templates (name, channel, subject_template, body_template, version)
+VALUES (
+    'scheduled_transfer_failed_inapp',
+    'in_app',
+    'Scheduled transfer failed',
+    'Your scheduled transfer of {{currency}} {{amount}} ({{description}}) due {{scheduled_for}} could not be completed: {{reason}}.{{paused_notice}}',
+    1
+)
+ON CONFLICT (name) DO NOTHING;
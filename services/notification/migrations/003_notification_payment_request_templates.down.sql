@@ -0,0 +1,9 @@
+-- Payment Request Templates Rollback
+
+DELETE FROM notification_templates WHERE name IN (
+    'payment_request_received_inapp',
+    'payment_request_accepted_inapp',
+    'payment_request_declined_inapp',
+    'payment_request_cancelled_inapp',
+    'payment_request_expired_inapp'
+);
@@ -0,0 +1,43 @@
+-- ============================================================================
+-- Payment Request Templates
+-- ============================================================================
+
+-- Sent by the transaction service to the parties of a payment request
+INSERT INTO notification_templates (name, channel, subject_template, body_template, version)
+VALUES
+(
+    'payment_request_received_inapp',
+    'in_app',
+    '{{requester_name}} requested {{currency}} {{amount}}',
+    '{{requester_name}} has requested {{currency}} {{amount}} from you for {{description}}. The request expires on {{expires_at}}.',
+    1
+),
+(
+    'payment_request_accepted_inapp',
+    'in_app',
+    'Payment request paid',
+    '{{payer_name}} paid your request for {{currency}} {{amount}} ({{description}}).',
+    1
+),
+(
+    'payment_request_declined_inapp',
+    'in_app',
+    'Payment request declined',
+    '{{payer_name}} declined your request for {{currency}} {{amount}} ({{description}}).{{reason_notice}}',
+    1
+),
+(
+    'payment_request_cancelled_inapp',
+    'in_app',
+    'Payment request cancelled',
+    '{{requester_name}} cancelled their request for {{currency}} {{amount}} ({{description}}).',
+    1
+),
+(
+    'payment_request_expired_inapp',
+    'in_app',
+    'Payment request expired',
+    'The payment request for {{currency}} {{amount}} ({{description}}) with {{counterparty}} has expired.',
+    1
+)
+ON CONFLICT (name) DO NOTHING;
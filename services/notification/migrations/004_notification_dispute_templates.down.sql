@@ -0,0 +1,11 @@
+-- Dispute Templates Rollback
+
+DELETE FROM notification_templates WHERE name IN (
+    'dispute_opened_inapp',
+    'dispute_counterparty_opened_inapp',
+    'dispute_investigating_inapp',
+    'dispute_resolved_inapp',
+    'dispute_counterparty_resolved_inapp',
+    'dispute_rejected_inapp',
+    'dispute_counterparty_rejected_inapp'
+);
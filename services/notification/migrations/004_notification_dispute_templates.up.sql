@@ -0,0 +1,58 @@
+-- ============================================================================
+-- Dispute Templates
+-- ============================================================================
+
+-- Sent by the transaction service to the customer disputing a transaction and,
+-- for transfers, to the counterparty who received it
+INSERT INTO notification_templates (name, channel, subject_template, body_template, version)
+VALUES
+(
+    'dispute_opened_inapp',
+    'in_app',
+    'Dispute received',
+    'We have received your dispute of {{currency}} {{amount}} ({{description}}) and will resolve it by {{resolve_by}}.',
+    1
+),
+(
+    'dispute_counterparty_opened_inapp',
+    'in_app',
+    'A payment you received is disputed',
+    'The sender of {{currency}} {{amount}} ({{description}}) has disputed the payment. We will let you know the outcome.',
+    1
+),
+(
+    'dispute_investigating_inapp',
+    'in_app',
+    'Dispute under investigation',
+    'We are investigating your dispute of {{currency}} {{amount}} ({{description}}) and will resolve it by {{resolve_by}}.',
+    1
+),
+(
+    'dispute_resolved_inapp',
+    'in_app',
+    'Dispute resolved in your favour',
+    'Your dispute of {{currency}} {{amount}} ({{description}}) was upheld. The amount is being credited to your wallet.',
+    1
+),
+(
+    'dispute_counterparty_resolved_inapp',
+    'in_app',
+    'Disputed payment reversed',
+    'The dispute of {{currency}} {{amount}} ({{description}}) was upheld. The amount is being debited from your wallet.',
+    1
+),
+(
+    'dispute_rejected_inapp',
+    'in_app',
+    'Dispute rejected',
+    'Your dispute of {{currency}} {{amount}} ({{description}}) was not upheld: {{notes}}',
+    1
+),
+(
+    'dispute_counterparty_rejected_inapp',
+    'in_app',
+    'Dispute closed',
+    'The dispute of the {{currency}} {{amount}} payment you received ({{description}}) was not upheld. No action is needed.',
+    1
+)
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,7 @@
+-- Delivery Providers Rollback
+
+DROP INDEX IF EXISTS idx_notifications_provider_message;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS provider_message_id,
+    DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,16 @@
+-- ============================================================================
+-- Delivery Providers
+-- ============================================================================
+
+-- The provider that accepted a notification and its ID for the message, which
+-- delivery receipts refer to.
+ALTER TABLE notifications
+    ADD COLUMN provider VARCHAR(50),
+    ADD COLUMN provider_message_id VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_notifications_provider_message
+    ON notifications(provider, provider_message_id)
+    WHERE provider_message_id IS NOT NULL;
+
+COMMENT ON COLUMN notifications.provider IS 'Delivery provider that accepted the notification (simulation, smtp, sms_gateway, webhook, inbox)';
+COMMENT ON COLUMN notifications.provider_message_id IS 'Provider''s message ID, matched against delivery receipts';
@@ -0,0 +1,8 @@
+-- In-App Inbox Rollback
+
+DROP INDEX IF EXISTS idx_notifications_inbox_unread;
+DROP INDEX IF EXISTS idx_notifications_inbox;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS read_at;
@@ -0,0 +1,20 @@
+-- ============================================================================
+-- In-App Inbox
+-- ============================================================================
+
+-- Delivered in-app notifications form each user's inbox. The user marks them
+-- read and archives them; old read and archived items are pruned.
+ALTER TABLE notifications
+    ADD COLUMN read_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_notifications_inbox
+    ON notifications(user_id, created_at DESC)
+    WHERE channel = 'in_app' AND status = 'delivered';
+
+CREATE INDEX idx_notifications_inbox_unread
+    ON notifications(user_id)
+    WHERE channel = 'in_app' AND status = 'delivered' AND read_at IS NULL AND archived_at IS NULL;
+
+COMMENT ON COLUMN notifications.read_at IS 'When the user read the in-app notification';
+COMMENT ON COLUMN notifications.archived_at IS 'When the user archived the in-app notification';
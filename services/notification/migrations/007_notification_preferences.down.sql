@@ -0,0 +1,15 @@
+-- Notification Preferences and Quiet Hours Rollback
+
+DELETE FROM notifications WHERE status = 'suppressed';
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('queued', 'sent', 'delivered', 'failed'));
+
+ALTER TABLE notifications
+    DROP CONSTRAINT IF EXISTS notifications_preference_decision_check,
+    DROP COLUMN IF EXISTS deliver_after,
+    DROP COLUMN IF EXISTS preference_decision;
+
+DROP TABLE IF EXISTS notification_quiet_hours;
+DROP TABLE IF EXISTS notification_preferences;
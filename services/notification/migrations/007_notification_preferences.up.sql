@@ -0,0 +1,56 @@
+-- ============================================================================
+-- Notification Preferences and Quiet Hours
+-- ============================================================================
+
+-- Whether a user receives notifications of a type on a channel. Users receive
+-- everything they have no preference for.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, type, channel),
+    CONSTRAINT notification_preferences_channel_check CHECK (channel IN ('sms', 'email', 'push', 'in_app'))
+);
+
+CREATE TRIGGER update_notification_preferences_updated_at
+    BEFORE UPDATE ON notification_preferences
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- A daily window in the user's timezone during which normal and low priority
+-- notifications are held back. A window whose start is after its end runs
+-- past midnight.
+CREATE TABLE IF NOT EXISTS notification_quiet_hours (
+    user_id UUID PRIMARY KEY,
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    timezone VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_quiet_hours_window_check CHECK (start_time <> end_time)
+);
+
+CREATE TRIGGER update_notification_quiet_hours_updated_at
+    BEFORE UPDATE ON notification_quiet_hours
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- How the preferences applied to each notification, and until when quiet
+-- hours defer it. Opted-out notifications are kept as suppressed.
+ALTER TABLE notifications
+    ADD COLUMN preference_decision VARCHAR(20),
+    ADD COLUMN deliver_after TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT notifications_preference_decision_check
+        CHECK (preference_decision IN ('allowed', 'deferred', 'suppressed', 'mandatory'));
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('queued', 'sent', 'delivered', 'failed', 'suppressed'));
+
+COMMENT ON COLUMN notifications.preference_decision IS 'allowed, deferred (quiet hours), suppressed (opted out) or mandatory (critical despite opt-out); null without a user';
+COMMENT ON COLUMN notifications.deliver_after IS 'Quiet hours defer delivery until this time';
@@ -0,0 +1,28 @@
+-- Template Language, Localisation and Version History Rollback
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS template_version,
+    DROP COLUMN IF EXISTS html_body;
+
+DROP TABLE IF EXISTS notification_template_versions;
+
+DELETE FROM notification_templates WHERE locale <> 'en';
+
+ALTER TABLE notification_templates DROP CONSTRAINT notification_templates_name_locale_key;
+ALTER TABLE notification_templates ADD CONSTRAINT notification_templates_name_key UNIQUE (name);
+
+ALTER TABLE notification_templates
+    DROP COLUMN IF EXISTS html_body_template,
+    DROP COLUMN IF EXISTS locale;
+
+-- Back to flat {{variable}} placeholders. Anything beyond plain variables and
+-- if/else blocks has no flat equivalent and is left as written.
+UPDATE notification_templates
+SET body_template = replace(
+        regexp_replace(body_template, '\{\{if \.([a-zA-Z0-9_]+)\}\}', '{{#if \1}}', 'g'),
+        '{{end}}', '{{/if}}')
+WHERE body_template LIKE '%{{if .%';
+
+UPDATE notification_templates
+SET subject_template = regexp_replace(subject_template, '\{\{\.([a-zA-Z0-9_]+)\}\}', '{{\1}}', 'g'),
+    body_template = regexp_replace(body_template, '\{\{\.([a-zA-Z0-9_]+)\}\}', '{{\1}}', 'g');
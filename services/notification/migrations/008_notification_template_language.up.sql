@@ -0,0 +1,94 @@
+-- ============================================================================
+-- Template Language, Localisation and Version History
+-- ============================================================================
+
+-- Templates move from flat {{variable}} substitution to Go's template
+-- language. Rewrite the existing ones: {{name}} becomes {{.name}}, and the
+-- handlebars-style blocks of kyc_update_email become {{if}} ... {{end}}.
+UPDATE notification_templates
+SET subject_template = regexp_replace(subject_template, '\{\{([a-zA-Z0-9_]+)\}\}', '{{.\1}}', 'g'),
+    body_template = regexp_replace(body_template, '\{\{([a-zA-Z0-9_]+)\}\}', '{{.\1}}', 'g');
+
+UPDATE notification_templates
+SET body_template = replace(
+        replace(
+            regexp_replace(body_template, '\{\{#if ([a-zA-Z0-9_]+)\}\}', '{{if .\1}}', 'g'),
+            '{{/if}}', '{{end}}'),
+        '{{.else}}', '{{else}}')
+WHERE body_template LIKE '%{{#if%';
+
+-- A template has one variant per locale, all sharing its name. Email templates
+-- may have an HTML body, sent alongside the plain-text one.
+ALTER TABLE notification_templates
+    ADD COLUMN locale VARCHAR(20) NOT NULL DEFAULT 'en',
+    ADD COLUMN html_body_template TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE notification_templates DROP CONSTRAINT notification_templates_name_key;
+ALTER TABLE notification_templates
+    ADD CONSTRAINT notification_templates_name_locale_key UNIQUE (name, locale);
+
+COMMENT ON COLUMN notification_templates.locale IS 'Variant locale (en, hi, hi-in); notifications fall back to the language, then en';
+COMMENT ON COLUMN notification_templates.html_body_template IS 'Email only: HTML alternative to body_template, escaped contextually';
+
+-- The content of every version of a template. Updates add a version, and a
+-- rollback adds one with the content of an earlier version.
+CREATE TABLE IF NOT EXISTS notification_template_versions (
+    template_id UUID NOT NULL REFERENCES notification_templates(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    subject_template VARCHAR(500) NOT NULL DEFAULT '',
+    body_template TEXT NOT NULL,
+    html_body_template TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (template_id, version)
+);
+
+-- Rendered HTML, and the template version a notification was rendered from
+ALTER TABLE notifications
+    ADD COLUMN html_body TEXT NOT NULL DEFAULT '',
+    ADD COLUMN template_version INT;
+
+-- ============================================================================
+-- Seed Data: HTML and Hindi Variants
+-- ============================================================================
+
+UPDATE notification_templates
+SET html_body_template = '<p>Dear {{.user_name}},</p>
+<p>Welcome to Nivo Money! We''re excited to have you on board.</p>
+<p>Your account has been successfully created. You can now:</p>
+<ul>
+  <li>Create wallets</li>
+  <li>Send and receive money</li>
+  <li>Track your transactions</li>
+</ul>
+<p>Get started by creating your first wallet.</p>
+<p>Best regards,<br>The Nivo Money Team</p>'
+WHERE name = 'welcome_email' AND locale = 'en';
+
+INSERT INTO notification_templates (name, locale, channel, subject_template, body_template, version)
+VALUES (
+    'otp_sms',
+    'hi',
+    'sms',
+    '',
+    'आपका Nivo Money OTP {{.otp}} है। यह {{.validity_minutes}} मिनट तक मान्य है। यह कोड किसी से साझा न करें। - Nivo Money',
+    1
+)
+ON CONFLICT (name, locale) DO NOTHING;
+
+INSERT INTO notification_templates (name, locale, channel, subject_template, body_template, version)
+VALUES (
+    'welcome_sms',
+    'hi',
+    'sms',
+    '',
+    'Nivo Money में आपका स्वागत है, {{.full_name}}! आपका खाता तैयार है। शुरू करने के लिए अपना पहला वॉलेट बनाएँ। - Nivo Money',
+    1
+)
+ON CONFLICT (name, locale) DO NOTHING;
+
+-- The current content of every template is its first recorded version
+INSERT INTO notification_template_versions (template_id, version, subject_template, body_template, html_body_template, created_at)
+SELECT id, version, COALESCE(subject_template, ''), body_template, html_body_template, updated_at
+FROM notification_templates
+ON CONFLICT (template_id, version) DO NOTHING;
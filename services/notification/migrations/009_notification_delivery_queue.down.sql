@@ -0,0 +1,17 @@
+-- Delivery Queue Rollback
+
+UPDATE notifications SET status = 'failed' WHERE status = 'dead_letter';
+
+DROP INDEX IF EXISTS idx_notifications_dead_letter;
+DROP INDEX IF EXISTS idx_notifications_due;
+
+CREATE INDEX idx_notifications_priority_status ON notifications(priority, status) WHERE status = 'queued';
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('queued', 'sent', 'delivered', 'failed', 'suppressed'));
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS dead_lettered_at,
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS next_attempt_at;
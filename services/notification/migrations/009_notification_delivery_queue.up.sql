@@ -0,0 +1,35 @@
+-- ============================================================================
+-- Delivery Queue
+-- ============================================================================
+
+-- Queued notifications are claimed by workers once next_attempt_at is due.
+-- A claim is a lease: a worker that dies mid-delivery leaves locked_until to
+-- lapse, and another worker picks the notification up. Temporary provider
+-- failures push next_attempt_at back; notifications that run out of attempts
+-- are dead-lettered for an operator to requeue.
+ALTER TABLE notifications
+    ADD COLUMN next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN dead_lettered_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE notifications
+SET next_attempt_at = COALESCE(deliver_after, queued_at)
+WHERE status = 'queued';
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('queued', 'sent', 'delivered', 'failed', 'suppressed', 'dead_letter'));
+
+DROP INDEX IF EXISTS idx_notifications_priority_status;
+
+CREATE INDEX idx_notifications_due
+    ON notifications(channel, next_attempt_at)
+    WHERE status = 'queued';
+
+CREATE INDEX idx_notifications_dead_letter
+    ON notifications(dead_lettered_at DESC)
+    WHERE status = 'dead_letter';
+
+COMMENT ON COLUMN notifications.next_attempt_at IS 'When the queued notification is next due for delivery';
+COMMENT ON COLUMN notifications.locked_until IS 'Lease of the worker delivering the notification';
+COMMENT ON COLUMN notifications.dead_lettered_at IS 'When delivery was given up after the last attempt';
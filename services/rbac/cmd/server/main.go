@@ -6,6 +6,8 @@ import (
 	"github.com/vnykmshr/nivo/services/rbac/internal/handler"
 	"github.com/vnykmshr/nivo/services/rbac/internal/repository"
 	"github.com/vnykmshr/nivo/services/rbac/internal/service"
+	"github.com/vnykmshr/nivo/shared/audit"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/server"
 )
 
@@ -18,15 +20,15 @@ func main() {
 
 			// Initialize service layer
 			rbacService := service.NewRBACService(rbacRepo)
+			rbacService.SetAuditLog(audit.NewLog(audit.NewPostgresStore(ctx.DB.DB), "rbac"))
 
 			// Initialize handler layer
 			rbacHandler := handler.NewRBACHandler(rbacService)
 
-			// Get JWT secret and internal service secret for setup routes
-			jwtSecret := server.RequireEnv("JWT_SECRET")
-			internalSecret := server.GetEnv("INTERNAL_SERVICE_SECRET", "")
+			// User and service tokens are verified against the identity service's published keys
+			jwtKeys := sharedjwt.NewRemoteKeySet(server.GetEnv("JWKS_URL", sharedjwt.DefaultJWKSURL))
 
-			return handler.SetupRoutes(rbacHandler, jwtSecret, internalSecret), nil
+			return handler.SetupRoutes(rbacHandler, jwtKeys), nil
 		},
 	})
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/vnykmshr/nivo/services/rbac/internal/models"
 	"github.com/vnykmshr/nivo/services/rbac/internal/service"
 	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
 	"github.com/vnykmshr/nivo/shared/response"
 )
 
@@ -242,8 +243,11 @@ func (h *RBACHandler) AssignPermissionToRole(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// TODO: Get assignor user ID from auth context
+	// The admin making the assignment
 	var assignedBy *string
+	if adminID, ok := middleware.GetUserID(r.Context()); ok {
+		assignedBy = &adminID
+	}
 
 	// Assign permission
 	if assignErr := h.service.AssignPermissionToRole(r.Context(), roleID, req.PermissionID, assignedBy); assignErr != nil {
@@ -330,8 +334,11 @@ func (h *RBACHandler) AssignRoleToUser(w http.ResponseWriter, r *http.Request) {
 	// Set user ID from path
 	req.UserID = userID
 
-	// TODO: Get assignor user ID from auth context
+	// The admin making the assignment
 	var assignedBy *string
+	if adminID, ok := middleware.GetUserID(r.Context()); ok {
+		assignedBy = &adminID
+	}
 
 	// Assign role
 	userRole, assignErr := h.service.AssignRoleToUser(r.Context(), &req, assignedBy)
@@ -344,7 +351,7 @@ func (h *RBACHandler) AssignRoleToUser(w http.ResponseWriter, r *http.Request) {
 }
 
 // AssignDefaultRoleInternal handles POST /internal/v1/users/{userId}/assign-default-role
-// This is an internal endpoint for service-to-service communication (no auth required).
+// This is an internal endpoint for service-to-service communication (service token required).
 func (h *RBACHandler) AssignDefaultRoleInternal(w http.ResponseWriter, r *http.Request) {
 	userID := r.PathValue("userId")
 	if userID == "" {
@@ -376,7 +383,7 @@ func (h *RBACHandler) AssignDefaultRoleInternal(w http.ResponseWriter, r *http.R
 }
 
 // AssignRoleByNameInternal handles POST /internal/v1/users/{userId}/assign-role
-// This is an internal endpoint for service-to-service communication (no auth required).
+// This is an internal endpoint for service-to-service communication (service token required).
 // Request body: {"role_name": "user_admin"}
 func (h *RBACHandler) AssignRoleByNameInternal(w http.ResponseWriter, r *http.Request) {
 	userID := r.PathValue("userId")
@@ -483,7 +490,7 @@ func (h *RBACHandler) GetUserPermissions(w http.ResponseWriter, r *http.Request)
 }
 
 // GetUserPermissionsInternal handles GET /internal/v1/users/{userId}/permissions
-// This is an internal endpoint for service-to-service communication (no authentication required).
+// This is an internal endpoint for service-to-service communication (service token required).
 func (h *RBACHandler) GetUserPermissionsInternal(w http.ResponseWriter, r *http.Request) {
 	userID := r.PathValue("userId")
 	if userID == "" {
@@ -3,12 +3,14 @@ package handler
 import (
 	"net/http"
 
+	"github.com/vnykmshr/nivo/shared/audit"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/metrics"
 	"github.com/vnykmshr/nivo/shared/middleware"
 )
 
 // SetupRoutes configures all routes for the RBAC service using Go 1.22+ stdlib router.
-func SetupRoutes(rbacHandler *RBACHandler, jwtSecret, internalSecret string) http.Handler {
+func SetupRoutes(rbacHandler *RBACHandler, jwtKeys sharedjwt.KeySource) http.Handler {
 	mux := http.NewServeMux()
 
 	// Health check endpoint (public)
@@ -23,7 +25,7 @@ func SetupRoutes(rbacHandler *RBACHandler, jwtSecret, internalSecret string) htt
 
 	// Setup auth middleware
 	authConfig := middleware.AuthConfig{
-		JWTSecret: jwtSecret,
+		Keys:      jwtKeys,
 		SkipPaths: []string{"/health"},
 	}
 	authMiddleware := middleware.Auth(authConfig)
@@ -70,20 +72,22 @@ func SetupRoutes(rbacHandler *RBACHandler, jwtSecret, internalSecret string) htt
 	mux.Handle("GET /api/v1/users/{userId}/permissions", authMiddleware(http.HandlerFunc(rbacHandler.GetUserPermissions)))
 
 	// ========================================================================
-	// Internal Endpoints (Service-to-Service with Shared Secret Auth)
+	// Internal Endpoints (Service-to-Service with Service Token Auth)
 	// ========================================================================
 
+	serviceAuth := middleware.NewServiceAuth(jwtKeys, sharedjwt.ServiceRBAC)
+
 	// Internal endpoint for identity service to assign default "user" role during registration
 	mux.HandleFunc("POST /internal/v1/users/{userId}/assign-default-role",
-		middleware.InternalAuthFunc(internalSecret, rbacHandler.AssignDefaultRoleInternal))
+		serviceAuth.Allow(rbacHandler.AssignDefaultRoleInternal, sharedjwt.ServiceIdentity))
 
 	// Internal endpoint for identity service to assign a role by name (e.g., user_admin)
 	mux.HandleFunc("POST /internal/v1/users/{userId}/assign-role",
-		middleware.InternalAuthFunc(internalSecret, rbacHandler.AssignRoleByNameInternal))
+		serviceAuth.Allow(rbacHandler.AssignRoleByNameInternal, sharedjwt.ServiceIdentity))
 
 	// Internal endpoint for identity service to fetch user permissions during login/token generation
 	mux.HandleFunc("GET /internal/v1/users/{userId}/permissions",
-		middleware.InternalAuthFunc(internalSecret, rbacHandler.GetUserPermissionsInternal))
+		serviceAuth.Allow(rbacHandler.GetUserPermissionsInternal, sharedjwt.ServiceIdentity))
 
 	// ========================================================================
 	// Permission Check Endpoints (Authenticated - used by services)
@@ -96,6 +100,9 @@ func SetupRoutes(rbacHandler *RBACHandler, jwtSecret, internalSecret string) htt
 	metricsCollector := metrics.NewCollector("rbac")
 	handler := metricsCollector.Middleware("rbac")(mux)
 
+	// Record the client IP with audited actions
+	handler = audit.Middleware()(handler)
+
 	// Apply request ID
 	handler = middleware.RequestID()(handler)
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/vnykmshr/nivo/services/rbac/internal/models"
+	"github.com/vnykmshr/nivo/shared/audit"
 	"github.com/vnykmshr/nivo/shared/errors"
 	sharedModels "github.com/vnykmshr/nivo/shared/models"
 )
@@ -44,7 +45,8 @@ type RBACRepositoryInterface interface {
 
 // RBACService handles all RBAC business logic.
 type RBACService struct {
-	repo RBACRepositoryInterface
+	repo     RBACRepositoryInterface
+	auditLog audit.Recorder // Optional durable record of role changes
 }
 
 // NewRBACService creates a new RBAC service.
@@ -52,6 +54,12 @@ func NewRBACService(repo RBACRepositoryInterface) *RBACService {
 	return &RBACService{repo: repo}
 }
 
+// SetAuditLog sets where role assignments and removals are recorded.
+// This is optional - if not set, they are not recorded.
+func (s *RBACService) SetAuditLog(auditLog audit.Recorder) {
+	s.auditLog = auditLog
+}
+
 // ============================================================================
 // Role Operations
 // ============================================================================
@@ -329,6 +337,8 @@ func (s *RBACService) AssignRoleToUser(ctx context.Context, req *models.AssignRo
 		expiresAt = &timestamp
 	}
 
+	before := s.auditRoleSnapshot(ctx, req.UserID)
+
 	userRole := &models.UserRole{
 		UserID:     req.UserID,
 		RoleID:     req.RoleID,
@@ -341,6 +351,8 @@ func (s *RBACService) AssignRoleToUser(ctx context.Context, req *models.AssignRo
 		return nil, err
 	}
 
+	s.recordRoleChange(ctx, "role.assign", req.UserID, before)
+
 	// Load role details
 	userRole.Role = role
 
@@ -349,7 +361,47 @@ func (s *RBACService) AssignRoleToUser(ctx context.Context, req *models.AssignRo
 
 // RemoveRoleFromUser removes a role from a user.
 func (s *RBACService) RemoveRoleFromUser(ctx context.Context, userID, roleID string) *errors.Error {
-	return s.repo.RemoveRoleFromUser(ctx, userID, roleID)
+	before := s.auditRoleSnapshot(ctx, userID)
+
+	if err := s.repo.RemoveRoleFromUser(ctx, userID, roleID); err != nil {
+		return err
+	}
+
+	s.recordRoleChange(ctx, "role.remove", userID, before)
+	return nil
+}
+
+// auditRoleSnapshot returns the IDs of a user's active roles, when role
+// changes are being recorded.
+func (s *RBACService) auditRoleSnapshot(ctx context.Context, userID string) map[string]interface{} {
+	if s.auditLog == nil {
+		return nil
+	}
+
+	userRoles, err := s.repo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	roleIDs := make([]string, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		roleIDs = append(roleIDs, userRole.RoleID)
+	}
+	return map[string]interface{}{"role_ids": roleIDs}
+}
+
+// recordRoleChange records a change to a user's roles, with their roles before and after.
+func (s *RBACService) recordRoleChange(ctx context.Context, action, userID string, before map[string]interface{}) {
+	if s.auditLog == nil {
+		return
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		Action:       action,
+		ResourceType: "user",
+		ResourceID:   userID,
+		Before:       before,
+		After:        s.auditRoleSnapshot(ctx, userID),
+	})
 }
 
 // GetUserRoles retrieves all active roles for a user.
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/vnykmshr/nivo/services/rbac/internal/models"
+	"github.com/vnykmshr/nivo/shared/audit"
 	"github.com/vnykmshr/nivo/shared/errors"
 	sharedModels "github.com/vnykmshr/nivo/shared/models"
 )
@@ -1104,6 +1105,56 @@ func TestRemoveRoleFromUser_Success(t *testing.T) {
 	}
 }
 
+// recordingAuditLog captures audit events for assertions.
+type recordingAuditLog struct {
+	events []audit.Event
+}
+
+func (r *recordingAuditLog) Record(ctx context.Context, event audit.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestRoleChanges_RecordAudit(t *testing.T) {
+	repo := newMockRBACRepository()
+	service := NewRBACService(repo)
+	auditLog := &recordingAuditLog{}
+	service.SetAuditLog(auditLog)
+	ctx := context.Background()
+
+	role, _ := service.CreateRole(ctx, &models.CreateRoleRequest{Name: "auditor", Description: "Auditor"}, "admin")
+
+	if _, err := service.AssignRoleToUser(ctx, &models.AssignRoleToUserRequest{UserID: "user_1", RoleID: role.ID}, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := service.RemoveRoleFromUser(ctx, "user_1", role.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(auditLog.events) != 2 {
+		t.Fatalf("expected 2 audit events, got %d", len(auditLog.events))
+	}
+
+	tests := []struct {
+		action string
+		before []string
+		after  []string
+	}{
+		{"role.assign", []string{}, []string{role.ID}},
+		{"role.remove", []string{role.ID}, []string{}},
+	}
+	for i, tt := range tests {
+		event := auditLog.events[i]
+		if event.Action != tt.action || event.ResourceType != "user" || event.ResourceID != "user_1" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+		before := event.Before.(map[string]interface{})["role_ids"].([]string)
+		after := event.After.(map[string]interface{})["role_ids"].([]string)
+		if len(before) != len(tt.before) || len(after) != len(tt.after) {
+			t.Errorf("%s: expected roles %v -> %v, got %v -> %v", tt.action, tt.before, tt.after, before, after)
+		}
+	}
+}
+
 func TestGetUserPermissions_Success(t *testing.T) {
 	repo := newMockRBACRepository()
 	service := NewRBACService(repo)
@@ -0,0 +1,3 @@
+-- Remove ledger report permissions
+DELETE FROM role_permissions WHERE permission_id = '30000000-0000-0000-0000-000000000030';
+DELETE FROM permissions WHERE id = '30000000-0000-0000-0000-000000000030';
@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Ledger Report Permissions
+-- ============================================================================
+
+INSERT INTO permissions (id, name, service, resource, action, description, is_system) VALUES
+('30000000-0000-0000-0000-000000000030', 'ledger:report:read', 'ledger', 'report', 'read', 'View trial balance, balance sheet and income statement', true)
+ON CONFLICT (name) DO NOTHING;
+
+-- ACCOUNTANT and ADMIN roles can view financial reports
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000003', '30000000-0000-0000-0000-000000000030'),
+('00000000-0000-0000-0000-000000000005', '30000000-0000-0000-0000-000000000030')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- Remove ledger accounting period permissions
+DELETE FROM role_permissions WHERE permission_id IN (
+    '30000000-0000-0000-0000-000000000031',
+    '30000000-0000-0000-0000-000000000032'
+);
+DELETE FROM permissions WHERE id IN (
+    '30000000-0000-0000-0000-000000000031',
+    '30000000-0000-0000-0000-000000000032'
+);
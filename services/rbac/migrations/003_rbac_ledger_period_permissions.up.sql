@@ -0,0 +1,19 @@
+-- ============================================================================
+-- Ledger Accounting Period Permissions
+-- ============================================================================
+
+INSERT INTO permissions (id, name, service, resource, action, description, is_system) VALUES
+('30000000-0000-0000-0000-000000000031', 'ledger:period:manage', 'ledger', 'period', 'manage', 'Create and close accounting periods', true),
+('30000000-0000-0000-0000-000000000032', 'ledger:period:reopen', 'ledger', 'period', 'reopen', 'Reopen a closed accounting period', true)
+ON CONFLICT (name) DO NOTHING;
+
+-- ACCOUNTANT and ADMIN roles can create and close periods
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000003', '30000000-0000-0000-0000-000000000031'),
+('00000000-0000-0000-0000-000000000005', '30000000-0000-0000-0000-000000000031')
+ON CONFLICT DO NOTHING;
+
+-- Only ADMIN (and SUPER_ADMIN by inheritance) can reopen a closed period
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000005', '30000000-0000-0000-0000-000000000032')
+ON CONFLICT DO NOTHING;
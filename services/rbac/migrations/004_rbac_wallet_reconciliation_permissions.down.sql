@@ -0,0 +1,9 @@
+-- Remove wallet reconciliation permissions
+DELETE FROM role_permissions WHERE permission_id IN (
+    '20000000-0000-0000-0000-000000000020',
+    '20000000-0000-0000-0000-000000000021'
+);
+DELETE FROM permissions WHERE id IN (
+    '20000000-0000-0000-0000-000000000020',
+    '20000000-0000-0000-0000-000000000021'
+);
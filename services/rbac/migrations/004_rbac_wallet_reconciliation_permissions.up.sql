@@ -0,0 +1,20 @@
+-- ============================================================================
+-- Wallet Reconciliation Permissions
+-- ============================================================================
+
+INSERT INTO permissions (id, name, service, resource, action, description, is_system) VALUES
+('20000000-0000-0000-0000-000000000020', 'wallet:reconciliation:read', 'wallet', 'reconciliation', 'read', 'View reconciliation runs and discrepancies', true),
+('20000000-0000-0000-0000-000000000021', 'wallet:reconciliation:manage', 'wallet', 'reconciliation', 'manage', 'Trigger reconciliation runs and resolve discrepancies', true)
+ON CONFLICT (name) DO NOTHING;
+
+-- ACCOUNTANT and COMPLIANCE_OFFICER roles can review discrepancies
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000003', '20000000-0000-0000-0000-000000000020'),
+('00000000-0000-0000-0000-000000000004', '20000000-0000-0000-0000-000000000020')
+ON CONFLICT DO NOTHING;
+
+-- ADMIN (and SUPER_ADMIN by inheritance) can review, run and resolve
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000005', '20000000-0000-0000-0000-000000000020'),
+('00000000-0000-0000-0000-000000000005', '20000000-0000-0000-0000-000000000021')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+-- RBAC Audit Log Rollback
+
+-- The audit log is kept: it is the append-only record of every service's
+-- administrative actions, and rolling back a migration must not erase it. Drop
+-- audit_log and audit_log_reject_change() by hand if it really has to go.
@@ -0,0 +1,50 @@
+-- ============================================================================
+-- Audit Log
+-- ============================================================================
+
+-- Administrative actions from every service (suspensions, KYC decisions,
+-- wallet freezes, reversals, role changes), with snapshots of the resource
+-- before and after. Entries form one hash chain: hash covers the entry and
+-- prev_hash, the hash of the entry before it. Snapshots are JSON rather than
+-- JSONB so they are stored byte for byte as they were hashed. The other
+-- services writing to the log start after this one.
+CREATE TABLE IF NOT EXISTS audit_log (
+    sequence BIGINT PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    service VARCHAR(50) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,           -- User ID, service name or 'system'
+    actor_account_type VARCHAR(50) NOT NULL,  -- Account type, 'service' or 'system'
+    action VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    before_snapshot JSON,
+    after_snapshot JSON,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id, sequence DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log(resource_type, resource_id, sequence DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log(action, sequence DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+-- The log is append-only
+CREATE OR REPLACE FUNCTION audit_log_reject_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_no_update_delete ON audit_log;
+CREATE TRIGGER audit_log_no_update_delete
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_reject_change();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_reject_change();
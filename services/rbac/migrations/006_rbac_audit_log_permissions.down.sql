@@ -0,0 +1,3 @@
+-- Remove audit log permissions
+DELETE FROM role_permissions WHERE permission_id = '20000000-0000-0000-0000-000000000022';
+DELETE FROM permissions WHERE id = '20000000-0000-0000-0000-000000000022';
@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Audit Log Permissions
+-- ============================================================================
+
+INSERT INTO permissions (id, name, service, resource, action, description, is_system) VALUES
+('20000000-0000-0000-0000-000000000022', 'identity:audit:read', 'identity', 'audit', 'read', 'Search, export and verify the audit log', true)
+ON CONFLICT (name) DO NOTHING;
+
+-- COMPLIANCE_OFFICER and ADMIN (and SUPER_ADMIN by inheritance) review the audit log
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000004', '20000000-0000-0000-0000-000000000022'),
+('00000000-0000-0000-0000-000000000005', '20000000-0000-0000-0000-000000000022')
+ON CONFLICT DO NOTHING;
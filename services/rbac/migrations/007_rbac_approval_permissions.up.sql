@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Approval Permissions
+-- ============================================================================
+
+-- Closing a wallet needs a second admin holding this permission
+INSERT INTO permissions (id, name, service, resource, action, description, is_system) VALUES
+('20000000-0000-0000-0000-000000000023', 'wallet:wallet:close', 'wallet', 'wallet', 'close', 'Close a wallet permanently', true)
+ON CONFLICT (name) DO NOTHING;
+
+-- ADMIN (and SUPER_ADMIN by inheritance) request and approve wallet closures
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000005', '20000000-0000-0000-0000-000000000023')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- Remove dispute permissions
+DELETE FROM role_permissions WHERE permission_id IN (
+    '40000000-0000-0000-0000-000000000020',
+    '40000000-0000-0000-0000-000000000021'
+);
+DELETE FROM permissions WHERE id IN (
+    '40000000-0000-0000-0000-000000000020',
+    '40000000-0000-0000-0000-000000000021'
+);
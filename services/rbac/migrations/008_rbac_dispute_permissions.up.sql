@@ -0,0 +1,19 @@
+-- ============================================================================
+-- Dispute Permissions
+-- ============================================================================
+
+INSERT INTO permissions (id, name, service, resource, action, description, is_system) VALUES
+('40000000-0000-0000-0000-000000000020', 'transaction:dispute:create', 'transaction', 'dispute', 'create', 'Dispute a transaction from own wallet', true),
+('40000000-0000-0000-0000-000000000021', 'transaction:dispute:manage', 'transaction', 'dispute', 'manage', 'Investigate and resolve transaction disputes', true)
+ON CONFLICT (name) DO NOTHING;
+
+-- USER role can dispute their own transactions
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000001', '40000000-0000-0000-0000-000000000020')
+ON CONFLICT DO NOTHING;
+
+-- COMPLIANCE_OFFICER and ADMIN (and SUPER_ADMIN by inheritance) investigate and resolve disputes
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000004', '40000000-0000-0000-0000-000000000021'),
+('00000000-0000-0000-0000-000000000005', '40000000-0000-0000-0000-000000000021')
+ON CONFLICT DO NOTHING;
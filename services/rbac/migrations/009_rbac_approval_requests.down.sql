@@ -0,0 +1,5 @@
+-- RBAC Approval Requests Rollback
+
+-- Approval requests are kept: every service's pending and decided requests are
+-- here, and rolling back a migration must not erase them. Drop
+-- approval_requests by hand if it really has to go.
@@ -0,0 +1,36 @@
+-- ============================================================================
+-- Approval Requests
+-- ============================================================================
+
+-- Four-eyes approval of sensitive admin operations (reversals, wallet
+-- closures, KYC overrides). The maker submits a request; a second admin
+-- approves or rejects it, and an approved operation is then executed.
+-- Shared by every service that uses the approval workflow; they start after
+-- this one.
+CREATE TABLE IF NOT EXISTS approval_requests (
+    id UUID PRIMARY KEY,
+    service VARCHAR(50) NOT NULL,
+    operation VARCHAR(100) NOT NULL,          -- e.g. 'transaction.reverse'
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',      -- Operation input, e.g. the reversal reason
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    maker_id VARCHAR(255) NOT NULL,
+    checker_id VARCHAR(255),
+    decision_reason TEXT,
+    result JSONB,
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP WITH TIME ZONE,
+    executed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT approval_requests_status_check CHECK (status IN ('pending', 'approved', 'rejected', 'executed', 'failed')),
+    -- Only a rejection (a maker withdrawing their request) may be decided by the maker
+    CONSTRAINT approval_requests_four_eyes CHECK (status IN ('pending', 'rejected') OR checker_id <> maker_id)
+);
+
+-- One pending request per operation and resource
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_requests_pending
+    ON approval_requests(service, operation, resource_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_approval_requests_service_status ON approval_requests(service, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_approval_requests_maker ON approval_requests(maker_id, created_at DESC);
@@ -0,0 +1,3 @@
+-- Remove wallet limits approval permissions
+DELETE FROM role_permissions WHERE permission_id = '20000000-0000-0000-0000-000000000024';
+DELETE FROM permissions WHERE id = '20000000-0000-0000-0000-000000000024';
@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Wallet Limits Approval Permissions
+-- ============================================================================
+
+-- Raising a wallet's limits needs an admin other than the owner holding this permission
+INSERT INTO permissions (id, name, service, resource, action, description, is_system) VALUES
+('20000000-0000-0000-0000-000000000024', 'wallet:wallet:limits_increase', 'wallet', 'wallet', 'limits_increase', 'Approve raising a wallet''s transfer limits', true)
+ON CONFLICT (name) DO NOTHING;
+
+-- ADMIN (and SUPER_ADMIN by inheritance) approve limit increases
+INSERT INTO role_permissions (role_id, permission_id) VALUES
+('00000000-0000-0000-0000-000000000005', '20000000-0000-0000-0000-000000000024')
+ON CONFLICT DO NOTHING;
@@ -2,12 +2,13 @@ package handler
 
 import (
 	"net/http"
-	"os"
 
 	"github.com/vnykmshr/nivo/services/risk/internal/service"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/logger"
 	"github.com/vnykmshr/nivo/shared/metrics"
 	"github.com/vnykmshr/nivo/shared/middleware"
+	"github.com/vnykmshr/nivo/shared/server"
 )
 
 // Router handles HTTP routing for the Risk Service
@@ -39,7 +40,7 @@ func (r *Router) SetupRoutes() http.Handler {
 
 	// Create JWT auth middleware for admin endpoints
 	authConfig := middleware.AuthConfig{
-		JWTSecret: os.Getenv("JWT_SECRET"),
+		Keys: sharedjwt.NewRemoteKeySet(server.GetEnv("JWKS_URL", sharedjwt.DefaultJWKSURL)),
 	}
 	jwtAuth := middleware.Auth(authConfig)
 
@@ -10,7 +10,6 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	simconfig "github.com/vnykmshr/nivo/services/simulation/internal/config"
 	"github.com/vnykmshr/nivo/services/simulation/internal/handler"
 	simmetrics "github.com/vnykmshr/nivo/services/simulation/internal/metrics"
@@ -43,27 +42,19 @@ func main() {
 
 	log.Printf("[%s] Connected to database successfully", serviceName)
 
-	// Get Gateway URL and admin token
+	// Get Gateway URL and the admin account the simulation acts as
 	gatewayURL := getEnvOrDefault("GATEWAY_URL", "http://gateway:8000")
-	adminToken := os.Getenv("ADMIN_TOKEN")
-	if adminToken == "" {
-		// Generate a service token using JWT_SECRET
-		jwtSecret := os.Getenv("JWT_SECRET")
-		if jwtSecret == "" {
-			log.Fatalf("[%s] Neither ADMIN_TOKEN nor JWT_SECRET set - cannot authenticate", serviceName)
-		}
-		var err error
-		adminToken, err = generateServiceToken(jwtSecret)
-		if err != nil {
-			log.Fatalf("[%s] Failed to generate service token: %v", serviceName, err)
-		}
-		log.Printf("[%s] Generated service token (expires in 1 year)", serviceName)
+	adminEmail := getEnvOrDefault("SIMULATION_ADMIN_EMAIL", "admin@nivo.local")
+	adminPassword := os.Getenv("SIMULATION_ADMIN_PASSWORD")
+	if adminPassword == "" {
+		log.Fatalf("[%s] SIMULATION_ADMIN_PASSWORD not set - cannot authenticate", serviceName)
 	}
 
 	log.Printf("[%s] Gateway URL: %s", serviceName, gatewayURL)
 
-	// Initialize gateway client
-	gatewayClient := service.NewGatewayClient(gatewayURL, adminToken)
+	// Initialize gateway client; admin calls use tokens from logging in as the admin
+	adminSession := service.NewAdminSession(gatewayURL, adminEmail, adminPassword)
+	gatewayClient := service.NewGatewayClient(gatewayURL, adminSession)
 
 	// Initialize simulation configuration
 	simulationConfig := simconfig.NewDefaultConfig()
@@ -187,93 +178,3 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
-
-// ServiceClaims represents JWT claims for a service token.
-type ServiceClaims struct {
-	UserID      string   `json:"user_id"`
-	Email       string   `json:"email"`
-	Status      string   `json:"status"`
-	AccountType string   `json:"account_type"`
-	Roles       []string `json:"roles"`
-	Permissions []string `json:"permissions"`
-	jwt.RegisteredClaims
-}
-
-// generateServiceToken creates a long-lived admin token for the simulation service.
-// This token has full admin permissions and expires in 1 year.
-func generateServiceToken(jwtSecret string) (string, error) {
-	// Full set of admin permissions matching seed service's admin role
-	// These are the specific permissions required by each service endpoint
-	permissions := []string{
-		// Identity service permissions
-		"identity:auth:login",
-		"identity:auth:logout",
-		"identity:auth:refresh",
-		"identity:profile:read",
-		"identity:profile:update",
-		"identity:profile:delete",
-		"identity:users:read",
-		"identity:users:create",
-		"identity:users:update",
-		"identity:users:delete",
-		"identity:kyc:submit",
-		"identity:kyc:read",
-		"identity:kyc:verify",
-		"identity:kyc:reject",
-		"identity:kyc:list",
-		"identity:user:suspend",
-		"identity:user:unsuspend",
-		// Wallet service permissions
-		"wallet:wallet:create",
-		"wallet:wallet:read",
-		"wallet:wallet:update",
-		"wallet:wallet:delete",
-		"wallet:wallet:list",
-		"wallet:wallet:freeze",
-		"wallet:wallet:unfreeze",
-		"wallet:beneficiary:manage",
-		// Transaction service permissions
-		"transaction:deposit:create",
-		"transaction:transfer:create",
-		"transaction:withdrawal:create",
-		"transaction:transaction:create",
-		"transaction:transaction:list",
-		"transaction:transaction:read",
-		"transaction:transaction:reverse",
-		// Ledger service permissions
-		"ledger:journal:read",
-		"ledger:journal:list",
-		"ledger:account:read",
-		"ledger:account:list",
-		// RBAC service permissions
-		"rbac:role:read",
-		"rbac:role:list",
-		"rbac:permission:read",
-		"rbac:permission:list",
-		// Risk service permissions
-		"risk:rule:read",
-		"risk:rule:list",
-		"risk:evaluation:read",
-		// Notification service permissions
-		"notification:notification:read",
-		"notification:notification:list",
-		"notification:notification:send",
-	}
-
-	claims := &ServiceClaims{
-		UserID:      "00000000-0000-0000-0000-000000000000", // System user ID
-		Email:       "simulation@system.nivo",
-		Status:      "active",
-		AccountType: "super_admin",
-		Roles:       []string{"super_admin", "admin"},
-		Permissions: permissions,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(365 * 24 * time.Hour)), // 1 year expiry
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "nivo-simulation",
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jwtSecret))
-}
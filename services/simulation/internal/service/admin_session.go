@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/clients"
+)
+
+// adminTokenRefreshMargin is how long before expiry the admin access token is replaced.
+const adminTokenRefreshMargin = time.Minute
+
+// AdminSession keeps an admin access token for the simulation's admin API
+// calls. It logs in through the gateway with the admin account's password and
+// renews the token with the refresh token before it expires, logging in again
+// if the refresh token is rejected.
+type AdminSession struct {
+	client   *clients.BaseClient
+	email    string
+	password string
+
+	mu               sync.Mutex
+	token            string
+	expiresAt        time.Time
+	refreshToken     string
+	refreshExpiresAt time.Time
+}
+
+// NewAdminSession creates a session for the admin account; it logs in on first use.
+func NewAdminSession(gatewayURL, email, password string) *AdminSession {
+	return &AdminSession{
+		client:   clients.NewBaseClient(gatewayURL, clients.DefaultTimeout),
+		email:    email,
+		password: password,
+	}
+}
+
+// adminTokenResponse is the token part of the identity service's login and refresh responses.
+type adminTokenResponse struct {
+	Token            string `json:"token"`
+	ExpiresAt        int64  `json:"expires_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt int64  `json:"refresh_expires_at"`
+}
+
+// Token returns a valid admin access token, refreshing or logging in as needed.
+func (s *AdminSession) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Until(s.expiresAt) > adminTokenRefreshMargin {
+		return s.token, nil
+	}
+
+	if s.refreshToken != "" && time.Until(s.refreshExpiresAt) > adminTokenRefreshMargin {
+		err := s.refresh(ctx)
+		if err == nil {
+			return s.token, nil
+		}
+		log.Printf("[simulation] Admin token refresh failed, logging in again: %v", err)
+	}
+
+	if err := s.login(ctx); err != nil {
+		return "", err
+	}
+	return s.token, nil
+}
+
+func (s *AdminSession) login(ctx context.Context) error {
+	req := LoginRequest{Identifier: s.email, Password: s.password}
+
+	var resp adminTokenResponse
+	if err := s.client.Post(ctx, "/api/v1/auth/login", req, &resp); err != nil {
+		return fmt.Errorf("admin login failed: %w", err)
+	}
+	if resp.Token == "" {
+		// Two-factor challenges can't be answered by the simulation
+		return fmt.Errorf("admin login for %s did not return a token; the account must not require two-factor login", s.email)
+	}
+
+	s.store(&resp)
+	log.Printf("[simulation] Admin logged in: %s", s.email)
+	return nil
+}
+
+func (s *AdminSession) refresh(ctx context.Context) error {
+	req := map[string]string{"refresh_token": s.refreshToken}
+
+	var resp adminTokenResponse
+	if err := s.client.Post(ctx, "/api/v1/auth/refresh", req, &resp); err != nil {
+		s.refreshToken = ""
+		return err
+	}
+
+	s.store(&resp)
+	return nil
+}
+
+func (s *AdminSession) store(resp *adminTokenResponse) {
+	s.token = resp.Token
+	s.expiresAt = time.Unix(resp.ExpiresAt, 0)
+	s.refreshToken = resp.RefreshToken
+	s.refreshExpiresAt = time.Unix(resp.RefreshExpiresAt, 0)
+}
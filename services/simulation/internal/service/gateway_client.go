@@ -12,18 +12,14 @@ import (
 // GatewayClient makes API calls to the Nivo Gateway
 type GatewayClient struct {
 	*clients.BaseClient
+	admin *AdminSession
 }
 
-// NewGatewayClient creates a new gateway client with admin auth token
-func NewGatewayClient(baseURL, authToken string) *GatewayClient {
-	var headers map[string]string
-	if authToken != "" {
-		headers = map[string]string{
-			"Authorization": "Bearer " + authToken,
-		}
-	}
+// NewGatewayClient creates a new gateway client that makes admin calls with admin's token
+func NewGatewayClient(baseURL string, admin *AdminSession) *GatewayClient {
 	return &GatewayClient{
-		BaseClient: clients.NewBaseClientWithHeaders(baseURL, clients.DefaultTimeout, headers),
+		BaseClient: clients.NewBaseClient(baseURL, clients.DefaultTimeout),
+		admin:      admin,
 	}
 }
 
@@ -117,8 +113,20 @@ func bearerToken(token string) map[string]string {
 	return map[string]string{"Authorization": "Bearer " + token}
 }
 
+// authHeaders creates auth headers for token, or for the admin session if token is empty.
+func (c *GatewayClient) authHeaders(ctx context.Context, token string) (map[string]string, error) {
+	if token == "" {
+		adminToken, err := c.admin.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		token = adminToken
+	}
+	return bearerToken(token), nil
+}
+
 // CreateDeposit creates a deposit transaction.
-// If token is provided, it's used for auth. Otherwise, falls back to client's default auth headers.
+// If token is provided, it's used for auth. Otherwise, the admin session's token is used.
 func (c *GatewayClient) CreateDeposit(ctx context.Context, token, walletID string, amountPaise int64, description string) error {
 	req := DepositRequest{
 		WalletID:    walletID,
@@ -127,21 +135,20 @@ func (c *GatewayClient) CreateDeposit(ctx context.Context, token, walletID strin
 		Description: description,
 	}
 
+	headers, err := c.authHeaders(ctx, token)
+	if err != nil {
+		return err
+	}
+
 	// Use typed error to avoid nil interface gotcha
-	if token != "" {
-		if err := c.PostWithHeaders(ctx, "/api/v1/transaction/transactions/deposit", req, nil, bearerToken(token)); err != nil {
-			return err
-		}
-	} else {
-		if err := c.Post(ctx, "/api/v1/transaction/transactions/deposit", req, nil); err != nil {
-			return err
-		}
+	if err := c.PostWithHeaders(ctx, "/api/v1/transaction/transactions/deposit", req, nil, headers); err != nil {
+		return err
 	}
 	return nil
 }
 
 // CreateTransfer creates a transfer transaction.
-// If token is provided, it's used for auth. Otherwise, falls back to client's default auth headers.
+// If token is provided, it's used for auth. Otherwise, the admin session's token is used.
 func (c *GatewayClient) CreateTransfer(ctx context.Context, token, sourceWalletID, destWalletID string, amountPaise int64, description string) error {
 	req := TransferRequest{
 		SourceWalletID:      sourceWalletID,
@@ -151,21 +158,20 @@ func (c *GatewayClient) CreateTransfer(ctx context.Context, token, sourceWalletI
 		Description:         description,
 	}
 
+	headers, err := c.authHeaders(ctx, token)
+	if err != nil {
+		return err
+	}
+
 	// Use typed error to avoid nil interface gotcha
-	if token != "" {
-		if err := c.PostWithHeaders(ctx, "/api/v1/transaction/transactions/transfer", req, nil, bearerToken(token)); err != nil {
-			return err
-		}
-	} else {
-		if err := c.Post(ctx, "/api/v1/transaction/transactions/transfer", req, nil); err != nil {
-			return err
-		}
+	if err := c.PostWithHeaders(ctx, "/api/v1/transaction/transactions/transfer", req, nil, headers); err != nil {
+		return err
 	}
 	return nil
 }
 
 // CreateWithdrawal creates a withdrawal transaction.
-// If token is provided, it's used for auth. Otherwise, falls back to client's default auth headers.
+// If token is provided, it's used for auth. Otherwise, the admin session's token is used.
 func (c *GatewayClient) CreateWithdrawal(ctx context.Context, token, walletID string, amountPaise int64, description string) error {
 	req := WithdrawalRequest{
 		WalletID:    walletID,
@@ -174,15 +180,14 @@ func (c *GatewayClient) CreateWithdrawal(ctx context.Context, token, walletID st
 		Description: description,
 	}
 
+	headers, err := c.authHeaders(ctx, token)
+	if err != nil {
+		return err
+	}
+
 	// Use typed error to avoid nil interface gotcha
-	if token != "" {
-		if err := c.PostWithHeaders(ctx, "/api/v1/transaction/transactions/withdrawal", req, nil, bearerToken(token)); err != nil {
-			return err
-		}
-	} else {
-		if err := c.Post(ctx, "/api/v1/transaction/transactions/withdrawal", req, nil); err != nil {
-			return err
-		}
+	if err := c.PostWithHeaders(ctx, "/api/v1/transaction/transactions/withdrawal", req, nil, headers); err != nil {
+		return err
 	}
 	return nil
 }
@@ -243,8 +248,13 @@ func (c *GatewayClient) SubmitKYC(ctx context.Context, token string, kycReq KYCS
 
 // VerifyKYC admin endpoint to verify KYC (requires admin token)
 func (c *GatewayClient) VerifyKYC(ctx context.Context, userID string) error {
+	headers, err := c.authHeaders(ctx, "")
+	if err != nil {
+		return err
+	}
+
 	path := fmt.Sprintf("/api/v1/admin/kyc/%s/verify", userID)
-	if err := c.Post(ctx, path, nil, nil); err != nil {
+	if err := c.PostWithHeaders(ctx, path, nil, nil, headers); err != nil {
 		return err
 	}
 	log.Printf("[simulation] ✓ KYC verified for user %s", userID)
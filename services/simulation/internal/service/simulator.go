@@ -419,7 +419,7 @@ func (s *SimulationEngine) generateTransaction(ctx context.Context, user UserWal
 		}
 	}
 
-	// Database users don't have session tokens - use empty token to rely on admin token from default headers
+	// Database users don't have session tokens - use empty token to act as the admin session
 	var err error
 	switch txType {
 	case "deposit":
@@ -1,28 +1,48 @@
 package main
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/vnykmshr/nivo/services/transaction/internal/handler"
 	"github.com/vnykmshr/nivo/services/transaction/internal/repository"
 	"github.com/vnykmshr/nivo/services/transaction/internal/router"
 	"github.com/vnykmshr/nivo/services/transaction/internal/service"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/audit"
+	"github.com/vnykmshr/nivo/shared/clients"
 	"github.com/vnykmshr/nivo/shared/events"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
+	"github.com/vnykmshr/nivo/shared/middleware"
 	"github.com/vnykmshr/nivo/shared/server"
 )
 
 func main() {
+	// Track worker cancel function for cleanup
+	var workerCancel context.CancelFunc
+
 	server.Run(server.ServiceConfig{
 		Name: "transaction",
 		SetupHandler: func(ctx *server.BootstrapContext) (http.Handler, error) {
 			// Initialize repository layer
 			transactionRepo := repository.NewTransactionRepository(ctx.DB.DB)
+			outboxRepo := repository.NewOutboxRepository(ctx.DB.DB)
+			sagaRepo := repository.NewSagaRepository(ctx.DB.DB)
+			scheduledTransferRepo := repository.NewScheduledTransferRepository(ctx.DB.DB)
+			paymentRequestRepo := repository.NewPaymentRequestRepository(ctx.DB.DB)
+			disputeRepo := repository.NewDisputeRepository(ctx.DB.DB)
+			idempotencyStore := middleware.NewPostgresIdempotencyStore(ctx.DB.DB)
 
-			// Initialize external service clients with internal auth for service-to-service calls
-			internalSecret := server.GetEnv("INTERNAL_SERVICE_SECRET", "")
+			// Initialize external service clients, with service tokens from the identity service for internal calls
+			identityURL := server.GetEnv("IDENTITY_SERVICE_URL", "http://identity-service:8080")
+			serviceTokens := clients.NewServiceTokenClient(identityURL, sharedjwt.ServiceTransaction, server.RequireEnv("SERVICE_SECRET"))
 			riskClient := service.NewRiskClient(server.GetEnv("RISK_SERVICE_URL", "http://risk-service:8085"))
-			walletClient := service.NewWalletClientWithSecret(server.GetEnv("WALLET_SERVICE_URL", "http://wallet-service:8083"), internalSecret)
-			ledgerClient := service.NewLedgerClient(server.GetEnv("LEDGER_SERVICE_URL", "http://ledger-service:8084"))
+			walletClient := service.NewWalletClient(server.GetEnv("WALLET_SERVICE_URL", "http://wallet-service:8083"), serviceTokens)
+			ledgerClient := service.NewLedgerClient(server.GetEnv("LEDGER_SERVICE_URL", "http://ledger-service:8081"), serviceTokens)
+			identityClient := service.NewIdentityClient(identityURL)
+			verificationClient := clients.NewVerificationClient(identityURL, serviceTokens)
+			notificationClient := clients.NewNotificationClient(server.GetEnv("NOTIFICATION_SERVICE_URL", "http://notification-service:8087"))
 
 			// Initialize event publisher
 			eventPublisher := events.NewPublisher(events.PublishConfig{
@@ -31,15 +51,217 @@ func main() {
 			})
 
 			// Initialize service layer
-			transactionService := service.NewTransactionService(transactionRepo, riskClient, walletClient, ledgerClient, eventPublisher)
+			settlementCode := server.GetEnv("LEDGER_SETTLEMENT_ACCOUNT_CODE", service.DefaultSettlementAccountCode)
+			ledgerPoster := service.NewLedgerPoster(transactionRepo, walletClient, ledgerClient, settlementCode)
+			transactionService := service.NewTransactionService(transactionRepo, sagaRepo, riskClient, walletClient, ledgerPoster)
+			transactionService.SetStepUpVerifier(verificationClient)
+			auditLog := audit.NewLog(audit.NewPostgresStore(ctx.DB.DB), "transaction")
+			transactionService.SetAuditLog(auditLog)
+			approvals := approval.NewWorkflow(approval.NewPostgresStore(ctx.DB.DB), "transaction")
+			approvals.SetAuditLog(auditLog)
+			transactionService.SetApprovals(approvals)
+			outboxRelay := service.NewOutboxRelay(outboxRepo, eventPublisher)
+			scheduledTransferService := service.NewScheduledTransferService(scheduledTransferRepo, transactionService, notificationClient)
+			scheduledTransferService.SetStepUpVerifier(verificationClient)
+			paymentRequestService := service.NewPaymentRequestService(paymentRequestRepo, identityClient, walletClient, transactionService, notificationClient, eventPublisher)
+			paymentRequestService.SetStepUpVerifier(verificationClient)
+			disputeService := service.NewDisputeService(disputeRepo, transactionService, walletClient, notificationClient, eventPublisher)
+			disputeService.SetAuditLog(auditLog)
+
+			// Start background worker delivering ledger postings and events from the outbox
+			relayInterval, err := time.ParseDuration(server.GetEnv("OUTBOX_RELAY_INTERVAL", "2s"))
+			if err != nil {
+				return nil, err
+			}
+
+			// Start background worker resuming sagas left pending or processing,
+			// e.g. by a restart, and retrying failed saga steps
+			recoveryInterval, err := time.ParseDuration(server.GetEnv("SAGA_RECOVERY_INTERVAL", "30s"))
+			if err != nil {
+				return nil, err
+			}
+
+			// Start background worker running scheduled transfers when due
+			schedulerInterval, err := time.ParseDuration(server.GetEnv("SCHEDULED_TRANSFER_INTERVAL", "1m"))
+			if err != nil {
+				return nil, err
+			}
+
+			// Start background worker expiring unanswered payment requests
+			paymentRequestExpiryInterval, err := time.ParseDuration(server.GetEnv("PAYMENT_REQUEST_EXPIRY_INTERVAL", "5m"))
+			if err != nil {
+				return nil, err
+			}
+
+			// Start background worker settling disputes resolved in the customer's
+			// favour and flagging disputes that breached their SLA
+			disputeInterval, err := time.ParseDuration(server.GetEnv("DISPUTE_WORKER_INTERVAL", "5m"))
+			if err != nil {
+				return nil, err
+			}
+
+			workerCtx, cancel := context.WithCancel(context.Background())
+			workerCancel = cancel
+
+			go func() {
+				ctx.Logger.WithField("interval", relayInterval.String()).Info("Starting outbox relay...")
+				ticker := time.NewTicker(relayInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						delivered, failed, err := outboxRelay.RelayPending(workerCtx)
+						if err != nil {
+							ctx.Logger.WithError(err).Error("Outbox relay failed")
+							continue
+						}
+						if delivered > 0 || failed > 0 {
+							ctx.Logger.WithField("delivered", delivered).
+								WithField("failed", failed).
+								Info("Outbox relay delivered messages")
+						}
+					case <-workerCtx.Done():
+						ctx.Logger.Info("Outbox relay stopped")
+						return
+					}
+				}
+			}()
+
+			go func() {
+				ctx.Logger.WithField("interval", recoveryInterval.String()).Info("Starting saga recovery worker...")
+				ticker := time.NewTicker(recoveryInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						resumed, err := transactionService.RecoverSagas(workerCtx)
+						if err != nil {
+							ctx.Logger.WithError(err).Error("Saga recovery failed")
+							continue
+						}
+						if resumed > 0 {
+							ctx.Logger.WithField("resumed", resumed).Info("Saga recovery resumed transactions")
+						}
+					case <-workerCtx.Done():
+						ctx.Logger.Info("Saga recovery worker stopped")
+						return
+					}
+				}
+			}()
+
+			go func() {
+				ctx.Logger.WithField("interval", schedulerInterval.String()).Info("Starting scheduled transfer worker...")
+				ticker := time.NewTicker(schedulerInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						run, err := scheduledTransferService.RunDue(workerCtx)
+						if err != nil {
+							ctx.Logger.WithError(err).Error("Scheduled transfer run failed")
+							continue
+						}
+						if run > 0 {
+							ctx.Logger.WithField("run", run).Info("Scheduled transfers run")
+						}
+					case <-workerCtx.Done():
+						ctx.Logger.Info("Scheduled transfer worker stopped")
+						return
+					}
+				}
+			}()
+
+			go func() {
+				ctx.Logger.WithField("interval", paymentRequestExpiryInterval.String()).Info("Starting payment request expiry worker...")
+				ticker := time.NewTicker(paymentRequestExpiryInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						expired, err := paymentRequestService.ExpireDue(workerCtx)
+						if err != nil {
+							ctx.Logger.WithError(err).Error("Payment request expiry failed")
+							continue
+						}
+						if expired > 0 {
+							ctx.Logger.WithField("expired", expired).Info("Payment requests expired")
+						}
+					case <-workerCtx.Done():
+						ctx.Logger.Info("Payment request expiry worker stopped")
+						return
+					}
+				}
+			}()
+
+			go func() {
+				ctx.Logger.WithField("interval", disputeInterval.String()).Info("Starting dispute worker...")
+				ticker := time.NewTicker(disputeInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						settled, err := disputeService.SettleDue(workerCtx)
+						if err != nil {
+							ctx.Logger.WithError(err).Error("Dispute settlement failed")
+						} else if settled > 0 {
+							ctx.Logger.WithField("settled", settled).Info("Disputes settled")
+						}
+
+						flagged, err := disputeService.FlagOverdue(workerCtx)
+						if err != nil {
+							ctx.Logger.WithError(err).Error("Dispute SLA check failed")
+						} else if flagged > 0 {
+							ctx.Logger.WithField("flagged", flagged).Warn("Disputes breached their SLA")
+						}
+					case <-workerCtx.Done():
+						ctx.Logger.Info("Dispute worker stopped")
+						return
+					}
+				}
+			}()
+
+			go func() {
+				ticker := time.NewTicker(time.Hour)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						purged, err := idempotencyStore.PurgeExpired(workerCtx)
+						if err != nil {
+							ctx.Logger.WithError(err).Error("Idempotency key purge failed")
+							continue
+						}
+						if purged > 0 {
+							ctx.Logger.WithField("purged", purged).Info("Expired idempotency keys purged")
+						}
+					case <-workerCtx.Done():
+						return
+					}
+				}
+			}()
 
 			// Initialize handler layer
 			transactionHandler := handler.NewTransactionHandler(transactionService, walletClient)
+			scheduledTransferHandler := handler.NewScheduledTransferHandler(scheduledTransferService, walletClient)
+			paymentRequestHandler := handler.NewPaymentRequestHandler(paymentRequestService, walletClient)
+			disputeHandler := handler.NewDisputeHandler(disputeService)
 
-			// Setup routes
-			jwtSecret := server.RequireEnv("JWT_SECRET")
+			// Setup routes (user and service tokens are verified against the identity service's published keys)
+			jwtKeys := sharedjwt.NewRemoteKeySet(server.GetEnv("JWKS_URL", sharedjwt.DefaultJWKSURL))
 
-			return router.SetupRoutes(transactionHandler, jwtSecret), nil
+			return router.SetupRoutes(transactionHandler, scheduledTransferHandler, paymentRequestHandler, disputeHandler, approval.NewHandler(approvals), idempotencyStore, jwtKeys), nil
+		},
+		Cleanup: func() error {
+			if workerCancel != nil {
+				workerCancel()
+			}
+			return nil
 		},
 	})
 }
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/services/transaction/internal/service"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/handler"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	"github.com/vnykmshr/nivo/shared/pagination"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// DisputeHandler handles HTTP requests for transaction disputes.
+type DisputeHandler struct {
+	disputeService *service.DisputeService
+}
+
+// NewDisputeHandler creates a new dispute handler.
+func NewDisputeHandler(disputeService *service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+	}
+}
+
+// OpenDispute handles POST /api/v1/transactions/{id}/disputes
+func (h *DisputeHandler) OpenDispute(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := handler.BindRequest[models.OpenDisputeRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	dispute, err := h.disputeService.Open(r.Context(), userID, r.PathValue("id"), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, dispute)
+}
+
+// ListDisputes handles GET /api/v1/disputes
+// Lists disputes the user opened or is the counterparty of.
+// Query parameters: status, page, per_page.
+func (h *DisputeHandler) ListDisputes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	h.list(w, r, userID)
+}
+
+// GetDispute handles GET /api/v1/disputes/{id}
+func (h *DisputeHandler) GetDispute(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	dispute, err := h.disputeService.Get(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, dispute)
+}
+
+// AdminListDisputes handles GET /api/v1/admin/transactions/disputes
+// Query parameters: status, overdue=true, page, per_page.
+func (h *DisputeHandler) AdminListDisputes(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, "")
+}
+
+// AdminGetDispute handles GET /api/v1/admin/transactions/disputes/{id}
+func (h *DisputeHandler) AdminGetDispute(w http.ResponseWriter, r *http.Request) {
+	dispute, err := h.disputeService.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, dispute)
+}
+
+// InvestigateDispute handles POST /api/v1/admin/transactions/disputes/{id}/investigate
+func (h *DisputeHandler) InvestigateDispute(w http.ResponseWriter, r *http.Request) {
+	dispute, err := h.disputeService.StartInvestigation(r.Context(), r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, dispute)
+}
+
+// ResolveDispute handles POST /api/v1/admin/transactions/disputes/{id}/resolve
+// A resolution in the customer's favour refunds them; the returned dispute
+// shows whether the settlement has completed.
+func (h *DisputeHandler) ResolveDispute(w http.ResponseWriter, r *http.Request) {
+	req, bindErr := handler.BindRequest[models.ResolveDisputeRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	dispute, err := h.disputeService.Resolve(r.Context(), r.PathValue("id"), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, dispute)
+}
+
+// list lists disputes of the user, or all disputes if userID is empty.
+func (h *DisputeHandler) list(w http.ResponseWriter, r *http.Request, userID string) {
+	params := pagination.FromRequest(r)
+	filter := &models.DisputeFilter{
+		UserID:  userID,
+		Overdue: r.URL.Query().Get("overdue") == "true",
+		Limit:   params.PerPage,
+		Offset:  params.Offset,
+	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		s := models.DisputeStatus(status)
+		filter.Status = &s
+	}
+
+	disputes, total, err := h.disputeService.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, disputes, params.Page, params.PerPage, int64(total))
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/services/transaction/internal/service"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/handler"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	"github.com/vnykmshr/nivo/shared/pagination"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// PaymentRequestHandler handles HTTP requests for payment requests.
+type PaymentRequestHandler struct {
+	paymentRequestService *service.PaymentRequestService
+	walletClient          *service.WalletClient
+}
+
+// NewPaymentRequestHandler creates a new payment request handler.
+func NewPaymentRequestHandler(paymentRequestService *service.PaymentRequestService, walletClient *service.WalletClient) *PaymentRequestHandler {
+	return &PaymentRequestHandler{
+		paymentRequestService: paymentRequestService,
+		walletClient:          walletClient,
+	}
+}
+
+// CreatePaymentRequest handles POST /api/v1/payment-requests
+func (h *PaymentRequestHandler) CreatePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := handler.BindRequest[models.CreatePaymentRequestRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	// The money must go to a wallet of the requester
+	if err := h.walletClient.VerifyWalletOwnership(r.Context(), req.WalletID, userID); err != nil {
+		response.Error(w, errors.Forbidden("wallet does not belong to user"))
+		return
+	}
+
+	request, createErr := h.paymentRequestService.Create(r.Context(), userID, &req)
+	if createErr != nil {
+		response.Error(w, createErr)
+		return
+	}
+
+	response.Created(w, request)
+}
+
+// ListPaymentRequests handles GET /api/v1/payment-requests
+// Query parameters: direction (incoming, the default, or outgoing), status, page, per_page.
+func (h *PaymentRequestHandler) ListPaymentRequests(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	params := pagination.FromRequest(r)
+	filter := &models.PaymentRequestFilter{
+		UserID:    userID,
+		Direction: models.PaymentRequestIncoming,
+		Limit:     params.PerPage,
+		Offset:    params.Offset,
+	}
+	if direction := r.URL.Query().Get("direction"); direction != "" {
+		filter.Direction = models.PaymentRequestDirection(direction)
+	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		s := models.PaymentRequestStatus(status)
+		filter.Status = &s
+	}
+
+	requests, total, err := h.paymentRequestService.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, requests, params.Page, params.PerPage, int64(total))
+}
+
+// GetPaymentRequest handles GET /api/v1/payment-requests/{id}
+func (h *PaymentRequestHandler) GetPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	request, err := h.paymentRequestService.Get(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, request)
+}
+
+// AcceptPaymentRequest handles POST /api/v1/payment-requests/{id}/accept
+func (h *PaymentRequestHandler) AcceptPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := handler.BindRequest[models.AcceptPaymentRequestRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	// The payer pays from one of their own wallets
+	if err := h.walletClient.VerifyWalletOwnership(r.Context(), req.SourceWalletID, userID); err != nil {
+		response.Error(w, errors.Forbidden("wallet does not belong to user"))
+		return
+	}
+
+	request, acceptErr := h.paymentRequestService.Accept(r.Context(), userID, r.PathValue("id"), &req)
+	if acceptErr != nil {
+		response.Error(w, acceptErr)
+		return
+	}
+
+	response.OK(w, request)
+}
+
+// DeclinePaymentRequest handles POST /api/v1/payment-requests/{id}/decline
+// The body, with an optional reason, may be omitted.
+func (h *PaymentRequestHandler) DeclinePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	var req models.DeclinePaymentRequestRequest
+	if r.ContentLength != 0 {
+		var bindErr *errors.Error
+		if req, bindErr = handler.BindRequest[models.DeclinePaymentRequestRequest](r); bindErr != nil {
+			response.Error(w, bindErr)
+			return
+		}
+	}
+
+	request, err := h.paymentRequestService.Decline(r.Context(), userID, r.PathValue("id"), req.Reason)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, request)
+}
+
+// CancelPaymentRequest handles POST /api/v1/payment-requests/{id}/cancel
+func (h *PaymentRequestHandler) CancelPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	request, err := h.paymentRequestService.Cancel(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, request)
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/services/transaction/internal/service"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/handler"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	"github.com/vnykmshr/nivo/shared/pagination"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// ScheduledTransferHandler handles HTTP requests for scheduled transfers.
+type ScheduledTransferHandler struct {
+	scheduledTransferService *service.ScheduledTransferService
+	walletClient             *service.WalletClient
+}
+
+// NewScheduledTransferHandler creates a new scheduled transfer handler.
+func NewScheduledTransferHandler(scheduledTransferService *service.ScheduledTransferService, walletClient *service.WalletClient) *ScheduledTransferHandler {
+	return &ScheduledTransferHandler{
+		scheduledTransferService: scheduledTransferService,
+		walletClient:             walletClient,
+	}
+}
+
+// CreateScheduledTransfer handles POST /api/v1/scheduled-transfers
+func (h *ScheduledTransferHandler) CreateScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := handler.BindRequest[models.CreateScheduledTransferRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	// Only the owner of the source wallet can schedule transfers from it
+	if err := h.walletClient.VerifyWalletOwnership(r.Context(), req.SourceWalletID, userID); err != nil {
+		response.Error(w, errors.Forbidden("wallet does not belong to user"))
+		return
+	}
+
+	schedule, createErr := h.scheduledTransferService.Create(r.Context(), userID, &req)
+	if createErr != nil {
+		response.Error(w, createErr)
+		return
+	}
+
+	response.Created(w, schedule)
+}
+
+// ListScheduledTransfers handles GET /api/v1/scheduled-transfers
+func (h *ScheduledTransferHandler) ListScheduledTransfers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	params := pagination.FromRequest(r)
+	filter := &models.ScheduledTransferFilter{
+		UserID: userID,
+		Limit:  params.PerPage,
+		Offset: params.Offset,
+	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		s := models.ScheduleStatus(status)
+		filter.Status = &s
+	}
+
+	schedules, total, err := h.scheduledTransferService.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, schedules, params.Page, params.PerPage, int64(total))
+}
+
+// GetScheduledTransfer handles GET /api/v1/scheduled-transfers/{id}
+func (h *ScheduledTransferHandler) GetScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	schedule, err := h.scheduledTransferService.Get(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, schedule)
+}
+
+// ListExecutions handles GET /api/v1/scheduled-transfers/{id}/executions
+func (h *ScheduledTransferHandler) ListExecutions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	params := pagination.FromRequest(r)
+	executions, total, err := h.scheduledTransferService.ListExecutions(r.Context(), userID, r.PathValue("id"), params.PerPage, params.Offset)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, executions, params.Page, params.PerPage, int64(total))
+}
+
+// SkipScheduledTransfer handles POST /api/v1/scheduled-transfers/{id}/skip
+func (h *ScheduledTransferHandler) SkipScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, h.scheduledTransferService.Skip)
+}
+
+// PauseScheduledTransfer handles POST /api/v1/scheduled-transfers/{id}/pause
+func (h *ScheduledTransferHandler) PauseScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, h.scheduledTransferService.Pause)
+}
+
+// ResumeScheduledTransfer handles POST /api/v1/scheduled-transfers/{id}/resume
+func (h *ScheduledTransferHandler) ResumeScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, h.scheduledTransferService.Resume)
+}
+
+// CancelScheduledTransfer handles POST /api/v1/scheduled-transfers/{id}/cancel
+func (h *ScheduledTransferHandler) CancelScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, h.scheduledTransferService.Cancel)
+}
+
+// update applies a state change to a scheduled transfer of the authenticated user.
+func (h *ScheduledTransferHandler) update(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, userID, id string) (*models.ScheduledTransfer, *errors.Error)) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	schedule, err := apply(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, schedule)
+}
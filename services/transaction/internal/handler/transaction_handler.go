@@ -73,6 +73,7 @@ func (h *TransactionHandler) verifyTransactionOwnership(r *http.Request, transac
 }
 
 // CreateTransfer handles POST /api/v1/transactions/transfer
+// Transfers above the high-value threshold need a verification_token.
 func (h *TransactionHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
 	req, bindErr := handler.BindRequest[models.CreateTransferRequest](r)
 	if bindErr != nil {
@@ -80,7 +81,13 @@ func (h *TransactionHandler) CreateTransfer(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	transaction, createErr := h.transactionService.CreateTransfer(r.Context(), &req)
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	transaction, createErr := h.transactionService.CreateUserTransfer(r.Context(), userID, &req)
 	if createErr != nil {
 		response.Error(w, createErr)
 		return
@@ -396,6 +403,7 @@ func (h *TransactionHandler) SearchAllTransactions(w http.ResponseWriter, r *htt
 }
 
 // ReverseTransaction handles POST /api/v1/transactions/:id/reverse
+// Submits the reversal for approval by a second admin and returns the pending request.
 func (h *TransactionHandler) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
 	transactionID := r.PathValue("id")
 
@@ -410,36 +418,32 @@ func (h *TransactionHandler) ReverseTransaction(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	reversalTx, reverseErr := h.transactionService.ReverseTransaction(r.Context(), transactionID, req.Reason)
+	approvalReq, reverseErr := h.transactionService.RequestReversal(r.Context(), transactionID, req.Reason)
 	if reverseErr != nil {
 		response.Error(w, reverseErr)
 		return
 	}
 
-	response.Created(w, reversalTx)
+	// The reversal is made once a second admin approves the request
+	response.Success(w, http.StatusAccepted, approvalReq)
 }
 
-// ProcessTransfer handles POST /internal/v1/transactions/:id/process (internal endpoint)
-// This endpoint processes a pending transfer transaction by executing the wallet-to-wallet transfer.
-func (h *TransactionHandler) ProcessTransfer(w http.ResponseWriter, r *http.Request) {
-	transactionID := r.PathValue("id")
-
-	if transactionID == "" {
-		response.Error(w, errors.BadRequest("transaction ID is required"))
+// GetWalletTotalsInternal handles POST /internal/v1/transactions/wallet-totals
+// Returns settled transaction totals per wallet (called by wallet service reconciliation).
+func (h *TransactionHandler) GetWalletTotalsInternal(w http.ResponseWriter, r *http.Request) {
+	req, bindErr := handler.BindRequest[models.WalletTotalsRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
 		return
 	}
 
-	processErr := h.transactionService.ProcessTransfer(r.Context(), transactionID)
-	if processErr != nil {
-		response.Error(w, processErr)
+	totals, svcErr := h.transactionService.GetWalletTotals(r.Context(), req.WalletIDs)
+	if svcErr != nil {
+		response.Error(w, svcErr)
 		return
 	}
 
-	response.OK(w, map[string]interface{}{
-		"success":        true,
-		"transaction_id": transactionID,
-		"message":        "Transfer processed successfully",
-	})
+	response.OK(w, totals)
 }
 
 // ========================================================================
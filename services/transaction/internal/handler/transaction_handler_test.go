@@ -31,7 +31,6 @@ type mockTransactionRepository struct {
 	ListByWalletFunc        func(ctx context.Context, walletID string, filter *models.TransactionFilter) ([]*models.Transaction, *errors.Error)
 	SearchAllFunc           func(ctx context.Context, filter *models.TransactionFilter) ([]*models.Transaction, *errors.Error)
 	UpdateMetadataFunc      func(ctx context.Context, id string, metadata map[string]string) *errors.Error
-	CompleteFunc            func(ctx context.Context, id string, metadata map[string]string) *errors.Error
 	UpdateStatusFunc        func(ctx context.Context, id string, status models.TransactionStatus, failureReason *string) *errors.Error
 	UpdateCategoryFunc      func(ctx context.Context, id string, category models.SpendingCategory) *errors.Error
 	GetCategoryPatternsFunc func(ctx context.Context) ([]*models.CategoryPattern, *errors.Error)
@@ -44,7 +43,7 @@ func newMockTransactionRepository() *mockTransactionRepository {
 	}
 }
 
-func (m *mockTransactionRepository) Create(ctx context.Context, transaction *models.Transaction) *errors.Error {
+func (m *mockTransactionRepository) Create(ctx context.Context, transaction *models.Transaction, outbox ...*models.OutboxMessage) *errors.Error {
 	if m.CreateFunc != nil {
 		return m.CreateFunc(ctx, transaction)
 	}
@@ -58,6 +57,14 @@ func (m *mockTransactionRepository) Create(ctx context.Context, transaction *mod
 	return nil
 }
 
+func (m *mockTransactionRepository) CreateWithSaga(ctx context.Context, transaction *models.Transaction, saga *models.TransactionSaga, outbox ...*models.OutboxMessage) *errors.Error {
+	if err := m.Create(ctx, transaction, outbox...); err != nil {
+		return err
+	}
+	saga.TransactionID = transaction.ID
+	return nil
+}
+
 func (m *mockTransactionRepository) GetByID(ctx context.Context, id string) (*models.Transaction, *errors.Error) {
 	if m.GetByIDFunc != nil {
 		return m.GetByIDFunc(ctx, id)
@@ -104,27 +111,29 @@ func (m *mockTransactionRepository) UpdateMetadata(ctx context.Context, id strin
 	return errors.NotFound("transaction not found")
 }
 
-func (m *mockTransactionRepository) CompleteWithMetadata(ctx context.Context, id string, metadata map[string]string) *errors.Error {
-	if m.CompleteFunc != nil {
-		return m.CompleteFunc(ctx, id, metadata)
+func (m *mockTransactionRepository) UpdateStatus(ctx context.Context, id string, status models.TransactionStatus, failureReason *string, outbox ...*models.OutboxMessage) *errors.Error {
+	if m.UpdateStatusFunc != nil {
+		return m.UpdateStatusFunc(ctx, id, status, failureReason)
 	}
 	if tx, ok := m.transactions[id]; ok {
-		tx.Status = models.TransactionStatusCompleted
-		tx.Metadata = metadata
-		now := sharedModels.Now()
-		tx.CompletedAt = &now
+		tx.Status = status
+		tx.FailureReason = failureReason
 		return nil
 	}
 	return errors.NotFound("transaction not found")
 }
 
-func (m *mockTransactionRepository) UpdateStatus(ctx context.Context, id string, status models.TransactionStatus, failureReason *string) *errors.Error {
-	if m.UpdateStatusFunc != nil {
-		return m.UpdateStatusFunc(ctx, id, status, failureReason)
+func (m *mockTransactionRepository) MarkProcessed(ctx context.Context, id string) *errors.Error {
+	if tx, ok := m.transactions[id]; ok {
+		tx.Status = models.TransactionStatusProcessing
+		return nil
 	}
+	return errors.NotFound("transaction not found")
+}
+
+func (m *mockTransactionRepository) UpdateLedgerEntry(ctx context.Context, id, ledgerEntryID string) *errors.Error {
 	if tx, ok := m.transactions[id]; ok {
-		tx.Status = status
-		tx.FailureReason = failureReason
+		tx.LedgerEntryID = &ledgerEntryID
 		return nil
 	}
 	return errors.NotFound("transaction not found")
@@ -155,11 +164,57 @@ func (m *mockTransactionRepository) GetCategorySummary(ctx context.Context, wall
 	return []models.CategorySummary{}, nil
 }
 
+func (m *mockTransactionRepository) SumByWallets(ctx context.Context, walletIDs []string) ([]models.WalletTotals, *errors.Error) {
+	totals := make([]models.WalletTotals, 0, len(walletIDs))
+	for _, walletID := range walletIDs {
+		totals = append(totals, models.WalletTotals{WalletID: walletID})
+	}
+	return totals, nil
+}
+
 // AddTransaction adds a transaction to the mock store (for test setup).
 func (m *mockTransactionRepository) AddTransaction(tx *models.Transaction) {
 	m.transactions[tx.ID] = tx
 }
 
+// ============================================================
+// Mock Saga Repository
+// ============================================================
+
+type mockSagaRepository struct {
+	sagas map[string]*models.TransactionSaga
+}
+
+func newMockSagaRepository() *mockSagaRepository {
+	return &mockSagaRepository{
+		sagas: make(map[string]*models.TransactionSaga),
+	}
+}
+
+func (m *mockSagaRepository) Start(ctx context.Context, saga *models.TransactionSaga) (*models.TransactionSaga, *errors.Error) {
+	if existing, ok := m.sagas[saga.TransactionID]; ok {
+		return existing, nil
+	}
+	m.sagas[saga.TransactionID] = saga
+	return saga, nil
+}
+
+func (m *mockSagaRepository) Get(ctx context.Context, transactionID string) (*models.TransactionSaga, *errors.Error) {
+	if saga, ok := m.sagas[transactionID]; ok {
+		return saga, nil
+	}
+	return nil, errors.NotFoundWithID("saga", transactionID)
+}
+
+func (m *mockSagaRepository) Update(ctx context.Context, saga *models.TransactionSaga) *errors.Error {
+	m.sagas[saga.TransactionID] = saga
+	return nil
+}
+
+func (m *mockSagaRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.TransactionSaga, *errors.Error) {
+	return []*models.TransactionSaga{}, nil
+}
+
 // ============================================================
 // Test Helper Functions
 // ============================================================
@@ -181,10 +236,10 @@ func createTestTransactionService() (*service.TransactionService, *mockTransacti
 	txRepo := newMockTransactionRepository()
 	txService := service.NewTransactionService(
 		txRepo,
+		newMockSagaRepository(),
 		nil, // riskClient
 		nil, // walletClient
-		nil, // ledgerClient
-		nil, // eventPublisher
+		nil, // ledgerPoster
 	)
 	return txService, txRepo
 }
@@ -408,43 +463,6 @@ func TestTransactionHandler_ListWalletTransactions(t *testing.T) {
 	})
 }
 
-func TestTransactionHandler_ProcessTransfer(t *testing.T) {
-	txService, txRepo := createTestTransactionService()
-	handler := NewTransactionHandler(txService, nil)
-
-	// Setup: Add a pending transfer transaction
-	sourceWallet := "wallet-source"
-	destWallet := "wallet-dest"
-	pendingTx := &models.Transaction{
-		ID:                  "tx-pending-transfer",
-		Type:                models.TransactionTypeTransfer,
-		Status:              models.TransactionStatusPending,
-		SourceWalletID:      &sourceWallet,
-		DestinationWalletID: &destWallet,
-		Amount:              50000,
-		Currency:            "INR",
-		Description:         "Pending transfer",
-	}
-	txRepo.AddTransaction(pendingTx)
-
-	t.Run("process transfer without ID returns 400", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodPost, "/internal/v1/transactions//process", nil)
-		rec := httptest.NewRecorder()
-		handler.ProcessTransfer(rec, req)
-
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
-	})
-
-	t.Run("process non-existent transfer returns 404", func(t *testing.T) {
-		rec, resp := makeRequestWithPathValue(t, handler.ProcessTransfer, http.MethodPost, "/internal/v1/transactions/non-existent/process", "id", "non-existent", nil)
-
-		assert.Equal(t, http.StatusNotFound, rec.Code)
-		assert.False(t, resp.Success)
-		require.NotNil(t, resp.Error)
-		assert.Equal(t, "NOT_FOUND", resp.Error.Code)
-	})
-}
-
 func TestTransactionHandler_ReverseTransaction(t *testing.T) {
 	txService, txRepo := createTestTransactionService()
 	handler := NewTransactionHandler(txService, nil)
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// DisputeStatus represents the state of a dispute.
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen             DisputeStatus = "open"               // Waiting for an admin to pick it up
+	DisputeStatusInvestigating    DisputeStatus = "investigating"      // Being investigated by an admin
+	DisputeStatusResolvedInFavour DisputeStatus = "resolved_in_favour" // Upheld: the customer gets the money back
+	DisputeStatusSettlementFailed DisputeStatus = "settlement_failed"  // Upheld, but settling it ran out of attempts; needs an admin
+	DisputeStatusRejected         DisputeStatus = "rejected"           // Not upheld
+)
+
+const (
+	// DisputeAcknowledgeSLA is how long an open dispute may wait for an admin to start investigating.
+	DisputeAcknowledgeSLA = 24 * time.Hour
+	// DisputeResolutionSLA is how long after it is opened a dispute must be resolved.
+	DisputeResolutionSLA = 10 * 24 * time.Hour
+	// DisputeWindow is how long after a transaction completes it can be disputed.
+	DisputeWindow = 120 * 24 * time.Hour
+)
+
+// Dispute is a customer's claim that a completed transaction from their wallet
+// should be refunded. Resolving it in the customer's favour settles it with a
+// provisional credit to the customer's wallet (a refund transaction) and, for
+// transfers, a final reversal recovering the amount from the counterparty's
+// wallet (a reversal transaction). Both are posted to the ledger.
+type Dispute struct {
+	ID                    string            `json:"id" db:"id"`
+	TransactionID         string            `json:"transaction_id" db:"transaction_id"`
+	CustomerUserID        string            `json:"customer_user_id" db:"customer_user_id"`
+	CustomerWalletID      string            `json:"customer_wallet_id" db:"customer_wallet_id"`
+	CounterpartyUserID    *string           `json:"counterparty_user_id,omitempty" db:"counterparty_user_id"`     // Transfers only
+	CounterpartyWalletID  *string           `json:"counterparty_wallet_id,omitempty" db:"counterparty_wallet_id"` // Transfers only
+	Amount                int64             `json:"amount" db:"amount"`                                           // In smallest unit (paise)
+	Currency              models.Currency   `json:"currency" db:"currency"`
+	Description           string            `json:"description" db:"description"` // Of the disputed transaction
+	Reason                DisputeReason     `json:"reason" db:"reason"`
+	Evidence              string            `json:"evidence" db:"evidence"` // The customer's notes
+	Status                DisputeStatus     `json:"status" db:"status"`
+	AssignedTo            *string           `json:"assigned_to,omitempty" db:"assigned_to"` // Investigating admin
+	ResolutionNotes       *string           `json:"resolution_notes,omitempty" db:"resolution_notes"`
+	ResolvedBy            *string           `json:"resolved_by,omitempty" db:"resolved_by"`
+	CreditTransactionID   *string           `json:"credit_transaction_id,omitempty" db:"credit_transaction_id"`     // Latest provisional credit
+	ReversalTransactionID *string           `json:"reversal_transaction_id,omitempty" db:"reversal_transaction_id"` // Latest recovery attempt
+	AcknowledgeBy         models.Timestamp  `json:"acknowledge_by" db:"acknowledge_by"`
+	ResolveBy             models.Timestamp  `json:"resolve_by" db:"resolve_by"`
+	SLABreachedAt         *models.Timestamp `json:"sla_breached_at,omitempty" db:"sla_breached_at"`
+	InvestigatingAt       *models.Timestamp `json:"investigating_at,omitempty" db:"investigating_at"`
+	ResolvedAt            *models.Timestamp `json:"resolved_at,omitempty" db:"resolved_at"`
+	SettledAt             *models.Timestamp `json:"settled_at,omitempty" db:"settled_at"`         // Money movements done
+	SettlementAttempts    int               `json:"settlement_attempts" db:"settlement_attempts"` // Settlement transactions created, failed ones included
+	CreatedAt             models.Timestamp  `json:"created_at" db:"created_at"`
+	UpdatedAt             models.Timestamp  `json:"updated_at" db:"updated_at"`
+}
+
+// DisputeReason is why a customer disputes a transaction.
+type DisputeReason string
+
+const (
+	DisputeReasonUnauthorized    DisputeReason = "unauthorized"     // Not made by the customer
+	DisputeReasonNotReceived     DisputeReason = "not_received"     // Goods or services not received
+	DisputeReasonDuplicate       DisputeReason = "duplicate"        // Charged twice
+	DisputeReasonIncorrectAmount DisputeReason = "incorrect_amount" // Wrong amount
+	DisputeReasonOther           DisputeReason = "other"
+)
+
+// IsValid returns true if the reason is one of the known dispute reasons.
+func (r DisputeReason) IsValid() bool {
+	switch r {
+	case DisputeReasonUnauthorized, DisputeReasonNotReceived, DisputeReasonDuplicate,
+		DisputeReasonIncorrectAmount, DisputeReasonOther:
+		return true
+	}
+	return false
+}
+
+// IsActive returns true if the dispute has not been resolved yet.
+func (d *Dispute) IsActive() bool {
+	return d.Status == DisputeStatusOpen || d.Status == DisputeStatusInvestigating
+}
+
+// HasParty returns true if the user is the customer or the counterparty.
+func (d *Dispute) HasParty(userID string) bool {
+	return d.CustomerUserID == userID || (d.CounterpartyUserID != nil && *d.CounterpartyUserID == userID)
+}
+
+// OpenDisputeRequest represents a customer disputing a transaction.
+type OpenDisputeRequest struct {
+	Reason   DisputeReason `json:"reason" validate:"required"`
+	Evidence string        `json:"evidence" validate:"required,min=10,max=2000"`
+}
+
+// ResolveDisputeRequest represents an admin resolving a dispute.
+type ResolveDisputeRequest struct {
+	InFavour bool   `json:"in_favour"`
+	Notes    string `json:"notes" validate:"required,min=3,max=1000"`
+}
+
+// DisputeFilter represents filters for listing disputes. Users see disputes
+// they are a party to; admins list all disputes.
+type DisputeFilter struct {
+	UserID  string // Party to the dispute; empty for all
+	Status  *DisputeStatus
+	Overdue bool // Only disputes that breached their SLA
+	Limit   int
+	Offset  int
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// OutboxKind identifies the side effect an outbox message delivers.
+type OutboxKind string
+
+const (
+	OutboxKindEvent OutboxKind = "event" // Event published to the gateway
+)
+
+// OutboxStatus represents the delivery status of an outbox message.
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"   // Waiting for (re)delivery
+	OutboxStatusDelivered OutboxStatus = "delivered" // Delivered successfully
+	OutboxStatusDead      OutboxStatus = "dead"      // Gave up after the maximum number of attempts
+)
+
+// OutboxMessage is a side effect of a transaction, recorded in the same database
+// transaction as the status change that caused it and delivered by the outbox relay.
+type OutboxMessage struct {
+	ID            string                 `json:"id" db:"id"`
+	TransactionID string                 `json:"transaction_id" db:"transaction_id"`
+	Kind          OutboxKind             `json:"kind" db:"kind"`
+	EventType     *string                `json:"event_type,omitempty" db:"event_type"`
+	Payload       map[string]interface{} `json:"payload" db:"payload"`
+	Status        OutboxStatus           `json:"status" db:"status"`
+	Attempts      int                    `json:"attempts" db:"attempts"`
+	NextAttemptAt models.Timestamp       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     *string                `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     models.Timestamp       `json:"created_at" db:"created_at"`
+	DeliveredAt   *models.Timestamp      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// NewOutboxEvent creates an outbox message that publishes a transaction event.
+// The relay adds transaction_id and event_id to the data on delivery.
+func NewOutboxEvent(eventType string, data map[string]interface{}) *OutboxMessage {
+	return &OutboxMessage{
+		Kind:      OutboxKindEvent,
+		EventType: &eventType,
+		Payload:   data,
+	}
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// PaymentRequestStatus represents the state of a payment request.
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestStatusPending   PaymentRequestStatus = "pending"   // Waiting for the payer
+	PaymentRequestStatusAccepted  PaymentRequestStatus = "accepted"  // Paid by a transfer
+	PaymentRequestStatusDeclined  PaymentRequestStatus = "declined"  // Declined by the payer
+	PaymentRequestStatusCancelled PaymentRequestStatus = "cancelled" // Withdrawn by the requester
+	PaymentRequestStatusExpired   PaymentRequestStatus = "expired"   // Not answered in time
+)
+
+// PaymentRequestDirection selects payment requests by the user's side.
+type PaymentRequestDirection string
+
+const (
+	PaymentRequestIncoming PaymentRequestDirection = "incoming" // Requests the user is asked to pay
+	PaymentRequestOutgoing PaymentRequestDirection = "outgoing" // Requests the user sent
+)
+
+const (
+	// DefaultPaymentRequestExpiry is how long a payment request stays open by default.
+	DefaultPaymentRequestExpiry = 7 * 24 * time.Hour
+	// MaxPaymentRequestExpiry is the longest a payment request can stay open.
+	MaxPaymentRequestExpiry = 30 * 24 * time.Hour
+)
+
+// PaymentRequest is a request from one user (the requester) for another user
+// (the payer) to send them money. Accepting it creates a transfer from a
+// wallet of the payer to the requester's wallet.
+type PaymentRequest struct {
+	ID                string               `json:"id" db:"id"`
+	RequesterUserID   string               `json:"requester_user_id" db:"requester_user_id"`
+	RequesterWalletID string               `json:"requester_wallet_id" db:"requester_wallet_id"` // Receives the money
+	RequesterName     string               `json:"requester_name" db:"requester_name"`
+	PayerUserID       string               `json:"payer_user_id" db:"payer_user_id"`
+	PayerPhone        string               `json:"payer_phone" db:"payer_phone"`
+	PayerName         string               `json:"payer_name" db:"payer_name"`
+	Amount            int64                `json:"amount" db:"amount"` // In smallest unit (paise)
+	Currency          models.Currency      `json:"currency" db:"currency"`
+	Description       string               `json:"description" db:"description"`
+	Status            PaymentRequestStatus `json:"status" db:"status"`
+	TransactionID     *string              `json:"transaction_id,omitempty" db:"transaction_id"` // Transfer that paid it
+	DeclineReason     *string              `json:"decline_reason,omitempty" db:"decline_reason"`
+	ExpiresAt         models.Timestamp     `json:"expires_at" db:"expires_at"`
+	RespondedAt       *models.Timestamp    `json:"responded_at,omitempty" db:"responded_at"`
+	CreatedAt         models.Timestamp     `json:"created_at" db:"created_at"`
+	UpdatedAt         models.Timestamp     `json:"updated_at" db:"updated_at"`
+}
+
+// IsPending returns true if the payment request is waiting for the payer.
+func (p *PaymentRequest) IsPending() bool {
+	return p.Status == PaymentRequestStatusPending
+}
+
+// IsExpiredAt returns true if a pending payment request can no longer be paid at t.
+func (p *PaymentRequest) IsExpiredAt(t time.Time) bool {
+	return !t.Before(p.ExpiresAt.Time)
+}
+
+// CreatePaymentRequestRequest represents a request to ask another user for money.
+// The payer is identified by phone number or by one of the requester's beneficiaries.
+type CreatePaymentRequestRequest struct {
+	PayerPhone     string          `json:"payer_phone,omitempty"`
+	BeneficiaryID  string          `json:"beneficiary_id,omitempty"`
+	WalletID       string          `json:"wallet_id" validate:"required,uuid"` // Requester's wallet to receive the money
+	Amount         int64           `json:"amount" validate:"required,gt=0"`
+	Currency       models.Currency `json:"currency" validate:"required,len=3"`
+	Description    string          `json:"description" validate:"required,min=3,max=500"`
+	ExpiresInHours *int            `json:"expires_in_hours,omitempty"` // Default 7 days, at most 30 days
+}
+
+// AcceptPaymentRequestRequest represents the payer accepting a payment request.
+type AcceptPaymentRequestRequest struct {
+	SourceWalletID    string `json:"source_wallet_id" validate:"required,uuid"`
+	VerificationToken string `json:"verification_token,omitempty"` // Required above the high-value threshold
+}
+
+// DeclinePaymentRequestRequest represents the payer declining a payment request.
+type DeclinePaymentRequestRequest struct {
+	Reason string `json:"reason,omitempty" validate:"max=500"`
+}
+
+// PaymentRequestFilter represents filters for listing payment requests.
+type PaymentRequestFilter struct {
+	UserID    string
+	Direction PaymentRequestDirection
+	Status    *PaymentRequestStatus
+	Limit     int
+	Offset    int
+}
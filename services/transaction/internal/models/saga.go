@@ -0,0 +1,53 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// SagaState represents where a transaction saga is in its lifecycle.
+type SagaState string
+
+const (
+	SagaStateRunning      SagaState = "running"       // Executing steps forward
+	SagaStateCompensating SagaState = "compensating"  // Undoing completed steps after a failure
+	SagaStateCompleted    SagaState = "completed"     // All steps succeeded
+	SagaStateCompensated  SagaState = "compensated"   // Failed and fully undone
+	SagaStateManualReview SagaState = "manual_review" // Stuck; needs an operator
+)
+
+// SagaStep identifies one step of a transaction saga.
+type SagaStep string
+
+const (
+	SagaStepRisk       SagaStep = "risk"        // Risk evaluation
+	SagaStepReserve    SagaStep = "reserve"     // Hold funds in the source wallet
+	SagaStepWalletMove SagaStep = "wallet_move" // Move balances between wallets
+	SagaStepLedgerPost SagaStep = "ledger_post" // Record the journal entry
+	SagaStepComplete   SagaStep = "complete"    // Mark the transaction completed
+)
+
+// TransactionSaga is the persisted progress of a transaction through its saga steps.
+type TransactionSaga struct {
+	TransactionID  string           `json:"transaction_id" db:"transaction_id"`
+	State          SagaState        `json:"state" db:"state"`
+	CurrentStep    SagaStep         `json:"current_step" db:"current_step"`
+	CompletedSteps []SagaStep       `json:"completed_steps" db:"completed_steps"`
+	Attempts       int              `json:"attempts" db:"attempts"` // Failed attempts of the current step
+	LastError      *string          `json:"last_error,omitempty" db:"last_error"`
+	FailureReason  *string          `json:"failure_reason,omitempty" db:"failure_reason"` // Recorded on the transaction once compensated
+	NextAttemptAt  models.Timestamp `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt      models.Timestamp `json:"created_at" db:"created_at"`
+	UpdatedAt      models.Timestamp `json:"updated_at" db:"updated_at"`
+}
+
+// HasCompleted returns true if the step has completed and not been compensated.
+func (s *TransactionSaga) HasCompleted(step SagaStep) bool {
+	return slices.Contains(s.CompletedSteps, step)
+}
+
+// IsActive returns true if the saga still has work to do.
+func (s *TransactionSaga) IsActive() bool {
+	return s.State == SagaStateRunning || s.State == SagaStateCompensating
+}
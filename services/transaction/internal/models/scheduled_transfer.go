@@ -0,0 +1,177 @@
+package models
+
+import (
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/models"
+)
+
+// ScheduleFrequency represents how often a scheduled transfer runs.
+type ScheduleFrequency string
+
+const (
+	ScheduleFrequencyOnce    ScheduleFrequency = "once"
+	ScheduleFrequencyDaily   ScheduleFrequency = "daily"
+	ScheduleFrequencyWeekly  ScheduleFrequency = "weekly"
+	ScheduleFrequencyMonthly ScheduleFrequency = "monthly"
+)
+
+// IsValid reports whether the frequency is supported.
+func (f ScheduleFrequency) IsValid() bool {
+	switch f {
+	case ScheduleFrequencyOnce, ScheduleFrequencyDaily, ScheduleFrequencyWeekly, ScheduleFrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+// ScheduleStatus represents the lifecycle state of a scheduled transfer.
+type ScheduleStatus string
+
+const (
+	ScheduleStatusActive    ScheduleStatus = "active"    // Runs when due
+	ScheduleStatusPaused    ScheduleStatus = "paused"    // Not run until resumed
+	ScheduleStatusCompleted ScheduleStatus = "completed" // All occurrences consumed
+	ScheduleStatusCancelled ScheduleStatus = "cancelled" // Stopped by the user
+)
+
+// ExecutionStatus represents the outcome of one occurrence of a scheduled transfer.
+type ExecutionStatus string
+
+const (
+	ExecutionStatusPending  ExecutionStatus = "pending"  // Transfer being created
+	ExecutionStatusExecuted ExecutionStatus = "executed" // Transfer created (see its transaction status)
+	ExecutionStatusFailed   ExecutionStatus = "failed"   // Transfer rejected or could not be created
+	ExecutionStatusSkipped  ExecutionStatus = "skipped"  // Occurrence skipped by the user
+)
+
+// MaxConsecutiveScheduleFailures is the number of failed occurrences in a row
+// after which a schedule is paused.
+const MaxConsecutiveScheduleFailures = 3
+
+// ScheduledTransfer is a one-off future transfer or a recurring transfer.
+// Occurrence n (from 0) is due at StartAt plus n periods of the frequency.
+type ScheduledTransfer struct {
+	ID                  string            `json:"id" db:"id"`
+	UserID              string            `json:"user_id" db:"user_id"`
+	SourceWalletID      string            `json:"source_wallet_id" db:"source_wallet_id"`
+	DestinationWalletID string            `json:"destination_wallet_id" db:"destination_wallet_id"`
+	Amount              int64             `json:"amount" db:"amount"` // In smallest unit (paise)
+	Currency            models.Currency   `json:"currency" db:"currency"`
+	Description         string            `json:"description" db:"description"`
+	Frequency           ScheduleFrequency `json:"frequency" db:"frequency"`
+	StartAt             models.Timestamp  `json:"start_at" db:"start_at"`
+	EndAt               *models.Timestamp `json:"end_at,omitempty" db:"end_at"`                   // No occurrence after this time
+	MaxOccurrences      *int              `json:"max_occurrences,omitempty" db:"max_occurrences"` // Including skipped occurrences
+	Occurrences         int               `json:"occurrences" db:"occurrences"`                   // Occurrences consumed so far
+	NextRunAt           *models.Timestamp `json:"next_run_at,omitempty" db:"next_run_at"`         // Nil once completed
+	Status              ScheduleStatus    `json:"status" db:"status"`
+	ConsecutiveFailures int               `json:"consecutive_failures" db:"consecutive_failures"`
+	LastRunAt           *models.Timestamp `json:"last_run_at,omitempty" db:"last_run_at"`
+	CreatedAt           models.Timestamp  `json:"created_at" db:"created_at"`
+	UpdatedAt           models.Timestamp  `json:"updated_at" db:"updated_at"`
+}
+
+// OccurrenceAt returns when occurrence n (from 0) is due. Monthly schedules
+// keep the day of the month of StartAt, clamped to the end of shorter months.
+func (s *ScheduledTransfer) OccurrenceAt(n int) time.Time {
+	start := s.StartAt.Time
+	switch s.Frequency {
+	case ScheduleFrequencyDaily:
+		return start.AddDate(0, 0, n)
+	case ScheduleFrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case ScheduleFrequencyMonthly:
+		return addMonthsClamped(start, n)
+	default:
+		return start
+	}
+}
+
+// Advance consumes the current occurrence and moves NextRunAt to the next one,
+// completing the schedule when no occurrences remain.
+func (s *ScheduledTransfer) Advance() {
+	s.Occurrences++
+
+	if !s.hasOccurrence(s.Occurrences) {
+		s.NextRunAt = nil
+		if s.Status != ScheduleStatusCancelled {
+			s.Status = ScheduleStatusCompleted
+		}
+		return
+	}
+
+	next := models.NewTimestamp(s.OccurrenceAt(s.Occurrences))
+	s.NextRunAt = &next
+}
+
+// SkipUntil consumes the occurrences due before t without running them.
+func (s *ScheduledTransfer) SkipUntil(t time.Time) {
+	for s.NextRunAt != nil && s.NextRunAt.Time.Before(t) {
+		s.Advance()
+	}
+}
+
+// hasOccurrence reports whether occurrence n is within the schedule's bounds.
+func (s *ScheduledTransfer) hasOccurrence(n int) bool {
+	if s.Frequency == ScheduleFrequencyOnce {
+		return n == 0
+	}
+	if s.MaxOccurrences != nil && n >= *s.MaxOccurrences {
+		return false
+	}
+	if s.EndAt != nil && s.OccurrenceAt(n).After(s.EndAt.Time) {
+		return false
+	}
+	return true
+}
+
+// CanBeModified reports whether the schedule can still be skipped, paused or cancelled.
+func (s *ScheduledTransfer) CanBeModified() bool {
+	return s.Status == ScheduleStatusActive || s.Status == ScheduleStatusPaused
+}
+
+func addMonthsClamped(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	target := firstOfMonth.AddDate(0, months, 0)
+	lastDay := target.AddDate(0, 1, -1).Day()
+	return target.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+// ScheduledTransferExecution records one occurrence of a scheduled transfer.
+type ScheduledTransferExecution struct {
+	ID                  string             `json:"id" db:"id"`
+	ScheduledTransferID string             `json:"scheduled_transfer_id" db:"scheduled_transfer_id"`
+	Occurrence          int                `json:"occurrence" db:"occurrence"`
+	ScheduledFor        models.Timestamp   `json:"scheduled_for" db:"scheduled_for"`
+	Status              ExecutionStatus    `json:"status" db:"status"`
+	TransactionID       *string            `json:"transaction_id,omitempty" db:"transaction_id"`
+	TransactionStatus   *TransactionStatus `json:"transaction_status,omitempty" db:"-"` // Current status of the transfer
+	FailureReason       *string            `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedAt           models.Timestamp   `json:"created_at" db:"created_at"`
+	CompletedAt         *models.Timestamp  `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// CreateScheduledTransferRequest represents a request to schedule a transfer.
+// Times are RFC 3339; for recurring transfers, either or both of end_at and
+// max_occurrences bound the schedule, otherwise it runs until cancelled.
+type CreateScheduledTransferRequest struct {
+	SourceWalletID      string            `json:"source_wallet_id" validate:"required,uuid"`
+	DestinationWalletID string            `json:"destination_wallet_id" validate:"required,uuid"`
+	Amount              int64             `json:"amount" validate:"required,gt=0"`
+	Currency            models.Currency   `json:"currency" validate:"required,len=3"`
+	Description         string            `json:"description" validate:"required,min=3,max=500"`
+	Frequency           ScheduleFrequency `json:"frequency" validate:"required"`
+	StartAt             string            `json:"start_at" validate:"required"`
+	EndAt               string            `json:"end_at,omitempty"`
+	MaxOccurrences      *int              `json:"max_occurrences,omitempty"`
+	VerificationToken   string            `json:"verification_token,omitempty"` // Required above the high-value threshold
+}
+
+// ScheduledTransferFilter represents filters for listing scheduled transfers.
+type ScheduledTransferFilter struct {
+	UserID string
+	Status *ScheduleStatus
+	Limit  int
+	Offset int
+}
@@ -104,6 +104,7 @@ type CreateTransferRequest struct {
 	Description         string          `json:"description" validate:"required,min=3,max=500"`
 	Reference           string          `json:"reference,omitempty" validate:"omitempty,max=100"`
 	MetadataRaw         json.RawMessage `json:"metadata,omitempty"`
+	VerificationToken   string          `json:"verification_token,omitempty"` // Required above the high-value threshold
 }
 
 // GetMetadata parses and returns the metadata map.
@@ -241,3 +242,18 @@ type CategorySummaryResponse struct {
 	} `json:"period"`
 	TotalSpent int64 `json:"total_spent"`
 }
+
+// WalletTotals represents the net effect of settled transactions on a wallet.
+// Completed and reversed transactions both moved money; a reversal is its own transaction.
+type WalletTotals struct {
+	WalletID         string `json:"wallet_id"`
+	Credits          int64  `json:"credits"`           // Sum of amounts received
+	Debits           int64  `json:"debits"`            // Sum of amounts sent
+	Net              int64  `json:"net"`               // Credits minus debits
+	TransactionCount int    `json:"transaction_count"` // Settled transactions touching the wallet
+}
+
+// WalletTotalsRequest represents a batch request for wallet transaction totals.
+type WalletTotalsRequest struct {
+	WalletIDs []string `json:"wallet_ids" validate:"required"`
+}
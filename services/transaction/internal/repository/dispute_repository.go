@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/database"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// DisputeRepository handles database operations for disputes.
+type DisputeRepository struct {
+	db *sql.DB
+}
+
+// NewDisputeRepository creates a new dispute repository.
+func NewDisputeRepository(db *sql.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+const disputeColumns = `
+	id, transaction_id, customer_user_id, customer_wallet_id, counterparty_user_id,
+	counterparty_wallet_id, amount, currency, description, reason, evidence, status,
+	assigned_to, resolution_notes, resolved_by, credit_transaction_id, reversal_transaction_id,
+	acknowledge_by, resolve_by, sla_breached_at, investigating_at, resolved_at, settled_at,
+	settlement_attempts, created_at, updated_at
+`
+
+func scanDispute(row rowScanner) (*models.Dispute, error) {
+	d := &models.Dispute{}
+	err := row.Scan(
+		&d.ID,
+		&d.TransactionID,
+		&d.CustomerUserID,
+		&d.CustomerWalletID,
+		&d.CounterpartyUserID,
+		&d.CounterpartyWalletID,
+		&d.Amount,
+		&d.Currency,
+		&d.Description,
+		&d.Reason,
+		&d.Evidence,
+		&d.Status,
+		&d.AssignedTo,
+		&d.ResolutionNotes,
+		&d.ResolvedBy,
+		&d.CreditTransactionID,
+		&d.ReversalTransactionID,
+		&d.AcknowledgeBy,
+		&d.ResolveBy,
+		&d.SLABreachedAt,
+		&d.InvestigatingAt,
+		&d.ResolvedAt,
+		&d.SettledAt,
+		&d.SettlementAttempts,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func scanDisputes(rows *sql.Rows) ([]*models.Dispute, *errors.Error) {
+	defer func() { _ = rows.Close() }()
+
+	disputes := make([]*models.Dispute, 0)
+	for rows.Next() {
+		d, err := scanDispute(rows)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan dispute")
+		}
+		disputes = append(disputes, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating disputes")
+	}
+
+	return disputes, nil
+}
+
+// Create records a new dispute. A transaction can only be disputed once.
+func (r *DisputeRepository) Create(ctx context.Context, d *models.Dispute) *errors.Error {
+	query := `
+		INSERT INTO disputes (
+			transaction_id, customer_user_id, customer_wallet_id, counterparty_user_id,
+			counterparty_wallet_id, amount, currency, description, reason, evidence,
+			status, acknowledge_by, resolve_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING ` + disputeColumns
+
+	created, err := scanDispute(r.db.QueryRowContext(ctx, query,
+		d.TransactionID,
+		d.CustomerUserID,
+		d.CustomerWalletID,
+		d.CounterpartyUserID,
+		d.CounterpartyWalletID,
+		d.Amount,
+		d.Currency,
+		d.Description,
+		d.Reason,
+		d.Evidence,
+		d.Status,
+		d.AcknowledgeBy,
+		d.ResolveBy,
+	))
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return errors.Conflict("transaction has already been disputed")
+		}
+		return errors.DatabaseWrap(err, "failed to create dispute")
+	}
+
+	*d = *created
+	return nil
+}
+
+// GetByID retrieves a dispute by ID.
+func (r *DisputeRepository) GetByID(ctx context.Context, id string) (*models.Dispute, *errors.Error) {
+	query := `SELECT ` + disputeColumns + ` FROM disputes WHERE id = $1`
+
+	d, err := scanDispute(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundWithID("dispute", id)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get dispute")
+	}
+
+	return d, nil
+}
+
+// List retrieves disputes matching the filter with the total count. Active
+// disputes are listed by resolution deadline, soonest first, and resolved ones
+// newest first.
+func (r *DisputeRepository) List(ctx context.Context, filter *models.DisputeFilter) ([]*models.Dispute, int, *errors.Error) {
+	where := `WHERE TRUE`
+	var args []any
+	if filter.UserID != "" {
+		args = append(args, filter.UserID)
+		where += fmt.Sprintf(" AND (customer_user_id = $%d OR counterparty_user_id = $%d)", len(args), len(args))
+	}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.Overdue {
+		where += " AND sla_breached_at IS NOT NULL"
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM disputes `+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count disputes")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT %s FROM disputes %s
+		ORDER BY status IN ('open', 'investigating') DESC,
+		         CASE WHEN status IN ('open', 'investigating') THEN resolve_by END,
+		         created_at DESC
+		LIMIT $%d OFFSET $%d`,
+		disputeColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list disputes")
+	}
+
+	disputes, scanErr := scanDisputes(rows)
+	if scanErr != nil {
+		return nil, 0, scanErr
+	}
+
+	return disputes, total, nil
+}
+
+// StartInvestigation assigns an open dispute to the investigating admin.
+func (r *DisputeRepository) StartInvestigation(ctx context.Context, id, adminID string) (*models.Dispute, *errors.Error) {
+	query := `
+		UPDATE disputes
+		SET status = 'investigating', assigned_to = $2, investigating_at = NOW()
+		WHERE id = $1 AND status = 'open'
+		RETURNING ` + disputeColumns
+
+	d, err := scanDispute(r.db.QueryRowContext(ctx, query, id, adminID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Conflict("dispute is no longer open")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to start dispute investigation")
+	}
+
+	return d, nil
+}
+
+// Resolve moves a dispute under investigation to its final status. A dispute
+// resolved in the customer's favour is locked for the lease so that its
+// settlement is run by the caller.
+func (r *DisputeRepository) Resolve(ctx context.Context, id string, status models.DisputeStatus, adminID, notes string, lease time.Duration) (*models.Dispute, *errors.Error) {
+	query := `
+		UPDATE disputes
+		SET status = $2, resolved_by = $3, resolution_notes = $4, resolved_at = NOW(),
+		    locked_until = CASE WHEN $2 = 'resolved_in_favour' THEN NOW() + make_interval(secs => $5) END
+		WHERE id = $1 AND status = 'investigating'
+		RETURNING ` + disputeColumns
+
+	d, err := scanDispute(r.db.QueryRowContext(ctx, query, id, status, adminID, notes, lease.Seconds()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Conflict("dispute is not under investigation")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to resolve dispute")
+	}
+
+	return d, nil
+}
+
+// ClaimUnsettled claims up to limit disputes resolved in the customer's favour
+// whose settlement is due, oldest resolution first, locking them for the lease.
+func (r *DisputeRepository) ClaimUnsettled(ctx context.Context, limit int, lease time.Duration) ([]*models.Dispute, *errors.Error) {
+	query := `
+		WITH claimed AS (
+			UPDATE disputes
+			SET locked_until = NOW() + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id
+				FROM disputes
+				WHERE status = 'resolved_in_favour'
+				  AND settled_at IS NULL
+				  AND (locked_until IS NULL OR locked_until <= NOW())
+				ORDER BY resolved_at, id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + disputeColumns + `
+		)
+		SELECT * FROM claimed
+		ORDER BY resolved_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to claim unsettled disputes")
+	}
+
+	return scanDisputes(rows)
+}
+
+// SaveSettlement records the progress of a claimed dispute's settlement,
+// including a move to settlement_failed. An unsettled dispute stays locked for
+// retryIn before it is claimed again.
+func (r *DisputeRepository) SaveSettlement(ctx context.Context, d *models.Dispute, retryIn time.Duration) *errors.Error {
+	query := `
+		UPDATE disputes
+		SET credit_transaction_id = $2,
+		    reversal_transaction_id = $3,
+		    settled_at = $4,
+		    settlement_attempts = $6,
+		    status = $7,
+		    locked_until = CASE WHEN $4::timestamptz IS NULL THEN NOW() + make_interval(secs => $5) END
+		WHERE id = $1 AND status = 'resolved_in_favour'
+	`
+
+	_, err := r.db.ExecContext(ctx, query, d.ID, d.CreditTransactionID, d.ReversalTransactionID, d.SettledAt, retryIn.Seconds(),
+		d.SettlementAttempts, d.Status)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to save dispute settlement")
+	}
+	return nil
+}
+
+// FlagOverdue marks up to limit active disputes that missed a deadline as
+// having breached their SLA and returns them. Open disputes must be picked up
+// by acknowledge_by; all active disputes must be resolved by resolve_by.
+func (r *DisputeRepository) FlagOverdue(ctx context.Context, limit int) ([]*models.Dispute, *errors.Error) {
+	query := `
+		UPDATE disputes
+		SET sla_breached_at = NOW()
+		WHERE id IN (
+			SELECT id
+			FROM disputes
+			WHERE sla_breached_at IS NULL
+			  AND ((status = 'open' AND acknowledge_by <= NOW())
+			    OR (status IN ('open', 'investigating') AND resolve_by <= NOW()))
+			ORDER BY resolve_by, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + disputeColumns
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to flag overdue disputes")
+	}
+
+	return scanDisputes(rows)
+}
+
+// FindTransactionByReference returns the ID, status and failure reason of the
+// latest transaction with the given reference, or a not found error.
+func (r *DisputeRepository) FindTransactionByReference(ctx context.Context, reference string) (*models.Transaction, *errors.Error) {
+	tx := &models.Transaction{Reference: &reference}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, status, failure_reason
+		FROM transactions
+		WHERE reference = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, reference).Scan(&tx.ID, &tx.Status, &tx.FailureReason)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound("transaction with reference " + reference)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to find transaction by reference")
+	}
+
+	return tx, nil
+}
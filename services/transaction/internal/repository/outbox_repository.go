@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// OutboxRepository handles database operations for the transaction outbox.
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new outbox repository.
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// insertOutboxMessage records an outbox message for a transaction inside an open database transaction.
+func insertOutboxMessage(ctx context.Context, tx *sql.Tx, transactionID string, msg *models.OutboxMessage) *errors.Error {
+	payload := msg.Payload
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Internal("failed to marshal outbox payload")
+	}
+
+	query := `
+		INSERT INTO transaction_outbox (transaction_id, kind, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, attempts, next_attempt_at, created_at
+	`
+
+	err = tx.QueryRowContext(ctx, query, transactionID, msg.Kind, msg.EventType, payloadJSON).Scan(
+		&msg.ID,
+		&msg.Status,
+		&msg.Attempts,
+		&msg.NextAttemptAt,
+		&msg.CreatedAt,
+	)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to create outbox message")
+	}
+
+	msg.TransactionID = transactionID
+	return nil
+}
+
+// ClaimDue claims up to limit pending messages whose next attempt is due, oldest first
+// so the events of a transaction go out in the order they were recorded.
+// Claiming counts as an attempt and pushes next_attempt_at out by the lease, so
+// concurrent relays skip the claimed rows and a relay that dies mid-delivery
+// only delays the messages until the lease expires.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxMessage, *errors.Error) {
+	query := `
+		WITH claimed AS (
+			UPDATE transaction_outbox
+			SET attempts = attempts + 1,
+			    next_attempt_at = NOW() + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id
+				FROM transaction_outbox
+				WHERE status = 'pending' AND next_attempt_at <= NOW()
+				ORDER BY created_at, id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, transaction_id, kind, event_type, payload, status,
+			          attempts, next_attempt_at, last_error, created_at, delivered_at
+		)
+		SELECT * FROM claimed
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to claim outbox messages")
+	}
+	defer func() { _ = rows.Close() }()
+
+	messages := make([]*models.OutboxMessage, 0)
+	for rows.Next() {
+		msg := &models.OutboxMessage{}
+		var payloadJSON []byte
+
+		if err := rows.Scan(
+			&msg.ID,
+			&msg.TransactionID,
+			&msg.Kind,
+			&msg.EventType,
+			&payloadJSON,
+			&msg.Status,
+			&msg.Attempts,
+			&msg.NextAttemptAt,
+			&msg.LastError,
+			&msg.CreatedAt,
+			&msg.DeliveredAt,
+		); err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan outbox message")
+		}
+
+		if len(payloadJSON) > 0 {
+			if err := json.Unmarshal(payloadJSON, &msg.Payload); err != nil {
+				return nil, errors.Internal("failed to parse outbox payload")
+			}
+		}
+
+		messages = append(messages, msg)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating outbox messages")
+	}
+
+	return messages, nil
+}
+
+// MarkDelivered marks a message as delivered.
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id string) *errors.Error {
+	query := `
+		UPDATE transaction_outbox
+		SET status = 'delivered', delivered_at = NOW(), last_error = NULL
+		WHERE id = $1
+	`
+
+	return r.update(ctx, id, query, "failed to mark outbox message delivered")
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id, lastError string, nextAttemptAt time.Time) *errors.Error {
+	query := `
+		UPDATE transaction_outbox
+		SET last_error = $2, next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	return r.update(ctx, id, query, "failed to mark outbox message failed", lastError, nextAttemptAt)
+}
+
+// MarkDead stops delivering a message after its final failed attempt.
+func (r *OutboxRepository) MarkDead(ctx context.Context, id, lastError string) *errors.Error {
+	query := `
+		UPDATE transaction_outbox
+		SET status = 'dead', last_error = $2
+		WHERE id = $1
+	`
+
+	return r.update(ctx, id, query, "failed to mark outbox message dead", lastError)
+}
+
+// update runs an UPDATE whose first parameter is the message ID.
+func (r *OutboxRepository) update(ctx context.Context, id, query, failMsg string, args ...any) *errors.Error {
+	result, err := r.db.ExecContext(ctx, query, append([]any{id}, args...)...)
+	if err != nil {
+		return errors.DatabaseWrap(err, failMsg)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.DatabaseWrap(err, failMsg)
+	}
+	if rows == 0 {
+		return errors.NotFoundWithID("outbox message", id)
+	}
+
+	return nil
+}
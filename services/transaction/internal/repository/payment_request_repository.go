@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// PaymentRequestRepository handles database operations for payment requests.
+type PaymentRequestRepository struct {
+	db *sql.DB
+}
+
+// NewPaymentRequestRepository creates a new payment request repository.
+func NewPaymentRequestRepository(db *sql.DB) *PaymentRequestRepository {
+	return &PaymentRequestRepository{db: db}
+}
+
+const paymentRequestColumns = `
+	id, requester_user_id, requester_wallet_id, requester_name, payer_user_id,
+	payer_phone, payer_name, amount, currency, description, status,
+	transaction_id, decline_reason, expires_at, responded_at, created_at, updated_at
+`
+
+func scanPaymentRequest(row rowScanner) (*models.PaymentRequest, error) {
+	p := &models.PaymentRequest{}
+	err := row.Scan(
+		&p.ID,
+		&p.RequesterUserID,
+		&p.RequesterWalletID,
+		&p.RequesterName,
+		&p.PayerUserID,
+		&p.PayerPhone,
+		&p.PayerName,
+		&p.Amount,
+		&p.Currency,
+		&p.Description,
+		&p.Status,
+		&p.TransactionID,
+		&p.DeclineReason,
+		&p.ExpiresAt,
+		&p.RespondedAt,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func scanPaymentRequests(rows *sql.Rows) ([]*models.PaymentRequest, *errors.Error) {
+	defer func() { _ = rows.Close() }()
+
+	requests := make([]*models.PaymentRequest, 0)
+	for rows.Next() {
+		p, err := scanPaymentRequest(rows)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan payment request")
+		}
+		requests = append(requests, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating payment requests")
+	}
+
+	return requests, nil
+}
+
+// Create records a new payment request.
+func (r *PaymentRequestRepository) Create(ctx context.Context, p *models.PaymentRequest) *errors.Error {
+	query := `
+		INSERT INTO payment_requests (
+			requester_user_id, requester_wallet_id, requester_name, payer_user_id,
+			payer_phone, payer_name, amount, currency, description, status, expires_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING ` + paymentRequestColumns
+
+	created, err := scanPaymentRequest(r.db.QueryRowContext(ctx, query,
+		p.RequesterUserID,
+		p.RequesterWalletID,
+		p.RequesterName,
+		p.PayerUserID,
+		p.PayerPhone,
+		p.PayerName,
+		p.Amount,
+		p.Currency,
+		p.Description,
+		p.Status,
+		p.ExpiresAt,
+	))
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to create payment request")
+	}
+
+	*p = *created
+	return nil
+}
+
+// GetByID retrieves a payment request by ID.
+func (r *PaymentRequestRepository) GetByID(ctx context.Context, id string) (*models.PaymentRequest, *errors.Error) {
+	query := `SELECT ` + paymentRequestColumns + ` FROM payment_requests WHERE id = $1`
+
+	p, err := scanPaymentRequest(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundWithID("payment request", id)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get payment request")
+	}
+
+	return p, nil
+}
+
+// List retrieves the payment requests a user sent or received, newest first,
+// with the total count.
+func (r *PaymentRequestRepository) List(ctx context.Context, filter *models.PaymentRequestFilter) ([]*models.PaymentRequest, int, *errors.Error) {
+	where := `WHERE payer_user_id = $1`
+	if filter.Direction == models.PaymentRequestOutgoing {
+		where = `WHERE requester_user_id = $1`
+	}
+	args := []any{filter.UserID}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM payment_requests `+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count payment requests")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM payment_requests %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		paymentRequestColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list payment requests")
+	}
+
+	requests, scanErr := scanPaymentRequests(rows)
+	if scanErr != nil {
+		return nil, 0, scanErr
+	}
+
+	return requests, total, nil
+}
+
+// Claim locks a pending, unexpired payment request for the lease while it is
+// being paid. It returns a conflict if the request is not pending, has expired
+// or is already claimed.
+func (r *PaymentRequestRepository) Claim(ctx context.Context, id string, lease time.Duration) (*models.PaymentRequest, *errors.Error) {
+	query := `
+		UPDATE payment_requests
+		SET locked_until = NOW() + make_interval(secs => $2)
+		WHERE id = $1
+		  AND status = 'pending'
+		  AND expires_at > NOW()
+		  AND (locked_until IS NULL OR locked_until <= NOW())
+		RETURNING ` + paymentRequestColumns
+
+	p, err := scanPaymentRequest(r.db.QueryRowContext(ctx, query, id, lease.Seconds()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Conflict("payment request is no longer pending or is being processed")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to claim payment request")
+	}
+
+	return p, nil
+}
+
+// ClaimExpired claims up to limit pending payment requests past their expiry,
+// earliest first, locking them for the lease.
+func (r *PaymentRequestRepository) ClaimExpired(ctx context.Context, limit int, lease time.Duration) ([]*models.PaymentRequest, *errors.Error) {
+	query := `
+		WITH claimed AS (
+			UPDATE payment_requests
+			SET locked_until = NOW() + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id
+				FROM payment_requests
+				WHERE status = 'pending'
+				  AND expires_at <= NOW()
+				  AND (locked_until IS NULL OR locked_until <= NOW())
+				ORDER BY expires_at, id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + paymentRequestColumns + `
+		)
+		SELECT * FROM claimed
+		ORDER BY expires_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to claim expired payment requests")
+	}
+
+	return scanPaymentRequests(rows)
+}
+
+// Release clears the claim on a payment request, leaving it pending.
+func (r *PaymentRequestRepository) Release(ctx context.Context, id string) *errors.Error {
+	_, err := r.db.ExecContext(ctx, `UPDATE payment_requests SET locked_until = NULL WHERE id = $1`, id)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to release payment request")
+	}
+	return nil
+}
+
+// Resolve moves a pending payment request claimed by the caller to its final
+// status: accepted with the transfer that paid it, or expired.
+func (r *PaymentRequestRepository) Resolve(ctx context.Context, id string, status models.PaymentRequestStatus, transactionID *string) (*models.PaymentRequest, *errors.Error) {
+	query := `
+		UPDATE payment_requests
+		SET status = $2,
+		    transaction_id = $3,
+		    responded_at = CASE WHEN $2 = 'accepted' THEN NOW() END,
+		    locked_until = NULL
+		WHERE id = $1 AND status = 'pending'
+		RETURNING ` + paymentRequestColumns
+
+	p, err := scanPaymentRequest(r.db.QueryRowContext(ctx, query, id, status, transactionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Conflict("payment request is no longer pending")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to resolve payment request")
+	}
+
+	return p, nil
+}
+
+// Close declines or cancels a pending payment request that is not being paid.
+func (r *PaymentRequestRepository) Close(ctx context.Context, id string, status models.PaymentRequestStatus, reason *string) (*models.PaymentRequest, *errors.Error) {
+	query := `
+		UPDATE payment_requests
+		SET status = $2, decline_reason = $3, responded_at = NOW()
+		WHERE id = $1
+		  AND status = 'pending'
+		  AND (locked_until IS NULL OR locked_until <= NOW())
+		RETURNING ` + paymentRequestColumns
+
+	p, err := scanPaymentRequest(r.db.QueryRowContext(ctx, query, id, status, reason))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Conflict("payment request is no longer pending or is being processed")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to close payment request")
+	}
+
+	return p, nil
+}
+
+// FindTransferByReference returns the ID, status and failure reason of the
+// latest transaction with the given reference, or a not found error.
+func (r *PaymentRequestRepository) FindTransferByReference(ctx context.Context, reference string) (*models.Transaction, *errors.Error) {
+	tx := &models.Transaction{Reference: &reference}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, status, failure_reason
+		FROM transactions
+		WHERE reference = $1 AND type = 'transfer'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, reference).Scan(&tx.ID, &tx.Status, &tx.FailureReason)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound("transaction with reference " + reference)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to find transaction by reference")
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// SagaRepository handles database operations for transaction sagas.
+type SagaRepository struct {
+	db *sql.DB
+}
+
+// NewSagaRepository creates a new saga repository.
+func NewSagaRepository(db *sql.DB) *SagaRepository {
+	return &SagaRepository{db: db}
+}
+
+const sagaColumns = `
+	transaction_id, state, current_step, completed_steps, attempts,
+	last_error, failure_reason, next_attempt_at, created_at, updated_at
+`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSaga(row rowScanner) (*models.TransactionSaga, error) {
+	saga := &models.TransactionSaga{}
+	var completed pq.StringArray
+
+	err := row.Scan(
+		&saga.TransactionID,
+		&saga.State,
+		&saga.CurrentStep,
+		&completed,
+		&saga.Attempts,
+		&saga.LastError,
+		&saga.FailureReason,
+		&saga.NextAttemptAt,
+		&saga.CreatedAt,
+		&saga.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	saga.CompletedSteps = make([]models.SagaStep, len(completed))
+	for i, step := range completed {
+		saga.CompletedSteps[i] = models.SagaStep(step)
+	}
+
+	return saga, nil
+}
+
+func stepsArray(steps []models.SagaStep) pq.StringArray {
+	arr := make(pq.StringArray, len(steps))
+	for i, step := range steps {
+		arr[i] = string(step)
+	}
+	return arr
+}
+
+// insertSaga records a new saga. ON CONFLICT leaves an existing saga for the
+// transaction untouched and returns no row.
+func insertSaga(ctx context.Context, q querier, saga *models.TransactionSaga) (bool, error) {
+	query := `
+		INSERT INTO transaction_sagas (transaction_id, state, current_step, completed_steps, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (transaction_id) DO NOTHING
+		RETURNING ` + sagaColumns
+
+	inserted, err := scanSaga(q.QueryRowContext(ctx, query,
+		saga.TransactionID,
+		saga.State,
+		saga.CurrentStep,
+		stepsArray(saga.CompletedSteps),
+		saga.NextAttemptAt,
+	))
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	*saga = *inserted
+	return true, nil
+}
+
+// Start records a saga for an existing transaction. If the transaction already
+// has a saga, that saga is returned instead, so starting is safe to repeat.
+func (r *SagaRepository) Start(ctx context.Context, saga *models.TransactionSaga) (*models.TransactionSaga, *errors.Error) {
+	inserted, err := insertSaga(ctx, r.db, saga)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to start saga")
+	}
+	if inserted {
+		return saga, nil
+	}
+
+	return r.Get(ctx, saga.TransactionID)
+}
+
+// Get retrieves the saga of a transaction.
+func (r *SagaRepository) Get(ctx context.Context, transactionID string) (*models.TransactionSaga, *errors.Error) {
+	query := `SELECT ` + sagaColumns + ` FROM transaction_sagas WHERE transaction_id = $1`
+
+	saga, err := scanSaga(r.db.QueryRowContext(ctx, query, transactionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundWithID("saga", transactionID)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get saga")
+	}
+
+	return saga, nil
+}
+
+// Update persists the progress of a saga.
+func (r *SagaRepository) Update(ctx context.Context, saga *models.TransactionSaga) *errors.Error {
+	query := `
+		UPDATE transaction_sagas
+		SET state = $2, current_step = $3, completed_steps = $4, attempts = $5,
+		    last_error = $6, failure_reason = $7, next_attempt_at = $8
+		WHERE transaction_id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		saga.TransactionID,
+		saga.State,
+		saga.CurrentStep,
+		stepsArray(saga.CompletedSteps),
+		saga.Attempts,
+		saga.LastError,
+		saga.FailureReason,
+		saga.NextAttemptAt,
+	).Scan(&saga.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NotFoundWithID("saga", saga.TransactionID)
+		}
+		return errors.DatabaseWrap(err, "failed to update saga")
+	}
+
+	return nil
+}
+
+// ClaimDue claims up to limit active sagas whose next attempt is due, oldest first.
+// Claiming pushes next_attempt_at out by the lease, so concurrent workers skip
+// the claimed sagas and a worker that dies mid-step only delays them.
+func (r *SagaRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.TransactionSaga, *errors.Error) {
+	query := `
+		WITH claimed AS (
+			UPDATE transaction_sagas
+			SET next_attempt_at = NOW() + make_interval(secs => $2)
+			WHERE transaction_id IN (
+				SELECT transaction_id
+				FROM transaction_sagas
+				WHERE state IN ('running', 'compensating') AND next_attempt_at <= NOW()
+				ORDER BY created_at, transaction_id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + sagaColumns + `
+		)
+		SELECT * FROM claimed
+		ORDER BY created_at, transaction_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to claim sagas")
+	}
+	defer func() { _ = rows.Close() }()
+
+	sagas := make([]*models.TransactionSaga, 0)
+	for rows.Next() {
+		saga, err := scanSaga(rows)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan saga")
+		}
+		sagas = append(sagas, saga)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating sagas")
+	}
+
+	return sagas, nil
+}
@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// ScheduledTransferRepository handles database operations for scheduled transfers.
+type ScheduledTransferRepository struct {
+	db *sql.DB
+}
+
+// NewScheduledTransferRepository creates a new scheduled transfer repository.
+func NewScheduledTransferRepository(db *sql.DB) *ScheduledTransferRepository {
+	return &ScheduledTransferRepository{db: db}
+}
+
+const scheduledTransferColumns = `
+	id, user_id, source_wallet_id, destination_wallet_id, amount, currency,
+	description, frequency, start_at, end_at, max_occurrences, occurrences,
+	next_run_at, status, consecutive_failures, last_run_at, created_at, updated_at
+`
+
+func scanScheduledTransfer(row rowScanner) (*models.ScheduledTransfer, error) {
+	s := &models.ScheduledTransfer{}
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.SourceWalletID,
+		&s.DestinationWalletID,
+		&s.Amount,
+		&s.Currency,
+		&s.Description,
+		&s.Frequency,
+		&s.StartAt,
+		&s.EndAt,
+		&s.MaxOccurrences,
+		&s.Occurrences,
+		&s.NextRunAt,
+		&s.Status,
+		&s.ConsecutiveFailures,
+		&s.LastRunAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+const executionColumns = `
+	id, scheduled_transfer_id, occurrence, scheduled_for, status,
+	transaction_id, failure_reason, created_at, completed_at
+`
+
+func scanExecution(row rowScanner) (*models.ScheduledTransferExecution, error) {
+	e := &models.ScheduledTransferExecution{}
+	err := row.Scan(
+		&e.ID,
+		&e.ScheduledTransferID,
+		&e.Occurrence,
+		&e.ScheduledFor,
+		&e.Status,
+		&e.TransactionID,
+		&e.FailureReason,
+		&e.CreatedAt,
+		&e.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Create records a new scheduled transfer.
+func (r *ScheduledTransferRepository) Create(ctx context.Context, s *models.ScheduledTransfer) *errors.Error {
+	query := `
+		INSERT INTO scheduled_transfers (
+			user_id, source_wallet_id, destination_wallet_id, amount, currency,
+			description, frequency, start_at, end_at, max_occurrences, next_run_at, status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING ` + scheduledTransferColumns
+
+	created, err := scanScheduledTransfer(r.db.QueryRowContext(ctx, query,
+		s.UserID,
+		s.SourceWalletID,
+		s.DestinationWalletID,
+		s.Amount,
+		s.Currency,
+		s.Description,
+		s.Frequency,
+		s.StartAt,
+		s.EndAt,
+		s.MaxOccurrences,
+		s.NextRunAt,
+		s.Status,
+	))
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to create scheduled transfer")
+	}
+
+	*s = *created
+	return nil
+}
+
+// GetByID retrieves a scheduled transfer by ID.
+func (r *ScheduledTransferRepository) GetByID(ctx context.Context, id string) (*models.ScheduledTransfer, *errors.Error) {
+	query := `SELECT ` + scheduledTransferColumns + ` FROM scheduled_transfers WHERE id = $1`
+
+	s, err := scanScheduledTransfer(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundWithID("scheduled transfer", id)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get scheduled transfer")
+	}
+
+	return s, nil
+}
+
+// List retrieves the scheduled transfers of a user, newest first, with the total count.
+func (r *ScheduledTransferRepository) List(ctx context.Context, filter *models.ScheduledTransferFilter) ([]*models.ScheduledTransfer, int, *errors.Error) {
+	where := `WHERE user_id = $1`
+	args := []any{filter.UserID}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM scheduled_transfers `+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count scheduled transfers")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM scheduled_transfers %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		scheduledTransferColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list scheduled transfers")
+	}
+	defer func() { _ = rows.Close() }()
+
+	schedules := make([]*models.ScheduledTransfer, 0)
+	for rows.Next() {
+		s, err := scanScheduledTransfer(rows)
+		if err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan scheduled transfer")
+		}
+		schedules = append(schedules, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "error iterating scheduled transfers")
+	}
+
+	return schedules, total, nil
+}
+
+// Modify applies modify to the scheduled transfer under a row lock and saves it,
+// so changes by the user and by the scheduler never overwrite each other. If
+// modify returns an execution, it is recorded in the same database transaction:
+// a new occurrence is inserted and a pending one is given its outcome. Recording
+// an occurrence that has finished, or skipping one that has started, is
+// rejected with a conflict.
+func (r *ScheduledTransferRepository) Modify(ctx context.Context, id string, modify func(*models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error)) (*models.ScheduledTransfer, *errors.Error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query := `SELECT ` + scheduledTransferColumns + ` FROM scheduled_transfers WHERE id = $1 FOR UPDATE`
+	s, err := scanScheduledTransfer(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundWithID("scheduled transfer", id)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get scheduled transfer")
+	}
+
+	execution, modifyErr := modify(s)
+	if modifyErr != nil {
+		return nil, modifyErr
+	}
+
+	if execution != nil {
+		if recordErr := recordExecution(ctx, tx, execution); recordErr != nil {
+			return nil, recordErr
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE scheduled_transfers
+		SET occurrences = $2, next_run_at = $3, status = $4,
+		    consecutive_failures = $5, last_run_at = $6
+		WHERE id = $1
+		RETURNING updated_at
+	`, s.ID, s.Occurrences, s.NextRunAt, s.Status, s.ConsecutiveFailures, s.LastRunAt).Scan(&s.UpdatedAt)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to update scheduled transfer")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to commit transaction")
+	}
+	committed = true
+
+	return s, nil
+}
+
+func recordExecution(ctx context.Context, tx *sql.Tx, e *models.ScheduledTransferExecution) *errors.Error {
+	query := `
+		INSERT INTO scheduled_transfer_executions (
+			scheduled_transfer_id, occurrence, scheduled_for, status,
+			transaction_id, failure_reason, completed_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (scheduled_transfer_id, occurrence) DO UPDATE
+		SET status = EXCLUDED.status,
+		    transaction_id = EXCLUDED.transaction_id,
+		    failure_reason = EXCLUDED.failure_reason,
+		    completed_at = EXCLUDED.completed_at
+		WHERE scheduled_transfer_executions.status = 'pending'
+		  AND EXCLUDED.status != 'skipped'
+		RETURNING ` + executionColumns
+
+	recorded, err := scanExecution(tx.QueryRowContext(ctx, query,
+		e.ScheduledTransferID,
+		e.Occurrence,
+		e.ScheduledFor,
+		e.Status,
+		e.TransactionID,
+		e.FailureReason,
+	))
+	if err == sql.ErrNoRows {
+		return errors.Conflict(fmt.Sprintf("occurrence %d has already been recorded", e.Occurrence))
+	}
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to record execution")
+	}
+
+	*e = *recorded
+	return nil
+}
+
+// StartExecution records an occurrence as pending before its transfer is created.
+// If the occurrence was already started, the existing execution is returned, so
+// a run interrupted by a crash is picked up again.
+func (r *ScheduledTransferRepository) StartExecution(ctx context.Context, e *models.ScheduledTransferExecution) (*models.ScheduledTransferExecution, *errors.Error) {
+	query := `
+		INSERT INTO scheduled_transfer_executions (scheduled_transfer_id, occurrence, scheduled_for, status)
+		VALUES ($1, $2, $3, 'pending')
+		ON CONFLICT (scheduled_transfer_id, occurrence) DO NOTHING
+		RETURNING ` + executionColumns
+
+	started, err := scanExecution(r.db.QueryRowContext(ctx, query, e.ScheduledTransferID, e.Occurrence, e.ScheduledFor))
+	if err == nil {
+		return started, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, errors.DatabaseWrap(err, "failed to start execution")
+	}
+
+	query = `SELECT ` + executionColumns + ` FROM scheduled_transfer_executions WHERE scheduled_transfer_id = $1 AND occurrence = $2`
+	existing, err := scanExecution(r.db.QueryRowContext(ctx, query, e.ScheduledTransferID, e.Occurrence))
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to get execution")
+	}
+	return existing, nil
+}
+
+// ListExecutions retrieves the execution history of a scheduled transfer, newest
+// first, with the current status of each transfer and the total count.
+func (r *ScheduledTransferRepository) ListExecutions(ctx context.Context, scheduleID string, limit, offset int) ([]*models.ScheduledTransferExecution, int, *errors.Error) {
+	var total int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM scheduled_transfer_executions WHERE scheduled_transfer_id = $1`, scheduleID,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count executions")
+	}
+
+	query := `
+		SELECT e.id, e.scheduled_transfer_id, e.occurrence, e.scheduled_for, e.status,
+		       e.transaction_id, e.failure_reason, e.created_at, e.completed_at, t.status
+		FROM scheduled_transfer_executions e
+		LEFT JOIN transactions t ON t.id = e.transaction_id
+		WHERE e.scheduled_transfer_id = $1
+		ORDER BY e.occurrence DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, scheduleID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list executions")
+	}
+	defer func() { _ = rows.Close() }()
+
+	executions := make([]*models.ScheduledTransferExecution, 0)
+	for rows.Next() {
+		e := &models.ScheduledTransferExecution{}
+		err := rows.Scan(
+			&e.ID,
+			&e.ScheduledTransferID,
+			&e.Occurrence,
+			&e.ScheduledFor,
+			&e.Status,
+			&e.TransactionID,
+			&e.FailureReason,
+			&e.CreatedAt,
+			&e.CompletedAt,
+			&e.TransactionStatus,
+		)
+		if err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan execution")
+		}
+		executions = append(executions, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "error iterating executions")
+	}
+
+	return executions, total, nil
+}
+
+// ClaimDue claims up to limit active scheduled transfers that are due, earliest
+// first. Claiming locks them for the lease, so concurrent schedulers skip them
+// until Release is called or the lease lapses.
+func (r *ScheduledTransferRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.ScheduledTransfer, *errors.Error) {
+	query := `
+		WITH claimed AS (
+			UPDATE scheduled_transfers
+			SET locked_until = NOW() + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id
+				FROM scheduled_transfers
+				WHERE status = 'active'
+				  AND next_run_at <= NOW()
+				  AND (locked_until IS NULL OR locked_until <= NOW())
+				ORDER BY next_run_at, id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + scheduledTransferColumns + `
+		)
+		SELECT * FROM claimed
+		ORDER BY next_run_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to claim scheduled transfers")
+	}
+	defer func() { _ = rows.Close() }()
+
+	schedules := make([]*models.ScheduledTransfer, 0)
+	for rows.Next() {
+		s, err := scanScheduledTransfer(rows)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan scheduled transfer")
+		}
+		schedules = append(schedules, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating scheduled transfers")
+	}
+
+	return schedules, nil
+}
+
+// Release clears the claim on a scheduled transfer.
+func (r *ScheduledTransferRepository) Release(ctx context.Context, id string) *errors.Error {
+	_, err := r.db.ExecContext(ctx, `UPDATE scheduled_transfers SET locked_until = NULL WHERE id = $1`, id)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to release scheduled transfer")
+	}
+	return nil
+}
+
+// FindTransferByReference returns the ID, status and failure reason of the
+// transaction with the given reference, or a not found error.
+func (r *ScheduledTransferRepository) FindTransferByReference(ctx context.Context, reference string) (*models.Transaction, *errors.Error) {
+	tx := &models.Transaction{Reference: &reference}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, status, failure_reason
+		FROM transactions
+		WHERE reference = $1 AND type = 'transfer'
+		ORDER BY created_at
+		LIMIT 1
+	`, reference).Scan(&tx.ID, &tx.Status, &tx.FailureReason)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound("transaction with reference " + reference)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to find transaction by reference")
+	}
+
+	return tx, nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/lib/pq"
 	"github.com/vnykmshr/nivo/services/transaction/internal/models"
 	"github.com/vnykmshr/nivo/shared/errors"
 )
@@ -21,6 +22,56 @@ func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// withOutbox runs a transaction write and inserts the outbox messages for the
+// transaction it returns in the same database transaction, so a status change
+// is never recorded without its side effects. Without messages the write runs
+// directly against the pool.
+func (r *TransactionRepository) withOutbox(ctx context.Context, outbox []*models.OutboxMessage, write func(q querier) (string, *errors.Error)) *errors.Error {
+	if len(outbox) == 0 {
+		_, err := write(r.db)
+		return err
+	}
+
+	return r.inTx(ctx, outbox, write)
+}
+
+// inTx runs a transaction write and inserts its outbox messages in a single database transaction.
+func (r *TransactionRepository) inTx(ctx context.Context, outbox []*models.OutboxMessage, write func(q querier) (string, *errors.Error)) *errors.Error {
+	dbTx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+	var committed bool
+	defer func() {
+		if !committed {
+			_ = dbTx.Rollback()
+		}
+	}()
+
+	transactionID, writeErr := write(dbTx)
+	if writeErr != nil {
+		return writeErr
+	}
+
+	for _, msg := range outbox {
+		if insertErr := insertOutboxMessage(ctx, dbTx, transactionID, msg); insertErr != nil {
+			return insertErr
+		}
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit transaction")
+	}
+	committed = true
+
+	return nil
+}
+
 // escapeLikePattern escapes special characters in LIKE patterns to prevent SQL injection.
 // Escapes: % (matches any string), _ (matches any single character), and \ (escape character itself).
 func escapeLikePattern(pattern string) string {
@@ -31,8 +82,35 @@ func escapeLikePattern(pattern string) string {
 	return pattern
 }
 
-// Create creates a new transaction.
-func (r *TransactionRepository) Create(ctx context.Context, tx *models.Transaction) *errors.Error {
+// Create creates a new transaction together with its outbox messages, atomically.
+func (r *TransactionRepository) Create(ctx context.Context, tx *models.Transaction, outbox ...*models.OutboxMessage) *errors.Error {
+	return r.withOutbox(ctx, outbox, func(q querier) (string, *errors.Error) {
+		if err := r.create(ctx, q, tx); err != nil {
+			return "", err
+		}
+		return tx.ID, nil
+	})
+}
+
+// CreateWithSaga creates a new transaction together with its saga and outbox
+// messages, atomically, so every transaction that needs processing can be
+// found by the saga recovery worker.
+func (r *TransactionRepository) CreateWithSaga(ctx context.Context, tx *models.Transaction, saga *models.TransactionSaga, outbox ...*models.OutboxMessage) *errors.Error {
+	return r.inTx(ctx, outbox, func(q querier) (string, *errors.Error) {
+		if err := r.create(ctx, q, tx); err != nil {
+			return "", err
+		}
+
+		saga.TransactionID = tx.ID
+		if _, err := insertSaga(ctx, q, saga); err != nil {
+			return "", errors.DatabaseWrap(err, "failed to create saga")
+		}
+
+		return tx.ID, nil
+	})
+}
+
+func (r *TransactionRepository) create(ctx context.Context, q querier, tx *models.Transaction) *errors.Error {
 	var metadataJSON []byte
 	var err error
 
@@ -52,7 +130,7 @@ func (r *TransactionRepository) Create(ctx context.Context, tx *models.Transacti
 		RETURNING id, created_at, updated_at
 	`
 
-	err = r.db.QueryRowContext(ctx, query,
+	err = q.QueryRowContext(ctx, query,
 		tx.Type,
 		tx.Status,
 		tx.SourceWalletID,
@@ -408,26 +486,30 @@ func (r *TransactionRepository) SearchAll(ctx context.Context, filter *models.Tr
 	return transactions, nil
 }
 
-// UpdateStatus updates the status of a transaction.
-func (r *TransactionRepository) UpdateStatus(ctx context.Context, id string, status models.TransactionStatus, failureReason *string) *errors.Error {
+// UpdateStatus updates the status of a transaction together with its outbox messages, atomically.
+// Moving to completed also stamps completed_at.
+func (r *TransactionRepository) UpdateStatus(ctx context.Context, id string, status models.TransactionStatus, failureReason *string, outbox ...*models.OutboxMessage) *errors.Error {
 	query := `
 		UPDATE transactions
-		SET status = $1, failure_reason = $2, updated_at = NOW()
+		SET status = $1, failure_reason = $2, updated_at = NOW(),
+		    completed_at = CASE WHEN $4 THEN NOW() ELSE completed_at END
 		WHERE id = $3
 		RETURNING id
 	`
 
-	var txID string
-	err := r.db.QueryRowContext(ctx, query, status, failureReason, id).Scan(&txID)
+	return r.withOutbox(ctx, outbox, func(q querier) (string, *errors.Error) {
+		var txID string
+		err := q.QueryRowContext(ctx, query, status, failureReason, id, status == models.TransactionStatusCompleted).Scan(&txID)
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return errors.NotFoundWithID("transaction", id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return "", errors.NotFoundWithID("transaction", id)
+			}
+			return "", errors.DatabaseWrap(err, "failed to update transaction status")
 		}
-		return errors.DatabaseWrap(err, "failed to update transaction status")
-	}
 
-	return nil
+		return txID, nil
+	})
 }
 
 // UpdateLedgerEntry updates the ledger entry ID for a transaction.
@@ -523,9 +605,10 @@ func (r *TransactionRepository) UpdateMetadata(ctx context.Context, id string, m
 	return nil
 }
 
-// CompleteWithMetadata completes a transaction and updates its metadata atomically.
+// CompleteWithMetadata completes a transaction and updates its metadata atomically,
+// together with its outbox messages.
 // This provides idempotency by only updating transactions in pending status.
-func (r *TransactionRepository) CompleteWithMetadata(ctx context.Context, id string, metadata map[string]string) *errors.Error {
+func (r *TransactionRepository) CompleteWithMetadata(ctx context.Context, id string, metadata map[string]string, outbox ...*models.OutboxMessage) *errors.Error {
 	metadataJSON, err := json.Marshal(metadata)
 	if err != nil {
 		return errors.Validation("invalid metadata format")
@@ -538,17 +621,19 @@ func (r *TransactionRepository) CompleteWithMetadata(ctx context.Context, id str
 		RETURNING id
 	`
 
-	var txID string
-	dbErr := r.db.QueryRowContext(ctx, query, models.TransactionStatusCompleted, metadataJSON, id, models.TransactionStatusPending).Scan(&txID)
+	return r.withOutbox(ctx, outbox, func(q querier) (string, *errors.Error) {
+		var txID string
+		dbErr := q.QueryRowContext(ctx, query, models.TransactionStatusCompleted, metadataJSON, id, models.TransactionStatusPending).Scan(&txID)
 
-	if dbErr != nil {
-		if dbErr == sql.ErrNoRows {
-			return errors.NotFound("transaction not found or already completed")
+		if dbErr != nil {
+			if dbErr == sql.ErrNoRows {
+				return "", errors.NotFound("transaction not found or already completed")
+			}
+			return "", errors.DatabaseWrap(dbErr, "failed to complete transaction")
 		}
-		return errors.DatabaseWrap(dbErr, "failed to complete transaction")
-	}
 
-	return nil
+		return txID, nil
+	})
 }
 
 // UpdateCategory updates the category of a transaction.
@@ -652,3 +737,41 @@ func (r *TransactionRepository) GetCategorySummary(ctx context.Context, walletID
 
 	return summaries, nil
 }
+
+// SumByWallets computes credits, debits and net settled amount for each wallet.
+// Wallets without settled transactions are returned with zero totals.
+func (r *TransactionRepository) SumByWallets(ctx context.Context, walletIDs []string) ([]models.WalletTotals, *errors.Error) {
+	query := `
+		SELECT w.id,
+		       COALESCE(SUM(t.amount) FILTER (WHERE t.destination_wallet_id = w.id), 0) AS credits,
+		       COALESCE(SUM(t.amount) FILTER (WHERE t.source_wallet_id = w.id), 0) AS debits,
+		       COUNT(t.id) AS transaction_count
+		FROM unnest($1::uuid[]) AS w(id)
+		LEFT JOIN transactions t
+		       ON (t.source_wallet_id = w.id OR t.destination_wallet_id = w.id)
+		      AND t.status IN ('completed', 'reversed')
+		GROUP BY w.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(walletIDs))
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to sum wallet transactions")
+	}
+	defer func() { _ = rows.Close() }()
+
+	totals := make([]models.WalletTotals, 0, len(walletIDs))
+	for rows.Next() {
+		var t models.WalletTotals
+		if err := rows.Scan(&t.WalletID, &t.Credits, &t.Debits, &t.TransactionCount); err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan wallet totals")
+		}
+		t.Net = t.Credits - t.Debits
+		totals = append(totals, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating wallet totals")
+	}
+
+	return totals, nil
+}
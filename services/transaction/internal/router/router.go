@@ -4,12 +4,15 @@ import (
 	"net/http"
 
 	"github.com/vnykmshr/nivo/services/transaction/internal/handler"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/audit"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/metrics"
 	"github.com/vnykmshr/nivo/shared/middleware"
 )
 
 // SetupRoutes configures all routes for the transaction service using Go 1.22+ stdlib router.
-func SetupRoutes(transactionHandler *handler.TransactionHandler, jwtSecret string) http.Handler {
+func SetupRoutes(transactionHandler *handler.TransactionHandler, scheduledTransferHandler *handler.ScheduledTransferHandler, paymentRequestHandler *handler.PaymentRequestHandler, disputeHandler *handler.DisputeHandler, approvalHandler *approval.Handler, idempotencyStore middleware.IdempotencyStore, jwtKeys sharedjwt.KeySource) http.Handler {
 	mux := http.NewServeMux()
 
 	// Health check endpoint (public)
@@ -24,7 +27,7 @@ func SetupRoutes(transactionHandler *handler.TransactionHandler, jwtSecret strin
 
 	// Setup auth middleware
 	authConfig := middleware.AuthConfig{
-		JWTSecret: jwtSecret,
+		Keys:      jwtKeys,
 		SkipPaths: []string{"/health"},
 	}
 	authMiddleware := middleware.Auth(authConfig)
@@ -32,6 +35,9 @@ func SetupRoutes(transactionHandler *handler.TransactionHandler, jwtSecret strin
 	// Rate limiting for money movement (prevent abuse)
 	moneyRateLimit := middleware.RateLimit(middleware.StrictRateLimitConfig())
 
+	// Idempotency keys make money movement safe to retry (runs after auth: keys are per user)
+	idempotent := middleware.Idempotency(middleware.DefaultIdempotencyConfig(idempotencyStore))
+
 	// Permission middleware
 	createTransferPerm := middleware.RequirePermission("transaction:transfer:create")
 	createDepositPerm := middleware.RequirePermission("transaction:deposit:create")
@@ -40,16 +46,18 @@ func SetupRoutes(transactionHandler *handler.TransactionHandler, jwtSecret strin
 	listTransactionsPerm := middleware.RequirePermission("transaction:transaction:list")
 	searchAllTransactionsPerm := middleware.RequirePermission("transaction:transaction:search")
 	reverseTransactionPerm := middleware.RequirePermission("transaction:transaction:reverse")
+	createDisputePerm := middleware.RequirePermission("transaction:dispute:create")
+	manageDisputesPerm := middleware.RequirePermission("transaction:dispute:manage")
 
 	// ========================================================================
 	// Transaction Creation Endpoints (with strict rate limiting)
 	// ========================================================================
 
-	mux.Handle("POST /api/v1/transactions/transfer", moneyRateLimit(authMiddleware(createTransferPerm(http.HandlerFunc(transactionHandler.CreateTransfer)))))
-	mux.Handle("POST /api/v1/transactions/deposit", moneyRateLimit(authMiddleware(createDepositPerm(http.HandlerFunc(transactionHandler.CreateDeposit)))))
-	mux.Handle("POST /api/v1/transactions/deposit/upi", moneyRateLimit(authMiddleware(createDepositPerm(http.HandlerFunc(transactionHandler.InitiateUPIDeposit)))))
+	mux.Handle("POST /api/v1/transactions/transfer", moneyRateLimit(authMiddleware(createTransferPerm(idempotent(http.HandlerFunc(transactionHandler.CreateTransfer))))))
+	mux.Handle("POST /api/v1/transactions/deposit", moneyRateLimit(authMiddleware(createDepositPerm(idempotent(http.HandlerFunc(transactionHandler.CreateDeposit))))))
+	mux.Handle("POST /api/v1/transactions/deposit/upi", moneyRateLimit(authMiddleware(createDepositPerm(idempotent(http.HandlerFunc(transactionHandler.InitiateUPIDeposit))))))
 	mux.Handle("POST /api/v1/transactions/deposit/upi/complete", authMiddleware(http.HandlerFunc(transactionHandler.CompleteUPIDeposit))) // Webhook endpoint (no rate limit)
-	mux.Handle("POST /api/v1/transactions/withdrawal", moneyRateLimit(authMiddleware(createWithdrawalPerm(http.HandlerFunc(transactionHandler.CreateWithdrawal)))))
+	mux.Handle("POST /api/v1/transactions/withdrawal", moneyRateLimit(authMiddleware(createWithdrawalPerm(idempotent(http.HandlerFunc(transactionHandler.CreateWithdrawal))))))
 
 	// ========================================================================
 	// Transaction Retrieval Endpoints
@@ -58,6 +66,38 @@ func SetupRoutes(transactionHandler *handler.TransactionHandler, jwtSecret strin
 	mux.Handle("GET /api/v1/transactions/{id}", authMiddleware(readTransactionPerm(http.HandlerFunc(transactionHandler.GetTransaction))))
 	mux.Handle("GET /api/v1/wallets/{walletId}/transactions", authMiddleware(listTransactionsPerm(http.HandlerFunc(transactionHandler.ListWalletTransactions))))
 
+	// ========================================================================
+	// Scheduled Transfer Endpoints
+	// ========================================================================
+
+	mux.Handle("POST /api/v1/scheduled-transfers", authMiddleware(createTransferPerm(idempotent(http.HandlerFunc(scheduledTransferHandler.CreateScheduledTransfer)))))
+	mux.Handle("GET /api/v1/scheduled-transfers", authMiddleware(readTransactionPerm(http.HandlerFunc(scheduledTransferHandler.ListScheduledTransfers))))
+	mux.Handle("GET /api/v1/scheduled-transfers/{id}", authMiddleware(readTransactionPerm(http.HandlerFunc(scheduledTransferHandler.GetScheduledTransfer))))
+	mux.Handle("GET /api/v1/scheduled-transfers/{id}/executions", authMiddleware(readTransactionPerm(http.HandlerFunc(scheduledTransferHandler.ListExecutions))))
+	mux.Handle("POST /api/v1/scheduled-transfers/{id}/skip", authMiddleware(createTransferPerm(http.HandlerFunc(scheduledTransferHandler.SkipScheduledTransfer))))
+	mux.Handle("POST /api/v1/scheduled-transfers/{id}/pause", authMiddleware(createTransferPerm(http.HandlerFunc(scheduledTransferHandler.PauseScheduledTransfer))))
+	mux.Handle("POST /api/v1/scheduled-transfers/{id}/resume", authMiddleware(createTransferPerm(http.HandlerFunc(scheduledTransferHandler.ResumeScheduledTransfer))))
+	mux.Handle("POST /api/v1/scheduled-transfers/{id}/cancel", authMiddleware(createTransferPerm(http.HandlerFunc(scheduledTransferHandler.CancelScheduledTransfer))))
+
+	// ========================================================================
+	// Payment Request Endpoints
+	// ========================================================================
+
+	mux.Handle("POST /api/v1/payment-requests", moneyRateLimit(authMiddleware(createTransferPerm(idempotent(http.HandlerFunc(paymentRequestHandler.CreatePaymentRequest))))))
+	mux.Handle("GET /api/v1/payment-requests", authMiddleware(readTransactionPerm(http.HandlerFunc(paymentRequestHandler.ListPaymentRequests))))
+	mux.Handle("GET /api/v1/payment-requests/{id}", authMiddleware(readTransactionPerm(http.HandlerFunc(paymentRequestHandler.GetPaymentRequest))))
+	mux.Handle("POST /api/v1/payment-requests/{id}/accept", moneyRateLimit(authMiddleware(createTransferPerm(idempotent(http.HandlerFunc(paymentRequestHandler.AcceptPaymentRequest))))))
+	mux.Handle("POST /api/v1/payment-requests/{id}/decline", authMiddleware(createTransferPerm(http.HandlerFunc(paymentRequestHandler.DeclinePaymentRequest))))
+	mux.Handle("POST /api/v1/payment-requests/{id}/cancel", authMiddleware(createTransferPerm(http.HandlerFunc(paymentRequestHandler.CancelPaymentRequest))))
+
+	// ========================================================================
+	// Dispute Endpoints
+	// ========================================================================
+
+	mux.Handle("POST /api/v1/transactions/{id}/disputes", authMiddleware(createDisputePerm(idempotent(http.HandlerFunc(disputeHandler.OpenDispute)))))
+	mux.Handle("GET /api/v1/disputes", authMiddleware(readTransactionPerm(http.HandlerFunc(disputeHandler.ListDisputes))))
+	mux.Handle("GET /api/v1/disputes/{id}", authMiddleware(readTransactionPerm(http.HandlerFunc(disputeHandler.GetDispute))))
+
 	// ========================================================================
 	// Spending Category Endpoints
 	// ========================================================================
@@ -87,22 +127,43 @@ func SetupRoutes(transactionHandler *handler.TransactionHandler, jwtSecret strin
 	mux.Handle("GET /api/v1/admin/transactions/search", moneyRateLimit(authMiddleware(searchAllTransactionsPerm(http.HandlerFunc(transactionHandler.SearchAllTransactions)))))
 
 	// ========================================================================
-	// Transaction Reversal Endpoint (Admin Operation - with strict rate limiting)
+	// Transaction Reversal Endpoints (Admin Operation - with strict rate limiting)
 	// ========================================================================
 
 	mux.Handle("POST /api/v1/transactions/{id}/reverse", moneyRateLimit(authMiddleware(reverseTransactionPerm(http.HandlerFunc(transactionHandler.ReverseTransaction)))))
 
+	// Reversal approvals: a second admin with the reverse permission approves or rejects
+	mux.Handle("GET /api/v1/admin/transactions/approvals", authMiddleware(reverseTransactionPerm(http.HandlerFunc(approvalHandler.List))))
+	mux.Handle("GET /api/v1/admin/transactions/approvals/{id}", authMiddleware(reverseTransactionPerm(http.HandlerFunc(approvalHandler.Get))))
+	mux.Handle("POST /api/v1/admin/transactions/approvals/{id}/approve", moneyRateLimit(authMiddleware(reverseTransactionPerm(http.HandlerFunc(approvalHandler.Approve)))))
+	mux.Handle("POST /api/v1/admin/transactions/approvals/{id}/reject", authMiddleware(reverseTransactionPerm(http.HandlerFunc(approvalHandler.Reject))))
+
+	// ========================================================================
+	// Dispute Management Endpoints (Admin Operation)
+	// ========================================================================
+
+	mux.Handle("GET /api/v1/admin/transactions/disputes", authMiddleware(manageDisputesPerm(http.HandlerFunc(disputeHandler.AdminListDisputes))))
+	mux.Handle("GET /api/v1/admin/transactions/disputes/{id}", authMiddleware(manageDisputesPerm(http.HandlerFunc(disputeHandler.AdminGetDispute))))
+	mux.Handle("POST /api/v1/admin/transactions/disputes/{id}/investigate", authMiddleware(manageDisputesPerm(http.HandlerFunc(disputeHandler.InvestigateDispute))))
+	mux.Handle("POST /api/v1/admin/transactions/disputes/{id}/resolve", moneyRateLimit(authMiddleware(manageDisputesPerm(http.HandlerFunc(disputeHandler.ResolveDispute)))))
+
 	// ========================================================================
-	// Internal Endpoints (no authentication - service-to-service)
+	// Internal Endpoints (service-to-service with service token auth)
 	// ========================================================================
 
-	// Process transfer (executes wallet transfer with limit checking)
-	mux.HandleFunc("POST /internal/v1/transactions/{id}/process", transactionHandler.ProcessTransfer)
+	serviceAuth := middleware.NewServiceAuth(jwtKeys, sharedjwt.ServiceTransaction)
+
+	// Settled totals per wallet (used by wallet service reconciliation)
+	mux.HandleFunc("POST /internal/v1/transactions/wallet-totals",
+		serviceAuth.Allow(transactionHandler.GetWalletTotalsInternal, sharedjwt.ServiceWallet))
 
 	// Apply middleware chain
 	metricsCollector := metrics.NewCollector("transaction")
 	handler := metricsCollector.Middleware("transaction")(mux)
 
+	// Record the client IP with audited actions
+	handler = audit.Middleware()(handler)
+
 	// Apply request ID
 	handler = middleware.RequestID()(handler)
 
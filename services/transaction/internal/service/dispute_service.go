@@ -0,0 +1,521 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/audit"
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/logger"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+const (
+	disputeSettlementLease       = 5 * time.Minute  // Longer than running both settlement sagas
+	disputeSettlementRetry       = 30 * time.Minute // Between settlement attempts that did not complete
+	disputeMaxSettlementAttempts = 6                // Settlement transactions per dispute, failed ones included
+	disputeBatchSize             = 100
+
+	disputeOpenedTemplate               = "dispute_opened_inapp"
+	disputeCounterpartyOpenedTemplate   = "dispute_counterparty_opened_inapp"
+	disputeInvestigatingTemplate        = "dispute_investigating_inapp"
+	disputeResolvedTemplate             = "dispute_resolved_inapp"
+	disputeCounterpartyResolvedTemplate = "dispute_counterparty_resolved_inapp"
+	disputeRejectedTemplate             = "dispute_rejected_inapp"
+	disputeCounterpartyRejectedTemplate = "dispute_counterparty_rejected_inapp"
+)
+
+// DisputeRepositoryInterface defines the interface for dispute storage.
+type DisputeRepositoryInterface interface {
+	Create(ctx context.Context, d *models.Dispute) *errors.Error
+	GetByID(ctx context.Context, id string) (*models.Dispute, *errors.Error)
+	List(ctx context.Context, filter *models.DisputeFilter) ([]*models.Dispute, int, *errors.Error)
+	StartInvestigation(ctx context.Context, id, adminID string) (*models.Dispute, *errors.Error)
+	Resolve(ctx context.Context, id string, status models.DisputeStatus, adminID, notes string, lease time.Duration) (*models.Dispute, *errors.Error)
+	ClaimUnsettled(ctx context.Context, limit int, lease time.Duration) ([]*models.Dispute, *errors.Error)
+	SaveSettlement(ctx context.Context, d *models.Dispute, retryIn time.Duration) *errors.Error
+	FlagOverdue(ctx context.Context, limit int) ([]*models.Dispute, *errors.Error)
+	FindTransactionByReference(ctx context.Context, reference string) (*models.Transaction, *errors.Error)
+}
+
+// DisputeTransactions reads disputed transactions and moves the money that
+// settles a dispute.
+type DisputeTransactions interface {
+	GetTransaction(ctx context.Context, id string) (*models.Transaction, *errors.Error)
+	CreateRefund(ctx context.Context, parent *models.Transaction, walletID, reference, description string) (*models.Transaction, *errors.Error)
+	CreateRecovery(ctx context.Context, parent *models.Transaction, walletID, reference, description string) (*models.Transaction, *errors.Error)
+	MarkReversed(ctx context.Context, transactionID string) *errors.Error
+}
+
+// DisputeEventPublisher publishes dispute events to the gateway's SSE broker.
+type DisputeEventPublisher interface {
+	PublishDisputeEvent(eventType string, disputeID string, data map[string]interface{})
+}
+
+// DisputeService handles disputes of completed transactions. A customer opens
+// a dispute on a transfer or withdrawal from their wallet; an admin starts
+// investigating it and resolves it in the customer's favour or rejects it.
+//
+// A resolution in the customer's favour is settled in two steps, each a
+// transaction with its own saga and ledger entry: a provisional credit (a
+// refund from the settlement account to the customer's wallet) and, for
+// transfers, the final reversal (recovering the amount from the
+// counterparty's wallet into the settlement account). The disputed
+// transaction is then marked reversed. Steps that do not complete are retried
+// by SettleDue, creating at most disputeMaxSettlementAttempts transactions;
+// after that the dispute is settlement_failed and left to an admin.
+//
+// Disputes must be picked up within models.DisputeAcknowledgeSLA and resolved
+// within models.DisputeResolutionSLA of being opened; FlagOverdue records
+// breaches.
+type DisputeService struct {
+	repo         DisputeRepositoryInterface
+	transactions DisputeTransactions
+	wallets      WalletInfoReader
+	notifier     NotificationSender
+	events       DisputeEventPublisher
+	auditLog     audit.Recorder
+	logger       *logger.Logger
+	now          func() time.Time
+}
+
+// NewDisputeService creates a new dispute service.
+func NewDisputeService(
+	repo DisputeRepositoryInterface,
+	transactions DisputeTransactions,
+	wallets WalletInfoReader,
+	notifier NotificationSender,
+	events DisputeEventPublisher,
+) *DisputeService {
+	return &DisputeService{
+		repo:         repo,
+		transactions: transactions,
+		wallets:      wallets,
+		notifier:     notifier,
+		events:       events,
+		logger:       logger.NewDefault("transaction"),
+		now:          time.Now,
+	}
+}
+
+// SetAuditLog sets where admin actions on disputes are recorded.
+func (s *DisputeService) SetAuditLog(auditLog audit.Recorder) {
+	s.auditLog = auditLog
+}
+
+// Open disputes a completed transfer or withdrawal made from the user's wallet.
+func (s *DisputeService) Open(ctx context.Context, userID, transactionID string, req *models.OpenDisputeRequest) (*models.Dispute, *errors.Error) {
+	if !req.Reason.IsValid() {
+		return nil, errors.BadRequest("invalid dispute reason")
+	}
+
+	transaction, err := s.transactions.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Type != models.TransactionTypeTransfer && transaction.Type != models.TransactionTypeWithdrawal {
+		return nil, errors.BadRequest("only transfers and withdrawals can be disputed")
+	}
+	if !transaction.IsCompleted() {
+		return nil, errors.BadRequest("only completed transactions can be disputed")
+	}
+
+	completedAt := transaction.CreatedAt.Time
+	if transaction.CompletedAt != nil {
+		completedAt = transaction.CompletedAt.Time
+	}
+	if s.now().After(completedAt.Add(models.DisputeWindow)) {
+		return nil, errors.BadRequest(fmt.Sprintf("transactions can only be disputed within %d days", int(models.DisputeWindow.Hours()/24)))
+	}
+
+	// Only the sender can dispute a transaction
+	source, err := s.wallets.GetWalletInfo(ctx, *transaction.SourceWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if source.UserID != userID {
+		return nil, errors.Forbidden("transaction was not made from your wallet")
+	}
+
+	now := s.now()
+	dispute := &models.Dispute{
+		TransactionID:    transaction.ID,
+		CustomerUserID:   userID,
+		CustomerWalletID: *transaction.SourceWalletID,
+		Amount:           transaction.Amount,
+		Currency:         transaction.Currency,
+		Description:      transaction.Description,
+		Reason:           req.Reason,
+		Evidence:         req.Evidence,
+		Status:           models.DisputeStatusOpen,
+		AcknowledgeBy:    sharedModels.NewTimestamp(now.Add(models.DisputeAcknowledgeSLA)),
+		ResolveBy:        sharedModels.NewTimestamp(now.Add(models.DisputeResolutionSLA)),
+	}
+
+	if transaction.Type == models.TransactionTypeTransfer {
+		destination, err := s.wallets.GetWalletInfo(ctx, *transaction.DestinationWalletID)
+		if err != nil {
+			return nil, err
+		}
+		dispute.CounterpartyWalletID = transaction.DestinationWalletID
+		dispute.CounterpartyUserID = &destination.UserID
+	}
+
+	if err := s.repo.Create(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	s.notify(dispute.CustomerUserID, disputeOpenedTemplate, dispute, clients.NotificationPriorityNormal, nil)
+	if dispute.CounterpartyUserID != nil {
+		s.notify(*dispute.CounterpartyUserID, disputeCounterpartyOpenedTemplate, dispute, clients.NotificationPriorityHigh, nil)
+	}
+	s.publish("dispute.opened", dispute)
+
+	return dispute, nil
+}
+
+// Get retrieves a dispute the user is a party to.
+func (s *DisputeService) Get(ctx context.Context, userID, id string) (*models.Dispute, *errors.Error) {
+	dispute, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !dispute.HasParty(userID) {
+		return nil, errors.NotFoundWithID("dispute", id)
+	}
+	return dispute, nil
+}
+
+// GetByID retrieves any dispute (admin operation).
+func (s *DisputeService) GetByID(ctx context.Context, id string) (*models.Dispute, *errors.Error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List retrieves disputes matching the filter.
+func (s *DisputeService) List(ctx context.Context, filter *models.DisputeFilter) ([]*models.Dispute, int, *errors.Error) {
+	if filter.Status != nil {
+		switch *filter.Status {
+		case models.DisputeStatusOpen, models.DisputeStatusInvestigating,
+			models.DisputeStatusResolvedInFavour, models.DisputeStatusSettlementFailed,
+			models.DisputeStatusRejected:
+		default:
+			return nil, 0, errors.BadRequest("invalid status filter")
+		}
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// StartInvestigation assigns an open dispute to the admin making the request.
+func (s *DisputeService) StartInvestigation(ctx context.Context, id string) (*models.Dispute, *errors.Error) {
+	adminID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, errors.Unauthorized("user not authenticated")
+	}
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	dispute, err := s.repo.StartInvestigation(ctx, id, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, "dispute.investigate", dispute, models.DisputeStatusOpen)
+	s.notify(dispute.CustomerUserID, disputeInvestigatingTemplate, dispute, clients.NotificationPriorityNormal, nil)
+	s.publish("dispute.investigating", dispute)
+
+	return dispute, nil
+}
+
+// Resolve resolves a dispute under investigation. A resolution in the
+// customer's favour is settled straight away; if a settlement step does not
+// complete, the dispute is returned unsettled and SettleDue finishes it.
+func (s *DisputeService) Resolve(ctx context.Context, id string, req *models.ResolveDisputeRequest) (*models.Dispute, *errors.Error) {
+	adminID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, errors.Unauthorized("user not authenticated")
+	}
+
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != models.DisputeStatusInvestigating {
+		return nil, errors.Conflict(fmt.Sprintf("dispute is %s, not under investigation", current.Status))
+	}
+
+	status := models.DisputeStatusRejected
+	if req.InFavour {
+		status = models.DisputeStatusResolvedInFavour
+
+		// The money may have been returned another way meanwhile
+		transaction, err := s.transactions.GetTransaction(ctx, current.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		if !transaction.IsCompleted() {
+			return nil, errors.Conflict(fmt.Sprintf("disputed transaction is %s and can no longer be refunded", transaction.Status))
+		}
+	}
+
+	dispute, err := s.repo.Resolve(ctx, id, status, adminID, req.Notes, disputeSettlementLease)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, "dispute.resolve", dispute, models.DisputeStatusInvestigating)
+
+	if status == models.DisputeStatusRejected {
+		extra := map[string]any{"notes": req.Notes}
+		s.notify(dispute.CustomerUserID, disputeRejectedTemplate, dispute, clients.NotificationPriorityNormal, extra)
+		if dispute.CounterpartyUserID != nil {
+			s.notify(*dispute.CounterpartyUserID, disputeCounterpartyRejectedTemplate, dispute, clients.NotificationPriorityNormal, nil)
+		}
+		s.publish("dispute.rejected", dispute)
+		return dispute, nil
+	}
+
+	s.notify(dispute.CustomerUserID, disputeResolvedTemplate, dispute, clients.NotificationPriorityHigh, nil)
+	if dispute.CounterpartyUserID != nil {
+		s.notify(*dispute.CounterpartyUserID, disputeCounterpartyResolvedTemplate, dispute, clients.NotificationPriorityHigh, nil)
+	}
+	s.publish("dispute.resolved", dispute)
+
+	if settleErr := s.settle(ctx, dispute); settleErr != nil {
+		s.logger.WithField("dispute_id", dispute.ID).WithError(settleErr).Warn("Dispute settlement did not complete, will retry")
+	}
+
+	return dispute, nil
+}
+
+// SettleDue continues the settlement of disputes resolved in the customer's
+// favour whose settlement has not completed and returns how many were
+// settled. It is called periodically by the dispute worker.
+func (s *DisputeService) SettleDue(ctx context.Context) (int, *errors.Error) {
+	disputes, err := s.repo.ClaimUnsettled(ctx, disputeBatchSize, disputeSettlementLease)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, dispute := range disputes {
+		if settleErr := s.settle(ctx, dispute); settleErr != nil {
+			s.logger.WithField("dispute_id", dispute.ID).WithError(settleErr).Error("Failed to settle dispute")
+			continue
+		}
+		if dispute.SettledAt != nil {
+			settled++
+		}
+	}
+
+	return settled, nil
+}
+
+// FlagOverdue records an SLA breach on active disputes that missed their
+// acknowledgement or resolution deadline and returns how many were flagged.
+// It is called periodically by the dispute worker.
+func (s *DisputeService) FlagOverdue(ctx context.Context) (int, *errors.Error) {
+	disputes, err := s.repo.FlagOverdue(ctx, disputeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, dispute := range disputes {
+		s.logger.With(map[string]interface{}{
+			"dispute_id":     dispute.ID,
+			"status":         string(dispute.Status),
+			"acknowledge_by": dispute.AcknowledgeBy.Time.Format(time.RFC3339),
+			"resolve_by":     dispute.ResolveBy.Time.Format(time.RFC3339),
+		}).Warn("Dispute breached its SLA")
+		s.publish("dispute.sla_breached", dispute)
+	}
+
+	return len(disputes), nil
+}
+
+// settle runs the next settlement steps of a claimed dispute resolved in the
+// customer's favour: the provisional credit, then for transfers the recovery
+// from the counterparty, then marking the disputed transaction reversed. A
+// step waits while its transaction is in progress and is tried again if it
+// failed, until the dispute runs out of settlement attempts. Progress is saved
+// even when a step returns an error.
+func (s *DisputeService) settle(ctx context.Context, dispute *models.Dispute) *errors.Error {
+	defer func() {
+		if err := s.repo.SaveSettlement(context.WithoutCancel(ctx), dispute, disputeSettlementRetry); err != nil {
+			s.logger.WithField("dispute_id", dispute.ID).WithError(err).Error("Failed to save dispute settlement")
+		}
+	}()
+
+	original, err := s.transactions.GetTransaction(ctx, dispute.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	credit, err := s.settlementAttempt(ctx, dispute, disputeReference(dispute.ID, "credit"), func() (*models.Transaction, *errors.Error) {
+		return s.transactions.CreateRefund(ctx, original, dispute.CustomerWalletID,
+			disputeReference(dispute.ID, "credit"), "Dispute refund: "+dispute.Description)
+	})
+	if err != nil {
+		return err
+	}
+	if credit == nil {
+		s.failSettlement(dispute)
+		return nil
+	}
+	dispute.CreditTransactionID = &credit.ID
+	if !credit.IsCompleted() {
+		return nil
+	}
+
+	if dispute.CounterpartyWalletID != nil {
+		recovery, err := s.settlementAttempt(ctx, dispute, disputeReference(dispute.ID, "recovery"), func() (*models.Transaction, *errors.Error) {
+			return s.transactions.CreateRecovery(ctx, original, *dispute.CounterpartyWalletID,
+				disputeReference(dispute.ID, "recovery"), "Dispute reversal: "+dispute.Description)
+		})
+		if err != nil {
+			return err
+		}
+		if recovery == nil {
+			s.failSettlement(dispute)
+			return nil
+		}
+		dispute.ReversalTransactionID = &recovery.ID
+		if !recovery.IsCompleted() {
+			return nil
+		}
+	}
+
+	if original.IsCompleted() {
+		if err := s.transactions.MarkReversed(ctx, original.ID); err != nil {
+			return err
+		}
+	}
+
+	settledAt := sharedModels.NewTimestamp(s.now())
+	dispute.SettledAt = &settledAt
+	s.publish("dispute.settled", dispute)
+	return nil
+}
+
+// settlementAttempt returns the latest transaction with the reference, or a
+// new one made by create if there is none or the latest failed. It returns
+// neither when a new one is needed but the dispute has no attempts left.
+func (s *DisputeService) settlementAttempt(ctx context.Context, dispute *models.Dispute, reference string, create func() (*models.Transaction, *errors.Error)) (*models.Transaction, *errors.Error) {
+	transaction, err := s.repo.FindTransactionByReference(ctx, reference)
+	if err != nil && err.Code != errors.ErrCodeNotFound {
+		return nil, err
+	}
+	if err == nil && !transaction.IsFailed() {
+		return transaction, nil
+	}
+
+	if dispute.SettlementAttempts >= disputeMaxSettlementAttempts {
+		return nil, nil
+	}
+	dispute.SettlementAttempts++
+
+	transaction, err = create()
+	if transaction == nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// failSettlement gives up settling a dispute that ran out of settlement
+// attempts. The dispute is no longer retried; admins are alerted through the
+// dispute.settlement_failed event and settle it by hand.
+func (s *DisputeService) failSettlement(dispute *models.Dispute) {
+	dispute.Status = models.DisputeStatusSettlementFailed
+
+	s.logger.With(map[string]interface{}{
+		"dispute_id":          dispute.ID,
+		"settlement_attempts": dispute.SettlementAttempts,
+	}).Error("Dispute settlement ran out of attempts, needs an admin")
+	s.publish("dispute.settlement_failed", dispute)
+}
+
+// record adds an admin action on a dispute to the audit log.
+func (s *DisputeService) record(ctx context.Context, action string, dispute *models.Dispute, before models.DisputeStatus) {
+	if s.auditLog == nil {
+		return
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		Action:       action,
+		ResourceType: "dispute",
+		ResourceID:   dispute.ID,
+		Before:       map[string]interface{}{"status": before},
+		After: map[string]interface{}{
+			"status":           dispute.Status,
+			"transaction_id":   dispute.TransactionID,
+			"resolution_notes": dispute.ResolutionNotes,
+		},
+	})
+}
+
+// notify sends an in-app notification about a dispute to one of its parties.
+func (s *DisputeService) notify(userID, template string, dispute *models.Dispute, priority clients.NotificationPriority, extra map[string]any) {
+	if s.notifier == nil {
+		return
+	}
+
+	variables := map[string]any{
+		"amount":      formatAmount(dispute.Amount),
+		"currency":    string(dispute.Currency),
+		"description": dispute.Description,
+		"resolve_by":  dispute.ResolveBy.Time.Format("2006-01-02"),
+	}
+	for k, v := range extra {
+		variables[k] = v
+	}
+
+	correlationID := fmt.Sprintf("dispute-%s-%s", dispute.ID, dispute.Status)
+
+	s.notifier.SendNotificationAsync(&clients.SendNotificationRequest{
+		UserID:        &userID,
+		Recipient:     userID,
+		Channel:       clients.NotificationChannelInApp,
+		Type:          clients.NotificationTypeTransactionAlert,
+		Priority:      priority,
+		TemplateID:    template,
+		Variables:     variables,
+		CorrelationID: &correlationID,
+		SourceService: "transaction",
+		Metadata: map[string]any{
+			"dispute_id":     dispute.ID,
+			"transaction_id": dispute.TransactionID,
+		},
+	}, "transaction")
+}
+
+// publish sends a dispute event to the SSE broker. The parties' user IDs are
+// included so clients can pick out their own disputes.
+func (s *DisputeService) publish(eventType string, dispute *models.Dispute) {
+	if s.events == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"transaction_id":   dispute.TransactionID,
+		"customer_user_id": dispute.CustomerUserID,
+		"amount":           dispute.Amount,
+		"currency":         string(dispute.Currency),
+		"status":           string(dispute.Status),
+		"resolve_by":       dispute.ResolveBy.Time.Format(time.RFC3339),
+	}
+	if dispute.CounterpartyUserID != nil {
+		data["counterparty_user_id"] = *dispute.CounterpartyUserID
+	}
+
+	s.events.PublishDisputeEvent(eventType, dispute.ID, data)
+}
+
+// disputeReference is the reference of the transactions settling a dispute.
+func disputeReference(id, step string) string {
+	return "dispute-" + id + "-" + step
+}
@@ -0,0 +1,584 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// =====================================================================
+// Mocks for Dispute Tests
+// =====================================================================
+
+type mockDisputeRepository struct {
+	disputes     map[string]*models.Dispute
+	locked       map[string]time.Time // Settlement lock expiry by dispute ID
+	transactions map[string]*models.Transaction
+	now          func() time.Time
+}
+
+func newMockDisputeRepository(transactions map[string]*models.Transaction, now func() time.Time) *mockDisputeRepository {
+	return &mockDisputeRepository{
+		disputes:     make(map[string]*models.Dispute),
+		locked:       make(map[string]time.Time),
+		transactions: transactions,
+		now:          now,
+	}
+}
+
+func (m *mockDisputeRepository) Create(ctx context.Context, d *models.Dispute) *errors.Error {
+	for _, existing := range m.disputes {
+		if existing.TransactionID == d.TransactionID {
+			return errors.Conflict("transaction has already been disputed")
+		}
+	}
+	d.ID = uuid.New().String()
+	stored := *d
+	m.disputes[d.ID] = &stored
+	return nil
+}
+
+func (m *mockDisputeRepository) GetByID(ctx context.Context, id string) (*models.Dispute, *errors.Error) {
+	d, ok := m.disputes[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("dispute", id)
+	}
+	copied := *d
+	return &copied, nil
+}
+
+func (m *mockDisputeRepository) List(ctx context.Context, filter *models.DisputeFilter) ([]*models.Dispute, int, *errors.Error) {
+	var disputes []*models.Dispute
+	for _, d := range m.disputes {
+		if filter.UserID == "" || d.HasParty(filter.UserID) {
+			disputes = append(disputes, d)
+		}
+	}
+	return disputes, len(disputes), nil
+}
+
+func (m *mockDisputeRepository) StartInvestigation(ctx context.Context, id, adminID string) (*models.Dispute, *errors.Error) {
+	d, ok := m.disputes[id]
+	if !ok || d.Status != models.DisputeStatusOpen {
+		return nil, errors.Conflict("dispute is no longer open")
+	}
+	d.Status = models.DisputeStatusInvestigating
+	d.AssignedTo = &adminID
+	return m.GetByID(ctx, id)
+}
+
+func (m *mockDisputeRepository) Resolve(ctx context.Context, id string, status models.DisputeStatus, adminID, notes string, lease time.Duration) (*models.Dispute, *errors.Error) {
+	d, ok := m.disputes[id]
+	if !ok || d.Status != models.DisputeStatusInvestigating {
+		return nil, errors.Conflict("dispute is not under investigation")
+	}
+	resolvedAt := sharedModels.NewTimestamp(m.now())
+	d.Status = status
+	d.ResolvedBy = &adminID
+	d.ResolutionNotes = &notes
+	d.ResolvedAt = &resolvedAt
+	if status == models.DisputeStatusResolvedInFavour {
+		m.locked[id] = m.now().Add(lease)
+	}
+	return m.GetByID(ctx, id)
+}
+
+func (m *mockDisputeRepository) ClaimUnsettled(ctx context.Context, limit int, lease time.Duration) ([]*models.Dispute, *errors.Error) {
+	var claimed []*models.Dispute
+	for id, d := range m.disputes {
+		if d.Status == models.DisputeStatusResolvedInFavour && d.SettledAt == nil && !m.locked[id].After(m.now()) {
+			m.locked[id] = m.now().Add(lease)
+			copied := *d
+			claimed = append(claimed, &copied)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *mockDisputeRepository) SaveSettlement(ctx context.Context, d *models.Dispute, retryIn time.Duration) *errors.Error {
+	stored := m.disputes[d.ID]
+	if stored.Status != models.DisputeStatusResolvedInFavour {
+		return nil
+	}
+	stored.CreditTransactionID = d.CreditTransactionID
+	stored.ReversalTransactionID = d.ReversalTransactionID
+	stored.SettledAt = d.SettledAt
+	stored.SettlementAttempts = d.SettlementAttempts
+	stored.Status = d.Status
+	if d.SettledAt == nil {
+		m.locked[d.ID] = m.now().Add(retryIn)
+	} else {
+		delete(m.locked, d.ID)
+	}
+	return nil
+}
+
+func (m *mockDisputeRepository) FlagOverdue(ctx context.Context, limit int) ([]*models.Dispute, *errors.Error) {
+	var flagged []*models.Dispute
+	now := m.now()
+	for _, d := range m.disputes {
+		if d.SLABreachedAt != nil || !d.IsActive() {
+			continue
+		}
+		if (d.Status == models.DisputeStatusOpen && !now.Before(d.AcknowledgeBy.Time)) || !now.Before(d.ResolveBy.Time) {
+			breachedAt := sharedModels.NewTimestamp(now)
+			d.SLABreachedAt = &breachedAt
+			copied := *d
+			flagged = append(flagged, &copied)
+		}
+	}
+	return flagged, nil
+}
+
+func (m *mockDisputeRepository) FindTransactionByReference(ctx context.Context, reference string) (*models.Transaction, *errors.Error) {
+	var latest *models.Transaction
+	for _, tx := range m.transactions {
+		// The mock stamps transactions with random IDs, not creation times, so
+		// a later attempt is one that has not failed
+		if tx.Reference != nil && *tx.Reference == reference && (latest == nil || latest.IsFailed()) {
+			latest = tx
+		}
+	}
+	if latest == nil {
+		return nil, errors.NotFound("transaction with reference " + reference)
+	}
+	return latest, nil
+}
+
+type disputeEvent struct {
+	eventType string
+	id        string
+}
+
+type mockDisputeEvents struct {
+	published []disputeEvent
+}
+
+func (m *mockDisputeEvents) PublishDisputeEvent(eventType string, disputeID string, data map[string]interface{}) {
+	m.published = append(m.published, disputeEvent{eventType: eventType, id: disputeID})
+}
+
+// Compile-time interface checks
+var _ DisputeRepositoryInterface = (*mockDisputeRepository)(nil)
+var _ DisputeTransactions = (*TransactionService)(nil)
+
+// disputeFixture runs disputes against a real TransactionService, so that
+// settlements go through their sagas and the ledger poster.
+type disputeFixture struct {
+	*sagaFixture
+	disputes *DisputeService
+	repo     *mockDisputeRepository
+	notifier *mockNotificationSender
+	events   *mockDisputeEvents
+}
+
+func newDisputeFixture() *disputeFixture {
+	f := &disputeFixture{
+		sagaFixture: newSagaFixture(),
+		notifier:    &mockNotificationSender{},
+		events:      &mockDisputeEvents{},
+	}
+	clock := func() time.Time { return f.now }
+	f.repo = newMockDisputeRepository(f.sagaFixture.repo.transactions, clock)
+	f.disputes = NewDisputeService(f.repo, f.service, f.wallets, f.notifier, f.events)
+	f.disputes.now = clock
+	return f
+}
+
+// completedTransfer creates a completed transfer and returns it with the
+// customer, its sender.
+func (f *disputeFixture) completedTransfer(t *testing.T) (*models.Transaction, string) {
+	t.Helper()
+	tx, err := f.createTransfer(t)
+	if err != nil || tx.Status != models.TransactionStatusCompleted {
+		t.Fatalf("expected completed transfer, got %v (%v)", tx, err)
+	}
+	completedAt := sharedModels.NewTimestamp(f.now)
+	tx.CompletedAt = &completedAt
+	f.wallets.calls = nil
+	return tx, "user-" + *tx.SourceWalletID
+}
+
+func (f *disputeFixture) open(t *testing.T, tx *models.Transaction, userID string) *models.Dispute {
+	t.Helper()
+	dispute, err := f.disputes.Open(context.Background(), userID, tx.ID, &models.OpenDisputeRequest{
+		Reason:   models.DisputeReasonNotReceived,
+		Evidence: "Paid for concert tickets that never arrived",
+	})
+	if err != nil {
+		t.Fatalf("expected dispute to open, got %v", err)
+	}
+	return dispute
+}
+
+// investigated opens a dispute on a completed transfer and starts investigating it.
+func (f *disputeFixture) investigated(t *testing.T) (*models.Dispute, *models.Transaction) {
+	t.Helper()
+	tx, customer := f.completedTransfer(t)
+	dispute := f.open(t, tx, customer)
+	if _, err := f.disputes.StartInvestigation(adminCtx(), dispute.ID); err != nil {
+		t.Fatalf("expected investigation to start, got %v", err)
+	}
+	f.notifier.sent = nil
+	return dispute, tx
+}
+
+func (f *disputeFixture) transactionsOfType(txType models.TransactionType) []*models.Transaction {
+	var found []*models.Transaction
+	for _, tx := range f.sagaFixture.repo.transactions {
+		if tx.Type == txType {
+			found = append(found, tx)
+		}
+	}
+	return found
+}
+
+func (f *disputeFixture) sentTemplates() []string {
+	var templates []string
+	for _, req := range f.notifier.sent {
+		templates = append(templates, req.TemplateID+":"+*req.UserID)
+	}
+	return templates
+}
+
+func adminCtx() context.Context {
+	return context.WithValue(context.Background(), middleware.UserIDKey, "admin-1")
+}
+
+// =====================================================================
+// Dispute Tests
+// =====================================================================
+
+func TestOpenDispute_NotifiesBothParties(t *testing.T) {
+	f := newDisputeFixture()
+	tx, customer := f.completedTransfer(t)
+	counterparty := "user-" + *tx.DestinationWalletID
+
+	dispute := f.open(t, tx, customer)
+
+	if dispute.Status != models.DisputeStatusOpen || dispute.Amount != tx.Amount {
+		t.Errorf("unexpected dispute: %+v", dispute)
+	}
+	if dispute.CounterpartyUserID == nil || *dispute.CounterpartyUserID != counterparty {
+		t.Errorf("expected counterparty %s, got %v", counterparty, dispute.CounterpartyUserID)
+	}
+	if !dispute.AcknowledgeBy.Time.Equal(f.now.Add(models.DisputeAcknowledgeSLA)) ||
+		!dispute.ResolveBy.Time.Equal(f.now.Add(models.DisputeResolutionSLA)) {
+		t.Errorf("unexpected SLA deadlines: %v, %v", dispute.AcknowledgeBy.Time, dispute.ResolveBy.Time)
+	}
+
+	want := []string{
+		disputeOpenedTemplate + ":" + customer,
+		disputeCounterpartyOpenedTemplate + ":" + counterparty,
+	}
+	if got := f.sentTemplates(); !slices.Equal(got, want) {
+		t.Errorf("expected notifications %v, got %v", want, got)
+	}
+	if len(f.events.published) != 1 || f.events.published[0].eventType != "dispute.opened" {
+		t.Errorf("expected dispute.opened event, got %+v", f.events.published)
+	}
+}
+
+func TestOpenDispute_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		prepare  func(f *disputeFixture, tx *models.Transaction, customer string) (string, models.DisputeReason)
+		wantCode errors.ErrorCode
+	}{
+		{
+			name: "not the sender",
+			prepare: func(f *disputeFixture, tx *models.Transaction, customer string) (string, models.DisputeReason) {
+				return "user-" + *tx.DestinationWalletID, models.DisputeReasonOther
+			},
+			wantCode: errors.ErrCodeForbidden,
+		},
+		{
+			name: "not completed",
+			prepare: func(f *disputeFixture, tx *models.Transaction, customer string) (string, models.DisputeReason) {
+				tx.Status = models.TransactionStatusReversed
+				return customer, models.DisputeReasonOther
+			},
+			wantCode: errors.ErrCodeBadRequest,
+		},
+		{
+			name: "outside the dispute window",
+			prepare: func(f *disputeFixture, tx *models.Transaction, customer string) (string, models.DisputeReason) {
+				f.now = f.now.Add(models.DisputeWindow + time.Hour)
+				return customer, models.DisputeReasonOther
+			},
+			wantCode: errors.ErrCodeBadRequest,
+		},
+		{
+			name: "unknown reason",
+			prepare: func(f *disputeFixture, tx *models.Transaction, customer string) (string, models.DisputeReason) {
+				return customer, "changed_my_mind"
+			},
+			wantCode: errors.ErrCodeBadRequest,
+		},
+		{
+			name: "already disputed",
+			prepare: func(f *disputeFixture, tx *models.Transaction, customer string) (string, models.DisputeReason) {
+				f.open(t, tx, customer)
+				return customer, models.DisputeReasonOther
+			},
+			wantCode: errors.ErrCodeConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDisputeFixture()
+			tx, customer := f.completedTransfer(t)
+			userID, reason := tt.prepare(f, tx, customer)
+
+			_, err := f.disputes.Open(context.Background(), userID, tx.ID, &models.OpenDisputeRequest{
+				Reason:   reason,
+				Evidence: "Paid for concert tickets that never arrived",
+			})
+			if err == nil || err.Code != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestResolveDispute_InFavourCreditsCustomerAndRecoversFromCounterparty(t *testing.T) {
+	f := newDisputeFixture()
+	dispute, original := f.investigated(t)
+
+	resolved, err := f.disputes.Resolve(adminCtx(), dispute.ID, &models.ResolveDisputeRequest{InFavour: true, Notes: "Merchant did not deliver"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if resolved.Status != models.DisputeStatusResolvedInFavour || resolved.SettledAt == nil {
+		t.Fatalf("expected settled dispute resolved in favour, got %+v", resolved)
+	}
+	// Provisional credit first, then the reversal holds and debits the counterparty
+	if want := []string{"deposit", "hold", "withdraw"}; !slices.Equal(f.wallets.calls, want) {
+		t.Errorf("expected wallet calls %v, got %v", want, f.wallets.calls)
+	}
+
+	refunds := f.transactionsOfType(models.TransactionTypeRefund)
+	reversals := f.transactionsOfType(models.TransactionTypeReversal)
+	if len(refunds) != 1 || len(reversals) != 1 {
+		t.Fatalf("expected 1 refund and 1 reversal, got %d and %d", len(refunds), len(reversals))
+	}
+	refund, reversal := refunds[0], reversals[0]
+	if *refund.DestinationWalletID != dispute.CustomerWalletID || *refund.ParentTransactionID != original.ID || !refund.IsCompleted() {
+		t.Errorf("unexpected refund: %+v", refund)
+	}
+	if *reversal.SourceWalletID != *dispute.CounterpartyWalletID || *reversal.ParentTransactionID != original.ID || !reversal.IsCompleted() {
+		t.Errorf("unexpected reversal: %+v", reversal)
+	}
+	if *resolved.CreditTransactionID != refund.ID || *resolved.ReversalTransactionID != reversal.ID {
+		t.Errorf("expected settlement transactions linked, got %v, %v", resolved.CreditTransactionID, resolved.ReversalTransactionID)
+	}
+	if original.Status != models.TransactionStatusReversed {
+		t.Errorf("expected disputed transaction reversed, got %s", original.Status)
+	}
+
+	// Transfer, refund and reversal are all in the ledger
+	if len(f.ledger.requests) != 3 {
+		t.Errorf("expected 3 journal entries, got %d", len(f.ledger.requests))
+	}
+	if !slices.Contains(f.outboxEventTypes(), "transaction.reversed") {
+		t.Error("expected transaction.reversed event")
+	}
+
+	want := []string{
+		disputeResolvedTemplate + ":" + dispute.CustomerUserID,
+		disputeCounterpartyResolvedTemplate + ":" + *dispute.CounterpartyUserID,
+	}
+	if got := f.sentTemplates(); !slices.Equal(got, want) {
+		t.Errorf("expected notifications %v, got %v", want, got)
+	}
+}
+
+func TestResolveDispute_FailedRecoveryIsRetried(t *testing.T) {
+	f := newDisputeFixture()
+	dispute, original := f.investigated(t)
+
+	f.wallets.failOn["withdraw"] = errors.BadRequest("insufficient balance")
+	resolved, err := f.disputes.Resolve(adminCtx(), dispute.ID, &models.ResolveDisputeRequest{InFavour: true, Notes: "Merchant did not deliver"})
+	if err != nil {
+		t.Fatalf("expected resolution despite the failed recovery, got %v", err)
+	}
+	if resolved.SettledAt != nil {
+		t.Fatal("expected dispute to stay unsettled")
+	}
+	if original.Status != models.TransactionStatusCompleted {
+		t.Errorf("expected disputed transaction to stay completed, got %s", original.Status)
+	}
+
+	// Not retried before the retry delay
+	if settled, _ := f.disputes.SettleDue(context.Background()); settled != 0 {
+		t.Fatalf("expected no settlement before the retry delay, got %d", settled)
+	}
+
+	delete(f.wallets.failOn, "withdraw")
+	f.now = f.now.Add(disputeSettlementRetry)
+	settled, settleErr := f.disputes.SettleDue(context.Background())
+	if settleErr != nil || settled != 1 {
+		t.Fatalf("expected 1 dispute settled, got %d (%v)", settled, settleErr)
+	}
+
+	// The customer is credited once; the recovery is attempted again
+	if refunds := f.transactionsOfType(models.TransactionTypeRefund); len(refunds) != 1 {
+		t.Errorf("expected 1 refund, got %d", len(refunds))
+	}
+	if reversals := f.transactionsOfType(models.TransactionTypeReversal); len(reversals) != 2 {
+		t.Errorf("expected 2 reversal attempts, got %d", len(reversals))
+	}
+	if original.Status != models.TransactionStatusReversed {
+		t.Errorf("expected disputed transaction reversed, got %s", original.Status)
+	}
+	if stored, _ := f.repo.GetByID(context.Background(), dispute.ID); stored.SettledAt == nil {
+		t.Error("expected stored dispute settled")
+	}
+}
+
+func TestSettleDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	f := newDisputeFixture()
+	dispute, original := f.investigated(t)
+
+	f.wallets.failOn["withdraw"] = errors.BadRequest("insufficient balance")
+	if _, err := f.disputes.Resolve(adminCtx(), dispute.ID, &models.ResolveDisputeRequest{InFavour: true, Notes: "Merchant did not deliver"}); err != nil {
+		t.Fatalf("expected resolution despite the failed recovery, got %v", err)
+	}
+
+	for i := 0; i < disputeMaxSettlementAttempts; i++ {
+		f.now = f.now.Add(disputeSettlementRetry)
+		if _, err := f.disputes.SettleDue(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	// One refund, then recoveries until the attempts ran out
+	refunds := f.transactionsOfType(models.TransactionTypeRefund)
+	reversals := f.transactionsOfType(models.TransactionTypeReversal)
+	if len(refunds)+len(reversals) != disputeMaxSettlementAttempts || len(refunds) != 1 {
+		t.Errorf("expected 1 refund and %d reversal attempts, got %d and %d", disputeMaxSettlementAttempts-1, len(refunds), len(reversals))
+	}
+
+	stored, _ := f.repo.GetByID(context.Background(), dispute.ID)
+	if stored.Status != models.DisputeStatusSettlementFailed || stored.SettledAt != nil {
+		t.Errorf("expected unsettled dispute with failed settlement, got %s", stored.Status)
+	}
+	if original.Status != models.TransactionStatusCompleted {
+		t.Errorf("expected disputed transaction to stay completed, got %s", original.Status)
+	}
+	if last := f.events.published[len(f.events.published)-1]; last.eventType != "dispute.settlement_failed" {
+		t.Errorf("expected dispute.settlement_failed event, got %s", last.eventType)
+	}
+
+	// No longer retried, even once the counterparty can pay
+	delete(f.wallets.failOn, "withdraw")
+	f.now = f.now.Add(disputeSettlementRetry)
+	if settled, _ := f.disputes.SettleDue(context.Background()); settled != 0 {
+		t.Errorf("expected no settlement, got %d", settled)
+	}
+	if after := f.transactionsOfType(models.TransactionTypeReversal); len(after) != len(reversals) {
+		t.Errorf("expected no new reversal attempt, got %d", len(after)-len(reversals))
+	}
+}
+
+func TestResolveDispute_RejectedMovesNoMoney(t *testing.T) {
+	f := newDisputeFixture()
+	dispute, original := f.investigated(t)
+
+	resolved, err := f.disputes.Resolve(adminCtx(), dispute.ID, &models.ResolveDisputeRequest{InFavour: false, Notes: "Delivery confirmed by courier"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if resolved.Status != models.DisputeStatusRejected || *resolved.ResolvedBy != "admin-1" {
+		t.Errorf("unexpected dispute: %+v", resolved)
+	}
+	if len(f.wallets.calls) != 0 || original.Status != models.TransactionStatusCompleted {
+		t.Errorf("expected no money moved, got wallet calls %v", f.wallets.calls)
+	}
+
+	want := []string{
+		disputeRejectedTemplate + ":" + dispute.CustomerUserID,
+		disputeCounterpartyRejectedTemplate + ":" + *dispute.CounterpartyUserID,
+	}
+	if got := f.sentTemplates(); !slices.Equal(got, want) {
+		t.Errorf("expected notifications %v, got %v", want, got)
+	}
+	if f.notifier.sent[0].Variables["notes"] != "Delivery confirmed by courier" {
+		t.Errorf("expected resolution notes in the customer notification, got %v", f.notifier.sent[0].Variables)
+	}
+}
+
+func TestResolveDispute_RequiresInvestigation(t *testing.T) {
+	f := newDisputeFixture()
+	tx, customer := f.completedTransfer(t)
+	dispute := f.open(t, tx, customer)
+
+	_, err := f.disputes.Resolve(adminCtx(), dispute.ID, &models.ResolveDisputeRequest{InFavour: true, Notes: "Looks valid"})
+	if err == nil || err.Code != errors.ErrCodeConflict {
+		t.Fatalf("expected conflict for an open dispute, got %v", err)
+	}
+	if len(f.wallets.calls) != 0 {
+		t.Errorf("expected no money moved, got %v", f.wallets.calls)
+	}
+}
+
+func TestResolveDispute_TransactionReversedMeanwhile(t *testing.T) {
+	f := newDisputeFixture()
+	dispute, original := f.investigated(t)
+	original.Status = models.TransactionStatusReversed
+
+	_, err := f.disputes.Resolve(adminCtx(), dispute.ID, &models.ResolveDisputeRequest{InFavour: true, Notes: "Looks valid"})
+	if err == nil || err.Code != errors.ErrCodeConflict {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+	if stored, _ := f.repo.GetByID(context.Background(), dispute.ID); stored.Status != models.DisputeStatusInvestigating {
+		t.Errorf("expected dispute to stay under investigation, got %s", stored.Status)
+	}
+}
+
+func TestFlagOverdue_FlagsMissedDeadlinesOnce(t *testing.T) {
+	f := newDisputeFixture()
+	tx, customer := f.completedTransfer(t)
+	dispute := f.open(t, tx, customer)
+
+	if flagged, _ := f.disputes.FlagOverdue(context.Background()); flagged != 0 {
+		t.Fatalf("expected nothing flagged before the deadline, got %d", flagged)
+	}
+
+	f.now = f.now.Add(models.DisputeAcknowledgeSLA)
+	flagged, err := f.disputes.FlagOverdue(context.Background())
+	if err != nil || flagged != 1 {
+		t.Fatalf("expected 1 dispute flagged, got %d (%v)", flagged, err)
+	}
+	if stored, _ := f.repo.GetByID(context.Background(), dispute.ID); stored.SLABreachedAt == nil {
+		t.Error("expected SLA breach recorded")
+	}
+	if flagged, _ := f.disputes.FlagOverdue(context.Background()); flagged != 0 {
+		t.Errorf("expected a breach to be flagged once, got %d", flagged)
+	}
+}
+
+func TestGetDispute_OnlyParties(t *testing.T) {
+	f := newDisputeFixture()
+	tx, customer := f.completedTransfer(t)
+	dispute := f.open(t, tx, customer)
+
+	for _, userID := range []string{customer, *dispute.CounterpartyUserID} {
+		if _, err := f.disputes.Get(context.Background(), userID, dispute.ID); err != nil {
+			t.Errorf("expected %s to see the dispute, got %v", userID, err)
+		}
+	}
+	if _, err := f.disputes.Get(context.Background(), "someone-else", dispute.ID); err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found for another user, got %v", err)
+	}
+}
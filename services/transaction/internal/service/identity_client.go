@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+)
+
+// IdentityClient handles communication with the Identity service.
+type IdentityClient struct {
+	*clients.BaseClient
+}
+
+// NewIdentityClient creates a new Identity service client.
+func NewIdentityClient(baseURL string) *IdentityClient {
+	return &IdentityClient{
+		BaseClient: clients.NewBaseClient(baseURL, clients.DefaultTimeout),
+	}
+}
+
+// UserInfo represents basic user information from the Identity service.
+type UserInfo struct {
+	ID       string `json:"id"`
+	Phone    string `json:"phone"`
+	FullName string `json:"full_name"`
+}
+
+// userAuthHeaders forwards the caller's JWT from the context, for endpoints
+// that act on behalf of the authenticated user.
+func userAuthHeaders(ctx context.Context) map[string]string {
+	if token, ok := ctx.Value(middleware.JWTTokenKey).(string); ok && token != "" {
+		return map[string]string{"Authorization": "Bearer " + token}
+	}
+	return nil
+}
+
+// GetCurrentUser retrieves the authenticated user.
+func (c *IdentityClient) GetCurrentUser(ctx context.Context) (*UserInfo, *errors.Error) {
+	var result UserInfo
+	if err := c.GetWithHeaders(ctx, "/api/v1/users/me", &result, userAuthHeaders(ctx)); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// LookupUserByPhone looks up a user by phone number on behalf of the authenticated user.
+func (c *IdentityClient) LookupUserByPhone(ctx context.Context, phone string) (*UserInfo, *errors.Error) {
+	path := fmt.Sprintf("/api/v1/users/lookup?phone=%s", url.QueryEscape(phone))
+
+	var result UserInfo
+	if err := c.GetWithHeaders(ctx, path, &result, userAuthHeaders(ctx)); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
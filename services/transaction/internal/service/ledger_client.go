@@ -2,11 +2,12 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/vnykmshr/nivo/shared/clients"
 	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 )
 
 // LedgerClient handles communication with the Ledger service.
@@ -14,10 +15,10 @@ type LedgerClient struct {
 	*clients.BaseClient
 }
 
-// NewLedgerClient creates a new Ledger service client.
-func NewLedgerClient(baseURL string) *LedgerClient {
+// NewLedgerClient creates a Ledger service client authenticated with service tokens.
+func NewLedgerClient(baseURL string, tokens clients.ServiceTokenSource) *LedgerClient {
 	return &LedgerClient{
-		BaseClient: clients.NewBaseClient(baseURL, clients.DefaultTimeout),
+		BaseClient: clients.NewInternalClient(baseURL, clients.DefaultTimeout, tokens, sharedjwt.ServiceLedger),
 	}
 }
 
@@ -54,38 +55,29 @@ type JournalEntry struct {
 	UpdatedAt     time.Time      `json:"updated_at"`
 }
 
-// CreateJournalEntry creates a new journal entry in the ledger.
-func (c *LedgerClient) CreateJournalEntry(ctx context.Context, req *CreateJournalEntryRequest) (*JournalEntry, *errors.Error) {
-	var result JournalEntry
-	if err := c.Post(ctx, "/api/v1/journal-entries", req, &result); err != nil {
+// LedgerAccount represents a ledger account.
+type LedgerAccount struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// GetAccountByCode retrieves a ledger account by its chart-of-accounts code (internal endpoint).
+func (c *LedgerClient) GetAccountByCode(ctx context.Context, code string) (*LedgerAccount, *errors.Error) {
+	var result LedgerAccount
+	if err := c.Get(ctx, "/internal/v1/accounts/by-code/"+url.PathEscape(code), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// PostJournalEntry posts a draft journal entry to the ledger (finalizes it).
-func (c *LedgerClient) PostJournalEntry(ctx context.Context, entryID string) (*JournalEntry, *errors.Error) {
+// RecordJournalEntry creates and posts a journal entry through the ledger's internal endpoint.
+// The ledger deduplicates on reference_type and reference_id, so the call is safe to retry.
+func (c *LedgerClient) RecordJournalEntry(ctx context.Context, req *CreateJournalEntryRequest) (*JournalEntry, *errors.Error) {
 	var result JournalEntry
-	path := fmt.Sprintf("/api/v1/journal-entries/%s/post", entryID)
-	if err := c.Post(ctx, path, nil, &result); err != nil {
+	if err := c.Post(ctx, "/internal/v1/journal-entries", req, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
-
-// CreateAndPostJournalEntry creates a journal entry and posts it in one operation.
-func (c *LedgerClient) CreateAndPostJournalEntry(ctx context.Context, req *CreateJournalEntryRequest) (*JournalEntry, *errors.Error) {
-	// Create the draft entry
-	entry, createErr := c.CreateJournalEntry(ctx, req)
-	if createErr != nil {
-		return nil, createErr
-	}
-
-	// Post the entry to finalize it
-	postedEntry, postErr := c.PostJournalEntry(ctx, entry.ID)
-	if postErr != nil {
-		return entry, postErr // Return draft entry even if posting fails
-	}
-
-	return postedEntry, nil
-}
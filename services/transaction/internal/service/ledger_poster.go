@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/logger"
+)
+
+// DefaultSettlementAccountCode is the ledger account money enters and leaves
+// the platform through: deposits credit it and withdrawals debit it.
+const DefaultSettlementAccountCode = "2100" // Customer Deposits
+
+// LedgerTransactionStore reads transactions and records their ledger entries.
+type LedgerTransactionStore interface {
+	GetByID(ctx context.Context, id string) (*models.Transaction, *errors.Error)
+	UpdateLedgerEntry(ctx context.Context, id, ledgerEntryID string) *errors.Error
+}
+
+// WalletInfoReader resolves wallets to their ledger accounts.
+type WalletInfoReader interface {
+	GetWalletInfo(ctx context.Context, walletID string) (*WalletInfo, *errors.Error)
+}
+
+// JournalRecorder records journal entries in the ledger, idempotently per reference.
+type JournalRecorder interface {
+	RecordJournalEntry(ctx context.Context, req *CreateJournalEntryRequest) (*JournalEntry, *errors.Error)
+	GetAccountByCode(ctx context.Context, code string) (*LedgerAccount, *errors.Error)
+}
+
+// LedgerPoster records transactions in the ledger and links the journal entry
+// to the transaction. Posting is idempotent: the ledger deduplicates on the
+// transaction reference and a transaction already linked is skipped.
+type LedgerPoster struct {
+	transactions   LedgerTransactionStore
+	wallets        WalletInfoReader
+	ledger         JournalRecorder
+	settlementCode string
+	logger         *logger.Logger
+
+	// settlementAccountID caches the resolved settlement account
+	mu                  sync.Mutex
+	settlementAccountID string
+}
+
+// NewLedgerPoster creates a new ledger poster. Deposits and withdrawals are
+// booked against the ledger account with the given settlement code.
+func NewLedgerPoster(transactions LedgerTransactionStore, wallets WalletInfoReader, ledger JournalRecorder, settlementCode string) *LedgerPoster {
+	return &LedgerPoster{
+		transactions:   transactions,
+		wallets:        wallets,
+		ledger:         ledger,
+		settlementCode: settlementCode,
+		logger:         logger.NewDefault("transaction"),
+	}
+}
+
+// Post records the transaction in the ledger unless it already has a journal entry.
+func (p *LedgerPoster) Post(ctx context.Context, transactionID string) *errors.Error {
+	if p.ledger == nil || p.wallets == nil {
+		return errors.Internal("ledger or wallet client not configured")
+	}
+
+	transaction, err := p.transactions.GetByID(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+
+	if transaction.LedgerEntryID != nil {
+		return nil
+	}
+
+	journalReq, buildErr := p.buildJournalEntry(ctx, transaction)
+	if buildErr != nil {
+		return buildErr
+	}
+
+	entry, ledgerErr := p.ledger.RecordJournalEntry(ctx, journalReq)
+	if ledgerErr != nil {
+		return errors.Wrap(ledgerErr, ledgerErr.Code, "failed to record journal entry")
+	}
+
+	if updateErr := p.transactions.UpdateLedgerEntry(ctx, transaction.ID, entry.ID); updateErr != nil {
+		return updateErr
+	}
+
+	p.logger.With(map[string]interface{}{
+		"transaction_id":   transaction.ID,
+		"journal_entry_id": entry.ID,
+		"entry_number":     entry.EntryNumber,
+	}).Info("Ledger journal entry recorded for transaction")
+
+	return nil
+}
+
+// buildJournalEntry builds the double-entry journal entry for a transaction.
+// Wallet accounts are assets, so money leaving a wallet is a credit and money
+// arriving is a debit; the settlement account is the other side of deposits
+// and refunds (money in) and of withdrawals and reversals (money out).
+func (p *LedgerPoster) buildJournalEntry(ctx context.Context, transaction *models.Transaction) (*CreateJournalEntryRequest, *errors.Error) {
+	var debitAccountID, creditAccountID, debitMemo, creditMemo string
+	metadata := map[string]any{"transaction_id": transaction.ID}
+
+	switch transaction.Type {
+	case models.TransactionTypeTransfer:
+		if transaction.SourceWalletID == nil || transaction.DestinationWalletID == nil {
+			return nil, errors.BadRequest("transfer must have both source and destination wallets")
+		}
+		source, err := p.walletAccount(ctx, *transaction.SourceWalletID, "source")
+		if err != nil {
+			return nil, err
+		}
+		dest, err := p.walletAccount(ctx, *transaction.DestinationWalletID, "destination")
+		if err != nil {
+			return nil, err
+		}
+		debitAccountID, debitMemo = dest, fmt.Sprintf("Transfer from %s", *transaction.SourceWalletID)
+		creditAccountID, creditMemo = source, fmt.Sprintf("Transfer to %s", *transaction.DestinationWalletID)
+		metadata["source_wallet_id"] = *transaction.SourceWalletID
+		metadata["destination_wallet_id"] = *transaction.DestinationWalletID
+
+	case models.TransactionTypeDeposit, models.TransactionTypeRefund:
+		if transaction.DestinationWalletID == nil {
+			return nil, errors.BadRequest(fmt.Sprintf("%s must have a destination wallet", transaction.Type))
+		}
+		wallet, err := p.walletAccount(ctx, *transaction.DestinationWalletID, "destination")
+		if err != nil {
+			return nil, err
+		}
+		settlement, err := p.settlementAccount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		label := transactionTypeLabel(transaction.Type)
+		debitAccountID, debitMemo = wallet, label
+		creditAccountID, creditMemo = settlement, fmt.Sprintf("%s to %s", label, *transaction.DestinationWalletID)
+		metadata["destination_wallet_id"] = *transaction.DestinationWalletID
+
+	case models.TransactionTypeWithdrawal, models.TransactionTypeReversal:
+		if transaction.SourceWalletID == nil {
+			return nil, errors.BadRequest(fmt.Sprintf("%s must have a source wallet", transaction.Type))
+		}
+		wallet, err := p.walletAccount(ctx, *transaction.SourceWalletID, "source")
+		if err != nil {
+			return nil, err
+		}
+		settlement, err := p.settlementAccount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		label := transactionTypeLabel(transaction.Type)
+		debitAccountID, debitMemo = settlement, fmt.Sprintf("%s from %s", label, *transaction.SourceWalletID)
+		creditAccountID, creditMemo = wallet, label
+		metadata["source_wallet_id"] = *transaction.SourceWalletID
+
+	default:
+		return nil, errors.BadRequest(fmt.Sprintf("ledger posting not supported for %s transactions", transaction.Type))
+	}
+
+	return &CreateJournalEntryRequest{
+		Type:          "standard",
+		Description:   fmt.Sprintf("%s: %s", transactionTypeLabel(transaction.Type), transaction.Description),
+		ReferenceType: "transaction",
+		ReferenceID:   transaction.ID,
+		Lines: []LedgerLine{
+			{
+				AccountID:    debitAccountID,
+				DebitAmount:  transaction.Amount,
+				CreditAmount: 0,
+				Description:  debitMemo,
+			},
+			{
+				AccountID:    creditAccountID,
+				DebitAmount:  0,
+				CreditAmount: transaction.Amount,
+				Description:  creditMemo,
+			},
+		},
+		Metadata: metadata,
+	}, nil
+}
+
+func transactionTypeLabel(t models.TransactionType) string {
+	switch t {
+	case models.TransactionTypeDeposit:
+		return "Deposit"
+	case models.TransactionTypeWithdrawal:
+		return "Withdrawal"
+	case models.TransactionTypeRefund:
+		return "Refund"
+	case models.TransactionTypeReversal:
+		return "Reversal"
+	default:
+		return "Transfer"
+	}
+}
+
+// walletAccount resolves a wallet to its ledger account ID.
+func (p *LedgerPoster) walletAccount(ctx context.Context, walletID, role string) (string, *errors.Error) {
+	info, err := p.wallets.GetWalletInfo(ctx, walletID)
+	if err != nil {
+		return "", errors.Wrap(err, err.Code, fmt.Sprintf("failed to get %s wallet info", role))
+	}
+	if info.LedgerAccountID == "" {
+		return "", errors.BadRequest("wallet missing ledger account ID")
+	}
+	return info.LedgerAccountID, nil
+}
+
+// settlementAccount resolves the settlement account code to an account ID once.
+func (p *LedgerPoster) settlementAccount(ctx context.Context) (string, *errors.Error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.settlementAccountID != "" {
+		return p.settlementAccountID, nil
+	}
+
+	account, err := p.ledger.GetAccountByCode(ctx, p.settlementCode)
+	if err != nil {
+		return "", errors.Wrap(err, err.Code, fmt.Sprintf("failed to resolve settlement account %s", p.settlementCode))
+	}
+
+	p.settlementAccountID = account.ID
+	return account.ID, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// =====================================================================
+// Mocks for Ledger Poster Tests
+// =====================================================================
+
+type mockOutboxTransactionStore struct {
+	transactions  map[string]*models.Transaction
+	ledgerEntries map[string]string
+}
+
+func (m *mockOutboxTransactionStore) GetByID(ctx context.Context, id string) (*models.Transaction, *errors.Error) {
+	tx, ok := m.transactions[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("transaction", id)
+	}
+	return tx, nil
+}
+
+func (m *mockOutboxTransactionStore) UpdateLedgerEntry(ctx context.Context, id, ledgerEntryID string) *errors.Error {
+	m.ledgerEntries[id] = ledgerEntryID
+	if tx, ok := m.transactions[id]; ok {
+		tx.LedgerEntryID = &ledgerEntryID
+	}
+	return nil
+}
+
+type mockWalletInfoReader struct {
+	wallets map[string]*WalletInfo
+}
+
+func (m *mockWalletInfoReader) GetWalletInfo(ctx context.Context, walletID string) (*WalletInfo, *errors.Error) {
+	info, ok := m.wallets[walletID]
+	if !ok {
+		return nil, errors.NotFoundWithID("wallet", walletID)
+	}
+	return info, nil
+}
+
+type mockJournalRecorder struct {
+	requests []*CreateJournalEntryRequest
+	err      *errors.Error
+}
+
+func (m *mockJournalRecorder) RecordJournalEntry(ctx context.Context, req *CreateJournalEntryRequest) (*JournalEntry, *errors.Error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.requests = append(m.requests, req)
+	return &JournalEntry{ID: uuid.New().String(), EntryNumber: "JE-2025-00001", Status: "posted"}, nil
+}
+
+func (m *mockJournalRecorder) GetAccountByCode(ctx context.Context, code string) (*LedgerAccount, *errors.Error) {
+	return &LedgerAccount{ID: "ledger-settlement-" + code, Code: code}, nil
+}
+
+// =====================================================================
+// Test Helpers
+// =====================================================================
+
+// Compile-time interface checks
+var _ LedgerTransactionStore = (*mockOutboxTransactionStore)(nil)
+
+type posterFixture struct {
+	poster       *LedgerPoster
+	transactions *mockOutboxTransactionStore
+	ledger       *mockJournalRecorder
+	transfer     *models.Transaction
+}
+
+func newPosterFixture() *posterFixture {
+	sourceWalletID := uuid.New().String()
+	destWalletID := uuid.New().String()
+	transfer := &models.Transaction{
+		ID:                  uuid.New().String(),
+		Type:                models.TransactionTypeTransfer,
+		Status:              models.TransactionStatusCompleted,
+		SourceWalletID:      &sourceWalletID,
+		DestinationWalletID: &destWalletID,
+		Amount:              25000,
+		Currency:            sharedModels.INR,
+		Description:         "Rent share",
+	}
+
+	f := &posterFixture{
+		transactions: &mockOutboxTransactionStore{
+			transactions:  map[string]*models.Transaction{transfer.ID: transfer},
+			ledgerEntries: make(map[string]string),
+		},
+		ledger:   &mockJournalRecorder{},
+		transfer: transfer,
+	}
+
+	wallets := &mockWalletInfoReader{wallets: map[string]*WalletInfo{
+		sourceWalletID: {ID: sourceWalletID, LedgerAccountID: "ledger-source"},
+		destWalletID:   {ID: destWalletID, LedgerAccountID: "ledger-dest"},
+	}}
+
+	f.poster = NewLedgerPoster(f.transactions, wallets, f.ledger, DefaultSettlementAccountCode)
+	return f
+}
+
+// =====================================================================
+// Post Tests
+// =====================================================================
+
+func TestLedgerPoster_PostsTransfer(t *testing.T) {
+	f := newPosterFixture()
+
+	if err := f.poster.Post(context.Background(), f.transfer.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(f.ledger.requests) != 1 {
+		t.Fatalf("expected 1 journal entry, got %d", len(f.ledger.requests))
+	}
+	req := f.ledger.requests[0]
+	if req.ReferenceType != "transaction" || req.ReferenceID != f.transfer.ID {
+		t.Errorf("expected transaction reference %s, got %s/%s", f.transfer.ID, req.ReferenceType, req.ReferenceID)
+	}
+	if req.Type != "standard" {
+		t.Errorf("expected standard entry, got %s", req.Type)
+	}
+
+	// Wallet accounts are assets: money leaving the source is a credit
+	for _, line := range req.Lines {
+		switch line.AccountID {
+		case "ledger-source":
+			if line.CreditAmount != 25000 || line.DebitAmount != 0 {
+				t.Errorf("expected source credited 25000, got debit %d credit %d", line.DebitAmount, line.CreditAmount)
+			}
+		case "ledger-dest":
+			if line.DebitAmount != 25000 || line.CreditAmount != 0 {
+				t.Errorf("expected destination debited 25000, got debit %d credit %d", line.DebitAmount, line.CreditAmount)
+			}
+		default:
+			t.Errorf("unexpected ledger account %s", line.AccountID)
+		}
+	}
+
+	if f.transactions.ledgerEntries[f.transfer.ID] == "" {
+		t.Error("expected ledger entry ID to be recorded on the transaction")
+	}
+}
+
+func TestLedgerPoster_SkipsTransactionAlreadyInLedger(t *testing.T) {
+	f := newPosterFixture()
+	entryID := uuid.New().String()
+	f.transfer.LedgerEntryID = &entryID
+
+	if err := f.poster.Post(context.Background(), f.transfer.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(f.ledger.requests) != 0 {
+		t.Errorf("expected no journal entry for a transaction already in the ledger, got %d", len(f.ledger.requests))
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/logger"
+)
+
+// Outbox relay tuning.
+const (
+	outboxBatchSize   = 50
+	outboxLease       = 2 * time.Minute // Longer than the slowest single delivery
+	outboxMaxAttempts = 10
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = 10 * time.Minute
+)
+
+// transactionEventTopic is the SSE topic transaction events are published on.
+const transactionEventTopic = "transactions"
+
+// OutboxRepositoryInterface defines the interface for outbox repository operations.
+type OutboxRepositoryInterface interface {
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxMessage, *errors.Error)
+	MarkDelivered(ctx context.Context, id string) *errors.Error
+	MarkFailed(ctx context.Context, id, lastError string, nextAttemptAt time.Time) *errors.Error
+	MarkDead(ctx context.Context, id, lastError string) *errors.Error
+}
+
+// EventSink publishes events synchronously so delivery failures can be retried.
+type EventSink interface {
+	PublishEvent(topic, eventType string, data map[string]interface{}) error
+}
+
+// OutboxRelay delivers outbox messages written alongside transaction status
+// changes. Delivery is at least once: a message is retried with exponential
+// backoff until it succeeds or runs out of attempts, after which it is marked
+// dead for manual follow-up.
+type OutboxRelay struct {
+	outboxRepo OutboxRepositoryInterface
+	events     EventSink
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// NewOutboxRelay creates a new outbox relay.
+func NewOutboxRelay(outboxRepo OutboxRepositoryInterface, events EventSink) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		events:     events,
+		logger:     logger.NewDefault("transaction"),
+		now:        time.Now,
+	}
+}
+
+// RelayPending delivers due messages batch by batch until a batch comes back
+// short, returning the totals delivered and failed.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, int, *errors.Error) {
+	totalDelivered, totalFailed := 0, 0
+	for {
+		delivered, failed, err := r.RelayBatch(ctx)
+		totalDelivered += delivered
+		totalFailed += failed
+		if err != nil {
+			return totalDelivered, totalFailed, err
+		}
+		if delivered+failed < outboxBatchSize || ctx.Err() != nil {
+			return totalDelivered, totalFailed, nil
+		}
+	}
+}
+
+// RelayBatch claims one batch of due messages and attempts to deliver each.
+// It returns the number delivered and the number that failed.
+func (r *OutboxRelay) RelayBatch(ctx context.Context) (int, int, *errors.Error) {
+	messages, err := r.outboxRepo.ClaimDue(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	delivered, failed := 0, 0
+	for _, msg := range messages {
+		if deliverErr := r.deliver(ctx, msg); deliverErr != nil {
+			failed++
+			if markErr := r.recordFailure(ctx, msg, deliverErr); markErr != nil {
+				return delivered, failed, markErr
+			}
+			continue
+		}
+
+		delivered++
+		if markErr := r.outboxRepo.MarkDelivered(ctx, msg.ID); markErr != nil {
+			// The message will be redelivered once its lease expires
+			return delivered, failed, markErr
+		}
+	}
+
+	return delivered, failed, nil
+}
+
+func (r *OutboxRelay) deliver(ctx context.Context, msg *models.OutboxMessage) error {
+	switch msg.Kind {
+	case models.OutboxKindEvent:
+		return r.publishEvent(msg)
+	default:
+		return fmt.Errorf("unknown outbox message kind %q", msg.Kind)
+	}
+}
+
+// recordFailure schedules the next attempt, or marks the message dead once
+// it has used all of its attempts.
+func (r *OutboxRelay) recordFailure(ctx context.Context, msg *models.OutboxMessage, deliverErr error) *errors.Error {
+	log := r.logger.WithError(deliverErr).With(map[string]interface{}{
+		"outbox_id":      msg.ID,
+		"transaction_id": msg.TransactionID,
+		"kind":           string(msg.Kind),
+		"attempts":       msg.Attempts,
+	})
+
+	if msg.Attempts >= outboxMaxAttempts {
+		log.Error("Outbox message exhausted its attempts - manual follow-up needed")
+		return r.outboxRepo.MarkDead(ctx, msg.ID, deliverErr.Error())
+	}
+
+	log.Warn("Outbox delivery failed, will retry")
+	return r.outboxRepo.MarkFailed(ctx, msg.ID, deliverErr.Error(), r.now().Add(outboxBackoff(msg.Attempts)))
+}
+
+// outboxBackoff returns the delay after the given number of failed attempts.
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return delay
+}
+
+// publishEvent publishes a transaction event. The message ID is sent as
+// event_id so consumers can discard redeliveries.
+func (r *OutboxRelay) publishEvent(msg *models.OutboxMessage) error {
+	if r.events == nil {
+		return fmt.Errorf("event publisher not configured")
+	}
+	if msg.EventType == nil {
+		return fmt.Errorf("event message has no event type")
+	}
+
+	data := make(map[string]interface{}, len(msg.Payload)+2)
+	for k, v := range msg.Payload {
+		data[k] = v
+	}
+	data["transaction_id"] = msg.TransactionID
+	data["event_id"] = msg.ID
+
+	return r.events.PublishEvent(transactionEventTopic, *msg.EventType, data)
+}
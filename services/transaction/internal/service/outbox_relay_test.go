@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// =====================================================================
+// Mocks for Outbox Relay Tests
+// =====================================================================
+
+type mockOutboxRepository struct {
+	due         []*models.OutboxMessage
+	delivered   []string
+	failed      map[string]time.Time
+	dead        []string
+	lastErrors  map[string]string
+	claimedWith time.Duration
+}
+
+func newMockOutboxRepository() *mockOutboxRepository {
+	return &mockOutboxRepository{
+		failed:     make(map[string]time.Time),
+		lastErrors: make(map[string]string),
+	}
+}
+
+func (m *mockOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxMessage, *errors.Error) {
+	m.claimedWith = lease
+	n := min(limit, len(m.due))
+	claimed := m.due[:n]
+	m.due = m.due[n:]
+	for _, msg := range claimed {
+		msg.Attempts++
+	}
+	return claimed, nil
+}
+
+func (m *mockOutboxRepository) MarkDelivered(ctx context.Context, id string) *errors.Error {
+	m.delivered = append(m.delivered, id)
+	return nil
+}
+
+func (m *mockOutboxRepository) MarkFailed(ctx context.Context, id, lastError string, nextAttemptAt time.Time) *errors.Error {
+	m.failed[id] = nextAttemptAt
+	m.lastErrors[id] = lastError
+	return nil
+}
+
+func (m *mockOutboxRepository) MarkDead(ctx context.Context, id, lastError string) *errors.Error {
+	m.dead = append(m.dead, id)
+	m.lastErrors[id] = lastError
+	return nil
+}
+
+type publishedEvent struct {
+	topic     string
+	eventType string
+	data      map[string]interface{}
+}
+
+type mockEventSink struct {
+	events []publishedEvent
+	err    error
+}
+
+func (m *mockEventSink) PublishEvent(topic, eventType string, data map[string]interface{}) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.events = append(m.events, publishedEvent{topic: topic, eventType: eventType, data: data})
+	return nil
+}
+
+// =====================================================================
+// Test Helpers
+// =====================================================================
+
+// Compile-time interface checks
+var _ OutboxRepositoryInterface = (*mockOutboxRepository)(nil)
+
+type relayFixture struct {
+	relay    *OutboxRelay
+	outbox   *mockOutboxRepository
+	events   *mockEventSink
+	transfer *models.Transaction
+	now      time.Time
+}
+
+func newRelayFixture() *relayFixture {
+	f := &relayFixture{
+		outbox:   newMockOutboxRepository(),
+		events:   &mockEventSink{},
+		transfer: &models.Transaction{ID: uuid.New().String()},
+		now:      time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	f.relay = NewOutboxRelay(f.outbox, f.events)
+	f.relay.now = func() time.Time { return f.now }
+	return f
+}
+
+func outboxMessage(transactionID string, msg *models.OutboxMessage) *models.OutboxMessage {
+	msg.ID = uuid.New().String()
+	msg.TransactionID = transactionID
+	msg.Status = models.OutboxStatusPending
+	return msg
+}
+
+// =====================================================================
+// RelayBatch Tests
+// =====================================================================
+
+func TestRelayBatch_PublishesEventWithIDs(t *testing.T) {
+	f := newRelayFixture()
+	msg := outboxMessage(f.transfer.ID, models.NewOutboxEvent("transaction.completed", map[string]interface{}{
+		"amount": float64(25000),
+	}))
+	f.outbox.due = []*models.OutboxMessage{msg}
+
+	if _, _, err := f.relay.RelayBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(f.events.events) != 1 {
+		t.Fatalf("expected 1 published event, got %d", len(f.events.events))
+	}
+	event := f.events.events[0]
+	if event.topic != "transactions" || event.eventType != "transaction.completed" {
+		t.Errorf("expected transactions/transaction.completed, got %s/%s", event.topic, event.eventType)
+	}
+	if event.data["transaction_id"] != f.transfer.ID {
+		t.Errorf("expected transaction_id %s, got %v", f.transfer.ID, event.data["transaction_id"])
+	}
+	if event.data["event_id"] != msg.ID {
+		t.Errorf("expected event_id %s, got %v", msg.ID, event.data["event_id"])
+	}
+	if event.data["amount"] != float64(25000) {
+		t.Errorf("expected payload to be preserved, got %v", event.data["amount"])
+	}
+}
+
+func TestRelayBatch_FailureSchedulesRetryWithBackoff(t *testing.T) {
+	f := newRelayFixture()
+	f.events.err = fmt.Errorf("gateway unavailable")
+	msg := outboxMessage(f.transfer.ID, models.NewOutboxEvent("transaction.created", nil))
+	msg.Attempts = 2 // third attempt once claimed
+	f.outbox.due = []*models.OutboxMessage{msg}
+
+	delivered, failed, err := f.relay.RelayBatch(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if delivered != 0 || failed != 1 {
+		t.Fatalf("expected 0 delivered and 1 failed, got %d and %d", delivered, failed)
+	}
+
+	next, ok := f.outbox.failed[msg.ID]
+	if !ok {
+		t.Fatal("expected message to be scheduled for retry")
+	}
+	if want := f.now.Add(20 * time.Second); !next.Equal(want) {
+		t.Errorf("expected next attempt at %v, got %v", want, next)
+	}
+	if f.outbox.lastErrors[msg.ID] != "gateway unavailable" {
+		t.Errorf("expected last error to be recorded, got %q", f.outbox.lastErrors[msg.ID])
+	}
+	if len(f.outbox.dead) != 0 {
+		t.Errorf("expected no dead messages, got %v", f.outbox.dead)
+	}
+}
+
+func TestRelayBatch_MarksDeadAfterMaxAttempts(t *testing.T) {
+	f := newRelayFixture()
+	f.events.err = fmt.Errorf("gateway unavailable")
+	msg := outboxMessage(f.transfer.ID, models.NewOutboxEvent("transaction.created", nil))
+	msg.Attempts = outboxMaxAttempts - 1
+	f.outbox.due = []*models.OutboxMessage{msg}
+
+	if _, _, err := f.relay.RelayBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(f.outbox.dead) != 1 || f.outbox.dead[0] != msg.ID {
+		t.Errorf("expected message %s marked dead, got %v", msg.ID, f.outbox.dead)
+	}
+	if _, ok := f.outbox.failed[msg.ID]; ok {
+		t.Error("expected no further retry for a dead message")
+	}
+}
+
+func TestRelayPending_DrainsFullBatches(t *testing.T) {
+	f := newRelayFixture()
+	for i := 0; i < outboxBatchSize+5; i++ {
+		f.outbox.due = append(f.outbox.due, outboxMessage(f.transfer.ID, models.NewOutboxEvent("transaction.created", nil)))
+	}
+
+	delivered, failed, err := f.relay.RelayPending(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if delivered != outboxBatchSize+5 || failed != 0 {
+		t.Errorf("expected %d delivered and 0 failed, got %d and %d", outboxBatchSize+5, delivered, failed)
+	}
+	if f.outbox.claimedWith != outboxLease {
+		t.Errorf("expected claims to use lease %v, got %v", outboxLease, f.outbox.claimedWith)
+	}
+}
+
+func TestOutboxBackoff_CapsAtMaximum(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{4, 40 * time.Second},
+		{8, 10 * time.Minute},
+		{30, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.expected {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.expected)
+		}
+	}
+}
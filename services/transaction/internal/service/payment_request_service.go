@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/logger"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+const (
+	paymentRequestLease     = 2 * time.Minute // Longer than creating the transfer
+	paymentRequestBatchSize = 100
+
+	paymentRequestReceivedTemplate  = "payment_request_received_inapp"
+	paymentRequestAcceptedTemplate  = "payment_request_accepted_inapp"
+	paymentRequestDeclinedTemplate  = "payment_request_declined_inapp"
+	paymentRequestCancelledTemplate = "payment_request_cancelled_inapp"
+	paymentRequestExpiredTemplate   = "payment_request_expired_inapp"
+)
+
+// PaymentRequestRepositoryInterface defines the interface for payment request storage.
+type PaymentRequestRepositoryInterface interface {
+	Create(ctx context.Context, p *models.PaymentRequest) *errors.Error
+	GetByID(ctx context.Context, id string) (*models.PaymentRequest, *errors.Error)
+	List(ctx context.Context, filter *models.PaymentRequestFilter) ([]*models.PaymentRequest, int, *errors.Error)
+	Claim(ctx context.Context, id string, lease time.Duration) (*models.PaymentRequest, *errors.Error)
+	ClaimExpired(ctx context.Context, limit int, lease time.Duration) ([]*models.PaymentRequest, *errors.Error)
+	Release(ctx context.Context, id string) *errors.Error
+	Resolve(ctx context.Context, id string, status models.PaymentRequestStatus, transactionID *string) (*models.PaymentRequest, *errors.Error)
+	Close(ctx context.Context, id string, status models.PaymentRequestStatus, reason *string) (*models.PaymentRequest, *errors.Error)
+	FindTransferByReference(ctx context.Context, reference string) (*models.Transaction, *errors.Error)
+}
+
+// UserDirectory looks up users in the Identity service on behalf of the authenticated user.
+type UserDirectory interface {
+	GetCurrentUser(ctx context.Context) (*UserInfo, *errors.Error)
+	LookupUserByPhone(ctx context.Context, phone string) (*UserInfo, *errors.Error)
+}
+
+// BeneficiaryLookup retrieves beneficiaries of the authenticated user.
+type BeneficiaryLookup interface {
+	GetBeneficiary(ctx context.Context, beneficiaryID string) (*Beneficiary, *errors.Error)
+}
+
+// PaymentRequestEventPublisher publishes payment request events to the gateway's SSE broker.
+type PaymentRequestEventPublisher interface {
+	PublishPaymentRequestEvent(eventType string, paymentRequestID string, data map[string]interface{})
+}
+
+// PaymentRequestService lets users request money from each other.
+type PaymentRequestService struct {
+	repo          PaymentRequestRepositoryInterface
+	users         UserDirectory
+	beneficiaries BeneficiaryLookup
+	transfers     TransferCreator
+	notifier      NotificationSender
+	events        PaymentRequestEventPublisher
+	verifier      StepUpVerifier
+	logger        *logger.Logger
+	now           func() time.Time
+}
+
+// NewPaymentRequestService creates a new payment request service.
+func NewPaymentRequestService(
+	repo PaymentRequestRepositoryInterface,
+	users UserDirectory,
+	beneficiaries BeneficiaryLookup,
+	transfers TransferCreator,
+	notifier NotificationSender,
+	events PaymentRequestEventPublisher,
+) *PaymentRequestService {
+	return &PaymentRequestService{
+		repo:          repo,
+		users:         users,
+		beneficiaries: beneficiaries,
+		transfers:     transfers,
+		notifier:      notifier,
+		events:        events,
+		logger:        logger.NewDefault("transaction"),
+		now:           time.Now,
+	}
+}
+
+// SetStepUpVerifier sets the verifier for accepting high-value payment requests.
+func (s *PaymentRequestService) SetStepUpVerifier(verifier StepUpVerifier) {
+	s.verifier = verifier
+}
+
+// Create sends a payment request from the requester to the payer identified
+// by phone number or by one of the requester's beneficiaries.
+func (s *PaymentRequestService) Create(ctx context.Context, requesterID string, req *models.CreatePaymentRequestRequest) (*models.PaymentRequest, *errors.Error) {
+	if req.Amount <= 0 {
+		return nil, errors.BadRequest("amount must be positive")
+	}
+	if (req.PayerPhone == "") == (req.BeneficiaryID == "") {
+		return nil, errors.BadRequest("exactly one of payer_phone or beneficiary_id is required")
+	}
+
+	expiry := models.DefaultPaymentRequestExpiry
+	if req.ExpiresInHours != nil {
+		expiry = time.Duration(*req.ExpiresInHours) * time.Hour
+		if expiry <= 0 || expiry > models.MaxPaymentRequestExpiry {
+			return nil, errors.BadRequest(fmt.Sprintf("expires_in_hours must be between 1 and %d", int(models.MaxPaymentRequestExpiry.Hours())))
+		}
+	}
+
+	phone := req.PayerPhone
+	if req.BeneficiaryID != "" {
+		beneficiary, err := s.beneficiaries.GetBeneficiary(ctx, req.BeneficiaryID)
+		if err != nil {
+			return nil, err
+		}
+		phone = beneficiary.Phone
+	}
+
+	payer, err := s.users.LookupUserByPhone(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	if payer.ID == requesterID {
+		return nil, errors.BadRequest("cannot request money from yourself")
+	}
+
+	requester, err := s.users.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &models.PaymentRequest{
+		RequesterUserID:   requesterID,
+		RequesterWalletID: req.WalletID,
+		RequesterName:     requester.FullName,
+		PayerUserID:       payer.ID,
+		PayerPhone:        payer.Phone,
+		PayerName:         payer.FullName,
+		Amount:            req.Amount,
+		Currency:          req.Currency,
+		Description:       req.Description,
+		Status:            models.PaymentRequestStatusPending,
+		ExpiresAt:         sharedModels.NewTimestamp(s.now().Add(expiry)),
+	}
+	if request.PayerPhone == "" {
+		request.PayerPhone = phone
+	}
+
+	if err := s.repo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	s.notify(request.PayerUserID, paymentRequestReceivedTemplate, request, clients.NotificationPriorityHigh, map[string]any{
+		"expires_at": request.ExpiresAt.Time.Format("2006-01-02 15:04 MST"),
+	})
+	s.publish("payment_request.created", request)
+
+	return request, nil
+}
+
+// Get retrieves a payment request the user sent or received.
+func (s *PaymentRequestService) Get(ctx context.Context, userID, id string) (*models.PaymentRequest, *errors.Error) {
+	request, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.RequesterUserID != userID && request.PayerUserID != userID {
+		return nil, errors.NotFoundWithID("payment request", id)
+	}
+	return request, nil
+}
+
+// List retrieves the payment requests a user sent or received.
+func (s *PaymentRequestService) List(ctx context.Context, filter *models.PaymentRequestFilter) ([]*models.PaymentRequest, int, *errors.Error) {
+	if filter.Direction != models.PaymentRequestIncoming && filter.Direction != models.PaymentRequestOutgoing {
+		return nil, 0, errors.BadRequest("direction must be incoming or outgoing")
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// Accept pays a payment request with a transfer from the payer's wallet to the
+// requester's wallet. If the transfer fails, the request stays pending so the
+// payer can try again, e.g. from another wallet. Requests above
+// clients.HighValueTransferThreshold need a verification token from the payer,
+// as for any transfer they make; it is released again if the transfer fails.
+func (s *PaymentRequestService) Accept(ctx context.Context, payerID, id string, req *models.AcceptPaymentRequestRequest) (*models.PaymentRequest, *errors.Error) {
+	request, err := s.pendingRequest(ctx, payerID, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if request.IsExpiredAt(s.now()) {
+		return nil, errors.Gone("payment request has expired")
+	}
+
+	if _, err := s.repo.Claim(ctx, id, paymentRequestLease); err != nil {
+		return nil, err
+	}
+
+	reference := paymentRequestReference(id)
+	transaction, txErr := s.findTransfer(ctx, reference)
+	redeemed := false
+	if transaction == nil && txErr == nil {
+		txErr = redeemHighValueTransfer(ctx, s.verifier, payerID, request.Amount, request.RequesterWalletID, req.VerificationToken)
+		redeemed = txErr == nil
+	}
+	if transaction == nil && txErr == nil {
+		transaction, txErr = s.transfers.CreateTransfer(ctx, s.transferRequest(request, req.SourceWalletID, reference))
+		if transaction == nil {
+			// A transfer rejected before it could be returned may still have been recorded
+			if recorded, findErr := s.repo.FindTransferByReference(ctx, reference); findErr == nil {
+				transaction = recorded
+			}
+		}
+	}
+
+	if transaction == nil || transaction.IsFailed() {
+		if releaseErr := s.repo.Release(ctx, id); releaseErr != nil {
+			s.logger.WithField("payment_request_id", id).WithError(releaseErr).Error("Failed to release payment request")
+		}
+		if redeemed {
+			releaseHighValueTransfer(ctx, s.verifier, s.logger, payerID, request.Amount, request.RequesterWalletID, req.VerificationToken)
+		}
+		if transaction != nil {
+			reason := "transfer failed"
+			if transaction.FailureReason != nil {
+				reason = *transaction.FailureReason
+			}
+			return nil, errors.TransactionFailed(reason).WithDetails(map[string]interface{}{
+				"transaction_id": transaction.ID,
+			})
+		}
+		return nil, txErr
+	}
+
+	return s.resolveAccepted(ctx, id, transaction.ID)
+}
+
+// Decline declines a payment request on behalf of the payer.
+func (s *PaymentRequestService) Decline(ctx context.Context, payerID, id, reason string) (*models.PaymentRequest, *errors.Error) {
+	if _, err := s.pendingRequest(ctx, payerID, id, true); err != nil {
+		return nil, err
+	}
+
+	var declineReason *string
+	if reason = strings.TrimSpace(reason); reason != "" {
+		declineReason = &reason
+	}
+
+	request, err := s.repo.Close(ctx, id, models.PaymentRequestStatusDeclined, declineReason)
+	if err != nil {
+		return nil, err
+	}
+
+	reasonNotice := ""
+	if declineReason != nil {
+		reasonNotice = " Reason: " + *declineReason
+	}
+	s.notify(request.RequesterUserID, paymentRequestDeclinedTemplate, request, clients.NotificationPriorityNormal, map[string]any{
+		"reason_notice": reasonNotice,
+	})
+	s.publish("payment_request.declined", request)
+
+	return request, nil
+}
+
+// Cancel withdraws a payment request on behalf of the requester.
+func (s *PaymentRequestService) Cancel(ctx context.Context, requesterID, id string) (*models.PaymentRequest, *errors.Error) {
+	if _, err := s.pendingRequest(ctx, requesterID, id, false); err != nil {
+		return nil, err
+	}
+
+	request, err := s.repo.Close(ctx, id, models.PaymentRequestStatusCancelled, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(request.PayerUserID, paymentRequestCancelledTemplate, request, clients.NotificationPriorityNormal, nil)
+	s.publish("payment_request.cancelled", request)
+
+	return request, nil
+}
+
+// ExpireDue expires pending payment requests past their expiry and returns how
+// many were expired. A request whose acceptance was interrupted after its
+// transfer was created is marked accepted instead. It is called periodically
+// by the expiry worker.
+func (s *PaymentRequestService) ExpireDue(ctx context.Context) (int, *errors.Error) {
+	requests, err := s.repo.ClaimExpired(ctx, paymentRequestBatchSize, paymentRequestLease)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, request := range requests {
+		transaction, findErr := s.findTransfer(ctx, paymentRequestReference(request.ID))
+		if findErr != nil {
+			s.logger.WithField("payment_request_id", request.ID).WithError(findErr).Error("Failed to check payment request transfer")
+			if releaseErr := s.repo.Release(ctx, request.ID); releaseErr != nil {
+				s.logger.WithField("payment_request_id", request.ID).WithError(releaseErr).Error("Failed to release payment request")
+			}
+			continue
+		}
+
+		if transaction != nil {
+			if _, acceptErr := s.resolveAccepted(ctx, request.ID, transaction.ID); acceptErr != nil {
+				s.logger.WithField("payment_request_id", request.ID).WithError(acceptErr).Error("Failed to accept paid payment request")
+			}
+			continue
+		}
+
+		resolved, resolveErr := s.repo.Resolve(ctx, request.ID, models.PaymentRequestStatusExpired, nil)
+		if resolveErr != nil {
+			s.logger.WithField("payment_request_id", request.ID).WithError(resolveErr).Error("Failed to expire payment request")
+			continue
+		}
+
+		s.notify(resolved.RequesterUserID, paymentRequestExpiredTemplate, resolved, clients.NotificationPriorityLow, map[string]any{
+			"counterparty": resolved.PayerName,
+		})
+		s.notify(resolved.PayerUserID, paymentRequestExpiredTemplate, resolved, clients.NotificationPriorityLow, map[string]any{
+			"counterparty": resolved.RequesterName,
+		})
+		s.publish("payment_request.expired", resolved)
+		expired++
+	}
+
+	return expired, nil
+}
+
+// pendingRequest retrieves a pending payment request and checks the user's side:
+// the payer when asPayer is set, the requester otherwise.
+func (s *PaymentRequestService) pendingRequest(ctx context.Context, userID, id string, asPayer bool) (*models.PaymentRequest, *errors.Error) {
+	request, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if asPayer && request.PayerUserID != userID {
+		return nil, errors.Forbidden("only the payer can respond to a payment request")
+	}
+	if !asPayer && request.RequesterUserID != userID {
+		return nil, errors.Forbidden("only the requester can cancel a payment request")
+	}
+
+	if !request.IsPending() {
+		return nil, errors.BadRequest(fmt.Sprintf("payment request is already %s", request.Status))
+	}
+	return request, nil
+}
+
+// findTransfer returns the transfer that paid a payment request, or nil if
+// there is none or it failed.
+func (s *PaymentRequestService) findTransfer(ctx context.Context, reference string) (*models.Transaction, *errors.Error) {
+	transaction, err := s.repo.FindTransferByReference(ctx, reference)
+	if err != nil {
+		if err.Code == errors.ErrCodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if transaction.IsFailed() {
+		return nil, nil
+	}
+	return transaction, nil
+}
+
+// resolveAccepted records a claimed payment request as paid by the transfer.
+func (s *PaymentRequestService) resolveAccepted(ctx context.Context, id, transactionID string) (*models.PaymentRequest, *errors.Error) {
+	request, err := s.repo.Resolve(ctx, id, models.PaymentRequestStatusAccepted, &transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(request.RequesterUserID, paymentRequestAcceptedTemplate, request, clients.NotificationPriorityNormal, nil)
+	s.publish("payment_request.accepted", request)
+
+	return request, nil
+}
+
+func (s *PaymentRequestService) transferRequest(request *models.PaymentRequest, sourceWalletID, reference string) *models.CreateTransferRequest {
+	metadata, _ := json.Marshal(map[string]string{
+		"payment_request_id": request.ID,
+	})
+
+	return &models.CreateTransferRequest{
+		SourceWalletID:      sourceWalletID,
+		DestinationWalletID: request.RequesterWalletID,
+		Amount:              request.Amount,
+		Currency:            request.Currency,
+		Description:         request.Description,
+		Reference:           reference,
+		MetadataRaw:         metadata,
+	}
+}
+
+// notify sends an in-app notification about a payment request to one of its parties.
+func (s *PaymentRequestService) notify(userID, template string, request *models.PaymentRequest, priority clients.NotificationPriority, extra map[string]any) {
+	if s.notifier == nil {
+		return
+	}
+
+	variables := map[string]any{
+		"amount":         formatAmount(request.Amount),
+		"currency":       string(request.Currency),
+		"description":    request.Description,
+		"requester_name": displayName(request.RequesterName, "A Nivo user"),
+		"payer_name":     displayName(request.PayerName, request.PayerPhone),
+	}
+	for k, v := range extra {
+		variables[k] = v
+	}
+
+	correlationID := fmt.Sprintf("payment-request-%s-%s", request.ID, request.Status)
+
+	s.notifier.SendNotificationAsync(&clients.SendNotificationRequest{
+		UserID:        &userID,
+		Recipient:     userID,
+		Channel:       clients.NotificationChannelInApp,
+		Type:          clients.NotificationTypeTransactionAlert,
+		Priority:      priority,
+		TemplateID:    template,
+		Variables:     variables,
+		CorrelationID: &correlationID,
+		SourceService: "transaction",
+		Metadata: map[string]any{
+			"payment_request_id": request.ID,
+		},
+	}, "transaction")
+}
+
+// publish sends a payment request event to the SSE broker. Both parties'
+// user IDs are included so clients can pick out their own requests.
+func (s *PaymentRequestService) publish(eventType string, request *models.PaymentRequest) {
+	if s.events == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"requester_user_id": request.RequesterUserID,
+		"payer_user_id":     request.PayerUserID,
+		"amount":            request.Amount,
+		"currency":          string(request.Currency),
+		"description":       request.Description,
+		"status":            string(request.Status),
+		"expires_at":        request.ExpiresAt.Time.Format(time.RFC3339),
+	}
+	if request.TransactionID != nil {
+		data["transaction_id"] = *request.TransactionID
+	}
+
+	s.events.PublishPaymentRequestEvent(eventType, request.ID, data)
+}
+
+// paymentRequestReference is the transfer reference of a payment request.
+func paymentRequestReference(id string) string {
+	return "payreq-" + id
+}
+
+func displayName(name, fallback string) string {
+	if name != "" {
+		return name
+	}
+	return fallback
+}
@@ -0,0 +1,614 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// =====================================================================
+// Mocks for Payment Request Tests
+// =====================================================================
+
+type mockPaymentRequestRepository struct {
+	requests  map[string]*models.PaymentRequest
+	locked    map[string]time.Time           // Claim expiry by request ID
+	transfers map[string]*models.Transaction // By reference
+	now       func() time.Time
+}
+
+func newMockPaymentRequestRepository(now func() time.Time) *mockPaymentRequestRepository {
+	return &mockPaymentRequestRepository{
+		requests:  make(map[string]*models.PaymentRequest),
+		locked:    make(map[string]time.Time),
+		transfers: make(map[string]*models.Transaction),
+		now:       now,
+	}
+}
+
+func (m *mockPaymentRequestRepository) isLocked(id string) bool {
+	until, ok := m.locked[id]
+	return ok && until.After(m.now())
+}
+
+func (m *mockPaymentRequestRepository) Create(ctx context.Context, p *models.PaymentRequest) *errors.Error {
+	p.ID = uuid.New().String()
+	stored := *p
+	m.requests[p.ID] = &stored
+	return nil
+}
+
+func (m *mockPaymentRequestRepository) GetByID(ctx context.Context, id string) (*models.PaymentRequest, *errors.Error) {
+	p, ok := m.requests[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("payment request", id)
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (m *mockPaymentRequestRepository) List(ctx context.Context, filter *models.PaymentRequestFilter) ([]*models.PaymentRequest, int, *errors.Error) {
+	var requests []*models.PaymentRequest
+	for _, p := range m.requests {
+		if (filter.Direction == models.PaymentRequestIncoming && p.PayerUserID == filter.UserID) ||
+			(filter.Direction == models.PaymentRequestOutgoing && p.RequesterUserID == filter.UserID) {
+			requests = append(requests, p)
+		}
+	}
+	return requests, len(requests), nil
+}
+
+func (m *mockPaymentRequestRepository) Claim(ctx context.Context, id string, lease time.Duration) (*models.PaymentRequest, *errors.Error) {
+	p, ok := m.requests[id]
+	if !ok || !p.IsPending() || p.IsExpiredAt(m.now()) || m.isLocked(id) {
+		return nil, errors.Conflict("payment request is no longer pending or is being processed")
+	}
+	m.locked[id] = m.now().Add(lease)
+	return m.GetByID(ctx, id)
+}
+
+func (m *mockPaymentRequestRepository) ClaimExpired(ctx context.Context, limit int, lease time.Duration) ([]*models.PaymentRequest, *errors.Error) {
+	var claimed []*models.PaymentRequest
+	for id, p := range m.requests {
+		if p.IsPending() && p.IsExpiredAt(m.now()) && !m.isLocked(id) {
+			m.locked[id] = m.now().Add(lease)
+			copied := *p
+			claimed = append(claimed, &copied)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *mockPaymentRequestRepository) Release(ctx context.Context, id string) *errors.Error {
+	delete(m.locked, id)
+	return nil
+}
+
+func (m *mockPaymentRequestRepository) Resolve(ctx context.Context, id string, status models.PaymentRequestStatus, transactionID *string) (*models.PaymentRequest, *errors.Error) {
+	p, ok := m.requests[id]
+	if !ok || !p.IsPending() {
+		return nil, errors.Conflict("payment request is no longer pending")
+	}
+	p.Status = status
+	p.TransactionID = transactionID
+	delete(m.locked, id)
+	return m.GetByID(ctx, id)
+}
+
+func (m *mockPaymentRequestRepository) Close(ctx context.Context, id string, status models.PaymentRequestStatus, reason *string) (*models.PaymentRequest, *errors.Error) {
+	p, ok := m.requests[id]
+	if !ok || !p.IsPending() || m.isLocked(id) {
+		return nil, errors.Conflict("payment request is no longer pending or is being processed")
+	}
+	p.Status = status
+	p.DeclineReason = reason
+	return m.GetByID(ctx, id)
+}
+
+func (m *mockPaymentRequestRepository) FindTransferByReference(ctx context.Context, reference string) (*models.Transaction, *errors.Error) {
+	tx, ok := m.transfers[reference]
+	if !ok {
+		return nil, errors.NotFound("transaction")
+	}
+	return tx, nil
+}
+
+type mockUserDirectory struct {
+	current *UserInfo
+	byPhone map[string]*UserInfo
+}
+
+func (m *mockUserDirectory) GetCurrentUser(ctx context.Context) (*UserInfo, *errors.Error) {
+	return m.current, nil
+}
+
+func (m *mockUserDirectory) LookupUserByPhone(ctx context.Context, phone string) (*UserInfo, *errors.Error) {
+	user, ok := m.byPhone[phone]
+	if !ok {
+		return nil, errors.NotFound("user")
+	}
+	return user, nil
+}
+
+type mockBeneficiaryLookup struct {
+	beneficiaries map[string]*Beneficiary
+}
+
+func (m *mockBeneficiaryLookup) GetBeneficiary(ctx context.Context, beneficiaryID string) (*Beneficiary, *errors.Error) {
+	b, ok := m.beneficiaries[beneficiaryID]
+	if !ok {
+		return nil, errors.NotFoundWithID("beneficiary", beneficiaryID)
+	}
+	return b, nil
+}
+
+type paymentRequestEvent struct {
+	eventType string
+	id        string
+	data      map[string]interface{}
+}
+
+type mockPaymentRequestEvents struct {
+	published []paymentRequestEvent
+}
+
+func (m *mockPaymentRequestEvents) PublishPaymentRequestEvent(eventType string, paymentRequestID string, data map[string]interface{}) {
+	m.published = append(m.published, paymentRequestEvent{eventType: eventType, id: paymentRequestID, data: data})
+}
+
+type paymentRequestFixture struct {
+	service   *PaymentRequestService
+	repo      *mockPaymentRequestRepository
+	transfers *mockTransferCreator
+	notifier  *mockNotificationSender
+	events    *mockPaymentRequestEvents
+	verifier  *mockStepUpVerifier
+	now       time.Time
+}
+
+func newPaymentRequestFixture() *paymentRequestFixture {
+	f := &paymentRequestFixture{
+		now:      time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		notifier: &mockNotificationSender{},
+		events:   &mockPaymentRequestEvents{},
+		verifier: &mockStepUpVerifier{},
+	}
+	clock := func() time.Time { return f.now }
+	f.repo = newMockPaymentRequestRepository(clock)
+	f.transfers = &mockTransferCreator{recorded: f.repo.transfers}
+
+	users := &mockUserDirectory{
+		current: &UserInfo{ID: "requester", Phone: "+919800000001", FullName: "Asha Rao"},
+		byPhone: map[string]*UserInfo{
+			"+919800000001": {ID: "requester", Phone: "+919800000001", FullName: "Asha Rao"},
+			"+919800000002": {ID: "payer", Phone: "+919800000002", FullName: "Vikram Iyer"},
+		},
+	}
+	beneficiaries := &mockBeneficiaryLookup{beneficiaries: map[string]*Beneficiary{
+		"ben-1": {ID: "ben-1", Nickname: "Vikram", Phone: "+919800000002", WalletID: "wallet-payer"},
+	}}
+
+	f.service = NewPaymentRequestService(f.repo, users, beneficiaries, f.transfers, f.notifier, f.events)
+	f.service.SetStepUpVerifier(f.verifier)
+	f.service.now = clock
+	return f
+}
+
+func (f *paymentRequestFixture) request() *models.CreatePaymentRequestRequest {
+	return &models.CreatePaymentRequestRequest{
+		PayerPhone:  "+919800000002",
+		WalletID:    "wallet-requester",
+		Amount:      120000,
+		Currency:    sharedModels.INR,
+		Description: "Dinner",
+	}
+}
+
+func (f *paymentRequestFixture) create(t *testing.T) *models.PaymentRequest {
+	t.Helper()
+	request, err := f.service.Create(context.Background(), "requester", f.request())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return request
+}
+
+func (f *paymentRequestFixture) accept(id string) (*models.PaymentRequest, *errors.Error) {
+	return f.service.Accept(context.Background(), "payer", id, &models.AcceptPaymentRequestRequest{SourceWalletID: "wallet-payer"})
+}
+
+// =====================================================================
+// Payment Request Tests
+// =====================================================================
+
+func TestCreatePaymentRequest_NotifiesPayer(t *testing.T) {
+	f := newPaymentRequestFixture()
+
+	request := f.create(t)
+
+	if request.PayerUserID != "payer" || request.PayerName != "Vikram Iyer" || request.RequesterName != "Asha Rao" {
+		t.Errorf("expected parties resolved, got %+v", request)
+	}
+	if request.Status != models.PaymentRequestStatusPending {
+		t.Errorf("expected status pending, got %s", request.Status)
+	}
+	if expected := f.now.Add(models.DefaultPaymentRequestExpiry); !request.ExpiresAt.Time.Equal(expected) {
+		t.Errorf("expected expiry %v, got %v", expected, request.ExpiresAt.Time)
+	}
+
+	if len(f.notifier.sent) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(f.notifier.sent))
+	}
+	sent := f.notifier.sent[0]
+	if *sent.UserID != "payer" || sent.TemplateID != paymentRequestReceivedTemplate {
+		t.Errorf("expected request notification to payer, got %s to %s", sent.TemplateID, *sent.UserID)
+	}
+	if sent.Variables["requester_name"] != "Asha Rao" || sent.Variables["amount"] != "1200.00" {
+		t.Errorf("expected requester and amount in notification, got %v", sent.Variables)
+	}
+
+	if len(f.events.published) != 1 || f.events.published[0].eventType != "payment_request.created" {
+		t.Fatalf("expected payment_request.created event, got %+v", f.events.published)
+	}
+	if data := f.events.published[0].data; data["payer_user_id"] != "payer" || data["requester_user_id"] != "requester" {
+		t.Errorf("expected both parties in event, got %v", data)
+	}
+}
+
+func TestCreatePaymentRequest_FromBeneficiary(t *testing.T) {
+	f := newPaymentRequestFixture()
+	req := f.request()
+	req.PayerPhone = ""
+	req.BeneficiaryID = "ben-1"
+
+	request, err := f.service.Create(context.Background(), "requester", req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if request.PayerUserID != "payer" || request.PayerPhone != "+919800000002" {
+		t.Errorf("expected payer resolved from beneficiary, got %s %s", request.PayerUserID, request.PayerPhone)
+	}
+}
+
+func TestCreatePaymentRequest_Validation(t *testing.T) {
+	f := newPaymentRequestFixture()
+	zero, tooLong := 0, 24*31
+
+	tests := []struct {
+		name   string
+		modify func(req *models.CreatePaymentRequestRequest)
+		code   errors.ErrorCode
+	}{
+		{"no payer", func(req *models.CreatePaymentRequestRequest) { req.PayerPhone = "" }, errors.ErrCodeBadRequest},
+		{"phone and beneficiary", func(req *models.CreatePaymentRequestRequest) { req.BeneficiaryID = "ben-1" }, errors.ErrCodeBadRequest},
+		{"self", func(req *models.CreatePaymentRequestRequest) { req.PayerPhone = "+919800000001" }, errors.ErrCodeBadRequest},
+		{"unknown payer", func(req *models.CreatePaymentRequestRequest) { req.PayerPhone = "+919800000009" }, errors.ErrCodeNotFound},
+		{"zero expiry", func(req *models.CreatePaymentRequestRequest) { req.ExpiresInHours = &zero }, errors.ErrCodeBadRequest},
+		{"expiry too long", func(req *models.CreatePaymentRequestRequest) { req.ExpiresInHours = &tooLong }, errors.ErrCodeBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := f.request()
+			tt.modify(req)
+
+			_, err := f.service.Create(context.Background(), "requester", req)
+			if err == nil || err.Code != tt.code {
+				t.Errorf("expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+
+	if len(f.repo.requests) != 0 {
+		t.Errorf("expected no requests created, got %d", len(f.repo.requests))
+	}
+}
+
+func TestAcceptPaymentRequest_TransfersToRequester(t *testing.T) {
+	f := newPaymentRequestFixture()
+	request := f.create(t)
+
+	accepted, err := f.accept(request.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(f.transfers.requests) != 1 {
+		t.Fatalf("expected 1 transfer, got %d", len(f.transfers.requests))
+	}
+	transfer := f.transfers.requests[0]
+	if transfer.SourceWalletID != "wallet-payer" || transfer.DestinationWalletID != "wallet-requester" || transfer.Amount != 120000 {
+		t.Errorf("expected transfer from payer to requester, got %+v", transfer)
+	}
+	if transfer.Reference != paymentRequestReference(request.ID) {
+		t.Errorf("expected reference %s, got %s", paymentRequestReference(request.ID), transfer.Reference)
+	}
+
+	if accepted.Status != models.PaymentRequestStatusAccepted || accepted.TransactionID == nil {
+		t.Errorf("expected accepted request with transaction, got %s %v", accepted.Status, accepted.TransactionID)
+	}
+
+	last := f.notifier.sent[len(f.notifier.sent)-1]
+	if *last.UserID != "requester" || last.TemplateID != paymentRequestAcceptedTemplate {
+		t.Errorf("expected accepted notification to requester, got %s to %s", last.TemplateID, *last.UserID)
+	}
+	if event := f.events.published[len(f.events.published)-1]; event.eventType != "payment_request.accepted" || event.data["transaction_id"] != *accepted.TransactionID {
+		t.Errorf("expected payment_request.accepted event with transaction, got %+v", event)
+	}
+}
+
+func TestAcceptPaymentRequest_FailedTransferLeavesRequestPending(t *testing.T) {
+	f := newPaymentRequestFixture()
+	f.transfers.status = models.TransactionStatusFailed
+	request := f.create(t)
+
+	_, err := f.accept(request.ID)
+
+	if err == nil || err.Code != errors.ErrCodeTransactionFailed {
+		t.Fatalf("expected transaction failed, got %v", err)
+	}
+	if err.Message != "insufficient balance" {
+		t.Errorf("expected failure reason, got %q", err.Message)
+	}
+	if !f.repo.requests[request.ID].IsPending() || f.repo.isLocked(request.ID) {
+		t.Error("expected request pending and released")
+	}
+
+	// The payer can retry, e.g. after topping up
+	f.transfers.status = ""
+	if _, err := f.accept(request.ID); err != nil {
+		t.Errorf("expected retry to succeed, got %v", err)
+	}
+}
+
+func TestAcceptPaymentRequest_HighValueRequiresVerification(t *testing.T) {
+	f := newPaymentRequestFixture()
+	req := f.request()
+	req.Amount = clients.HighValueTransferThreshold + 1
+	request, err := f.service.Create(context.Background(), "requester", req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = f.accept(request.ID)
+	if err == nil || err.Code != errors.ErrCodeVerificationRequired {
+		t.Fatalf("expected verification required, got %v", err)
+	}
+	if len(f.transfers.requests) != 0 {
+		t.Fatalf("expected no transfer, got %d", len(f.transfers.requests))
+	}
+	if !f.repo.requests[request.ID].IsPending() || f.repo.isLocked(request.ID) {
+		t.Error("expected request pending and released")
+	}
+
+	accepted, err := f.service.Accept(context.Background(), "payer", request.ID, &models.AcceptPaymentRequestRequest{
+		SourceWalletID:    "wallet-payer",
+		VerificationToken: "ver-token",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if accepted.Status != models.PaymentRequestStatusAccepted {
+		t.Errorf("expected accepted, got %s", accepted.Status)
+	}
+	if len(f.verifier.redeemed) != 1 {
+		t.Fatalf("expected token redeemed once, got %d", len(f.verifier.redeemed))
+	}
+	if bound := f.verifier.redeemed[0]; bound["amount"] != request.Amount || bound["destination_wallet_id"] != "wallet-requester" {
+		t.Errorf("expected token bound to amount and requester wallet, got %v", bound)
+	}
+}
+
+func TestAcceptPaymentRequest_FailedHighValueTransferReleasesToken(t *testing.T) {
+	f := newPaymentRequestFixture()
+	f.transfers.status = models.TransactionStatusFailed
+	req := f.request()
+	req.Amount = clients.HighValueTransferThreshold + 1
+	request, err := f.service.Create(context.Background(), "requester", req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	accept := &models.AcceptPaymentRequestRequest{SourceWalletID: "wallet-payer", VerificationToken: "ver-token"}
+
+	if _, err := f.service.Accept(context.Background(), "payer", request.ID, accept); err == nil || err.Code != errors.ErrCodeTransactionFailed {
+		t.Fatalf("expected transaction failed, got %v", err)
+	}
+	if len(f.verifier.released) != 1 {
+		t.Fatalf("expected token released once, got %d", len(f.verifier.released))
+	}
+	if released := f.verifier.released[0]; released["amount"] != request.Amount || released["destination_wallet_id"] != "wallet-requester" {
+		t.Errorf("expected the redeemed details released, got %v", released)
+	}
+
+	// The payer can retry with the same token, e.g. after topping up
+	f.transfers.status = ""
+	if _, err := f.service.Accept(context.Background(), "payer", request.ID, accept); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if len(f.verifier.released) != 1 {
+		t.Errorf("expected token kept for the accepted transfer, got %d releases", len(f.verifier.released))
+	}
+}
+
+func TestAcceptPaymentRequest_OnlyPayer(t *testing.T) {
+	f := newPaymentRequestFixture()
+	request := f.create(t)
+
+	_, err := f.service.Accept(context.Background(), "requester", request.ID, &models.AcceptPaymentRequestRequest{SourceWalletID: "wallet-requester"})
+	if err == nil || err.Code != errors.ErrCodeForbidden {
+		t.Errorf("expected forbidden for requester, got %v", err)
+	}
+
+	_, err = f.service.Accept(context.Background(), "stranger", request.ID, &models.AcceptPaymentRequestRequest{SourceWalletID: "wallet-x"})
+	if err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found for other users, got %v", err)
+	}
+
+	if len(f.transfers.requests) != 0 {
+		t.Errorf("expected no transfers, got %d", len(f.transfers.requests))
+	}
+}
+
+func TestAcceptPaymentRequest_Expired(t *testing.T) {
+	f := newPaymentRequestFixture()
+	request := f.create(t)
+	f.now = f.now.Add(models.DefaultPaymentRequestExpiry)
+
+	_, err := f.accept(request.ID)
+
+	if err == nil || err.Code != errors.ErrCodeGone {
+		t.Errorf("expected gone, got %v", err)
+	}
+}
+
+func TestAcceptPaymentRequest_RejectsConcurrentAcceptance(t *testing.T) {
+	f := newPaymentRequestFixture()
+	request := f.create(t)
+	_, _ = f.repo.Claim(context.Background(), request.ID, paymentRequestLease)
+
+	_, err := f.accept(request.ID)
+
+	if err == nil || err.Code != errors.ErrCodeConflict {
+		t.Errorf("expected conflict, got %v", err)
+	}
+	if _, err := f.service.Decline(context.Background(), "payer", request.ID, ""); err == nil || err.Code != errors.ErrCodeConflict {
+		t.Errorf("expected conflict on decline while paying, got %v", err)
+	}
+	if len(f.transfers.requests) != 0 {
+		t.Errorf("expected no transfers, got %d", len(f.transfers.requests))
+	}
+}
+
+func TestAcceptPaymentRequest_InterruptedAcceptanceDoesNotPayTwice(t *testing.T) {
+	f := newPaymentRequestFixture()
+	request := f.create(t)
+	f.repo.transfers[paymentRequestReference(request.ID)] = &models.Transaction{ID: "tx-1", Status: models.TransactionStatusCompleted}
+
+	accepted, err := f.accept(request.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(f.transfers.requests) != 0 {
+		t.Errorf("expected no new transfer, got %d", len(f.transfers.requests))
+	}
+	if accepted.TransactionID == nil || *accepted.TransactionID != "tx-1" {
+		t.Errorf("expected existing transfer recorded, got %v", accepted.TransactionID)
+	}
+}
+
+func TestDeclinePaymentRequest_NotifiesRequester(t *testing.T) {
+	f := newPaymentRequestFixture()
+	request := f.create(t)
+
+	declined, err := f.service.Decline(context.Background(), "payer", request.ID, "  Already paid in cash ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if declined.Status != models.PaymentRequestStatusDeclined || declined.DeclineReason == nil || *declined.DeclineReason != "Already paid in cash" {
+		t.Errorf("expected declined with reason, got %s %v", declined.Status, declined.DeclineReason)
+	}
+
+	last := f.notifier.sent[len(f.notifier.sent)-1]
+	if *last.UserID != "requester" || last.TemplateID != paymentRequestDeclinedTemplate {
+		t.Errorf("expected declined notification to requester, got %s to %s", last.TemplateID, *last.UserID)
+	}
+	if last.Variables["reason_notice"] != " Reason: Already paid in cash" {
+		t.Errorf("expected reason in notification, got %q", last.Variables["reason_notice"])
+	}
+
+	if _, err := f.accept(request.ID); err == nil || err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected bad request accepting a declined request, got %v", err)
+	}
+}
+
+func TestCancelPaymentRequest_OnlyRequester(t *testing.T) {
+	f := newPaymentRequestFixture()
+	request := f.create(t)
+
+	if _, err := f.service.Cancel(context.Background(), "payer", request.ID); err == nil || err.Code != errors.ErrCodeForbidden {
+		t.Errorf("expected forbidden for payer, got %v", err)
+	}
+
+	cancelled, err := f.service.Cancel(context.Background(), "requester", request.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if cancelled.Status != models.PaymentRequestStatusCancelled {
+		t.Errorf("expected cancelled, got %s", cancelled.Status)
+	}
+	last := f.notifier.sent[len(f.notifier.sent)-1]
+	if *last.UserID != "payer" || last.TemplateID != paymentRequestCancelledTemplate {
+		t.Errorf("expected cancelled notification to payer, got %s to %s", last.TemplateID, *last.UserID)
+	}
+}
+
+func TestExpireDue_ExpiresAndNotifiesBothParties(t *testing.T) {
+	f := newPaymentRequestFixture()
+	request := f.create(t)
+	f.notifier.sent = nil
+
+	if expired, _ := f.service.ExpireDue(context.Background()); expired != 0 {
+		t.Errorf("expected nothing expired before expiry, got %d", expired)
+	}
+
+	f.now = f.now.Add(models.DefaultPaymentRequestExpiry + time.Minute)
+	expired, err := f.service.ExpireDue(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if expired != 1 {
+		t.Errorf("expected 1 expired, got %d", expired)
+	}
+	if f.repo.requests[request.ID].Status != models.PaymentRequestStatusExpired {
+		t.Errorf("expected expired, got %s", f.repo.requests[request.ID].Status)
+	}
+	if len(f.notifier.sent) != 2 {
+		t.Fatalf("expected notifications to both parties, got %d", len(f.notifier.sent))
+	}
+	if f.notifier.sent[0].Variables["counterparty"] != "Vikram Iyer" || f.notifier.sent[1].Variables["counterparty"] != "Asha Rao" {
+		t.Errorf("expected each party told about the other, got %v and %v", f.notifier.sent[0].Variables, f.notifier.sent[1].Variables)
+	}
+}
+
+func TestExpireDue_SettlesRequestPaidBeforeInterruption(t *testing.T) {
+	f := newPaymentRequestFixture()
+	request := f.create(t)
+	f.repo.transfers[paymentRequestReference(request.ID)] = &models.Transaction{ID: "tx-1", Status: models.TransactionStatusCompleted}
+	f.now = f.now.Add(models.DefaultPaymentRequestExpiry + time.Minute)
+
+	expired, _ := f.service.ExpireDue(context.Background())
+
+	if expired != 0 {
+		t.Errorf("expected nothing expired, got %d", expired)
+	}
+	stored := f.repo.requests[request.ID]
+	if stored.Status != models.PaymentRequestStatusAccepted || *stored.TransactionID != "tx-1" {
+		t.Errorf("expected request accepted with its transfer, got %s %v", stored.Status, stored.TransactionID)
+	}
+}
+
+func TestListPaymentRequests_RequiresDirection(t *testing.T) {
+	f := newPaymentRequestFixture()
+	f.create(t)
+
+	_, _, err := f.service.List(context.Background(), &models.PaymentRequestFilter{UserID: "payer", Direction: "sideways"})
+	if err == nil || err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected bad request, got %v", err)
+	}
+
+	incoming, total, _ := f.service.List(context.Background(), &models.PaymentRequestFilter{UserID: "payer", Direction: models.PaymentRequestIncoming})
+	if total != 1 || len(incoming) != 1 {
+		t.Errorf("expected 1 incoming request for payer, got %d", total)
+	}
+}
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/logger"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+const (
+	scheduleLease     = 5 * time.Minute // Longer than running the occurrences of one schedule
+	scheduleBatchSize = 50
+
+	// scheduleStartTolerance allows a start time slightly in the past, for
+	// clients that schedule "now" and clock skew.
+	scheduleStartTolerance = time.Minute
+
+	scheduledTransferFailedTemplate = "scheduled_transfer_failed_inapp"
+)
+
+// ScheduledTransferRepositoryInterface defines the interface for scheduled transfer storage.
+type ScheduledTransferRepositoryInterface interface {
+	Create(ctx context.Context, s *models.ScheduledTransfer) *errors.Error
+	GetByID(ctx context.Context, id string) (*models.ScheduledTransfer, *errors.Error)
+	List(ctx context.Context, filter *models.ScheduledTransferFilter) ([]*models.ScheduledTransfer, int, *errors.Error)
+	Modify(ctx context.Context, id string, modify func(*models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error)) (*models.ScheduledTransfer, *errors.Error)
+	StartExecution(ctx context.Context, e *models.ScheduledTransferExecution) (*models.ScheduledTransferExecution, *errors.Error)
+	ListExecutions(ctx context.Context, scheduleID string, limit, offset int) ([]*models.ScheduledTransferExecution, int, *errors.Error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.ScheduledTransfer, *errors.Error)
+	Release(ctx context.Context, id string) *errors.Error
+	FindTransferByReference(ctx context.Context, reference string) (*models.Transaction, *errors.Error)
+}
+
+// TransferCreator creates transfers through the regular transfer path.
+type TransferCreator interface {
+	CreateTransfer(ctx context.Context, req *models.CreateTransferRequest) (*models.Transaction, *errors.Error)
+}
+
+// NotificationSender sends notifications without waiting for delivery.
+type NotificationSender interface {
+	SendNotificationAsync(req *clients.SendNotificationRequest, serviceName string)
+}
+
+// ScheduledTransferService manages scheduled and recurring transfers and runs them when due.
+type ScheduledTransferService struct {
+	repo      ScheduledTransferRepositoryInterface
+	transfers TransferCreator
+	notifier  NotificationSender
+	verifier  StepUpVerifier
+	logger    *logger.Logger
+	now       func() time.Time
+}
+
+// NewScheduledTransferService creates a new scheduled transfer service.
+func NewScheduledTransferService(repo ScheduledTransferRepositoryInterface, transfers TransferCreator, notifier NotificationSender) *ScheduledTransferService {
+	return &ScheduledTransferService{
+		repo:      repo,
+		transfers: transfers,
+		notifier:  notifier,
+		logger:    logger.NewDefault("transaction"),
+		now:       time.Now,
+	}
+}
+
+// SetStepUpVerifier sets the verifier for schedules of high-value transfers.
+func (s *ScheduledTransferService) SetStepUpVerifier(verifier StepUpVerifier) {
+	s.verifier = verifier
+}
+
+// Create schedules a one-off or recurring transfer for the user. Schedules
+// above clients.HighValueTransferThreshold need a verification token bound to
+// the amount and destination wallet; once verified here, each occurrence runs
+// without one. A schedule's amount and destination cannot be changed later.
+func (s *ScheduledTransferService) Create(ctx context.Context, userID string, req *models.CreateScheduledTransferRequest) (*models.ScheduledTransfer, *errors.Error) {
+	if req.SourceWalletID == req.DestinationWalletID {
+		return nil, errors.BadRequest("source and destination wallets must be different")
+	}
+	if req.Amount <= 0 {
+		return nil, errors.BadRequest("amount must be positive")
+	}
+	if !req.Frequency.IsValid() {
+		return nil, errors.BadRequest("frequency must be one of once, daily, weekly or monthly")
+	}
+
+	startAt, parseErr := time.Parse(time.RFC3339, req.StartAt)
+	if parseErr != nil {
+		return nil, errors.BadRequest("start_at must be an RFC 3339 timestamp")
+	}
+	if startAt.Before(s.now().Add(-scheduleStartTolerance)) {
+		return nil, errors.BadRequest("start_at must not be in the past")
+	}
+
+	schedule := &models.ScheduledTransfer{
+		UserID:              userID,
+		SourceWalletID:      req.SourceWalletID,
+		DestinationWalletID: req.DestinationWalletID,
+		Amount:              req.Amount,
+		Currency:            req.Currency,
+		Description:         req.Description,
+		Frequency:           req.Frequency,
+		StartAt:             sharedModels.NewTimestamp(startAt),
+		Status:              models.ScheduleStatusActive,
+	}
+	schedule.NextRunAt = &schedule.StartAt
+
+	if req.Frequency == models.ScheduleFrequencyOnce {
+		if req.EndAt != "" || req.MaxOccurrences != nil {
+			return nil, errors.BadRequest("end_at and max_occurrences apply to recurring transfers only")
+		}
+	} else {
+		if req.EndAt != "" {
+			endAt, err := time.Parse(time.RFC3339, req.EndAt)
+			if err != nil {
+				return nil, errors.BadRequest("end_at must be an RFC 3339 timestamp")
+			}
+			if endAt.Before(startAt) {
+				return nil, errors.BadRequest("end_at must not be before start_at")
+			}
+			end := sharedModels.NewTimestamp(endAt)
+			schedule.EndAt = &end
+		}
+		if req.MaxOccurrences != nil {
+			if *req.MaxOccurrences <= 0 {
+				return nil, errors.BadRequest("max_occurrences must be positive")
+			}
+			schedule.MaxOccurrences = req.MaxOccurrences
+		}
+	}
+
+	if err := redeemHighValueTransfer(ctx, s.verifier, userID, req.Amount, req.DestinationWalletID, req.VerificationToken); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, schedule); err != nil {
+		releaseHighValueTransfer(ctx, s.verifier, s.logger, userID, req.Amount, req.DestinationWalletID, req.VerificationToken)
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// Get retrieves a scheduled transfer of the user.
+func (s *ScheduledTransferService) Get(ctx context.Context, userID, id string) (*models.ScheduledTransfer, *errors.Error) {
+	schedule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.UserID != userID {
+		return nil, errors.NotFoundWithID("scheduled transfer", id)
+	}
+	return schedule, nil
+}
+
+// List retrieves the scheduled transfers of a user.
+func (s *ScheduledTransferService) List(ctx context.Context, filter *models.ScheduledTransferFilter) ([]*models.ScheduledTransfer, int, *errors.Error) {
+	return s.repo.List(ctx, filter)
+}
+
+// ListExecutions retrieves the execution history of a scheduled transfer of the user.
+func (s *ScheduledTransferService) ListExecutions(ctx context.Context, userID, id string, limit, offset int) ([]*models.ScheduledTransferExecution, int, *errors.Error) {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListExecutions(ctx, id, limit, offset)
+}
+
+// Skip skips the next occurrence of a scheduled transfer.
+func (s *ScheduledTransferService) Skip(ctx context.Context, userID, id string) (*models.ScheduledTransfer, *errors.Error) {
+	return s.modify(ctx, userID, id, func(schedule *models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error) {
+		if !schedule.CanBeModified() || schedule.NextRunAt == nil {
+			return nil, errors.BadRequest(fmt.Sprintf("cannot skip a %s scheduled transfer", schedule.Status))
+		}
+
+		skipped := &models.ScheduledTransferExecution{
+			ScheduledTransferID: schedule.ID,
+			Occurrence:          schedule.Occurrences,
+			ScheduledFor:        *schedule.NextRunAt,
+			Status:              models.ExecutionStatusSkipped,
+		}
+		schedule.Advance()
+		return skipped, nil
+	})
+}
+
+// Pause stops an active scheduled transfer from running until it is resumed.
+func (s *ScheduledTransferService) Pause(ctx context.Context, userID, id string) (*models.ScheduledTransfer, *errors.Error) {
+	return s.modify(ctx, userID, id, func(schedule *models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error) {
+		if schedule.Status != models.ScheduleStatusActive {
+			return nil, errors.BadRequest(fmt.Sprintf("cannot pause a %s scheduled transfer", schedule.Status))
+		}
+		schedule.Status = models.ScheduleStatusPaused
+		return nil, nil
+	})
+}
+
+// Resume reactivates a paused scheduled transfer. Occurrences that fell due
+// while it was paused are skipped, not run.
+func (s *ScheduledTransferService) Resume(ctx context.Context, userID, id string) (*models.ScheduledTransfer, *errors.Error) {
+	return s.modify(ctx, userID, id, func(schedule *models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error) {
+		if schedule.Status != models.ScheduleStatusPaused {
+			return nil, errors.BadRequest(fmt.Sprintf("cannot resume a %s scheduled transfer", schedule.Status))
+		}
+		schedule.Status = models.ScheduleStatusActive
+		schedule.ConsecutiveFailures = 0
+		if schedule.Frequency != models.ScheduleFrequencyOnce {
+			schedule.SkipUntil(s.now())
+		}
+		return nil, nil
+	})
+}
+
+// Cancel stops a scheduled transfer permanently.
+func (s *ScheduledTransferService) Cancel(ctx context.Context, userID, id string) (*models.ScheduledTransfer, *errors.Error) {
+	return s.modify(ctx, userID, id, func(schedule *models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error) {
+		if !schedule.CanBeModified() {
+			return nil, errors.BadRequest(fmt.Sprintf("cannot cancel a %s scheduled transfer", schedule.Status))
+		}
+		schedule.Status = models.ScheduleStatusCancelled
+		schedule.NextRunAt = nil
+		return nil, nil
+	})
+}
+
+// modify applies a change requested by the user to one of their scheduled transfers.
+func (s *ScheduledTransferService) modify(ctx context.Context, userID, id string, modify func(*models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error)) (*models.ScheduledTransfer, *errors.Error) {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.Modify(ctx, id, modify)
+}
+
+// RunDue runs the occurrences of scheduled transfers that are due and returns
+// how many were run. It is called periodically by the scheduler worker.
+func (s *ScheduledTransferService) RunDue(ctx context.Context) (int, *errors.Error) {
+	schedules, err := s.repo.ClaimDue(ctx, scheduleBatchSize, scheduleLease)
+	if err != nil {
+		return 0, err
+	}
+
+	run := 0
+	for _, schedule := range schedules {
+		run += s.runSchedule(ctx, schedule)
+
+		if releaseErr := s.repo.Release(ctx, schedule.ID); releaseErr != nil {
+			s.logger.WithField("scheduled_transfer_id", schedule.ID).
+				WithError(releaseErr).Error("Failed to release scheduled transfer")
+		}
+	}
+
+	return run, nil
+}
+
+// runSchedule runs the due occurrences of a schedule in order, catching up on
+// any missed while the scheduler was not running.
+func (s *ScheduledTransferService) runSchedule(ctx context.Context, schedule *models.ScheduledTransfer) int {
+	run := 0
+	for schedule.Status == models.ScheduleStatusActive && schedule.NextRunAt != nil && !schedule.NextRunAt.Time.After(s.now()) {
+		next, err := s.runOccurrence(ctx, schedule)
+		if err != nil {
+			s.logger.WithField("scheduled_transfer_id", schedule.ID).
+				WithField("occurrence", schedule.Occurrences).
+				WithError(err).Error("Failed to run scheduled transfer")
+			return run
+		}
+		schedule = next
+		run++
+	}
+	return run
+}
+
+// runOccurrence creates the transfer for the current occurrence and records the outcome.
+// The transfer reference identifies the occurrence, so an occurrence interrupted
+// after its transfer was created is recorded without creating another.
+func (s *ScheduledTransferService) runOccurrence(ctx context.Context, schedule *models.ScheduledTransfer) (*models.ScheduledTransfer, *errors.Error) {
+	occurrence := schedule.Occurrences
+	execution, err := s.repo.StartExecution(ctx, &models.ScheduledTransferExecution{
+		ScheduledTransferID: schedule.ID,
+		Occurrence:          occurrence,
+		ScheduledFor:        *schedule.NextRunAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if execution.Status != models.ExecutionStatusPending {
+		// Recorded by a previous run that did not advance the schedule
+		return s.repo.Modify(ctx, schedule.ID, func(current *models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error) {
+			if current.Occurrences == occurrence {
+				current.Advance()
+			}
+			return nil, nil
+		})
+	}
+
+	reference := scheduleReference(schedule.ID, occurrence)
+	transaction, txErr := s.repo.FindTransferByReference(ctx, reference)
+	if txErr != nil {
+		if txErr.Code != errors.ErrCodeNotFound {
+			return nil, txErr
+		}
+		transaction, txErr = s.transfers.CreateTransfer(ctx, s.transferRequest(schedule, reference, occurrence))
+		if transaction == nil {
+			// A transfer rejected before it could be returned may still have been recorded
+			if recorded, findErr := s.repo.FindTransferByReference(ctx, reference); findErr == nil {
+				transaction = recorded
+			}
+		}
+	}
+
+	var failureReason *string
+	switch {
+	case transaction == nil:
+		failureReason = &txErr.Message
+		execution.Status = models.ExecutionStatusFailed
+	case transaction.IsFailed():
+		failureReason = transaction.FailureReason
+		if failureReason == nil && txErr != nil {
+			failureReason = &txErr.Message
+		}
+		execution.Status = models.ExecutionStatusFailed
+		execution.TransactionID = &transaction.ID
+	default:
+		execution.Status = models.ExecutionStatusExecuted
+		execution.TransactionID = &transaction.ID
+	}
+	execution.FailureReason = failureReason
+
+	updated, err := s.repo.Modify(ctx, schedule.ID, func(current *models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error) {
+		now := sharedModels.NewTimestamp(s.now())
+		current.LastRunAt = &now
+
+		if execution.Status == models.ExecutionStatusFailed {
+			current.ConsecutiveFailures++
+		} else {
+			current.ConsecutiveFailures = 0
+		}
+
+		if current.Occurrences == occurrence {
+			current.Advance()
+		}
+
+		if current.ConsecutiveFailures >= models.MaxConsecutiveScheduleFailures && current.Status == models.ScheduleStatusActive {
+			current.Status = models.ScheduleStatusPaused
+		}
+		return execution, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if execution.Status == models.ExecutionStatusFailed {
+		s.notifyFailure(updated, execution)
+	}
+
+	return updated, nil
+}
+
+func (s *ScheduledTransferService) transferRequest(schedule *models.ScheduledTransfer, reference string, occurrence int) *models.CreateTransferRequest {
+	metadata, _ := json.Marshal(map[string]string{
+		"scheduled_transfer_id": schedule.ID,
+		"occurrence":            strconv.Itoa(occurrence),
+	})
+
+	return &models.CreateTransferRequest{
+		SourceWalletID:      schedule.SourceWalletID,
+		DestinationWalletID: schedule.DestinationWalletID,
+		Amount:              schedule.Amount,
+		Currency:            schedule.Currency,
+		Description:         schedule.Description,
+		Reference:           reference,
+		MetadataRaw:         metadata,
+	}
+}
+
+// notifyFailure tells the user an occurrence failed, and whether the schedule
+// was paused as a result.
+func (s *ScheduledTransferService) notifyFailure(schedule *models.ScheduledTransfer, execution *models.ScheduledTransferExecution) {
+	if s.notifier == nil {
+		return
+	}
+
+	reason := "the transfer could not be completed"
+	if execution.FailureReason != nil {
+		reason = *execution.FailureReason
+	}
+
+	pausedNotice := ""
+	if schedule.Status == models.ScheduleStatusPaused {
+		pausedNotice = fmt.Sprintf(" The schedule has been paused after %d failed attempts in a row.", schedule.ConsecutiveFailures)
+	}
+
+	userID := schedule.UserID
+	correlationID := fmt.Sprintf("scheduled-transfer-%s-%d", schedule.ID, execution.Occurrence)
+
+	s.notifier.SendNotificationAsync(&clients.SendNotificationRequest{
+		UserID:     &userID,
+		Recipient:  userID,
+		Channel:    clients.NotificationChannelInApp,
+		Type:       clients.NotificationTypeTransactionAlert,
+		Priority:   clients.NotificationPriorityHigh,
+		TemplateID: scheduledTransferFailedTemplate,
+		Variables: map[string]any{
+			"amount":        formatAmount(schedule.Amount),
+			"currency":      string(schedule.Currency),
+			"description":   schedule.Description,
+			"scheduled_for": execution.ScheduledFor.Time.Format("2006-01-02 15:04 MST"),
+			"reason":        reason,
+			"paused_notice": pausedNotice,
+		},
+		CorrelationID: &correlationID,
+		SourceService: "transaction",
+		Metadata: map[string]any{
+			"scheduled_transfer_id": schedule.ID,
+			"occurrence":            execution.Occurrence,
+		},
+	}, "transaction")
+}
+
+// scheduleReference is the transfer reference of an occurrence.
+func scheduleReference(scheduleID string, occurrence int) string {
+	return fmt.Sprintf("sched-%s-%d", scheduleID, occurrence)
+}
@@ -0,0 +1,597 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// =====================================================================
+// Mocks for Scheduled Transfer Tests
+// =====================================================================
+
+type mockScheduledTransferRepository struct {
+	schedules  map[string]*models.ScheduledTransfer
+	executions map[string]*models.ScheduledTransferExecution // By schedule ID and occurrence
+	transfers  map[string]*models.Transaction                // By reference
+	now        func() time.Time
+}
+
+func newMockScheduledTransferRepository(now func() time.Time) *mockScheduledTransferRepository {
+	return &mockScheduledTransferRepository{
+		schedules:  make(map[string]*models.ScheduledTransfer),
+		executions: make(map[string]*models.ScheduledTransferExecution),
+		transfers:  make(map[string]*models.Transaction),
+		now:        now,
+	}
+}
+
+func executionKey(scheduleID string, occurrence int) string {
+	return fmt.Sprintf("%s/%d", scheduleID, occurrence)
+}
+
+func (m *mockScheduledTransferRepository) Create(ctx context.Context, s *models.ScheduledTransfer) *errors.Error {
+	s.ID = uuid.New().String()
+	stored := *s
+	m.schedules[s.ID] = &stored
+	return nil
+}
+
+func (m *mockScheduledTransferRepository) GetByID(ctx context.Context, id string) (*models.ScheduledTransfer, *errors.Error) {
+	s, ok := m.schedules[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("scheduled transfer", id)
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (m *mockScheduledTransferRepository) List(ctx context.Context, filter *models.ScheduledTransferFilter) ([]*models.ScheduledTransfer, int, *errors.Error) {
+	var schedules []*models.ScheduledTransfer
+	for _, s := range m.schedules {
+		if s.UserID == filter.UserID {
+			schedules = append(schedules, s)
+		}
+	}
+	return schedules, len(schedules), nil
+}
+
+func (m *mockScheduledTransferRepository) Modify(ctx context.Context, id string, modify func(*models.ScheduledTransfer) (*models.ScheduledTransferExecution, *errors.Error)) (*models.ScheduledTransfer, *errors.Error) {
+	s, err := m.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	execution, err := modify(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if execution != nil {
+		key := executionKey(id, execution.Occurrence)
+		if existing, ok := m.executions[key]; ok {
+			if existing.Status != models.ExecutionStatusPending || execution.Status == models.ExecutionStatusSkipped {
+				return nil, errors.Conflict("occurrence already recorded")
+			}
+		}
+		recorded := *execution
+		m.executions[key] = &recorded
+	}
+
+	stored := *s
+	m.schedules[id] = &stored
+	return s, nil
+}
+
+func (m *mockScheduledTransferRepository) StartExecution(ctx context.Context, e *models.ScheduledTransferExecution) (*models.ScheduledTransferExecution, *errors.Error) {
+	key := executionKey(e.ScheduledTransferID, e.Occurrence)
+	if existing, ok := m.executions[key]; ok {
+		copied := *existing
+		return &copied, nil
+	}
+	e.Status = models.ExecutionStatusPending
+	recorded := *e
+	m.executions[key] = &recorded
+	return e, nil
+}
+
+func (m *mockScheduledTransferRepository) ListExecutions(ctx context.Context, scheduleID string, limit, offset int) ([]*models.ScheduledTransferExecution, int, *errors.Error) {
+	var executions []*models.ScheduledTransferExecution
+	for n := 0; ; n++ {
+		e, ok := m.executions[executionKey(scheduleID, n)]
+		if !ok {
+			break
+		}
+		executions = append(executions, e)
+	}
+	return executions, len(executions), nil
+}
+
+func (m *mockScheduledTransferRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.ScheduledTransfer, *errors.Error) {
+	var due []*models.ScheduledTransfer
+	for _, s := range m.schedules {
+		if s.Status == models.ScheduleStatusActive && s.NextRunAt != nil && !s.NextRunAt.Time.After(m.now()) {
+			copied := *s
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (m *mockScheduledTransferRepository) Release(ctx context.Context, id string) *errors.Error {
+	return nil
+}
+
+func (m *mockScheduledTransferRepository) FindTransferByReference(ctx context.Context, reference string) (*models.Transaction, *errors.Error) {
+	tx, ok := m.transfers[reference]
+	if !ok {
+		return nil, errors.NotFound("transaction")
+	}
+	return tx, nil
+}
+
+type mockTransferCreator struct {
+	recorded map[string]*models.Transaction // Created transfers by reference
+	requests []*models.CreateTransferRequest
+	status   models.TransactionStatus // Status of created transfers; completed when empty
+	err      *errors.Error            // Returned without creating a transfer
+}
+
+func (m *mockTransferCreator) CreateTransfer(ctx context.Context, req *models.CreateTransferRequest) (*models.Transaction, *errors.Error) {
+	m.requests = append(m.requests, req)
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	tx := &models.Transaction{
+		ID:     uuid.New().String(),
+		Status: models.TransactionStatusCompleted,
+	}
+	if m.status != "" {
+		tx.Status = m.status
+	}
+	if tx.IsFailed() {
+		reason := "insufficient balance"
+		tx.FailureReason = &reason
+	}
+	m.recorded[req.Reference] = tx
+	return tx, nil
+}
+
+type mockNotificationSender struct {
+	sent []*clients.SendNotificationRequest
+}
+
+func (m *mockNotificationSender) SendNotificationAsync(req *clients.SendNotificationRequest, serviceName string) {
+	m.sent = append(m.sent, req)
+}
+
+type scheduledTransferFixture struct {
+	service   *ScheduledTransferService
+	repo      *mockScheduledTransferRepository
+	transfers *mockTransferCreator
+	notifier  *mockNotificationSender
+	verifier  *mockStepUpVerifier
+	now       time.Time
+}
+
+func newScheduledTransferFixture() *scheduledTransferFixture {
+	f := &scheduledTransferFixture{
+		now:      time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC),
+		notifier: &mockNotificationSender{},
+		verifier: &mockStepUpVerifier{},
+	}
+	clock := func() time.Time { return f.now }
+	f.repo = newMockScheduledTransferRepository(clock)
+	f.transfers = &mockTransferCreator{recorded: f.repo.transfers}
+	f.service = NewScheduledTransferService(f.repo, f.transfers, f.notifier)
+	f.service.SetStepUpVerifier(f.verifier)
+	f.service.now = clock
+	return f
+}
+
+func (f *scheduledTransferFixture) request(frequency models.ScheduleFrequency, startAt time.Time) *models.CreateScheduledTransferRequest {
+	return &models.CreateScheduledTransferRequest{
+		SourceWalletID:      "wallet-source",
+		DestinationWalletID: "wallet-dest",
+		Amount:              50000,
+		Currency:            sharedModels.INR,
+		Description:         "Rent",
+		Frequency:           frequency,
+		StartAt:             startAt.Format(time.RFC3339),
+	}
+}
+
+// create schedules a transfer for user-1 and moves the clock to advance.
+func (f *scheduledTransferFixture) create(t *testing.T, req *models.CreateScheduledTransferRequest, advance time.Duration) *models.ScheduledTransfer {
+	t.Helper()
+	schedule, err := f.service.Create(context.Background(), "user-1", req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	f.now = f.now.Add(advance)
+	return schedule
+}
+
+// =====================================================================
+// Scheduled Transfer Tests
+// =====================================================================
+
+func TestCreateScheduledTransfer_Validation(t *testing.T) {
+	f := newScheduledTransferFixture()
+	count := 0
+
+	tests := []struct {
+		name   string
+		modify func(req *models.CreateScheduledTransferRequest)
+	}{
+		{"same wallet", func(req *models.CreateScheduledTransferRequest) { req.DestinationWalletID = req.SourceWalletID }},
+		{"unknown frequency", func(req *models.CreateScheduledTransferRequest) { req.Frequency = "hourly" }},
+		{"invalid start", func(req *models.CreateScheduledTransferRequest) { req.StartAt = "tomorrow" }},
+		{"start in the past", func(req *models.CreateScheduledTransferRequest) {
+			req.StartAt = f.now.Add(-time.Hour).Format(time.RFC3339)
+		}},
+		{"end before start", func(req *models.CreateScheduledTransferRequest) {
+			req.EndAt = f.now.Format(time.RFC3339)
+		}},
+		{"non-positive count", func(req *models.CreateScheduledTransferRequest) { req.MaxOccurrences = &count }},
+		{"bounds on one-off transfer", func(req *models.CreateScheduledTransferRequest) {
+			req.Frequency = models.ScheduleFrequencyOnce
+			req.MaxOccurrences = &count
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := f.request(models.ScheduleFrequencyMonthly, f.now.Add(24*time.Hour))
+			tt.modify(req)
+
+			_, err := f.service.Create(context.Background(), "user-1", req)
+			if err == nil || err.Code != errors.ErrCodeBadRequest {
+				t.Errorf("expected bad request, got %v", err)
+			}
+		})
+	}
+
+	if len(f.repo.schedules) != 0 {
+		t.Errorf("expected no schedules created, got %d", len(f.repo.schedules))
+	}
+}
+
+func TestCreateScheduledTransfer_FirstRunAtStart(t *testing.T) {
+	f := newScheduledTransferFixture()
+	startAt := f.now.Add(48 * time.Hour)
+
+	schedule := f.create(t, f.request(models.ScheduleFrequencyWeekly, startAt), 0)
+
+	if schedule.Status != models.ScheduleStatusActive {
+		t.Errorf("expected status active, got %s", schedule.Status)
+	}
+	if schedule.NextRunAt == nil || !schedule.NextRunAt.Time.Equal(startAt) {
+		t.Errorf("expected next run at %v, got %v", startAt, schedule.NextRunAt)
+	}
+}
+
+func TestCreateScheduledTransfer_HighValueRequiresVerification(t *testing.T) {
+	f := newScheduledTransferFixture()
+	req := f.request(models.ScheduleFrequencyOnce, f.now.Add(time.Hour))
+	req.Amount = clients.HighValueTransferThreshold + 1
+
+	_, err := f.service.Create(context.Background(), "user-1", req)
+	if err == nil || err.Code != errors.ErrCodeVerificationRequired {
+		t.Fatalf("expected verification required, got %v", err)
+	}
+	if len(f.repo.schedules) != 0 {
+		t.Fatalf("expected no schedule created, got %d", len(f.repo.schedules))
+	}
+
+	req.VerificationToken = "ver-token"
+	f.create(t, req, 2*time.Hour)
+	if len(f.verifier.redeemed) != 1 {
+		t.Fatalf("expected token redeemed once, got %d", len(f.verifier.redeemed))
+	}
+	if bound := f.verifier.redeemed[0]; bound["amount"] != req.Amount || bound["destination_wallet_id"] != req.DestinationWalletID {
+		t.Errorf("expected token bound to amount and destination, got %v", bound)
+	}
+
+	// The schedule was verified when it was created, so it runs without another token
+	if _, err := f.service.RunDue(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(f.transfers.requests) != 1 {
+		t.Errorf("expected 1 transfer, got %d", len(f.transfers.requests))
+	}
+}
+
+func TestScheduledTransferOccurrenceAt_MonthlyClampsToMonthEnd(t *testing.T) {
+	schedule := &models.ScheduledTransfer{
+		Frequency: models.ScheduleFrequencyMonthly,
+		StartAt:   sharedModels.NewTimestamp(time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)),
+	}
+
+	expected := []time.Time{
+		time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 30, 10, 0, 0, 0, time.UTC),
+		time.Date(2025, 2, 28, 10, 0, 0, 0, time.UTC),
+	}
+	for i, n := range []int{0, 1, 2, 3, 13} {
+		if got := schedule.OccurrenceAt(n); !got.Equal(expected[i]) {
+			t.Errorf("occurrence %d: expected %v, got %v", n, expected[i], got)
+		}
+	}
+}
+
+func TestRunDue_CatchesUpAndCompletesAfterMaxOccurrences(t *testing.T) {
+	f := newScheduledTransferFixture()
+	req := f.request(models.ScheduleFrequencyDaily, f.now)
+	maxOccurrences := 3
+	req.MaxOccurrences = &maxOccurrences
+	schedule := f.create(t, req, 5*24*time.Hour)
+
+	run, err := f.service.RunDue(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if run != 3 {
+		t.Errorf("expected 3 occurrences run, got %d", run)
+	}
+	if len(f.transfers.requests) != 3 {
+		t.Fatalf("expected 3 transfers, got %d", len(f.transfers.requests))
+	}
+	for n, transfer := range f.transfers.requests {
+		if transfer.Reference != scheduleReference(schedule.ID, n) {
+			t.Errorf("expected reference %s, got %s", scheduleReference(schedule.ID, n), transfer.Reference)
+		}
+		if transfer.Amount != 50000 || transfer.SourceWalletID != "wallet-source" {
+			t.Errorf("expected transfer of the schedule, got %+v", transfer)
+		}
+	}
+
+	stored := f.repo.schedules[schedule.ID]
+	if stored.Status != models.ScheduleStatusCompleted || stored.NextRunAt != nil {
+		t.Errorf("expected completed schedule without next run, got %s %v", stored.Status, stored.NextRunAt)
+	}
+
+	executions, _, _ := f.repo.ListExecutions(context.Background(), schedule.ID, 10, 0)
+	if len(executions) != 3 {
+		t.Fatalf("expected 3 executions, got %d", len(executions))
+	}
+	for _, e := range executions {
+		if e.Status != models.ExecutionStatusExecuted || e.TransactionID == nil {
+			t.Errorf("expected executed occurrence with transaction, got %+v", e)
+		}
+	}
+	if len(f.notifier.sent) != 0 {
+		t.Errorf("expected no notifications, got %d", len(f.notifier.sent))
+	}
+}
+
+func TestRunDue_StopsAtEndDate(t *testing.T) {
+	f := newScheduledTransferFixture()
+	req := f.request(models.ScheduleFrequencyWeekly, f.now)
+	req.EndAt = f.now.Add(15 * 24 * time.Hour).Format(time.RFC3339)
+	schedule := f.create(t, req, 30*24*time.Hour)
+
+	run, _ := f.service.RunDue(context.Background())
+
+	if run != 3 {
+		t.Errorf("expected 3 occurrences run (days 0, 7 and 14), got %d", run)
+	}
+	if f.repo.schedules[schedule.ID].Status != models.ScheduleStatusCompleted {
+		t.Errorf("expected completed schedule, got %s", f.repo.schedules[schedule.ID].Status)
+	}
+}
+
+func TestRunDue_DoesNotRunFutureOccurrences(t *testing.T) {
+	f := newScheduledTransferFixture()
+	schedule := f.create(t, f.request(models.ScheduleFrequencyMonthly, f.now), time.Hour)
+
+	run, _ := f.service.RunDue(context.Background())
+
+	if run != 1 {
+		t.Errorf("expected 1 occurrence run, got %d", run)
+	}
+	stored := f.repo.schedules[schedule.ID]
+	expectedNext := time.Date(2025, 2, 10, 9, 0, 0, 0, time.UTC)
+	if stored.Status != models.ScheduleStatusActive || !stored.NextRunAt.Time.Equal(expectedNext) {
+		t.Errorf("expected active schedule next due %v, got %s %v", expectedNext, stored.Status, stored.NextRunAt)
+	}
+}
+
+func TestRunDue_FailureNotifiesAndPausesAfterRepeatedFailures(t *testing.T) {
+	f := newScheduledTransferFixture()
+	f.transfers.status = models.TransactionStatusFailed
+	schedule := f.create(t, f.request(models.ScheduleFrequencyDaily, f.now), 10*24*time.Hour)
+
+	run, _ := f.service.RunDue(context.Background())
+
+	if run != models.MaxConsecutiveScheduleFailures {
+		t.Errorf("expected %d occurrences run before pausing, got %d", models.MaxConsecutiveScheduleFailures, run)
+	}
+	stored := f.repo.schedules[schedule.ID]
+	if stored.Status != models.ScheduleStatusPaused {
+		t.Errorf("expected paused schedule, got %s", stored.Status)
+	}
+
+	executions, _, _ := f.repo.ListExecutions(context.Background(), schedule.ID, 10, 0)
+	for _, e := range executions {
+		if e.Status != models.ExecutionStatusFailed || e.FailureReason == nil || *e.FailureReason != "insufficient balance" {
+			t.Errorf("expected failed occurrence with reason, got %+v", e)
+		}
+	}
+
+	if len(f.notifier.sent) != 3 {
+		t.Fatalf("expected 3 failure notifications, got %d", len(f.notifier.sent))
+	}
+	first, last := f.notifier.sent[0], f.notifier.sent[2]
+	if first.TemplateID != scheduledTransferFailedTemplate || *first.UserID != "user-1" {
+		t.Errorf("expected failure template for user-1, got %s for %s", first.TemplateID, *first.UserID)
+	}
+	if first.Variables["paused_notice"] != "" {
+		t.Errorf("expected no paused notice on first failure, got %q", first.Variables["paused_notice"])
+	}
+	if last.Variables["paused_notice"] == "" {
+		t.Error("expected paused notice once the schedule is paused")
+	}
+}
+
+func TestRunDue_TransferRejectedBeforeCreationIsRecordedAsFailed(t *testing.T) {
+	f := newScheduledTransferFixture()
+	f.transfers.err = errors.BadRequest("wallet is frozen")
+	schedule := f.create(t, f.request(models.ScheduleFrequencyOnce, f.now), time.Minute)
+
+	_, _ = f.service.RunDue(context.Background())
+
+	e := f.repo.executions[executionKey(schedule.ID, 0)]
+	if e.Status != models.ExecutionStatusFailed || e.TransactionID != nil || *e.FailureReason != "wallet is frozen" {
+		t.Errorf("expected failed occurrence without transaction, got %+v", e)
+	}
+	if f.repo.schedules[schedule.ID].Status != models.ScheduleStatusCompleted {
+		t.Errorf("expected one-off schedule completed, got %s", f.repo.schedules[schedule.ID].Status)
+	}
+	if len(f.notifier.sent) != 1 {
+		t.Errorf("expected 1 failure notification, got %d", len(f.notifier.sent))
+	}
+}
+
+func TestRunDue_InterruptedOccurrenceDoesNotTransferTwice(t *testing.T) {
+	f := newScheduledTransferFixture()
+	schedule := f.create(t, f.request(models.ScheduleFrequencyOnce, f.now), time.Minute)
+
+	// A previous run created the transfer but stopped before recording it
+	_, _ = f.repo.StartExecution(context.Background(), &models.ScheduledTransferExecution{
+		ScheduledTransferID: schedule.ID,
+		ScheduledFor:        schedule.StartAt,
+	})
+	f.repo.transfers[scheduleReference(schedule.ID, 0)] = &models.Transaction{ID: "tx-1", Status: models.TransactionStatusCompleted}
+
+	_, _ = f.service.RunDue(context.Background())
+
+	if len(f.transfers.requests) != 0 {
+		t.Errorf("expected no new transfer, got %d", len(f.transfers.requests))
+	}
+	e := f.repo.executions[executionKey(schedule.ID, 0)]
+	if e.Status != models.ExecutionStatusExecuted || e.TransactionID == nil || *e.TransactionID != "tx-1" {
+		t.Errorf("expected occurrence recorded with existing transfer, got %+v", e)
+	}
+}
+
+func TestSkipScheduledTransfer_RecordsSkipAndAdvances(t *testing.T) {
+	f := newScheduledTransferFixture()
+	schedule := f.create(t, f.request(models.ScheduleFrequencyWeekly, f.now.Add(time.Hour)), 0)
+
+	updated, err := f.service.Skip(context.Background(), "user-1", schedule.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if updated.Occurrences != 1 {
+		t.Errorf("expected 1 occurrence consumed, got %d", updated.Occurrences)
+	}
+	expectedNext := f.now.Add(time.Hour + 7*24*time.Hour)
+	if !updated.NextRunAt.Time.Equal(expectedNext) {
+		t.Errorf("expected next run at %v, got %v", expectedNext, updated.NextRunAt)
+	}
+	if e := f.repo.executions[executionKey(schedule.ID, 0)]; e == nil || e.Status != models.ExecutionStatusSkipped {
+		t.Errorf("expected skipped execution, got %+v", e)
+	}
+}
+
+func TestSkipScheduledTransfer_RejectsOccurrenceInProgress(t *testing.T) {
+	f := newScheduledTransferFixture()
+	schedule := f.create(t, f.request(models.ScheduleFrequencyWeekly, f.now), 0)
+	_, _ = f.repo.StartExecution(context.Background(), &models.ScheduledTransferExecution{
+		ScheduledTransferID: schedule.ID,
+		ScheduledFor:        schedule.StartAt,
+	})
+
+	_, err := f.service.Skip(context.Background(), "user-1", schedule.ID)
+
+	if err == nil || err.Code != errors.ErrCodeConflict {
+		t.Errorf("expected conflict, got %v", err)
+	}
+	if f.repo.schedules[schedule.ID].Occurrences != 0 {
+		t.Error("expected schedule not advanced")
+	}
+}
+
+func TestResumeScheduledTransfer_SkipsOccurrencesMissedWhilePaused(t *testing.T) {
+	f := newScheduledTransferFixture()
+	schedule := f.create(t, f.request(models.ScheduleFrequencyDaily, f.now.Add(time.Hour)), 0)
+
+	if _, err := f.service.Pause(context.Background(), "user-1", schedule.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	f.now = f.now.Add(3 * 24 * time.Hour)
+	if run, _ := f.service.RunDue(context.Background()); run != 0 {
+		t.Errorf("expected paused schedule not run, got %d", run)
+	}
+
+	resumed, err := f.service.Resume(context.Background(), "user-1", schedule.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if resumed.Status != models.ScheduleStatusActive {
+		t.Errorf("expected active schedule, got %s", resumed.Status)
+	}
+	if resumed.Occurrences != 3 {
+		t.Errorf("expected 3 missed occurrences skipped, got %d", resumed.Occurrences)
+	}
+	if !resumed.NextRunAt.Time.After(f.now) {
+		t.Errorf("expected next run in the future, got %v", resumed.NextRunAt)
+	}
+	if len(f.transfers.requests) != 0 {
+		t.Errorf("expected no transfers, got %d", len(f.transfers.requests))
+	}
+}
+
+func TestCancelScheduledTransfer(t *testing.T) {
+	f := newScheduledTransferFixture()
+	schedule := f.create(t, f.request(models.ScheduleFrequencyMonthly, f.now), 0)
+
+	cancelled, err := f.service.Cancel(context.Background(), "user-1", schedule.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cancelled.Status != models.ScheduleStatusCancelled || cancelled.NextRunAt != nil {
+		t.Errorf("expected cancelled schedule without next run, got %s %v", cancelled.Status, cancelled.NextRunAt)
+	}
+
+	if run, _ := f.service.RunDue(context.Background()); run != 0 {
+		t.Errorf("expected cancelled schedule not run, got %d", run)
+	}
+	for _, action := range []func(context.Context, string, string) (*models.ScheduledTransfer, *errors.Error){
+		f.service.Skip, f.service.Pause, f.service.Resume, f.service.Cancel,
+	} {
+		if _, err := action(context.Background(), "user-1", schedule.ID); err == nil || err.Code != errors.ErrCodeBadRequest {
+			t.Errorf("expected bad request on cancelled schedule, got %v", err)
+		}
+	}
+}
+
+func TestScheduledTransfer_OtherUsersScheduleNotFound(t *testing.T) {
+	f := newScheduledTransferFixture()
+	schedule := f.create(t, f.request(models.ScheduleFrequencyMonthly, f.now), 0)
+
+	if _, err := f.service.Get(context.Background(), "user-2", schedule.ID); err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found on get, got %v", err)
+	}
+	if _, err := f.service.Cancel(context.Background(), "user-2", schedule.ID); err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found on cancel, got %v", err)
+	}
+	if f.repo.schedules[schedule.ID].Status != models.ScheduleStatusActive {
+		t.Error("expected schedule unchanged")
+	}
+}
@@ -0,0 +1,522 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// Saga tuning.
+const (
+	sagaLease             = 2 * time.Minute // Longer than the slowest single step
+	sagaMaxAttempts       = 8
+	sagaBaseBackoff       = 5 * time.Second
+	sagaMaxBackoff        = 5 * time.Minute
+	sagaRecoveryBatchSize = 50
+)
+
+// sagaPlans lists the steps each transaction type runs through, in order.
+// Deposits bring money in from outside, so there is nothing to reserve.
+// Refunds and reversals settle disputes (see dispute_service.go): a refund
+// credits a wallet from the settlement account like a deposit, a reversal
+// recovers money from a wallet into it like a withdrawal. The platform makes
+// them itself, so they skip risk evaluation.
+var sagaPlans = map[models.TransactionType][]models.SagaStep{
+	models.TransactionTypeTransfer: {
+		models.SagaStepRisk, models.SagaStepReserve, models.SagaStepWalletMove, models.SagaStepLedgerPost, models.SagaStepComplete,
+	},
+	models.TransactionTypeWithdrawal: {
+		models.SagaStepRisk, models.SagaStepReserve, models.SagaStepWalletMove, models.SagaStepLedgerPost, models.SagaStepComplete,
+	},
+	models.TransactionTypeDeposit: {
+		models.SagaStepRisk, models.SagaStepWalletMove, models.SagaStepLedgerPost, models.SagaStepComplete,
+	},
+	models.TransactionTypeRefund: {
+		models.SagaStepWalletMove, models.SagaStepLedgerPost, models.SagaStepComplete,
+	},
+	models.TransactionTypeReversal: {
+		models.SagaStepReserve, models.SagaStepWalletMove, models.SagaStepLedgerPost, models.SagaStepComplete,
+	},
+}
+
+// sagaPivot is the step after which a saga only moves forward: once a
+// transaction is in the ledger it is completed, never compensated.
+const sagaPivot = models.SagaStepLedgerPost
+
+// SagaRepositoryInterface defines the interface for saga repository operations.
+type SagaRepositoryInterface interface {
+	Start(ctx context.Context, saga *models.TransactionSaga) (*models.TransactionSaga, *errors.Error)
+	Get(ctx context.Context, transactionID string) (*models.TransactionSaga, *errors.Error)
+	Update(ctx context.Context, saga *models.TransactionSaga) *errors.Error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.TransactionSaga, *errors.Error)
+}
+
+// stepFailure describes a failed saga step or compensation.
+type stepFailure struct {
+	err       *errors.Error // Returned to the caller
+	reason    string        // Recorded on the transaction if it fails
+	permanent bool          // Retrying cannot help
+}
+
+func newStepFailure(err *errors.Error) *stepFailure {
+	return &stepFailure{err: err, reason: err.Message, permanent: isPermanentError(err)}
+}
+
+// isPermanentError reports whether an error is a rejection rather than an
+// outage: client errors other than timeouts and rate limiting.
+func isPermanentError(err *errors.Error) bool {
+	status := err.HTTPStatusCode()
+	return status >= 400 && status < 500 &&
+		status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+// sagaBackoff returns the delay after the given number of failed attempts.
+func sagaBackoff(attempts int) time.Duration {
+	delay := sagaBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= sagaMaxBackoff {
+			return sagaMaxBackoff
+		}
+	}
+	return delay
+}
+
+// newSaga returns a saga at its first step, leased to the caller that is about to run it.
+func (s *TransactionService) newSaga(transactionID string) *models.TransactionSaga {
+	return &models.TransactionSaga{
+		TransactionID:  transactionID,
+		State:          models.SagaStateRunning,
+		CurrentStep:    models.SagaStepRisk,
+		CompletedSteps: []models.SagaStep{},
+		NextAttemptAt:  sharedModels.NewTimestamp(s.now().Add(sagaLease)),
+	}
+}
+
+// executeSaga runs the saga of a new transaction and returns the transaction
+// in its resulting state. A rejection by risk evaluation is returned as an
+// error (fail-closed when the risk service is unavailable); any other failure
+// is reflected in the transaction status, and retries are left to recovery.
+func (s *TransactionService) executeSaga(ctx context.Context, saga *models.TransactionSaga) (*models.Transaction, *errors.Error) {
+	runErr := s.runSaga(ctx, saga)
+	if runErr != nil {
+		s.logger.WithError(runErr).With(map[string]interface{}{
+			"transaction_id": saga.TransactionID,
+			"step":           string(saga.CurrentStep),
+			"state":          string(saga.State),
+		}).Warn("Transaction saga did not complete")
+	}
+
+	transaction, err := s.transactionRepo.GetByID(ctx, saga.TransactionID)
+
+	if runErr != nil && saga.CurrentStep == models.SagaStepRisk && saga.State == models.SagaStateCompensated {
+		if runErr.Code == errors.ErrCodeBadRequest && err == nil {
+			return transaction, runErr
+		}
+		return nil, runErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// RecoverSagas resumes sagas that are due: ones waiting to retry a step and
+// ones left behind by a restart, whose lease has expired. It returns the
+// number of sagas resumed.
+func (s *TransactionService) RecoverSagas(ctx context.Context) (int, *errors.Error) {
+	total := 0
+	for {
+		sagas, err := s.sagaRepo.ClaimDue(ctx, sagaRecoveryBatchSize, sagaLease)
+		if err != nil {
+			return total, err
+		}
+
+		for _, saga := range sagas {
+			if runErr := s.runSaga(ctx, saga); runErr != nil {
+				s.logger.WithError(runErr).With(map[string]interface{}{
+					"transaction_id": saga.TransactionID,
+					"step":           string(saga.CurrentStep),
+					"state":          string(saga.State),
+				}).Warn("Recovered saga did not complete")
+			}
+		}
+		total += len(sagas)
+
+		if len(sagas) < sagaRecoveryBatchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+// runSaga drives a saga until it finishes or has to wait to retry a step,
+// persisting its progress after every step. It returns nil once the
+// transaction has completed and otherwise the error that stopped it.
+func (s *TransactionService) runSaga(ctx context.Context, saga *models.TransactionSaga) *errors.Error {
+	transaction, err := s.transactionRepo.GetByID(ctx, saga.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	plan, ok := sagaPlans[transaction.Type]
+	if !ok {
+		return errors.BadRequest(fmt.Sprintf("no saga for %s transactions", transaction.Type))
+	}
+
+	var failure *stepFailure
+	for saga.IsActive() {
+		var f *stepFailure
+		if saga.State == models.SagaStateRunning {
+			f = s.advance(ctx, transaction, saga, plan)
+		} else {
+			f = s.compensate(ctx, transaction, saga)
+		}
+
+		startedCompensation := false
+		if f != nil {
+			failure = f
+			startedCompensation = s.recordFailure(transaction, saga, f)
+		}
+
+		if updateErr := s.sagaRepo.Update(ctx, saga); updateErr != nil {
+			return updateErr
+		}
+
+		if f != nil && !startedCompensation {
+			// Waiting to retry, or stopped for manual review
+			return f.err
+		}
+	}
+
+	switch saga.State {
+	case models.SagaStateCompleted:
+		return nil
+	case models.SagaStateCompensated:
+		if failure != nil {
+			return failure.err
+		}
+		return errors.TransactionFailed(derefString(saga.FailureReason))
+	default:
+		return errors.TransactionFailed(fmt.Sprintf("transaction needs manual review: %s", derefString(saga.LastError)))
+	}
+}
+
+// advance runs the next step of the plan.
+func (s *TransactionService) advance(ctx context.Context, transaction *models.Transaction, saga *models.TransactionSaga, plan []models.SagaStep) *stepFailure {
+	var next models.SagaStep
+	for _, step := range plan {
+		if !saga.HasCompleted(step) {
+			next = step
+			break
+		}
+	}
+
+	if next == "" {
+		saga.State = models.SagaStateCompleted
+		return nil
+	}
+
+	saga.CurrentStep = next
+	if f := s.executeStep(ctx, transaction, next); f != nil {
+		return f
+	}
+
+	saga.CompletedSteps = append(saga.CompletedSteps, next)
+	s.resetAttempts(saga)
+	return nil
+}
+
+// compensate undoes the most recently completed step, and fails the
+// transaction once nothing is left to undo.
+func (s *TransactionService) compensate(ctx context.Context, transaction *models.Transaction, saga *models.TransactionSaga) *stepFailure {
+	if len(saga.CompletedSteps) == 0 {
+		if f := s.failTransaction(ctx, transaction, derefString(saga.FailureReason)); f != nil {
+			return f
+		}
+		saga.State = models.SagaStateCompensated
+		return nil
+	}
+
+	step := saga.CompletedSteps[len(saga.CompletedSteps)-1]
+	if f := s.compensateStep(ctx, transaction, step); f != nil {
+		return f
+	}
+
+	saga.CompletedSteps = saga.CompletedSteps[:len(saga.CompletedSteps)-1]
+	s.resetAttempts(saga)
+	return nil
+}
+
+func (s *TransactionService) resetAttempts(saga *models.TransactionSaga) {
+	saga.Attempts = 0
+	saga.LastError = nil
+	saga.NextAttemptAt = sharedModels.NewTimestamp(s.now().Add(sagaLease))
+}
+
+// recordFailure decides what happens after a failed step or compensation:
+// retry with backoff, start compensating, or stop for manual review. It
+// returns true when the saga starts compensating.
+func (s *TransactionService) recordFailure(transaction *models.Transaction, saga *models.TransactionSaga, f *stepFailure) bool {
+	saga.Attempts++
+	lastError := f.err.Error()
+	saga.LastError = &lastError
+
+	log := s.logger.WithError(f.err).With(map[string]interface{}{
+		"transaction_id": transaction.ID,
+		"step":           string(saga.CurrentStep),
+		"attempts":       saga.Attempts,
+	})
+
+	if !f.permanent && saga.Attempts < sagaMaxAttempts {
+		saga.NextAttemptAt = sharedModels.NewTimestamp(s.now().Add(sagaBackoff(saga.Attempts)))
+		log.Warn("Saga step failed, will retry")
+		return false
+	}
+
+	if saga.State == models.SagaStateCompensating || saga.HasCompleted(sagaPivot) {
+		saga.State = models.SagaStateManualReview
+		log.Error("Saga cannot make progress - manual review needed")
+		return false
+	}
+
+	saga.State = models.SagaStateCompensating
+	saga.FailureReason = &f.reason
+	// A step can take effect and still fail (a timeout, say), so the failed
+	// step is undone too; compensations are no-ops when it did not
+	if compensable(saga.CurrentStep) {
+		saga.CompletedSteps = append(saga.CompletedSteps, saga.CurrentStep)
+	}
+	s.resetAttempts(saga)
+	log.Warn("Saga step failed, compensating")
+	return true
+}
+
+// compensable reports whether a step has a compensating action.
+func compensable(step models.SagaStep) bool {
+	return step == models.SagaStepReserve || step == models.SagaStepWalletMove
+}
+
+// executeStep runs one forward step. Every step is idempotent per transaction,
+// so a step interrupted by a restart is simply run again.
+func (s *TransactionService) executeStep(ctx context.Context, transaction *models.Transaction, step models.SagaStep) *stepFailure {
+	switch step {
+	case models.SagaStepRisk:
+		return s.checkRisk(ctx, transaction)
+	case models.SagaStepReserve:
+		return s.reserveFunds(ctx, transaction)
+	case models.SagaStepWalletMove:
+		return s.moveFunds(ctx, transaction)
+	case models.SagaStepLedgerPost:
+		if s.ledgerPoster == nil {
+			return newStepFailure(errors.Internal("ledger poster not configured"))
+		}
+		if err := s.ledgerPoster.Post(ctx, transaction.ID); err != nil {
+			return newStepFailure(err)
+		}
+		return nil
+	case models.SagaStepComplete:
+		return s.completeTransaction(ctx, transaction)
+	default:
+		return newStepFailure(errors.Internal(fmt.Sprintf("unknown saga step %q", step)))
+	}
+}
+
+// compensateStep undoes one completed step.
+func (s *TransactionService) compensateStep(ctx context.Context, transaction *models.Transaction, step models.SagaStep) *stepFailure {
+	if !compensable(step) {
+		return nil
+	}
+	if s.walletClient == nil {
+		return newStepFailure(errors.Internal("wallet client not configured"))
+	}
+
+	var err *errors.Error
+	switch step {
+	case models.SagaStepReserve:
+		err = s.walletClient.ReleaseHold(ctx, transaction.ID)
+	case models.SagaStepWalletMove:
+		err = s.walletClient.ReverseMovement(ctx, transaction.ID)
+	}
+	if err != nil {
+		return newStepFailure(err)
+	}
+	return nil
+}
+
+// checkRisk evaluates the transaction. A block, or an unavailable risk
+// service, fails the transaction without retrying.
+func (s *TransactionService) checkRisk(ctx context.Context, transaction *models.Transaction) *stepFailure {
+	result, err := s.evaluateTransactionRisk(ctx, transaction)
+	if err != nil {
+		s.logger.WithError(err).WithField("transaction_id", transaction.ID).Error("Risk evaluation failed - blocking transaction")
+		return &stepFailure{
+			err:       errors.Internal("transaction blocked: risk service unavailable"),
+			reason:    "risk evaluation unavailable",
+			permanent: true,
+		}
+	}
+
+	if result != nil && !result.Allowed {
+		return &stepFailure{
+			err:       errors.BadRequest("transaction blocked by risk evaluation"),
+			reason:    fmt.Sprintf("blocked by risk: %s", result.Reason),
+			permanent: true,
+		}
+	}
+
+	return nil
+}
+
+// reserveFunds holds the amount in the source wallet.
+func (s *TransactionService) reserveFunds(ctx context.Context, transaction *models.Transaction) *stepFailure {
+	if transaction.SourceWalletID == nil {
+		return newStepFailure(errors.BadRequest(fmt.Sprintf("%s must have a source wallet", transaction.Type)))
+	}
+	if s.walletClient == nil {
+		return newStepFailure(errors.Internal("wallet client not configured"))
+	}
+
+	err := s.walletClient.HoldFunds(ctx, &HoldRequest{
+		WalletID:      *transaction.SourceWalletID,
+		Amount:        transaction.Amount,
+		TransactionID: transaction.ID,
+	})
+	if err != nil {
+		return newStepFailure(err)
+	}
+	return nil
+}
+
+// moveFunds moves the balances in the wallet service and marks the transaction processing.
+func (s *TransactionService) moveFunds(ctx context.Context, transaction *models.Transaction) *stepFailure {
+	if s.walletClient == nil {
+		return newStepFailure(errors.Internal("wallet client not configured"))
+	}
+
+	var err *errors.Error
+	switch transaction.Type {
+	case models.TransactionTypeTransfer:
+		if transaction.SourceWalletID == nil || transaction.DestinationWalletID == nil {
+			return newStepFailure(errors.BadRequest("transfer must have both source and destination wallets"))
+		}
+		err = s.walletClient.ExecuteTransfer(ctx, &TransferRequest{
+			SourceWalletID:      *transaction.SourceWalletID,
+			DestinationWalletID: *transaction.DestinationWalletID,
+			Amount:              transaction.Amount,
+			TransactionID:       transaction.ID,
+			Description:         transaction.Description,
+		})
+	case models.TransactionTypeDeposit, models.TransactionTypeRefund:
+		if transaction.DestinationWalletID == nil {
+			return newStepFailure(errors.BadRequest(fmt.Sprintf("%s must have a destination wallet", transaction.Type)))
+		}
+		err = s.walletClient.CreditDeposit(ctx, &DepositRequest{
+			WalletID:      *transaction.DestinationWalletID,
+			Amount:        transaction.Amount,
+			TransactionID: transaction.ID,
+			Description:   transaction.Description,
+		})
+	case models.TransactionTypeWithdrawal, models.TransactionTypeReversal:
+		if transaction.SourceWalletID == nil {
+			return newStepFailure(errors.BadRequest(fmt.Sprintf("%s must have a source wallet", transaction.Type)))
+		}
+		err = s.walletClient.DebitWithdrawal(ctx, &WithdrawalRequest{
+			WalletID:      *transaction.SourceWalletID,
+			Amount:        transaction.Amount,
+			TransactionID: transaction.ID,
+		})
+	}
+	if err != nil {
+		return newStepFailure(err)
+	}
+
+	if transaction.Status == models.TransactionStatusPending {
+		if markErr := s.transactionRepo.MarkProcessed(ctx, transaction.ID); markErr != nil {
+			return newStepFailure(markErr)
+		}
+		transaction.Status = models.TransactionStatusProcessing
+	}
+
+	return nil
+}
+
+// completeTransaction marks the transaction completed together with its completion event.
+func (s *TransactionService) completeTransaction(ctx context.Context, transaction *models.Transaction) *stepFailure {
+	if transaction.Status == models.TransactionStatusCompleted {
+		return nil
+	}
+
+	eventType := "transaction.completed"
+	payload := map[string]interface{}{
+		"type":                  string(transaction.Type),
+		"status":                string(models.TransactionStatusCompleted),
+		"amount":                transaction.Amount,
+		"currency":              transaction.Currency,
+		"source_wallet_id":      transaction.SourceWalletID,
+		"destination_wallet_id": transaction.DestinationWalletID,
+	}
+	if isUPIDeposit(transaction) {
+		eventType = "transaction.upi_deposit.completed"
+		payload["upi_transaction_id"] = transaction.Metadata["external_upi_transaction_id"]
+	}
+
+	err := s.transactionRepo.UpdateStatus(ctx, transaction.ID, models.TransactionStatusCompleted, nil,
+		models.NewOutboxEvent(eventType, payload))
+	if err != nil {
+		return newStepFailure(err)
+	}
+	transaction.Status = models.TransactionStatusCompleted
+
+	s.logger.With(map[string]interface{}{
+		"transaction_id": transaction.ID,
+		"type":           string(transaction.Type),
+		"amount":         transaction.Amount,
+	}).Info("Transaction completed")
+	return nil
+}
+
+// failTransaction marks a compensated transaction failed together with its failure event.
+func (s *TransactionService) failTransaction(ctx context.Context, transaction *models.Transaction, reason string) *stepFailure {
+	if transaction.Status == models.TransactionStatusFailed {
+		return nil
+	}
+
+	eventType := "transaction.failed"
+	if isUPIDeposit(transaction) {
+		eventType = "transaction.upi_deposit.failed"
+	}
+	failedEvent := models.NewOutboxEvent(eventType, map[string]interface{}{
+		"type":           string(transaction.Type),
+		"status":         string(models.TransactionStatusFailed),
+		"failure_reason": reason,
+	})
+
+	if err := s.transactionRepo.UpdateStatus(ctx, transaction.ID, models.TransactionStatusFailed, &reason, failedEvent); err != nil {
+		return newStepFailure(err)
+	}
+	transaction.Status = models.TransactionStatusFailed
+
+	s.logger.With(map[string]interface{}{
+		"transaction_id": transaction.ID,
+		"reason":         reason,
+	}).Warn("Transaction failed")
+	return nil
+}
+
+func isUPIDeposit(transaction *models.Transaction) bool {
+	return transaction.Type == models.TransactionTypeDeposit &&
+		transaction.Metadata != nil && transaction.Metadata["payment_method"] == "upi"
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// =====================================================================
+// Mocks for Saga Tests
+// =====================================================================
+
+type mockSagaRepository struct {
+	sagas map[string]*models.TransactionSaga
+	due   []*models.TransactionSaga
+}
+
+func newMockSagaRepository() *mockSagaRepository {
+	return &mockSagaRepository{
+		sagas: make(map[string]*models.TransactionSaga),
+	}
+}
+
+func (m *mockSagaRepository) Start(ctx context.Context, saga *models.TransactionSaga) (*models.TransactionSaga, *errors.Error) {
+	if existing, ok := m.sagas[saga.TransactionID]; ok {
+		return existing, nil
+	}
+	m.sagas[saga.TransactionID] = saga
+	return saga, nil
+}
+
+func (m *mockSagaRepository) Get(ctx context.Context, transactionID string) (*models.TransactionSaga, *errors.Error) {
+	saga, ok := m.sagas[transactionID]
+	if !ok {
+		return nil, errors.NotFoundWithID("saga", transactionID)
+	}
+	return saga, nil
+}
+
+func (m *mockSagaRepository) Update(ctx context.Context, saga *models.TransactionSaga) *errors.Error {
+	m.sagas[saga.TransactionID] = saga
+	return nil
+}
+
+func (m *mockSagaRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.TransactionSaga, *errors.Error) {
+	n := min(limit, len(m.due))
+	claimed := m.due[:n]
+	m.due = m.due[n:]
+	return claimed, nil
+}
+
+type mockRiskEvaluator struct {
+	result *RiskEvaluationResult
+	err    *errors.Error
+}
+
+func (m *mockRiskEvaluator) EvaluateTransaction(ctx context.Context, req *RiskEvaluationRequest) (*RiskEvaluationResult, *errors.Error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.result, nil
+}
+
+// mockWalletOperations records the wallet operations called, in order,
+// and fails the ones configured in failOn.
+type mockWalletOperations struct {
+	calls  []string
+	failOn map[string]*errors.Error
+}
+
+func newMockWalletOperations() *mockWalletOperations {
+	return &mockWalletOperations{failOn: make(map[string]*errors.Error)}
+}
+
+func (m *mockWalletOperations) call(op string) *errors.Error {
+	m.calls = append(m.calls, op)
+	return m.failOn[op]
+}
+
+func (m *mockWalletOperations) GetWalletInfo(ctx context.Context, walletID string) (*WalletInfo, *errors.Error) {
+	return &WalletInfo{ID: walletID, UserID: "user-" + walletID, Status: "active", LedgerAccountID: "ledger-" + walletID}, nil
+}
+
+func (m *mockWalletOperations) HoldFunds(ctx context.Context, req *HoldRequest) *errors.Error {
+	return m.call("hold")
+}
+
+func (m *mockWalletOperations) ReleaseHold(ctx context.Context, transactionID string) *errors.Error {
+	return m.call("release")
+}
+
+func (m *mockWalletOperations) ExecuteTransfer(ctx context.Context, req *TransferRequest) *errors.Error {
+	return m.call("transfer")
+}
+
+func (m *mockWalletOperations) CreditDeposit(ctx context.Context, req *DepositRequest) *errors.Error {
+	return m.call("deposit")
+}
+
+func (m *mockWalletOperations) DebitWithdrawal(ctx context.Context, req *WithdrawalRequest) *errors.Error {
+	return m.call("withdraw")
+}
+
+func (m *mockWalletOperations) ReverseMovement(ctx context.Context, transactionID string) *errors.Error {
+	return m.call("reverse")
+}
+
+// =====================================================================
+// Test Helpers
+// =====================================================================
+
+// Compile-time interface checks
+var _ SagaRepositoryInterface = (*mockSagaRepository)(nil)
+var _ WalletOperations = (*mockWalletOperations)(nil)
+
+type sagaFixture struct {
+	service *TransactionService
+	repo    *mockTransactionRepository
+	sagas   *mockSagaRepository
+	wallets *mockWalletOperations
+	ledger  *mockJournalRecorder
+	risk    *mockRiskEvaluator
+	now     time.Time
+}
+
+func newSagaFixture() *sagaFixture {
+	f := &sagaFixture{
+		repo:    &mockTransactionRepository{transactions: make(map[string]*models.Transaction)},
+		sagas:   newMockSagaRepository(),
+		wallets: newMockWalletOperations(),
+		ledger:  &mockJournalRecorder{},
+		risk:    &mockRiskEvaluator{result: &RiskEvaluationResult{Allowed: true, Action: "allow"}},
+		now:     time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	poster := NewLedgerPoster(f.repo, f.wallets, f.ledger, DefaultSettlementAccountCode)
+	f.service = NewTransactionService(f.repo, f.sagas, f.risk, f.wallets, poster)
+	f.service.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *sagaFixture) createTransfer(t *testing.T) (*models.Transaction, *errors.Error) {
+	t.Helper()
+	return f.service.CreateTransfer(context.Background(), &models.CreateTransferRequest{
+		SourceWalletID:      uuid.New().String(),
+		DestinationWalletID: uuid.New().String(),
+		Amount:              50000,
+		Currency:            sharedModels.INR,
+		Description:         "Dinner split",
+	})
+}
+
+// onlyTransaction returns the single transaction in the repository.
+func (f *sagaFixture) onlyTransaction(t *testing.T) *models.Transaction {
+	t.Helper()
+	if len(f.repo.transactions) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(f.repo.transactions))
+	}
+	for _, tx := range f.repo.transactions {
+		return tx
+	}
+	return nil
+}
+
+func (f *sagaFixture) outboxEventTypes() []string {
+	var types []string
+	for _, msg := range f.repo.outbox {
+		if msg.EventType != nil {
+			types = append(types, *msg.EventType)
+		}
+	}
+	return types
+}
+
+// =====================================================================
+// Saga Tests
+// =====================================================================
+
+func TestCreateTransfer_SagaRunsAllSteps(t *testing.T) {
+	f := newSagaFixture()
+
+	tx, err := f.createTransfer(t)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if tx.Status != models.TransactionStatusCompleted {
+		t.Errorf("expected completed status, got %s", tx.Status)
+	}
+	if want := []string{"hold", "transfer"}; !slices.Equal(f.wallets.calls, want) {
+		t.Errorf("expected wallet calls %v, got %v", want, f.wallets.calls)
+	}
+	if len(f.ledger.requests) != 1 {
+		t.Errorf("expected 1 journal entry, got %d", len(f.ledger.requests))
+	}
+	if tx.LedgerEntryID == nil {
+		t.Error("expected ledger entry ID to be recorded on the transaction")
+	}
+
+	saga := f.sagas.sagas[tx.ID]
+	if saga.State != models.SagaStateCompleted {
+		t.Errorf("expected completed saga, got %s", saga.State)
+	}
+	if want := sagaPlans[models.TransactionTypeTransfer]; !slices.Equal(saga.CompletedSteps, want) {
+		t.Errorf("expected completed steps %v, got %v", want, saga.CompletedSteps)
+	}
+
+	if !slices.Contains(f.outboxEventTypes(), "transaction.completed") {
+		t.Errorf("expected transaction.completed event, got %v", f.outboxEventTypes())
+	}
+}
+
+func TestCreateTransfer_RiskBlockedFailsWithoutMovingFunds(t *testing.T) {
+	f := newSagaFixture()
+	f.risk.result = &RiskEvaluationResult{Allowed: false, Action: "block", Reason: "velocity limit"}
+
+	tx, err := f.createTransfer(t)
+	if err == nil {
+		t.Fatal("expected error for blocked transfer")
+	}
+	if err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected bad request error, got %s", err.Code)
+	}
+	if tx == nil || tx.Status != models.TransactionStatusFailed {
+		t.Fatalf("expected failed transaction to be returned, got %v", tx)
+	}
+	if tx.FailureReason == nil || *tx.FailureReason != "blocked by risk: velocity limit" {
+		t.Errorf("expected risk failure reason, got %v", tx.FailureReason)
+	}
+	if len(f.wallets.calls) != 0 {
+		t.Errorf("expected no wallet calls, got %v", f.wallets.calls)
+	}
+	if saga := f.sagas.sagas[tx.ID]; saga.State != models.SagaStateCompensated {
+		t.Errorf("expected compensated saga, got %s", saga.State)
+	}
+}
+
+func TestCreateTransfer_RiskUnavailableFailsClosed(t *testing.T) {
+	f := newSagaFixture()
+	f.risk.err = errors.Internal("connection refused")
+
+	tx, err := f.createTransfer(t)
+	if err == nil {
+		t.Fatal("expected error when risk service is unavailable")
+	}
+	if err.Code != errors.ErrCodeInternal {
+		t.Errorf("expected internal error, got %s", err.Code)
+	}
+	if tx != nil {
+		t.Errorf("expected no transaction, got %v", tx)
+	}
+
+	stored := f.onlyTransaction(t)
+	if stored.Status != models.TransactionStatusFailed {
+		t.Errorf("expected failed status, got %s", stored.Status)
+	}
+	if len(f.wallets.calls) != 0 {
+		t.Errorf("expected no wallet calls, got %v", f.wallets.calls)
+	}
+}
+
+func TestCreateTransfer_RejectedMoveCompensatesInReverseOrder(t *testing.T) {
+	f := newSagaFixture()
+	f.wallets.failOn["transfer"] = errors.BadRequest("insufficient balance")
+
+	tx, err := f.createTransfer(t)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if tx.Status != models.TransactionStatusFailed {
+		t.Errorf("expected failed status, got %s", tx.Status)
+	}
+	if tx.FailureReason == nil || *tx.FailureReason != "insufficient balance" {
+		t.Errorf("expected wallet failure reason, got %v", tx.FailureReason)
+	}
+
+	// The failed move is undone too in case it took effect, then the hold is released
+	if want := []string{"hold", "transfer", "reverse", "release"}; !slices.Equal(f.wallets.calls, want) {
+		t.Errorf("expected wallet calls %v, got %v", want, f.wallets.calls)
+	}
+	if len(f.ledger.requests) != 0 {
+		t.Errorf("expected no journal entry, got %d", len(f.ledger.requests))
+	}
+
+	saga := f.sagas.sagas[tx.ID]
+	if saga.State != models.SagaStateCompensated {
+		t.Errorf("expected compensated saga, got %s", saga.State)
+	}
+	if len(saga.CompletedSteps) != 0 {
+		t.Errorf("expected all steps compensated, got %v", saga.CompletedSteps)
+	}
+	if !slices.Contains(f.outboxEventTypes(), "transaction.failed") {
+		t.Errorf("expected transaction.failed event, got %v", f.outboxEventTypes())
+	}
+}
+
+func TestCreateTransfer_TransientFailureSchedulesRetry(t *testing.T) {
+	f := newSagaFixture()
+	f.wallets.failOn["transfer"] = errors.Internal("wallet service unavailable")
+
+	tx, err := f.createTransfer(t)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if tx.Status != models.TransactionStatusPending {
+		t.Errorf("expected pending status, got %s", tx.Status)
+	}
+
+	saga := f.sagas.sagas[tx.ID]
+	if saga.State != models.SagaStateRunning || saga.CurrentStep != models.SagaStepWalletMove {
+		t.Errorf("expected running saga at wallet_move, got %s at %s", saga.State, saga.CurrentStep)
+	}
+	if saga.Attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", saga.Attempts)
+	}
+	if want := f.now.Add(sagaBaseBackoff); !saga.NextAttemptAt.Time.Equal(want) {
+		t.Errorf("expected next attempt at %v, got %v", want, saga.NextAttemptAt.Time)
+	}
+	if slices.Contains(f.wallets.calls, "release") {
+		t.Error("expected hold to be kept for the retry")
+	}
+}
+
+func TestRecoverSagas_ResumesPendingSaga(t *testing.T) {
+	f := newSagaFixture()
+	f.wallets.failOn["transfer"] = errors.Internal("wallet service unavailable")
+
+	tx, _ := f.createTransfer(t)
+	delete(f.wallets.failOn, "transfer")
+	f.sagas.due = []*models.TransactionSaga{f.sagas.sagas[tx.ID]}
+
+	resumed, err := f.service.RecoverSagas(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resumed != 1 {
+		t.Errorf("expected 1 saga resumed, got %d", resumed)
+	}
+
+	if tx.Status != models.TransactionStatusCompleted {
+		t.Errorf("expected completed status, got %s", tx.Status)
+	}
+	// The hold is not placed twice on resume
+	if want := []string{"hold", "transfer", "transfer"}; !slices.Equal(f.wallets.calls, want) {
+		t.Errorf("expected wallet calls %v, got %v", want, f.wallets.calls)
+	}
+}
+
+func TestRecoverSagas_CompensatesAfterMaxAttempts(t *testing.T) {
+	f := newSagaFixture()
+	f.wallets.failOn["transfer"] = errors.Internal("wallet service unavailable")
+
+	tx, _ := f.createTransfer(t)
+	saga := f.sagas.sagas[tx.ID]
+	saga.Attempts = sagaMaxAttempts - 1
+	f.sagas.due = []*models.TransactionSaga{saga}
+
+	if _, err := f.service.RecoverSagas(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if tx.Status != models.TransactionStatusFailed {
+		t.Errorf("expected failed status, got %s", tx.Status)
+	}
+	if saga.State != models.SagaStateCompensated {
+		t.Errorf("expected compensated saga, got %s", saga.State)
+	}
+	if !slices.Contains(f.wallets.calls, "release") {
+		t.Error("expected hold to be released")
+	}
+}
+
+func TestRunSaga_FailedCompensationNeedsManualReview(t *testing.T) {
+	f := newSagaFixture()
+	f.wallets.failOn["transfer"] = errors.BadRequest("destination wallet is not active")
+	f.wallets.failOn["reverse"] = errors.BadRequest("insufficient available balance to reverse the movement")
+
+	tx, _ := f.createTransfer(t)
+
+	saga := f.sagas.sagas[tx.ID]
+	if saga.State != models.SagaStateManualReview {
+		t.Errorf("expected manual review, got %s", saga.State)
+	}
+	if tx.Status == models.TransactionStatusFailed {
+		t.Error("expected transaction not to be failed while compensation is incomplete")
+	}
+	if slices.Contains(f.wallets.calls, "release") {
+		t.Error("expected hold not to be released before the move is reversed")
+	}
+}
+
+func TestCreateWithdrawal_SagaDebitsWalletAgainstSettlement(t *testing.T) {
+	f := newSagaFixture()
+	walletID := uuid.New().String()
+
+	tx, err := f.service.CreateWithdrawal(context.Background(), &models.CreateWithdrawalRequest{
+		WalletID:    walletID,
+		Amount:      75000,
+		Currency:    sharedModels.INR,
+		Description: "Bank payout",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if tx.Status != models.TransactionStatusCompleted {
+		t.Errorf("expected completed status, got %s", tx.Status)
+	}
+	if want := []string{"hold", "withdraw"}; !slices.Equal(f.wallets.calls, want) {
+		t.Errorf("expected wallet calls %v, got %v", want, f.wallets.calls)
+	}
+
+	if len(f.ledger.requests) != 1 {
+		t.Fatalf("expected 1 journal entry, got %d", len(f.ledger.requests))
+	}
+	for _, line := range f.ledger.requests[0].Lines {
+		switch line.AccountID {
+		case "ledger-" + walletID:
+			if line.CreditAmount != 75000 {
+				t.Errorf("expected wallet credited 75000, got credit %d", line.CreditAmount)
+			}
+		case "ledger-settlement-" + DefaultSettlementAccountCode:
+			if line.DebitAmount != 75000 {
+				t.Errorf("expected settlement debited 75000, got debit %d", line.DebitAmount)
+			}
+		default:
+			t.Errorf("unexpected ledger account %s", line.AccountID)
+		}
+	}
+}
+
+func TestProcessTransfer_StartsSagaForTransferWithoutOne(t *testing.T) {
+	f := newSagaFixture()
+	sourceWalletID := uuid.New().String()
+	destWalletID := uuid.New().String()
+	transfer := &models.Transaction{
+		ID:                  uuid.New().String(),
+		Type:                models.TransactionTypeTransfer,
+		Status:              models.TransactionStatusPending,
+		SourceWalletID:      &sourceWalletID,
+		DestinationWalletID: &destWalletID,
+		Amount:              1000,
+		Currency:            sharedModels.INR,
+	}
+	f.repo.transactions[transfer.ID] = transfer
+
+	if err := f.service.ProcessTransfer(context.Background(), transfer.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if transfer.Status != models.TransactionStatusCompleted {
+		t.Errorf("expected completed status, got %s", transfer.Status)
+	}
+	if saga, ok := f.sagas.sagas[transfer.ID]; !ok || saga.State != models.SagaStateCompleted {
+		t.Errorf("expected completed saga, got %v", saga)
+	}
+}
+
+func TestSagaBackoff_CapsAtMaximum(t *testing.T) {
+	if got := sagaBackoff(1); got != sagaBaseBackoff {
+		t.Errorf("expected %v after the first attempt, got %v", sagaBaseBackoff, got)
+	}
+	if got := sagaBackoff(3); got != 4*sagaBaseBackoff {
+		t.Errorf("expected %v after the third attempt, got %v", 4*sagaBaseBackoff, got)
+	}
+	if got := sagaBackoff(sagaMaxAttempts + 10); got != sagaMaxBackoff {
+		t.Errorf("expected backoff capped at %v, got %v", sagaMaxBackoff, got)
+	}
+}
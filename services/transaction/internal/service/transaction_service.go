@@ -2,49 +2,178 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/vnykmshr/nivo/services/transaction/internal/models"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/audit"
+	"github.com/vnykmshr/nivo/shared/clients"
 	"github.com/vnykmshr/nivo/shared/errors"
-	"github.com/vnykmshr/nivo/shared/events"
 	"github.com/vnykmshr/nivo/shared/logger"
 )
 
 // TransactionRepositoryInterface defines the interface for transaction repository operations.
 type TransactionRepositoryInterface interface {
-	Create(ctx context.Context, transaction *models.Transaction) *errors.Error
+	Create(ctx context.Context, transaction *models.Transaction, outbox ...*models.OutboxMessage) *errors.Error
+	CreateWithSaga(ctx context.Context, transaction *models.Transaction, saga *models.TransactionSaga, outbox ...*models.OutboxMessage) *errors.Error
 	GetByID(ctx context.Context, id string) (*models.Transaction, *errors.Error)
 	ListByWallet(ctx context.Context, walletID string, filter *models.TransactionFilter) ([]*models.Transaction, *errors.Error)
 	SearchAll(ctx context.Context, filter *models.TransactionFilter) ([]*models.Transaction, *errors.Error)
 	UpdateMetadata(ctx context.Context, id string, metadata map[string]string) *errors.Error
-	CompleteWithMetadata(ctx context.Context, id string, metadata map[string]string) *errors.Error
-	UpdateStatus(ctx context.Context, id string, status models.TransactionStatus, failureReason *string) *errors.Error
+	UpdateStatus(ctx context.Context, id string, status models.TransactionStatus, failureReason *string, outbox ...*models.OutboxMessage) *errors.Error
+	MarkProcessed(ctx context.Context, id string) *errors.Error
+	UpdateLedgerEntry(ctx context.Context, id, ledgerEntryID string) *errors.Error
 	UpdateCategory(ctx context.Context, id string, category models.SpendingCategory) *errors.Error
 	GetCategoryPatterns(ctx context.Context) ([]*models.CategoryPattern, *errors.Error)
 	GetCategorySummary(ctx context.Context, walletID string, startDate, endDate string) ([]models.CategorySummary, *errors.Error)
+	SumByWallets(ctx context.Context, walletIDs []string) ([]models.WalletTotals, *errors.Error)
+}
+
+// RiskEvaluator evaluates transactions for risk.
+type RiskEvaluator interface {
+	EvaluateTransaction(ctx context.Context, req *RiskEvaluationRequest) (*RiskEvaluationResult, *errors.Error)
+}
+
+// WalletOperations moves and holds funds in the wallet service. Every
+// operation is idempotent per transaction ID.
+type WalletOperations interface {
+	GetWalletInfo(ctx context.Context, walletID string) (*WalletInfo, *errors.Error)
+	HoldFunds(ctx context.Context, req *HoldRequest) *errors.Error
+	ReleaseHold(ctx context.Context, transactionID string) *errors.Error
+	ExecuteTransfer(ctx context.Context, req *TransferRequest) *errors.Error
+	CreditDeposit(ctx context.Context, req *DepositRequest) *errors.Error
+	DebitWithdrawal(ctx context.Context, req *WithdrawalRequest) *errors.Error
+	ReverseMovement(ctx context.Context, transactionID string) *errors.Error
+}
+
+// StepUpVerifier redeems step-up verification tokens issued by the identity
+// service, and releases them when the operation is rejected.
+type StepUpVerifier interface {
+	Redeem(ctx context.Context, userID, operation, token string, metadata map[string]any) *errors.Error
+	Release(ctx context.Context, userID, operation, token string, metadata map[string]any) *errors.Error
 }
 
 // TransactionService handles business logic for transaction operations.
+// Transfers, deposits and withdrawals are processed as sagas (see transaction_saga.go).
+// Events are not delivered here: they are written to the outbox together with
+// the status change that causes them and delivered by the OutboxRelay.
 type TransactionService struct {
 	transactionRepo TransactionRepositoryInterface
-	riskClient      *RiskClient
-	walletClient    *WalletClient
-	ledgerClient    *LedgerClient
-	eventPublisher  *events.Publisher
+	sagaRepo        SagaRepositoryInterface
+	riskClient      RiskEvaluator
+	walletClient    WalletOperations
+	ledgerPoster    *LedgerPoster
+	verifier        StepUpVerifier
+	auditLog        audit.Recorder
+	approvals       *approval.Workflow
 	logger          *logger.Logger
+	now             func() time.Time
 }
 
 // NewTransactionService creates a new transaction service.
-func NewTransactionService(transactionRepo TransactionRepositoryInterface, riskClient *RiskClient, walletClient *WalletClient, ledgerClient *LedgerClient, eventPublisher *events.Publisher) *TransactionService {
+func NewTransactionService(
+	transactionRepo TransactionRepositoryInterface,
+	sagaRepo SagaRepositoryInterface,
+	riskClient RiskEvaluator,
+	walletClient WalletOperations,
+	ledgerPoster *LedgerPoster,
+) *TransactionService {
 	return &TransactionService{
 		transactionRepo: transactionRepo,
+		sagaRepo:        sagaRepo,
 		riskClient:      riskClient,
 		walletClient:    walletClient,
-		ledgerClient:    ledgerClient,
-		eventPublisher:  eventPublisher,
+		ledgerPoster:    ledgerPoster,
 		logger:          logger.NewDefault("transaction"),
+		now:             time.Now,
+	}
+}
+
+// SetStepUpVerifier sets the verifier for high-value transfers made by users.
+func (s *TransactionService) SetStepUpVerifier(verifier StepUpVerifier) {
+	s.verifier = verifier
+}
+
+// SetAuditLog sets where reversals are recorded.
+func (s *TransactionService) SetAuditLog(auditLog audit.Recorder) {
+	s.auditLog = auditLog
+}
+
+// OperationReverse is the approval operation for transaction reversals.
+const OperationReverse = "transaction.reverse"
+
+// SetApprovals sets the workflow through which reversals are requested and
+// registers the reversal operation with it.
+func (s *TransactionService) SetApprovals(workflow *approval.Workflow) {
+	s.approvals = workflow
+	workflow.Register(approval.Operation{
+		Name:         OperationReverse,
+		ResourceType: "transaction",
+		Permission:   "transaction:transaction:reverse",
+		Execute: func(ctx context.Context, req *approval.Request) (any, *errors.Error) {
+			var payload models.ReverseTransactionRequest
+			if err := json.Unmarshal(req.Payload, &payload); err != nil {
+				return nil, errors.InternalWrap(err, "invalid reversal payload")
+			}
+			return s.ReverseTransaction(ctx, req.ResourceID, payload.Reason)
+		},
+	})
+}
+
+// CreateUserTransfer creates a transfer requested by a user. Transfers above
+// clients.HighValueTransferThreshold need a verification token bound to the
+// amount and destination wallet, which is used up by the transfer. If the
+// transfer is rejected (e.g. insufficient funds or a risk block) the token is
+// released, so the user can retry without verifying again.
+// Scheduled transfers and payment requests redeem their token when the user
+// creates the schedule or accepts the request, then use CreateTransfer.
+func (s *TransactionService) CreateUserTransfer(ctx context.Context, userID string, req *models.CreateTransferRequest) (*models.Transaction, *errors.Error) {
+	if err := redeemHighValueTransfer(ctx, s.verifier, userID, req.Amount, req.DestinationWalletID, req.VerificationToken); err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.CreateTransfer(ctx, req)
+	if transaction == nil || transaction.IsFailed() {
+		releaseHighValueTransfer(ctx, s.verifier, s.logger, userID, req.Amount, req.DestinationWalletID, req.VerificationToken)
+	}
+	return transaction, err
+}
+
+// redeemHighValueTransfer redeems the verification token a user needs to move
+// more than clients.HighValueTransferThreshold to a wallet. Smaller amounts need none.
+func redeemHighValueTransfer(ctx context.Context, verifier StepUpVerifier, userID string, amount int64, destinationWalletID, token string) *errors.Error {
+	if amount <= clients.HighValueTransferThreshold {
+		return nil
+	}
+	if verifier == nil {
+		return errors.Internal("step-up verification is not configured")
+	}
+
+	metadata := map[string]any{
+		"amount":                amount,
+		"destination_wallet_id": destinationWalletID,
+	}
+	return verifier.Redeem(ctx, userID, clients.VerificationOpHighValueTransfer, token, metadata)
+}
+
+// releaseHighValueTransfer releases a token redeemed with redeemHighValueTransfer
+// for a transfer that was not made. A failure is only logged: the user can
+// still verify the transfer again.
+func releaseHighValueTransfer(ctx context.Context, verifier StepUpVerifier, log *logger.Logger, userID string, amount int64, destinationWalletID, token string) {
+	if amount <= clients.HighValueTransferThreshold || verifier == nil {
+		return
+	}
+
+	metadata := map[string]any{
+		"amount":                amount,
+		"destination_wallet_id": destinationWalletID,
+	}
+	if err := verifier.Release(ctx, userID, clients.VerificationOpHighValueTransfer, token, metadata); err != nil {
+		log.WithField("user_id", userID).WithError(err).Error("Failed to release verification token")
 	}
 }
 
@@ -81,59 +210,25 @@ func (s *TransactionService) CreateTransfer(ctx context.Context, req *models.Cre
 		Metadata:            metadata,
 	}
 
-	if createErr := s.transactionRepo.Create(ctx, transaction); createErr != nil {
+	// Record the transaction.created event with the transaction
+	createdEvent := models.NewOutboxEvent("transaction.created", map[string]interface{}{
+		"type":                  string(transaction.Type),
+		"status":                string(transaction.Status),
+		"amount":                transaction.Amount,
+		"currency":              transaction.Currency,
+		"source_wallet_id":      transaction.SourceWalletID,
+		"destination_wallet_id": transaction.DestinationWalletID,
+		"description":           transaction.Description,
+	})
+
+	// Create the transaction with its saga, then run the saga synchronously:
+	// risk evaluation, fund hold, wallet transfer, ledger posting, completion
+	saga := s.newSaga("")
+	if createErr := s.transactionRepo.CreateWithSaga(ctx, transaction, saga, createdEvent); createErr != nil {
 		return nil, createErr
 	}
 
-	// Publish transaction.created event
-	if s.eventPublisher != nil {
-		s.eventPublisher.PublishTransactionEvent("transaction.created", transaction.ID, map[string]interface{}{
-			"type":                  string(transaction.Type),
-			"status":                string(transaction.Status),
-			"amount":                transaction.Amount,
-			"currency":              transaction.Currency,
-			"source_wallet_id":      transaction.SourceWalletID,
-			"destination_wallet_id": transaction.DestinationWalletID,
-			"description":           transaction.Description,
-		})
-	}
-
-	// Evaluate risk for the transaction (fail-closed: block if risk service unavailable)
-	riskBlocked, riskErr := s.evaluateTransactionRisk(ctx, transaction)
-	if riskErr != nil {
-		s.logger.WithError(riskErr).WithField("transaction_id", transaction.ID).Error("Risk evaluation failed - blocking transaction")
-		failureReason := "risk evaluation unavailable"
-		_ = s.transactionRepo.UpdateStatus(ctx, transaction.ID, models.TransactionStatusFailed, &failureReason)
-		return nil, errors.Internal("transaction blocked: risk service unavailable")
-	}
-
-	// If risk blocked the transaction, fail it
-	if riskBlocked {
-		s.logger.WithField("transaction_id", transaction.ID).Warn("Transaction blocked by risk evaluation")
-		// Transaction already marked as failed in evaluateTransactionRisk
-		if updatedTx, getErr := s.transactionRepo.GetByID(ctx, transaction.ID); getErr == nil {
-			return updatedTx, errors.BadRequest("transaction blocked by risk evaluation")
-		}
-		return nil, errors.BadRequest("transaction blocked by risk evaluation")
-	}
-
-	// Process the transfer synchronously
-	// This executes the wallet transfer and marks the transaction as completed
-	if processErr := s.ProcessTransfer(ctx, transaction.ID); processErr != nil {
-		s.logger.WithError(processErr).WithField("transaction_id", transaction.ID).Error("Failed to process transfer")
-		// Return the transaction even if processing failed - caller can check status
-		// Refetch to get updated status
-		if updatedTx, getErr := s.transactionRepo.GetByID(ctx, transaction.ID); getErr == nil {
-			transaction = updatedTx
-		}
-	} else {
-		// Refetch to get completed status
-		if updatedTx, getErr := s.transactionRepo.GetByID(ctx, transaction.ID); getErr == nil {
-			transaction = updatedTx
-		}
-	}
-
-	return transaction, nil
+	return s.executeSaga(ctx, saga)
 }
 
 // CreateDeposit creates a deposit transaction to a wallet.
@@ -161,27 +256,22 @@ func (s *TransactionService) CreateDeposit(ctx context.Context, req *models.Crea
 		Metadata:            metadata,
 	}
 
-	if createErr := s.transactionRepo.Create(ctx, transaction); createErr != nil {
-		return nil, createErr
-	}
+	// Record the transaction.created event with the transaction
+	createdEvent := models.NewOutboxEvent("transaction.created", map[string]interface{}{
+		"type":                  string(transaction.Type),
+		"status":                string(transaction.Status),
+		"amount":                transaction.Amount,
+		"currency":              transaction.Currency,
+		"destination_wallet_id": transaction.DestinationWalletID,
+		"description":           transaction.Description,
+	})
 
-	// Publish transaction.created event
-	if s.eventPublisher != nil {
-		s.eventPublisher.PublishTransactionEvent("transaction.created", transaction.ID, map[string]interface{}{
-			"type":                  string(transaction.Type),
-			"status":                string(transaction.Status),
-			"amount":                transaction.Amount,
-			"currency":              transaction.Currency,
-			"destination_wallet_id": transaction.DestinationWalletID,
-			"description":           transaction.Description,
-		})
+	if createErr := s.transactionRepo.Create(ctx, transaction, createdEvent); createErr != nil {
+		return nil, createErr
 	}
 
-	// TODO: Trigger async processing for deposit
-	// 1. Verify external payment received
-	// 2. Create ledger entry
-	// 3. Update wallet balance
-	// 4. Mark transaction as completed
+	// TODO: Verify the external payment, then start the deposit saga
+	// (as CompleteUPIDeposit does) to credit the wallet and post to the ledger
 
 	return transaction, nil
 }
@@ -216,20 +306,18 @@ func (s *TransactionService) InitiateUPIDeposit(ctx context.Context, req *models
 		},
 	}
 
-	if createErr := s.transactionRepo.Create(ctx, transaction); createErr != nil {
-		return nil, createErr
-	}
+	// Record the initiation event with the transaction
+	initiatedEvent := models.NewOutboxEvent("transaction.upi_deposit.initiated", map[string]interface{}{
+		"type":                  string(transaction.Type),
+		"status":                string(transaction.Status),
+		"amount":                transaction.Amount,
+		"currency":              transaction.Currency,
+		"destination_wallet_id": transaction.DestinationWalletID,
+		"virtual_upi_id":        virtualUPIID,
+	})
 
-	// Publish event
-	if s.eventPublisher != nil {
-		s.eventPublisher.PublishTransactionEvent("transaction.upi_deposit.initiated", transaction.ID, map[string]interface{}{
-			"type":                  string(transaction.Type),
-			"status":                string(transaction.Status),
-			"amount":                transaction.Amount,
-			"currency":              transaction.Currency,
-			"destination_wallet_id": transaction.DestinationWalletID,
-			"virtual_upi_id":        virtualUPIID,
-		})
+	if createErr := s.transactionRepo.Create(ctx, transaction, initiatedEvent); createErr != nil {
+		return nil, createErr
 	}
 
 	// Calculate expiry (30 minutes from now)
@@ -272,7 +360,7 @@ func (s *TransactionService) CompleteUPIDeposit(ctx context.Context, req *models
 		return transaction, nil
 	}
 
-	if transaction.Status != models.TransactionStatusPending {
+	if !transaction.IsPending() {
 		return nil, errors.BadRequest("transaction is not in pending status")
 	}
 
@@ -283,66 +371,49 @@ func (s *TransactionService) CompleteUPIDeposit(ctx context.Context, req *models
 
 	// Update transaction based on status
 	if req.Status == "success" {
-		// Update metadata with external UPI transaction ID
+		// Record the external UPI transaction ID for the completion event
 		updatedMetadata := make(map[string]string)
 		for k, v := range transaction.Metadata {
 			updatedMetadata[k] = v
 		}
 		updatedMetadata["external_upi_transaction_id"] = req.UPITransactionID
 
-		// Complete transaction atomically with metadata update (provides idempotency)
-		if updateErr := s.transactionRepo.CompleteWithMetadata(ctx, transaction.ID, updatedMetadata); updateErr != nil {
+		if updateErr := s.transactionRepo.UpdateMetadata(ctx, transaction.ID, updatedMetadata); updateErr != nil {
 			return nil, updateErr
 		}
 
+		// Credit the wallet and post to the ledger through the deposit saga.
+		// Starting is idempotent, so a repeated webhook resumes the same saga.
+		saga, startErr := s.sagaRepo.Start(ctx, s.newSaga(transaction.ID))
+		if startErr != nil {
+			return nil, startErr
+		}
+
+		if saga.IsActive() {
+			if runErr := s.runSaga(ctx, saga); runErr != nil {
+				s.logger.WithError(runErr).WithField("transaction_id", transaction.ID).
+					Warn("UPI deposit saga did not complete")
+			}
+		}
+
 		// Refetch to get updated transaction
 		transaction, err = s.transactionRepo.GetByID(ctx, req.TransactionID)
 		if err != nil {
 			return nil, err
 		}
-
-		// Publish completion event
-		if s.eventPublisher != nil {
-			s.eventPublisher.PublishTransactionEvent("transaction.upi_deposit.completed", transaction.ID, map[string]interface{}{
-				"type":                  string(transaction.Type),
-				"status":                string(transaction.Status),
-				"amount":                transaction.Amount,
-				"currency":              transaction.Currency,
-				"destination_wallet_id": transaction.DestinationWalletID,
-				"upi_transaction_id":    req.UPITransactionID,
-			})
+	} else {
+		if transaction.Status != models.TransactionStatusPending {
+			return nil, errors.BadRequest("transaction is not in pending status")
 		}
 
-		s.logger.With(map[string]interface{}{
-			"transaction_id": transaction.ID,
-			"amount":         transaction.Amount,
-		}).Info("UPI deposit completed")
-
-		// Credit the deposit to the wallet
-		// NOTE: In a fully event-driven architecture, this would be handled by the Wallet service
-		// listening to the "transaction.upi_deposit.completed" event. For now, we call it directly.
-		if s.walletClient != nil && transaction.DestinationWalletID != nil {
-			depositReq := &DepositRequest{
-				WalletID:      *transaction.DestinationWalletID,
-				Amount:        transaction.Amount,
-				TransactionID: transaction.ID,
-				Description:   transaction.Description,
-			}
-			if creditErr := s.walletClient.CreditDeposit(ctx, depositReq); creditErr != nil {
-				s.logger.WithError(creditErr).Warn("Failed to credit deposit to wallet")
-				// Don't fail the transaction - it's already marked as completed
-				// Manual intervention may be needed to reconcile the wallet balance
-			} else {
-				s.logger.With(map[string]interface{}{
-					"wallet_id": *transaction.DestinationWalletID,
-					"amount":    transaction.Amount,
-				}).Info("Wallet credited")
-			}
-		}
-	} else {
 		// Mark as failed
 		failureReason := "UPI payment failed"
-		if updateErr := s.transactionRepo.UpdateStatus(ctx, transaction.ID, models.TransactionStatusFailed, &failureReason); updateErr != nil {
+		failedEvent := models.NewOutboxEvent("transaction.upi_deposit.failed", map[string]interface{}{
+			"type":           string(transaction.Type),
+			"status":         string(models.TransactionStatusFailed),
+			"failure_reason": failureReason,
+		})
+		if updateErr := s.transactionRepo.UpdateStatus(ctx, transaction.ID, models.TransactionStatusFailed, &failureReason, failedEvent); updateErr != nil {
 			return nil, updateErr
 		}
 
@@ -352,15 +423,6 @@ func (s *TransactionService) CompleteUPIDeposit(ctx context.Context, req *models
 			return nil, err
 		}
 
-		// Publish failure event
-		if s.eventPublisher != nil {
-			s.eventPublisher.PublishTransactionEvent("transaction.upi_deposit.failed", transaction.ID, map[string]interface{}{
-				"type":           string(transaction.Type),
-				"status":         string(transaction.Status),
-				"failure_reason": failureReason,
-			})
-		}
-
 		s.logger.WithField("transaction_id", transaction.ID).Info("UPI deposit failed")
 	}
 
@@ -399,31 +461,25 @@ func (s *TransactionService) CreateWithdrawal(ctx context.Context, req *models.C
 		Metadata:       metadata,
 	}
 
-	if createErr := s.transactionRepo.Create(ctx, transaction); createErr != nil {
+	// Record the transaction.created event with the transaction
+	createdEvent := models.NewOutboxEvent("transaction.created", map[string]interface{}{
+		"type":             string(transaction.Type),
+		"status":           string(transaction.Status),
+		"amount":           transaction.Amount,
+		"currency":         transaction.Currency,
+		"source_wallet_id": transaction.SourceWalletID,
+		"description":      transaction.Description,
+	})
+
+	// Create the transaction with its saga, then run the saga synchronously:
+	// risk evaluation, fund hold, wallet debit, ledger posting, completion
+	// TODO: Initiate the external payout before completing
+	saga := s.newSaga("")
+	if createErr := s.transactionRepo.CreateWithSaga(ctx, transaction, saga, createdEvent); createErr != nil {
 		return nil, createErr
 	}
 
-	// Publish transaction.created event
-	if s.eventPublisher != nil {
-		s.eventPublisher.PublishTransactionEvent("transaction.created", transaction.ID, map[string]interface{}{
-			"type":             string(transaction.Type),
-			"status":           string(transaction.Status),
-			"amount":           transaction.Amount,
-			"currency":         transaction.Currency,
-			"source_wallet_id": transaction.SourceWalletID,
-			"description":      transaction.Description,
-		})
-	}
-
-	// TODO: Trigger async processing for withdrawal
-	// 1. Verify wallet has sufficient balance
-	// 2. Create hold on wallet
-	// 3. Initiate external payment
-	// 4. Create ledger entry
-	// 5. Update wallet balance
-	// 6. Mark transaction as completed
-
-	return transaction, nil
+	return s.executeSaga(ctx, saga)
 }
 
 // GetTransaction retrieves a transaction by ID.
@@ -452,6 +508,24 @@ func (s *TransactionService) SearchAllTransactions(ctx context.Context, filter *
 	return s.transactionRepo.SearchAll(ctx, filter)
 }
 
+// RequestReversal submits a reversal for approval by a second admin. The
+// reversal is made by ReverseTransaction once approved.
+func (s *TransactionService) RequestReversal(ctx context.Context, transactionID, reason string) (*approval.Request, *errors.Error) {
+	if s.approvals == nil {
+		return nil, errors.Internal("approval workflow is not configured")
+	}
+
+	originalTx, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if checkErr := checkReversible(originalTx); checkErr != nil {
+		return nil, checkErr
+	}
+
+	return s.approvals.Submit(ctx, OperationReverse, transactionID, models.ReverseTransactionRequest{Reason: reason})
+}
+
 // ReverseTransaction reverses a completed transaction.
 func (s *TransactionService) ReverseTransaction(ctx context.Context, transactionID, reason string) (*models.Transaction, *errors.Error) {
 	// Get original transaction
@@ -460,13 +534,8 @@ func (s *TransactionService) ReverseTransaction(ctx context.Context, transaction
 		return nil, err
 	}
 
-	// Validate transaction can be reversed
-	if !originalTx.IsCompleted() {
-		return nil, errors.BadRequest("only completed transactions can be reversed")
-	}
-
-	if originalTx.Type == models.TransactionTypeReversal {
-		return nil, errors.BadRequest("cannot reverse a reversal transaction")
+	if checkErr := checkReversible(originalTx); checkErr != nil {
+		return nil, checkErr
 	}
 
 	// Create reversal transaction
@@ -487,6 +556,24 @@ func (s *TransactionService) ReverseTransaction(ctx context.Context, transaction
 		return nil, createErr
 	}
 
+	if s.auditLog != nil {
+		s.auditLog.Record(ctx, audit.Event{
+			Action:       "transaction.reverse",
+			ResourceType: "transaction",
+			ResourceID:   transactionID,
+			Before: map[string]interface{}{
+				"status": originalTx.Status,
+				"amount": originalTx.Amount,
+			},
+			// The original keeps its status until the reversal completes
+			After: map[string]interface{}{
+				"reversal_transaction_id":     reversalTx.ID,
+				"reversal_transaction_status": reversalTx.Status,
+				"reversal_reason":             reason,
+			},
+		})
+	}
+
 	// TODO: Trigger async processing for reversal
 	// 1. Create reversal ledger entry
 	// 2. Update wallet balances
@@ -496,8 +583,78 @@ func (s *TransactionService) ReverseTransaction(ctx context.Context, transaction
 	return reversalTx, nil
 }
 
-// ProcessTransfer processes a pending transfer transaction by executing the wallet transfer
-// with limit checking and balance updates. This is typically called after risk evaluation.
+// checkReversible validates that a transaction can be reversed.
+func checkReversible(tx *models.Transaction) *errors.Error {
+	if !tx.IsCompleted() {
+		return errors.BadRequest("only completed transactions can be reversed")
+	}
+	if tx.Type == models.TransactionTypeReversal {
+		return errors.BadRequest("cannot reverse a reversal transaction")
+	}
+	return nil
+}
+
+// CreateRefund credits a wallet with the amount of the parent transaction from
+// the settlement account and runs the refund's saga.
+func (s *TransactionService) CreateRefund(ctx context.Context, parent *models.Transaction, walletID, reference, description string) (*models.Transaction, *errors.Error) {
+	return s.createSettlement(ctx, &models.Transaction{
+		Type:                models.TransactionTypeRefund,
+		DestinationWalletID: &walletID,
+		Description:         description,
+	}, parent, reference)
+}
+
+// CreateRecovery debits the amount of the parent transaction from a wallet
+// into the settlement account, as a reversal, and runs the reversal's saga.
+func (s *TransactionService) CreateRecovery(ctx context.Context, parent *models.Transaction, walletID, reference, description string) (*models.Transaction, *errors.Error) {
+	return s.createSettlement(ctx, &models.Transaction{
+		Type:           models.TransactionTypeReversal,
+		SourceWalletID: &walletID,
+		Description:    description,
+	}, parent, reference)
+}
+
+// createSettlement records a refund or reversal of the parent transaction with
+// its saga and runs the saga. A failure is reflected in the returned
+// transaction's status.
+func (s *TransactionService) createSettlement(ctx context.Context, transaction, parent *models.Transaction, reference string) (*models.Transaction, *errors.Error) {
+	parentID := parent.ID
+	transaction.Status = models.TransactionStatusPending
+	transaction.Amount = parent.Amount
+	transaction.Currency = parent.Currency
+	transaction.Reference = &reference
+	transaction.ParentTransactionID = &parentID
+
+	createdEvent := models.NewOutboxEvent("transaction.created", map[string]interface{}{
+		"type":                  string(transaction.Type),
+		"status":                string(transaction.Status),
+		"amount":                transaction.Amount,
+		"currency":              transaction.Currency,
+		"source_wallet_id":      transaction.SourceWalletID,
+		"destination_wallet_id": transaction.DestinationWalletID,
+		"parent_transaction_id": parentID,
+		"description":           transaction.Description,
+	})
+
+	saga := s.newSaga("")
+	if createErr := s.transactionRepo.CreateWithSaga(ctx, transaction, saga, createdEvent); createErr != nil {
+		return nil, createErr
+	}
+
+	return s.executeSaga(ctx, saga)
+}
+
+// MarkReversed marks a completed transaction reversed once its amount has been
+// returned, together with the transaction.reversed event.
+func (s *TransactionService) MarkReversed(ctx context.Context, transactionID string) *errors.Error {
+	reversedEvent := models.NewOutboxEvent("transaction.reversed", map[string]interface{}{
+		"status": string(models.TransactionStatusReversed),
+	})
+	return s.transactionRepo.UpdateStatus(ctx, transactionID, models.TransactionStatusReversed, nil, reversedEvent)
+}
+
+// ProcessTransfer resumes the saga of a pending transfer transaction. Transfers
+// created before sagas were introduced get a new saga.
 func (s *TransactionService) ProcessTransfer(ctx context.Context, transactionID string) *errors.Error {
 	// Get the transaction
 	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
@@ -506,7 +663,7 @@ func (s *TransactionService) ProcessTransfer(ctx context.Context, transactionID
 	}
 
 	// Validate transaction is pending
-	if transaction.Status != models.TransactionStatusPending {
+	if !transaction.IsPending() {
 		return errors.BadRequest(fmt.Sprintf("transaction is not pending (status: %s)", transaction.Status))
 	}
 
@@ -515,76 +672,30 @@ func (s *TransactionService) ProcessTransfer(ctx context.Context, transactionID
 		return errors.BadRequest(fmt.Sprintf("transaction is not a transfer (type: %s)", transaction.Type))
 	}
 
-	// Validate required fields
-	if transaction.SourceWalletID == nil || transaction.DestinationWalletID == nil {
-		return errors.BadRequest("transfer must have both source and destination wallets")
-	}
-
-	// Call wallet service to execute the transfer (includes limit checking and balance updates)
-	if s.walletClient == nil {
-		s.logger.Error("Wallet client not configured, cannot process transfer")
-		return errors.Internal("wallet client not configured")
-	}
-
-	transferReq := &TransferRequest{
-		SourceWalletID:      *transaction.SourceWalletID,
-		DestinationWalletID: *transaction.DestinationWalletID,
-		Amount:              transaction.Amount,
-		TransactionID:       transaction.ID,
-		Description:         transaction.Description,
-	}
-
-	transferErr := s.walletClient.ExecuteTransfer(ctx, transferReq)
-	if transferErr != nil {
-		// Transfer failed - update transaction status
-		failureReason := transferErr.Error()
-		updateErr := s.transactionRepo.UpdateStatus(ctx, transactionID, models.TransactionStatusFailed, &failureReason)
-		if updateErr != nil {
-			s.logger.WithError(updateErr).Error("Failed to update failed transaction status")
+	saga, sagaErr := s.sagaRepo.Get(ctx, transactionID)
+	if sagaErr != nil {
+		if sagaErr.Code != errors.ErrCodeNotFound {
+			return sagaErr
 		}
-
-		s.logger.WithError(transferErr).WithField("transaction_id", transactionID).Error("Transfer failed")
-		return errors.Internal(fmt.Sprintf("transfer failed: %s", failureReason))
-	}
-
-	// Create ledger journal entry for audit trail
-	if s.ledgerClient != nil {
-		if ledgerErr := s.createTransferLedgerEntry(ctx, transaction); ledgerErr != nil {
-			// Log error but don't fail the transaction - wallet balances already updated
-			// In production, this would trigger a reconciliation process
-			s.logger.WithError(ledgerErr).WithField("transaction_id", transactionID).Error("Failed to create ledger entry - reconciliation needed")
+		if saga, sagaErr = s.sagaRepo.Start(ctx, s.newSaga(transactionID)); sagaErr != nil {
+			return sagaErr
 		}
 	}
 
-	// Mark transaction as completed
-	completeErr := s.transactionRepo.UpdateStatus(ctx, transactionID, models.TransactionStatusCompleted, nil)
-	if completeErr != nil {
-		s.logger.WithError(completeErr).Error("Failed to mark transaction as completed")
-		return completeErr
+	if !saga.IsActive() {
+		return errors.BadRequest(fmt.Sprintf("transaction saga is not active (state: %s)", saga.State))
 	}
 
-	// Publish transaction.completed event
-	if s.eventPublisher != nil {
-		s.eventPublisher.PublishTransactionEvent("transaction.completed", transactionID, map[string]interface{}{
-			"type":                  string(transaction.Type),
-			"status":                string(models.TransactionStatusCompleted),
-			"amount":                transaction.Amount,
-			"currency":              transaction.Currency,
-			"source_wallet_id":      transaction.SourceWalletID,
-			"destination_wallet_id": transaction.DestinationWalletID,
-		})
-	}
-
-	s.logger.WithField("transaction_id", transactionID).Info("Transfer completed successfully")
-	return nil
+	return s.runSaga(ctx, saga)
 }
 
-// evaluateTransactionRisk evaluates risk for a transaction using the Risk Service.
-// Returns (blocked bool, error). If blocked is true, the transaction was rejected by risk.
-func (s *TransactionService) evaluateTransactionRisk(ctx context.Context, transaction *models.Transaction) (bool, error) {
+// evaluateTransactionRisk evaluates risk for a transaction using the Risk Service
+// and records the outcome in the transaction metadata. Returns a nil result if
+// risk evaluation is not configured.
+func (s *TransactionService) evaluateTransactionRisk(ctx context.Context, transaction *models.Transaction) (*RiskEvaluationResult, error) {
 	if s.riskClient == nil {
 		s.logger.Debug("Risk client not configured, skipping risk evaluation")
-		return false, nil
+		return nil, nil
 	}
 
 	// Get user ID from wallet service for proper per-user risk limits
@@ -615,7 +726,7 @@ func (s *TransactionService) evaluateTransactionRisk(ctx context.Context, transa
 	// Call risk service
 	result, err := s.riskClient.EvaluateTransaction(ctx, riskReq)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	// Log risk evaluation result
@@ -650,14 +761,6 @@ func (s *TransactionService) evaluateTransactionRisk(ctx context.Context, transa
 			"reason":         result.Reason,
 			"risk_score":     result.RiskScore,
 		}).Warn("Transaction BLOCKED by risk evaluation")
-
-		// Mark transaction as failed
-		failureReason := fmt.Sprintf("blocked by risk: %s", result.Reason)
-		if updateErr := s.transactionRepo.UpdateStatus(ctx, transaction.ID, models.TransactionStatusFailed, &failureReason); updateErr != nil {
-			s.logger.WithError(updateErr).Error("Failed to update blocked transaction status")
-		}
-
-		return true, nil // blocked = true
 	}
 
 	if result.Action == "flag" {
@@ -668,70 +771,7 @@ func (s *TransactionService) evaluateTransactionRisk(ctx context.Context, transa
 		// Transaction proceeds but is flagged for compliance review
 	}
 
-	return false, nil // not blocked
-}
-
-// createTransferLedgerEntry creates a double-entry journal entry for a transfer transaction.
-func (s *TransactionService) createTransferLedgerEntry(ctx context.Context, transaction *models.Transaction) error {
-	if transaction.SourceWalletID == nil || transaction.DestinationWalletID == nil {
-		return fmt.Errorf("transfer must have both source and destination wallets")
-	}
-
-	// Get ledger account IDs for both wallets
-	sourceWalletInfo, srcErr := s.walletClient.GetWalletInfo(ctx, *transaction.SourceWalletID)
-	if srcErr != nil {
-		return fmt.Errorf("failed to get source wallet info: %w", srcErr)
-	}
-
-	destWalletInfo, destErr := s.walletClient.GetWalletInfo(ctx, *transaction.DestinationWalletID)
-	if destErr != nil {
-		return fmt.Errorf("failed to get destination wallet info: %w", destErr)
-	}
-
-	if sourceWalletInfo.LedgerAccountID == "" || destWalletInfo.LedgerAccountID == "" {
-		return fmt.Errorf("wallet missing ledger account ID")
-	}
-
-	// Create balanced journal entry: debit source, credit destination
-	journalReq := &CreateJournalEntryRequest{
-		Type:          "transfer",
-		Description:   fmt.Sprintf("Transfer: %s", transaction.Description),
-		ReferenceType: "transaction",
-		ReferenceID:   transaction.ID,
-		Lines: []LedgerLine{
-			{
-				AccountID:    sourceWalletInfo.LedgerAccountID,
-				DebitAmount:  transaction.Amount,
-				CreditAmount: 0,
-				Description:  fmt.Sprintf("Transfer to %s", *transaction.DestinationWalletID),
-			},
-			{
-				AccountID:    destWalletInfo.LedgerAccountID,
-				DebitAmount:  0,
-				CreditAmount: transaction.Amount,
-				Description:  fmt.Sprintf("Transfer from %s", *transaction.SourceWalletID),
-			},
-		},
-		Metadata: map[string]any{
-			"transaction_id":        transaction.ID,
-			"source_wallet_id":      *transaction.SourceWalletID,
-			"destination_wallet_id": *transaction.DestinationWalletID,
-		},
-	}
-
-	// Create and post the journal entry
-	entry, ledgerErr := s.ledgerClient.CreateAndPostJournalEntry(ctx, journalReq)
-	if ledgerErr != nil {
-		return fmt.Errorf("failed to create/post journal entry: %w", ledgerErr)
-	}
-
-	s.logger.With(map[string]interface{}{
-		"transaction_id":   transaction.ID,
-		"journal_entry_id": entry.ID,
-		"entry_number":     entry.EntryNumber,
-	}).Info("Ledger journal entry created for transfer")
-
-	return nil
+	return result, nil
 }
 
 // ========================================================================
@@ -768,6 +808,27 @@ func (s *TransactionService) UpdateTransactionCategory(ctx context.Context, tran
 	return transaction, nil
 }
 
+// maxWalletTotalsBatch caps the number of wallets in one totals request.
+const maxWalletTotalsBatch = 500
+
+// GetWalletTotals computes settled transaction totals for a batch of wallets.
+// Used by the wallet service to reconcile balances against transaction history.
+func (s *TransactionService) GetWalletTotals(ctx context.Context, walletIDs []string) ([]models.WalletTotals, *errors.Error) {
+	if len(walletIDs) == 0 {
+		return []models.WalletTotals{}, nil
+	}
+	if len(walletIDs) > maxWalletTotalsBatch {
+		return nil, errors.Validation(fmt.Sprintf("at most %d wallet IDs allowed per request", maxWalletTotalsBatch))
+	}
+	for _, id := range walletIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, errors.Validation(fmt.Sprintf("invalid wallet ID: %s", id))
+		}
+	}
+
+	return s.transactionRepo.SumByWallets(ctx, walletIDs)
+}
+
 // GetSpendingSummary retrieves spending summary grouped by category for a wallet.
 func (s *TransactionService) GetSpendingSummary(ctx context.Context, walletID, startDate, endDate string) (*models.CategorySummaryResponse, *errors.Error) {
 	summaries, err := s.transactionRepo.GetCategorySummary(ctx, walletID, startDate, endDate)
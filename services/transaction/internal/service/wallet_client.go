@@ -6,6 +6,7 @@ import (
 
 	"github.com/vnykmshr/nivo/shared/clients"
 	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 )
 
 // WalletClient handles communication with the Wallet service.
@@ -13,17 +14,10 @@ type WalletClient struct {
 	*clients.BaseClient
 }
 
-// NewWalletClient creates a new Wallet service client.
-func NewWalletClient(baseURL string) *WalletClient {
+// NewWalletClient creates a Wallet service client authenticated with service tokens.
+func NewWalletClient(baseURL string, tokens clients.ServiceTokenSource) *WalletClient {
 	return &WalletClient{
-		BaseClient: clients.NewBaseClient(baseURL, clients.DefaultTimeout),
-	}
-}
-
-// NewWalletClientWithSecret creates a Wallet client with internal service authentication.
-func NewWalletClientWithSecret(baseURL, internalSecret string) *WalletClient {
-	return &WalletClient{
-		BaseClient: clients.NewInternalClient(baseURL, clients.DefaultTimeout, internalSecret),
+		BaseClient: clients.NewInternalClient(baseURL, clients.DefaultTimeout, tokens, sharedjwt.ServiceWallet),
 	}
 }
 
@@ -58,6 +52,20 @@ type DepositRequest struct {
 	Description   string `json:"description"`
 }
 
+// WithdrawalRequest represents an internal withdrawal request.
+type WithdrawalRequest struct {
+	WalletID      string `json:"wallet_id"`
+	Amount        int64  `json:"amount"`
+	TransactionID string `json:"transaction_id"`
+}
+
+// HoldRequest represents an internal request to hold funds for a transaction.
+type HoldRequest struct {
+	WalletID      string `json:"wallet_id"`
+	Amount        int64  `json:"amount"`
+	TransactionID string `json:"transaction_id"`
+}
+
 // WalletInfo represents wallet details including ownership.
 type WalletInfo struct {
 	ID              string `json:"id"`
@@ -66,6 +74,14 @@ type WalletInfo struct {
 	LedgerAccountID string `json:"ledger_account_id"`
 }
 
+// Beneficiary represents a saved recipient of the authenticated user.
+type Beneficiary struct {
+	ID       string `json:"id"`
+	Nickname string `json:"nickname"`
+	Phone    string `json:"phone"`
+	WalletID string `json:"wallet_id"`
+}
+
 // GetBalance retrieves the balance of a wallet.
 func (c *WalletClient) GetBalance(ctx context.Context, walletID string) (*WalletBalance, *errors.Error) {
 	var result WalletBalance
@@ -99,6 +115,29 @@ func (c *WalletClient) CreditDeposit(ctx context.Context, req *DepositRequest) *
 	return c.Post(ctx, "/internal/v1/wallets/deposit", req, nil)
 }
 
+// DebitWithdrawal debits a withdrawal from a wallet (internal endpoint).
+// Funds held for the transaction are captured.
+func (c *WalletClient) DebitWithdrawal(ctx context.Context, req *WithdrawalRequest) *errors.Error {
+	return c.Post(ctx, "/internal/v1/wallets/withdraw", req, nil)
+}
+
+// HoldFunds holds funds in a wallet for a pending transaction (internal endpoint).
+func (c *WalletClient) HoldFunds(ctx context.Context, req *HoldRequest) *errors.Error {
+	return c.Post(ctx, "/internal/v1/wallets/holds", req, nil)
+}
+
+// ReleaseHold releases the funds held for a transaction (internal endpoint).
+func (c *WalletClient) ReleaseHold(ctx context.Context, transactionID string) *errors.Error {
+	path := fmt.Sprintf("/internal/v1/wallets/holds/%s/release", transactionID)
+	return c.Post(ctx, path, nil, nil)
+}
+
+// ReverseMovement undoes the balance movement of a transaction (internal endpoint).
+func (c *WalletClient) ReverseMovement(ctx context.Context, transactionID string) *errors.Error {
+	path := fmt.Sprintf("/internal/v1/wallets/transactions/%s/reverse", transactionID)
+	return c.Post(ctx, path, nil, nil)
+}
+
 // GetWalletInfo retrieves wallet information including owner (internal endpoint).
 func (c *WalletClient) GetWalletInfo(ctx context.Context, walletID string) (*WalletInfo, *errors.Error) {
 	var result WalletInfo
@@ -122,3 +161,13 @@ func (c *WalletClient) VerifyWalletOwnership(ctx context.Context, walletID, user
 
 	return nil
 }
+
+// GetBeneficiary retrieves a beneficiary of the authenticated user.
+func (c *WalletClient) GetBeneficiary(ctx context.Context, beneficiaryID string) (*Beneficiary, *errors.Error) {
+	var result Beneficiary
+	path := fmt.Sprintf("/api/v1/beneficiaries/%s", beneficiaryID)
+	if err := c.GetWithHeaders(ctx, path, &result, userAuthHeaders(ctx)); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
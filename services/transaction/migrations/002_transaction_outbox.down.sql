@@ -0,0 +1,3 @@
+-- Transaction Outbox Rollback
+
+DROP TABLE IF EXISTS transaction_outbox;
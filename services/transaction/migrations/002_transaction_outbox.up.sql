@@ -0,0 +1,28 @@
+-- ============================================================================
+-- Transactional Outbox
+-- ============================================================================
+
+-- Events of a transaction are written here in the same database transaction as
+-- the status change that causes them, then delivered by the outbox relay with
+-- retries. Delivery is at least once, so consumers must tolerate duplicates
+-- (events carry event_id). Ledger postings are a step of the transaction saga.
+CREATE TABLE IF NOT EXISTS transaction_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id),
+    kind VARCHAR(20) NOT NULL,
+    event_type VARCHAR(100),
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT transaction_outbox_kind_check CHECK (kind IN ('event')),
+    CONSTRAINT transaction_outbox_status_check CHECK (status IN ('pending', 'delivered', 'dead')),
+    CONSTRAINT transaction_outbox_event_type_check CHECK (kind != 'event' OR event_type IS NOT NULL)
+);
+
+CREATE INDEX idx_transaction_outbox_due ON transaction_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_transaction_outbox_transaction ON transaction_outbox(transaction_id);
@@ -0,0 +1,3 @@
+-- Transaction Sagas Rollback
+
+DROP TABLE IF EXISTS transaction_sagas;
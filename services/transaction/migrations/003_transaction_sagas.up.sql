@@ -0,0 +1,31 @@
+-- ============================================================================
+-- Transaction Sagas
+-- ============================================================================
+
+-- Transfers, deposits and withdrawals run as a saga of steps across the risk,
+-- wallet and ledger services: risk -> reserve -> wallet_move -> ledger_post ->
+-- complete. The saga state is persisted after every step so a transaction
+-- interrupted by a restart is resumed by the recovery worker, and a failed
+-- transaction is compensated by undoing its completed steps in reverse order.
+CREATE TABLE IF NOT EXISTS transaction_sagas (
+    transaction_id UUID PRIMARY KEY REFERENCES transactions(id),
+    state VARCHAR(20) NOT NULL DEFAULT 'running',
+    current_step VARCHAR(20) NOT NULL DEFAULT 'risk',
+    completed_steps TEXT[] NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    failure_reason TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT transaction_sagas_state_check CHECK (state IN ('running', 'compensating', 'completed', 'compensated', 'manual_review')),
+    CONSTRAINT transaction_sagas_step_check CHECK (current_step IN ('risk', 'reserve', 'wallet_move', 'ledger_post', 'complete'))
+);
+
+CREATE INDEX idx_transaction_sagas_due ON transaction_sagas(next_attempt_at) WHERE state IN ('running', 'compensating');
+
+CREATE TRIGGER update_transaction_sagas_updated_at
+    BEFORE UPDATE ON transaction_sagas
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+-- Scheduled Transfers Rollback
+
+DROP TABLE IF EXISTS scheduled_transfer_executions;
+DROP TABLE IF EXISTS scheduled_transfers;
@@ -0,0 +1,59 @@
+-- ============================================================================
+-- Scheduled Transfers
+-- ============================================================================
+
+-- One-off future transfers and recurring transfers. Occurrence n (from 0) is
+-- due at start_at plus n periods of the frequency; the scheduler runs active
+-- schedules whose next_run_at is due through the regular transfer path.
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    source_wallet_id UUID NOT NULL,
+    destination_wallet_id UUID NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    frequency VARCHAR(20) NOT NULL,
+    start_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_at TIMESTAMP WITH TIME ZONE,
+    max_occurrences INTEGER,
+    occurrences INTEGER NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT scheduled_transfers_amount_check CHECK (amount > 0),
+    CONSTRAINT scheduled_transfers_wallets_check CHECK (source_wallet_id != destination_wallet_id),
+    CONSTRAINT scheduled_transfers_frequency_check CHECK (frequency IN ('once', 'daily', 'weekly', 'monthly')),
+    CONSTRAINT scheduled_transfers_status_check CHECK (status IN ('active', 'paused', 'completed', 'cancelled')),
+    CONSTRAINT scheduled_transfers_max_occurrences_check CHECK (max_occurrences IS NULL OR max_occurrences > 0)
+);
+
+CREATE INDEX idx_scheduled_transfers_user ON scheduled_transfers(user_id, created_at DESC);
+CREATE INDEX idx_scheduled_transfers_due ON scheduled_transfers(next_run_at) WHERE status = 'active';
+
+CREATE TRIGGER update_scheduled_transfers_updated_at
+    BEFORE UPDATE ON scheduled_transfers
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- One row per occurrence. The unique occurrence makes running an occurrence
+-- safe to repeat: the transfer reference is derived from it.
+CREATE TABLE IF NOT EXISTS scheduled_transfer_executions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    scheduled_transfer_id UUID NOT NULL REFERENCES scheduled_transfers(id),
+    occurrence INTEGER NOT NULL,
+    scheduled_for TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    transaction_id UUID REFERENCES transactions(id),
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT scheduled_transfer_executions_status_check CHECK (status IN ('pending', 'executed', 'failed', 'skipped')),
+    CONSTRAINT scheduled_transfer_executions_occurrence_unique UNIQUE (scheduled_transfer_id, occurrence)
+);
@@ -0,0 +1,3 @@
+-- Payment Requests Rollback
+
+DROP TABLE IF EXISTS payment_requests;
@@ -0,0 +1,42 @@
+-- ============================================================================
+-- Payment Requests
+-- ============================================================================
+
+-- A user (the requester) asking another user (the payer) for money. Accepting
+-- creates a regular transfer from the payer's wallet to requester_wallet_id;
+-- locked_until is set while the transfer is being created so that the request
+-- is not accepted twice or declined meanwhile.
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    requester_user_id UUID NOT NULL,
+    requester_wallet_id UUID NOT NULL,
+    requester_name VARCHAR(255) NOT NULL DEFAULT '',
+    payer_user_id UUID NOT NULL,
+    payer_phone VARCHAR(20) NOT NULL,
+    payer_name VARCHAR(255) NOT NULL DEFAULT '',
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    transaction_id UUID REFERENCES transactions(id),
+    decline_reason VARCHAR(500),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT payment_requests_amount_check CHECK (amount > 0),
+    CONSTRAINT payment_requests_users_check CHECK (requester_user_id != payer_user_id),
+    CONSTRAINT payment_requests_status_check CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled', 'expired')),
+    CONSTRAINT payment_requests_accepted_check CHECK (status != 'accepted' OR transaction_id IS NOT NULL)
+);
+
+CREATE INDEX idx_payment_requests_payer ON payment_requests(payer_user_id, created_at DESC);
+CREATE INDEX idx_payment_requests_requester ON payment_requests(requester_user_id, created_at DESC);
+CREATE INDEX idx_payment_requests_expiry ON payment_requests(expires_at) WHERE status = 'pending';
+
+CREATE TRIGGER update_payment_requests_updated_at
+    BEFORE UPDATE ON payment_requests
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
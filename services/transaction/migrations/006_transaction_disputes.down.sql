@@ -0,0 +1,3 @@
+-- Transaction Disputes Rollback
+
+DROP TABLE IF EXISTS disputes;
@@ -0,0 +1,62 @@
+-- ============================================================================
+-- Transaction Disputes
+-- ============================================================================
+
+-- A customer's claim that a completed transaction from their wallet should be
+-- refunded. Admins investigate and resolve it; a resolution in the customer's
+-- favour is settled with a refund transaction crediting customer_wallet_id and,
+-- for transfers, a reversal transaction debiting counterparty_wallet_id. The
+-- latest attempt of each is linked here; settled_at is set once both are done.
+-- locked_until is set while settlement runs and delays retrying an attempt.
+-- settlement_attempts counts the settlement transactions created, failed ones
+-- included; once they run out the dispute is settlement_failed and is left to
+-- an admin.
+CREATE TABLE IF NOT EXISTS disputes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id),
+    customer_user_id UUID NOT NULL,
+    customer_wallet_id UUID NOT NULL,
+    counterparty_user_id UUID,
+    counterparty_wallet_id UUID,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    reason VARCHAR(30) NOT NULL,
+    evidence TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    assigned_to UUID,
+    resolution_notes TEXT,
+    resolved_by UUID,
+    credit_transaction_id UUID REFERENCES transactions(id),
+    reversal_transaction_id UUID REFERENCES transactions(id),
+    acknowledge_by TIMESTAMP WITH TIME ZONE NOT NULL,
+    resolve_by TIMESTAMP WITH TIME ZONE NOT NULL,
+    sla_breached_at TIMESTAMP WITH TIME ZONE,
+    investigating_at TIMESTAMP WITH TIME ZONE,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    settled_at TIMESTAMP WITH TIME ZONE,
+    settlement_attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT disputes_amount_check CHECK (amount > 0),
+    CONSTRAINT disputes_reason_check CHECK (reason IN ('unauthorized', 'not_received', 'duplicate', 'incorrect_amount', 'other')),
+    CONSTRAINT disputes_status_check CHECK (status IN ('open', 'investigating', 'resolved_in_favour', 'settlement_failed', 'rejected')),
+    CONSTRAINT disputes_resolved_check CHECK (
+        status NOT IN ('resolved_in_favour', 'settlement_failed', 'rejected') OR (resolved_at IS NOT NULL AND resolution_notes IS NOT NULL)
+    ),
+    CONSTRAINT disputes_settled_check CHECK (settled_at IS NULL OR status = 'resolved_in_favour')
+);
+
+-- A transaction can be disputed once: a rejection is final
+CREATE UNIQUE INDEX idx_disputes_transaction ON disputes(transaction_id);
+CREATE INDEX idx_disputes_customer ON disputes(customer_user_id, created_at DESC);
+CREATE INDEX idx_disputes_counterparty ON disputes(counterparty_user_id, created_at DESC) WHERE counterparty_user_id IS NOT NULL;
+CREATE INDEX idx_disputes_active ON disputes(status, resolve_by) WHERE status IN ('open', 'investigating');
+CREATE INDEX idx_disputes_unsettled ON disputes(resolved_at) WHERE status = 'resolved_in_favour' AND settled_at IS NULL;
+
+CREATE TRIGGER update_disputes_updated_at
+    BEFORE UPDATE ON disputes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
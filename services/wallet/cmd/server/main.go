@@ -1,18 +1,29 @@
 package main
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/vnykmshr/nivo/services/wallet/internal/handler"
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
 	"github.com/vnykmshr/nivo/services/wallet/internal/repository"
 	"github.com/vnykmshr/nivo/services/wallet/internal/router"
 	"github.com/vnykmshr/nivo/services/wallet/internal/service"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/audit"
 	"github.com/vnykmshr/nivo/shared/clients"
 	"github.com/vnykmshr/nivo/shared/events"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
+	"github.com/vnykmshr/nivo/shared/metrics"
+	"github.com/vnykmshr/nivo/shared/middleware"
 	"github.com/vnykmshr/nivo/shared/server"
 )
 
 func main() {
+	// Track worker cancel function for cleanup
+	var workerCancel context.CancelFunc
+
 	server.Run(server.ServiceConfig{
 		Name: "wallet",
 		SetupHandler: func(ctx *server.BootstrapContext) (http.Handler, error) {
@@ -21,6 +32,9 @@ func main() {
 			beneficiaryRepo := repository.NewBeneficiaryRepository(ctx.DB.DB)
 			upiDepositRepo := repository.NewUPIDepositRepository(ctx.DB.DB)
 			virtualCardRepo := repository.NewVirtualCardRepository(ctx.DB.DB)
+			reconRepo := repository.NewReconciliationRepository(ctx.DB.DB)
+			groupRepo := repository.NewExpenseGroupRepository(ctx.DB.DB)
+			idempotencyStore := middleware.NewPostgresIdempotencyStore(ctx.DB.DB)
 
 			// Initialize event publisher
 			eventPublisher := events.NewPublisher(events.PublishConfig{
@@ -28,28 +42,101 @@ func main() {
 				ServiceName: "wallet",
 			})
 
-			// Initialize external service clients
-			ledgerClient := service.NewLedgerClient(server.GetEnv("LEDGER_SERVICE_URL", "http://ledger-service:8081"))
+			// Initialize external service clients, with service tokens from the identity service for internal calls
+			identityURL := server.GetEnv("IDENTITY_SERVICE_URL", "http://identity-service:8080")
+			serviceTokens := clients.NewServiceTokenClient(identityURL, sharedjwt.ServiceWallet, server.RequireEnv("SERVICE_SECRET"))
+			ledgerClient := service.NewLedgerClient(server.GetEnv("LEDGER_SERVICE_URL", "http://ledger-service:8081"), serviceTokens)
 			notificationClient := clients.NewNotificationClient(server.GetEnv("NOTIFICATION_SERVICE_URL", "http://notification-service:8087"))
-			identityClient := service.NewIdentityClient(server.GetEnv("IDENTITY_SERVICE_URL", "http://identity-service:8080"))
+			identityClient := service.NewIdentityClient(identityURL)
+			verificationClient := clients.NewVerificationClient(identityURL, serviceTokens)
+			transactionClient := service.NewTransactionClient(server.GetEnv("TRANSACTION_SERVICE_URL", "http://transaction-service:8084"), serviceTokens)
+
+			metricsCollector := metrics.NewCollector("wallet")
 
 			// Initialize service layer
 			walletService := service.NewWalletService(walletRepo, eventPublisher, ledgerClient, notificationClient, identityClient)
-			beneficiaryService := service.NewBeneficiaryService(beneficiaryRepo, walletRepo, identityClient, eventPublisher)
+			auditLog := audit.NewLog(audit.NewPostgresStore(ctx.DB.DB), "wallet")
+			walletService.SetAuditLog(auditLog)
+			approvals := approval.NewWorkflow(approval.NewPostgresStore(ctx.DB.DB), "wallet")
+			approvals.SetAuditLog(auditLog)
+			walletService.SetApprovals(approvals)
+			beneficiaryService := service.NewBeneficiaryService(beneficiaryRepo, walletRepo, identityClient, verificationClient, eventPublisher)
 			upiDepositService := service.NewUPIDepositService(upiDepositRepo, walletRepo, eventPublisher)
 			virtualCardService := service.NewVirtualCardService(virtualCardRepo, walletRepo)
+			reconService := service.NewReconciliationService(walletRepo, reconRepo, ledgerClient, transactionClient, metricsCollector)
+			groupService := service.NewExpenseGroupService(groupRepo, beneficiaryRepo, walletRepo, identityClient, transactionClient, eventPublisher)
+
+			// Start background worker for scheduled reconciliation
+			reconInterval, err := time.ParseDuration(server.GetEnv("RECONCILIATION_INTERVAL", "1h"))
+			if err != nil {
+				return nil, err
+			}
+
+			workerCtx, cancel := context.WithCancel(context.Background())
+			workerCancel = cancel
+
+			go func() {
+				ctx.Logger.WithField("interval", reconInterval.String()).Info("Starting reconciliation worker...")
+				ticker := time.NewTicker(reconInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						run, err := reconService.RunReconciliation(workerCtx, models.ReconciliationTriggerScheduled)
+						if err != nil {
+							ctx.Logger.WithError(err).Error("Reconciliation run failed")
+							continue
+						}
+						ctx.Logger.WithField("wallets_checked", run.WalletsChecked).
+							WithField("discrepancies", run.DiscrepanciesFound).
+							Info("Reconciliation run completed")
+					case <-workerCtx.Done():
+						ctx.Logger.Info("Reconciliation worker stopped")
+						return
+					}
+				}
+			}()
+
+			go func() {
+				ticker := time.NewTicker(time.Hour)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						purged, err := idempotencyStore.PurgeExpired(workerCtx)
+						if err != nil {
+							ctx.Logger.WithError(err).Error("Idempotency key purge failed")
+							continue
+						}
+						if purged > 0 {
+							ctx.Logger.WithField("purged", purged).Info("Expired idempotency keys purged")
+						}
+					case <-workerCtx.Done():
+						return
+					}
+				}
+			}()
 
 			// Initialize handler layer
 			walletHandler := handler.NewWalletHandler(walletService)
 			beneficiaryHandler := handler.NewBeneficiaryHandler(beneficiaryService)
 			upiDepositHandler := handler.NewUPIDepositHandler(upiDepositService)
 			virtualCardHandler := handler.NewVirtualCardHandler(virtualCardService)
+			reconHandler := handler.NewReconciliationHandler(reconService)
+			groupHandler := handler.NewExpenseGroupHandler(groupService)
 
-			// Setup routes
-			jwtSecret := server.RequireEnv("JWT_SECRET")
-			internalSecret := server.GetEnv("INTERNAL_SERVICE_SECRET", "")
+			// Setup routes (user and service tokens are verified against the identity service's published keys)
+			jwtKeys := sharedjwt.NewRemoteKeySet(server.GetEnv("JWKS_URL", sharedjwt.DefaultJWKSURL))
 
-			return router.SetupRoutes(walletHandler, beneficiaryHandler, upiDepositHandler, virtualCardHandler, jwtSecret, internalSecret), nil
+			return router.SetupRoutes(walletHandler, beneficiaryHandler, upiDepositHandler, virtualCardHandler, reconHandler, groupHandler, approval.NewHandler(approvals), metricsCollector, idempotencyStore, jwtKeys), nil
+		},
+		Cleanup: func() error {
+			if workerCancel != nil {
+				workerCancel()
+			}
+			return nil
 		},
 	})
 }
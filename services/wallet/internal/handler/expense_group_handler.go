@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/vnykmshr/gopantic/pkg/model"
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/services/wallet/internal/service"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	"github.com/vnykmshr/nivo/shared/pagination"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// ExpenseGroupHandler handles HTTP requests for split-bill expense groups.
+type ExpenseGroupHandler struct {
+	groupService *service.ExpenseGroupService
+}
+
+// NewExpenseGroupHandler creates a new expense group handler.
+func NewExpenseGroupHandler(groupService *service.ExpenseGroupService) *ExpenseGroupHandler {
+	return &ExpenseGroupHandler{
+		groupService: groupService,
+	}
+}
+
+// CreateGroup handles POST /api/v1/expense-groups
+func (h *ExpenseGroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := parseBody[models.CreateExpenseGroupRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, group)
+}
+
+// ListGroups handles GET /api/v1/expense-groups
+func (h *ExpenseGroupHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	params := pagination.FromRequest(r)
+
+	groups, total, err := h.groupService.ListGroups(r.Context(), userID, params.PerPage, params.Offset)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, groups, params.Page, params.PerPage, int64(total))
+}
+
+// GetGroup handles GET /api/v1/expense-groups/{id}
+func (h *ExpenseGroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	group, err := h.groupService.GetGroup(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, group)
+}
+
+// AddMember handles POST /api/v1/expense-groups/{id}/members
+func (h *ExpenseGroupHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := parseBody[models.AddGroupMemberRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	member, err := h.groupService.AddMember(r.Context(), userID, r.PathValue("id"), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, member)
+}
+
+// AddExpense handles POST /api/v1/expense-groups/{id}/expenses
+func (h *ExpenseGroupHandler) AddExpense(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	req, bindErr := parseBody[models.AddExpenseRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	expense, err := h.groupService.AddExpense(r.Context(), userID, r.PathValue("id"), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, expense)
+}
+
+// ListExpenses handles GET /api/v1/expense-groups/{id}/expenses
+func (h *ExpenseGroupHandler) ListExpenses(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	params := pagination.FromRequest(r)
+
+	expenses, total, err := h.groupService.ListExpenses(r.Context(), userID, r.PathValue("id"), params.PerPage, params.Offset)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, expenses, params.Page, params.PerPage, int64(total))
+}
+
+// GetBalances handles GET /api/v1/expense-groups/{id}/balances
+// Returns each member's net balance and the suggested settle-up transfers.
+func (h *ExpenseGroupHandler) GetBalances(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	balances, err := h.groupService.GetBalances(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, balances)
+}
+
+// Settle handles POST /api/v1/expense-groups/{id}/settle
+// Pays everything the caller owes in the group and returns the settlements.
+// The body, with an optional verification token, may be omitted.
+func (h *ExpenseGroupHandler) Settle(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	var req models.SettleGroupRequest
+	if r.ContentLength != 0 {
+		var bindErr *errors.Error
+		if req, bindErr = parseBody[models.SettleGroupRequest](r); bindErr != nil {
+			response.Error(w, bindErr)
+			return
+		}
+	}
+
+	settlements, err := h.groupService.Settle(r.Context(), userID, r.PathValue("id"), req.VerificationToken)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, settlements)
+}
+
+// ListSettlements handles GET /api/v1/expense-groups/{id}/settlements
+func (h *ExpenseGroupHandler) ListSettlements(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	params := pagination.FromRequest(r)
+
+	settlements, total, err := h.groupService.ListSettlements(r.Context(), userID, r.PathValue("id"), params.PerPage, params.Offset)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, settlements, params.Page, params.PerPage, int64(total))
+}
+
+// parseBody reads and validates a JSON request body.
+func parseBody[T any](r *http.Request) (T, *errors.Error) {
+	var zero T
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return zero, errors.BadRequest("failed to read request body")
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	req, parseErr := model.ParseInto[T](body)
+	if parseErr != nil {
+		return zero, errors.Validation(parseErr.Error())
+	}
+
+	return req, nil
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/vnykmshr/gopantic/pkg/model"
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/services/wallet/internal/service"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+	"github.com/vnykmshr/nivo/shared/pagination"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// ReconciliationHandler handles HTTP requests for wallet reconciliation (admin only).
+type ReconciliationHandler struct {
+	reconService *service.ReconciliationService
+}
+
+// NewReconciliationHandler creates a new reconciliation handler.
+func NewReconciliationHandler(reconService *service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconService: reconService,
+	}
+}
+
+// ListDiscrepancies handles GET /api/v1/admin/reconciliation/discrepancies?status=open&severity=high&wallet_id=...
+func (h *ReconciliationHandler) ListDiscrepancies(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &models.DiscrepancyFilter{}
+
+	if status := query.Get("status"); status != "" {
+		s := models.DiscrepancyStatus(status)
+		filter.Status = &s
+	}
+	if severity := query.Get("severity"); severity != "" {
+		s := models.DiscrepancySeverity(severity)
+		filter.Severity = &s
+	}
+	if walletID := query.Get("wallet_id"); walletID != "" {
+		filter.WalletID = &walletID
+	}
+
+	params := pagination.FromRequest(r)
+
+	discrepancies, total, err := h.reconService.ListDiscrepancies(r.Context(), filter, params.PerPage, params.Offset)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, discrepancies, params.Page, params.PerPage, int64(total))
+}
+
+// GetDiscrepancy handles GET /api/v1/admin/reconciliation/discrepancies/{id}
+func (h *ReconciliationHandler) GetDiscrepancy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		response.Error(w, errors.BadRequest("discrepancy ID is required"))
+		return
+	}
+
+	discrepancy, err := h.reconService.GetDiscrepancy(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, discrepancy)
+}
+
+// ResolveDiscrepancy handles POST /api/v1/admin/reconciliation/discrepancies/{id}/resolve
+func (h *ReconciliationHandler) ResolveDiscrepancy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		response.Error(w, errors.BadRequest("discrepancy ID is required"))
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok || userID == "" {
+		response.Error(w, errors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	req, parseErr := model.ParseInto[models.ResolveDiscrepancyRequest](body)
+	if parseErr != nil {
+		response.Error(w, errors.Validation(parseErr.Error()))
+		return
+	}
+
+	discrepancy, serviceErr := h.reconService.ResolveDiscrepancy(r.Context(), id, userID, req.Note)
+	if serviceErr != nil {
+		response.Error(w, serviceErr)
+		return
+	}
+
+	response.OK(w, discrepancy)
+}
+
+// TriggerRun handles POST /api/v1/admin/reconciliation/runs
+// Runs reconciliation synchronously and returns the finished run.
+func (h *ReconciliationHandler) TriggerRun(w http.ResponseWriter, r *http.Request) {
+	run, err := h.reconService.RunReconciliation(r.Context(), models.ReconciliationTriggerManual)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, run)
+}
+
+// ListRuns handles GET /api/v1/admin/reconciliation/runs
+func (h *ReconciliationHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	params := pagination.FromRequest(r)
+
+	runs, total, err := h.reconService.ListRuns(r.Context(), params.PerPage, params.Offset)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, runs, params.Page, params.PerPage, int64(total))
+}
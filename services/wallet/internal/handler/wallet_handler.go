@@ -180,6 +180,7 @@ func (h *WalletHandler) FreezeWallet(w http.ResponseWriter, r *http.Request) {
 }
 
 // UnfreezeWallet handles POST /api/v1/wallets/:id/unfreeze
+// Submits the unfreeze for approval by a second admin and returns the pending request.
 func (h *WalletHandler) UnfreezeWallet(w http.ResponseWriter, r *http.Request) {
 	walletID := r.PathValue("id")
 
@@ -188,16 +189,17 @@ func (h *WalletHandler) UnfreezeWallet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	wallet, err := h.walletService.UnfreezeWallet(r.Context(), walletID)
+	approvalReq, err := h.walletService.RequestUnfreeze(r.Context(), walletID)
 	if err != nil {
 		response.Error(w, err)
 		return
 	}
 
-	response.OK(w, wallet)
+	response.Success(w, http.StatusAccepted, approvalReq)
 }
 
 // CloseWallet handles POST /api/v1/wallets/:id/close
+// Submits the closure for approval by a second admin and returns the pending request.
 func (h *WalletHandler) CloseWallet(w http.ResponseWriter, r *http.Request) {
 	walletID := r.PathValue("id")
 
@@ -220,13 +222,13 @@ func (h *WalletHandler) CloseWallet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	wallet, closeErr := h.walletService.CloseWallet(r.Context(), walletID, req.Reason)
+	approvalReq, closeErr := h.walletService.RequestClose(r.Context(), walletID, req.Reason)
 	if closeErr != nil {
 		response.Error(w, closeErr)
 		return
 	}
 
-	response.OK(w, wallet)
+	response.Success(w, http.StatusAccepted, approvalReq)
 }
 
 // GetWalletBalance handles GET /api/v1/wallets/:id/balance
@@ -266,6 +268,7 @@ func (h *WalletHandler) GetWalletLimits(w http.ResponseWriter, r *http.Request)
 }
 
 // UpdateWalletLimits handles PUT /api/v1/wallets/:id/limits
+// Lower limits apply straight away; an increase returns the pending approval request.
 func (h *WalletHandler) UpdateWalletLimits(w http.ResponseWriter, r *http.Request) {
 	walletID := r.PathValue("id")
 
@@ -288,11 +291,15 @@ func (h *WalletHandler) UpdateWalletLimits(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	limits, updateErr := h.walletService.UpdateWalletLimits(r.Context(), walletID, &req)
+	limits, approvalReq, updateErr := h.walletService.UpdateWalletLimits(r.Context(), walletID, &req)
 	if updateErr != nil {
 		response.Error(w, updateErr)
 		return
 	}
+	if approvalReq != nil {
+		response.Success(w, http.StatusAccepted, approvalReq)
+		return
+	}
 
 	response.OK(w, limits)
 }
@@ -373,6 +380,103 @@ func (h *WalletHandler) ProcessDeposit(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ProcessWithdrawal handles POST /internal/v1/wallets/withdraw (internal endpoint)
+// This endpoint is called by the transaction service to debit withdrawals from wallets.
+func (h *WalletHandler) ProcessWithdrawal(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	req, parseErr := model.ParseInto[models.ProcessWithdrawalRequest](body)
+	if parseErr != nil {
+		response.Error(w, errors.Validation(parseErr.Error()))
+		return
+	}
+
+	if withdrawErr := h.walletService.ProcessWithdrawal(r.Context(), req.WalletID, req.Amount, req.TransactionID); withdrawErr != nil {
+		response.Error(w, withdrawErr)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"success":        true,
+		"wallet_id":      req.WalletID,
+		"amount":         req.Amount,
+		"transaction_id": req.TransactionID,
+	})
+}
+
+// HoldFunds handles POST /internal/v1/wallets/holds (internal endpoint)
+// This endpoint is called by the transaction service to reserve funds before moving them.
+func (h *WalletHandler) HoldFunds(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, errors.BadRequest("failed to read request body"))
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	req, parseErr := model.ParseInto[models.HoldFundsRequest](body)
+	if parseErr != nil {
+		response.Error(w, errors.Validation(parseErr.Error()))
+		return
+	}
+
+	if holdErr := h.walletService.HoldFunds(r.Context(), req.WalletID, req.Amount, req.TransactionID); holdErr != nil {
+		response.Error(w, holdErr)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"success":        true,
+		"wallet_id":      req.WalletID,
+		"amount":         req.Amount,
+		"transaction_id": req.TransactionID,
+	})
+}
+
+// ReleaseHold handles POST /internal/v1/wallets/holds/{transaction_id}/release (internal endpoint)
+func (h *WalletHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	transactionID := r.PathValue("transaction_id")
+	if transactionID == "" {
+		response.Error(w, errors.BadRequest("transaction ID is required"))
+		return
+	}
+
+	if releaseErr := h.walletService.ReleaseHold(r.Context(), transactionID); releaseErr != nil {
+		response.Error(w, releaseErr)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"success":        true,
+		"transaction_id": transactionID,
+	})
+}
+
+// ReverseMovement handles POST /internal/v1/wallets/transactions/{transaction_id}/reverse (internal endpoint)
+// This endpoint is called by the transaction service to compensate a failed transaction.
+func (h *WalletHandler) ReverseMovement(w http.ResponseWriter, r *http.Request) {
+	transactionID := r.PathValue("transaction_id")
+	if transactionID == "" {
+		response.Error(w, errors.BadRequest("transaction ID is required"))
+		return
+	}
+
+	if reverseErr := h.walletService.ReverseMovement(r.Context(), transactionID); reverseErr != nil {
+		response.Error(w, reverseErr)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"success":        true,
+		"transaction_id": transactionID,
+	})
+}
+
 // GetWalletInfo handles GET /internal/v1/wallets/:id/info (internal endpoint)
 // This endpoint returns wallet information including ownership for authorization checks.
 func (h *WalletHandler) GetWalletInfo(w http.ResponseWriter, r *http.Request) {
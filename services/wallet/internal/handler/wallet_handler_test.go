@@ -178,6 +178,22 @@ func (m *mockWalletRepository) ProcessDepositWithinTx(ctx context.Context, walle
 	return errors.NotFound("wallet not found")
 }
 
+func (m *mockWalletRepository) ProcessWithdrawalWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	return nil
+}
+
+func (m *mockWalletRepository) HoldFundsWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	return nil
+}
+
+func (m *mockWalletRepository) ReleaseHoldWithinTx(ctx context.Context, transactionID string) *errors.Error {
+	return nil
+}
+
+func (m *mockWalletRepository) ReverseMovementWithinTx(ctx context.Context, transactionID string) *errors.Error {
+	return nil
+}
+
 func (m *mockWalletRepository) UpdateBalance(ctx context.Context, walletID string, amount int64) *errors.Error {
 	if m.UpdateBalanceFunc != nil {
 		return m.UpdateBalanceFunc(ctx, walletID, amount)
@@ -19,8 +19,9 @@ type Beneficiary struct {
 
 // AddBeneficiaryRequest represents a request to add a new beneficiary.
 type AddBeneficiaryRequest struct {
-	Phone    string `json:"phone" validate:"required,e164"`             // Phone number to add (e.g., "+919876543210")
-	Nickname string `json:"nickname" validate:"required,min=1,max=100"` // Friendly name
+	Phone             string `json:"phone" validate:"required,e164"`             // Phone number to add (e.g., "+919876543210")
+	Nickname          string `json:"nickname" validate:"required,min=1,max=100"` // Friendly name
+	VerificationToken string `json:"verification_token,omitempty"`               // From verifying a beneficiary_add for this phone
 }
 
 // UpdateBeneficiaryRequest represents a request to update a beneficiary's nickname.
@@ -0,0 +1,140 @@
+package models
+
+import "github.com/vnykmshr/nivo/shared/models"
+
+// SplitType determines how an expense is divided between members.
+type SplitType string
+
+const (
+	SplitTypeEqual      SplitType = "equal"      // Divided evenly; leftover paise go to the first members
+	SplitTypePercentage SplitType = "percentage" // Percentages adding up to 100
+	SplitTypeExact      SplitType = "exact"      // Amounts adding up to the expense amount
+)
+
+// SettlementStatus represents the status of a settle-up transfer.
+type SettlementStatus string
+
+const (
+	SettlementStatusPending   SettlementStatus = "pending" // Transfer requested, outcome not yet recorded
+	SettlementStatusCompleted SettlementStatus = "completed"
+	SettlementStatusFailed    SettlementStatus = "failed"
+)
+
+// ExpenseGroup is a group of users sharing expenses.
+type ExpenseGroup struct {
+	ID          string                `json:"id" db:"id"`
+	OwnerUserID string                `json:"owner_user_id" db:"owner_user_id"`
+	Name        string                `json:"name" db:"name"`
+	Currency    models.Currency       `json:"currency" db:"currency"`
+	Members     []*ExpenseGroupMember `json:"members,omitempty"`
+	CreatedAt   models.Timestamp      `json:"created_at" db:"created_at"`
+	UpdatedAt   models.Timestamp      `json:"updated_at" db:"updated_at"`
+}
+
+// ExpenseGroupMember is a user in an expense group, with the wallet used to settle up.
+type ExpenseGroupMember struct {
+	ID            string           `json:"id" db:"id"`
+	GroupID       string           `json:"group_id" db:"group_id"`
+	UserID        string           `json:"user_id" db:"user_id"`
+	WalletID      string           `json:"wallet_id" db:"wallet_id"`
+	DisplayName   string           `json:"display_name" db:"display_name"`               // Beneficiary nickname, or the owner's name
+	BeneficiaryID *string          `json:"beneficiary_id,omitempty" db:"beneficiary_id"` // Nil for the owner
+	CreatedAt     models.Timestamp `json:"created_at" db:"created_at"`
+}
+
+// GroupExpense is an expense paid by one member and shared between members.
+type GroupExpense struct {
+	ID              string           `json:"id" db:"id"`
+	GroupID         string           `json:"group_id" db:"group_id"`
+	PaidByMemberID  string           `json:"paid_by_member_id" db:"paid_by_member_id"`
+	Amount          int64            `json:"amount" db:"amount"`
+	Description     string           `json:"description" db:"description"`
+	SplitType       SplitType        `json:"split_type" db:"split_type"`
+	Shares          []*ExpenseShare  `json:"shares"`
+	CreatedByUserID string           `json:"created_by_user_id" db:"created_by_user_id"`
+	CreatedAt       models.Timestamp `json:"created_at" db:"created_at"`
+}
+
+// ExpenseShare is a member's share of an expense, in paise.
+type ExpenseShare struct {
+	MemberID string `json:"member_id" db:"member_id"`
+	Amount   int64  `json:"amount" db:"amount"`
+}
+
+// GroupSettlement is a transfer between members to settle their balances.
+type GroupSettlement struct {
+	ID            string           `json:"id" db:"id"`
+	GroupID       string           `json:"group_id" db:"group_id"`
+	FromMemberID  string           `json:"from_member_id" db:"from_member_id"`
+	ToMemberID    string           `json:"to_member_id" db:"to_member_id"`
+	FromWalletID  string           `json:"from_wallet_id" db:"from_wallet_id"`
+	ToWalletID    string           `json:"to_wallet_id" db:"to_wallet_id"`
+	Amount        int64            `json:"amount" db:"amount"`
+	Status        SettlementStatus `json:"status" db:"status"`
+	TransactionID *string          `json:"transaction_id,omitempty" db:"transaction_id"`
+	FailureReason *string          `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedAt     models.Timestamp `json:"created_at" db:"created_at"`
+	UpdatedAt     models.Timestamp `json:"updated_at" db:"updated_at"`
+}
+
+// MemberBalance is a member's position in a group, in paise.
+// Net is positive when the member is owed money and negative when they owe.
+type MemberBalance struct {
+	MemberID    string `json:"member_id"`
+	DisplayName string `json:"display_name"`
+	Paid        int64  `json:"paid"`        // Expenses paid for the group
+	Share       int64  `json:"share"`       // Their shares of all expenses
+	SettledOut  int64  `json:"settled_out"` // Completed settlements paid
+	SettledIn   int64  `json:"settled_in"`  // Completed settlements received
+	Net         int64  `json:"net"`         // Paid - Share + SettledOut - SettledIn
+}
+
+// SettleUpTransfer is a transfer suggested to settle a group.
+type SettleUpTransfer struct {
+	FromMemberID string `json:"from_member_id"`
+	FromName     string `json:"from_name"`
+	ToMemberID   string `json:"to_member_id"`
+	ToName       string `json:"to_name"`
+	Amount       int64  `json:"amount"`
+}
+
+// GroupBalances is the balance of every member and the transfers that settle them.
+type GroupBalances struct {
+	GroupID   string              `json:"group_id"`
+	Currency  models.Currency     `json:"currency"`
+	Balances  []*MemberBalance    `json:"balances"`
+	Transfers []*SettleUpTransfer `json:"settle_up"`
+}
+
+// CreateExpenseGroupRequest represents a request to create an expense group.
+type CreateExpenseGroupRequest struct {
+	Name           string   `json:"name" validate:"required,min=1,max=100"`
+	BeneficiaryIDs []string `json:"beneficiary_ids"` // Beneficiaries of the caller to add as members
+}
+
+// AddGroupMemberRequest represents a request to add a beneficiary to an expense group.
+type AddGroupMemberRequest struct {
+	BeneficiaryID string `json:"beneficiary_id" validate:"required"`
+}
+
+// SettleGroupRequest represents a request to settle up in an expense group.
+type SettleGroupRequest struct {
+	VerificationToken string `json:"verification_token,omitempty"` // Required for settle-ups above the high-value threshold
+}
+
+// ExpenseSplit is one member's part of an expense in AddExpenseRequest.
+// Percentage is used by percentage splits and Amount by exact splits.
+type ExpenseSplit struct {
+	MemberID   string  `json:"member_id"`
+	Percentage float64 `json:"percentage,omitempty"`
+	Amount     int64   `json:"amount,omitempty"`
+}
+
+// AddExpenseRequest represents a request to log an expense in a group.
+type AddExpenseRequest struct {
+	PaidByMemberID string         `json:"paid_by_member_id,omitempty"` // Defaults to the caller
+	Amount         int64          `json:"amount" validate:"required,gt=0"`
+	Description    string         `json:"description" validate:"required,min=1,max=200"`
+	SplitType      SplitType      `json:"split_type" validate:"required,oneof=equal percentage exact"`
+	Splits         []ExpenseSplit `json:"splits,omitempty"` // Required for percentage and exact; equal splits default to all members
+}
@@ -0,0 +1,94 @@
+package models
+
+import "github.com/vnykmshr/nivo/shared/models"
+
+// ReconciliationRunStatus represents the status of a reconciliation run.
+type ReconciliationRunStatus string
+
+const (
+	ReconciliationRunRunning   ReconciliationRunStatus = "running"
+	ReconciliationRunCompleted ReconciliationRunStatus = "completed"
+	ReconciliationRunFailed    ReconciliationRunStatus = "failed"
+)
+
+// ReconciliationTrigger records what started a reconciliation run.
+type ReconciliationTrigger string
+
+const (
+	ReconciliationTriggerScheduled ReconciliationTrigger = "scheduled"
+	ReconciliationTriggerManual    ReconciliationTrigger = "manual"
+)
+
+// DiscrepancySeverity ranks a discrepancy by the size of the mismatch.
+type DiscrepancySeverity string
+
+const (
+	DiscrepancySeverityLow      DiscrepancySeverity = "low"
+	DiscrepancySeverityMedium   DiscrepancySeverity = "medium"
+	DiscrepancySeverityHigh     DiscrepancySeverity = "high"
+	DiscrepancySeverityCritical DiscrepancySeverity = "critical" // Also used when the ledger account is missing
+)
+
+// AllDiscrepancySeverities lists every severity, lowest first.
+var AllDiscrepancySeverities = []DiscrepancySeverity{
+	DiscrepancySeverityLow,
+	DiscrepancySeverityMedium,
+	DiscrepancySeverityHigh,
+	DiscrepancySeverityCritical,
+}
+
+// DiscrepancyStatus represents the status of a discrepancy.
+type DiscrepancyStatus string
+
+const (
+	DiscrepancyStatusOpen     DiscrepancyStatus = "open"
+	DiscrepancyStatusResolved DiscrepancyStatus = "resolved"
+)
+
+// ReconciliationRun records a single pass over all wallets.
+type ReconciliationRun struct {
+	ID                 string                  `json:"id" db:"id"`
+	Status             ReconciliationRunStatus `json:"status" db:"status"`
+	Trigger            ReconciliationTrigger   `json:"trigger" db:"trigger"`
+	WalletsChecked     int                     `json:"wallets_checked" db:"wallets_checked"`
+	DiscrepanciesFound int                     `json:"discrepancies_found" db:"discrepancies_found"`
+	ErrorMessage       *string                 `json:"error_message,omitempty" db:"error_message"`
+	StartedAt          models.Timestamp        `json:"started_at" db:"started_at"`
+	CompletedAt        *models.Timestamp       `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// ReconciliationDiscrepancy records a wallet whose balance disagrees with its
+// ledger account or with the net of its settled transactions.
+type ReconciliationDiscrepancy struct {
+	ID                    string              `json:"id" db:"id"`
+	WalletID              string              `json:"wallet_id" db:"wallet_id"`
+	LedgerAccountID       string              `json:"ledger_account_id" db:"ledger_account_id"`
+	FirstRunID            string              `json:"first_run_id" db:"first_run_id"`
+	LastRunID             string              `json:"last_run_id" db:"last_run_id"`
+	WalletBalance         int64               `json:"wallet_balance" db:"wallet_balance"`
+	LedgerBalance         *int64              `json:"ledger_balance" db:"ledger_balance"` // Nil when the ledger account was not found
+	TransactionNet        int64               `json:"transaction_net" db:"transaction_net"`
+	LedgerDifference      int64               `json:"ledger_difference" db:"ledger_difference"`           // Wallet balance minus ledger balance
+	TransactionDifference int64               `json:"transaction_difference" db:"transaction_difference"` // Wallet balance minus transaction net
+	Severity              DiscrepancySeverity `json:"severity" db:"severity"`
+	Status                DiscrepancyStatus   `json:"status" db:"status"`
+	DetectedAt            models.Timestamp    `json:"detected_at" db:"detected_at"`
+	LastSeenAt            models.Timestamp    `json:"last_seen_at" db:"last_seen_at"`
+	ResolvedAt            *models.Timestamp   `json:"resolved_at,omitempty" db:"resolved_at"`
+	ResolvedBy            *string             `json:"resolved_by,omitempty" db:"resolved_by"` // Nil when resolved automatically
+	ResolutionNote        *string             `json:"resolution_note,omitempty" db:"resolution_note"`
+	CreatedAt             models.Timestamp    `json:"created_at" db:"created_at"`
+	UpdatedAt             models.Timestamp    `json:"updated_at" db:"updated_at"`
+}
+
+// DiscrepancyFilter narrows a discrepancy listing.
+type DiscrepancyFilter struct {
+	Status   *DiscrepancyStatus
+	Severity *DiscrepancySeverity
+	WalletID *string
+}
+
+// ResolveDiscrepancyRequest represents a request to manually resolve a discrepancy.
+type ResolveDiscrepancyRequest struct {
+	Note string `json:"note" validate:"required,min:5,max:1000"`
+}
@@ -157,3 +157,27 @@ type ProcessDepositRequest struct {
 	TransactionID string `json:"transaction_id" validate:"required,uuid"`
 	Description   string `json:"description,omitempty"`
 }
+
+// ProcessWithdrawalRequest represents an internal request to debit a withdrawal.
+// This is called by the transaction service to execute approved withdrawals.
+type ProcessWithdrawalRequest struct {
+	WalletID      string `json:"wallet_id" validate:"required,uuid"`
+	Amount        int64  `json:"amount" validate:"required,gt=0"`
+	TransactionID string `json:"transaction_id" validate:"required,uuid"`
+}
+
+// HoldStatus represents the lifecycle of funds held for a transaction.
+type HoldStatus string
+
+const (
+	HoldStatusHeld     HoldStatus = "held"     // Funds are reserved against the available balance
+	HoldStatusCaptured HoldStatus = "captured" // Held funds were debited by the transaction
+	HoldStatusReleased HoldStatus = "released" // Held funds were returned to the available balance
+)
+
+// HoldFundsRequest represents an internal request to hold funds for a pending transaction.
+type HoldFundsRequest struct {
+	WalletID      string `json:"wallet_id" validate:"required,uuid"`
+	Amount        int64  `json:"amount" validate:"required,gt=0"`
+	TransactionID string `json:"transaction_id" validate:"required,uuid"`
+}
@@ -0,0 +1,462 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/database"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// ExpenseGroupRepository handles database operations for expense groups,
+// their expenses and settlements.
+type ExpenseGroupRepository struct {
+	db *sql.DB
+}
+
+// NewExpenseGroupRepository creates a new expense group repository.
+func NewExpenseGroupRepository(db *sql.DB) *ExpenseGroupRepository {
+	return &ExpenseGroupRepository{db: db}
+}
+
+const groupMemberColumns = `id, group_id, user_id, wallet_id, display_name, beneficiary_id, created_at`
+
+const settlementColumns = `id, group_id, from_member_id, to_member_id, from_wallet_id, to_wallet_id,
+	       amount, status, transaction_id, failure_reason, created_at, updated_at`
+
+// CreateGroup creates an expense group with its initial members.
+func (r *ExpenseGroupRepository) CreateGroup(ctx context.Context, group *models.ExpenseGroup, members []*models.ExpenseGroupMember) *errors.Error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO expense_groups (owner_user_id, name, currency)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`, group.OwnerUserID, group.Name, group.Currency).Scan(&group.ID, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to create expense group")
+	}
+
+	for _, member := range members {
+		member.GroupID = group.ID
+		if err := insertMember(ctx, tx, member); err != nil {
+			if database.IsUniqueViolation(err) {
+				return errors.Conflict("a user can only be added to a group once")
+			}
+			return errors.DatabaseWrap(err, "failed to add expense group member")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit expense group")
+	}
+
+	group.Members = members
+	return nil
+}
+
+// rowQuerier is implemented by both *sql.DB and *sql.Tx.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func insertMember(ctx context.Context, q rowQuerier, member *models.ExpenseGroupMember) error {
+	return q.QueryRowContext(ctx, `
+		INSERT INTO expense_group_members (group_id, user_id, wallet_id, display_name, beneficiary_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, member.GroupID, member.UserID, member.WalletID, member.DisplayName, member.BeneficiaryID).Scan(&member.ID, &member.CreatedAt)
+}
+
+// GetGroup retrieves an expense group with its members.
+func (r *ExpenseGroupRepository) GetGroup(ctx context.Context, id string) (*models.ExpenseGroup, *errors.Error) {
+	group := &models.ExpenseGroup{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, owner_user_id, name, currency, created_at, updated_at
+		FROM expense_groups
+		WHERE id = $1
+	`, id).Scan(&group.ID, &group.OwnerUserID, &group.Name, &group.Currency, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundWithID("expense group", id)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get expense group")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+groupMemberColumns+`
+		FROM expense_group_members
+		WHERE group_id = $1
+		ORDER BY created_at, id
+	`, id)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to list expense group members")
+	}
+	defer func() { _ = rows.Close() }()
+
+	group.Members = make([]*models.ExpenseGroupMember, 0)
+	for rows.Next() {
+		member := &models.ExpenseGroupMember{}
+		err := rows.Scan(
+			&member.ID,
+			&member.GroupID,
+			&member.UserID,
+			&member.WalletID,
+			&member.DisplayName,
+			&member.BeneficiaryID,
+			&member.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan expense group member")
+		}
+		group.Members = append(group.Members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating expense group members")
+	}
+
+	return group, nil
+}
+
+// ListGroupsByMember retrieves the groups a user belongs to, newest first,
+// with the total count. Members are not loaded.
+func (r *ExpenseGroupRepository) ListGroupsByMember(ctx context.Context, userID string, limit, offset int) ([]*models.ExpenseGroup, int, *errors.Error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM expense_group_members WHERE user_id = $1
+	`, userID).Scan(&total)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count expense groups")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT g.id, g.owner_user_id, g.name, g.currency, g.created_at, g.updated_at
+		FROM expense_groups g
+		JOIN expense_group_members m ON m.group_id = g.id
+		WHERE m.user_id = $1
+		ORDER BY g.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list expense groups")
+	}
+	defer func() { _ = rows.Close() }()
+
+	groups := make([]*models.ExpenseGroup, 0)
+	for rows.Next() {
+		group := &models.ExpenseGroup{}
+		if err := rows.Scan(&group.ID, &group.OwnerUserID, &group.Name, &group.Currency, &group.CreatedAt, &group.UpdatedAt); err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan expense group")
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "error iterating expense groups")
+	}
+
+	return groups, total, nil
+}
+
+// AddMember adds a member to an expense group.
+func (r *ExpenseGroupRepository) AddMember(ctx context.Context, member *models.ExpenseGroupMember) *errors.Error {
+	if err := insertMember(ctx, r.db, member); err != nil {
+		if database.IsUniqueViolation(err) {
+			return errors.Conflict("this user is already a member of the group")
+		}
+		return errors.DatabaseWrap(err, "failed to add expense group member")
+	}
+	return nil
+}
+
+// CreateExpense records an expense with its shares.
+func (r *ExpenseGroupRepository) CreateExpense(ctx context.Context, expense *models.GroupExpense) *errors.Error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO group_expenses (group_id, paid_by_member_id, amount, description, split_type, created_by_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`,
+		expense.GroupID,
+		expense.PaidByMemberID,
+		expense.Amount,
+		expense.Description,
+		expense.SplitType,
+		expense.CreatedByUserID,
+	).Scan(&expense.ID, &expense.CreatedAt)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to create expense")
+	}
+
+	for _, share := range expense.Shares {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO group_expense_shares (expense_id, member_id, amount)
+			VALUES ($1, $2, $3)
+		`, expense.ID, share.MemberID, share.Amount)
+		if err != nil {
+			return errors.DatabaseWrap(err, "failed to record expense share")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit expense")
+	}
+
+	return nil
+}
+
+// ListExpenses retrieves a group's expenses with their shares, newest first,
+// with the total count.
+func (r *ExpenseGroupRepository) ListExpenses(ctx context.Context, groupID string, limit, offset int) ([]*models.GroupExpense, int, *errors.Error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM group_expenses WHERE group_id = $1`, groupID).Scan(&total); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count expenses")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, group_id, paid_by_member_id, amount, description, split_type, created_by_user_id, created_at
+		FROM group_expenses
+		WHERE group_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`, groupID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list expenses")
+	}
+	defer func() { _ = rows.Close() }()
+
+	expenses := make([]*models.GroupExpense, 0)
+	byID := make(map[string]*models.GroupExpense)
+	ids := make([]string, 0)
+	for rows.Next() {
+		expense := &models.GroupExpense{Shares: make([]*models.ExpenseShare, 0)}
+		err := rows.Scan(
+			&expense.ID,
+			&expense.GroupID,
+			&expense.PaidByMemberID,
+			&expense.Amount,
+			&expense.Description,
+			&expense.SplitType,
+			&expense.CreatedByUserID,
+			&expense.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan expense")
+		}
+		expenses = append(expenses, expense)
+		byID[expense.ID] = expense
+		ids = append(ids, expense.ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "error iterating expenses")
+	}
+
+	if len(ids) == 0 {
+		return expenses, total, nil
+	}
+
+	shareRows, err := r.db.QueryContext(ctx, `
+		SELECT s.expense_id, s.member_id, s.amount
+		FROM group_expense_shares s
+		JOIN expense_group_members m ON m.id = s.member_id
+		WHERE s.expense_id = ANY($1)
+		ORDER BY m.created_at, m.id
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list expense shares")
+	}
+	defer func() { _ = shareRows.Close() }()
+
+	for shareRows.Next() {
+		var expenseID string
+		share := &models.ExpenseShare{}
+		if err := shareRows.Scan(&expenseID, &share.MemberID, &share.Amount); err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan expense share")
+		}
+		byID[expenseID].Shares = append(byID[expenseID].Shares, share)
+	}
+
+	if err = shareRows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "error iterating expense shares")
+	}
+
+	return expenses, total, nil
+}
+
+// GetMemberTotals sums what each member of a group paid, owes and settled, in
+// member order. Only completed settlements are counted; Net is left to the caller.
+func (r *ExpenseGroupRepository) GetMemberTotals(ctx context.Context, groupID string) ([]*models.MemberBalance, *errors.Error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id, m.display_name,
+		       COALESCE((SELECT SUM(e.amount) FROM group_expenses e WHERE e.paid_by_member_id = m.id), 0),
+		       COALESCE((SELECT SUM(s.amount) FROM group_expense_shares s WHERE s.member_id = m.id), 0),
+		       COALESCE((SELECT SUM(gs.amount) FROM group_settlements gs
+		                 WHERE gs.from_member_id = m.id AND gs.status = 'completed'), 0),
+		       COALESCE((SELECT SUM(gs.amount) FROM group_settlements gs
+		                 WHERE gs.to_member_id = m.id AND gs.status = 'completed'), 0)
+		FROM expense_group_members m
+		WHERE m.group_id = $1
+		ORDER BY m.created_at, m.id
+	`, groupID)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to get member totals")
+	}
+	defer func() { _ = rows.Close() }()
+
+	balances := make([]*models.MemberBalance, 0)
+	for rows.Next() {
+		b := &models.MemberBalance{}
+		if err := rows.Scan(&b.MemberID, &b.DisplayName, &b.Paid, &b.Share, &b.SettledOut, &b.SettledIn); err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan member totals")
+		}
+		balances = append(balances, b)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating member totals")
+	}
+
+	return balances, nil
+}
+
+func scanSettlement(row rowScanner) (*models.GroupSettlement, error) {
+	s := &models.GroupSettlement{}
+	err := row.Scan(
+		&s.ID,
+		&s.GroupID,
+		&s.FromMemberID,
+		&s.ToMemberID,
+		&s.FromWalletID,
+		&s.ToWalletID,
+		&s.Amount,
+		&s.Status,
+		&s.TransactionID,
+		&s.FailureReason,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func scanSettlements(rows *sql.Rows) ([]*models.GroupSettlement, *errors.Error) {
+	defer func() { _ = rows.Close() }()
+
+	settlements := make([]*models.GroupSettlement, 0)
+	for rows.Next() {
+		s, err := scanSettlement(rows)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan settlement")
+		}
+		settlements = append(settlements, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating settlements")
+	}
+
+	return settlements, nil
+}
+
+// CreateSettlement records a pending settlement. It returns a conflict if the
+// paying member already has a pending settlement in the group.
+func (r *ExpenseGroupRepository) CreateSettlement(ctx context.Context, settlement *models.GroupSettlement) *errors.Error {
+	query := `
+		INSERT INTO group_settlements (group_id, from_member_id, to_member_id, from_wallet_id, to_wallet_id, amount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (group_id, from_member_id) WHERE status = 'pending' DO NOTHING
+		RETURNING ` + settlementColumns
+
+	created, err := scanSettlement(r.db.QueryRowContext(ctx, query,
+		settlement.GroupID,
+		settlement.FromMemberID,
+		settlement.ToMemberID,
+		settlement.FromWalletID,
+		settlement.ToWalletID,
+		settlement.Amount,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.Conflict("a settlement for this member is already in progress")
+		}
+		return errors.DatabaseWrap(err, "failed to create settlement")
+	}
+
+	*settlement = *created
+	return nil
+}
+
+// ListPendingSettlements retrieves a member's pending settlements in a group.
+func (r *ExpenseGroupRepository) ListPendingSettlements(ctx context.Context, groupID, fromMemberID string) ([]*models.GroupSettlement, *errors.Error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+settlementColumns+`
+		FROM group_settlements
+		WHERE group_id = $1 AND from_member_id = $2 AND status = 'pending'
+		ORDER BY created_at
+	`, groupID, fromMemberID)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to list pending settlements")
+	}
+
+	return scanSettlements(rows)
+}
+
+// CompleteSettlement records the outcome of a pending settlement.
+func (r *ExpenseGroupRepository) CompleteSettlement(ctx context.Context, id string, status models.SettlementStatus, transactionID, failureReason *string) (*models.GroupSettlement, *errors.Error) {
+	query := `
+		UPDATE group_settlements
+		SET status = $2, transaction_id = $3, failure_reason = $4
+		WHERE id = $1 AND status = 'pending'
+		RETURNING ` + settlementColumns
+
+	s, err := scanSettlement(r.db.QueryRowContext(ctx, query, id, status, transactionID, failureReason))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Conflict("settlement is no longer pending")
+		}
+		return nil, errors.DatabaseWrap(err, "failed to complete settlement")
+	}
+
+	return s, nil
+}
+
+// ListSettlements retrieves a group's settlements, newest first, with the total count.
+func (r *ExpenseGroupRepository) ListSettlements(ctx context.Context, groupID string, limit, offset int) ([]*models.GroupSettlement, int, *errors.Error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM group_settlements WHERE group_id = $1`, groupID).Scan(&total); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count settlements")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+settlementColumns+`
+		FROM group_settlements
+		WHERE group_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, groupID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list settlements")
+	}
+
+	settlements, scanErr := scanSettlements(rows)
+	if scanErr != nil {
+		return nil, 0, scanErr
+	}
+
+	return settlements, total, nil
+}
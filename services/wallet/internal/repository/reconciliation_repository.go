@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// ReconciliationRepository handles database operations for reconciliation runs and discrepancies.
+type ReconciliationRepository struct {
+	db *sql.DB
+}
+
+// NewReconciliationRepository creates a new reconciliation repository.
+func NewReconciliationRepository(db *sql.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{db: db}
+}
+
+const discrepancyColumns = `id, wallet_id, ledger_account_id, first_run_id, last_run_id,
+	       wallet_balance, ledger_balance, transaction_net, ledger_difference,
+	       transaction_difference, severity, status, detected_at, last_seen_at,
+	       resolved_at, resolved_by, resolution_note, created_at, updated_at`
+
+// CreateRun records the start of a reconciliation run.
+func (r *ReconciliationRepository) CreateRun(ctx context.Context, run *models.ReconciliationRun) *errors.Error {
+	query := `
+		INSERT INTO reconciliation_runs (status, trigger)
+		VALUES ($1, $2)
+		RETURNING id, started_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, run.Status, run.Trigger).Scan(&run.ID, &run.StartedAt)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to create reconciliation run")
+	}
+
+	return nil
+}
+
+// FinishRun records the outcome of a reconciliation run.
+func (r *ReconciliationRepository) FinishRun(ctx context.Context, run *models.ReconciliationRun) *errors.Error {
+	query := `
+		UPDATE reconciliation_runs
+		SET status = $2, wallets_checked = $3, discrepancies_found = $4,
+		    error_message = $5, completed_at = NOW()
+		WHERE id = $1
+		RETURNING completed_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		run.ID,
+		run.Status,
+		run.WalletsChecked,
+		run.DiscrepanciesFound,
+		run.ErrorMessage,
+	).Scan(&run.CompletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NotFoundWithID("reconciliation run", run.ID)
+		}
+		return errors.DatabaseWrap(err, "failed to finish reconciliation run")
+	}
+
+	return nil
+}
+
+// ListRuns retrieves reconciliation runs, most recent first, with the total count.
+func (r *ReconciliationRepository) ListRuns(ctx context.Context, limit, offset int) ([]*models.ReconciliationRun, int, *errors.Error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM reconciliation_runs`).Scan(&total); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count reconciliation runs")
+	}
+
+	query := `
+		SELECT id, status, trigger, wallets_checked, discrepancies_found,
+		       error_message, started_at, completed_at
+		FROM reconciliation_runs
+		ORDER BY started_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list reconciliation runs")
+	}
+	defer func() { _ = rows.Close() }()
+
+	runs := make([]*models.ReconciliationRun, 0)
+	for rows.Next() {
+		run := &models.ReconciliationRun{}
+
+		err := rows.Scan(
+			&run.ID,
+			&run.Status,
+			&run.Trigger,
+			&run.WalletsChecked,
+			&run.DiscrepanciesFound,
+			&run.ErrorMessage,
+			&run.StartedAt,
+			&run.CompletedAt,
+		)
+		if err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan reconciliation run")
+		}
+
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "error iterating reconciliation runs")
+	}
+
+	return runs, total, nil
+}
+
+// UpsertDiscrepancy records a mismatch for a wallet. If the wallet already has an
+// open discrepancy it is refreshed with the latest figures instead of duplicated.
+func (r *ReconciliationRepository) UpsertDiscrepancy(ctx context.Context, d *models.ReconciliationDiscrepancy) *errors.Error {
+	query := `
+		INSERT INTO reconciliation_discrepancies (
+			wallet_id, ledger_account_id, first_run_id, last_run_id, wallet_balance,
+			ledger_balance, transaction_net, ledger_difference, transaction_difference,
+			severity, status
+		)
+		VALUES ($1, $2, $3, $3, $4, $5, $6, $7, $8, $9, 'open')
+		ON CONFLICT (wallet_id) WHERE status = 'open' DO UPDATE
+		SET last_run_id = EXCLUDED.last_run_id,
+		    ledger_account_id = EXCLUDED.ledger_account_id,
+		    wallet_balance = EXCLUDED.wallet_balance,
+		    ledger_balance = EXCLUDED.ledger_balance,
+		    transaction_net = EXCLUDED.transaction_net,
+		    ledger_difference = EXCLUDED.ledger_difference,
+		    transaction_difference = EXCLUDED.transaction_difference,
+		    severity = EXCLUDED.severity,
+		    last_seen_at = NOW(),
+		    updated_at = NOW()
+		RETURNING ` + discrepancyColumns
+
+	saved, err := scanDiscrepancy(r.db.QueryRowContext(ctx, query,
+		d.WalletID,
+		d.LedgerAccountID,
+		d.LastRunID,
+		d.WalletBalance,
+		d.LedgerBalance,
+		d.TransactionNet,
+		d.LedgerDifference,
+		d.TransactionDifference,
+		d.Severity,
+	))
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to record reconciliation discrepancy")
+	}
+
+	*d = *saved
+	return nil
+}
+
+// ResolveMatched resolves the open discrepancies of wallets that now reconcile.
+// These are system resolutions, so resolved_by is left empty.
+func (r *ReconciliationRepository) ResolveMatched(ctx context.Context, walletIDs []string) (int64, *errors.Error) {
+	if len(walletIDs) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		UPDATE reconciliation_discrepancies
+		SET status = 'resolved', resolved_at = NOW(),
+		    resolution_note = 'balances reconciled', updated_at = NOW()
+		WHERE wallet_id = ANY($1::uuid[]) AND status = 'open'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, pq.Array(walletIDs))
+	if err != nil {
+		return 0, errors.DatabaseWrap(err, "failed to resolve reconciled discrepancies")
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
+// ListDiscrepancies retrieves discrepancies matching the filter, most recently
+// detected first, with the total count.
+func (r *ReconciliationRepository) ListDiscrepancies(ctx context.Context, filter *models.DiscrepancyFilter, limit, offset int) ([]*models.ReconciliationDiscrepancy, int, *errors.Error) {
+	where := ` WHERE 1=1`
+	args := []interface{}{}
+	argPos := 1
+
+	if filter.Status != nil {
+		where += fmt.Sprintf(" AND status = $%d", argPos)
+		args = append(args, *filter.Status)
+		argPos++
+	}
+
+	if filter.Severity != nil {
+		where += fmt.Sprintf(" AND severity = $%d", argPos)
+		args = append(args, *filter.Severity)
+		argPos++
+	}
+
+	if filter.WalletID != nil {
+		where += fmt.Sprintf(" AND wallet_id = $%d", argPos)
+		args = append(args, *filter.WalletID)
+		argPos++
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM reconciliation_discrepancies` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count reconciliation discrepancies")
+	}
+
+	query := `SELECT ` + discrepancyColumns + ` FROM reconciliation_discrepancies` + where +
+		fmt.Sprintf(" ORDER BY detected_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list reconciliation discrepancies")
+	}
+	defer func() { _ = rows.Close() }()
+
+	discrepancies := make([]*models.ReconciliationDiscrepancy, 0)
+	for rows.Next() {
+		d, err := scanDiscrepancy(rows)
+		if err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan reconciliation discrepancy")
+		}
+		discrepancies = append(discrepancies, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "error iterating reconciliation discrepancies")
+	}
+
+	return discrepancies, total, nil
+}
+
+// GetDiscrepancy retrieves a discrepancy by ID.
+func (r *ReconciliationRepository) GetDiscrepancy(ctx context.Context, id string) (*models.ReconciliationDiscrepancy, *errors.Error) {
+	query := `SELECT ` + discrepancyColumns + ` FROM reconciliation_discrepancies WHERE id = $1`
+
+	d, err := scanDiscrepancy(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundWithID("reconciliation discrepancy", id)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get reconciliation discrepancy")
+	}
+
+	return d, nil
+}
+
+// ResolveDiscrepancy marks an open discrepancy as resolved by an operator.
+func (r *ReconciliationRepository) ResolveDiscrepancy(ctx context.Context, id, resolvedBy, note string) *errors.Error {
+	query := `
+		UPDATE reconciliation_discrepancies
+		SET status = 'resolved', resolved_at = NOW(), resolved_by = $2,
+		    resolution_note = $3, updated_at = NOW()
+		WHERE id = $1 AND status = 'open'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, resolvedBy, note)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to resolve reconciliation discrepancy")
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.BadRequest("reconciliation discrepancy not found or already resolved")
+	}
+
+	return nil
+}
+
+// CountOpenBySeverity returns the number of open discrepancies per severity.
+// Severities without open discrepancies are omitted.
+func (r *ReconciliationRepository) CountOpenBySeverity(ctx context.Context) (map[models.DiscrepancySeverity]int, *errors.Error) {
+	query := `
+		SELECT severity, COUNT(*)
+		FROM reconciliation_discrepancies
+		WHERE status = 'open'
+		GROUP BY severity
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to count open discrepancies")
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[models.DiscrepancySeverity]int)
+	for rows.Next() {
+		var severity models.DiscrepancySeverity
+		var count int
+		if err := rows.Scan(&severity, &count); err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan discrepancy count")
+		}
+		counts[severity] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating discrepancy counts")
+	}
+
+	return counts, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDiscrepancy(row rowScanner) (*models.ReconciliationDiscrepancy, error) {
+	d := &models.ReconciliationDiscrepancy{}
+
+	err := row.Scan(
+		&d.ID,
+		&d.WalletID,
+		&d.LedgerAccountID,
+		&d.FirstRunID,
+		&d.LastRunID,
+		&d.WalletBalance,
+		&d.LedgerBalance,
+		&d.TransactionNet,
+		&d.LedgerDifference,
+		&d.TransactionDifference,
+		&d.Severity,
+		&d.Status,
+		&d.DetectedAt,
+		&d.LastSeenAt,
+		&d.ResolvedAt,
+		&d.ResolvedBy,
+		&d.ResolutionNote,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
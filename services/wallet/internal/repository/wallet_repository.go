@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/vnykmshr/nivo/services/wallet/internal/models"
 	"github.com/vnykmshr/nivo/shared/errors"
@@ -265,6 +266,51 @@ func (r *WalletRepository) GetBalance(ctx context.Context, id string) (*models.W
 	return balance, nil
 }
 
+// ListForReconciliation retrieves a page of wallets ordered by ID, starting after
+// afterID (empty for the first page). Only the fields needed to reconcile
+// balances are populated.
+func (r *WalletRepository) ListForReconciliation(ctx context.Context, afterID string, limit int) ([]*models.Wallet, *errors.Error) {
+	query := `
+		SELECT id, user_id, currency, balance, available_balance, status, ledger_account_id
+		FROM wallets
+		WHERE ($1 = '' OR id > $1::uuid)
+		ORDER BY id
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, errors.DatabaseWrap(err, "failed to list wallets for reconciliation")
+	}
+	defer func() { _ = rows.Close() }()
+
+	wallets := make([]*models.Wallet, 0, limit)
+	for rows.Next() {
+		wallet := &models.Wallet{}
+
+		err := rows.Scan(
+			&wallet.ID,
+			&wallet.UserID,
+			&wallet.Currency,
+			&wallet.Balance,
+			&wallet.AvailableBalance,
+			&wallet.Status,
+			&wallet.LedgerAccountID,
+		)
+		if err != nil {
+			return nil, errors.DatabaseWrap(err, "failed to scan wallet")
+		}
+
+		wallets = append(wallets, wallet)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.DatabaseWrap(err, "error iterating wallets")
+	}
+
+	return wallets, nil
+}
+
 // isUniqueViolation checks if the error is a unique constraint violation.
 func isUniqueViolation(err error) bool {
 	// PostgreSQL unique violation error code is 23505
@@ -465,25 +511,29 @@ func (r *WalletRepository) ProcessTransferWithinTx(ctx context.Context, sourceWa
 		return errors.BadRequest(fmt.Sprintf("currency mismatch: source is %s, destination is %s", sourceCurrency, destCurrency))
 	}
 
-	// 5. Check if source has sufficient balance
-	if sourceBalance < amount {
-		shortfall := amount - sourceBalance
-		return errors.BadRequest(fmt.Sprintf("insufficient balance (short by: ₹%.2f)", float64(shortfall)/100))
+	// 5. Capture the hold placed for this transaction, if any. Held funds were
+	// checked against the available balance and limits when the hold was placed.
+	captured, holdErr := captureHoldWithinTx(ctx, tx, transactionID, sourceWalletID, amount)
+	if holdErr != nil {
+		return holdErr
 	}
 
-	// 6. Check and reserve limits
-	if limitErr := r.CheckAndReserveLimitWithinTx(ctx, tx, sourceWalletID, amount); limitErr != nil {
-		return limitErr
+	if !captured {
+		// 5a. Check if source has sufficient balance
+		if sourceBalance < amount {
+			shortfall := amount - sourceBalance
+			return errors.BadRequest(fmt.Sprintf("insufficient balance (short by: ₹%.2f)", float64(shortfall)/100))
+		}
+
+		// 6. Check and reserve limits
+		if limitErr := r.CheckAndReserveLimitWithinTx(ctx, tx, sourceWalletID, amount); limitErr != nil {
+			return limitErr
+		}
 	}
 
-	// 7. Update source wallet balance (debit)
-	_, err = tx.ExecContext(ctx, `
-		UPDATE wallets
-		SET balance = balance - $1,
-		    available_balance = available_balance - $1,
-		    updated_at = NOW()
-		WHERE id = $2
-	`, amount, sourceWalletID)
+	// 7. Update source wallet balance (debit). A captured hold already took
+	// the amount out of the available balance.
+	_, err = tx.ExecContext(ctx, debitWalletQuery(captured), amount, sourceWalletID)
 
 	if err != nil {
 		return errors.DatabaseWrap(err, "failed to debit source wallet")
@@ -757,3 +807,550 @@ func (r *WalletRepository) ProcessDepositWithinTx(ctx context.Context, walletID
 	committed = true
 	return nil
 }
+
+// debitWalletQuery returns the statement that debits a wallet by $1. When the
+// funds were held, only the balance moves; available_balance was reduced by the hold.
+func debitWalletQuery(held bool) string {
+	if held {
+		return `
+			UPDATE wallets
+			SET balance = balance - $1,
+			    updated_at = NOW()
+			WHERE id = $2
+		`
+	}
+	return `
+		UPDATE wallets
+		SET balance = balance - $1,
+		    available_balance = available_balance - $1,
+		    updated_at = NOW()
+		WHERE id = $2
+	`
+}
+
+// captureHoldWithinTx marks the hold for a transaction as captured.
+// Returns false when the transaction has no hold.
+func captureHoldWithinTx(ctx context.Context, tx *sql.Tx, transactionID, walletID string, amount int64) (bool, *errors.Error) {
+	var holdWalletID, status string
+	var holdAmount int64
+	err := tx.QueryRowContext(ctx, `
+		SELECT wallet_id, amount, status
+		FROM wallet_holds
+		WHERE transaction_id = $1
+		FOR UPDATE
+	`, transactionID).Scan(&holdWalletID, &holdAmount, &status)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.DatabaseWrap(err, "failed to get hold")
+	}
+
+	if status != string(models.HoldStatusHeld) {
+		return false, errors.Conflict(fmt.Sprintf("hold for transaction is already %s", status))
+	}
+	if holdWalletID != walletID || holdAmount != amount {
+		return false, errors.BadRequest("hold does not match the wallet and amount being debited")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE wallet_holds SET status = $1 WHERE transaction_id = $2
+	`, models.HoldStatusCaptured, transactionID)
+	if err != nil {
+		return false, errors.DatabaseWrap(err, "failed to capture hold")
+	}
+
+	return true, nil
+}
+
+// releaseLimitWithinTx gives back limit headroom reserved for an amount that was not spent.
+func releaseLimitWithinTx(ctx context.Context, tx *sql.Tx, walletID string, amount int64) *errors.Error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE wallet_limits
+		SET daily_spent = GREATEST(daily_spent - $1, 0),
+		    monthly_spent = GREATEST(monthly_spent - $1, 0),
+		    updated_at = NOW()
+		WHERE wallet_id = $2
+	`, amount, walletID)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to release wallet limit")
+	}
+	return nil
+}
+
+// HoldFundsWithinTx holds funds in a wallet for a pending transaction: the amount
+// is taken out of the available balance and reserved against spending limits.
+// Idempotent on transactionID; a hold that was already released cannot be placed again.
+func (r *WalletRepository) HoldFundsWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// 1. Idempotency check
+	var existingStatus string
+	err = tx.QueryRowContext(ctx, `
+		SELECT status FROM wallet_holds WHERE transaction_id = $1
+	`, transactionID).Scan(&existingStatus)
+
+	if err == nil {
+		if existingStatus == string(models.HoldStatusReleased) {
+			return errors.Conflict("hold for transaction was already released")
+		}
+		return nil
+	} else if err != sql.ErrNoRows {
+		return errors.DatabaseWrap(err, "failed to check idempotency")
+	}
+
+	// 2. Lock wallet and validate it can cover the hold
+	var walletStatus string
+	var availableBalance int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, available_balance
+		FROM wallets
+		WHERE id = $1
+		FOR UPDATE
+	`, walletID).Scan(&walletStatus, &availableBalance)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NotFoundWithID("wallet", walletID)
+		}
+		return errors.DatabaseWrap(err, "failed to lock wallet")
+	}
+
+	if walletStatus != string(models.WalletStatusActive) {
+		return errors.BadRequest("wallet is not active")
+	}
+
+	if availableBalance < amount {
+		shortfall := amount - availableBalance
+		return errors.BadRequest(fmt.Sprintf("insufficient balance (short by: ₹%.2f)", float64(shortfall)/100))
+	}
+
+	// 3. Check and reserve limits
+	if limitErr := r.CheckAndReserveLimitWithinTx(ctx, tx, walletID, amount); limitErr != nil {
+		return limitErr
+	}
+
+	// 4. Take the amount out of the available balance
+	_, err = tx.ExecContext(ctx, `
+		UPDATE wallets
+		SET available_balance = available_balance - $1,
+		    updated_at = NOW()
+		WHERE id = $2
+	`, amount, walletID)
+
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to hold funds")
+	}
+
+	// 5. Record the hold
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO wallet_holds (transaction_id, wallet_id, amount, status)
+		VALUES ($1, $2, $3, $4)
+	`, transactionID, walletID, amount, models.HoldStatusHeld)
+
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to record hold")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit hold transaction")
+	}
+
+	committed = true
+	return nil
+}
+
+// ReleaseHoldWithinTx releases the hold for a transaction, returning the funds to the
+// available balance and the limit headroom to the wallet. Releasing a transaction
+// without a hold, or one already released or captured, is a no-op: captured funds
+// have moved and are restored by ReverseMovementWithinTx.
+func (r *WalletRepository) ReleaseHoldWithinTx(ctx context.Context, transactionID string) *errors.Error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var walletID, status string
+	var amount int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT wallet_id, amount, status
+		FROM wallet_holds
+		WHERE transaction_id = $1
+		FOR UPDATE
+	`, transactionID).Scan(&walletID, &amount, &status)
+
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to get hold")
+	}
+
+	if models.HoldStatus(status) != models.HoldStatusHeld {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE wallets
+		SET available_balance = available_balance + $1,
+		    updated_at = NOW()
+		WHERE id = $2
+	`, amount, walletID)
+
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to release held funds")
+	}
+
+	if limitErr := releaseLimitWithinTx(ctx, tx, walletID, amount); limitErr != nil {
+		return limitErr
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE wallet_holds SET status = $1 WHERE transaction_id = $2
+	`, models.HoldStatusReleased, transactionID)
+
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to release hold")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit hold release")
+	}
+
+	committed = true
+	return nil
+}
+
+// ProcessWithdrawalWithinTx debits a withdrawal from a wallet atomically with idempotency.
+// A hold placed for the transaction is captured; without one, the available balance
+// and limits are checked and reserved here.
+func (r *WalletRepository) ProcessWithdrawalWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// 1. Idempotency check - has this withdrawal already been processed?
+	var existingTxID string
+	err = tx.QueryRowContext(ctx, `
+		SELECT transaction_id
+		FROM processed_withdrawals
+		WHERE transaction_id = $1
+	`, transactionID).Scan(&existingTxID)
+
+	if err == nil {
+		return nil
+	} else if err != sql.ErrNoRows {
+		return errors.DatabaseWrap(err, "failed to check idempotency")
+	}
+
+	// 2. Lock wallet and validate it's active
+	var walletStatus string
+	var availableBalance int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, available_balance
+		FROM wallets
+		WHERE id = $1
+		FOR UPDATE
+	`, walletID).Scan(&walletStatus, &availableBalance)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NotFoundWithID("wallet", walletID)
+		}
+		return errors.DatabaseWrap(err, "failed to lock wallet")
+	}
+
+	if walletStatus != string(models.WalletStatusActive) {
+		return errors.BadRequest("wallet is not active")
+	}
+
+	// 3. Capture the hold, or check balance and limits directly
+	captured, holdErr := captureHoldWithinTx(ctx, tx, transactionID, walletID, amount)
+	if holdErr != nil {
+		return holdErr
+	}
+
+	if !captured {
+		if availableBalance < amount {
+			shortfall := amount - availableBalance
+			return errors.BadRequest(fmt.Sprintf("insufficient balance (short by: ₹%.2f)", float64(shortfall)/100))
+		}
+
+		if limitErr := r.CheckAndReserveLimitWithinTx(ctx, tx, walletID, amount); limitErr != nil {
+			return limitErr
+		}
+	}
+
+	// 4. Update wallet balance (debit)
+	_, err = tx.ExecContext(ctx, debitWalletQuery(captured), amount, walletID)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to debit wallet")
+	}
+
+	// 5. Record this withdrawal as processed for idempotency
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO processed_withdrawals (transaction_id, wallet_id, amount)
+		VALUES ($1, $2, $3)
+	`, transactionID, walletID, amount)
+
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to record processed withdrawal")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit withdrawal transaction")
+	}
+
+	committed = true
+	return nil
+}
+
+// ReverseMovementWithinTx undoes the balance movement recorded for a transaction
+// (transfer, deposit or withdrawal). It is the compensating action of a failed
+// transaction saga: idempotent, and a no-op when nothing was moved. Reversing a
+// transfer or deposit fails if the credited wallet no longer has the funds available.
+func (r *WalletRepository) ReverseMovementWithinTx(ctx context.Context, transactionID string) *errors.Error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to begin transaction")
+	}
+
+	var committed bool
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var reverseErr *errors.Error
+	var found bool
+
+	if found, reverseErr = reverseTransferWithinTx(ctx, tx, transactionID); reverseErr != nil {
+		return reverseErr
+	}
+	if !found {
+		if found, reverseErr = reverseDepositWithinTx(ctx, tx, transactionID); reverseErr != nil {
+			return reverseErr
+		}
+	}
+	if !found {
+		if _, reverseErr = reverseWithdrawalWithinTx(ctx, tx, transactionID); reverseErr != nil {
+			return reverseErr
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.DatabaseWrap(err, "failed to commit reversal transaction")
+	}
+
+	committed = true
+	return nil
+}
+
+func reverseTransferWithinTx(ctx context.Context, tx *sql.Tx, transactionID string) (bool, *errors.Error) {
+	var sourceWalletID, destWalletID string
+	var amount int64
+	var reversedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, `
+		SELECT source_wallet_id, destination_wallet_id, amount, reversed_at
+		FROM processed_transfers
+		WHERE transaction_id = $1
+		FOR UPDATE
+	`, transactionID).Scan(&sourceWalletID, &destWalletID, &amount, &reversedAt)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.DatabaseWrap(err, "failed to get processed transfer")
+	}
+	if reversedAt.Valid {
+		return true, nil
+	}
+
+	// Lock both wallets in deterministic order to prevent deadlocks
+	if lockErr := lockWalletsWithinTx(ctx, tx, sourceWalletID, destWalletID); lockErr != nil {
+		return true, lockErr
+	}
+
+	if debitErr := debitAvailableWithinTx(ctx, tx, destWalletID, amount); debitErr != nil {
+		return true, debitErr
+	}
+	if creditErr := creditWalletWithinTx(ctx, tx, sourceWalletID, amount); creditErr != nil {
+		return true, creditErr
+	}
+	if limitErr := releaseLimitWithinTx(ctx, tx, sourceWalletID, amount); limitErr != nil {
+		return true, limitErr
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE processed_transfers SET reversed_at = NOW() WHERE transaction_id = $1
+	`, transactionID)
+	if err != nil {
+		return true, errors.DatabaseWrap(err, "failed to mark transfer reversed")
+	}
+
+	return true, nil
+}
+
+func reverseDepositWithinTx(ctx context.Context, tx *sql.Tx, transactionID string) (bool, *errors.Error) {
+	var walletID string
+	var amount int64
+	var reversedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, `
+		SELECT wallet_id, amount, reversed_at
+		FROM processed_deposits
+		WHERE transaction_id = $1
+		FOR UPDATE
+	`, transactionID).Scan(&walletID, &amount, &reversedAt)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.DatabaseWrap(err, "failed to get processed deposit")
+	}
+	if reversedAt.Valid {
+		return true, nil
+	}
+
+	if lockErr := lockWalletsWithinTx(ctx, tx, walletID); lockErr != nil {
+		return true, lockErr
+	}
+	if debitErr := debitAvailableWithinTx(ctx, tx, walletID, amount); debitErr != nil {
+		return true, debitErr
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE processed_deposits SET reversed_at = NOW() WHERE transaction_id = $1
+	`, transactionID)
+	if err != nil {
+		return true, errors.DatabaseWrap(err, "failed to mark deposit reversed")
+	}
+
+	return true, nil
+}
+
+func reverseWithdrawalWithinTx(ctx context.Context, tx *sql.Tx, transactionID string) (bool, *errors.Error) {
+	var walletID string
+	var amount int64
+	var reversedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, `
+		SELECT wallet_id, amount, reversed_at
+		FROM processed_withdrawals
+		WHERE transaction_id = $1
+		FOR UPDATE
+	`, transactionID).Scan(&walletID, &amount, &reversedAt)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.DatabaseWrap(err, "failed to get processed withdrawal")
+	}
+	if reversedAt.Valid {
+		return true, nil
+	}
+
+	if lockErr := lockWalletsWithinTx(ctx, tx, walletID); lockErr != nil {
+		return true, lockErr
+	}
+	if creditErr := creditWalletWithinTx(ctx, tx, walletID, amount); creditErr != nil {
+		return true, creditErr
+	}
+	if limitErr := releaseLimitWithinTx(ctx, tx, walletID, amount); limitErr != nil {
+		return true, limitErr
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE processed_withdrawals SET reversed_at = NOW() WHERE transaction_id = $1
+	`, transactionID)
+	if err != nil {
+		return true, errors.DatabaseWrap(err, "failed to mark withdrawal reversed")
+	}
+
+	return true, nil
+}
+
+// lockWalletsWithinTx locks wallets in lexicographic ID order to prevent deadlocks.
+func lockWalletsWithinTx(ctx context.Context, tx *sql.Tx, walletIDs ...string) *errors.Error {
+	ids := append([]string(nil), walletIDs...)
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		var lockedID string
+		err := tx.QueryRowContext(ctx, `
+			SELECT id FROM wallets WHERE id = $1 FOR UPDATE
+		`, id).Scan(&lockedID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.NotFoundWithID("wallet", id)
+			}
+			return errors.DatabaseWrap(err, "failed to lock wallet")
+		}
+	}
+
+	return nil
+}
+
+// debitAvailableWithinTx debits a wallet, failing if the amount is no longer available.
+func debitAvailableWithinTx(ctx context.Context, tx *sql.Tx, walletID string, amount int64) *errors.Error {
+	var id string
+	err := tx.QueryRowContext(ctx, `
+		UPDATE wallets
+		SET balance = balance - $1,
+		    available_balance = available_balance - $1,
+		    updated_at = NOW()
+		WHERE id = $2 AND available_balance >= $1
+		RETURNING id
+	`, amount, walletID).Scan(&id)
+
+	if err == sql.ErrNoRows {
+		return errors.BadRequest("insufficient available balance to reverse the movement")
+	}
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to debit wallet")
+	}
+	return nil
+}
+
+// creditWalletWithinTx credits a wallet's balance and available balance.
+func creditWalletWithinTx(ctx context.Context, tx *sql.Tx, walletID string, amount int64) *errors.Error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE wallets
+		SET balance = balance + $1,
+		    available_balance = available_balance + $1,
+		    updated_at = NOW()
+		WHERE id = $2
+	`, amount, walletID)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to credit wallet")
+	}
+	return nil
+}
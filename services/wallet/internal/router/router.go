@@ -4,12 +4,15 @@ import (
 	"net/http"
 
 	"github.com/vnykmshr/nivo/services/wallet/internal/handler"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/audit"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/metrics"
 	"github.com/vnykmshr/nivo/shared/middleware"
 )
 
 // SetupRoutes configures all routes for the wallet service using Go 1.22+ stdlib router.
-func SetupRoutes(walletHandler *handler.WalletHandler, beneficiaryHandler *handler.BeneficiaryHandler, upiHandler *handler.UPIDepositHandler, cardHandler *handler.VirtualCardHandler, jwtSecret, internalSecret string) http.Handler {
+func SetupRoutes(walletHandler *handler.WalletHandler, beneficiaryHandler *handler.BeneficiaryHandler, upiHandler *handler.UPIDepositHandler, cardHandler *handler.VirtualCardHandler, reconHandler *handler.ReconciliationHandler, groupHandler *handler.ExpenseGroupHandler, approvalHandler *approval.Handler, metricsCollector *metrics.Collector, idempotencyStore middleware.IdempotencyStore, jwtKeys sharedjwt.KeySource) http.Handler {
 	mux := http.NewServeMux()
 
 	// Health check endpoint (public)
@@ -24,11 +27,14 @@ func SetupRoutes(walletHandler *handler.WalletHandler, beneficiaryHandler *handl
 
 	// Setup auth middleware
 	authConfig := middleware.AuthConfig{
-		JWTSecret: jwtSecret,
+		Keys:      jwtKeys,
 		SkipPaths: []string{"/health"},
 	}
 	authMiddleware := middleware.Auth(authConfig)
 
+	// Idempotency keys make deposits and card creation safe to retry (runs after auth: keys are per user)
+	idempotent := middleware.Idempotency(middleware.DefaultIdempotencyConfig(idempotencyStore))
+
 	// Permission middleware
 	createWalletPerm := middleware.RequirePermission("wallet:wallet:create")
 	readWalletPerm := middleware.RequirePermission("wallet:wallet:read")
@@ -47,7 +53,7 @@ func SetupRoutes(walletHandler *handler.WalletHandler, beneficiaryHandler *handl
 	mux.Handle("GET /api/v1/wallets/{id}/limits", authMiddleware(readWalletPerm(http.HandlerFunc(walletHandler.GetWalletLimits))))
 	mux.Handle("PUT /api/v1/wallets/{id}/limits", authMiddleware(readWalletPerm(http.HandlerFunc(walletHandler.UpdateWalletLimits))))
 
-	// Wallet status management (admin/support operations)
+	// Wallet status management (admin/support operations; unfreeze and close are submitted for approval)
 	mux.Handle("POST /api/v1/wallets/{id}/activate", authMiddleware(manageWalletPerm(http.HandlerFunc(walletHandler.ActivateWallet))))
 	mux.Handle("POST /api/v1/wallets/{id}/freeze", authMiddleware(manageWalletPerm(http.HandlerFunc(walletHandler.FreezeWallet))))
 	mux.Handle("POST /api/v1/wallets/{id}/unfreeze", authMiddleware(manageWalletPerm(http.HandlerFunc(walletHandler.UnfreezeWallet))))
@@ -64,25 +70,36 @@ func SetupRoutes(walletHandler *handler.WalletHandler, beneficiaryHandler *handl
 	// ========================================================================
 
 	// UPI deposit operations
-	mux.Handle("POST /api/v1/wallets/{id}/deposit/upi", authMiddleware(readWalletPerm(http.HandlerFunc(upiHandler.InitiateDeposit))))
+	mux.Handle("POST /api/v1/wallets/{id}/deposit/upi", authMiddleware(readWalletPerm(idempotent(http.HandlerFunc(upiHandler.InitiateDeposit)))))
 	mux.Handle("GET /api/v1/wallets/{id}/upi", authMiddleware(readWalletPerm(http.HandlerFunc(upiHandler.GetWalletUPIDetails))))
 	mux.Handle("GET /api/v1/deposits/upi", authMiddleware(readWalletPerm(http.HandlerFunc(upiHandler.ListDeposits))))
 	mux.Handle("GET /api/v1/deposits/upi/{id}", authMiddleware(readWalletPerm(http.HandlerFunc(upiHandler.GetDeposit))))
 
 	// ========================================================================
-	// Internal Endpoints (service-to-service with shared secret auth)
+	// Internal Endpoints (service-to-service with service token auth)
 	// ========================================================================
 
+	serviceAuth := middleware.NewServiceAuth(jwtKeys, sharedjwt.ServiceWallet)
+
 	// Process wallet transfer (called by transaction service)
 	mux.HandleFunc("POST /internal/v1/wallets/transfer",
-		middleware.InternalAuthFunc(internalSecret, walletHandler.ProcessTransfer))
+		serviceAuth.Allow(walletHandler.ProcessTransfer, sharedjwt.ServiceTransaction))
 	mux.HandleFunc("POST /internal/v1/wallets/deposit",
-		middleware.InternalAuthFunc(internalSecret, walletHandler.ProcessDeposit))
+		serviceAuth.Allow(walletHandler.ProcessDeposit, sharedjwt.ServiceTransaction))
+	mux.HandleFunc("POST /internal/v1/wallets/withdraw",
+		serviceAuth.Allow(walletHandler.ProcessWithdrawal, sharedjwt.ServiceTransaction))
+	// Fund holds and compensation (called by the transaction saga)
+	mux.HandleFunc("POST /internal/v1/wallets/holds",
+		serviceAuth.Allow(walletHandler.HoldFunds, sharedjwt.ServiceTransaction))
+	mux.HandleFunc("POST /internal/v1/wallets/holds/{transaction_id}/release",
+		serviceAuth.Allow(walletHandler.ReleaseHold, sharedjwt.ServiceTransaction))
+	mux.HandleFunc("POST /internal/v1/wallets/transactions/{transaction_id}/reverse",
+		serviceAuth.Allow(walletHandler.ReverseMovement, sharedjwt.ServiceTransaction))
 	mux.HandleFunc("GET /internal/v1/wallets/{id}/info",
-		middleware.InternalAuthFunc(internalSecret, walletHandler.GetWalletInfo))
+		serviceAuth.Allow(walletHandler.GetWalletInfo, sharedjwt.ServiceTransaction))
 	// Create wallet (called by identity service during user registration)
 	mux.HandleFunc("POST /internal/v1/wallets",
-		middleware.InternalAuthFunc(internalSecret, walletHandler.CreateWalletInternal))
+		serviceAuth.Allow(walletHandler.CreateWalletInternal, sharedjwt.ServiceIdentity))
 
 	// ========================================================================
 	// Beneficiary Management Endpoints
@@ -106,6 +123,32 @@ func SetupRoutes(walletHandler *handler.WalletHandler, beneficiaryHandler *handl
 	mux.Handle("DELETE /api/v1/beneficiaries/{id}",
 		beneficiaryRateLimit(authMiddleware(manageBeneficiaryPerm(http.HandlerFunc(beneficiaryHandler.DeleteBeneficiary)))))
 
+	// ========================================================================
+	// Expense Group (Split Bill) Endpoints
+	// ========================================================================
+
+	// Groups are built from beneficiaries, so they share the beneficiary permission
+	mux.Handle("POST /api/v1/expense-groups",
+		beneficiaryRateLimit(authMiddleware(manageBeneficiaryPerm(http.HandlerFunc(groupHandler.CreateGroup)))))
+	mux.Handle("GET /api/v1/expense-groups",
+		authMiddleware(manageBeneficiaryPerm(http.HandlerFunc(groupHandler.ListGroups))))
+	mux.Handle("GET /api/v1/expense-groups/{id}",
+		authMiddleware(manageBeneficiaryPerm(http.HandlerFunc(groupHandler.GetGroup))))
+	mux.Handle("POST /api/v1/expense-groups/{id}/members",
+		beneficiaryRateLimit(authMiddleware(manageBeneficiaryPerm(http.HandlerFunc(groupHandler.AddMember)))))
+	mux.Handle("POST /api/v1/expense-groups/{id}/expenses",
+		authMiddleware(manageBeneficiaryPerm(http.HandlerFunc(groupHandler.AddExpense))))
+	mux.Handle("GET /api/v1/expense-groups/{id}/expenses",
+		authMiddleware(manageBeneficiaryPerm(http.HandlerFunc(groupHandler.ListExpenses))))
+	mux.Handle("GET /api/v1/expense-groups/{id}/balances",
+		authMiddleware(manageBeneficiaryPerm(http.HandlerFunc(groupHandler.GetBalances))))
+	mux.Handle("GET /api/v1/expense-groups/{id}/settlements",
+		authMiddleware(manageBeneficiaryPerm(http.HandlerFunc(groupHandler.ListSettlements))))
+
+	// Settling up moves money, so it is rate limited and idempotent like other money movement
+	mux.Handle("POST /api/v1/expense-groups/{id}/settle",
+		beneficiaryRateLimit(authMiddleware(manageBeneficiaryPerm(idempotent(http.HandlerFunc(groupHandler.Settle))))))
+
 	// ========================================================================
 	// Virtual Card Management Endpoints
 	// ========================================================================
@@ -115,7 +158,7 @@ func SetupRoutes(walletHandler *handler.WalletHandler, beneficiaryHandler *handl
 
 	// Card CRUD operations (with rate limiting)
 	mux.Handle("POST /api/v1/wallets/{walletId}/cards",
-		beneficiaryRateLimit(authMiddleware(manageCardPerm(http.HandlerFunc(cardHandler.CreateCard)))))
+		beneficiaryRateLimit(authMiddleware(manageCardPerm(idempotent(http.HandlerFunc(cardHandler.CreateCard))))))
 	mux.Handle("GET /api/v1/wallets/{walletId}/cards",
 		authMiddleware(manageCardPerm(http.HandlerFunc(cardHandler.ListCards))))
 	mux.Handle("GET /api/v1/cards/{id}",
@@ -137,10 +180,46 @@ func SetupRoutes(walletHandler *handler.WalletHandler, beneficiaryHandler *handl
 	mux.Handle("GET /api/v1/cards/{id}/reveal",
 		beneficiaryRateLimit(authMiddleware(manageCardPerm(http.HandlerFunc(cardHandler.RevealCardDetails)))))
 
+	// ========================================================================
+	// Reconciliation Endpoints (admin only)
+	// ========================================================================
+
+	readReconPerm := middleware.RequirePermission("wallet:reconciliation:read")
+	manageReconPerm := middleware.RequirePermission("wallet:reconciliation:manage")
+
+	mux.Handle("GET /api/v1/admin/reconciliation/discrepancies",
+		authMiddleware(readReconPerm(http.HandlerFunc(reconHandler.ListDiscrepancies))))
+	mux.Handle("GET /api/v1/admin/reconciliation/discrepancies/{id}",
+		authMiddleware(readReconPerm(http.HandlerFunc(reconHandler.GetDiscrepancy))))
+	mux.Handle("POST /api/v1/admin/reconciliation/discrepancies/{id}/resolve",
+		authMiddleware(manageReconPerm(http.HandlerFunc(reconHandler.ResolveDiscrepancy))))
+	mux.Handle("GET /api/v1/admin/reconciliation/runs",
+		authMiddleware(readReconPerm(http.HandlerFunc(reconHandler.ListRuns))))
+	mux.Handle("POST /api/v1/admin/reconciliation/runs",
+		authMiddleware(manageReconPerm(http.HandlerFunc(reconHandler.TriggerRun))))
+
+	// ========================================================================
+	// Approval Endpoints (unfreezes and closures need a second admin)
+	// ========================================================================
+
+	// Each approval also requires the permission of its operation
+	approvalPerm := middleware.RequireAnyPermission("wallet:wallet:unfreeze", "wallet:wallet:close", "wallet:wallet:limits_increase")
+
+	mux.Handle("GET /api/v1/admin/wallets/approvals",
+		authMiddleware(approvalPerm(http.HandlerFunc(approvalHandler.List))))
+	mux.Handle("GET /api/v1/admin/wallets/approvals/{id}",
+		authMiddleware(approvalPerm(http.HandlerFunc(approvalHandler.Get))))
+	mux.Handle("POST /api/v1/admin/wallets/approvals/{id}/approve",
+		authMiddleware(approvalPerm(http.HandlerFunc(approvalHandler.Approve))))
+	mux.Handle("POST /api/v1/admin/wallets/approvals/{id}/reject",
+		authMiddleware(approvalPerm(http.HandlerFunc(approvalHandler.Reject))))
+
 	// Apply middleware chain
-	metricsCollector := metrics.NewCollector("wallet")
 	handler := metricsCollector.Middleware("wallet")(mux)
 
+	// Record the client IP with audited actions
+	handler = audit.Middleware()(handler)
+
 	// Apply request ID
 	handler = middleware.RequestID()(handler)
 
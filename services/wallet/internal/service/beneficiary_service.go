@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/clients"
 	"github.com/vnykmshr/nivo/shared/errors"
 	"github.com/vnykmshr/nivo/shared/events"
 )
@@ -24,6 +25,11 @@ type UserLookupClient interface {
 	LookupUserByPhone(ctx context.Context, phone string) (*UserInfo, *errors.Error)
 }
 
+// StepUpVerifier redeems step-up verification tokens issued by the identity service.
+type StepUpVerifier interface {
+	Redeem(ctx context.Context, userID, operation, token string, metadata map[string]any) *errors.Error
+}
+
 // UserInfo represents basic user information from identity service.
 type UserInfo struct {
 	ID          string `json:"id"`
@@ -38,6 +44,7 @@ type BeneficiaryService struct {
 	beneficiaryRepo BeneficiaryRepositoryInterface
 	walletRepo      WalletRepositoryInterface
 	userClient      UserLookupClient
+	verifier        StepUpVerifier
 	eventPublisher  *events.Publisher
 }
 
@@ -46,17 +53,21 @@ func NewBeneficiaryService(
 	beneficiaryRepo BeneficiaryRepositoryInterface,
 	walletRepo WalletRepositoryInterface,
 	userClient UserLookupClient,
+	verifier StepUpVerifier,
 	eventPublisher *events.Publisher,
 ) *BeneficiaryService {
 	return &BeneficiaryService{
 		beneficiaryRepo: beneficiaryRepo,
 		walletRepo:      walletRepo,
 		userClient:      userClient,
+		verifier:        verifier,
 		eventPublisher:  eventPublisher,
 	}
 }
 
 // AddBeneficiary adds a new beneficiary for a user.
+// Requires a verification token bound to the phone number, which is used up
+// once the beneficiary is known to be valid.
 func (s *BeneficiaryService) AddBeneficiary(ctx context.Context, ownerUserID string, req *models.AddBeneficiaryRequest) (*models.Beneficiary, *errors.Error) {
 	// Lookup user by phone using identity service
 	userInfo, err := s.userClient.LookupUserByPhone(ctx, req.Phone)
@@ -103,6 +114,11 @@ func (s *BeneficiaryService) AddBeneficiary(ctx context.Context, ownerUserID str
 		return nil, errors.BadRequest("beneficiary's wallet is not available for transfers")
 	}
 
+	metadata := map[string]any{"phone": req.Phone}
+	if err := s.verifier.Redeem(ctx, ownerUserID, clients.VerificationOpBeneficiaryAdd, req.VerificationToken, metadata); err != nil {
+		return nil, err
+	}
+
 	// Create beneficiary
 	beneficiary := &models.Beneficiary{
 		OwnerUserID:         ownerUserID,
@@ -176,10 +176,44 @@ func (m *mockWalletRepoForBeneficiary) ProcessDepositWithinTx(ctx context.Contex
 	return nil
 }
 
+func (m *mockWalletRepoForBeneficiary) ProcessWithdrawalWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	return nil
+}
+
+func (m *mockWalletRepoForBeneficiary) HoldFundsWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	return nil
+}
+
+func (m *mockWalletRepoForBeneficiary) ReleaseHoldWithinTx(ctx context.Context, transactionID string) *errors.Error {
+	return nil
+}
+
+func (m *mockWalletRepoForBeneficiary) ReverseMovementWithinTx(ctx context.Context, transactionID string) *errors.Error {
+	return nil
+}
+
 func (m *mockWalletRepoForBeneficiary) UpdateBalance(ctx context.Context, walletID string, amount int64) *errors.Error {
 	return nil
 }
 
+type mockStepUpVerifier struct {
+	userID    string
+	operation string
+	metadata  map[string]any
+	err       *errors.Error
+}
+
+func (m *mockStepUpVerifier) Redeem(ctx context.Context, userID, operation, token string, metadata map[string]any) *errors.Error {
+	if token == "" {
+		return errors.VerificationRequired("this operation requires verification")
+	}
+	if m.err != nil {
+		return m.err
+	}
+	m.userID, m.operation, m.metadata = userID, operation, metadata
+	return nil
+}
+
 // Test cases
 
 func TestAddBeneficiary_Success(t *testing.T) {
@@ -187,11 +221,12 @@ func TestAddBeneficiary_Success(t *testing.T) {
 	walletRepo := newMockWalletRepoForBeneficiary()
 	userClient := newMockUserClient()
 
-	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, nil)
+	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, &mockStepUpVerifier{}, nil)
 
 	req := &models.AddBeneficiaryRequest{
-		Phone:    "+919876543210",
-		Nickname: "John",
+		Phone:             "+919876543210",
+		Nickname:          "John",
+		VerificationToken: "ver-token",
 	}
 
 	beneficiary, err := service.AddBeneficiary(context.Background(), "user-1", req)
@@ -213,12 +248,59 @@ func TestAddBeneficiary_Success(t *testing.T) {
 	}
 }
 
+func TestAddBeneficiary_RequiresVerification(t *testing.T) {
+	beneficiaryRepo := newMockBeneficiaryRepository()
+	verifier := &mockStepUpVerifier{}
+	service := NewBeneficiaryService(beneficiaryRepo, newMockWalletRepoForBeneficiary(), newMockUserClient(), verifier, nil)
+
+	req := &models.AddBeneficiaryRequest{
+		Phone:    "+919876543210",
+		Nickname: "John",
+	}
+
+	_, err := service.AddBeneficiary(context.Background(), "user-1", req)
+	if err == nil || err.Code != errors.ErrCodeVerificationRequired {
+		t.Fatalf("Expected verification required, got %v", err)
+	}
+	if len(beneficiaryRepo.beneficiaries) != 0 {
+		t.Errorf("Expected no beneficiary created, got %d", len(beneficiaryRepo.beneficiaries))
+	}
+
+	req.VerificationToken = "ver-token"
+	if _, err := service.AddBeneficiary(context.Background(), "user-1", req); err != nil {
+		t.Fatalf("Expected no error with token, got %v", err)
+	}
+	if verifier.userID != "user-1" || verifier.operation != "beneficiary_add" || verifier.metadata["phone"] != "+919876543210" {
+		t.Errorf("Expected token bound to user and phone, got %s %s %v", verifier.userID, verifier.operation, verifier.metadata)
+	}
+}
+
+func TestAddBeneficiary_RejectedToken(t *testing.T) {
+	beneficiaryRepo := newMockBeneficiaryRepository()
+	verifier := &mockStepUpVerifier{err: errors.VerificationRequired("verification token is invalid, expired or already used")}
+	service := NewBeneficiaryService(beneficiaryRepo, newMockWalletRepoForBeneficiary(), newMockUserClient(), verifier, nil)
+
+	req := &models.AddBeneficiaryRequest{
+		Phone:             "+919876543210",
+		Nickname:          "John",
+		VerificationToken: "token-for-another-phone",
+	}
+
+	_, err := service.AddBeneficiary(context.Background(), "user-1", req)
+	if err == nil || err.Code != errors.ErrCodeVerificationRequired {
+		t.Fatalf("Expected verification required, got %v", err)
+	}
+	if len(beneficiaryRepo.beneficiaries) != 0 {
+		t.Errorf("Expected no beneficiary created, got %d", len(beneficiaryRepo.beneficiaries))
+	}
+}
+
 func TestAddBeneficiary_UserNotFound(t *testing.T) {
 	beneficiaryRepo := newMockBeneficiaryRepository()
 	walletRepo := newMockWalletRepoForBeneficiary()
 	userClient := newMockUserClient()
 
-	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, nil)
+	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, &mockStepUpVerifier{}, nil)
 
 	req := &models.AddBeneficiaryRequest{
 		Phone:    "+919999999999", // Non-existent phone
@@ -241,7 +323,7 @@ func TestAddBeneficiary_CannotAddSelf(t *testing.T) {
 	walletRepo := newMockWalletRepoForBeneficiary()
 	userClient := newMockUserClient()
 
-	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, nil)
+	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, &mockStepUpVerifier{}, nil)
 
 	req := &models.AddBeneficiaryRequest{
 		Phone:    "+919876543210",
@@ -265,11 +347,12 @@ func TestAddBeneficiary_DuplicateBeneficiary(t *testing.T) {
 	walletRepo := newMockWalletRepoForBeneficiary()
 	userClient := newMockUserClient()
 
-	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, nil)
+	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, &mockStepUpVerifier{}, nil)
 
 	req := &models.AddBeneficiaryRequest{
-		Phone:    "+919876543210",
-		Nickname: "John",
+		Phone:             "+919876543210",
+		Nickname:          "John",
+		VerificationToken: "ver-token",
 	}
 
 	// Add beneficiary first time
@@ -294,12 +377,13 @@ func TestListBeneficiaries_Success(t *testing.T) {
 	walletRepo := newMockWalletRepoForBeneficiary()
 	userClient := newMockUserClient()
 
-	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, nil)
+	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, &mockStepUpVerifier{}, nil)
 
 	// Add a beneficiary
 	req := &models.AddBeneficiaryRequest{
-		Phone:    "+919876543210",
-		Nickname: "John",
+		Phone:             "+919876543210",
+		Nickname:          "John",
+		VerificationToken: "ver-token",
 	}
 	_, _ = service.AddBeneficiary(context.Background(), "user-1", req)
 
@@ -320,12 +404,13 @@ func TestDeleteBeneficiary_Success(t *testing.T) {
 	walletRepo := newMockWalletRepoForBeneficiary()
 	userClient := newMockUserClient()
 
-	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, nil)
+	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, &mockStepUpVerifier{}, nil)
 
 	// Add a beneficiary
 	req := &models.AddBeneficiaryRequest{
-		Phone:    "+919876543210",
-		Nickname: "John",
+		Phone:             "+919876543210",
+		Nickname:          "John",
+		VerificationToken: "ver-token",
 	}
 	beneficiary, _ := service.AddBeneficiary(context.Background(), "user-1", req)
 
@@ -348,12 +433,13 @@ func TestUpdateBeneficiary_Success(t *testing.T) {
 	walletRepo := newMockWalletRepoForBeneficiary()
 	userClient := newMockUserClient()
 
-	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, nil)
+	service := NewBeneficiaryService(beneficiaryRepo, walletRepo, userClient, &mockStepUpVerifier{}, nil)
 
 	// Add a beneficiary
 	addReq := &models.AddBeneficiaryRequest{
-		Phone:    "+919876543210",
-		Nickname: "John",
+		Phone:             "+919876543210",
+		Nickname:          "John",
+		VerificationToken: "ver-token",
 	}
 	beneficiary, _ := service.AddBeneficiary(context.Background(), "user-1", addReq)
 
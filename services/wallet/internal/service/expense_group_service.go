@@ -0,0 +1,537 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/events"
+	"github.com/vnykmshr/nivo/shared/logger"
+	sharedModels "github.com/vnykmshr/nivo/shared/models"
+)
+
+// ExpenseGroupRepositoryInterface defines the interface for expense group storage.
+type ExpenseGroupRepositoryInterface interface {
+	CreateGroup(ctx context.Context, group *models.ExpenseGroup, members []*models.ExpenseGroupMember) *errors.Error
+	GetGroup(ctx context.Context, id string) (*models.ExpenseGroup, *errors.Error)
+	ListGroupsByMember(ctx context.Context, userID string, limit, offset int) ([]*models.ExpenseGroup, int, *errors.Error)
+	AddMember(ctx context.Context, member *models.ExpenseGroupMember) *errors.Error
+	CreateExpense(ctx context.Context, expense *models.GroupExpense) *errors.Error
+	ListExpenses(ctx context.Context, groupID string, limit, offset int) ([]*models.GroupExpense, int, *errors.Error)
+	GetMemberTotals(ctx context.Context, groupID string) ([]*models.MemberBalance, *errors.Error)
+	CreateSettlement(ctx context.Context, settlement *models.GroupSettlement) *errors.Error
+	ListPendingSettlements(ctx context.Context, groupID, fromMemberID string) ([]*models.GroupSettlement, *errors.Error)
+	CompleteSettlement(ctx context.Context, id string, status models.SettlementStatus, transactionID, failureReason *string) (*models.GroupSettlement, *errors.Error)
+	ListSettlements(ctx context.Context, groupID string, limit, offset int) ([]*models.GroupSettlement, int, *errors.Error)
+}
+
+// CurrentUserClient retrieves the authenticated user from the identity service.
+type CurrentUserClient interface {
+	GetCurrentUser(ctx context.Context) (*UserInfo, *errors.Error)
+}
+
+// SettlementTransferClient creates settle-up transfers through the transaction service.
+type SettlementTransferClient interface {
+	CreateTransfer(ctx context.Context, req *SettlementTransferRequest, idempotencyKey string) (*TransferResult, *errors.Error)
+}
+
+// ExpenseGroupService handles business logic for split-bill expense groups.
+type ExpenseGroupService struct {
+	groupRepo       ExpenseGroupRepositoryInterface
+	beneficiaryRepo BeneficiaryRepositoryInterface
+	walletRepo      WalletRepositoryInterface
+	userClient      CurrentUserClient
+	transferClient  SettlementTransferClient
+	eventPublisher  *events.Publisher
+	logger          *logger.Logger
+}
+
+// NewExpenseGroupService creates a new expense group service.
+func NewExpenseGroupService(
+	groupRepo ExpenseGroupRepositoryInterface,
+	beneficiaryRepo BeneficiaryRepositoryInterface,
+	walletRepo WalletRepositoryInterface,
+	userClient CurrentUserClient,
+	transferClient SettlementTransferClient,
+	eventPublisher *events.Publisher,
+) *ExpenseGroupService {
+	return &ExpenseGroupService{
+		groupRepo:       groupRepo,
+		beneficiaryRepo: beneficiaryRepo,
+		walletRepo:      walletRepo,
+		userClient:      userClient,
+		transferClient:  transferClient,
+		eventPublisher:  eventPublisher,
+		logger:          logger.NewDefault("wallet.expense_groups"),
+	}
+}
+
+// CreateGroup creates an expense group of the owner and some of their beneficiaries.
+func (s *ExpenseGroupService) CreateGroup(ctx context.Context, ownerUserID string, req *models.CreateExpenseGroupRequest) (*models.ExpenseGroup, *errors.Error) {
+	if len(req.BeneficiaryIDs) == 0 {
+		return nil, errors.BadRequest("at least one beneficiary is required")
+	}
+
+	owner, err := s.userClient.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerWallet, err := s.defaultWallet(ctx, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []*models.ExpenseGroupMember{{
+		UserID:      ownerUserID,
+		WalletID:    ownerWallet.ID,
+		DisplayName: owner.FullName,
+	}}
+	seen := map[string]bool{ownerUserID: true}
+
+	for _, beneficiaryID := range req.BeneficiaryIDs {
+		member, err := s.beneficiaryMember(ctx, ownerUserID, beneficiaryID)
+		if err != nil {
+			return nil, err
+		}
+		if seen[member.UserID] {
+			return nil, errors.BadRequest("each member can only be added once")
+		}
+		seen[member.UserID] = true
+		members = append(members, member)
+	}
+
+	group := &models.ExpenseGroup{
+		OwnerUserID: ownerUserID,
+		Name:        req.Name,
+		Currency:    sharedModels.INR,
+	}
+
+	if err := s.groupRepo.CreateGroup(ctx, group, members); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetGroup retrieves a group the user is a member of.
+func (s *ExpenseGroupService) GetGroup(ctx context.Context, userID, groupID string) (*models.ExpenseGroup, *errors.Error) {
+	group, _, err := s.membership(ctx, userID, groupID)
+	return group, err
+}
+
+// ListGroups retrieves the groups a user is a member of.
+func (s *ExpenseGroupService) ListGroups(ctx context.Context, userID string, limit, offset int) ([]*models.ExpenseGroup, int, *errors.Error) {
+	return s.groupRepo.ListGroupsByMember(ctx, userID, limit, offset)
+}
+
+// AddMember adds one of the owner's beneficiaries to a group. Only the owner can add members.
+func (s *ExpenseGroupService) AddMember(ctx context.Context, userID, groupID string, req *models.AddGroupMemberRequest) (*models.ExpenseGroupMember, *errors.Error) {
+	group, _, err := s.membership(ctx, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.OwnerUserID != userID {
+		return nil, errors.Forbidden("only the group owner can add members")
+	}
+
+	member, err := s.beneficiaryMember(ctx, userID, req.BeneficiaryID)
+	if err != nil {
+		return nil, err
+	}
+	member.GroupID = group.ID
+
+	if err := s.groupRepo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// AddExpense logs an expense paid by one member and splits it between members.
+func (s *ExpenseGroupService) AddExpense(ctx context.Context, userID, groupID string, req *models.AddExpenseRequest) (*models.GroupExpense, *errors.Error) {
+	group, caller, err := s.membership(ctx, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	paidBy := caller.ID
+	if req.PaidByMemberID != "" {
+		if findMember(group, req.PaidByMemberID) == nil {
+			return nil, errors.BadRequest("paid_by_member_id is not a member of this group")
+		}
+		paidBy = req.PaidByMemberID
+	}
+
+	shares, err := splitExpense(req, group.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	expense := &models.GroupExpense{
+		GroupID:         group.ID,
+		PaidByMemberID:  paidBy,
+		Amount:          req.Amount,
+		Description:     req.Description,
+		SplitType:       req.SplitType,
+		Shares:          shares,
+		CreatedByUserID: userID,
+	}
+
+	if err := s.groupRepo.CreateExpense(ctx, expense); err != nil {
+		return nil, err
+	}
+
+	return expense, nil
+}
+
+// ListExpenses retrieves the expenses of a group the user is a member of.
+func (s *ExpenseGroupService) ListExpenses(ctx context.Context, userID, groupID string, limit, offset int) ([]*models.GroupExpense, int, *errors.Error) {
+	if _, _, err := s.membership(ctx, userID, groupID); err != nil {
+		return nil, 0, err
+	}
+	return s.groupRepo.ListExpenses(ctx, groupID, limit, offset)
+}
+
+// GetBalances returns each member's net balance and the transfers that settle the group.
+func (s *ExpenseGroupService) GetBalances(ctx context.Context, userID, groupID string) (*models.GroupBalances, *errors.Error) {
+	group, _, err := s.membership(ctx, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return s.balances(ctx, group)
+}
+
+// Settle pays every settle-up transfer owed by the user in a group. Each one is
+// a regular transfer from the user's member wallet, created through the
+// transaction service on the user's behalf; completed transfers mark the debt paid.
+// verificationToken is passed with each transfer, for those above the
+// high-value threshold that need step-up verification.
+func (s *ExpenseGroupService) Settle(ctx context.Context, userID, groupID, verificationToken string) ([]*models.GroupSettlement, *errors.Error) {
+	group, member, err := s.membership(ctx, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Finish settlements an earlier settle-up left pending before paying anything new
+	pending, err := s.groupRepo.ListPendingSettlements(ctx, group.ID, member.ID)
+	if err != nil {
+		return nil, err
+	}
+	settlements := make([]*models.GroupSettlement, 0)
+	for _, settlement := range pending {
+		settlement = s.executeSettlement(ctx, group, settlement, verificationToken)
+		if settlement.Status == models.SettlementStatusPending {
+			return nil, errors.Conflict("a previous settlement is still being processed, try again shortly")
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	balances, err := s.balances(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transfer := range balances.Transfers {
+		if transfer.FromMemberID != member.ID {
+			continue
+		}
+
+		settlement := &models.GroupSettlement{
+			GroupID:      group.ID,
+			FromMemberID: member.ID,
+			ToMemberID:   transfer.ToMemberID,
+			FromWalletID: member.WalletID,
+			ToWalletID:   findMember(group, transfer.ToMemberID).WalletID,
+			Amount:       transfer.Amount,
+		}
+		if err := s.groupRepo.CreateSettlement(ctx, settlement); err != nil {
+			return nil, err
+		}
+
+		settlements = append(settlements, s.executeSettlement(ctx, group, settlement, verificationToken))
+	}
+
+	if len(settlements) == 0 {
+		return nil, errors.BadRequest("you have nothing to settle in this group")
+	}
+
+	return settlements, nil
+}
+
+// ListSettlements retrieves the settlements of a group the user is a member of.
+func (s *ExpenseGroupService) ListSettlements(ctx context.Context, userID, groupID string, limit, offset int) ([]*models.GroupSettlement, int, *errors.Error) {
+	if _, _, err := s.membership(ctx, userID, groupID); err != nil {
+		return nil, 0, err
+	}
+	return s.groupRepo.ListSettlements(ctx, groupID, limit, offset)
+}
+
+// executeSettlement creates the transfer for a pending settlement and records
+// its outcome. When the outcome is unknown (a timeout or server error) the
+// settlement stays pending; retrying it reuses the idempotency key, so the
+// transfer is never created twice. A retry with a different verification token
+// is refused by the transaction service and also leaves the settlement pending.
+func (s *ExpenseGroupService) executeSettlement(ctx context.Context, group *models.ExpenseGroup, settlement *models.GroupSettlement, verificationToken string) *models.GroupSettlement {
+	result, transferErr := s.transferClient.CreateTransfer(ctx, &SettlementTransferRequest{
+		SourceWalletID:      settlement.FromWalletID,
+		DestinationWalletID: settlement.ToWalletID,
+		Amount:              settlement.Amount,
+		Currency:            string(group.Currency),
+		Description:         fmt.Sprintf("Settle up: %s", group.Name),
+		Reference:           "settle-" + settlement.ID,
+		VerificationToken:   verificationToken,
+	}, "group-settlement-"+settlement.ID)
+
+	status := models.SettlementStatusCompleted
+	var transactionID, failureReason *string
+
+	switch {
+	case transferErr != nil && (transferErr.Code == errors.ErrCodeInternal || transferErr.Code == errors.ErrCodeDuplicateIdempotencyKey):
+		s.logger.WithError(transferErr).WithField("settlement_id", settlement.ID).Warn("Settlement transfer outcome unknown, leaving it pending")
+		return settlement
+	case transferErr != nil:
+		status = models.SettlementStatusFailed
+		failureReason = &transferErr.Message
+	case result.Status == "failed":
+		status = models.SettlementStatusFailed
+		reason := "transfer failed"
+		if result.FailureReason != nil {
+			reason = *result.FailureReason
+		}
+		failureReason = &reason
+	default:
+		transactionID = &result.ID
+	}
+
+	updated, err := s.groupRepo.CompleteSettlement(ctx, settlement.ID, status, transactionID, failureReason)
+	if err != nil {
+		s.logger.WithError(err).WithField("settlement_id", settlement.ID).Error("Failed to record settlement outcome")
+		return settlement
+	}
+
+	if updated.Status == models.SettlementStatusCompleted && s.eventPublisher != nil {
+		s.eventPublisher.PublishWalletEvent("expense_group.settled", updated.FromWalletID, map[string]interface{}{
+			"group_id":       group.ID,
+			"settlement_id":  updated.ID,
+			"from_member_id": updated.FromMemberID,
+			"to_member_id":   updated.ToMemberID,
+			"amount":         updated.Amount,
+			"transaction_id": *updated.TransactionID,
+		})
+	}
+
+	return updated
+}
+
+// balances computes each member's net balance and the transfers that settle the group.
+func (s *ExpenseGroupService) balances(ctx context.Context, group *models.ExpenseGroup) (*models.GroupBalances, *errors.Error) {
+	balances, err := s.groupRepo.GetMemberTotals(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range balances {
+		b.Net = b.Paid - b.Share + b.SettledOut - b.SettledIn
+	}
+
+	return &models.GroupBalances{
+		GroupID:   group.ID,
+		Currency:  group.Currency,
+		Balances:  balances,
+		Transfers: planSettlements(balances),
+	}, nil
+}
+
+// membership loads a group and the user's member record in it. Users outside
+// the group get a not found error, so group IDs cannot be probed.
+func (s *ExpenseGroupService) membership(ctx context.Context, userID, groupID string) (*models.ExpenseGroup, *models.ExpenseGroupMember, *errors.Error) {
+	group, err := s.groupRepo.GetGroup(ctx, groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, member := range group.Members {
+		if member.UserID == userID {
+			return group, member, nil
+		}
+	}
+
+	return nil, nil, errors.NotFoundWithID("expense group", groupID)
+}
+
+// beneficiaryMember builds a member from one of the owner's beneficiaries.
+func (s *ExpenseGroupService) beneficiaryMember(ctx context.Context, ownerUserID, beneficiaryID string) (*models.ExpenseGroupMember, *errors.Error) {
+	beneficiary, err := s.beneficiaryRepo.GetByID(ctx, beneficiaryID, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ExpenseGroupMember{
+		UserID:        beneficiary.BeneficiaryUserID,
+		WalletID:      beneficiary.BeneficiaryWalletID,
+		DisplayName:   beneficiary.Nickname,
+		BeneficiaryID: &beneficiary.ID,
+	}, nil
+}
+
+// defaultWallet returns the user's default INR wallet.
+func (s *ExpenseGroupService) defaultWallet(ctx context.Context, userID string) (*models.Wallet, *errors.Error) {
+	wallets, err := s.walletRepo.ListByUserID(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, wallet := range wallets {
+		if wallet.Type == models.WalletTypeDefault && wallet.Currency == sharedModels.INR {
+			return wallet, nil
+		}
+	}
+
+	return nil, errors.BadRequest("you need a default INR wallet to create a group")
+}
+
+func findMember(group *models.ExpenseGroup, memberID string) *models.ExpenseGroupMember {
+	for _, member := range group.Members {
+		if member.ID == memberID {
+			return member
+		}
+	}
+	return nil
+}
+
+// splitExpense divides an expense into shares. Paise left over by rounding go
+// one each to the first members listed, so shares always add up to the amount.
+func splitExpense(req *models.AddExpenseRequest, members []*models.ExpenseGroupMember) ([]*models.ExpenseShare, *errors.Error) {
+	splits := req.Splits
+	if len(splits) == 0 {
+		if req.SplitType != models.SplitTypeEqual {
+			return nil, errors.BadRequest("splits are required for percentage and exact splits")
+		}
+		for _, member := range members {
+			splits = append(splits, models.ExpenseSplit{MemberID: member.ID})
+		}
+	}
+
+	isMember := make(map[string]bool, len(members))
+	for _, member := range members {
+		isMember[member.ID] = true
+	}
+	seen := make(map[string]bool, len(splits))
+	for _, split := range splits {
+		if !isMember[split.MemberID] {
+			return nil, errors.BadRequest(fmt.Sprintf("%s is not a member of this group", split.MemberID))
+		}
+		if seen[split.MemberID] {
+			return nil, errors.BadRequest("each member can only appear once in splits")
+		}
+		seen[split.MemberID] = true
+	}
+
+	shares := make([]*models.ExpenseShare, len(splits))
+	var allocated int64
+
+	switch req.SplitType {
+	case models.SplitTypeEqual:
+		each := req.Amount / int64(len(splits))
+		for i, split := range splits {
+			shares[i] = &models.ExpenseShare{MemberID: split.MemberID, Amount: each}
+			allocated += each
+		}
+
+	case models.SplitTypePercentage:
+		// Percentages are handled in basis points to keep the arithmetic exact
+		var totalBasisPoints int64
+		for i, split := range splits {
+			basisPoints := int64(math.Round(split.Percentage * 100))
+			if basisPoints <= 0 {
+				return nil, errors.BadRequest("percentages must be positive")
+			}
+			totalBasisPoints += basisPoints
+
+			amount := req.Amount * basisPoints / 10000
+			shares[i] = &models.ExpenseShare{MemberID: split.MemberID, Amount: amount}
+			allocated += amount
+		}
+		if totalBasisPoints != 10000 {
+			return nil, errors.BadRequest("percentages must add up to 100")
+		}
+
+	case models.SplitTypeExact:
+		for i, split := range splits {
+			if split.Amount <= 0 {
+				return nil, errors.BadRequest("split amounts must be positive")
+			}
+			shares[i] = &models.ExpenseShare{MemberID: split.MemberID, Amount: split.Amount}
+			allocated += split.Amount
+		}
+		if allocated != req.Amount {
+			return nil, errors.BadRequest(fmt.Sprintf("split amounts add up to %d, expected %d", allocated, req.Amount))
+		}
+
+	default:
+		return nil, errors.BadRequest("split_type must be equal, percentage or exact")
+	}
+
+	for i := 0; allocated < req.Amount; i++ {
+		shares[i%len(shares)].Amount++
+		allocated++
+	}
+
+	return shares, nil
+}
+
+// planSettlements suggests transfers that bring every balance to zero, by
+// repeatedly paying the largest creditor from the largest debtor. This takes at
+// most one transfer fewer than the number of members with a non-zero balance.
+// Ties keep member order, so the plan is stable between calls.
+func planSettlements(balances []*models.MemberBalance) []*models.SettleUpTransfer {
+	type position struct {
+		balance *models.MemberBalance
+		amount  int64
+	}
+
+	var creditors, debtors []*position
+	for _, b := range balances {
+		switch {
+		case b.Net > 0:
+			creditors = append(creditors, &position{balance: b, amount: b.Net})
+		case b.Net < 0:
+			debtors = append(debtors, &position{balance: b, amount: -b.Net})
+		}
+	}
+
+	byAmount := func(positions []*position) func(i, j int) bool {
+		return func(i, j int) bool { return positions[i].amount > positions[j].amount }
+	}
+	sort.SliceStable(creditors, byAmount(creditors))
+	sort.SliceStable(debtors, byAmount(debtors))
+
+	transfers := make([]*models.SettleUpTransfer, 0)
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		debtor, creditor := debtors[i], creditors[j]
+		amount := min(debtor.amount, creditor.amount)
+
+		transfers = append(transfers, &models.SettleUpTransfer{
+			FromMemberID: debtor.balance.MemberID,
+			FromName:     debtor.balance.DisplayName,
+			ToMemberID:   creditor.balance.MemberID,
+			ToName:       creditor.balance.DisplayName,
+			Amount:       amount,
+		})
+
+		debtor.amount -= amount
+		creditor.amount -= amount
+		if debtor.amount == 0 {
+			i++
+		}
+		if creditor.amount == 0 {
+			j++
+		}
+	}
+
+	return transfers
+}
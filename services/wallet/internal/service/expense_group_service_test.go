@@ -0,0 +1,543 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// Mock implementations for testing
+
+type mockExpenseGroupRepository struct {
+	groups      map[string]*models.ExpenseGroup
+	expenses    []*models.GroupExpense
+	settlements []*models.GroupSettlement
+	nextID      int
+}
+
+func newMockExpenseGroupRepository() *mockExpenseGroupRepository {
+	return &mockExpenseGroupRepository{groups: make(map[string]*models.ExpenseGroup)}
+}
+
+func (m *mockExpenseGroupRepository) id(prefix string) string {
+	m.nextID++
+	return fmt.Sprintf("%s-%d", prefix, m.nextID)
+}
+
+func (m *mockExpenseGroupRepository) CreateGroup(ctx context.Context, group *models.ExpenseGroup, members []*models.ExpenseGroupMember) *errors.Error {
+	group.ID = m.id("group")
+	for _, member := range members {
+		member.ID = m.id("member")
+		member.GroupID = group.ID
+	}
+	group.Members = members
+	m.groups[group.ID] = group
+	return nil
+}
+
+func (m *mockExpenseGroupRepository) GetGroup(ctx context.Context, id string) (*models.ExpenseGroup, *errors.Error) {
+	group, ok := m.groups[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("expense group", id)
+	}
+	return group, nil
+}
+
+func (m *mockExpenseGroupRepository) ListGroupsByMember(ctx context.Context, userID string, limit, offset int) ([]*models.ExpenseGroup, int, *errors.Error) {
+	groups := make([]*models.ExpenseGroup, 0)
+	for _, group := range m.groups {
+		for _, member := range group.Members {
+			if member.UserID == userID {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups, len(groups), nil
+}
+
+func (m *mockExpenseGroupRepository) AddMember(ctx context.Context, member *models.ExpenseGroupMember) *errors.Error {
+	group := m.groups[member.GroupID]
+	for _, existing := range group.Members {
+		if existing.UserID == member.UserID {
+			return errors.Conflict("this user is already a member of the group")
+		}
+	}
+	member.ID = m.id("member")
+	group.Members = append(group.Members, member)
+	return nil
+}
+
+func (m *mockExpenseGroupRepository) CreateExpense(ctx context.Context, expense *models.GroupExpense) *errors.Error {
+	expense.ID = m.id("expense")
+	m.expenses = append(m.expenses, expense)
+	return nil
+}
+
+func (m *mockExpenseGroupRepository) ListExpenses(ctx context.Context, groupID string, limit, offset int) ([]*models.GroupExpense, int, *errors.Error) {
+	return m.expenses, len(m.expenses), nil
+}
+
+func (m *mockExpenseGroupRepository) GetMemberTotals(ctx context.Context, groupID string) ([]*models.MemberBalance, *errors.Error) {
+	balances := make([]*models.MemberBalance, 0)
+	for _, member := range m.groups[groupID].Members {
+		b := &models.MemberBalance{MemberID: member.ID, DisplayName: member.DisplayName}
+		for _, e := range m.expenses {
+			if e.PaidByMemberID == member.ID {
+				b.Paid += e.Amount
+			}
+			for _, share := range e.Shares {
+				if share.MemberID == member.ID {
+					b.Share += share.Amount
+				}
+			}
+		}
+		for _, s := range m.settlements {
+			if s.Status != models.SettlementStatusCompleted {
+				continue
+			}
+			if s.FromMemberID == member.ID {
+				b.SettledOut += s.Amount
+			}
+			if s.ToMemberID == member.ID {
+				b.SettledIn += s.Amount
+			}
+		}
+		balances = append(balances, b)
+	}
+	return balances, nil
+}
+
+func (m *mockExpenseGroupRepository) CreateSettlement(ctx context.Context, settlement *models.GroupSettlement) *errors.Error {
+	for _, s := range m.settlements {
+		if s.GroupID == settlement.GroupID && s.FromMemberID == settlement.FromMemberID && s.Status == models.SettlementStatusPending {
+			return errors.Conflict("a settlement for this member is already in progress")
+		}
+	}
+	settlement.ID = m.id("settlement")
+	settlement.Status = models.SettlementStatusPending
+	stored := *settlement
+	m.settlements = append(m.settlements, &stored)
+	return nil
+}
+
+func (m *mockExpenseGroupRepository) ListPendingSettlements(ctx context.Context, groupID, fromMemberID string) ([]*models.GroupSettlement, *errors.Error) {
+	pending := make([]*models.GroupSettlement, 0)
+	for _, s := range m.settlements {
+		if s.GroupID == groupID && s.FromMemberID == fromMemberID && s.Status == models.SettlementStatusPending {
+			copied := *s
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (m *mockExpenseGroupRepository) CompleteSettlement(ctx context.Context, id string, status models.SettlementStatus, transactionID, failureReason *string) (*models.GroupSettlement, *errors.Error) {
+	for _, s := range m.settlements {
+		if s.ID == id && s.Status == models.SettlementStatusPending {
+			s.Status = status
+			s.TransactionID = transactionID
+			s.FailureReason = failureReason
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, errors.Conflict("settlement is no longer pending")
+}
+
+func (m *mockExpenseGroupRepository) ListSettlements(ctx context.Context, groupID string, limit, offset int) ([]*models.GroupSettlement, int, *errors.Error) {
+	return m.settlements, len(m.settlements), nil
+}
+
+type mockCurrentUserClient struct {
+	user *UserInfo
+}
+
+func (m *mockCurrentUserClient) GetCurrentUser(ctx context.Context) (*UserInfo, *errors.Error) {
+	return m.user, nil
+}
+
+type mockSettlementTransferClient struct {
+	requests []*SettlementTransferRequest
+	keys     []string
+	created  map[string]*TransferResult // By idempotency key, to replay retries
+	status   string                     // Status of created transfers; completed when empty
+	err      *errors.Error              // Returned without creating a transfer
+}
+
+func (m *mockSettlementTransferClient) CreateTransfer(ctx context.Context, req *SettlementTransferRequest, idempotencyKey string) (*TransferResult, *errors.Error) {
+	m.keys = append(m.keys, idempotencyKey)
+	if m.err != nil {
+		return nil, m.err
+	}
+	if req.Amount > clients.HighValueTransferThreshold && req.VerificationToken == "" {
+		return nil, errors.VerificationRequired("this operation requires verification")
+	}
+	if result, ok := m.created[idempotencyKey]; ok {
+		return result, nil
+	}
+
+	m.requests = append(m.requests, req)
+	result := &TransferResult{ID: fmt.Sprintf("tx-%d", len(m.requests)), Status: "completed"}
+	if m.status != "" {
+		result.Status = m.status
+		reason := "insufficient balance"
+		result.FailureReason = &reason
+	}
+	m.created[idempotencyKey] = result
+	return result, nil
+}
+
+type expenseGroupFixture struct {
+	service   *ExpenseGroupService
+	repo      *mockExpenseGroupRepository
+	transfers *mockSettlementTransferClient
+	group     *models.ExpenseGroup
+}
+
+// newExpenseGroupFixture creates a group owned by user-1 with beneficiaries
+// user-2 and user-3.
+func newExpenseGroupFixture(t *testing.T) *expenseGroupFixture {
+	t.Helper()
+
+	beneficiaryRepo := newMockBeneficiaryRepository()
+	for i, nickname := range []string{"Bob", "Carol"} {
+		id := fmt.Sprintf("ben-%d", i+2)
+		beneficiaryRepo.beneficiaries[id] = &models.Beneficiary{
+			ID:                  id,
+			OwnerUserID:         "user-1",
+			BeneficiaryUserID:   fmt.Sprintf("user-%d", i+2),
+			BeneficiaryWalletID: fmt.Sprintf("wallet-%d", i+2),
+			Nickname:            nickname,
+		}
+	}
+	beneficiaryRepo.beneficiaries["ben-other"] = &models.Beneficiary{
+		ID:                  "ben-other",
+		OwnerUserID:         "user-9",
+		BeneficiaryUserID:   "user-4",
+		BeneficiaryWalletID: "wallet-4",
+		Nickname:            "Dave",
+	}
+
+	walletRepo := newMockWalletRepoForBeneficiary()
+	walletRepo.wallets["wallet-1"] = &models.Wallet{
+		ID:       "wallet-1",
+		UserID:   "user-1",
+		Type:     models.WalletTypeDefault,
+		Currency: "INR",
+		Status:   models.WalletStatusActive,
+	}
+
+	f := &expenseGroupFixture{
+		repo:      newMockExpenseGroupRepository(),
+		transfers: &mockSettlementTransferClient{created: make(map[string]*TransferResult)},
+	}
+	users := &mockCurrentUserClient{user: &UserInfo{ID: "user-1", FullName: "Alice"}}
+	f.service = NewExpenseGroupService(f.repo, beneficiaryRepo, walletRepo, users, f.transfers, nil)
+
+	group, err := f.service.CreateGroup(context.Background(), "user-1", &models.CreateExpenseGroupRequest{
+		Name:           "Goa trip",
+		BeneficiaryIDs: []string{"ben-2", "ben-3"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating group, got %v", err)
+	}
+	f.group = group
+	return f
+}
+
+// member returns the member ID of the nth member: 0 is the owner.
+func (f *expenseGroupFixture) member(n int) string {
+	return f.group.Members[n].ID
+}
+
+func (f *expenseGroupFixture) addExpense(t *testing.T, userID string, req *models.AddExpenseRequest) *models.GroupExpense {
+	t.Helper()
+	expense, err := f.service.AddExpense(context.Background(), userID, f.group.ID, req)
+	if err != nil {
+		t.Fatalf("expected no error adding expense, got %v", err)
+	}
+	return expense
+}
+
+func shareAmounts(shares []*models.ExpenseShare) []int64 {
+	amounts := make([]int64, len(shares))
+	for i, share := range shares {
+		amounts[i] = share.Amount
+	}
+	return amounts
+}
+
+// Test cases
+
+func TestCreateGroup_AddsOwnerAndBeneficiaries(t *testing.T) {
+	f := newExpenseGroupFixture(t)
+
+	if len(f.group.Members) != 3 {
+		t.Fatalf("expected 3 members, got %d", len(f.group.Members))
+	}
+	owner := f.group.Members[0]
+	if owner.UserID != "user-1" || owner.WalletID != "wallet-1" || owner.DisplayName != "Alice" || owner.BeneficiaryID != nil {
+		t.Errorf("expected owner with default wallet, got %+v", owner)
+	}
+	if bob := f.group.Members[1]; bob.UserID != "user-2" || bob.WalletID != "wallet-2" || bob.DisplayName != "Bob" {
+		t.Errorf("expected member from beneficiary, got %+v", bob)
+	}
+}
+
+func TestCreateGroup_OnlyOwnBeneficiaries(t *testing.T) {
+	f := newExpenseGroupFixture(t)
+
+	_, err := f.service.CreateGroup(context.Background(), "user-1", &models.CreateExpenseGroupRequest{
+		Name:           "Flat",
+		BeneficiaryIDs: []string{"ben-other"},
+	})
+
+	if err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestGroupAccess_NonMember(t *testing.T) {
+	f := newExpenseGroupFixture(t)
+
+	if _, err := f.service.GetGroup(context.Background(), "user-9", f.group.ID); err == nil || err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found for non-member, got %v", err)
+	}
+	if _, err := f.service.AddMember(context.Background(), "user-2", f.group.ID, &models.AddGroupMemberRequest{BeneficiaryID: "ben-3"}); err == nil || err.Code != errors.ErrCodeForbidden {
+		t.Errorf("expected forbidden for member adding members, got %v", err)
+	}
+}
+
+func TestSplitExpense(t *testing.T) {
+	members := []*models.ExpenseGroupMember{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	tests := []struct {
+		name     string
+		req      *models.AddExpenseRequest
+		expected []int64
+	}{
+		{
+			name:     "equal among all members",
+			req:      &models.AddExpenseRequest{Amount: 1000, SplitType: models.SplitTypeEqual},
+			expected: []int64{334, 333, 333},
+		},
+		{
+			name: "equal among some members",
+			req: &models.AddExpenseRequest{Amount: 1001, SplitType: models.SplitTypeEqual,
+				Splits: []models.ExpenseSplit{{MemberID: "b"}, {MemberID: "c"}}},
+			expected: []int64{501, 500},
+		},
+		{
+			name: "percentage",
+			req: &models.AddExpenseRequest{Amount: 1000, SplitType: models.SplitTypePercentage,
+				Splits: []models.ExpenseSplit{{MemberID: "a", Percentage: 33.33}, {MemberID: "b", Percentage: 33.33}, {MemberID: "c", Percentage: 33.34}}},
+			expected: []int64{334, 333, 333},
+		},
+		{
+			name: "exact",
+			req: &models.AddExpenseRequest{Amount: 1000, SplitType: models.SplitTypeExact,
+				Splits: []models.ExpenseSplit{{MemberID: "a", Amount: 700}, {MemberID: "c", Amount: 300}}},
+			expected: []int64{700, 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := splitExpense(tt.req, members)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := shareAmounts(shares); fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected shares %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSplitExpense_Invalid(t *testing.T) {
+	members := []*models.ExpenseGroupMember{{ID: "a"}, {ID: "b"}}
+
+	tests := []struct {
+		name string
+		req  *models.AddExpenseRequest
+	}{
+		{"percentage without splits", &models.AddExpenseRequest{Amount: 1000, SplitType: models.SplitTypePercentage}},
+		{"percentages not 100", &models.AddExpenseRequest{Amount: 1000, SplitType: models.SplitTypePercentage,
+			Splits: []models.ExpenseSplit{{MemberID: "a", Percentage: 50}, {MemberID: "b", Percentage: 40}}}},
+		{"exact does not add up", &models.AddExpenseRequest{Amount: 1000, SplitType: models.SplitTypeExact,
+			Splits: []models.ExpenseSplit{{MemberID: "a", Amount: 500}, {MemberID: "b", Amount: 400}}}},
+		{"not a member", &models.AddExpenseRequest{Amount: 1000, SplitType: models.SplitTypeEqual,
+			Splits: []models.ExpenseSplit{{MemberID: "z"}}}},
+		{"member twice", &models.AddExpenseRequest{Amount: 1000, SplitType: models.SplitTypeEqual,
+			Splits: []models.ExpenseSplit{{MemberID: "a"}, {MemberID: "a"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := splitExpense(tt.req, members)
+			if err == nil || err.Code != errors.ErrCodeBadRequest {
+				t.Errorf("expected bad request, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPlanSettlements_SettlesEveryBalance(t *testing.T) {
+	balances := []*models.MemberBalance{
+		{MemberID: "a", Net: 500},
+		{MemberID: "b", Net: 300},
+		{MemberID: "c", Net: -600},
+		{MemberID: "d", Net: -200},
+	}
+
+	transfers := planSettlements(balances)
+
+	if len(transfers) != 3 {
+		t.Fatalf("expected 3 transfers, got %d", len(transfers))
+	}
+
+	net := map[string]int64{"a": 500, "b": 300, "c": -600, "d": -200}
+	for _, tr := range transfers {
+		net[tr.FromMemberID] += tr.Amount
+		net[tr.ToMemberID] -= tr.Amount
+	}
+	for member, remaining := range net {
+		if remaining != 0 {
+			t.Errorf("expected %s settled, %d remaining", member, remaining)
+		}
+	}
+
+	if first := transfers[0]; first.FromMemberID != "c" || first.ToMemberID != "a" || first.Amount != 500 {
+		t.Errorf("expected largest debtor to pay largest creditor first, got %+v", first)
+	}
+}
+
+func TestGetBalances_AfterExpenses(t *testing.T) {
+	f := newExpenseGroupFixture(t)
+	f.addExpense(t, "user-1", &models.AddExpenseRequest{Amount: 300000, Description: "Hotel", SplitType: models.SplitTypeEqual})
+	f.addExpense(t, "user-2", &models.AddExpenseRequest{Amount: 60000, Description: "Dinner", SplitType: models.SplitTypeExact,
+		Splits: []models.ExpenseSplit{{MemberID: f.member(1), Amount: 20000}, {MemberID: f.member(2), Amount: 40000}}})
+
+	balances, err := f.service.GetBalances(context.Background(), "user-3", f.group.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Alice paid 3000 and owes 1000; Bob paid 600 and owes 1200; Carol owes 1400
+	expected := []int64{200000, -60000, -140000}
+	for i, b := range balances.Balances {
+		if b.Net != expected[i] {
+			t.Errorf("expected %s net %d, got %d", b.DisplayName, expected[i], b.Net)
+		}
+	}
+	if len(balances.Transfers) != 2 {
+		t.Errorf("expected 2 settle-up transfers, got %+v", balances.Transfers)
+	}
+}
+
+func TestSettle_PaysDebtsAndClearsBalance(t *testing.T) {
+	f := newExpenseGroupFixture(t)
+	f.addExpense(t, "user-1", &models.AddExpenseRequest{Amount: 90000, Description: "Groceries", SplitType: models.SplitTypeEqual})
+
+	settlements, err := f.service.Settle(context.Background(), "user-2", f.group.ID, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(settlements) != 1 || settlements[0].Status != models.SettlementStatusCompleted {
+		t.Fatalf("expected 1 completed settlement, got %+v", settlements)
+	}
+	transfer := f.transfers.requests[0]
+	if transfer.SourceWalletID != "wallet-2" || transfer.DestinationWalletID != "wallet-1" || transfer.Amount != 30000 {
+		t.Errorf("expected 300.00 from Bob to Alice, got %+v", transfer)
+	}
+	if f.transfers.keys[0] != "group-settlement-"+settlements[0].ID {
+		t.Errorf("expected idempotency key per settlement, got %s", f.transfers.keys[0])
+	}
+
+	balances, _ := f.service.GetBalances(context.Background(), "user-2", f.group.ID)
+	if balances.Balances[1].Net != 0 || balances.Balances[0].Net != 30000 {
+		t.Errorf("expected Bob settled and Alice owed only by Carol, got %+v %+v", balances.Balances[0], balances.Balances[1])
+	}
+
+	if _, err := f.service.Settle(context.Background(), "user-2", f.group.ID, ""); err == nil || err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected bad request settling again, got %v", err)
+	}
+}
+
+func TestSettle_FailedTransferKeepsDebt(t *testing.T) {
+	f := newExpenseGroupFixture(t)
+	f.addExpense(t, "user-1", &models.AddExpenseRequest{Amount: 90000, Description: "Groceries", SplitType: models.SplitTypeEqual})
+	f.transfers.status = "failed"
+
+	settlements, err := f.service.Settle(context.Background(), "user-3", f.group.ID, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if settlements[0].Status != models.SettlementStatusFailed || *settlements[0].FailureReason != "insufficient balance" {
+		t.Errorf("expected failed settlement with reason, got %+v", settlements[0])
+	}
+	balances, _ := f.service.GetBalances(context.Background(), "user-3", f.group.ID)
+	if balances.Balances[2].Net != -30000 {
+		t.Errorf("expected Carol still owing, got %d", balances.Balances[2].Net)
+	}
+}
+
+func TestSettle_HighValueForwardsVerificationToken(t *testing.T) {
+	f := newExpenseGroupFixture(t)
+	f.addExpense(t, "user-1", &models.AddExpenseRequest{Amount: 4500000, Description: "Villa", SplitType: models.SplitTypeEqual})
+
+	settlements, err := f.service.Settle(context.Background(), "user-2", f.group.ID, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if settlements[0].Status != models.SettlementStatusFailed || *settlements[0].FailureReason != "this operation requires verification" {
+		t.Fatalf("expected settlement failed for want of verification, got %+v", settlements[0])
+	}
+
+	settlements, err = f.service.Settle(context.Background(), "user-2", f.group.ID, "ver-token")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(settlements) != 1 || settlements[0].Status != models.SettlementStatusCompleted {
+		t.Fatalf("expected 1 completed settlement, got %+v", settlements)
+	}
+	if transfer := f.transfers.requests[0]; transfer.Amount != 1500000 || transfer.VerificationToken != "ver-token" {
+		t.Errorf("expected 15000.00 transfer with the verification token, got %+v", transfer)
+	}
+}
+
+func TestSettle_UnknownOutcomeRetriedWithSameKey(t *testing.T) {
+	f := newExpenseGroupFixture(t)
+	f.addExpense(t, "user-1", &models.AddExpenseRequest{Amount: 90000, Description: "Groceries", SplitType: models.SplitTypeEqual})
+	f.transfers.err = errors.Internal("request failed: timeout")
+
+	settlements, err := f.service.Settle(context.Background(), "user-2", f.group.ID, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if settlements[0].Status != models.SettlementStatusPending {
+		t.Fatalf("expected settlement left pending, got %s", settlements[0].Status)
+	}
+
+	f.transfers.err = nil
+	settlements, err = f.service.Settle(context.Background(), "user-2", f.group.ID, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(settlements) != 1 || settlements[0].Status != models.SettlementStatusCompleted {
+		t.Fatalf("expected the pending settlement completed, got %+v", settlements)
+	}
+	if f.transfers.keys[0] != f.transfers.keys[1] {
+		t.Errorf("expected retry with the same idempotency key, got %v", f.transfers.keys)
+	}
+	if len(f.transfers.requests) != 1 {
+		t.Errorf("expected 1 transfer, got %d", len(f.transfers.requests))
+	}
+}
@@ -47,6 +47,15 @@ func (c *IdentityClient) LookupUserByPhone(ctx context.Context, phone string) (*
 	return &result, nil
 }
 
+// GetCurrentUser retrieves the authenticated user, forwarding their JWT.
+func (c *IdentityClient) GetCurrentUser(ctx context.Context) (*UserInfo, *errors.Error) {
+	var result UserInfo
+	if err := c.GetWithHeaders(ctx, "/api/v1/users/me", &result, getAuthHeaders(ctx)); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // userKYCResponse is used to parse the nested KYC status response.
 type userKYCResponse struct {
 	KYC struct {
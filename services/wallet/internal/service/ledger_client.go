@@ -6,6 +6,7 @@ import (
 
 	"github.com/vnykmshr/nivo/shared/clients"
 	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 )
 
 // LedgerAccount represents a ledger account from the ledger service.
@@ -33,15 +34,15 @@ type LedgerClient struct {
 	*clients.BaseClient
 }
 
-// NewLedgerClient creates a new ledger service client.
-func NewLedgerClient(baseURL string) *LedgerClient {
+// NewLedgerClient creates a ledger service client authenticated with service tokens.
+func NewLedgerClient(baseURL string, tokens clients.ServiceTokenSource) *LedgerClient {
 	return &LedgerClient{
-		BaseClient: clients.NewBaseClient(baseURL, clients.DefaultTimeout),
+		BaseClient: clients.NewInternalClient(baseURL, clients.DefaultTimeout, tokens, sharedjwt.ServiceLedger),
 	}
 }
 
 // CreateAccount creates a new ledger account.
-// Uses internal endpoint for service-to-service communication (no auth required).
+// Uses internal endpoint for service-to-service communication.
 func (c *LedgerClient) CreateAccount(ctx context.Context, req *CreateLedgerAccountRequest) (*LedgerAccount, *errors.Error) {
 	var result LedgerAccount
 	if err := c.Post(ctx, "/internal/v1/accounts", req, &result); err != nil {
@@ -75,3 +76,23 @@ func (c *LedgerClient) GetAccountByCode(ctx context.Context, code string) (*Ledg
 	}
 	return &result, nil
 }
+
+// LedgerAccountBalance represents the current balance of a ledger account.
+type LedgerAccountBalance struct {
+	AccountID   string `json:"account_id"`
+	DebitTotal  int64  `json:"debit_total"`
+	CreditTotal int64  `json:"credit_total"`
+	Balance     int64  `json:"balance"`
+}
+
+// GetAccountBalances retrieves the current balances of several ledger accounts.
+// Uses internal endpoint for service-to-service communication.
+// Accounts that do not exist are absent from the result.
+func (c *LedgerClient) GetAccountBalances(ctx context.Context, accountIDs []string) ([]LedgerAccountBalance, *errors.Error) {
+	req := map[string][]string{"account_ids": accountIDs}
+	var result []LedgerAccountBalance
+	if err := c.Post(ctx, "/internal/v1/accounts/balances", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
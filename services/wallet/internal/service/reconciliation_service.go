@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// reconciliationBatchSize is the number of wallets compared per round trip
+// to the ledger and transaction services.
+const reconciliationBatchSize = 100
+
+// Severity thresholds in the smallest currency unit (paise).
+const (
+	lowSeverityMaxDifference    int64 = 100       // ₹1
+	mediumSeverityMaxDifference int64 = 10_000    // ₹100
+	highSeverityMaxDifference   int64 = 1_000_000 // ₹10,000
+)
+
+// ReconciliationWalletSource pages through every wallet for reconciliation.
+type ReconciliationWalletSource interface {
+	ListForReconciliation(ctx context.Context, afterID string, limit int) ([]*models.Wallet, *errors.Error)
+}
+
+// ReconciliationRepositoryInterface defines the interface for reconciliation repository operations.
+type ReconciliationRepositoryInterface interface {
+	CreateRun(ctx context.Context, run *models.ReconciliationRun) *errors.Error
+	FinishRun(ctx context.Context, run *models.ReconciliationRun) *errors.Error
+	ListRuns(ctx context.Context, limit, offset int) ([]*models.ReconciliationRun, int, *errors.Error)
+	UpsertDiscrepancy(ctx context.Context, d *models.ReconciliationDiscrepancy) *errors.Error
+	ResolveMatched(ctx context.Context, walletIDs []string) (int64, *errors.Error)
+	ListDiscrepancies(ctx context.Context, filter *models.DiscrepancyFilter, limit, offset int) ([]*models.ReconciliationDiscrepancy, int, *errors.Error)
+	GetDiscrepancy(ctx context.Context, id string) (*models.ReconciliationDiscrepancy, *errors.Error)
+	ResolveDiscrepancy(ctx context.Context, id, resolvedBy, note string) *errors.Error
+	CountOpenBySeverity(ctx context.Context) (map[models.DiscrepancySeverity]int, *errors.Error)
+}
+
+// LedgerBalanceReader reads current ledger account balances.
+type LedgerBalanceReader interface {
+	GetAccountBalances(ctx context.Context, accountIDs []string) ([]LedgerAccountBalance, *errors.Error)
+}
+
+// TransactionTotalsReader reads settled transaction totals per wallet.
+type TransactionTotalsReader interface {
+	GetWalletTotals(ctx context.Context, walletIDs []string) ([]WalletTransactionTotals, *errors.Error)
+}
+
+// DiscrepancyGauge publishes the number of open discrepancies per severity.
+type DiscrepancyGauge interface {
+	SetReconciliationDiscrepancies(serviceName, severity string, count int)
+}
+
+// ReconciliationService compares every wallet balance against its ledger
+// account and the net of its settled transactions.
+type ReconciliationService struct {
+	walletSource ReconciliationWalletSource
+	reconRepo    ReconciliationRepositoryInterface
+	ledger       LedgerBalanceReader
+	transactions TransactionTotalsReader
+	gauge        DiscrepancyGauge
+
+	// running guards against overlapping runs (scheduled and manual)
+	running sync.Mutex
+}
+
+// NewReconciliationService creates a new reconciliation service.
+func NewReconciliationService(
+	walletSource ReconciliationWalletSource,
+	reconRepo ReconciliationRepositoryInterface,
+	ledger LedgerBalanceReader,
+	transactions TransactionTotalsReader,
+	gauge DiscrepancyGauge,
+) *ReconciliationService {
+	return &ReconciliationService{
+		walletSource: walletSource,
+		reconRepo:    reconRepo,
+		ledger:       ledger,
+		transactions: transactions,
+		gauge:        gauge,
+	}
+}
+
+// RunReconciliation reconciles every wallet in batches. Mismatches are recorded
+// as open discrepancies (one per wallet, refreshed on later runs) and open
+// discrepancies of wallets that now match are resolved automatically.
+//
+// Balances are read from three services without a shared snapshot, so a
+// transfer in flight can show up as a transient mismatch that the next run clears.
+func (s *ReconciliationService) RunReconciliation(ctx context.Context, trigger models.ReconciliationTrigger) (*models.ReconciliationRun, *errors.Error) {
+	if !s.running.TryLock() {
+		return nil, errors.Conflict("a reconciliation run is already in progress")
+	}
+	defer s.running.Unlock()
+
+	run := &models.ReconciliationRun{
+		Status:  models.ReconciliationRunRunning,
+		Trigger: trigger,
+	}
+	if err := s.reconRepo.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	runErr := s.reconcileAll(ctx, run)
+	if runErr != nil {
+		run.Status = models.ReconciliationRunFailed
+		msg := runErr.Error()
+		run.ErrorMessage = &msg
+	} else {
+		run.Status = models.ReconciliationRunCompleted
+	}
+
+	if err := s.reconRepo.FinishRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	if runErr != nil {
+		return nil, runErr
+	}
+
+	if err := s.refreshGauge(ctx); err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+func (s *ReconciliationService) reconcileAll(ctx context.Context, run *models.ReconciliationRun) *errors.Error {
+	afterID := ""
+	for {
+		wallets, err := s.walletSource.ListForReconciliation(ctx, afterID, reconciliationBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(wallets) == 0 {
+			return nil
+		}
+
+		found, batchErr := s.reconcileBatch(ctx, run.ID, wallets)
+		if batchErr != nil {
+			return batchErr
+		}
+
+		run.WalletsChecked += len(wallets)
+		run.DiscrepanciesFound += found
+
+		if len(wallets) < reconciliationBatchSize {
+			return nil
+		}
+		afterID = wallets[len(wallets)-1].ID
+	}
+}
+
+func (s *ReconciliationService) reconcileBatch(ctx context.Context, runID string, wallets []*models.Wallet) (int, *errors.Error) {
+	walletIDs := make([]string, len(wallets))
+	accountIDs := make([]string, len(wallets))
+	for i, w := range wallets {
+		walletIDs[i] = w.ID
+		accountIDs[i] = w.LedgerAccountID
+	}
+
+	balances, err := s.ledger.GetAccountBalances(ctx, accountIDs)
+	if err != nil {
+		return 0, err
+	}
+	ledgerByAccount := make(map[string]int64, len(balances))
+	for _, b := range balances {
+		ledgerByAccount[b.AccountID] = b.Balance
+	}
+
+	totals, err := s.transactions.GetWalletTotals(ctx, walletIDs)
+	if err != nil {
+		return 0, err
+	}
+	netByWallet := make(map[string]int64, len(totals))
+	for _, t := range totals {
+		netByWallet[t.WalletID] = t.Net
+	}
+
+	found := 0
+	matched := make([]string, 0, len(wallets))
+	for _, w := range wallets {
+		d := compareWallet(w, ledgerByAccount, netByWallet[w.ID])
+		if d == nil {
+			matched = append(matched, w.ID)
+			continue
+		}
+
+		d.LastRunID = runID
+		if err := s.reconRepo.UpsertDiscrepancy(ctx, d); err != nil {
+			return 0, err
+		}
+		found++
+	}
+
+	if _, err := s.reconRepo.ResolveMatched(ctx, matched); err != nil {
+		return 0, err
+	}
+
+	return found, nil
+}
+
+// compareWallet returns a discrepancy for w, or nil when the wallet balance
+// equals both its ledger balance and its transaction net.
+func compareWallet(w *models.Wallet, ledgerByAccount map[string]int64, transactionNet int64) *models.ReconciliationDiscrepancy {
+	d := &models.ReconciliationDiscrepancy{
+		WalletID:              w.ID,
+		LedgerAccountID:       w.LedgerAccountID,
+		WalletBalance:         w.Balance,
+		TransactionNet:        transactionNet,
+		TransactionDifference: w.Balance - transactionNet,
+	}
+
+	ledgerBalance, ok := ledgerByAccount[w.LedgerAccountID]
+	if !ok {
+		// A wallet without a ledger account cannot be trusted at any amount
+		d.LedgerDifference = w.Balance
+		d.Severity = models.DiscrepancySeverityCritical
+		return d
+	}
+
+	d.LedgerBalance = &ledgerBalance
+	d.LedgerDifference = w.Balance - ledgerBalance
+
+	if d.LedgerDifference == 0 && d.TransactionDifference == 0 {
+		return nil
+	}
+
+	d.Severity = classifySeverity(max(absInt64(d.LedgerDifference), absInt64(d.TransactionDifference)))
+	return d
+}
+
+// classifySeverity ranks a mismatch by its absolute size.
+func classifySeverity(difference int64) models.DiscrepancySeverity {
+	switch {
+	case difference <= lowSeverityMaxDifference:
+		return models.DiscrepancySeverityLow
+	case difference <= mediumSeverityMaxDifference:
+		return models.DiscrepancySeverityMedium
+	case difference <= highSeverityMaxDifference:
+		return models.DiscrepancySeverityHigh
+	default:
+		return models.DiscrepancySeverityCritical
+	}
+}
+
+func absInt64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// refreshGauge publishes the open discrepancy count for every severity,
+// including zeroes so resolved severities drop back on the dashboard.
+func (s *ReconciliationService) refreshGauge(ctx context.Context) *errors.Error {
+	if s.gauge == nil {
+		return nil
+	}
+
+	counts, err := s.reconRepo.CountOpenBySeverity(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, severity := range models.AllDiscrepancySeverities {
+		s.gauge.SetReconciliationDiscrepancies("wallet", string(severity), counts[severity])
+	}
+
+	return nil
+}
+
+// ListDiscrepancies retrieves discrepancies matching the filter with the total count.
+func (s *ReconciliationService) ListDiscrepancies(ctx context.Context, filter *models.DiscrepancyFilter, limit, offset int) ([]*models.ReconciliationDiscrepancy, int, *errors.Error) {
+	if filter.Status != nil {
+		switch *filter.Status {
+		case models.DiscrepancyStatusOpen, models.DiscrepancyStatusResolved:
+		default:
+			return nil, 0, errors.Validation("status must be one of: open, resolved")
+		}
+	}
+
+	if filter.Severity != nil && !isValidSeverity(*filter.Severity) {
+		return nil, 0, errors.Validation("severity must be one of: low, medium, high, critical")
+	}
+
+	return s.reconRepo.ListDiscrepancies(ctx, filter, limit, offset)
+}
+
+func isValidSeverity(severity models.DiscrepancySeverity) bool {
+	for _, s := range models.AllDiscrepancySeverities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDiscrepancy retrieves a discrepancy by ID.
+func (s *ReconciliationService) GetDiscrepancy(ctx context.Context, id string) (*models.ReconciliationDiscrepancy, *errors.Error) {
+	return s.reconRepo.GetDiscrepancy(ctx, id)
+}
+
+// ResolveDiscrepancy marks a discrepancy as resolved by an operator after the
+// underlying balances have been corrected or explained.
+func (s *ReconciliationService) ResolveDiscrepancy(ctx context.Context, id, resolvedBy, note string) (*models.ReconciliationDiscrepancy, *errors.Error) {
+	d, err := s.reconRepo.GetDiscrepancy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Status != models.DiscrepancyStatusOpen {
+		return nil, errors.BadRequest("reconciliation discrepancy is already resolved")
+	}
+
+	if err := s.reconRepo.ResolveDiscrepancy(ctx, id, resolvedBy, note); err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshGauge(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.reconRepo.GetDiscrepancy(ctx, id)
+}
+
+// ListRuns retrieves reconciliation runs with the total count.
+func (s *ReconciliationService) ListRuns(ctx context.Context, limit, offset int) ([]*models.ReconciliationRun, int, *errors.Error) {
+	return s.reconRepo.ListRuns(ctx, limit, offset)
+}
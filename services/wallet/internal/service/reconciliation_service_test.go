@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// Mock implementations for testing
+
+type mockReconciliationWalletSource struct {
+	wallets []*models.Wallet // ordered by ID
+}
+
+func (m *mockReconciliationWalletSource) ListForReconciliation(ctx context.Context, afterID string, limit int) ([]*models.Wallet, *errors.Error) {
+	result := make([]*models.Wallet, 0, limit)
+	for _, w := range m.wallets {
+		if w.ID > afterID && len(result) < limit {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+type mockReconciliationRepository struct {
+	runs          []*models.ReconciliationRun
+	discrepancies map[string]*models.ReconciliationDiscrepancy // keyed by ID
+	nextID        int
+}
+
+func newMockReconciliationRepository() *mockReconciliationRepository {
+	return &mockReconciliationRepository{
+		discrepancies: make(map[string]*models.ReconciliationDiscrepancy),
+	}
+}
+
+func (m *mockReconciliationRepository) CreateRun(ctx context.Context, run *models.ReconciliationRun) *errors.Error {
+	m.nextID++
+	run.ID = fmt.Sprintf("run-%d", m.nextID)
+	m.runs = append(m.runs, run)
+	return nil
+}
+
+func (m *mockReconciliationRepository) FinishRun(ctx context.Context, run *models.ReconciliationRun) *errors.Error {
+	return nil
+}
+
+func (m *mockReconciliationRepository) ListRuns(ctx context.Context, limit, offset int) ([]*models.ReconciliationRun, int, *errors.Error) {
+	return m.runs, len(m.runs), nil
+}
+
+func (m *mockReconciliationRepository) openFor(walletID string) *models.ReconciliationDiscrepancy {
+	for _, d := range m.discrepancies {
+		if d.WalletID == walletID && d.Status == models.DiscrepancyStatusOpen {
+			return d
+		}
+	}
+	return nil
+}
+
+func (m *mockReconciliationRepository) UpsertDiscrepancy(ctx context.Context, d *models.ReconciliationDiscrepancy) *errors.Error {
+	if existing := m.openFor(d.WalletID); existing != nil {
+		d.ID = existing.ID
+		d.FirstRunID = existing.FirstRunID
+	} else {
+		m.nextID++
+		d.ID = fmt.Sprintf("disc-%d", m.nextID)
+		d.FirstRunID = d.LastRunID
+	}
+	d.Status = models.DiscrepancyStatusOpen
+	m.discrepancies[d.ID] = d
+	return nil
+}
+
+func (m *mockReconciliationRepository) ResolveMatched(ctx context.Context, walletIDs []string) (int64, *errors.Error) {
+	var resolved int64
+	for _, id := range walletIDs {
+		if d := m.openFor(id); d != nil {
+			d.Status = models.DiscrepancyStatusResolved
+			resolved++
+		}
+	}
+	return resolved, nil
+}
+
+func (m *mockReconciliationRepository) ListDiscrepancies(ctx context.Context, filter *models.DiscrepancyFilter, limit, offset int) ([]*models.ReconciliationDiscrepancy, int, *errors.Error) {
+	result := make([]*models.ReconciliationDiscrepancy, 0)
+	for _, d := range m.discrepancies {
+		if filter.Status != nil && d.Status != *filter.Status {
+			continue
+		}
+		result = append(result, d)
+	}
+	return result, len(result), nil
+}
+
+func (m *mockReconciliationRepository) GetDiscrepancy(ctx context.Context, id string) (*models.ReconciliationDiscrepancy, *errors.Error) {
+	d, ok := m.discrepancies[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("reconciliation discrepancy", id)
+	}
+	return d, nil
+}
+
+func (m *mockReconciliationRepository) ResolveDiscrepancy(ctx context.Context, id, resolvedBy, note string) *errors.Error {
+	d := m.discrepancies[id]
+	d.Status = models.DiscrepancyStatusResolved
+	d.ResolvedBy = &resolvedBy
+	d.ResolutionNote = &note
+	return nil
+}
+
+func (m *mockReconciliationRepository) CountOpenBySeverity(ctx context.Context) (map[models.DiscrepancySeverity]int, *errors.Error) {
+	counts := make(map[models.DiscrepancySeverity]int)
+	for _, d := range m.discrepancies {
+		if d.Status == models.DiscrepancyStatusOpen {
+			counts[d.Severity]++
+		}
+	}
+	return counts, nil
+}
+
+type mockLedgerBalanceReader struct {
+	balances map[string]int64 // keyed by ledger account ID
+}
+
+func (m *mockLedgerBalanceReader) GetAccountBalances(ctx context.Context, accountIDs []string) ([]LedgerAccountBalance, *errors.Error) {
+	result := make([]LedgerAccountBalance, 0)
+	for _, id := range accountIDs {
+		if balance, ok := m.balances[id]; ok {
+			result = append(result, LedgerAccountBalance{AccountID: id, Balance: balance})
+		}
+	}
+	return result, nil
+}
+
+type mockTransactionTotalsReader struct {
+	net map[string]int64 // keyed by wallet ID
+	err *errors.Error
+}
+
+func (m *mockTransactionTotalsReader) GetWalletTotals(ctx context.Context, walletIDs []string) ([]WalletTransactionTotals, *errors.Error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := make([]WalletTransactionTotals, 0, len(walletIDs))
+	for _, id := range walletIDs {
+		result = append(result, WalletTransactionTotals{WalletID: id, Net: m.net[id]})
+	}
+	return result, nil
+}
+
+type mockDiscrepancyGauge struct {
+	values map[string]int
+}
+
+func (m *mockDiscrepancyGauge) SetReconciliationDiscrepancies(serviceName, severity string, count int) {
+	m.values[severity] = count
+}
+
+type reconciliationFixture struct {
+	wallets      *mockReconciliationWalletSource
+	repo         *mockReconciliationRepository
+	ledger       *mockLedgerBalanceReader
+	transactions *mockTransactionTotalsReader
+	gauge        *mockDiscrepancyGauge
+	service      *ReconciliationService
+}
+
+func newReconciliationFixture() *reconciliationFixture {
+	f := &reconciliationFixture{
+		wallets:      &mockReconciliationWalletSource{},
+		repo:         newMockReconciliationRepository(),
+		ledger:       &mockLedgerBalanceReader{balances: make(map[string]int64)},
+		transactions: &mockTransactionTotalsReader{net: make(map[string]int64)},
+		gauge:        &mockDiscrepancyGauge{values: make(map[string]int)},
+	}
+	f.service = NewReconciliationService(f.wallets, f.repo, f.ledger, f.transactions, f.gauge)
+	return f
+}
+
+// addWallet registers a wallet with the given balance on all three sides.
+func (f *reconciliationFixture) addWallet(id string, walletBalance, ledgerBalance, transactionNet int64) {
+	accountID := "acct-" + id
+	f.wallets.wallets = append(f.wallets.wallets, &models.Wallet{
+		ID:              id,
+		Balance:         walletBalance,
+		LedgerAccountID: accountID,
+	})
+	f.ledger.balances[accountID] = ledgerBalance
+	f.transactions.net[id] = transactionNet
+}
+
+// Test cases
+
+func TestRunReconciliation_AllMatch(t *testing.T) {
+	f := newReconciliationFixture()
+	f.addWallet("w-01", 5000, 5000, 5000)
+	f.addWallet("w-02", 0, 0, 0)
+
+	run, err := f.service.RunReconciliation(context.Background(), models.ReconciliationTriggerManual)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if run.Status != models.ReconciliationRunCompleted {
+		t.Errorf("Expected completed run, got %s", run.Status)
+	}
+	if run.WalletsChecked != 2 {
+		t.Errorf("Expected 2 wallets checked, got %d", run.WalletsChecked)
+	}
+	if run.DiscrepanciesFound != 0 {
+		t.Errorf("Expected no discrepancies, got %d", run.DiscrepanciesFound)
+	}
+	if f.gauge.values["critical"] != 0 || len(f.gauge.values) != len(models.AllDiscrepancySeverities) {
+		t.Errorf("Expected zero gauge for every severity, got %v", f.gauge.values)
+	}
+}
+
+func TestRunReconciliation_RecordsDiscrepancies(t *testing.T) {
+	f := newReconciliationFixture()
+	f.addWallet("w-01", 5000, 5000, 5000)
+	f.addWallet("w-02", 5050, 5000, 5000)       // 50 paise off the ledger
+	f.addWallet("w-03", 500000, 500000, 400000) // ₹1,000 off the transactions
+	f.addWallet("w-04", 1000, 0, 1000)
+	delete(f.ledger.balances, "acct-w-04") // ledger account missing
+
+	run, err := f.service.RunReconciliation(context.Background(), models.ReconciliationTriggerScheduled)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if run.DiscrepanciesFound != 3 {
+		t.Fatalf("Expected 3 discrepancies, got %d", run.DiscrepanciesFound)
+	}
+
+	low := f.repo.openFor("w-02")
+	if low == nil || low.Severity != models.DiscrepancySeverityLow || low.LedgerDifference != 50 {
+		t.Errorf("Expected low ledger discrepancy of 50 for w-02, got %+v", low)
+	}
+
+	high := f.repo.openFor("w-03")
+	if high == nil || high.Severity != models.DiscrepancySeverityHigh || high.TransactionDifference != 100000 {
+		t.Errorf("Expected high transaction discrepancy of 100000 for w-03, got %+v", high)
+	}
+
+	missing := f.repo.openFor("w-04")
+	if missing == nil || missing.Severity != models.DiscrepancySeverityCritical || missing.LedgerBalance != nil {
+		t.Errorf("Expected critical discrepancy with no ledger balance for w-04, got %+v", missing)
+	}
+
+	if f.gauge.values["low"] != 1 || f.gauge.values["high"] != 1 || f.gauge.values["critical"] != 1 {
+		t.Errorf("Unexpected gauge values %v", f.gauge.values)
+	}
+}
+
+func TestRunReconciliation_ReusesAndAutoResolvesDiscrepancy(t *testing.T) {
+	f := newReconciliationFixture()
+	f.addWallet("w-01", 5050, 5000, 5050)
+
+	if _, err := f.service.RunReconciliation(context.Background(), models.ReconciliationTriggerScheduled); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	first := f.repo.openFor("w-01")
+
+	// Still mismatched: the same discrepancy is refreshed
+	if _, err := f.service.RunReconciliation(context.Background(), models.ReconciliationTriggerScheduled); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second := f.repo.openFor("w-01")
+	if second == nil || second.ID != first.ID || second.FirstRunID != "run-1" {
+		t.Fatalf("Expected discrepancy %s to be refreshed, got %+v", first.ID, second)
+	}
+
+	// Ledger catches up: the discrepancy is resolved
+	f.ledger.balances["acct-w-01"] = 5050
+	if _, err := f.service.RunReconciliation(context.Background(), models.ReconciliationTriggerScheduled); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.repo.openFor("w-01") != nil {
+		t.Error("Expected discrepancy to be resolved once balances match")
+	}
+	if f.gauge.values["low"] != 0 {
+		t.Errorf("Expected low gauge to drop to 0, got %d", f.gauge.values["low"])
+	}
+}
+
+func TestRunReconciliation_Batches(t *testing.T) {
+	f := newReconciliationFixture()
+	for i := 0; i < reconciliationBatchSize*2+5; i++ {
+		f.addWallet(fmt.Sprintf("w-%04d", i), 100, 100, 100)
+	}
+
+	run, err := f.service.RunReconciliation(context.Background(), models.ReconciliationTriggerScheduled)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if run.WalletsChecked != reconciliationBatchSize*2+5 {
+		t.Errorf("Expected %d wallets checked, got %d", reconciliationBatchSize*2+5, run.WalletsChecked)
+	}
+}
+
+func TestRunReconciliation_UpstreamFailureMarksRunFailed(t *testing.T) {
+	f := newReconciliationFixture()
+	f.addWallet("w-01", 100, 100, 100)
+	f.transactions.err = errors.Internal("transaction service unavailable")
+
+	_, err := f.service.RunReconciliation(context.Background(), models.ReconciliationTriggerScheduled)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	run := f.repo.runs[0]
+	if run.Status != models.ReconciliationRunFailed || run.ErrorMessage == nil {
+		t.Errorf("Expected failed run with error message, got %+v", run)
+	}
+}
+
+func TestRunReconciliation_RejectsOverlappingRun(t *testing.T) {
+	f := newReconciliationFixture()
+
+	f.service.running.Lock()
+	defer f.service.running.Unlock()
+
+	_, err := f.service.RunReconciliation(context.Background(), models.ReconciliationTriggerManual)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if err.Code != errors.ErrCodeConflict {
+		t.Errorf("Expected conflict error, got %s", err.Code)
+	}
+}
+
+func TestClassifySeverity(t *testing.T) {
+	tests := []struct {
+		difference int64
+		want       models.DiscrepancySeverity
+	}{
+		{1, models.DiscrepancySeverityLow},
+		{100, models.DiscrepancySeverityLow},
+		{101, models.DiscrepancySeverityMedium},
+		{10_000, models.DiscrepancySeverityMedium},
+		{10_001, models.DiscrepancySeverityHigh},
+		{1_000_000, models.DiscrepancySeverityHigh},
+		{1_000_001, models.DiscrepancySeverityCritical},
+	}
+
+	for _, tt := range tests {
+		if got := classifySeverity(tt.difference); got != tt.want {
+			t.Errorf("classifySeverity(%d) = %s, want %s", tt.difference, got, tt.want)
+		}
+	}
+}
+
+func TestResolveDiscrepancy(t *testing.T) {
+	f := newReconciliationFixture()
+	f.addWallet("w-01", 5050, 5000, 5050)
+
+	if _, err := f.service.RunReconciliation(context.Background(), models.ReconciliationTriggerScheduled); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	d := f.repo.openFor("w-01")
+
+	resolved, err := f.service.ResolveDiscrepancy(context.Background(), d.ID, "admin-1", "ledger adjusted manually")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resolved.Status != models.DiscrepancyStatusResolved || *resolved.ResolvedBy != "admin-1" {
+		t.Errorf("Expected discrepancy resolved by admin-1, got %+v", resolved)
+	}
+	if f.gauge.values["low"] != 0 {
+		t.Errorf("Expected low gauge to drop to 0, got %d", f.gauge.values["low"])
+	}
+
+	// Resolving twice is rejected
+	_, err = f.service.ResolveDiscrepancy(context.Background(), d.ID, "admin-1", "ledger adjusted manually")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestListDiscrepancies_InvalidSeverity(t *testing.T) {
+	f := newReconciliationFixture()
+
+	severity := models.DiscrepancySeverity("urgent")
+	_, _, err := f.service.ListDiscrepancies(context.Background(), &models.DiscrepancyFilter{Severity: &severity}, 20, 0)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if err.Code != errors.ErrCodeValidation {
+		t.Errorf("Expected validation error, got %s", err.Code)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vnykmshr/nivo/shared/clients"
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
+	"github.com/vnykmshr/nivo/shared/middleware"
+)
+
+// WalletTransactionTotals represents the settled transaction totals for a wallet.
+type WalletTransactionTotals struct {
+	WalletID         string `json:"wallet_id"`
+	Credits          int64  `json:"credits"`
+	Debits           int64  `json:"debits"`
+	Net              int64  `json:"net"`
+	TransactionCount int    `json:"transaction_count"`
+}
+
+// SettlementTransferRequest is a transfer created on behalf of the paying user.
+type SettlementTransferRequest struct {
+	SourceWalletID      string `json:"source_wallet_id"`
+	DestinationWalletID string `json:"destination_wallet_id"`
+	Amount              int64  `json:"amount"`
+	Currency            string `json:"currency"`
+	Description         string `json:"description"`
+	Reference           string `json:"reference,omitempty"`
+	VerificationToken   string `json:"verification_token,omitempty"` // Required above the high-value threshold
+}
+
+// TransferResult is the outcome of a transfer created by the transaction service.
+type TransferResult struct {
+	ID            string  `json:"id"`
+	Status        string  `json:"status"`
+	FailureReason *string `json:"failure_reason,omitempty"`
+}
+
+// TransactionClient handles communication with the transaction service.
+type TransactionClient struct {
+	*clients.BaseClient
+}
+
+// NewTransactionClient creates a transaction service client authenticated with service tokens.
+func NewTransactionClient(baseURL string, tokens clients.ServiceTokenSource) *TransactionClient {
+	return &TransactionClient{
+		BaseClient: clients.NewInternalClient(baseURL, clients.DefaultTimeout, tokens, sharedjwt.ServiceTransaction),
+	}
+}
+
+// GetWalletTotals retrieves settled transaction totals for several wallets.
+// Uses internal endpoint for service-to-service communication.
+func (c *TransactionClient) GetWalletTotals(ctx context.Context, walletIDs []string) ([]WalletTransactionTotals, *errors.Error) {
+	req := map[string][]string{"wallet_ids": walletIDs}
+	var result []WalletTransactionTotals
+	if err := c.Post(ctx, "/internal/v1/transactions/wallet-totals", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateTransfer creates a transfer on behalf of the authenticated user, forwarding
+// their JWT. The idempotency key makes retries of the same transfer safe.
+func (c *TransactionClient) CreateTransfer(ctx context.Context, req *SettlementTransferRequest, idempotencyKey string) (*TransferResult, *errors.Error) {
+	headers := getAuthHeaders(ctx)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	headers[middleware.IdempotencyKeyHeader] = idempotencyKey
+
+	var result TransferResult
+	if err := c.PostWithHeaders(ctx, "/api/v1/transactions/transfer", req, &result, headers); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+// Approval operations for wallet changes that need a second person: status
+// changes requested by an admin, and limit increases requested by the owner.
+const (
+	OperationUnfreeze       = "wallet.unfreeze"
+	OperationClose          = "wallet.close"
+	OperationLimitsIncrease = "wallet.limits_increase"
+)
+
+// SetApprovals sets the workflow through which unfreezes, closures and limit
+// increases are requested and registers the operations with it.
+func (s *WalletService) SetApprovals(workflow *approval.Workflow) {
+	s.approvals = workflow
+	workflow.Register(approval.Operation{
+		Name:         OperationUnfreeze,
+		ResourceType: "wallet",
+		Permission:   "wallet:wallet:unfreeze",
+		Execute: func(ctx context.Context, req *approval.Request) (any, *errors.Error) {
+			return s.UnfreezeWallet(ctx, req.ResourceID)
+		},
+	})
+	workflow.Register(approval.Operation{
+		Name:         OperationClose,
+		ResourceType: "wallet",
+		Permission:   "wallet:wallet:close",
+		Execute: func(ctx context.Context, req *approval.Request) (any, *errors.Error) {
+			var payload models.CloseWalletRequest
+			if err := json.Unmarshal(req.Payload, &payload); err != nil {
+				return nil, errors.InternalWrap(err, "invalid wallet closure payload")
+			}
+			return s.CloseWallet(ctx, req.ResourceID, payload.Reason)
+		},
+	})
+	workflow.Register(approval.Operation{
+		Name:         OperationLimitsIncrease,
+		ResourceType: "wallet",
+		Permission:   "wallet:wallet:limits_increase",
+		Execute: func(ctx context.Context, req *approval.Request) (any, *errors.Error) {
+			var payload models.UpdateLimitsRequest
+			if err := json.Unmarshal(req.Payload, &payload); err != nil {
+				return nil, errors.InternalWrap(err, "invalid wallet limits payload")
+			}
+			return s.applyWalletLimits(ctx, req.ResourceID, &payload)
+		},
+	})
+}
+
+// RequestUnfreeze submits unfreezing a wallet for approval by a second admin.
+func (s *WalletService) RequestUnfreeze(ctx context.Context, walletID string) (*approval.Request, *errors.Error) {
+	wallet, err := s.approvableWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if checkErr := checkUnfreezable(wallet); checkErr != nil {
+		return nil, checkErr
+	}
+
+	return s.approvals.Submit(ctx, OperationUnfreeze, walletID, struct{}{})
+}
+
+// RequestClose submits closing a wallet for approval by a second admin.
+func (s *WalletService) RequestClose(ctx context.Context, walletID, reason string) (*approval.Request, *errors.Error) {
+	wallet, err := s.approvableWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if checkErr := checkClosable(wallet); checkErr != nil {
+		return nil, checkErr
+	}
+
+	return s.approvals.Submit(ctx, OperationClose, walletID, models.CloseWalletRequest{Reason: reason})
+}
+
+// approvableWallet loads a wallet whose status change is being requested.
+func (s *WalletService) approvableWallet(ctx context.Context, walletID string) (*models.Wallet, *errors.Error) {
+	if s.approvals == nil {
+		return nil, errors.Internal("approval workflow is not configured")
+	}
+	return s.walletRepo.GetByID(ctx, walletID)
+}
+
+// checkUnfreezable validates that a wallet can be unfrozen.
+func checkUnfreezable(wallet *models.Wallet) *errors.Error {
+	if wallet.Status != models.WalletStatusFrozen {
+		return errors.BadRequest("only frozen wallets can be unfrozen")
+	}
+	return nil
+}
+
+// checkClosable validates that a wallet can be closed.
+func checkClosable(wallet *models.Wallet) *errors.Error {
+	if wallet.Status == models.WalletStatusClosed {
+		return errors.BadRequest("wallet is already closed")
+	}
+	if wallet.Balance > 0 {
+		return errors.BadRequest("cannot close wallet with non-zero balance")
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+)
+
+// ============================================================================
+// Mock Approval Store
+// ============================================================================
+
+type mockApprovalStore struct {
+	requests map[string]*approval.Request
+}
+
+func newMockApprovalStore() *mockApprovalStore {
+	return &mockApprovalStore{requests: make(map[string]*approval.Request)}
+}
+
+func (m *mockApprovalStore) Create(ctx context.Context, req *approval.Request) *errors.Error {
+	stored := *req
+	m.requests[req.ID] = &stored
+	return nil
+}
+
+func (m *mockApprovalStore) Get(ctx context.Context, service, id string) (*approval.Request, *errors.Error) {
+	req, ok := m.requests[id]
+	if !ok {
+		return nil, errors.NotFoundWithID("approval request", id)
+	}
+	copied := *req
+	return &copied, nil
+}
+
+func (m *mockApprovalStore) List(ctx context.Context, filter *approval.Filter) ([]*approval.Request, int64, *errors.Error) {
+	return nil, 0, nil
+}
+
+func (m *mockApprovalStore) Decide(ctx context.Context, req *approval.Request) *errors.Error {
+	if m.requests[req.ID].Status != approval.StatusPending {
+		return errors.Conflict("approval request has already been decided")
+	}
+	*m.requests[req.ID] = *req
+	return nil
+}
+
+func (m *mockApprovalStore) Complete(ctx context.Context, req *approval.Request) *errors.Error {
+	*m.requests[req.ID] = *req
+	return nil
+}
+
+// userContext authenticates userID with the given permissions.
+func userContext(userID string, permissions ...string) context.Context {
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, userID)
+	return context.WithValue(ctx, middleware.UserPermissionsKey, permissions)
+}
+
+// setupLimitsTest creates an active wallet owned by "owner" with limits of
+// ₹1,00,000 a day and ₹10,00,000 a month.
+func setupLimitsTest(t *testing.T) (*WalletService, *mockWalletRepository, *approval.Workflow, *models.Wallet) {
+	t.Helper()
+	repo := newMockWalletRepository()
+	service := NewWalletService(repo, nil, nil, nil, nil)
+	workflow := approval.NewWorkflow(newMockApprovalStore(), "wallet")
+	service.SetApprovals(workflow)
+
+	wallet, err := service.CreateWallet(context.Background(), &models.CreateWalletRequest{
+		UserID:          "owner",
+		Type:            models.WalletTypeDefault,
+		Currency:        "INR",
+		LedgerAccountID: "acc_001",
+	})
+	if err != nil {
+		t.Fatalf("expected wallet to be created, got %v", err)
+	}
+	if _, err := service.ActivateWallet(context.Background(), wallet.ID); err != nil {
+		t.Fatalf("expected wallet to be activated, got %v", err)
+	}
+	_ = repo.UpdateLimits(context.Background(), wallet.ID, 10000000, 100000000)
+
+	return service, repo, workflow, wallet
+}
+
+// ============================================================================
+// Tests: Wallet Limits
+// ============================================================================
+
+func TestUpdateWalletLimits_DecreaseAppliedDirectly(t *testing.T) {
+	service, repo, _, wallet := setupLimitsTest(t)
+
+	limits, approvalReq, err := service.UpdateWalletLimits(userContext("owner"), wallet.ID, &models.UpdateLimitsRequest{
+		DailyLimit:   5000000,
+		MonthlyLimit: 100000000,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if approvalReq != nil {
+		t.Errorf("expected no approval request for lower limits, got %+v", approvalReq)
+	}
+	if limits == nil || limits.DailyLimit != 5000000 || repo.limits[wallet.ID].DailyLimit != 5000000 {
+		t.Errorf("expected daily limit lowered to 5000000, got %+v", repo.limits[wallet.ID])
+	}
+}
+
+func TestUpdateWalletLimits_IncreaseNeedsApproval(t *testing.T) {
+	service, repo, workflow, wallet := setupLimitsTest(t)
+
+	limits, approvalReq, err := service.UpdateWalletLimits(userContext("owner"), wallet.ID, &models.UpdateLimitsRequest{
+		DailyLimit:   10000000,
+		MonthlyLimit: 200000000,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if limits != nil || approvalReq == nil || approvalReq.Operation != OperationLimitsIncrease || approvalReq.Status != approval.StatusPending {
+		t.Fatalf("expected pending limits increase request, got %+v", approvalReq)
+	}
+	if repo.limits[wallet.ID].MonthlyLimit != 100000000 {
+		t.Fatal("limits must not change until the increase is approved")
+	}
+
+	// The owner cannot approve their own increase, even holding the permission
+	if _, err := workflow.Approve(userContext("owner", "wallet:wallet:limits_increase"), approvalReq.ID); err == nil || err.Code != errors.ErrCodeForbidden {
+		t.Fatalf("expected the maker to be refused, got %v", err)
+	}
+
+	approved, err := workflow.Approve(userContext("admin-1", "wallet:wallet:limits_increase"), approvalReq.ID)
+	if err != nil {
+		t.Fatalf("expected approval to succeed, got %v", err)
+	}
+	if approved.Status != approval.StatusExecuted {
+		t.Errorf("expected executed request, got %s", approved.Status)
+	}
+	if repo.limits[wallet.ID].MonthlyLimit != 200000000 {
+		t.Errorf("expected monthly limit raised after approval, got %d", repo.limits[wallet.ID].MonthlyLimit)
+	}
+}
+
+func TestUpdateWalletLimits_Error_NotOwner(t *testing.T) {
+	service, _, _, wallet := setupLimitsTest(t)
+
+	_, _, err := service.UpdateWalletLimits(userContext("someone-else"), wallet.ID, &models.UpdateLimitsRequest{
+		DailyLimit:   5000000,
+		MonthlyLimit: 50000000,
+	})
+	if err == nil || err.Code != errors.ErrCodeForbidden {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/approval"
+	"github.com/vnykmshr/nivo/shared/audit"
 	"github.com/vnykmshr/nivo/shared/clients"
 	"github.com/vnykmshr/nivo/shared/errors"
 	"github.com/vnykmshr/nivo/shared/events"
@@ -23,6 +25,10 @@ type WalletRepositoryInterface interface {
 	UpdateLimits(ctx context.Context, walletID string, dailyLimit, monthlyLimit int64) *errors.Error
 	ProcessTransferWithinTx(ctx context.Context, sourceWalletID, destWalletID string, amount int64, transactionID string) *errors.Error
 	ProcessDepositWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error
+	ProcessWithdrawalWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error
+	HoldFundsWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error
+	ReleaseHoldWithinTx(ctx context.Context, transactionID string) *errors.Error
+	ReverseMovementWithinTx(ctx context.Context, transactionID string) *errors.Error
 	UpdateBalance(ctx context.Context, walletID string, amount int64) *errors.Error
 }
 
@@ -33,6 +39,8 @@ type WalletService struct {
 	ledgerClient       *LedgerClient
 	notificationClient *clients.NotificationClient
 	identityClient     *IdentityClient
+	auditLog           audit.Recorder     // Optional durable record of admin actions
+	approvals          *approval.Workflow // Second-admin approval of unfreezes and closures
 }
 
 // NewWalletService creates a new wallet service.
@@ -46,6 +54,40 @@ func NewWalletService(walletRepo WalletRepositoryInterface, eventPublisher *even
 	}
 }
 
+// SetAuditLog sets where freezes, unfreezes and closures are recorded.
+// This is optional - if not set, they are not recorded.
+func (s *WalletService) SetAuditLog(auditLog audit.Recorder) {
+	s.auditLog = auditLog
+}
+
+// recordStatusChange records a wallet status change in the audit log, if set.
+func (s *WalletService) recordStatusChange(ctx context.Context, action string, before map[string]interface{}, after *models.Wallet, reason string) {
+	if s.auditLog == nil {
+		return
+	}
+
+	afterSnapshot := walletAuditSnapshot(after)
+	if reason != "" {
+		afterSnapshot["reason"] = reason
+	}
+	s.auditLog.Record(ctx, audit.Event{
+		Action:       action,
+		ResourceType: "wallet",
+		ResourceID:   after.ID,
+		Before:       before,
+		After:        afterSnapshot,
+	})
+}
+
+func walletAuditSnapshot(wallet *models.Wallet) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":           wallet.UserID,
+		"status":            wallet.Status,
+		"balance":           wallet.Balance,
+		"available_balance": wallet.AvailableBalance,
+	}
+}
+
 // CreateWallet creates a new wallet for a user.
 func (s *WalletService) CreateWallet(ctx context.Context, req *models.CreateWalletRequest) (*models.Wallet, *errors.Error) {
 	// Parse metadata
@@ -245,6 +287,8 @@ func (s *WalletService) FreezeWallet(ctx context.Context, walletID, reason strin
 		return nil, errors.BadRequest("only active wallets can be frozen")
 	}
 
+	before := walletAuditSnapshot(wallet)
+
 	// Update status
 	if updateErr := s.walletRepo.UpdateStatus(ctx, walletID, models.WalletStatusFrozen); updateErr != nil {
 		return nil, updateErr
@@ -270,6 +314,8 @@ func (s *WalletService) FreezeWallet(ctx context.Context, walletID, reason strin
 		})
 	}
 
+	s.recordStatusChange(ctx, "wallet.freeze", before, updatedWallet, reason)
+
 	return updatedWallet, nil
 }
 
@@ -282,10 +328,12 @@ func (s *WalletService) UnfreezeWallet(ctx context.Context, walletID string) (*m
 	}
 
 	// Validate status transition
-	if wallet.Status != models.WalletStatusFrozen {
-		return nil, errors.BadRequest("only frozen wallets can be unfrozen")
+	if checkErr := checkUnfreezable(wallet); checkErr != nil {
+		return nil, checkErr
 	}
 
+	before := walletAuditSnapshot(wallet)
+
 	// Update status
 	if updateErr := s.walletRepo.UpdateStatus(ctx, walletID, models.WalletStatusActive); updateErr != nil {
 		return nil, updateErr
@@ -310,6 +358,8 @@ func (s *WalletService) UnfreezeWallet(ctx context.Context, walletID string) (*m
 		})
 	}
 
+	s.recordStatusChange(ctx, "wallet.unfreeze", before, updatedWallet, "")
+
 	return updatedWallet, nil
 }
 
@@ -322,17 +372,13 @@ func (s *WalletService) CloseWallet(ctx context.Context, walletID, reason string
 	}
 
 	// Validate wallet can be closed
-	if wallet.Status == models.WalletStatusClosed {
-		return nil, errors.BadRequest("wallet is already closed")
-	}
-
-	// Validate balance is zero
-	if wallet.Balance > 0 {
-		return nil, errors.BadRequest("cannot close wallet with non-zero balance")
+	if checkErr := checkClosable(wallet); checkErr != nil {
+		return nil, checkErr
 	}
 
 	// Store old status before closing
 	oldStatus := wallet.Status
+	before := walletAuditSnapshot(wallet)
 
 	// Close wallet
 	if closeErr := s.walletRepo.Close(ctx, walletID, reason); closeErr != nil {
@@ -359,6 +405,8 @@ func (s *WalletService) CloseWallet(ctx context.Context, walletID, reason string
 		})
 	}
 
+	s.recordStatusChange(ctx, "wallet.close", before, updatedWallet, reason)
+
 	return updatedWallet, nil
 }
 
@@ -378,47 +426,77 @@ func (s *WalletService) GetWalletLimits(ctx context.Context, walletID string) (*
 	return s.walletRepo.GetLimits(ctx, walletID)
 }
 
-// UpdateWalletLimits updates the transfer limits for a wallet after verifying user password.
-func (s *WalletService) UpdateWalletLimits(ctx context.Context, walletID string, req *models.UpdateLimitsRequest) (*models.WalletLimits, *errors.Error) {
+// UpdateWalletLimits changes the transfer limits of the user's wallet. Lower
+// limits take effect straight away. Raising either limit is submitted for
+// approval by an admin instead, and the pending request is returned.
+func (s *WalletService) UpdateWalletLimits(ctx context.Context, walletID string, req *models.UpdateLimitsRequest) (*models.WalletLimits, *approval.Request, *errors.Error) {
 	// Get wallet to verify ownership
 	wallet, err := s.walletRepo.GetByID(ctx, walletID)
 	if err != nil {
-		return nil, err
-	}
-
-	// Verify wallet is active
-	if !wallet.IsActive() {
-		return nil, errors.BadRequest("cannot update limits for inactive wallet")
+		return nil, nil, err
 	}
 
 	// Get user ID from context (set by auth middleware)
 	userID, ok := middleware.GetUserID(ctx)
 	if !ok {
-		return nil, errors.Unauthorized("user ID not found in context")
+		return nil, nil, errors.Unauthorized("user ID not found in context")
 	}
 
 	// Verify user owns this wallet
 	if wallet.UserID != userID {
-		return nil, errors.Forbidden("you do not own this wallet")
+		return nil, nil, errors.Forbidden("you do not own this wallet")
 	}
 
-	// Note: Authentication is handled via JWT middleware - no additional password verification needed.
-	// The user has already authenticated and we've verified wallet ownership above.
+	if err := checkLimitsUpdatable(wallet, req); err != nil {
+		return nil, nil, err
+	}
 
-	// Validate limits
-	if req.DailyLimit > req.MonthlyLimit {
-		return nil, errors.BadRequest("daily limit cannot exceed monthly limit")
+	current, err := s.walletRepo.GetLimits(ctx, walletID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.DailyLimit > current.DailyLimit || req.MonthlyLimit > current.MonthlyLimit {
+		if s.approvals == nil {
+			return nil, nil, errors.Internal("approval workflow is not configured")
+		}
+		approvalReq, err := s.approvals.Submit(ctx, OperationLimitsIncrease, walletID, req)
+		return nil, approvalReq, err
+	}
+
+	limits, err := s.applyWalletLimits(ctx, walletID, req)
+	return limits, nil, err
+}
+
+// applyWalletLimits sets the transfer limits of a wallet, re-checking that it
+// is active: an approved increase may run after the wallet has changed.
+func (s *WalletService) applyWalletLimits(ctx context.Context, walletID string, req *models.UpdateLimitsRequest) (*models.WalletLimits, *errors.Error) {
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLimitsUpdatable(wallet, req); err != nil {
+		return nil, err
 	}
 
-	// Update limits
 	if err := s.walletRepo.UpdateLimits(ctx, walletID, req.DailyLimit, req.MonthlyLimit); err != nil {
 		return nil, err
 	}
 
-	// Return updated limits
 	return s.walletRepo.GetLimits(ctx, walletID)
 }
 
+// checkLimitsUpdatable validates new transfer limits for a wallet.
+func checkLimitsUpdatable(wallet *models.Wallet, req *models.UpdateLimitsRequest) *errors.Error {
+	if !wallet.IsActive() {
+		return errors.BadRequest("cannot update limits for inactive wallet")
+	}
+	if req.DailyLimit > req.MonthlyLimit {
+		return errors.BadRequest("daily limit cannot exceed monthly limit")
+	}
+	return nil
+}
+
 // ProcessTransfer processes a wallet-to-wallet transfer with limit checking and balance updates.
 // This is an internal endpoint called by the transaction service to execute approved transfers.
 func (s *WalletService) ProcessTransfer(ctx context.Context, sourceWalletID, destWalletID string, amount int64, transactionID string) *errors.Error {
@@ -497,3 +575,52 @@ func (s *WalletService) ProcessDeposit(ctx context.Context, walletID string, amo
 
 	return nil
 }
+
+// ProcessWithdrawal debits a withdrawal from a wallet (internal method called by transaction service).
+// Idempotent per transactionID; funds held for the transaction are captured.
+func (s *WalletService) ProcessWithdrawal(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	if amount <= 0 {
+		return errors.BadRequest("withdrawal amount must be positive")
+	}
+
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return err
+	}
+
+	if debitErr := s.walletRepo.ProcessWithdrawalWithinTx(ctx, walletID, amount, transactionID); debitErr != nil {
+		return debitErr
+	}
+
+	if s.eventPublisher != nil {
+		s.eventPublisher.PublishWalletEvent("wallet.withdrawal.completed", walletID, map[string]interface{}{
+			"wallet_id":      walletID,
+			"amount":         amount,
+			"transaction_id": transactionID,
+			"user_id":        wallet.UserID,
+		})
+	}
+
+	return nil
+}
+
+// HoldFunds reserves funds in a wallet for a pending transaction. The held amount
+// is unavailable for other spending until it is captured or released.
+func (s *WalletService) HoldFunds(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	if amount <= 0 {
+		return errors.BadRequest("hold amount must be positive")
+	}
+
+	return s.walletRepo.HoldFundsWithinTx(ctx, walletID, amount, transactionID)
+}
+
+// ReleaseHold returns funds held for a transaction that will not go ahead.
+func (s *WalletService) ReleaseHold(ctx context.Context, transactionID string) *errors.Error {
+	return s.walletRepo.ReleaseHoldWithinTx(ctx, transactionID)
+}
+
+// ReverseMovement undoes the balance movement of a transaction that failed after
+// its funds moved. Safe to retry.
+func (s *WalletService) ReverseMovement(ctx context.Context, transactionID string) *errors.Error {
+	return s.walletRepo.ReverseMovementWithinTx(ctx, transactionID)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/vnykmshr/nivo/services/wallet/internal/models"
+	"github.com/vnykmshr/nivo/shared/approval"
 	"github.com/vnykmshr/nivo/shared/errors"
 	sharedModels "github.com/vnykmshr/nivo/shared/models"
 )
@@ -16,17 +17,23 @@ import (
 
 type mockWalletRepository struct {
 	wallets map[string]*models.Wallet
+	limits  map[string]*models.WalletLimits
 
 	// Function hooks for error injection
 	createFunc       func(ctx context.Context, wallet *models.Wallet) *errors.Error
 	getByIDFunc      func(ctx context.Context, id string) (*models.Wallet, *errors.Error)
 	updateStatusFunc func(ctx context.Context, id string, status models.WalletStatus) *errors.Error
 	closeFunc        func(ctx context.Context, id, reason string) *errors.Error
+
+	// Transaction IDs passed to the saga operations
+	heldTransactions      []string
+	withdrawnTransactions []string
 }
 
 func newMockWalletRepository() *mockWalletRepository {
 	return &mockWalletRepository{
 		wallets: make(map[string]*models.Wallet),
+		limits:  make(map[string]*models.WalletLimits),
 	}
 }
 
@@ -126,10 +133,16 @@ func (m *mockWalletRepository) GetBalance(ctx context.Context, id string) (*mode
 }
 
 func (m *mockWalletRepository) GetLimits(ctx context.Context, walletID string) (*models.WalletLimits, *errors.Error) {
-	return nil, nil
+	limits, ok := m.limits[walletID]
+	if !ok {
+		return nil, errors.NotFound("wallet limits")
+	}
+	copied := *limits
+	return &copied, nil
 }
 
 func (m *mockWalletRepository) UpdateLimits(ctx context.Context, walletID string, dailyLimit, monthlyLimit int64) *errors.Error {
+	m.limits[walletID] = &models.WalletLimits{WalletID: walletID, DailyLimit: dailyLimit, MonthlyLimit: monthlyLimit}
 	return nil
 }
 
@@ -141,6 +154,24 @@ func (m *mockWalletRepository) ProcessDepositWithinTx(ctx context.Context, walle
 	return nil
 }
 
+func (m *mockWalletRepository) ProcessWithdrawalWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	m.withdrawnTransactions = append(m.withdrawnTransactions, transactionID)
+	return nil
+}
+
+func (m *mockWalletRepository) HoldFundsWithinTx(ctx context.Context, walletID string, amount int64, transactionID string) *errors.Error {
+	m.heldTransactions = append(m.heldTransactions, transactionID)
+	return nil
+}
+
+func (m *mockWalletRepository) ReleaseHoldWithinTx(ctx context.Context, transactionID string) *errors.Error {
+	return nil
+}
+
+func (m *mockWalletRepository) ReverseMovementWithinTx(ctx context.Context, transactionID string) *errors.Error {
+	return nil
+}
+
 func (m *mockWalletRepository) UpdateBalance(ctx context.Context, walletID string, amount int64) *errors.Error {
 	return nil
 }
@@ -652,6 +683,37 @@ func TestCloseWallet_Error_NonZeroBalance(t *testing.T) {
 	}
 }
 
+func TestRequestClose_Error_NonZeroBalance(t *testing.T) {
+	repo := newMockWalletRepository()
+	service := NewWalletService(repo, nil, nil, nil, nil) // notification and identity clients (nil for tests)
+	// The store is never reached: the wallet is checked before submission
+	service.SetApprovals(approval.NewWorkflow(nil, "wallet"))
+	ctx := context.Background()
+
+	req := &models.CreateWalletRequest{
+		UserID:          "user_request_close",
+		Type:            models.WalletTypeDefault,
+		Currency:        "INR",
+		LedgerAccountID: "acc_001",
+	}
+	wallet, _ := service.CreateWallet(ctx, req)
+	repo.wallets[wallet.ID].Balance = 1000
+
+	_, err := service.RequestClose(ctx, wallet.ID, "customer request")
+	if err == nil || err.Message != "cannot close wallet with non-zero balance" {
+		t.Fatalf("expected closure to be refused before approval, got %v", err)
+	}
+}
+
+func TestRequestUnfreeze_Error_NotConfigured(t *testing.T) {
+	service := NewWalletService(newMockWalletRepository(), nil, nil, nil, nil)
+
+	_, err := service.RequestUnfreeze(context.Background(), "wallet_1")
+	if err == nil || err.Code != errors.ErrCodeInternal {
+		t.Fatalf("expected internal error without an approval workflow, got %v", err)
+	}
+}
+
 // ============================================================================
 // Tests: Wallet Balance
 // ============================================================================
@@ -756,3 +818,79 @@ func TestWalletStatusTransitions_FullLifecycle(t *testing.T) {
 		t.Errorf("expected status CLOSED after closure, got %s", wallet.Status)
 	}
 }
+
+// ============================================================================
+// Tests: Holds and Withdrawals
+// ============================================================================
+
+func TestHoldFunds_Success(t *testing.T) {
+	repo := newMockWalletRepository()
+	service := NewWalletService(repo, nil, nil, nil, nil)
+
+	if err := service.HoldFunds(context.Background(), "wallet_1", 5000, "txn_1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(repo.heldTransactions) != 1 || repo.heldTransactions[0] != "txn_1" {
+		t.Errorf("expected hold for txn_1, got %v", repo.heldTransactions)
+	}
+}
+
+func TestHoldFunds_Error_NonPositiveAmount(t *testing.T) {
+	repo := newMockWalletRepository()
+	service := NewWalletService(repo, nil, nil, nil, nil)
+
+	err := service.HoldFunds(context.Background(), "wallet_1", 0, "txn_1")
+
+	if err == nil {
+		t.Fatal("expected error for zero hold amount")
+	}
+
+	if err.Code != errors.ErrCodeBadRequest {
+		t.Errorf("expected bad request error, got %s", err.Code)
+	}
+
+	if len(repo.heldTransactions) != 0 {
+		t.Error("expected no hold to be placed")
+	}
+}
+
+func TestProcessWithdrawal_Success(t *testing.T) {
+	repo := newMockWalletRepository()
+	service := NewWalletService(repo, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	wallet, _ := service.CreateWallet(ctx, &models.CreateWalletRequest{
+		UserID:          "user_withdraw",
+		Type:            models.WalletTypeDefault,
+		Currency:        "INR",
+		LedgerAccountID: "acc_001",
+	})
+
+	if err := service.ProcessWithdrawal(ctx, wallet.ID, 2500, "txn_w1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(repo.withdrawnTransactions) != 1 || repo.withdrawnTransactions[0] != "txn_w1" {
+		t.Errorf("expected withdrawal for txn_w1, got %v", repo.withdrawnTransactions)
+	}
+}
+
+func TestProcessWithdrawal_Error_WalletNotFound(t *testing.T) {
+	repo := newMockWalletRepository()
+	service := NewWalletService(repo, nil, nil, nil, nil)
+
+	err := service.ProcessWithdrawal(context.Background(), "missing", 2500, "txn_w1")
+
+	if err == nil {
+		t.Fatal("expected error for missing wallet")
+	}
+
+	if err.Code != errors.ErrCodeNotFound {
+		t.Errorf("expected not found error, got %s", err.Code)
+	}
+
+	if len(repo.withdrawnTransactions) != 0 {
+		t.Error("expected no withdrawal to be processed")
+	}
+}
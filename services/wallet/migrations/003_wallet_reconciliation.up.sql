@@ -0,0 +1,58 @@
+-- ============================================================================
+-- Wallet Reconciliation
+-- ============================================================================
+
+-- Each run compares every wallet balance against its ledger account balance
+-- and the net of its settled transactions.
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    trigger VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    wallets_checked INTEGER NOT NULL DEFAULT 0,
+    discrepancies_found INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT reconciliation_runs_status_check CHECK (status IN ('running', 'completed', 'failed')),
+    CONSTRAINT reconciliation_runs_trigger_check CHECK (trigger IN ('scheduled', 'manual'))
+);
+
+CREATE INDEX idx_reconciliation_runs_started_at ON reconciliation_runs(started_at DESC);
+
+-- A wallet has at most one open discrepancy. Later runs refresh it until the
+-- balances match again, at which point it is resolved automatically.
+CREATE TABLE IF NOT EXISTS reconciliation_discrepancies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    ledger_account_id UUID NOT NULL,
+    first_run_id UUID NOT NULL REFERENCES reconciliation_runs(id),
+    last_run_id UUID NOT NULL REFERENCES reconciliation_runs(id),
+    wallet_balance BIGINT NOT NULL,
+    ledger_balance BIGINT,              -- NULL when the ledger account could not be found
+    transaction_net BIGINT NOT NULL,
+    ledger_difference BIGINT NOT NULL,      -- wallet_balance - ledger_balance
+    transaction_difference BIGINT NOT NULL, -- wallet_balance - transaction_net
+    severity VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by UUID,
+    resolution_note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT reconciliation_discrepancies_severity_check CHECK (severity IN ('low', 'medium', 'high', 'critical')),
+    CONSTRAINT reconciliation_discrepancies_status_check CHECK (status IN ('open', 'resolved'))
+);
+
+CREATE UNIQUE INDEX idx_reconciliation_discrepancies_open_wallet
+    ON reconciliation_discrepancies(wallet_id) WHERE status = 'open';
+CREATE INDEX idx_reconciliation_discrepancies_status_severity
+    ON reconciliation_discrepancies(status, severity);
+CREATE INDEX idx_reconciliation_discrepancies_detected_at
+    ON reconciliation_discrepancies(detected_at DESC);
+
+COMMENT ON TABLE reconciliation_discrepancies IS
+'Wallets whose balance disagrees with their ledger account or transaction history.';
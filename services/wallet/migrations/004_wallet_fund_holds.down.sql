@@ -0,0 +1,5 @@
+ALTER TABLE processed_deposits DROP COLUMN IF EXISTS reversed_at;
+ALTER TABLE processed_transfers DROP COLUMN IF EXISTS reversed_at;
+
+DROP TABLE IF EXISTS processed_withdrawals;
+DROP TABLE IF EXISTS wallet_holds;
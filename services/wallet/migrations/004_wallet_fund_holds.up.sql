@@ -0,0 +1,41 @@
+-- ============================================================================
+-- Fund Holds and Compensation for Transaction Sagas
+-- ============================================================================
+
+-- A hold earmarks funds for a transaction before they move: available_balance
+-- is reduced and spending limits are reserved, but balance is untouched until
+-- the transfer or withdrawal captures the hold. Releasing a hold undoes both.
+CREATE TABLE IF NOT EXISTS wallet_holds (
+    transaction_id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'held',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT wallet_holds_amount_check CHECK (amount > 0),
+    CONSTRAINT wallet_holds_status_check CHECK (status IN ('held', 'captured', 'released'))
+);
+
+CREATE INDEX idx_wallet_holds_wallet_held ON wallet_holds(wallet_id) WHERE status = 'held';
+
+CREATE TRIGGER update_wallet_holds_updated_at
+    BEFORE UPDATE ON wallet_holds
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Withdrawals are idempotent on the transaction ID, like transfers and deposits
+CREATE TABLE IF NOT EXISTS processed_withdrawals (
+    transaction_id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL,
+    amount BIGINT NOT NULL,
+    processed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reversed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_processed_withdrawals_wallet ON processed_withdrawals(wallet_id);
+
+-- Set when a saga compensates the movement, so a reversal is applied at most once
+ALTER TABLE processed_transfers ADD COLUMN IF NOT EXISTS reversed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE processed_deposits ADD COLUMN IF NOT EXISTS reversed_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,5 @@
+-- Wallet Idempotency Keys Rollback
+
+-- The table is kept: the transaction service uses it too, and rolling back the
+-- wallet service must not break it. Drop idempotency_keys by hand if it really
+-- has to go.
@@ -0,0 +1,25 @@
+-- ============================================================================
+-- Idempotency Keys
+-- ============================================================================
+
+-- Keys sent in X-Idempotency-Key on money-movement endpoints, with the response
+-- of the first request so retries are replayed instead of processed again.
+-- Keys are scoped by user and endpoint. A key without a response is locked by
+-- an in-flight request until locked_until. Shared with the transaction
+-- service, which starts after this one.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(512) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_status INTEGER,
+    response_content_type VARCHAR(255),
+    response_body BYTEA,
+    locked_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
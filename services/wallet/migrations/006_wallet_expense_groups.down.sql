@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS group_settlements;
+DROP TABLE IF EXISTS group_expense_shares;
+DROP TABLE IF EXISTS group_expenses;
+DROP TABLE IF EXISTS expense_group_members;
+DROP TABLE IF EXISTS expense_groups;
@@ -0,0 +1,96 @@
+-- ============================================================================
+-- Expense Groups (split bills)
+-- ============================================================================
+
+-- A group of users sharing expenses. The owner builds it from their
+-- beneficiaries and is always a member.
+CREATE TABLE IF NOT EXISTS expense_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'INR',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT expense_groups_name_length CHECK (LENGTH(name) >= 1 AND LENGTH(name) <= 100)
+);
+
+CREATE INDEX idx_expense_groups_owner ON expense_groups(owner_user_id, created_at DESC);
+
+CREATE TRIGGER update_expense_groups_updated_at
+    BEFORE UPDATE ON expense_groups
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Members pay and are paid through the wallet recorded when they joined
+-- (the beneficiary's wallet, or the owner's default INR wallet).
+CREATE TABLE IF NOT EXISTS expense_group_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES expense_groups(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    wallet_id UUID NOT NULL,
+    display_name VARCHAR(100) NOT NULL,
+    beneficiary_id UUID,                -- NULL for the owner
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_expense_group_members_unique_user ON expense_group_members(group_id, user_id);
+CREATE INDEX idx_expense_group_members_user ON expense_group_members(user_id);
+
+CREATE TABLE IF NOT EXISTS group_expenses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES expense_groups(id) ON DELETE CASCADE,
+    paid_by_member_id UUID NOT NULL REFERENCES expense_group_members(id),
+    amount BIGINT NOT NULL,
+    description VARCHAR(200) NOT NULL,
+    split_type VARCHAR(20) NOT NULL,
+    created_by_user_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT group_expenses_amount_positive CHECK (amount > 0),
+    CONSTRAINT group_expenses_split_type_check CHECK (split_type IN ('equal', 'percentage', 'exact'))
+);
+
+CREATE INDEX idx_group_expenses_group ON group_expenses(group_id, created_at DESC);
+
+-- Each member's share of an expense, in paise. Shares add up to the expense amount.
+CREATE TABLE IF NOT EXISTS group_expense_shares (
+    expense_id UUID NOT NULL REFERENCES group_expenses(id) ON DELETE CASCADE,
+    member_id UUID NOT NULL REFERENCES expense_group_members(id),
+    amount BIGINT NOT NULL,
+
+    PRIMARY KEY (expense_id, member_id),
+    CONSTRAINT group_expense_shares_amount_check CHECK (amount >= 0)
+);
+
+-- Settle-up transfers between members. Only completed settlements count
+-- towards balances. A member has at most one pending settlement per group, so
+-- concurrent settle-ups cannot pay the same debt twice.
+CREATE TABLE IF NOT EXISTS group_settlements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES expense_groups(id) ON DELETE CASCADE,
+    from_member_id UUID NOT NULL REFERENCES expense_group_members(id),
+    to_member_id UUID NOT NULL REFERENCES expense_group_members(id),
+    from_wallet_id UUID NOT NULL,
+    to_wallet_id UUID NOT NULL,
+    amount BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    transaction_id UUID,
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT group_settlements_amount_positive CHECK (amount > 0),
+    CONSTRAINT group_settlements_members_differ CHECK (from_member_id != to_member_id),
+    CONSTRAINT group_settlements_status_check CHECK (status IN ('pending', 'completed', 'failed')),
+    CONSTRAINT group_settlements_completed_transaction CHECK (status != 'completed' OR transaction_id IS NOT NULL)
+);
+
+CREATE INDEX idx_group_settlements_group ON group_settlements(group_id, created_at DESC);
+CREATE UNIQUE INDEX idx_group_settlements_pending_member
+    ON group_settlements(group_id, from_member_id) WHERE status = 'pending';
+
+CREATE TRIGGER update_group_settlements_updated_at
+    BEFORE UPDATE ON group_settlements
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,299 @@
+// Package approval runs sensitive admin operations under four-eyes control.
+//
+// Instead of executing an operation such as a transaction reversal, the admin
+// who asks for it (the maker) submits an approval request. A second admin
+// (the checker) holding the operation's RBAC permission approves or rejects
+// it; on approval the operation runs with the checker's context. The maker can
+// never approve their own request, and a request is decided at most once.
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/shared/audit"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/logger"
+	"github.com/vnykmshr/nivo/shared/middleware"
+)
+
+// Status is the state of an approval request.
+type Status string
+
+const (
+	StatusPending  Status = "pending"  // Waiting for a checker
+	StatusApproved Status = "approved" // Approved, operation running
+	StatusRejected Status = "rejected" // Rejected by a checker or withdrawn by the maker
+	StatusExecuted Status = "executed" // Approved and the operation succeeded
+	StatusFailed   Status = "failed"   // Approved but the operation returned an error
+)
+
+// Request is an operation waiting for, or decided by, a second admin.
+type Request struct {
+	ID             string          `json:"id"`
+	Service        string          `json:"service"`
+	Operation      string          `json:"operation"`
+	ResourceType   string          `json:"resource_type"`
+	ResourceID     string          `json:"resource_id"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         Status          `json:"status"`
+	MakerID        string          `json:"maker_id"`
+	CheckerID      *string         `json:"checker_id,omitempty"`
+	DecisionReason *string         `json:"decision_reason,omitempty"`
+	Result         json.RawMessage `json:"result,omitempty"`
+	FailureReason  *string         `json:"failure_reason,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DecidedAt      *time.Time      `json:"decided_at,omitempty"`
+	ExecutedAt     *time.Time      `json:"executed_at,omitempty"`
+}
+
+// Operation is an action that needs a second admin's approval.
+type Operation struct {
+	Name         string // e.g. "transaction.reverse"
+	ResourceType string // e.g. "transaction"
+	// Permission the checker must hold, usually the one that guards the operation's endpoint
+	Permission string
+	// Execute performs the approved operation and returns its result. It should
+	// re-check its preconditions: the resource may have changed since submission.
+	Execute func(ctx context.Context, req *Request) (any, *errors.Error)
+}
+
+// Filter selects approval requests. Empty fields match everything.
+type Filter struct {
+	Service    string
+	Status     Status
+	Operation  string
+	ResourceID string
+	MakerID    string
+	Limit      int
+	Offset     int
+}
+
+// Store persists approval requests.
+type Store interface {
+	// Create inserts a pending request, returning a conflict if the same
+	// operation is already pending for the resource.
+	Create(ctx context.Context, req *Request) *errors.Error
+	Get(ctx context.Context, service, id string) (*Request, *errors.Error)
+	List(ctx context.Context, filter *Filter) ([]*Request, int64, *errors.Error)
+	// Decide moves a pending request to req.Status with its checker and reason,
+	// returning a conflict if it is no longer pending.
+	Decide(ctx context.Context, req *Request) *errors.Error
+	// Complete records the outcome of executing an approved request.
+	Complete(ctx context.Context, req *Request) *errors.Error
+}
+
+// Workflow submits and decides the approval requests of one service.
+type Workflow struct {
+	store      Store
+	service    string
+	operations map[string]Operation
+	auditLog   audit.Recorder
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// NewWorkflow creates a workflow keeping the named service's requests in store.
+func NewWorkflow(store Store, service string) *Workflow {
+	return &Workflow{
+		store:      store,
+		service:    service,
+		operations: make(map[string]Operation),
+		logger:     logger.NewDefault(service + ".approval"),
+		now:        time.Now,
+	}
+}
+
+// SetAuditLog sets the audit log recording submissions and decisions (optional).
+func (w *Workflow) SetAuditLog(auditLog audit.Recorder) {
+	w.auditLog = auditLog
+}
+
+// Register adds an operation that can be submitted for approval.
+func (w *Workflow) Register(op Operation) {
+	w.operations[op.Name] = op
+}
+
+// Submit creates a pending request for operation on the resource, made by the
+// authenticated user. payload is passed to the operation when it executes.
+func (w *Workflow) Submit(ctx context.Context, operation, resourceID string, payload any) (*Request, *errors.Error) {
+	op, ok := w.operations[operation]
+	if !ok {
+		return nil, errors.Internal(fmt.Sprintf("operation %s does not support approval", operation))
+	}
+
+	makerID, ok := middleware.GetUserID(ctx)
+	if !ok || makerID == "" {
+		return nil, errors.Unauthorized("user not authenticated")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to encode approval payload")
+	}
+
+	req := &Request{
+		ID:           uuid.New().String(),
+		Service:      w.service,
+		Operation:    op.Name,
+		ResourceType: op.ResourceType,
+		ResourceID:   resourceID,
+		Payload:      data,
+		Status:       StatusPending,
+		MakerID:      makerID,
+		CreatedAt:    w.now().UTC(),
+	}
+	if createErr := w.store.Create(ctx, req); createErr != nil {
+		return nil, createErr
+	}
+
+	w.record(ctx, "approval.submit", req)
+	return req, nil
+}
+
+// Approve approves a pending request as the authenticated user and executes
+// its operation. The maker cannot approve their own request. If the operation
+// fails, the request is marked failed and the operation's error is returned
+// with it.
+func (w *Workflow) Approve(ctx context.Context, id string) (*Request, *errors.Error) {
+	req, op, checkerID, err := w.decision(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if checkerID == req.MakerID {
+		return nil, errors.Forbidden("approval requests must be approved by someone other than the requester")
+	}
+
+	decidedAt := w.now().UTC()
+	req.Status = StatusApproved
+	req.CheckerID = &checkerID
+	req.DecidedAt = &decidedAt
+	if decideErr := w.store.Decide(ctx, req); decideErr != nil {
+		return nil, decideErr
+	}
+	w.record(ctx, "approval.approve", req)
+
+	result, execErr := op.Execute(ctx, req)
+
+	executedAt := w.now().UTC()
+	req.ExecutedAt = &executedAt
+	if execErr != nil {
+		reason := execErr.Message
+		req.Status = StatusFailed
+		req.FailureReason = &reason
+	} else {
+		req.Status = StatusExecuted
+		var encodeErr error
+		if req.Result, encodeErr = encodeResult(result); encodeErr != nil {
+			w.logger.WithContext(ctx).WithError(encodeErr).WithField("approval_id", req.ID).
+				Error("Failed to encode approval result")
+		}
+	}
+
+	// The operation has run, so record its outcome even if the client has gone away
+	if completeErr := w.store.Complete(context.WithoutCancel(ctx), req); completeErr != nil {
+		w.logger.WithContext(ctx).WithError(completeErr).With(map[string]interface{}{
+			"approval_id": req.ID,
+			"status":      req.Status,
+		}).Error("Failed to record approval outcome")
+	}
+
+	return req, execErr
+}
+
+// Reject rejects a pending request as the authenticated user. Makers may
+// reject their own requests to withdraw them.
+func (w *Workflow) Reject(ctx context.Context, id, reason string) (*Request, *errors.Error) {
+	req, _, checkerID, err := w.decision(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	decidedAt := w.now().UTC()
+	req.Status = StatusRejected
+	req.CheckerID = &checkerID
+	req.DecisionReason = &reason
+	req.DecidedAt = &decidedAt
+	if decideErr := w.store.Decide(ctx, req); decideErr != nil {
+		return nil, decideErr
+	}
+
+	w.record(ctx, "approval.reject", req)
+	return req, nil
+}
+
+// Get returns one of the service's requests.
+func (w *Workflow) Get(ctx context.Context, id string) (*Request, *errors.Error) {
+	return w.store.Get(ctx, w.service, id)
+}
+
+// List returns a page of the service's requests, newest first, and the
+// number of matching requests.
+func (w *Workflow) List(ctx context.Context, filter *Filter) ([]*Request, int64, *errors.Error) {
+	filter.Service = w.service
+	return w.store.List(ctx, filter)
+}
+
+// decision loads a pending request and checks that the authenticated user
+// holds the permission of its operation.
+func (w *Workflow) decision(ctx context.Context, id string) (*Request, Operation, string, *errors.Error) {
+	checkerID, ok := middleware.GetUserID(ctx)
+	if !ok || checkerID == "" {
+		return nil, Operation{}, "", errors.Unauthorized("user not authenticated")
+	}
+
+	req, err := w.store.Get(ctx, w.service, id)
+	if err != nil {
+		return nil, Operation{}, "", err
+	}
+
+	op, ok := w.operations[req.Operation]
+	if !ok {
+		return nil, Operation{}, "", errors.Internal(fmt.Sprintf("operation %s does not support approval", req.Operation))
+	}
+
+	permissions, _ := middleware.GetUserPermissions(ctx)
+	if !slices.Contains(permissions, op.Permission) {
+		return nil, Operation{}, "", errors.Forbidden(fmt.Sprintf("missing required permission: %s", op.Permission))
+	}
+
+	if req.Status != StatusPending {
+		return nil, Operation{}, "", errors.Conflict(fmt.Sprintf("approval request is already %s", req.Status))
+	}
+
+	return req, op, checkerID, nil
+}
+
+func (w *Workflow) record(ctx context.Context, action string, req *Request) {
+	if w.auditLog == nil {
+		return
+	}
+
+	after := map[string]interface{}{
+		"operation":     req.Operation,
+		"resource_type": req.ResourceType,
+		"resource_id":   req.ResourceID,
+		"status":        req.Status,
+		"maker_id":      req.MakerID,
+	}
+	if req.DecisionReason != nil {
+		after["reason"] = *req.DecisionReason
+	}
+	w.auditLog.Record(ctx, audit.Event{
+		Action:       action,
+		ResourceType: "approval_request",
+		ResourceID:   req.ID,
+		After:        after,
+	})
+}
+
+func encodeResult(result any) (json.RawMessage, error) {
+	if result == nil {
+		return nil, nil
+	}
+	return json.Marshal(result)
+}
@@ -0,0 +1,303 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vnykmshr/nivo/shared/audit"
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/middleware"
+)
+
+// memoryStore keeps requests in memory, enforcing the same rules as PostgresStore.
+type memoryStore struct {
+	requests map[string]*Request
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{requests: make(map[string]*Request)}
+}
+
+func (s *memoryStore) Create(ctx context.Context, req *Request) *errors.Error {
+	for _, existing := range s.requests {
+		if existing.Status == StatusPending && existing.Service == req.Service &&
+			existing.Operation == req.Operation && existing.ResourceID == req.ResourceID {
+			return errors.Conflict("already pending")
+		}
+	}
+	stored := *req
+	s.requests[req.ID] = &stored
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, service, id string) (*Request, *errors.Error) {
+	req, ok := s.requests[id]
+	if !ok || req.Service != service {
+		return nil, errors.NotFoundWithID("approval request", id)
+	}
+	copied := *req
+	return &copied, nil
+}
+
+func (s *memoryStore) List(ctx context.Context, filter *Filter) ([]*Request, int64, *errors.Error) {
+	var requests []*Request
+	for _, req := range s.requests {
+		if req.Service == filter.Service && (filter.Status == "" || req.Status == filter.Status) {
+			requests = append(requests, req)
+		}
+	}
+	return requests, int64(len(requests)), nil
+}
+
+func (s *memoryStore) Decide(ctx context.Context, req *Request) *errors.Error {
+	stored := s.requests[req.ID]
+	if stored.Status != StatusPending {
+		return errors.Conflict("approval request has already been decided")
+	}
+	*stored = *req
+	return nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, req *Request) *errors.Error {
+	*s.requests[req.ID] = *req
+	return nil
+}
+
+type recordingAuditLog struct {
+	actions []string
+}
+
+func (l *recordingAuditLog) Record(ctx context.Context, event audit.Event) {
+	l.actions = append(l.actions, event.Action)
+}
+
+func adminContext(userID string, permissions ...string) context.Context {
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, userID)
+	return context.WithValue(ctx, middleware.UserPermissionsKey, permissions)
+}
+
+type reversePayload struct {
+	Reason string `json:"reason"`
+}
+
+// newTestWorkflow registers a reversal operation that records what it executed.
+func newTestWorkflow(executed *[]string, execErr *errors.Error) (*Workflow, *memoryStore) {
+	store := newMemoryStore()
+	workflow := NewWorkflow(store, "transaction")
+	workflow.Register(Operation{
+		Name:         "transaction.reverse",
+		ResourceType: "transaction",
+		Permission:   "transaction:transaction:reverse",
+		Execute: func(ctx context.Context, req *Request) (any, *errors.Error) {
+			if execErr != nil {
+				return nil, execErr
+			}
+			var payload reversePayload
+			_ = json.Unmarshal(req.Payload, &payload)
+			checker, _ := middleware.GetUserID(ctx)
+			*executed = append(*executed, req.ResourceID+":"+payload.Reason+":"+checker)
+			return map[string]string{"reversal_transaction_id": "rev-1"}, nil
+		},
+	})
+	return workflow, store
+}
+
+func TestWorkflow_SubmitAndApprove(t *testing.T) {
+	var executed []string
+	workflow, _ := newTestWorkflow(&executed, nil)
+	auditLog := &recordingAuditLog{}
+	workflow.SetAuditLog(auditLog)
+
+	maker := adminContext("maker", "transaction:transaction:reverse")
+	req, err := workflow.Submit(maker, "transaction.reverse", "tx-1", reversePayload{Reason: "duplicate charge"})
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if req.Status != StatusPending || req.MakerID != "maker" || req.ResourceType != "transaction" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if len(executed) != 0 {
+		t.Fatal("operation must not run before approval")
+	}
+
+	approved, err := workflow.Approve(adminContext("checker", "transaction:transaction:reverse"), req.ID)
+	if err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+	if approved.Status != StatusExecuted || *approved.CheckerID != "checker" || approved.ExecutedAt == nil {
+		t.Errorf("expected executed request approved by checker, got %+v", approved)
+	}
+	if string(approved.Result) != `{"reversal_transaction_id":"rev-1"}` {
+		t.Errorf("unexpected result: %s", approved.Result)
+	}
+	if len(executed) != 1 || executed[0] != "tx-1:duplicate charge:checker" {
+		t.Errorf("expected operation executed once as the checker, got %v", executed)
+	}
+
+	stored, _ := workflow.Get(context.Background(), req.ID)
+	if stored.Status != StatusExecuted {
+		t.Errorf("expected stored status executed, got %s", stored.Status)
+	}
+	if strings.Join(auditLog.actions, ",") != "approval.submit,approval.approve" {
+		t.Errorf("unexpected audit actions: %v", auditLog.actions)
+	}
+}
+
+func TestWorkflow_ApproveRefused(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode errors.ErrorCode
+	}{
+		{"maker approves own request", adminContext("maker", "transaction:transaction:reverse"), errors.ErrCodeForbidden},
+		{"checker lacks permission", adminContext("checker", "wallet:wallet:freeze"), errors.ErrCodeForbidden},
+		{"unauthenticated", context.Background(), errors.ErrCodeUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var executed []string
+			workflow, _ := newTestWorkflow(&executed, nil)
+			req, _ := workflow.Submit(adminContext("maker"), "transaction.reverse", "tx-1", reversePayload{})
+
+			_, err := workflow.Approve(tt.ctx, req.ID)
+			if err == nil || err.Code != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+			if len(executed) != 0 {
+				t.Error("operation must not run")
+			}
+			if stored, _ := workflow.Get(context.Background(), req.ID); stored.Status != StatusPending {
+				t.Errorf("expected request to stay pending, got %s", stored.Status)
+			}
+		})
+	}
+}
+
+func TestWorkflow_DecidedOnce(t *testing.T) {
+	var executed []string
+	workflow, _ := newTestWorkflow(&executed, nil)
+	req, _ := workflow.Submit(adminContext("maker"), "transaction.reverse", "tx-1", reversePayload{})
+
+	checker := adminContext("checker", "transaction:transaction:reverse")
+	if _, err := workflow.Approve(checker, req.ID); err != nil {
+		t.Fatalf("first approval failed: %v", err)
+	}
+	if _, err := workflow.Approve(adminContext("other", "transaction:transaction:reverse"), req.ID); err == nil || err.Code != errors.ErrCodeConflict {
+		t.Errorf("expected conflict on second approval, got %v", err)
+	}
+	if _, err := workflow.Reject(checker, req.ID, "changed my mind"); err == nil || err.Code != errors.ErrCodeConflict {
+		t.Errorf("expected conflict rejecting an executed request, got %v", err)
+	}
+	if len(executed) != 1 {
+		t.Errorf("expected one execution, got %d", len(executed))
+	}
+}
+
+func TestWorkflow_ExecutionFailure(t *testing.T) {
+	var executed []string
+	workflow, _ := newTestWorkflow(&executed, errors.BadRequest("only completed transactions can be reversed"))
+	req, _ := workflow.Submit(adminContext("maker"), "transaction.reverse", "tx-1", reversePayload{})
+
+	failed, err := workflow.Approve(adminContext("checker", "transaction:transaction:reverse"), req.ID)
+	if err == nil || err.Code != errors.ErrCodeBadRequest {
+		t.Fatalf("expected the operation's error, got %v", err)
+	}
+	if failed.Status != StatusFailed || *failed.FailureReason != "only completed transactions can be reversed" {
+		t.Errorf("expected failed request with reason, got %+v", failed)
+	}
+	if stored, _ := workflow.Get(context.Background(), req.ID); stored.Status != StatusFailed {
+		t.Errorf("expected stored status failed, got %s", stored.Status)
+	}
+}
+
+func TestWorkflow_Reject(t *testing.T) {
+	var executed []string
+	workflow, _ := newTestWorkflow(&executed, nil)
+	req, _ := workflow.Submit(adminContext("maker"), "transaction.reverse", "tx-1", reversePayload{})
+
+	// The maker may withdraw their own request
+	rejected, err := workflow.Reject(adminContext("maker", "transaction:transaction:reverse"), req.ID, "raised in error")
+	if err != nil {
+		t.Fatalf("reject failed: %v", err)
+	}
+	if rejected.Status != StatusRejected || *rejected.DecisionReason != "raised in error" {
+		t.Errorf("unexpected rejected request: %+v", rejected)
+	}
+	if len(executed) != 0 {
+		t.Error("rejected operation must not run")
+	}
+
+	// Once decided, the operation can be requested again
+	if _, err := workflow.Submit(adminContext("maker"), "transaction.reverse", "tx-1", reversePayload{}); err != nil {
+		t.Errorf("expected resubmission after rejection, got %v", err)
+	}
+}
+
+func TestWorkflow_Submit(t *testing.T) {
+	var executed []string
+	workflow, _ := newTestWorkflow(&executed, nil)
+
+	if _, err := workflow.Submit(adminContext("maker"), "wallet.close", "w-1", nil); err == nil || err.Code != errors.ErrCodeInternal {
+		t.Errorf("expected unregistered operation to be refused, got %v", err)
+	}
+	if _, err := workflow.Submit(context.Background(), "transaction.reverse", "tx-1", nil); err == nil || err.Code != errors.ErrCodeUnauthorized {
+		t.Errorf("expected unauthenticated submission to be refused, got %v", err)
+	}
+
+	if _, err := workflow.Submit(adminContext("maker"), "transaction.reverse", "tx-1", nil); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if _, err := workflow.Submit(adminContext("other"), "transaction.reverse", "tx-1", nil); err == nil || err.Code != errors.ErrCodeConflict {
+		t.Errorf("expected conflict for a second pending request, got %v", err)
+	}
+}
+
+func TestFilterWhere(t *testing.T) {
+	where, args := (&Filter{Service: "wallet", Status: StatusPending}).where()
+
+	if where != " WHERE service = $1 AND status = $2" {
+		t.Errorf("unexpected where clause: %q", where)
+	}
+	if len(args) != 2 || args[0] != "wallet" || args[1] != "pending" {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestHandler_Reject(t *testing.T) {
+	var executed []string
+	workflow, _ := newTestWorkflow(&executed, nil)
+	req, _ := workflow.Submit(adminContext("maker"), "transaction.reverse", "tx-1", reversePayload{})
+	handler := NewHandler(workflow)
+
+	reject := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/admin/transactions/approvals/"+req.ID+"/reject", strings.NewReader(body))
+		r.SetPathValue("id", req.ID)
+		r = r.WithContext(adminContext("checker", "transaction:transaction:reverse"))
+		rec := httptest.NewRecorder()
+		handler.Reject(rec, r)
+		return rec
+	}
+
+	if rec := reject(`{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without a reason, got %d", rec.Code)
+	}
+	if rec := reject(`{"reason":"not a duplicate"}`); rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_ListInvalidStatus(t *testing.T) {
+	var executed []string
+	workflow, _ := newTestWorkflow(&executed, nil)
+
+	rec := httptest.NewRecorder()
+	NewHandler(workflow).List(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/approvals?status=done", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,97 @@
+package approval
+
+import (
+	"net/http"
+
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/handler"
+	"github.com/vnykmshr/nivo/shared/pagination"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+// RejectRequest is the body of a rejection.
+type RejectRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+// Handler serves a service's approval queue to checkers. Each service mounts
+// it under its own admin prefix, behind auth and permission middleware.
+type Handler struct {
+	workflow *Workflow
+}
+
+// NewHandler creates a new approval handler.
+func NewHandler(workflow *Workflow) *Handler {
+	return &Handler{
+		workflow: workflow,
+	}
+}
+
+// List handles GET .../approvals?status=pending&operation=...
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &Filter{
+		Status:     Status(query.Get("status")),
+		Operation:  query.Get("operation"),
+		ResourceID: query.Get("resource_id"),
+		MakerID:    query.Get("maker_id"),
+	}
+	switch filter.Status {
+	case "", StatusPending, StatusApproved, StatusRejected, StatusExecuted, StatusFailed:
+	default:
+		response.Error(w, errors.BadRequest("invalid status filter"))
+		return
+	}
+
+	params := pagination.FromRequest(r)
+	filter.Limit = params.PerPage
+	filter.Offset = params.Offset
+
+	requests, total, err := h.workflow.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, requests, params.Page, params.PerPage, total)
+}
+
+// Get handles GET .../approvals/{id}
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	req, err := h.workflow.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, req)
+}
+
+// Approve handles POST .../approvals/{id}/approve
+// Executes the operation; its error is returned if it fails.
+func (h *Handler) Approve(w http.ResponseWriter, r *http.Request) {
+	req, err := h.workflow.Approve(r.Context(), r.PathValue("id"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, req)
+}
+
+// Reject handles POST .../approvals/{id}/reject
+func (h *Handler) Reject(w http.ResponseWriter, r *http.Request) {
+	body, bindErr := handler.BindRequest[RejectRequest](r)
+	if bindErr != nil {
+		response.Error(w, bindErr)
+		return
+	}
+
+	req, err := h.workflow.Reject(r.Context(), r.PathValue("id"), body.Reason)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, req)
+}
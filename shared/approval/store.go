@@ -0,0 +1,182 @@
+package approval
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vnykmshr/nivo/shared/database"
+	"github.com/vnykmshr/nivo/shared/errors"
+)
+
+const requestColumns = `id, service, operation, resource_type, resource_id, payload, status, maker_id,
+	checker_id, decision_reason, result, failure_reason, created_at, decided_at, executed_at`
+
+// PostgresStore keeps approval requests in the approval_requests table of the
+// shared database, created by the rbac service's 009_rbac_approval_requests migration.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates an approval store backed by the given database.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Create inserts a pending request.
+func (s *PostgresStore) Create(ctx context.Context, req *Request) *errors.Error {
+	query := `INSERT INTO approval_requests (id, service, operation, resource_type, resource_id, payload, status, maker_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := s.db.ExecContext(ctx, query,
+		req.ID, req.Service, req.Operation, req.ResourceType, req.ResourceID,
+		string(req.Payload), req.Status, req.MakerID, req.CreatedAt,
+	)
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return errors.Conflict(fmt.Sprintf("an approval request for %s on %s is already pending", req.Operation, req.ResourceID))
+		}
+		return errors.DatabaseWrap(err, "failed to create approval request")
+	}
+	return nil
+}
+
+// Get retrieves one of the service's requests by ID.
+func (s *PostgresStore) Get(ctx context.Context, service, id string) (*Request, *errors.Error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.NotFoundWithID("approval request", id)
+	}
+
+	query := `SELECT ` + requestColumns + ` FROM approval_requests WHERE id = $1 AND service = $2`
+	req, err := scanRequest(s.db.QueryRowContext(ctx, query, id, service))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundWithID("approval request", id)
+		}
+		return nil, errors.DatabaseWrap(err, "failed to get approval request")
+	}
+	return req, nil
+}
+
+// List returns a page of requests matching filter, newest first, and the
+// number of matching requests.
+func (s *PostgresStore) List(ctx context.Context, filter *Filter) ([]*Request, int64, *errors.Error) {
+	where, args := filter.where()
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM approval_requests`+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to count approval requests")
+	}
+
+	query := `SELECT ` + requestColumns + ` FROM approval_requests` + where + ` ORDER BY created_at DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to list approval requests")
+	}
+	defer func() { _ = rows.Close() }()
+
+	requests := make([]*Request, 0)
+	for rows.Next() {
+		req, err := scanRequest(rows)
+		if err != nil {
+			return nil, 0, errors.DatabaseWrap(err, "failed to scan approval request")
+		}
+		requests = append(requests, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.DatabaseWrap(err, "failed to read approval requests")
+	}
+
+	return requests, total, nil
+}
+
+// Decide moves a pending request to its decided status. The status condition
+// makes concurrent decisions safe: only the first one updates the row.
+func (s *PostgresStore) Decide(ctx context.Context, req *Request) *errors.Error {
+	query := `UPDATE approval_requests
+		SET status = $2, checker_id = $3, decision_reason = $4, decided_at = $5
+		WHERE id = $1 AND status = 'pending'`
+	result, err := s.db.ExecContext(ctx, query, req.ID, req.Status, req.CheckerID, req.DecisionReason, req.DecidedAt)
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to decide approval request")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.DatabaseWrap(err, "failed to decide approval request")
+	}
+	if rows == 0 {
+		return errors.Conflict("approval request has already been decided")
+	}
+	return nil
+}
+
+// Complete records the outcome of executing an approved request.
+func (s *PostgresStore) Complete(ctx context.Context, req *Request) *errors.Error {
+	query := `UPDATE approval_requests
+		SET status = $2, result = $3, failure_reason = $4, executed_at = $5
+		WHERE id = $1 AND status = 'approved'`
+	if _, err := s.db.ExecContext(ctx, query, req.ID, req.Status, nullableJSON(req.Result), req.FailureReason, req.ExecutedAt); err != nil {
+		return errors.DatabaseWrap(err, "failed to complete approval request")
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRequest(row scanner) (*Request, error) {
+	req := &Request{}
+	var payload, result []byte
+	if err := row.Scan(
+		&req.ID, &req.Service, &req.Operation, &req.ResourceType, &req.ResourceID, &payload, &req.Status, &req.MakerID,
+		&req.CheckerID, &req.DecisionReason, &result, &req.FailureReason, &req.CreatedAt, &req.DecidedAt, &req.ExecutedAt,
+	); err != nil {
+		return nil, err
+	}
+	req.Payload = json.RawMessage(payload)
+	if result != nil {
+		req.Result = json.RawMessage(result)
+	}
+	return req, nil
+}
+
+// where builds the WHERE clause for the filter.
+func (f *Filter) where() (string, []any) {
+	var conditions []string
+	var args []any
+
+	for _, field := range []struct{ column, value string }{
+		{"service", f.Service},
+		{"status", string(f.Status)},
+		{"operation", f.Operation},
+		{"resource_id", f.ResourceID},
+		{"maker_id", f.MakerID},
+	} {
+		if field.value != "" {
+			args = append(args, field.value)
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", field.column, len(args)))
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// nullableJSON passes JSON as text: lib/pq would send []byte as bytea.
+func nullableJSON(data json.RawMessage) any {
+	if data == nil {
+		return nil
+	}
+	return string(data)
+}
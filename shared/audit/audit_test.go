@@ -0,0 +1,225 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/logger"
+	"github.com/vnykmshr/nivo/shared/middleware"
+)
+
+// memoryStore chains entries in memory, as PostgresStore does.
+type memoryStore struct {
+	entries []*Entry
+	err     error
+}
+
+func (s *memoryStore) Append(ctx context.Context, entry *Entry) error {
+	if s.err != nil {
+		return s.err
+	}
+	prevSequence, prevHash := int64(0), GenesisHash
+	if n := len(s.entries); n > 0 {
+		prevSequence, prevHash = s.entries[n-1].Sequence, s.entries[n-1].Hash
+	}
+	link(entry, prevSequence, prevHash)
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *memoryStore) verify() Verification {
+	verifier := newChainVerifier()
+	for _, entry := range s.entries {
+		if !verifier.check(entry) {
+			break
+		}
+	}
+	return verifier.result
+}
+
+func TestLog_Record(t *testing.T) {
+	store := &memoryStore{}
+	log := NewLog(store, "wallet")
+	log.now = func() time.Time { return time.Date(2024, 3, 1, 10, 0, 0, 123456789, time.UTC) }
+
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, "admin-1")
+	ctx = context.WithValue(ctx, middleware.AccountTypeKey, "admin")
+	ctx = context.WithValue(ctx, logger.RequestIDKey, "req-1")
+	ctx = context.WithValue(ctx, ipKey, "203.0.113.7")
+
+	log.Record(ctx, Event{
+		Action:       "wallet.freeze",
+		ResourceType: "wallet",
+		ResourceID:   "wallet-1",
+		Before:       map[string]string{"status": "active"},
+		After:        map[string]string{"status": "frozen"},
+	})
+
+	if len(store.entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(store.entries))
+	}
+	entry := store.entries[0]
+	if entry.Service != "wallet" || entry.ActorID != "admin-1" || entry.ActorAccountType != "admin" {
+		t.Errorf("unexpected service or actor: %+v", entry)
+	}
+	if entry.RequestID != "req-1" || entry.IPAddress != "203.0.113.7" {
+		t.Errorf("expected request ID and IP from context, got %q, %q", entry.RequestID, entry.IPAddress)
+	}
+	if string(entry.Before) != `{"status":"active"}` || string(entry.After) != `{"status":"frozen"}` {
+		t.Errorf("unexpected snapshots: %s, %s", entry.Before, entry.After)
+	}
+	if entry.CreatedAt.Nanosecond() != 123456000 {
+		t.Errorf("expected created_at truncated to microseconds, got %v", entry.CreatedAt)
+	}
+	if entry.Sequence != 1 || entry.PrevHash != GenesisHash || entry.Hash != entry.computeHash() {
+		t.Errorf("expected first entry linked to genesis, got %+v", entry)
+	}
+}
+
+func TestLog_RecordActor(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantID   string
+		wantType string
+	}{
+		{
+			name:     "authenticated user",
+			ctx:      context.WithValue(context.WithValue(context.Background(), middleware.UserIDKey, "user-1"), middleware.AccountTypeKey, "admin"),
+			wantID:   "user-1",
+			wantType: "admin",
+		},
+		{
+			name:     "calling service",
+			ctx:      context.WithValue(context.Background(), middleware.CallerServiceKey, "transaction"),
+			wantID:   "transaction",
+			wantType: ActorTypeService,
+		},
+		{
+			name:     "no actor",
+			ctx:      context.Background(),
+			wantID:   ActorSystem,
+			wantType: ActorSystem,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryStore{}
+			NewLog(store, "identity").Record(tt.ctx, Event{Action: "user.suspend", ResourceType: "user", ResourceID: "u"})
+
+			entry := store.entries[0]
+			if entry.ActorID != tt.wantID || entry.ActorAccountType != tt.wantType {
+				t.Errorf("expected actor %s (%s), got %s (%s)", tt.wantID, tt.wantType, entry.ActorID, entry.ActorAccountType)
+			}
+			if entry.Before != nil || entry.After != nil {
+				t.Errorf("expected no snapshots, got %s, %s", entry.Before, entry.After)
+			}
+		})
+	}
+}
+
+func TestLog_RecordStoreFailure(t *testing.T) {
+	store := &memoryStore{err: errors.New("connection refused")}
+	// Must not panic or return anything: the action already happened
+	NewLog(store, "rbac").Record(context.Background(), Event{Action: "role.assign"})
+}
+
+func TestChainVerification(t *testing.T) {
+	newChain := func() *memoryStore {
+		store := &memoryStore{}
+		log := NewLog(store, "identity")
+		for _, id := range []string{"u1", "u2", "u3"} {
+			log.Record(context.Background(), Event{
+				Action:       "user.suspend",
+				ResourceType: "user",
+				ResourceID:   id,
+				After:        map[string]string{"status": "suspended"},
+			})
+		}
+		return store
+	}
+
+	if result := newChain().verify(); !result.Valid || result.Entries != 3 {
+		t.Fatalf("expected intact chain of 3, got %+v", result)
+	}
+
+	tests := []struct {
+		name     string
+		tamper   func(s *memoryStore)
+		brokenAt int64
+	}{
+		{"edited field", func(s *memoryStore) { s.entries[1].ActorID = "someone-else" }, 2},
+		{"edited snapshot", func(s *memoryStore) { s.entries[0].After = json.RawMessage(`{"status":"active"}`) }, 1},
+		{"removed entry", func(s *memoryStore) { s.entries = append(s.entries[:1], s.entries[2:]...) }, 3},
+		{"rehashed entry", func(s *memoryStore) {
+			s.entries[1].ResourceID = "u9"
+			s.entries[1].Hash = s.entries[1].computeHash()
+		}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newChain()
+			tt.tamper(store)
+
+			result := store.verify()
+			if result.Valid || result.BrokenAt != tt.brokenAt || result.Reason == "" {
+				t.Errorf("expected chain broken at %d, got %+v", tt.brokenAt, result)
+			}
+		})
+	}
+}
+
+func TestFilterWhere(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	where, args := (&Filter{Action: "wallet.freeze", Service: "wallet", From: &from}).where()
+
+	if where != " WHERE action = $1 AND service = $2 AND created_at >= $3" {
+		t.Errorf("unexpected where clause: %q", where)
+	}
+	if len(args) != 3 || args[0] != "wallet.freeze" || args[1] != "wallet" || args[2] != from {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	if where, args := (&Filter{}).where(); where != "" || args != nil {
+		t.Errorf("expected no conditions, got %q, %v", where, args)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		realIP     string
+		remoteAddr string
+		want       string
+	}{
+		{"gateway header", "198.51.100.1", "10.0.0.5:41000", "198.51.100.1"},
+		{"direct connection", "", "10.0.0.5:41000", "10.0.0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ipFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/1/suspend", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("expected IP %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GenesisHash is the previous hash of the first entry in the chain.
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// hashedFields is what an entry's hash covers. Snapshots are hashed as the
+// bytes stored, so they must not be reformatted on the way in or out.
+type hashedFields struct {
+	Sequence         int64  `json:"sequence"`
+	ID               string `json:"id"`
+	Service          string `json:"service"`
+	ActorID          string `json:"actor_id"`
+	ActorAccountType string `json:"actor_account_type"`
+	Action           string `json:"action"`
+	ResourceType     string `json:"resource_type"`
+	ResourceID       string `json:"resource_id"`
+	Before           string `json:"before"`
+	After            string `json:"after"`
+	RequestID        string `json:"request_id"`
+	IPAddress        string `json:"ip_address"`
+	CreatedAt        string `json:"created_at"`
+	PrevHash         string `json:"prev_hash"`
+}
+
+// computeHash returns the SHA-256 of the entry's fields and previous hash.
+func (e *Entry) computeHash() string {
+	data, _ := json.Marshal(hashedFields{
+		Sequence:         e.Sequence,
+		ID:               e.ID,
+		Service:          e.Service,
+		ActorID:          e.ActorID,
+		ActorAccountType: e.ActorAccountType,
+		Action:           e.Action,
+		ResourceType:     e.ResourceType,
+		ResourceID:       e.ResourceID,
+		Before:           string(e.Before),
+		After:            string(e.After),
+		RequestID:        e.RequestID,
+		IPAddress:        e.IPAddress,
+		CreatedAt:        e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:         e.PrevHash,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// link makes entry the successor of the entry with prevSequence and prevHash
+// (0 and GenesisHash for the first entry).
+func link(entry *Entry, prevSequence int64, prevHash string) {
+	entry.Sequence = prevSequence + 1
+	entry.PrevHash = prevHash
+	entry.Hash = entry.computeHash()
+}
+
+// Verification is the result of checking the chain.
+type Verification struct {
+	Valid    bool  `json:"valid"`
+	Entries  int64 `json:"entries"` // Entries checked, up to and including the first broken one
+	BrokenAt int64 `json:"broken_at,omitempty"`
+	// Reason describes why the entry at BrokenAt does not fit the chain
+	Reason string `json:"reason,omitempty"`
+}
+
+// chainVerifier checks entries one at a time, in sequence order.
+type chainVerifier struct {
+	prevSequence int64
+	prevHash     string
+	result       Verification
+}
+
+func newChainVerifier() *chainVerifier {
+	return &chainVerifier{prevHash: GenesisHash, result: Verification{Valid: true}}
+}
+
+// check adds entry to the verification, returning false once the chain is broken.
+func (v *chainVerifier) check(entry *Entry) bool {
+	v.result.Entries++
+
+	var reason string
+	switch {
+	case entry.Sequence != v.prevSequence+1:
+		reason = fmt.Sprintf("expected sequence %d, entries are missing", v.prevSequence+1)
+	case entry.PrevHash != v.prevHash:
+		reason = "previous hash does not match the preceding entry"
+	case entry.Hash != entry.computeHash():
+		reason = "hash does not match the entry's contents"
+	}
+	if reason != "" {
+		v.result.Valid = false
+		v.result.BrokenAt = entry.Sequence
+		v.result.Reason = reason
+		return false
+	}
+
+	v.prevSequence = entry.Sequence
+	v.prevHash = entry.Hash
+	return true
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/vnykmshr/nivo/shared/middleware"
+)
+
+type contextKey string
+
+const ipKey contextKey = "audit_ip"
+
+const (
+	// ActorTypeService is the account type recorded for calls made by another service.
+	ActorTypeService = "service"
+	// ActorSystem is recorded when an action has no user or service behind it.
+	ActorSystem = "system"
+)
+
+type actor struct {
+	ID          string
+	AccountType string
+}
+
+// actorFromContext returns the user set by the auth middleware, else the
+// calling service.
+func actorFromContext(ctx context.Context) actor {
+	if userID, ok := middleware.GetUserID(ctx); ok && userID != "" {
+		accountType, _ := middleware.GetAccountType(ctx)
+		return actor{ID: userID, AccountType: accountType}
+	}
+	if caller, ok := middleware.GetCallerService(ctx); ok {
+		return actor{ID: caller, AccountType: ActorTypeService}
+	}
+	return actor{ID: ActorSystem, AccountType: ActorSystem}
+}
+
+// Middleware adds the client IP address to the request context so it can be
+// recorded with audit entries.
+func Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), ipKey, clientIP(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func ipFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ipKey).(string)
+	return ip
+}
+
+// clientIP prefers X-Real-IP, which the gateway sets on proxied requests,
+// over the address of the connection.
+func clientIP(r *http.Request) string {
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
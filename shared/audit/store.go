@@ -0,0 +1,223 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// chainLockID is the Postgres advisory lock held while appending, so entries
+// from every service link to the one before them. The value is "audit" in ASCII.
+const chainLockID = 0x6175646974
+
+// verifyBatchSize is how many entries Verify reads at a time.
+const verifyBatchSize = 1000
+
+const entryColumns = `sequence, id, service, actor_id, actor_account_type, action, resource_type, resource_id,
+	before_snapshot, after_snapshot, request_id, ip_address, created_at, prev_hash, hash`
+
+// Filter selects audit entries. Empty fields match everything.
+type Filter struct {
+	ActorID      string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Service      string
+	RequestID    string
+	From         *time.Time // Inclusive
+	To           *time.Time // Exclusive
+	Limit        int
+	Offset       int
+}
+
+// Reader queries the audit log.
+type Reader interface {
+	List(ctx context.Context, filter *Filter) ([]*Entry, int64, error)
+	Each(ctx context.Context, filter *Filter, fn func(*Entry) error) error
+	Verify(ctx context.Context) (*Verification, error)
+}
+
+// PostgresStore keeps the audit log in the audit_log table of the shared
+// database, created by the rbac service's 005_rbac_audit_log migration.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates an audit store backed by the given database.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Append links entry to the end of the chain and inserts it.
+func (s *PostgresStore) Append(ctx context.Context, entry *Entry) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin audit transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, chainLockID); err != nil {
+		return fmt.Errorf("failed to lock audit chain: %w", err)
+	}
+
+	prevSequence, prevHash := int64(0), GenesisHash
+	err = tx.QueryRowContext(ctx, `SELECT sequence, hash FROM audit_log ORDER BY sequence DESC LIMIT 1`).
+		Scan(&prevSequence, &prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read last audit entry: %w", err)
+	}
+
+	link(entry, prevSequence, prevHash)
+
+	query := `INSERT INTO audit_log (` + entryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+	_, err = tx.ExecContext(ctx, query,
+		entry.Sequence, entry.ID, entry.Service, entry.ActorID, entry.ActorAccountType,
+		entry.Action, entry.ResourceType, entry.ResourceID,
+		nullableJSON(entry.Before), nullableJSON(entry.After),
+		entry.RequestID, entry.IPAddress, entry.CreatedAt, entry.PrevHash, entry.Hash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// List returns a page of entries matching filter, newest first, and the
+// number of matching entries.
+func (s *PostgresStore) List(ctx context.Context, filter *Filter) ([]*Entry, int64, error) {
+	where, args := filter.where()
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	query := `SELECT ` + entryColumns + ` FROM audit_log` + where + ` ORDER BY sequence DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	}
+
+	entries := make([]*Entry, 0)
+	err := s.query(ctx, query, args, func(entry *Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// Each calls fn for every entry matching filter, oldest first, without
+// loading them all at once. Limit and Offset are ignored.
+func (s *PostgresStore) Each(ctx context.Context, filter *Filter, fn func(*Entry) error) error {
+	where, args := filter.where()
+	return s.query(ctx, `SELECT `+entryColumns+` FROM audit_log`+where+` ORDER BY sequence`, args, fn)
+}
+
+// Verify walks the whole chain, recomputing every hash, and reports the
+// first entry that was altered, removed or inserted out of order.
+func (s *PostgresStore) Verify(ctx context.Context) (*Verification, error) {
+	verifier := newChainVerifier()
+	for {
+		checked := 0
+		broken := false
+		query := `SELECT ` + entryColumns + ` FROM audit_log WHERE sequence > $1 ORDER BY sequence LIMIT $2`
+		err := s.query(ctx, query, []any{verifier.prevSequence, verifyBatchSize}, func(entry *Entry) error {
+			checked++
+			if !broken && !verifier.check(entry) {
+				broken = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if broken || checked < verifyBatchSize {
+			return &verifier.result, nil
+		}
+	}
+}
+
+func (s *PostgresStore) query(ctx context.Context, query string, args []any, fn func(*Entry) error) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		entry := &Entry{}
+		var before, after []byte
+		if err := rows.Scan(
+			&entry.Sequence, &entry.ID, &entry.Service, &entry.ActorID, &entry.ActorAccountType,
+			&entry.Action, &entry.ResourceType, &entry.ResourceID, &before, &after,
+			&entry.RequestID, &entry.IPAddress, &entry.CreatedAt, &entry.PrevHash, &entry.Hash,
+		); err != nil {
+			return fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if before != nil {
+			entry.Before = json.RawMessage(before)
+		}
+		if after != nil {
+			entry.After = json.RawMessage(after)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read audit entries: %w", err)
+	}
+	return nil
+}
+
+// where builds the WHERE clause for the filter.
+func (f *Filter) where() (string, []any) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	for _, field := range []struct{ column, value string }{
+		{"actor_id", f.ActorID},
+		{"action", f.Action},
+		{"resource_type", f.ResourceType},
+		{"resource_id", f.ResourceID},
+		{"service", f.Service},
+		{"request_id", f.RequestID},
+	} {
+		if field.value != "" {
+			add(field.column+" = $%d", field.value)
+		}
+	}
+	if f.From != nil {
+		add("created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("created_at < $%d", *f.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// nullableJSON passes snapshots as text: lib/pq would send []byte as bytea.
+func nullableJSON(data json.RawMessage) any {
+	if data == nil {
+		return nil
+	}
+	return string(data)
+}
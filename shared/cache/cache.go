@@ -21,6 +21,10 @@ type Cache interface {
 	// Exists checks if a key exists in the cache.
 	Exists(ctx context.Context, key string) (bool, error)
 
+	// Increment atomically adds one to a counter and resets its TTL, returning the new value.
+	// A missing key starts at zero.
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+
 	// Ping checks the connection health.
 	Ping(ctx context.Context) error
 
@@ -48,6 +52,10 @@ func (n *NoOpCache) Exists(ctx context.Context, key string) (bool, error) {
 	return false, nil
 }
 
+func (n *NoOpCache) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return 0, nil
+}
+
 func (n *NoOpCache) Ping(ctx context.Context) error {
 	return nil
 }
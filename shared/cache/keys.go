@@ -12,6 +12,9 @@ const (
 	PrefixSession = "session:"
 	PrefixUser    = "user:"
 	PrefixToken   = "token:"
+
+	PrefixLoginFailures = "login_failures:"
+	PrefixLoginBlock    = "login_block:"
 )
 
 // Default TTLs
@@ -46,6 +49,18 @@ func TokenKey(token string) string {
 	return fmt.Sprintf("%s%s", PrefixToken, hex.EncodeToString(hash[:]))
 }
 
+// LoginFailuresKey generates a cache key for counting failed logins.
+// Format: login_failures:{identifier_hash}
+func LoginFailuresKey(identifierHash string) string {
+	return PrefixLoginFailures + identifierHash
+}
+
+// LoginBlockKey generates a cache key for a login delay or lockout.
+// Format: login_block:{identifier_hash}
+func LoginBlockKey(identifierHash string) string {
+	return PrefixLoginBlock + identifierHash
+}
+
 // HashToken creates a SHA-256 hash of a token string.
 func HashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
@@ -110,6 +110,20 @@ func (r *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 	return count > 0, nil
 }
 
+// Increment atomically increments a counter and resets its TTL.
+func (r *RedisCache) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("redis increment error: %w", err)
+	}
+	return incr.Val(), nil
+}
+
 // Ping checks the connection health.
 func (r *RedisCache) Ping(ctx context.Context) error {
 	if err := r.client.Ping(ctx).Err(); err != nil {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/vnykmshr/nivo/shared/config"
 	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 )
 
 // Default timeouts for service clients
@@ -28,6 +29,8 @@ type BaseClient struct {
 	baseURL        string
 	httpClient     *http.Client
 	defaultHeaders map[string]string
+	serviceTokens  ServiceTokenSource
+	audience       string
 }
 
 // NewBaseClient creates a new base client with the specified timeout.
@@ -60,21 +63,13 @@ func (c *BaseClient) SetAuthToken(token string) {
 	c.defaultHeaders["Authorization"] = "Bearer " + token
 }
 
-// SetInternalSecret sets the internal service secret for service-to-service calls.
-// This header is validated by the InternalAuth middleware on receiving services.
-func (c *BaseClient) SetInternalSecret(secret string) {
-	if secret != "" {
-		c.defaultHeaders["X-Internal-Secret"] = secret
-	}
-}
-
-// NewInternalClient creates a base client configured for internal service-to-service calls.
-// The secret is sent as X-Internal-Secret header and validated by InternalAuth middleware.
-func NewInternalClient(baseURL string, timeout time.Duration, internalSecret string) *BaseClient {
+// NewInternalClient creates a base client for calling another service's internal
+// endpoints. Every request carries a service token for audience, the called
+// service's name, which it checks against the route's allow-list.
+func NewInternalClient(baseURL string, timeout time.Duration, tokens ServiceTokenSource, audience string) *BaseClient {
 	client := NewBaseClient(baseURL, timeout)
-	if internalSecret != "" {
-		client.defaultHeaders["X-Internal-Secret"] = internalSecret
-	}
+	client.serviceTokens = tokens
+	client.audience = audience
 	return client
 }
 
@@ -176,6 +171,14 @@ func (c *BaseClient) doRequest(req *http.Request, result any, headers map[string
 		req.Header.Set(k, v)
 	}
 
+	if c.serviceTokens != nil {
+		token, err := c.serviceTokens.Token(req.Context(), c.audience)
+		if err != nil {
+			return errors.Unavailable(fmt.Sprintf("failed to get service token: %v", err))
+		}
+		req.Header.Set(sharedjwt.ServiceTokenHeader, token)
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return errors.Internal(fmt.Sprintf("request failed: %v", err))
@@ -305,6 +308,9 @@ func errorForStatusCode(statusCode int, msg string) *errors.Error {
 		return errors.Unauthorized(msg)
 	case http.StatusForbidden:
 		return errors.Forbidden(msg)
+	case http.StatusAccepted:
+		// Not a success code for any call: the operation needs step-up verification
+		return errors.VerificationRequired(msg)
 	default:
 		return errors.Internal(msg)
 	}
@@ -376,6 +376,7 @@ func TestBaseClient_ErrorStatusCodes(t *testing.T) {
 		{http.StatusBadRequest, http.StatusBadRequest, "bad request"},
 		{http.StatusUnauthorized, http.StatusUnauthorized, "unauthorized"},
 		{http.StatusForbidden, http.StatusForbidden, "forbidden"},
+		{http.StatusAccepted, http.StatusAccepted, "verification required"},
 		{http.StatusNotFound, http.StatusNotFound, "not found"},
 		{http.StatusInternalServerError, http.StatusInternalServerError, "server error"},
 	}
@@ -51,6 +51,7 @@ type SendNotificationRequest struct {
 	Type          NotificationType     `json:"type"`
 	Priority      NotificationPriority `json:"priority"`
 	TemplateID    string               `json:"template_id"`
+	Locale        string               `json:"locale,omitempty"` // Template variant, e.g. hi-IN; falls back to English
 	Variables     map[string]any       `json:"variables,omitempty"`
 	CorrelationID *string              `json:"correlation_id,omitempty"`
 	SourceService string               `json:"source_service"`
@@ -0,0 +1,71 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// serviceTokenRefreshMargin is how long before expiry a cached service token is replaced.
+const serviceTokenRefreshMargin = time.Minute
+
+// ServiceTokenSource provides the token a service presents when calling
+// another service's internal endpoints.
+type ServiceTokenSource interface {
+	// Token returns a token for calling the audience service.
+	Token(ctx context.Context, audience string) (string, error)
+}
+
+// ServiceToken is a service token issued by the identity service.
+type ServiceToken struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// ServiceTokenClient obtains service tokens from the identity service using
+// this service's credential. Tokens are cached per audience until shortly
+// before they expire.
+type ServiceTokenClient struct {
+	*BaseClient
+	service string
+	secret  string
+
+	mu     sync.Mutex
+	tokens map[string]*ServiceToken
+}
+
+// NewServiceTokenClient creates a token client for the named service.
+func NewServiceTokenClient(identityURL, service, secret string) *ServiceTokenClient {
+	return &ServiceTokenClient{
+		BaseClient: NewBaseClient(identityURL, ShortTimeout),
+		service:    service,
+		secret:     secret,
+		tokens:     make(map[string]*ServiceToken),
+	}
+}
+
+// issueServiceTokenRequest is the identity service's token request.
+type issueServiceTokenRequest struct {
+	Service  string `json:"service"`
+	Secret   string `json:"secret"`
+	Audience string `json:"audience"`
+}
+
+// Token returns a cached token for audience, or requests a new one.
+func (c *ServiceTokenClient) Token(ctx context.Context, audience string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.tokens[audience]; ok && time.Until(time.Unix(cached.ExpiresAt, 0)) > serviceTokenRefreshMargin {
+		return cached.Token, nil
+	}
+
+	req := &issueServiceTokenRequest{Service: c.service, Secret: c.secret, Audience: audience}
+	var token ServiceToken
+	if err := c.Post(ctx, "/internal/v1/service-tokens", req, &token); err != nil {
+		return "", err
+	}
+
+	c.tokens[audience] = &token
+	return token.Token, nil
+}
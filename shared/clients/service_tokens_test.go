@@ -0,0 +1,115 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
+)
+
+// staticServiceToken is a ServiceTokenSource returning the same token for every audience.
+type staticServiceToken string
+
+func (s staticServiceToken) Token(ctx context.Context, audience string) (string, error) {
+	return string(s), nil
+}
+
+type failingServiceToken struct{}
+
+func (failingServiceToken) Token(ctx context.Context, audience string) (string, error) {
+	return "", errors.New("identity unavailable")
+}
+
+func TestServiceTokenClient_Token(t *testing.T) {
+	var issued atomic.Int32
+	var expiresIn atomic.Int64
+	expiresIn.Store(int64(time.Hour))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/internal/v1/service-tokens" {
+			t.Errorf("expected service token path, got %s", r.URL.Path)
+		}
+
+		var body issueServiceTokenRequest
+		readJSON(r, &body)
+		if body.Service != "wallet" || body.Secret != "wallet-secret" {
+			t.Errorf("unexpected credential: %+v", body)
+		}
+
+		n := issued.Add(1)
+		writeJSON(w, map[string]any{"success": true, "data": ServiceToken{
+			Token:     fmt.Sprintf("%s-token-%d", body.Audience, n),
+			ExpiresAt: time.Now().Add(time.Duration(expiresIn.Load())).Unix(),
+		}})
+	}))
+	defer server.Close()
+
+	client := NewServiceTokenClient(server.URL, "wallet", "wallet-secret")
+
+	t.Run("caches tokens per audience", func(t *testing.T) {
+		first, err := client.Token(context.Background(), "ledger")
+		if err != nil {
+			t.Fatalf("expected token, got %v", err)
+		}
+		again, _ := client.Token(context.Background(), "ledger")
+		if again != first {
+			t.Errorf("expected cached token %s, got %s", first, again)
+		}
+
+		other, _ := client.Token(context.Background(), "transaction")
+		if other == first {
+			t.Error("expected a separate token for another audience")
+		}
+		if got := issued.Load(); got != 2 {
+			t.Errorf("expected 2 tokens issued, got %d", got)
+		}
+	})
+
+	t.Run("replaces tokens close to expiry", func(t *testing.T) {
+		expiresIn.Store(int64(30 * time.Second))
+		first, _ := client.Token(context.Background(), "identity")
+		second, _ := client.Token(context.Background(), "identity")
+		if first == second {
+			t.Error("expected a token about to expire to be replaced")
+		}
+	})
+}
+
+func TestInternalClient_SendsServiceToken(t *testing.T) {
+	t.Run("adds token header", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(sharedjwt.ServiceTokenHeader) != "svc-token" {
+				t.Errorf("expected service token header, got %q", r.Header.Get(sharedjwt.ServiceTokenHeader))
+			}
+			writeJSON(w, map[string]any{"success": true})
+		}))
+		defer server.Close()
+
+		client := NewInternalClient(server.URL, ShortTimeout, staticServiceToken("svc-token"), "ledger")
+		if err := client.Get(context.Background(), "/internal/v1/test", nil); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("fails without calling when no token is available", func(t *testing.T) {
+		var called atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called.Store(true)
+		}))
+		defer server.Close()
+
+		client := NewInternalClient(server.URL, ShortTimeout, failingServiceToken{}, "ledger")
+		if err := client.Get(context.Background(), "/internal/v1/test", nil); err == nil {
+			t.Error("expected an error without a service token")
+		}
+		if called.Load() {
+			t.Error("expected the request not to be sent")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
+)
+
+func TestVerificationClient_Redeem(t *testing.T) {
+	metadata := map[string]any{"phone": "+919876543210"}
+
+	t.Run("missing token requires verification without calling identity", func(t *testing.T) {
+		client := NewVerificationClient("http://127.0.0.1:0", staticServiceToken("svc-token"))
+
+		err := client.Redeem(context.Background(), "user-1", VerificationOpBeneficiaryAdd, "", metadata)
+		if err == nil || err.Code != errors.ErrCodeVerificationRequired {
+			t.Fatalf("expected verification required, got %v", err)
+		}
+		if err.Details["operation_type"] != VerificationOpBeneficiaryAdd || err.Details["metadata"] == nil {
+			t.Errorf("expected operation and metadata in details, got %v", err.Details)
+		}
+	})
+
+	t.Run("redeems token with service auth", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/internal/v1/verifications/redeem" {
+				t.Errorf("expected redeem path, got %s", r.URL.Path)
+			}
+			if r.Header.Get(sharedjwt.ServiceTokenHeader) != "svc-token" {
+				t.Errorf("expected service token header")
+			}
+
+			var body redeemVerificationRequest
+			readJSON(r, &body)
+			if body.Token != "ver-token" || body.UserID != "user-1" || body.Metadata["phone"] != "+919876543210" {
+				t.Errorf("unexpected redeem request: %+v", body)
+			}
+
+			writeJSON(w, map[string]any{"success": true, "data": map[string]any{"verification_id": "ver_1"}})
+		}))
+		defer server.Close()
+
+		client := NewVerificationClient(server.URL, staticServiceToken("svc-token"))
+		if err := client.Redeem(context.Background(), "user-1", VerificationOpBeneficiaryAdd, "ver-token", metadata); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("rejected token requires verification", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			writeJSON(w, map[string]any{
+				"success": false,
+				"error":   map[string]string{"code": "FORBIDDEN", "message": "verification token has already been used"},
+			})
+		}))
+		defer server.Close()
+
+		client := NewVerificationClient(server.URL, staticServiceToken("svc-token"))
+		err := client.Redeem(context.Background(), "user-1", VerificationOpBeneficiaryAdd, "ver-token", metadata)
+		if err == nil || err.Code != errors.ErrCodeVerificationRequired {
+			t.Fatalf("expected verification required, got %v", err)
+		}
+		if err.Details["reason"] == nil {
+			t.Errorf("expected rejection reason in details, got %v", err.Details)
+		}
+	})
+
+	t.Run("identity outage is not a verification failure", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		client := NewVerificationClient(server.URL, staticServiceToken("svc-token"))
+		err := client.Redeem(context.Background(), "user-1", VerificationOpBeneficiaryAdd, "ver-token", metadata)
+		if err == nil || err.Code != errors.ErrCodeInternal {
+			t.Errorf("expected internal error, got %v", err)
+		}
+	})
+}
+
+func TestVerificationClient_Release(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/internal/v1/verifications/release" {
+			t.Errorf("expected release path, got %s", r.URL.Path)
+		}
+
+		var body redeemVerificationRequest
+		readJSON(r, &body)
+		if body.Token != "ver-token" || body.UserID != "user-1" || body.OperationType != VerificationOpHighValueTransfer {
+			t.Errorf("unexpected release request: %+v", body)
+		}
+
+		writeJSON(w, map[string]any{"success": true, "data": map[string]any{"verification_id": "ver_1"}})
+	}))
+	defer server.Close()
+
+	client := NewVerificationClient(server.URL, staticServiceToken("svc-token"))
+	metadata := map[string]any{"amount": 1500000, "destination_wallet_id": "wallet-2"}
+	if err := client.Release(context.Background(), "user-1", VerificationOpHighValueTransfer, "ver-token", metadata); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
@@ -89,7 +89,7 @@ func Load() (*Config, error) {
 		NSQDAddr:       getEnv("NSQD_ADDR", "localhost:4150"),
 
 		// JWT configuration
-		JWTSecret:     requireEnv("JWT_SECRET"), // Required - no default for security
+		JWTSecret:     getEnv("JWT_SECRET", ""), // Identity only - access tokens are verified with its JWKS
 		JWTExpiry:     getEnvAsDuration("JWT_EXPIRY", 24*time.Hour),
 		JWTRefreshExp: getEnvAsDuration("JWT_REFRESH_EXPIRY", 7*24*time.Hour),
 
@@ -133,10 +133,6 @@ func Load() (*Config, error) {
 // Validate ensures critical configuration values are set properly.
 func (c *Config) Validate() error {
 	// Required secrets - these must always be set (no defaults)
-	if c.JWTSecret == "" {
-		return fmt.Errorf("JWT_SECRET environment variable is required")
-	}
-
 	if c.DatabasePassword == "" {
 		return fmt.Errorf("DATABASE_PASSWORD environment variable is required")
 	}
@@ -26,7 +26,7 @@ func TestLoad(t *testing.T) {
 		{
 			name:    "missing required secrets",
 			envVars: map[string]string{
-				// No DATABASE_PASSWORD or JWT_SECRET - should fail
+				// No DATABASE_PASSWORD - should fail
 			},
 			want:    nil,
 			wantErr: true,
@@ -120,15 +120,15 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "invalid config - missing jwt secret",
+			name: "valid config - jwt secret is optional",
 			config: &Config{
 				Environment:      "development",
 				ServicePort:      8080,
 				DatabasePort:     5432,
-				JWTSecret:        "", // empty - should fail
+				JWTSecret:        "", // only the identity service needs it
 				DatabasePassword: "some-password",
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "invalid config - missing db password",
@@ -0,0 +1,81 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// SecretKeySize is the size in bytes of a SecretCipher key (AES-256).
+const SecretKeySize = 32
+
+// SecretCipher encrypts secrets that have to be stored and read back, such as
+// TOTP seeds, with AES-256-GCM. Secrets that only need to be checked should be
+// hashed instead.
+//
+// Each secret is encrypted with associated data naming what it belongs to
+// (e.g. the user ID), so a ciphertext copied to another row does not decrypt.
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher creates a cipher with a SecretKeySize-byte key.
+func NewSecretCipher(key []byte) (*SecretCipher, error) {
+	if len(key) != SecretKeySize {
+		return nil, fmt.Errorf("secret encryption key must be %d bytes, got %d", SecretKeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret cipher: %w", err)
+	}
+
+	return &SecretCipher{aead: aead}, nil
+}
+
+// NewSecretCipherFromBase64 creates a cipher with a base64-encoded key, as
+// generated by `openssl rand -base64 32`.
+func NewSecretCipherFromBase64(encoded string) (*SecretCipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("secret encryption key is not valid base64: %w", err)
+	}
+	return NewSecretCipher(key)
+}
+
+// Encrypt encrypts a secret bound to associatedData and returns the nonce and
+// ciphertext base64-encoded.
+func (c *SecretCipher) Encrypt(secret, associatedData string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(secret), []byte(associatedData))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a secret returned by Encrypt with the same associatedData.
+func (c *SecretCipher) Decrypt(encrypted, associatedData string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("encrypted secret is not valid base64: %w", err)
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("encrypted secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	secret, err := c.aead.Open(nil, nonce, ciphertext, []byte(associatedData))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	return string(secret), nil
+}
@@ -0,0 +1,81 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func newTestSecretCipher(t *testing.T, fill byte) *SecretCipher {
+	t.Helper()
+	c, err := NewSecretCipher(bytes.Repeat([]byte{fill}, SecretKeySize))
+	if err != nil {
+		t.Fatalf("NewSecretCipher() error = %v", err)
+	}
+	return c
+}
+
+func TestSecretCipher_RoundTrip(t *testing.T) {
+	c := newTestSecretCipher(t, 1)
+
+	encrypted, err := c.Encrypt("JBSWY3DPEHPK3PXP", "user-1")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if encrypted == "JBSWY3DPEHPK3PXP" {
+		t.Fatal("expected the secret to be encrypted")
+	}
+
+	again, _ := c.Encrypt("JBSWY3DPEHPK3PXP", "user-1")
+	if again == encrypted {
+		t.Error("expected a fresh nonce for each encryption")
+	}
+
+	secret, err := c.Decrypt(encrypted, "user-1")
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Decrypt() = %s, want JBSWY3DPEHPK3PXP", secret)
+	}
+}
+
+func TestSecretCipher_Rejects(t *testing.T) {
+	c := newTestSecretCipher(t, 1)
+	encrypted, _ := c.Encrypt("JBSWY3DPEHPK3PXP", "user-1")
+
+	tests := []struct {
+		name           string
+		cipher         *SecretCipher
+		encrypted      string
+		associatedData string
+	}{
+		{"other associated data", c, encrypted, "user-2"},
+		{"other key", newTestSecretCipher(t, 2), encrypted, "user-1"},
+		{"plaintext", c, "JBSWY3DPEHPK3PXP", "user-1"},
+		{"too short", c, base64.StdEncoding.EncodeToString([]byte("short")), "user-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cipher.Decrypt(tt.encrypted, tt.associatedData); err == nil {
+				t.Error("expected decryption to fail")
+			}
+		})
+	}
+}
+
+func TestNewSecretCipherFromBase64(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, SecretKeySize))
+	if _, err := NewSecretCipherFromBase64(key); err != nil {
+		t.Errorf("expected valid key, got %v", err)
+	}
+
+	short := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 16))
+	if _, err := NewSecretCipherFromBase64(short); err == nil {
+		t.Error("expected error for a 16-byte key")
+	}
+	if _, err := NewSecretCipherFromBase64("not base64!"); err == nil {
+		t.Error("expected error for invalid base64")
+	}
+}
@@ -0,0 +1,94 @@
+package crypto
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 seed from RFC 6238 Appendix B ("12345678901234567890").
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// RFC 6238 publishes 8-digit codes; 6-digit codes are their last six digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode(%d) error = %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("TOTPCode(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := TOTPStep(now)
+	code, _ := TOTPCode(rfc6238Secret, step)
+	previous, _ := TOTPCode(rfc6238Secret, step-1)
+	stale, _ := TOTPCode(rfc6238Secret, step-2)
+
+	if got, ok := ValidateTOTP(rfc6238Secret, code, now, 0); !ok || got != step {
+		t.Errorf("current code: got step %d ok=%v, want %d", got, ok, step)
+	}
+	if got, ok := ValidateTOTP(rfc6238Secret, previous, now, 0); !ok || got != step-1 {
+		t.Errorf("previous step should be accepted for clock drift, got step %d ok=%v", got, ok)
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, stale, now, 0); ok {
+		t.Error("code two steps old should be rejected")
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, code, now, step); ok {
+		t.Error("code for an already used step should be rejected")
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, "12345", now, 0); ok {
+		t.Error("malformed code should be rejected")
+	}
+	if _, ok := ValidateTOTP("not base32!", code, now, 0); ok {
+		t.Error("invalid secret should be rejected")
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() error = %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret length = %d, want 32 base32 characters", len(secret))
+	}
+	if _, err := TOTPCode(secret, 1); err != nil {
+		t.Errorf("generated secret should be usable: %v", err)
+	}
+
+	other, _ := GenerateTOTPSecret()
+	if secret == other {
+		t.Error("secrets should be unique")
+	}
+}
+
+func TestTOTPAuthURI(t *testing.T) {
+	uri := TOTPAuthURI("Nivo", "user@example.com", "JBSWY3DPEHPK3PXP")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Nivo:user@example.com?") {
+		t.Errorf("unexpected URI label: %s", uri)
+	}
+	for _, part := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Nivo", "digits=6", "period=30", "algorithm=SHA1"} {
+		if !strings.Contains(uri, part) {
+			t.Errorf("URI %s missing %s", uri, part)
+		}
+	}
+}
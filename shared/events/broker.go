@@ -4,10 +4,26 @@ package events
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
 
+// userTopicPrefix prefixes the private topic of each user.
+const userTopicPrefix = "user:"
+
+// UserTopic returns the private topic of a user. Only a client the gateway
+// authenticated as that user is subscribed to it.
+func UserTopic(userID string) string {
+	return userTopicPrefix + userID
+}
+
+// IsPrivateTopic reports whether a topic belongs to a single user. Events on
+// private topics are not sent to clients subscribed to "all".
+func IsPrivateTopic(topic string) bool {
+	return strings.HasPrefix(topic, userTopicPrefix)
+}
+
 // Event represents a single event to be broadcasted.
 type Event struct {
 	Type      string                 `json:"type"`
@@ -109,10 +125,11 @@ func (b *Broker) Start() {
 				b.mu.Unlock()
 
 			case event := <-b.broadcast:
+				private := IsPrivateTopic(event.Topic)
 				b.mu.RLock()
 				for _, client := range b.clients {
-					// Only send to clients subscribed to this topic (or "all")
-					if client.IsSubscribed(event.Topic) || client.IsSubscribed("all") {
+					// Only send to clients subscribed to this topic (or "all", unless it is private)
+					if client.IsSubscribed(event.Topic) || (!private && client.IsSubscribed("all")) {
 						select {
 						case client.Channel <- event.Event:
 						default:
@@ -146,3 +146,30 @@ func (p *Publisher) PublishUserEvent(eventType string, userID string, data map[s
 func (p *Publisher) PublishRiskEvent(eventType string, data map[string]interface{}) {
 	p.PublishEventAsync("risk", eventType, data)
 }
+
+// PublishPaymentRequestEvent publishes a payment request event.
+func (p *Publisher) PublishPaymentRequestEvent(eventType string, paymentRequestID string, data map[string]interface{}) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["payment_request_id"] = paymentRequestID
+	p.PublishEventAsync("payment_requests", eventType, data)
+}
+
+// PublishDisputeEvent publishes a transaction dispute event.
+func (p *Publisher) PublishDisputeEvent(eventType string, disputeID string, data map[string]interface{}) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["dispute_id"] = disputeID
+	p.PublishEventAsync("disputes", eventType, data)
+}
+
+// PublishInboxEvent publishes an in-app inbox event to the user's private topic.
+func (p *Publisher) PublishInboxEvent(eventType string, userID string, data map[string]interface{}) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["user_id"] = userID
+	p.PublishEventAsync(UserTopic(userID), eventType, data)
+}
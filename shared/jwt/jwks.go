@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// JWKS is a JSON Web Key Set (RFC 7517), as served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a public key in JSON Web Key form. RSA keys use n and e; Ed25519 keys
+// use kty "OKP" with crv and x (RFC 8037).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// NewJWK encodes a public key as a JWK.
+func NewJWK(key *PublicKey) JWK {
+	jwk := JWK{Kid: key.ID, Alg: key.Algorithm, Use: "sig"}
+
+	switch pub := key.Key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
+
+// PublicKey decodes the JWK. Only RS256 and EdDSA signing keys are accepted.
+func (k JWK) PublicKey() (*PublicKey, error) {
+	if k.Kid == "" {
+		return nil, fmt.Errorf("key has no kid")
+	}
+	if k.Use != "" && k.Use != "sig" {
+		return nil, fmt.Errorf("key %s is not a signing key", k.Kid)
+	}
+
+	switch {
+	case k.Kty == "RSA" && k.Alg == AlgRS256:
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: invalid modulus: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: invalid exponent: %w", k.Kid, err)
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if pub.N.BitLen() < minRSAKeyBits || pub.E < 3 {
+			return nil, fmt.Errorf("key %s: RSA key is too weak", k.Kid)
+		}
+		return &PublicKey{ID: k.Kid, Algorithm: AlgRS256, Key: pub}, nil
+
+	case k.Kty == "OKP" && k.Crv == "Ed25519" && k.Alg == AlgEdDSA:
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("key %s: invalid Ed25519 public key", k.Kid)
+		}
+		return &PublicKey{ID: k.Kid, Algorithm: AlgEdDSA, Key: ed25519.PublicKey(x)}, nil
+
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %s/%s", k.Kid, k.Kty, k.Alg)
+	}
+}
@@ -0,0 +1,204 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms accepted for access tokens.
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// minRSAKeyBits is the smallest RSA key accepted for signing.
+const minRSAKeyBits = 2048
+
+// SigningKey is a private key used to sign tokens, identified by its key ID (kid).
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	private   crypto.Signer
+}
+
+// NewSigningKey wraps an RSA or Ed25519 private key.
+func NewSigningKey(id string, private crypto.Signer) (*SigningKey, error) {
+	if id == "" {
+		return nil, fmt.Errorf("signing key ID is required")
+	}
+
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("signing key %s: RSA keys must be at least %d bits", id, minRSAKeyBits)
+		}
+		return &SigningKey{ID: id, Algorithm: AlgRS256, private: key}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: id, Algorithm: AlgEdDSA, private: key}, nil
+	default:
+		return nil, fmt.Errorf("signing key %s: unsupported key type %T", id, private)
+	}
+}
+
+// ParseSigningKey reads a PEM-encoded RSA (PKCS#1 or PKCS#8) or Ed25519 (PKCS#8) private key.
+func ParseSigningKey(id string, pemData []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s: no PEM data found", id)
+	}
+
+	var private interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("signing key %s: unsupported PEM block %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: %w", id, err)
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s: unsupported key type %T", id, private)
+	}
+	return NewSigningKey(id, signer)
+}
+
+// GenerateSigningKey creates a new Ed25519 signing key.
+func GenerateSigningKey(id string) (*SigningKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return NewSigningKey(id, private)
+}
+
+// PublicKey returns the verification half of the key.
+func (k *SigningKey) PublicKey() *PublicKey {
+	return &PublicKey{ID: k.ID, Algorithm: k.Algorithm, Key: k.private.Public()}
+}
+
+func (k *SigningKey) method() gojwt.SigningMethod {
+	if k.Algorithm == AlgRS256 {
+		return gojwt.SigningMethodRS256
+	}
+	return gojwt.SigningMethodEdDSA
+}
+
+// PublicKey is a key tokens are verified with.
+type PublicKey struct {
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
+// KeySet holds the key new tokens are signed with and older keys whose tokens
+// are still accepted. To rotate, add a new key, make it active once verifiers
+// have fetched it, and drop the old key after its tokens have expired.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// NewKeySet creates a key set that signs with active and also publishes previous.
+func NewKeySet(active *SigningKey, previous ...*SigningKey) *KeySet {
+	keys := map[string]*SigningKey{active.ID: active}
+	for _, key := range previous {
+		keys[key.ID] = key
+	}
+	return &KeySet{active: active, keys: keys}
+}
+
+// LoadKeySet reads every <kid>.pem file in dir. activeID names the signing key
+// and may be empty when the directory holds a single key.
+func LoadKeySet(dir, activeID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no signing keys (*.pem) found in %s", dir)
+	}
+
+	var active *SigningKey
+	var previous []*SigningKey
+	for _, path := range paths {
+		pemData, err := os.ReadFile(path) //nolint:gosec // G304: path comes from the configured key directory
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+
+		key, err := ParseSigningKey(strings.TrimSuffix(filepath.Base(path), ".pem"), pemData)
+		if err != nil {
+			return nil, err
+		}
+
+		if key.ID == activeID || (activeID == "" && len(paths) == 1) {
+			active = key
+		} else {
+			previous = append(previous, key)
+		}
+	}
+
+	if active == nil {
+		if activeID == "" {
+			return nil, fmt.Errorf("active signing key ID is required when %s holds more than one key", dir)
+		}
+		return nil, fmt.Errorf("active signing key %s not found in %s", activeID, dir)
+	}
+
+	return NewKeySet(active, previous...), nil
+}
+
+// ActiveKeyID returns the ID of the key new tokens are signed with.
+func (s *KeySet) ActiveKeyID() string {
+	return s.active.ID
+}
+
+// Sign creates a token for claims signed with the active key, with its ID in the kid header.
+func (s *KeySet) Sign(claims gojwt.Claims) (string, error) {
+	token := gojwt.NewWithClaims(s.active.method(), claims)
+	token.Header["kid"] = s.active.ID
+	return token.SignedString(s.active.private)
+}
+
+// Key returns the public key for a key ID, so a KeySet can verify its own tokens.
+func (s *KeySet) Key(kid string) (*PublicKey, error) {
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key.PublicKey(), nil
+}
+
+// JWKS returns the public keys for publishing, the active key first.
+func (s *KeySet) JWKS() *JWKS {
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		if id != s.active.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	set := &JWKS{Keys: []JWK{NewJWK(s.active.PublicKey())}}
+	for _, id := range ids {
+		set.Keys = append(set.Keys, NewJWK(s.keys[id].PublicKey()))
+	}
+	return set
+}
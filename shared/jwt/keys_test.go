@@ -0,0 +1,237 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+func testClaims() *gojwt.RegisteredClaims {
+	return &gojwt.RegisteredClaims{
+		Subject:   "user-1",
+		ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func mustGenerate(t *testing.T, id string) *SigningKey {
+	t.Helper()
+	key, err := GenerateSigningKey(id)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+func rsaPEM(t *testing.T, bits int) []byte {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+}
+
+func ed25519PEM(t *testing.T) []byte {
+	t.Helper()
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestParseSigningKey(t *testing.T) {
+	rsaKey, err := ParseSigningKey("rsa", rsaPEM(t, 2048))
+	if err != nil {
+		t.Fatalf("expected RSA key to parse, got %v", err)
+	}
+	if rsaKey.Algorithm != AlgRS256 {
+		t.Errorf("expected %s, got %s", AlgRS256, rsaKey.Algorithm)
+	}
+
+	edKey, err := ParseSigningKey("ed", ed25519PEM(t))
+	if err != nil {
+		t.Fatalf("expected Ed25519 key to parse, got %v", err)
+	}
+	if edKey.Algorithm != AlgEdDSA {
+		t.Errorf("expected %s, got %s", AlgEdDSA, edKey.Algorithm)
+	}
+
+	if _, err := ParseSigningKey("weak", rsaPEM(t, 1024)); err == nil {
+		t.Error("expected 1024-bit RSA key to be rejected")
+	}
+	if _, err := ParseSigningKey("junk", []byte("not a key")); err == nil {
+		t.Error("expected non-PEM data to be rejected")
+	}
+}
+
+func TestKeySet_SignAndVerify(t *testing.T) {
+	for _, pemData := range [][]byte{rsaPEM(t, 2048), ed25519PEM(t)} {
+		key, err := ParseSigningKey("k1", pemData)
+		if err != nil {
+			t.Fatalf("failed to parse key: %v", err)
+		}
+		keys := NewKeySet(key)
+
+		tokenString, err := keys.Sign(testClaims())
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+
+		claims := &gojwt.RegisteredClaims{}
+		if err := ParseToken(tokenString, claims, keys); err != nil {
+			t.Fatalf("%s: expected token to verify, got %v", key.Algorithm, err)
+		}
+		if claims.Subject != "user-1" {
+			t.Errorf("expected subject user-1, got %s", claims.Subject)
+		}
+	}
+}
+
+func TestParseToken_Rejects(t *testing.T) {
+	keys := NewKeySet(mustGenerate(t, "k1"))
+	other := NewKeySet(mustGenerate(t, "k1"))
+
+	expired := &gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(-time.Minute))}
+	expiredToken, _ := keys.Sign(expired)
+
+	forged, _ := other.Sign(testClaims())
+
+	hmac := gojwt.NewWithClaims(gojwt.SigningMethodHS256, testClaims())
+	hmac.Header["kid"] = "k1"
+	hmacToken, _ := hmac.SignedString([]byte("shared-secret"))
+
+	noKid := gojwt.NewWithClaims(gojwt.SigningMethodEdDSA, testClaims())
+	noKidToken, _ := noKid.SignedString(keys.active.private)
+
+	unknown := NewKeySet(mustGenerate(t, "k2"))
+	unknownToken, _ := unknown.Sign(testClaims())
+
+	tests := map[string]string{
+		"expired":     expiredToken,
+		"wrong key":   forged,
+		"hmac":        hmacToken,
+		"no kid":      noKidToken,
+		"unknown kid": unknownToken,
+	}
+
+	for name, tokenString := range tests {
+		if err := ParseToken(tokenString, &gojwt.RegisteredClaims{}, keys); err == nil {
+			t.Errorf("%s: expected token to be rejected", name)
+		}
+	}
+}
+
+func TestParseToken_AlgorithmMustMatchKey(t *testing.T) {
+	rsaKey, _ := ParseSigningKey("k1", rsaPEM(t, 2048))
+	edKey := mustGenerate(t, "k1")
+
+	// An EdDSA token naming the RS256 key's kid must not verify, even with a valid signature
+	tokenString, _ := NewKeySet(edKey).Sign(testClaims())
+	if err := ParseToken(tokenString, &gojwt.RegisteredClaims{}, NewKeySet(rsaKey)); err == nil {
+		t.Error("expected algorithm mismatch to be rejected")
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldKey := mustGenerate(t, "2025-01")
+	newKey := mustGenerate(t, "2025-06")
+
+	oldToken, _ := NewKeySet(oldKey).Sign(testClaims())
+
+	rotated := NewKeySet(newKey, oldKey)
+	if err := ParseToken(oldToken, &gojwt.RegisteredClaims{}, rotated); err != nil {
+		t.Errorf("expected token signed with the previous key to verify, got %v", err)
+	}
+
+	newToken, _ := rotated.Sign(testClaims())
+	token, _, _ := gojwt.NewParser().ParseUnverified(newToken, &gojwt.RegisteredClaims{})
+	if token.Header["kid"] != "2025-06" {
+		t.Errorf("expected new tokens to use the active key, got kid %v", token.Header["kid"])
+	}
+
+	jwks := rotated.JWKS()
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Kid != "2025-06" {
+		t.Errorf("expected both keys published with the active key first, got %+v", jwks.Keys)
+	}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	writeKey := func(name string, data []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("failed to write key: %v", err)
+		}
+	}
+
+	writeKey("2025-01.pem", ed25519PEM(t))
+	keys, err := LoadKeySet(dir, "")
+	if err != nil {
+		t.Fatalf("expected single key to load, got %v", err)
+	}
+	if keys.ActiveKeyID() != "2025-01" {
+		t.Errorf("expected kid from file name, got %s", keys.ActiveKeyID())
+	}
+
+	writeKey("2025-06.pem", rsaPEM(t, 2048))
+	if _, err := LoadKeySet(dir, ""); err == nil {
+		t.Error("expected an active key ID to be required with several keys")
+	}
+	if _, err := LoadKeySet(dir, "2024-12"); err == nil {
+		t.Error("expected a missing active key to be an error")
+	}
+
+	keys, err = LoadKeySet(dir, "2025-06")
+	if err != nil {
+		t.Fatalf("expected keys to load, got %v", err)
+	}
+	if keys.ActiveKeyID() != "2025-06" || len(keys.JWKS().Keys) != 2 {
+		t.Errorf("expected 2025-06 active with both keys published, got %s and %d keys", keys.ActiveKeyID(), len(keys.JWKS().Keys))
+	}
+
+	if _, err := LoadKeySet(t.TempDir(), ""); err == nil {
+		t.Error("expected an empty directory to be an error")
+	}
+}
+
+func TestJWK_RoundTrip(t *testing.T) {
+	rsaKey, _ := ParseSigningKey("rsa", rsaPEM(t, 2048))
+
+	for _, key := range []*SigningKey{rsaKey, mustGenerate(t, "ed")} {
+		jwk := NewJWK(key.PublicKey())
+		decoded, err := jwk.PublicKey()
+		if err != nil {
+			t.Fatalf("%s: expected JWK to decode, got %v", key.Algorithm, err)
+		}
+
+		tokenString, _ := NewKeySet(key).Sign(testClaims())
+		if err := ParseToken(tokenString, &gojwt.RegisteredClaims{}, staticKeys{decoded}); err != nil {
+			t.Errorf("%s: expected decoded key to verify, got %v", key.Algorithm, err)
+		}
+	}
+
+	if _, err := (JWK{Kty: "oct", Kid: "hmac", Alg: "HS256"}).PublicKey(); err == nil {
+		t.Error("expected symmetric JWK to be rejected")
+	}
+}
+
+// staticKeys is a KeySource over fixed public keys.
+type staticKeys []*PublicKey
+
+func (s staticKeys) Key(kid string) (*PublicKey, error) {
+	for _, key := range s {
+		if key.ID == kid {
+			return key, nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
@@ -0,0 +1,38 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseServiceToken(t *testing.T) {
+	keys := NewKeySet(mustGenerate(t, "k1"))
+
+	tokenString, err := keys.Sign(NewServiceClaims(ServiceWallet, ServiceLedger, time.Minute))
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	claims, err := ParseServiceToken(tokenString, keys, ServiceLedger)
+	if err != nil {
+		t.Fatalf("expected service token to verify, got %v", err)
+	}
+	if claims.Subject != ServiceWallet {
+		t.Errorf("expected caller %s, got %s", ServiceWallet, claims.Subject)
+	}
+
+	if _, err := ParseServiceToken(tokenString, keys, ServiceTransaction); err == nil {
+		t.Error("expected a token for another audience to be rejected")
+	}
+
+	expired, _ := keys.Sign(NewServiceClaims(ServiceWallet, ServiceLedger, -time.Minute))
+	if _, err := ParseServiceToken(expired, keys, ServiceLedger); err == nil {
+		t.Error("expected an expired service token to be rejected")
+	}
+
+	// A user's access token must not pass as a service token
+	userToken, _ := keys.Sign(testClaims())
+	if _, err := ParseServiceToken(userToken, keys, ServiceLedger); err == nil {
+		t.Error("expected an access token to be rejected")
+	}
+}
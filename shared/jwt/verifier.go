@@ -0,0 +1,146 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// JWKSPath is where the identity service publishes its public keys.
+	JWKSPath = "/.well-known/jwks.json"
+
+	// DefaultJWKSURL is the identity service's JWKS inside the compose network.
+	DefaultJWKSURL = "http://identity-service:8080" + JWKSPath
+)
+
+const (
+	// DefaultJWKSRefreshInterval is how long fetched keys are used before fetching again.
+	DefaultJWKSRefreshInterval = 10 * time.Minute
+
+	// minJWKSFetchInterval limits fetches triggered by unknown key IDs, so
+	// tokens with made-up kids cannot be used to flood the issuer.
+	minJWKSFetchInterval = 30 * time.Second
+
+	jwksFetchTimeout = 5 * time.Second
+)
+
+// KeySource finds the public key for a token's kid header.
+type KeySource interface {
+	Key(kid string) (*PublicKey, error)
+}
+
+// ParseToken verifies a token's signature against keys and decodes it into
+// claims. The token must name its key in the kid header and be signed with
+// that key's algorithm, and its expiry is checked.
+func ParseToken(tokenString string, claims gojwt.Claims, keys KeySource) error {
+	token, err := gojwt.ParseWithClaims(tokenString, claims, func(token *gojwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("token has no kid header")
+		}
+
+		key, err := keys.Key(kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("token algorithm %s does not match key %s", token.Method.Alg(), kid)
+		}
+		return key.Key, nil
+	}, gojwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}))
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return gojwt.ErrTokenSignatureInvalid
+	}
+	return nil
+}
+
+// RemoteKeySet fetches and caches a JWKS over HTTP. Keys are fetched on first
+// use, again every refresh interval, and early when a token names a key not
+// yet seen, which is how a newly rotated key is picked up. If a fetch fails
+// the previously fetched keys are kept.
+type RemoteKeySet struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]*PublicKey
+	fetchedAt time.Time
+	triedAt   time.Time
+}
+
+// NewRemoteKeySet creates a key set backed by the JWKS at url.
+func NewRemoteKeySet(url string) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:             url,
+		client:          &http.Client{Timeout: jwksFetchTimeout},
+		refreshInterval: DefaultJWKSRefreshInterval,
+		keys:            make(map[string]*PublicKey),
+	}
+}
+
+// Key returns the public key for kid, fetching the key set if needed.
+func (r *RemoteKeySet) Key(kid string) (*PublicKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.fetchedAt) > r.refreshInterval && time.Since(r.triedAt) > minJWKSFetchInterval {
+		_ = r.fetch()
+	}
+
+	if key, ok := r.keys[kid]; ok {
+		return key, nil
+	}
+
+	// Unknown key: the issuer may have rotated since the last fetch
+	if time.Since(r.triedAt) > minJWKSFetchInterval {
+		if err := r.fetch(); err != nil {
+			return nil, err
+		}
+		if key, ok := r.keys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// fetch replaces the cached keys with the current JWKS. Callers hold r.mu.
+func (r *RemoteKeySet) fetch() error {
+	r.triedAt = time.Now()
+
+	resp, err := r.client.Get(r.url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		// Skip keys this verifier cannot use rather than rejecting the whole set
+		if key, err := jwk.PublicKey(); err == nil {
+			keys[key.ID] = key
+		}
+	}
+
+	r.keys = keys
+	r.fetchedAt = r.triedAt
+	return nil
+}
@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer serves the JWKS of whichever key set is current.
+type jwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    *KeySet
+	fail    bool
+	fetches atomic.Int32
+}
+
+func newJWKSServer(keys *KeySet) *jwksServer {
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(s.keys.JWKS())
+	}))
+	return s
+}
+
+func (s *jwksServer) set(keys *KeySet, fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.fail = fail
+}
+
+func TestRemoteKeySet_VerifiesAndCaches(t *testing.T) {
+	keys := NewKeySet(mustGenerate(t, "k1"))
+	server := newJWKSServer(keys)
+	defer server.Close()
+
+	remote := NewRemoteKeySet(server.URL + JWKSPath)
+
+	for i := 0; i < 3; i++ {
+		tokenString, _ := keys.Sign(testClaims())
+		if err := ParseToken(tokenString, &gojwt.RegisteredClaims{}, remote); err != nil {
+			t.Fatalf("expected token to verify, got %v", err)
+		}
+	}
+
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("expected the key set to be fetched once, got %d", got)
+	}
+}
+
+func TestRemoteKeySet_PicksUpRotatedKey(t *testing.T) {
+	oldKey := mustGenerate(t, "k1")
+	server := newJWKSServer(NewKeySet(oldKey))
+	defer server.Close()
+
+	remote := NewRemoteKeySet(server.URL + JWKSPath)
+	if _, err := remote.Key("k1"); err != nil {
+		t.Fatalf("expected k1, got %v", err)
+	}
+
+	rotated := NewKeySet(mustGenerate(t, "k2"), oldKey)
+	server.set(rotated, false)
+
+	// Pretend the last fetch was long enough ago to allow another
+	remote.triedAt = time.Now().Add(-time.Minute)
+
+	tokenString, _ := rotated.Sign(testClaims())
+	if err := ParseToken(tokenString, &gojwt.RegisteredClaims{}, remote); err != nil {
+		t.Errorf("expected token signed with the new key to verify, got %v", err)
+	}
+}
+
+func TestRemoteKeySet_LimitsFetchesForUnknownKeys(t *testing.T) {
+	server := newJWKSServer(NewKeySet(mustGenerate(t, "k1")))
+	defer server.Close()
+
+	remote := NewRemoteKeySet(server.URL + JWKSPath)
+	for _, kid := range []string{"k1", "made-up-1", "made-up-2", "made-up-3"} {
+		_, _ = remote.Key(kid)
+	}
+
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("expected unknown kids not to trigger repeated fetches, got %d fetches", got)
+	}
+}
+
+func TestRemoteKeySet_KeepsKeysWhenFetchFails(t *testing.T) {
+	keys := NewKeySet(mustGenerate(t, "k1"))
+	server := newJWKSServer(keys)
+	defer server.Close()
+
+	remote := NewRemoteKeySet(server.URL + JWKSPath)
+	if _, err := remote.Key("k1"); err != nil {
+		t.Fatalf("expected k1, got %v", err)
+	}
+
+	server.set(keys, true)
+	remote.fetchedAt = time.Now().Add(-2 * DefaultJWKSRefreshInterval)
+	remote.triedAt = remote.fetchedAt
+
+	if _, err := remote.Key("k1"); err != nil {
+		t.Errorf("expected cached key to be used while the JWKS is unavailable, got %v", err)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Errorf("expected a refresh attempt, got %d fetches", got)
+	}
+}
+
+func TestRemoteKeySet_Unavailable(t *testing.T) {
+	server := newJWKSServer(nil)
+	server.set(nil, true)
+	defer server.Close()
+
+	remote := NewRemoteKeySet(server.URL + JWKSPath)
+	if _, err := remote.Key("k1"); err == nil {
+		t.Error("expected an error when the key set cannot be fetched")
+	}
+}
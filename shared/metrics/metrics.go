@@ -25,6 +25,9 @@ type Collector struct {
 	LedgerEntriesTotal    *prometheus.CounterVec
 	RiskEventsTotal       *prometheus.CounterVec
 
+	// Reconciliation Metrics
+	ReconciliationDiscrepancies *prometheus.GaugeVec
+
 	// System Metrics
 	DBConnectionsActive prometheus.Gauge
 	DBQueryDuration     *prometheus.HistogramVec
@@ -106,6 +109,15 @@ func NewCollector(serviceName string) *Collector {
 			[]string{"service", "rule", "action"},
 		),
 
+		// Reconciliation Metrics
+		ReconciliationDiscrepancies: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "reconciliation_discrepancies",
+				Help: "Number of open reconciliation discrepancies",
+			},
+			[]string{"service", "severity"},
+		),
+
 		// System Metrics
 		DBConnectionsActive: promauto.NewGauge(
 			prometheus.GaugeOpts{
@@ -241,6 +253,11 @@ func (c *Collector) RecordRiskEvent(serviceName, rule, action string) {
 	c.RiskEventsTotal.WithLabelValues(serviceName, rule, action).Inc()
 }
 
+// SetReconciliationDiscrepancies sets the open discrepancy count for a severity
+func (c *Collector) SetReconciliationDiscrepancies(serviceName, severity string, count int) {
+	c.ReconciliationDiscrepancies.WithLabelValues(serviceName, severity).Set(float64(count))
+}
+
 // RecordDBQuery records a database query duration
 func (c *Collector) RecordDBQuery(serviceName, queryType string, duration time.Duration) {
 	c.DBQueryDuration.WithLabelValues(serviceName, queryType).Observe(duration.Seconds())
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/vnykmshr/nivo/shared/errors"
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
 	"github.com/vnykmshr/nivo/shared/response"
 )
 
@@ -29,6 +31,8 @@ const (
 	JWTTokenKey ContextKey = "jwt_token"
 	// AccountTypeKey is the context key for account type (user, user_admin).
 	AccountTypeKey ContextKey = "account_type"
+	// CallerServiceKey is the context key for the service making an internal call.
+	CallerServiceKey ContextKey = "caller_service"
 )
 
 // JWTClaims represents the JWT token claims structure.
@@ -44,7 +48,9 @@ type JWTClaims struct {
 
 // AuthConfig holds configuration for auth middleware.
 type AuthConfig struct {
-	JWTSecret string
+	// Keys verifies token signatures, normally a sharedjwt.RemoteKeySet for
+	// the identity service's JWKS.
+	Keys sharedjwt.KeySource
 	// Optional: Skip auth for certain paths
 	SkipPaths []string
 }
@@ -79,15 +85,8 @@ func Auth(config AuthConfig) func(http.Handler) http.Handler {
 
 			// Parse and validate JWT
 			claims := &JWTClaims{}
-			token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-				// Validate signing method
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-				}
-				return []byte(config.JWTSecret), nil
-			})
-
-			if err != nil || !token.Valid {
+			// Service tokens are signed with the same keys but carry no user
+			if err := sharedjwt.ParseToken(tokenString, claims, config.Keys); err != nil || claims.UserID == "" {
 				response.Error(w, errors.Unauthorized("invalid or expired token"))
 				return
 			}
@@ -274,71 +273,49 @@ func GetAccountType(ctx context.Context) (string, bool) {
 	return accountType, ok
 }
 
-// InternalAuthConfig holds configuration for internal service-to-service auth.
-type InternalAuthConfig struct {
-	// Secret is the shared secret for internal service communication.
-	// Should be set via INTERNAL_SERVICE_SECRET environment variable.
-	Secret string
-	// HeaderName is the header to check for the secret. Defaults to X-Internal-Secret.
-	HeaderName string
+// ServiceAuth authenticates service-to-service calls to internal endpoints.
+// Callers send a service token in the X-Service-Token header, issued by the
+// identity service for calling this service, and each route lists the
+// services allowed to call it.
+type ServiceAuth struct {
+	keys    sharedjwt.KeySource
+	service string
 }
 
-// InternalAuth creates a middleware that validates service-to-service requests
-// using a shared secret header. This protects internal endpoints from external access.
-func InternalAuth(config InternalAuthConfig) func(http.Handler) http.Handler {
-	headerName := config.HeaderName
-	if headerName == "" {
-		headerName = "X-Internal-Secret"
-	}
-
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// If no secret configured, allow (for backwards compatibility in dev)
-			if config.Secret == "" {
-				next.ServeHTTP(w, r)
-				return
-			}
-
-			// Check for internal secret header
-			providedSecret := r.Header.Get(headerName)
-			if providedSecret == "" {
-				response.Error(w, errors.Unauthorized("missing internal authentication"))
-				return
-			}
-
-			// Validate secret (constant-time comparison would be better but this is acceptable)
-			if providedSecret != config.Secret {
-				response.Error(w, errors.Unauthorized("invalid internal authentication"))
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
+// NewServiceAuth creates service authentication for the named service.
+// keys verifies token signatures, as for user tokens.
+func NewServiceAuth(keys sharedjwt.KeySource, service string) *ServiceAuth {
+	return &ServiceAuth{keys: keys, service: service}
 }
 
-// InternalAuthFunc is a simpler version that returns a http.HandlerFunc wrapper.
-// Use this for registering with mux.HandleFunc.
-func InternalAuthFunc(secret string, next http.HandlerFunc) http.HandlerFunc {
+// Allow wraps an internal handler so only the listed services may call it.
+// The caller's name is added to the request context.
+func (a *ServiceAuth) Allow(next http.HandlerFunc, callers ...string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// If no secret configured, allow (for backwards compatibility in dev)
-		if secret == "" {
-			next(w, r)
+		tokenString := r.Header.Get(sharedjwt.ServiceTokenHeader)
+		if tokenString == "" {
+			response.Error(w, errors.Unauthorized("missing service token"))
 			return
 		}
 
-		// Check for internal secret header
-		providedSecret := r.Header.Get("X-Internal-Secret")
-		if providedSecret == "" {
-			response.Error(w, errors.Unauthorized("missing internal authentication"))
+		claims, err := sharedjwt.ParseServiceToken(tokenString, a.keys, a.service)
+		if err != nil {
+			response.Error(w, errors.Unauthorized("invalid or expired service token"))
 			return
 		}
 
-		if providedSecret != secret {
-			response.Error(w, errors.Unauthorized("invalid internal authentication"))
+		if !slices.Contains(callers, claims.Subject) {
+			response.Error(w, errors.Forbidden(fmt.Sprintf("service %s may not call this endpoint", claims.Subject)))
 			return
 		}
 
-		next(w, r)
+		ctx := context.WithValue(r.Context(), CallerServiceKey, claims.Subject)
+		next(w, r.WithContext(ctx))
 	}
 }
+
+// GetCallerService extracts the calling service's name from the request context.
+func GetCallerService(ctx context.Context) (string, bool) {
+	service, ok := ctx.Value(CallerServiceKey).(string)
+	return service, ok
+}
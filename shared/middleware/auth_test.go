@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sharedjwt "github.com/vnykmshr/nivo/shared/jwt"
+)
+
+func TestServiceAuth(t *testing.T) {
+	key, err := sharedjwt.GenerateSigningKey("test-key")
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	keys := sharedjwt.NewKeySet(key)
+
+	serviceToken := func(caller, audience string) string {
+		token, err := keys.Sign(sharedjwt.NewServiceClaims(caller, audience, time.Minute))
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		return token
+	}
+
+	var caller string
+	handler := NewServiceAuth(keys, sharedjwt.ServiceLedger).Allow(func(w http.ResponseWriter, r *http.Request) {
+		caller, _ = GetCallerService(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}, sharedjwt.ServiceWallet)
+
+	userToken, _ := keys.Sign(&JWTClaims{UserID: "user-1"})
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{"allowed caller", serviceToken(sharedjwt.ServiceWallet, sharedjwt.ServiceLedger), http.StatusOK},
+		{"missing token", "", http.StatusUnauthorized},
+		{"caller not on allow-list", serviceToken(sharedjwt.ServiceTransaction, sharedjwt.ServiceLedger), http.StatusForbidden},
+		{"token for another service", serviceToken(sharedjwt.ServiceWallet, sharedjwt.ServiceRBAC), http.StatusUnauthorized},
+		{"user access token", userToken, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller = ""
+			req := httptest.NewRequest(http.MethodPost, "/internal/v1/accounts", nil)
+			if tt.token != "" {
+				req.Header.Set(sharedjwt.ServiceTokenHeader, tt.token)
+			}
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantStatus == http.StatusOK && caller != sharedjwt.ServiceWallet {
+				t.Errorf("expected caller %s in context, got %q", sharedjwt.ServiceWallet, caller)
+			}
+		})
+	}
+}
+
+func TestAuth_RejectsServiceTokens(t *testing.T) {
+	key, _ := sharedjwt.GenerateSigningKey("test-key")
+	keys := sharedjwt.NewKeySet(key)
+	token, _ := keys.Sign(sharedjwt.NewServiceClaims(sharedjwt.ServiceWallet, sharedjwt.ServiceLedger, time.Minute))
+
+	handler := Auth(AuthConfig{Keys: keys})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected service token to be rejected as a user token, got %d", rec.Code)
+	}
+}
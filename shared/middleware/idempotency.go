@@ -0,0 +1,249 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/vnykmshr/nivo/shared/errors"
+	"github.com/vnykmshr/nivo/shared/logger"
+	"github.com/vnykmshr/nivo/shared/response"
+)
+
+const (
+	// IdempotencyKeyHeader is the header clients send to make a request safe to retry.
+	IdempotencyKeyHeader = "X-Idempotency-Key"
+	// IdempotencyKeyHeaderAlt is the unprefixed form of the header, also accepted.
+	IdempotencyKeyHeaderAlt = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on responses replayed from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// MaxIdempotencyKeyLength is the longest idempotency key accepted.
+	MaxIdempotencyKeyLength = 255
+)
+
+// IdempotencyRecord is a stored idempotency key. A record without a response
+// belongs to a request that is still being processed.
+type IdempotencyRecord struct {
+	RequestHash string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyStore persists idempotency keys and the responses they produced.
+// Keys are unique per scope; the middleware scopes them by user, method and path.
+type IdempotencyStore interface {
+	// Reserve claims the key for a request. It returns nil if the key was claimed,
+	// or the existing record if the key is already in use. Expired keys and keys
+	// whose lock has lapsed for the same request can be claimed again.
+	Reserve(ctx context.Context, scope, key, requestHash string, lock, ttl time.Duration) (*IdempotencyRecord, error)
+
+	// Complete stores the response for a claimed key.
+	Complete(ctx context.Context, scope, key string, record *IdempotencyRecord) error
+
+	// Release removes a claimed key that produced no response worth replaying.
+	Release(ctx context.Context, scope, key string) error
+}
+
+// IdempotencyConfig holds configuration for the idempotency middleware.
+type IdempotencyConfig struct {
+	// Store persists keys and responses.
+	Store IdempotencyStore
+
+	// TTL is how long a key and its response are kept (default: 24 hours).
+	TTL time.Duration
+
+	// LockTimeout is how long a key stays locked by a request that has not
+	// completed, after which a retry may claim it (default: 1 minute).
+	LockTimeout time.Duration
+
+	// MaxBodyBytes limits the request body read for hashing (default: 1 MiB).
+	MaxBodyBytes int64
+
+	// Logger logs store failures (default: a logger named "idempotency").
+	Logger *logger.Logger
+}
+
+// DefaultIdempotencyConfig returns the default configuration for the given store.
+func DefaultIdempotencyConfig(store IdempotencyStore) IdempotencyConfig {
+	return IdempotencyConfig{
+		Store:        store,
+		TTL:          24 * time.Hour,
+		LockTimeout:  time.Minute,
+		MaxBodyBytes: 1 << 20,
+	}
+}
+
+// Idempotency creates a middleware that makes requests carrying an idempotency
+// key safe to retry. The first request with a key is processed and its response
+// stored; retries with the same key and body get the stored response replayed
+// instead of being processed again. Reusing a key with a different body is
+// rejected with 409, as is a retry while the first request is still in flight.
+//
+// Server errors (5xx) are not stored, so the request can be retried with the
+// same key. Requests without a key are passed through unchanged.
+//
+// The middleware must run after Auth: keys are scoped to the authenticated user.
+func Idempotency(config IdempotencyConfig) Middleware {
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = time.Minute
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = 1 << 20
+	}
+	if config.Logger == nil {
+		config.Logger = logger.NewDefault("idempotency")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				key = r.Header.Get(IdempotencyKeyHeaderAlt)
+			}
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > MaxIdempotencyKeyLength {
+				response.Error(w, errors.BadRequest(fmt.Sprintf("idempotency key must be at most %d characters", MaxIdempotencyKeyLength)))
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxBodyBytes+1))
+			if err != nil {
+				response.Error(w, errors.BadRequest("failed to read request body"))
+				return
+			}
+			if int64(len(body)) > config.MaxBodyBytes {
+				response.Error(w, errors.BadRequest("request body too large"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			scope := idempotencyScope(r)
+			requestHash := hashRequest(r, body)
+
+			existing, err := config.Store.Reserve(ctx, scope, key, requestHash, config.LockTimeout, config.TTL)
+			if err != nil {
+				config.logError(ctx, "failed to reserve idempotency key", err)
+				response.Error(w, errors.Internal("failed to check idempotency key"))
+				return
+			}
+
+			if existing != nil {
+				switch {
+				case existing.RequestHash != requestHash:
+					response.Error(w, errors.DuplicateIdempotencyKey(key).
+						WithDetails(map[string]interface{}{"reason": "key was already used with a different request"}))
+				case !existing.Completed:
+					w.Header().Set("Retry-After", "1")
+					response.Error(w, errors.Conflict("a request with this idempotency key is still being processed"))
+				default:
+					replayResponse(w, existing)
+				}
+				return
+			}
+
+			recorder := newResponseRecorder(w)
+			release := true
+			defer func() {
+				// Free the key after a server error or panic, so the client can retry
+				if release {
+					if err := config.Store.Release(context.WithoutCancel(ctx), scope, key); err != nil {
+						config.logError(ctx, "failed to release idempotency key", err)
+					}
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			if recorder.statusCode >= http.StatusInternalServerError {
+				return
+			}
+			release = false
+
+			record := &IdempotencyRecord{
+				RequestHash: requestHash,
+				Completed:   true,
+				StatusCode:  recorder.statusCode,
+				ContentType: recorder.Header().Get("Content-Type"),
+				Body:        recorder.body.Bytes(),
+			}
+			if err := config.Store.Complete(context.WithoutCancel(ctx), scope, key, record); err != nil {
+				// The key stays locked, so a retry is processed again only once the lock lapses
+				config.logError(ctx, "failed to store idempotent response", err)
+			}
+		})
+	}
+}
+
+func (c IdempotencyConfig) logError(ctx context.Context, msg string, err error) {
+	c.Logger.WithContext(ctx).WithError(err).Error(msg)
+}
+
+// idempotencyScope namespaces keys by user and endpoint, so one user's keys
+// never match another's and a key cannot be replayed against another endpoint.
+func idempotencyScope(r *http.Request) string {
+	userID, _ := r.Context().Value(UserIDKey).(string)
+	if userID == "" {
+		userID = "anonymous"
+	}
+	return fmt.Sprintf("%s %s %s", userID, r.Method, r.URL.Path)
+}
+
+// hashRequest fingerprints the request a key was used for.
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n", r.Method, r.URL.RequestURI())
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replayResponse(w http.ResponseWriter, record *IdempotencyRecord) {
+	if record.ContentType != "" {
+		w.Header().Set("Content-Type", record.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// responseRecorder passes a response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if rr.wroteHeader {
+		return
+	}
+	rr.statusCode = statusCode
+	rr.wroteHeader = true
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if !rr.wroteHeader {
+		rr.WriteHeader(http.StatusOK)
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PostgresIdempotencyStore stores idempotency keys in the idempotency_keys table
+// of the shared database, created by the wallet service's 005_wallet_idempotency_keys migration.
+type PostgresIdempotencyStore struct {
+	db *sql.DB
+}
+
+// NewPostgresIdempotencyStore creates an idempotency store backed by the given database.
+func NewPostgresIdempotencyStore(db *sql.DB) *PostgresIdempotencyStore {
+	return &PostgresIdempotencyStore{db: db}
+}
+
+// Reserve claims the key, replacing it if it expired or if its lock lapsed
+// before the same request completed.
+func (s *PostgresIdempotencyStore) Reserve(ctx context.Context, scope, key, requestHash string, lock, ttl time.Duration) (*IdempotencyRecord, error) {
+	query := `
+		INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, locked_until, expires_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4), NOW() + make_interval(secs => $5))
+		ON CONFLICT (scope, idempotency_key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+		    response_status = NULL,
+		    response_content_type = NULL,
+		    response_body = NULL,
+		    locked_until = EXCLUDED.locked_until,
+		    expires_at = EXCLUDED.expires_at,
+		    created_at = NOW(),
+		    completed_at = NULL
+		WHERE idempotency_keys.expires_at <= NOW()
+		   OR (idempotency_keys.completed_at IS NULL
+		       AND idempotency_keys.locked_until <= NOW()
+		       AND idempotency_keys.request_hash = EXCLUDED.request_hash)
+		RETURNING scope
+	`
+
+	var claimed string
+	err := s.db.QueryRowContext(ctx, query, scope, key, requestHash, lock.Seconds(), ttl.Seconds()).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	record := &IdempotencyRecord{}
+	var status sql.NullInt64
+	var contentType sql.NullString
+	var completedAt sql.NullTime
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT request_hash, response_status, response_content_type, response_body, completed_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2
+	`, scope, key).Scan(&record.RequestHash, &status, &contentType, &record.Body, &completedAt)
+	if err == sql.ErrNoRows {
+		// Released between the two queries; report it as in flight so the client retries
+		return &IdempotencyRecord{RequestHash: requestHash}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	record.Completed = completedAt.Valid
+	record.StatusCode = int(status.Int64)
+	record.ContentType = contentType.String
+	return record, nil
+}
+
+// Complete stores the response for a claimed key.
+func (s *PostgresIdempotencyStore) Complete(ctx context.Context, scope, key string, record *IdempotencyRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET response_status = $3, response_content_type = $4, response_body = $5, completed_at = NOW()
+		WHERE scope = $1 AND idempotency_key = $2
+	`, scope, key, record.StatusCode, record.ContentType, record.Body)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release deletes a claimed key that has not completed.
+func (s *PostgresIdempotencyStore) Release(ctx context.Context, scope, key string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2 AND completed_at IS NULL
+	`, scope, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired deletes expired keys and returns how many were removed.
+func (s *PostgresIdempotencyStore) PurgeExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryIdempotencyStore is an in-memory IdempotencyStore for tests.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*IdempotencyRecord)}
+}
+
+func (m *memoryIdempotencyStore) Reserve(ctx context.Context, scope, key, requestHash string, lock, ttl time.Duration) (*IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.records[scope+"|"+key]; ok {
+		copied := *existing
+		return &copied, nil
+	}
+	m.records[scope+"|"+key] = &IdempotencyRecord{RequestHash: requestHash}
+	return nil, nil
+}
+
+func (m *memoryIdempotencyStore) Complete(ctx context.Context, scope, key string, record *IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[scope+"|"+key] = record
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Release(ctx context.Context, scope, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if record, ok := m.records[scope+"|"+key]; ok && !record.Completed {
+		delete(m.records, scope+"|"+key)
+	}
+	return nil
+}
+
+func idempotentRequest(userID, key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/transfer", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if userID != "" {
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+	}
+	return req
+}
+
+func TestIdempotency(t *testing.T) {
+	newHandler := func(status int) (http.Handler, *int) {
+		calls := 0
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"success":true,"data":{"id":"tx-1"}}`))
+		}), &calls
+	}
+
+	t.Run("passes through requests without a key", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		handler, calls := newHandler(http.StatusCreated)
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(handler)
+
+		for range 2 {
+			wrapped.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "", `{"amount":100}`))
+		}
+
+		if *calls != 2 {
+			t.Errorf("expected handler called twice, got %d", *calls)
+		}
+		if len(store.records) != 0 {
+			t.Errorf("expected no stored keys, got %d", len(store.records))
+		}
+	})
+
+	t.Run("replays the stored response on retry", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		handler, calls := newHandler(http.StatusCreated)
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(handler)
+
+		first := httptest.NewRecorder()
+		wrapped.ServeHTTP(first, idempotentRequest("user-1", "key-1", `{"amount":100}`))
+		retry := httptest.NewRecorder()
+		wrapped.ServeHTTP(retry, idempotentRequest("user-1", "key-1", `{"amount":100}`))
+
+		if *calls != 1 {
+			t.Errorf("expected handler called once, got %d", *calls)
+		}
+		if retry.Code != http.StatusCreated {
+			t.Errorf("expected replayed status 201, got %d", retry.Code)
+		}
+		if retry.Body.String() != first.Body.String() {
+			t.Errorf("expected replayed body %q, got %q", first.Body.String(), retry.Body.String())
+		}
+		if retry.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("expected replayed content type, got %q", retry.Header().Get("Content-Type"))
+		}
+		if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+			t.Error("expected replayed response to be marked")
+		}
+		if first.Header().Get(IdempotentReplayedHeader) != "" {
+			t.Error("expected original response not to be marked as replayed")
+		}
+	})
+
+	t.Run("accepts the unprefixed header", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		handler, calls := newHandler(http.StatusCreated)
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(handler)
+
+		for range 2 {
+			req := idempotentRequest("user-1", "", `{"amount":100}`)
+			req.Header.Set(IdempotencyKeyHeaderAlt, "key-1")
+			wrapped.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		if *calls != 1 {
+			t.Errorf("expected handler called once, got %d", *calls)
+		}
+	})
+
+	t.Run("rejects the same key with a different body", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		handler, calls := newHandler(http.StatusCreated)
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(handler)
+
+		wrapped.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", `{"amount":100}`))
+		rec := httptest.NewRecorder()
+		wrapped.ServeHTTP(rec, idempotentRequest("user-1", "key-1", `{"amount":999}`))
+
+		if *calls != 1 {
+			t.Errorf("expected handler called once, got %d", *calls)
+		}
+		if rec.Code != http.StatusConflict {
+			t.Errorf("expected status 409, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "DUPLICATE_IDEMPOTENCY_KEY") {
+			t.Errorf("expected duplicate idempotency key error, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("rejects a retry while the first request is in flight", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		body := `{"amount":100}`
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(http.NotFoundHandler())
+
+		// Reserve the key as an in-flight request would
+		req := idempotentRequest("user-1", "key-1", body)
+		if _, err := store.Reserve(req.Context(), idempotencyScope(req), "key-1", hashRequest(req, []byte(body)), time.Minute, time.Hour); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		rec := httptest.NewRecorder()
+		wrapped.ServeHTTP(rec, idempotentRequest("user-1", "key-1", body))
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("expected status 409, got %d", rec.Code)
+		}
+		if rec.Header().Get("Retry-After") == "" {
+			t.Error("expected Retry-After header")
+		}
+	})
+
+	t.Run("does not store server errors", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		handler, calls := newHandler(http.StatusServiceUnavailable)
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(handler)
+
+		for range 2 {
+			wrapped.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", `{"amount":100}`))
+		}
+
+		if *calls != 2 {
+			t.Errorf("expected handler called twice, got %d", *calls)
+		}
+		if len(store.records) != 0 {
+			t.Errorf("expected key to be released, got %d stored", len(store.records))
+		}
+	})
+
+	t.Run("stores client errors", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		handler, calls := newHandler(http.StatusBadRequest)
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(handler)
+
+		var rec *httptest.ResponseRecorder
+		for range 2 {
+			rec = httptest.NewRecorder()
+			wrapped.ServeHTTP(rec, idempotentRequest("user-1", "key-1", `{"amount":100}`))
+		}
+
+		if *calls != 1 {
+			t.Errorf("expected handler called once, got %d", *calls)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected replayed status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("scopes keys to the user", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		handler, calls := newHandler(http.StatusCreated)
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(handler)
+
+		wrapped.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", `{"amount":100}`))
+		wrapped.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-2", "key-1", `{"amount":100}`))
+
+		if *calls != 2 {
+			t.Errorf("expected handler called for each user, got %d", *calls)
+		}
+	})
+
+	t.Run("passes the body through to the handler", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		var received string
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received = string(body)
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		wrapped.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", `{"amount":100}`))
+
+		if received != `{"amount":100}` {
+			t.Errorf("expected handler to read the body, got %q", received)
+		}
+	})
+
+	t.Run("rejects keys that are too long", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		handler, calls := newHandler(http.StatusCreated)
+		wrapped := Idempotency(DefaultIdempotencyConfig(store))(handler)
+
+		rec := httptest.NewRecorder()
+		wrapped.ServeHTTP(rec, idempotentRequest("user-1", strings.Repeat("k", MaxIdempotencyKeyLength+1), `{}`))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+		if *calls != 0 {
+			t.Errorf("expected handler not called, got %d", *calls)
+		}
+	})
+}